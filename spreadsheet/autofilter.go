@@ -0,0 +1,662 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/unidoc/unioffice"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+)
+
+// AutoFilter is the autofilter of a sheet, which filters the rows below its
+// header row by criteria for each of its columns.
+type AutoFilter struct {
+	s *Sheet
+	x *sml.CT_AutoFilter
+}
+
+// FilterColumn is the filter criteria of a column of an autofilter.
+type FilterColumn struct {
+	x *sml.CT_FilterColumn
+}
+
+// CustomFilter is a comparison of a custom filter.  For Equal and NotEqual,
+// text values may contain the wildcards * and ?, which are escaped with ~.
+type CustomFilter struct {
+	Operator sml.ST_FilterOperator
+	Value    string
+}
+
+// SortCondition is a sort key of the sort state of a sheet or autofilter.
+type SortCondition struct {
+	x *sml.CT_SortCondition
+}
+
+// AutoFilter returns the autofilter of the sheet, or false if it has none.
+// SetAutoFilter creates one.
+func (s *Sheet) AutoFilter() (AutoFilter, bool) {
+	if s._bcgb.AutoFilter == nil || s._bcgb.AutoFilter.RefAttr == nil {
+		return AutoFilter{}, false
+	}
+	return AutoFilter{s, s._bcgb.AutoFilter}, true
+}
+
+// X returns the inner wrapped XML type.
+func (a AutoFilter) X() *sml.CT_AutoFilter { return a.x }
+
+// Reference returns the range of the autofilter, including its header row.
+func (a AutoFilter) Reference() string { return *a.x.RefAttr }
+
+// Column returns the filter criteria of a column of the sheet (e.g. "B"),
+// creating it if necessary.  It returns an error if the column isn't within
+// the range of the autofilter.
+func (a AutoFilter) Column(col string) (FilterColumn, error) {
+	ar, idx, err := a.column(col)
+	if err != nil {
+		return FilterColumn{}, err
+	}
+	id := idx - ar.col1
+	for _, fc := range a.x.FilterColumn {
+		if fc.ColIdAttr == id {
+			return FilterColumn{fc}, nil
+		}
+	}
+	fc := sml.NewCT_FilterColumn()
+	fc.ColIdAttr = id
+	a.x.FilterColumn = append(a.x.FilterColumn, fc)
+	sort.Slice(a.x.FilterColumn, func(i, j int) bool {
+		return a.x.FilterColumn[i].ColIdAttr < a.x.FilterColumn[j].ColIdAttr
+	})
+	return FilterColumn{fc}, nil
+}
+
+// column returns the range of the autofilter and the index of a column,
+// returning an error if the column isn't within the range.
+func (a AutoFilter) column(col string) (area, uint32, error) {
+	ar, ok := a.s.areaOf(a.Reference())
+	if !ok {
+		return area{}, 0, fmt.Errorf("invalid autofilter range %s", a.Reference())
+	}
+	if col == "" || strings.Trim(strings.ToUpper(col), "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return area{}, 0, fmt.Errorf("invalid column %q", col)
+	}
+	idx := reference.ColumnToIndex(col)
+	if idx < ar.col1 || idx > ar.col2 {
+		return area{}, 0, fmt.Errorf("column %s is outside of the autofilter range %s", col, a.Reference())
+	}
+	return ar, idx, nil
+}
+
+// ClearFilters removes the criteria of all of the columns.
+func (a AutoFilter) ClearFilters() { a.x.FilterColumn = nil }
+
+// AddSortCondition adds a key to the sort state of the autofilter, which
+// records how its rows are sorted so that Excel shows it on the filter
+// buttons.  It doesn't sort the rows, and returns an error if the column isn't
+// within the range of the autofilter.
+func (a AutoFilter) AddSortCondition(col string, order SortOrder) (SortCondition, error) {
+	ar, idx, err := a.column(col)
+	if err != nil {
+		return SortCondition{}, err
+	}
+	if a.x.SortState == nil {
+		a.x.SortState = sml.NewCT_SortState()
+		a.x.SortState.RefAttr = areaReference(area{col1: ar.col1, row1: ar.row1 + 1, col2: ar.col2, row2: ar.row2})
+	}
+	sc := sml.NewCT_SortCondition()
+	sc.RefAttr = areaReference(area{col1: idx, row1: ar.row1 + 1, col2: idx, row2: ar.row2})
+	if order == SortOrderDescending {
+		sc.DescendingAttr = unioffice.Bool(true)
+	}
+	a.x.SortState.SortCondition = append(a.x.SortState.SortCondition, sc)
+	return SortCondition{sc}, nil
+}
+
+// ClearSortState removes the sort state of the autofilter.
+func (a AutoFilter) ClearSortState() { a.x.SortState = nil }
+
+// areaReference returns the reference of an area, e.g. "A2:D10".
+func areaReference(a area) string {
+	return fmt.Sprintf("%s%d:%s%d", reference.IndexToColumn(a.col1), a.row1, reference.IndexToColumn(a.col2), a.row2)
+}
+
+// X returns the inner wrapped XML type.
+func (sc SortCondition) X() *sml.CT_SortCondition { return sc.x }
+
+// SetCustomList sorts by the order of a list of values (e.g. "Jan", "Feb",
+// ...), where values that aren't in the list come after them.
+func (sc SortCondition) SetCustomList(values []string) {
+	sc.x.CustomListAttr = unioffice.String(strings.Join(values, ","))
+}
+
+// SetCellColor sorts the cells with the fill color of a differential style
+// first, or last if descending.
+func (sc SortCondition) SetCellColor(d DifferentialStyle) {
+	sc.x.SortByAttr = sml.ST_SortByCellColor
+	sc.x.DxfIdAttr = unioffice.Uint32(d.Index())
+}
+
+// SetFontColor sorts the cells with the font color of a differential style
+// first, or last if descending.
+func (sc SortCondition) SetFontColor(d DifferentialStyle) {
+	sc.x.SortByAttr = sml.ST_SortByFontColor
+	sc.x.DxfIdAttr = unioffice.Uint32(d.Index())
+}
+
+// X returns the inner wrapped XML type.
+func (f FilterColumn) X() *sml.CT_FilterColumn { return f.x }
+
+// Clear removes the criteria of the column.
+func (f FilterColumn) Clear() {
+	f.x.Filters = nil
+	f.x.Top10 = nil
+	f.x.CustomFilters = nil
+	f.x.DynamicFilter = nil
+	f.x.ColorFilter = nil
+	f.x.IconFilter = nil
+}
+
+// SetValues shows only the rows whose cells display one of a list of
+// values, where an empty value selects blank cells.
+func (f FilterColumn) SetValues(values ...string) {
+	f.Clear()
+	f.x.Filters = sml.NewCT_Filters()
+	for _, v := range values {
+		if v == "" {
+			f.x.Filters.BlankAttr = unioffice.Bool(true)
+			continue
+		}
+		f.x.Filters.Filter = append(f.x.Filters.Filter, &sml.CT_Filter{ValAttr: unioffice.String(v)})
+	}
+}
+
+// AddDateGroup adds the dates of the same year, month, day, hour, minute or
+// second as a time to the values that SetValues selects.
+func (f FilterColumn) AddDateGroup(t time.Time, g sml.ST_DateTimeGrouping) {
+	if f.x.Filters == nil {
+		f.Clear()
+		f.x.Filters = sml.NewCT_Filters()
+	}
+	item := sml.NewCT_DateGroupItem()
+	item.YearAttr = uint16(t.Year())
+	item.DateTimeGroupingAttr = g
+	parts := []**uint16{&item.MonthAttr, &item.DayAttr, &item.HourAttr, &item.MinuteAttr, &item.SecondAttr}
+	values := []int{int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second()}
+	for i := 0; i < int(g)-1 && i < len(parts); i++ {
+		v := uint16(values[i])
+		*parts[i] = &v
+	}
+	f.x.Filters.DateGroupItem = append(f.x.Filters.DateGroupItem, item)
+}
+
+// SetCustomFilters shows only the rows whose cells satisfy one or two
+// comparisons, either both of them if and is true or either one.
+func (f FilterColumn) SetCustomFilters(and bool, filters ...CustomFilter) {
+	f.Clear()
+	f.x.CustomFilters = sml.NewCT_CustomFilters()
+	if and {
+		f.x.CustomFilters.AndAttr = unioffice.Bool(true)
+	}
+	for _, c := range filters {
+		cf := sml.NewCT_CustomFilter()
+		cf.OperatorAttr = c.Operator
+		cf.ValAttr = unioffice.String(c.Value)
+		f.x.CustomFilters.CustomFilter = append(f.x.CustomFilters.CustomFilter, cf)
+	}
+}
+
+// SetTop10 shows only the rows with the highest or lowest numbers of the
+// column, either a number of them or a percentage.
+func (f FilterColumn) SetTop10(top, percent bool, n float64) {
+	f.Clear()
+	f.x.Top10 = sml.NewCT_Top10()
+	if !top {
+		f.x.Top10.TopAttr = unioffice.Bool(false)
+	}
+	if percent {
+		f.x.Top10.PercentAttr = unioffice.Bool(true)
+	}
+	f.x.Top10.ValAttr = n
+}
+
+// SetDynamicFilter shows only the rows whose numbers are above or below the
+// average, or whose dates are within a period relative to today.
+func (f FilterColumn) SetDynamicFilter(t sml.ST_DynamicFilterType) {
+	f.Clear()
+	f.x.DynamicFilter = sml.NewCT_DynamicFilter()
+	f.x.DynamicFilter.TypeAttr = t
+}
+
+// SetCellColorFilter shows only the rows whose cells have the fill color of
+// a differential style.
+func (f FilterColumn) SetCellColorFilter(d DifferentialStyle) {
+	f.Clear()
+	f.x.ColorFilter = sml.NewCT_ColorFilter()
+	f.x.ColorFilter.DxfIdAttr = unioffice.Uint32(d.Index())
+}
+
+// SetFontColorFilter shows only the rows whose cells have the font color of
+// a differential style.
+func (f FilterColumn) SetFontColorFilter(d DifferentialStyle) {
+	f.Clear()
+	f.x.ColorFilter = sml.NewCT_ColorFilter()
+	f.x.ColorFilter.DxfIdAttr = unioffice.Uint32(d.Index())
+	f.x.ColorFilter.CellColorAttr = unioffice.Bool(false)
+}
+
+// SetIconFilter shows only the rows whose cells show an icon of an icon set
+// of the conditional formatting, where icon is its position as in
+// ConditionalFormat.  A negative icon selects cells without an icon.
+func (f FilterColumn) SetIconFilter(set sml.ST_IconSetType, icon int) {
+	f.Clear()
+	f.x.IconFilter = sml.NewCT_IconFilter()
+	f.x.IconFilter.IconSetAttr = set
+	if icon >= 0 {
+		f.x.IconFilter.IconIdAttr = unioffice.Uint32(uint32(icon))
+	}
+}
+
+// filterCell is a cell of a column of an autofilter, which may not exist.
+type filterCell struct {
+	c   Cell
+	r   *sml.CT_Row
+	row uint32
+	v   formula.Result
+}
+
+// ApplyAutoFilter hides the rows of the autofilter of the sheet that don't
+// satisfy the criteria of its columns and shows those that do, as Excel does
+// when the filter is reapplied.  Top 10 and average filters record the
+// values they compare to.
+func (s *Sheet) ApplyAutoFilter() error {
+	af, ok := s.AutoFilter()
+	if !ok {
+		return nil
+	}
+	a, ok := s.areaOf(af.Reference())
+	if !ok {
+		return fmt.Errorf("invalid autofilter range %s", af.Reference())
+	}
+	rows := map[uint32]*sml.CT_Row{}
+	for _, r := range s._bcgb.SheetData.Row {
+		if r.RAttr != nil && *r.RAttr > a.row1 && *r.RAttr <= a.row2 {
+			rows[*r.RAttr] = r
+		}
+	}
+	hidden := map[uint32]bool{}
+	var cf *ConditionalFormatter
+	for _, fc := range af.x.FilterColumn {
+		col := a.col1 + fc.ColIdAttr
+		if col > a.col2 {
+			continue
+		}
+		cells := make([]filterCell, 0, a.row2-a.row1)
+		for rn := a.row1 + 1; rn <= a.row2; rn++ {
+			fcell := filterCell{r: rows[rn], row: rn, v: formula.MakeEmptyResult()}
+			if r := rows[rn]; r != nil {
+				for _, c := range r.C {
+					if columnOf(c) == col {
+						fcell.c = Cell{s._bdb, s, r, c}
+						fcell.v = cellValue(fcell.c)
+						break
+					}
+				}
+			}
+			cells = append(cells, fcell)
+		}
+		if fc.IconFilter != nil && cf == nil {
+			cf = s.ConditionalFormatter()
+		}
+		match := s.filterColumn(fc, col, cells, cf)
+		for i, c := range cells {
+			if !match[i] {
+				hidden[c.row] = true
+			}
+		}
+	}
+	for rn := a.row1 + 1; rn <= a.row2; rn++ {
+		switch {
+		case hidden[rn]:
+			s.Row(rn).SetHidden(true)
+		case rows[rn] != nil:
+			Row{s._bdb, s, rows[rn]}.SetHidden(false)
+		}
+	}
+	if s._bcgb.SheetPr == nil {
+		s._bcgb.SheetPr = sml.NewCT_SheetPr()
+	}
+	s._bcgb.SheetPr.FilterModeAttr = optionalBool(len(af.x.FilterColumn) > 0)
+	return nil
+}
+
+// filterColumn returns which of the cells of a column satisfy its criteria.
+func (s *Sheet) filterColumn(fc *sml.CT_FilterColumn, col uint32, cells []filterCell, cf *ConditionalFormatter) []bool {
+	match := make([]bool, len(cells))
+	numbers := []float64{}
+	for _, c := range cells {
+		if isNumber(c.v) {
+			numbers = append(numbers, c.v.ValueNumber)
+		}
+	}
+	sort.Float64s(numbers)
+	average := 0.0
+	for _, n := range numbers {
+		average += n / float64(len(numbers))
+	}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	// the value a top 10 filter compares to, which Excel records
+	limit, top := math.Inf(-1), true
+	if t := fc.Top10; t != nil && len(numbers) > 0 {
+		n := int(t.ValAttr)
+		if t.PercentAttr != nil && *t.PercentAttr {
+			n = int(float64(len(numbers)) * t.ValAttr / 100)
+		}
+		if n < 1 {
+			n = 1
+		}
+		if n > len(numbers) {
+			n = len(numbers)
+		}
+		top = t.TopAttr == nil || *t.TopAttr
+		if top {
+			limit = numbers[len(numbers)-n]
+		} else {
+			limit = numbers[n-1]
+		}
+		t.FilterValAttr = unioffice.Float64(limit)
+	}
+	if d := fc.DynamicFilter; d != nil && len(numbers) > 0 &&
+		(d.TypeAttr == sml.ST_DynamicFilterTypeAboveAverage || d.TypeAttr == sml.ST_DynamicFilterTypeBelowAverage) {
+		d.ValAttr = unioffice.Float64(average)
+	}
+
+	for i, c := range cells {
+		text := ""
+		if c.c._dbd != nil {
+			text = c.c.GetFormattedValue()
+		}
+		blank := c.v.Type == formula.ResultTypeEmpty || strings.TrimSpace(text) == ""
+		switch {
+		case fc.Filters != nil:
+			f := fc.Filters
+			match[i] = blank && f.BlankAttr != nil && *f.BlankAttr
+			for _, v := range f.Filter {
+				if !blank && v.ValAttr != nil && strings.EqualFold(*v.ValAttr, text) {
+					match[i] = true
+				}
+			}
+			if isNumber(c.v) && !match[i] {
+				t := s._bdb.Epoch().Add(time.Duration(c.v.ValueNumber * 24 * float64(time.Hour)))
+				for _, g := range f.DateGroupItem {
+					match[i] = match[i] || inDateGroup(g, t)
+				}
+			}
+		case fc.CustomFilters != nil:
+			f := fc.CustomFilters
+			and := f.AndAttr != nil && *f.AndAttr
+			match[i] = and
+			for _, cond := range f.CustomFilter {
+				m := customFilter(cond, c.v, text, blank)
+				if and {
+					match[i] = match[i] && m
+				} else {
+					match[i] = match[i] || m
+				}
+			}
+		case fc.Top10 != nil:
+			match[i] = isNumber(c.v) && (top && c.v.ValueNumber >= limit || !top && c.v.ValueNumber <= limit)
+		case fc.DynamicFilter != nil:
+			switch t := fc.DynamicFilter.TypeAttr; t {
+			case sml.ST_DynamicFilterTypeUnset, sml.ST_DynamicFilterTypeNull:
+				match[i] = true
+			case sml.ST_DynamicFilterTypeAboveAverage:
+				match[i] = isNumber(c.v) && c.v.ValueNumber > average
+			case sml.ST_DynamicFilterTypeBelowAverage:
+				match[i] = isNumber(c.v) && c.v.ValueNumber < average
+			default:
+				if isNumber(c.v) {
+					day := s._bdb.Epoch().AddDate(0, 0, int(math.Floor(c.v.ValueNumber)))
+					match[i] = inPeriod(t, day, today)
+				}
+			}
+		case fc.ColorFilter != nil:
+			match[i] = s.colorFilter(fc.ColorFilter, c, col)
+		case fc.IconFilter != nil:
+			res := cf.format(c.c, col, c.row)
+			if fc.IconFilter.IconIdAttr == nil {
+				match[i] = res.IconSet == sml.ST_IconSetTypeUnset
+			} else {
+				match[i] = res.IconSet == fc.IconFilter.IconSetAttr && uint32(res.Icon) == *fc.IconFilter.IconIdAttr
+			}
+		default:
+			match[i] = true
+		}
+	}
+	return match
+}
+
+// inDateGroup returns true if a time has the same parts as a date group item
+// up to its grouping.
+func inDateGroup(g *sml.CT_DateGroupItem, t time.Time) bool {
+	values := []int{t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second()}
+	parts := []*uint16{&g.YearAttr, g.MonthAttr, g.DayAttr, g.HourAttr, g.MinuteAttr, g.SecondAttr}
+	for i := 0; i < int(g.DateTimeGroupingAttr) && i < len(parts); i++ {
+		if parts[i] == nil || int(*parts[i]) != values[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// customFilter returns true if a value satisfies a comparison of a custom
+// filter.  Numbers are compared with numbers and otherwise the displayed
+// text is compared ignoring case.
+func customFilter(cond *sml.CT_CustomFilter, v formula.Result, text string, blank bool) bool {
+	val := ""
+	if cond.ValAttr != nil {
+		val = *cond.ValAttr
+	}
+	c := 0
+	n, err := strconv.ParseFloat(val, 64)
+	switch {
+	case err == nil && isNumber(v):
+		switch {
+		case v.ValueNumber < n:
+			c = -1
+		case v.ValueNumber > n:
+			c = 1
+		}
+	case cond.OperatorAttr == sml.ST_FilterOperatorEqual || cond.OperatorAttr == sml.ST_FilterOperatorUnset:
+		if val == "" {
+			return blank
+		}
+		return wildcardMatch(strings.ToLower(val), strings.ToLower(text))
+	case cond.OperatorAttr == sml.ST_FilterOperatorNotEqual:
+		if val == "" {
+			return !blank
+		}
+		return !wildcardMatch(strings.ToLower(val), strings.ToLower(text))
+	case blank || isNumber(v) != (err == nil):
+		// text and numbers aren't ordered relative to each other
+		return false
+	default:
+		c = strings.Compare(strings.ToLower(text), strings.ToLower(val))
+	}
+	switch cond.OperatorAttr {
+	case sml.ST_FilterOperatorLessThan:
+		return c < 0
+	case sml.ST_FilterOperatorLessThanOrEqual:
+		return c <= 0
+	case sml.ST_FilterOperatorNotEqual:
+		return c != 0
+	case sml.ST_FilterOperatorGreaterThanOrEqual:
+		return c >= 0
+	case sml.ST_FilterOperatorGreaterThan:
+		return c > 0
+	}
+	return c == 0
+}
+
+// wildcardMatch returns true if text matches a pattern where * matches any
+// text, ? any character and ~ escapes the next character.
+func wildcardMatch(pattern, text string) bool {
+	p, t := []rune(pattern), []rune(text)
+	// the positions to resume from after the last *
+	star, next := -1, 0
+	i, j := 0, 0
+	for j < len(t) {
+		switch {
+		case i < len(p) && p[i] == '*':
+			star, next = i, j
+			i++
+		case i < len(p) && p[i] == '~' && i+1 < len(p) && p[i+1] == t[j]:
+			i += 2
+			j++
+		case i < len(p) && p[i] != '~' && (p[i] == '?' || p[i] == t[j]):
+			i++
+			j++
+		case star >= 0:
+			next++
+			i, j = star+1, next
+		default:
+			return false
+		}
+	}
+	for i < len(p) && p[i] == '*' {
+		i++
+	}
+	return i == len(p)
+}
+
+// inPeriod returns true if a day is within a period relative to today, where
+// weeks start on Sunday.
+func inPeriod(t sml.ST_DynamicFilterType, day, today time.Time) bool {
+	diff := int(math.Round(day.Sub(today).Hours() / 24))
+	sunday := -int(today.Weekday())
+	month := func(d time.Time) int { return d.Year()*12 + int(d.Month()) - 1 }
+	quarter := func(d time.Time) int { return month(d) / 3 }
+	switch t {
+	case sml.ST_DynamicFilterTypeTomorrow:
+		return diff == 1
+	case sml.ST_DynamicFilterTypeToday:
+		return diff == 0
+	case sml.ST_DynamicFilterTypeYesterday:
+		return diff == -1
+	case sml.ST_DynamicFilterTypeNextWeek:
+		return diff >= sunday+7 && diff < sunday+14
+	case sml.ST_DynamicFilterTypeThisWeek:
+		return diff >= sunday && diff < sunday+7
+	case sml.ST_DynamicFilterTypeLastWeek:
+		return diff >= sunday-7 && diff < sunday
+	case sml.ST_DynamicFilterTypeNextMonth:
+		return month(day) == month(today)+1
+	case sml.ST_DynamicFilterTypeThisMonth:
+		return month(day) == month(today)
+	case sml.ST_DynamicFilterTypeLastMonth:
+		return month(day) == month(today)-1
+	case sml.ST_DynamicFilterTypeNextQuarter:
+		return quarter(day) == quarter(today)+1
+	case sml.ST_DynamicFilterTypeThisQuarter:
+		return quarter(day) == quarter(today)
+	case sml.ST_DynamicFilterTypeLastQuarter:
+		return quarter(day) == quarter(today)-1
+	case sml.ST_DynamicFilterTypeNextYear:
+		return day.Year() == today.Year()+1
+	case sml.ST_DynamicFilterTypeThisYear:
+		return day.Year() == today.Year()
+	case sml.ST_DynamicFilterTypeLastYear:
+		return day.Year() == today.Year()-1
+	case sml.ST_DynamicFilterTypeYearToDate:
+		return day.Year() == today.Year() && diff <= 0
+	}
+	if t >= sml.ST_DynamicFilterTypeQ1 && t <= sml.ST_DynamicFilterTypeQ4 {
+		return (int(day.Month())-1)/3 == int(t-sml.ST_DynamicFilterTypeQ1)
+	}
+	if t >= sml.ST_DynamicFilterTypeM1 && t <= sml.ST_DynamicFilterTypeM12 {
+		return int(day.Month())-1 == int(t-sml.ST_DynamicFilterTypeM1)
+	}
+	return false
+}
+
+// colorFilter returns true if a cell has the fill or font color of a color
+// filter.
+func (s *Sheet) colorFilter(f *sml.CT_ColorFilter, c filterCell, col uint32) bool {
+	var dxf *sml.CT_Dxf
+	if dxfs := s._bdb.StyleSheet.X().Dxfs; f.DxfIdAttr != nil && dxfs != nil && int(*f.DxfIdAttr) < len(dxfs.Dxf) {
+		dxf = dxfs.Dxf[*f.DxfIdAttr]
+	}
+	fill, font := s.cellColors(c.r, c.c._dbd, col)
+	if f.CellColorAttr == nil || *f.CellColorAttr {
+		want, ok := uint32(0), false
+		if dxf != nil && dxf.Fill != nil {
+			want, ok = s._bdb.dxfFillRGB(dxf.Fill)
+		}
+		return fill.ok == ok && (!ok || fill.rgb == want)
+	}
+	want, ok := uint32(0), false
+	if dxf != nil && dxf.Font != nil && len(dxf.Font.Color) > 0 {
+		want, ok = s._bdb.rgb(dxf.Font.Color[0])
+	}
+	return font.ok == ok && (!ok || font.rgb == want)
+}
+
+// cellColor is a color of a cell as 0xRRGGBB, if ok is true.
+type cellColor struct {
+	rgb uint32
+	ok  bool
+}
+
+// cellColors returns the fill and font colors of the cell of a row at a
+// column, where the row and cell may not exist.
+func (s *Sheet) cellColors(r *sml.CT_Row, c *sml.CT_Cell, col uint32) (cellColor, cellColor) {
+	var fill, font cellColor
+	ss := s._bdb.StyleSheet.X()
+	xf := s.styleIndex(r, c, col)
+	if ss.CellXfs == nil || xf >= uint32(len(ss.CellXfs.Xf)) {
+		return fill, font
+	}
+	x := ss.CellXfs.Xf[xf]
+	if x.FillIdAttr != nil && ss.Fills != nil && *x.FillIdAttr < uint32(len(ss.Fills.Fill)) {
+		if p := ss.Fills.Fill[*x.FillIdAttr].PatternFill; p != nil && p.PatternTypeAttr != sml.ST_PatternTypeNone && p.PatternTypeAttr != sml.ST_PatternTypeUnset {
+			fill.rgb, fill.ok = s._bdb.rgb(p.FgColor)
+		}
+	}
+	if x.FontIdAttr != nil && ss.Fonts != nil && *x.FontIdAttr < uint32(len(ss.Fonts.Font)) {
+		if f := ss.Fonts.Font[*x.FontIdAttr]; len(f.Color) > 0 {
+			font.rgb, font.ok = s._bdb.rgb(f.Color[0])
+		}
+	}
+	return fill, font
+}
+
+// dxfFillRGB returns the color of the fill of a differential style, where
+// solid fills use their background color rather than their foreground
+// color.
+func (wb *Workbook) dxfFillRGB(f *sml.CT_Fill) (uint32, bool) {
+	p := f.PatternFill
+	if p == nil {
+		return 0, false
+	}
+	c := p.FgColor
+	if p.PatternTypeAttr == sml.ST_PatternTypeUnset || p.PatternTypeAttr == sml.ST_PatternTypeSolid {
+		if p.BgColor != nil {
+			c = p.BgColor
+		}
+	}
+	return wb.rgb(c)
+}
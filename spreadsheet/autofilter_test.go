@@ -0,0 +1,279 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/unidoc/unioffice/color"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+)
+
+// filterSheet returns a sheet with an autofilter on A1:E7, with names in A,
+// amounts in B, dates relative to today in C, colors in D and fixed dates in
+// E.
+func filterSheet(t *testing.T) (*Workbook, Sheet, AutoFilter) {
+	t.Helper()
+	wb := New()
+	s := wb.AddSheet()
+	for i, h := range []string{"Name", "Amount", "Day", "Color", "Date"} {
+		s.Cell(fmt.Sprintf("%c1", 'A'+i)).SetString(h)
+	}
+	for i, n := range []string{"apple", "Pear", "banana", "a*b", "", "Apple pie"} {
+		if n != "" {
+			s.Cell(fmt.Sprintf("A%d", i+2)).SetString(n)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		s.Cell(fmt.Sprintf("B%d", i+2)).SetNumber(float64(10 * (i + 1)))
+	}
+	s.Cell("B7").SetString("n/a")
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for i, d := range []time.Time{
+		today, today.AddDate(0, 0, -1), today.AddDate(0, 0, 1),
+		today.AddDate(0, 0, -30), today.AddDate(-1, 0, 0), today.AddDate(1, 0, 0),
+	} {
+		s.Cell(fmt.Sprintf("C%d", i+2)).SetDate(d)
+	}
+
+	yellow := wb.StyleSheet.AddCellStyle()
+	fill := wb.StyleSheet.Fills().AddFill()
+	pf := fill.SetPatternFill()
+	pf.SetPattern(sml.ST_PatternTypeSolid)
+	pf.SetFgColor(color.Yellow)
+	yellow.SetFill(fill)
+	red := wb.StyleSheet.AddCellStyle()
+	f := wb.StyleSheet.AddFont()
+	f.SetColor(color.Red)
+	red.SetFont(f)
+	for i, cs := range []*CellStyle{&yellow, &red, &yellow, nil, &red, nil} {
+		c := s.Cell(fmt.Sprintf("D%d", i+2))
+		c.SetString("x")
+		if cs != nil {
+			c.SetStyle(*cs)
+		}
+	}
+
+	for i, d := range []time.Time{
+		time.Date(2023, 3, 15, 10, 0, 0, 0, time.UTC), time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2023, 3, 15, 18, 0, 0, 0, time.UTC),
+	} {
+		s.Cell(fmt.Sprintf("E%d", i+2)).SetTime(d)
+	}
+	s.SetAutoFilter("A1:E7")
+	af, ok := s.AutoFilter()
+	if !ok {
+		t.Fatal("expected an autofilter")
+	}
+	return wb, s, af
+}
+
+// visibleRows returns the rows of the autofilter that aren't hidden.
+func visibleRows(s Sheet) string {
+	got := []string{}
+	for rn := uint32(2); rn <= 7; rn++ {
+		if !s.Row(rn).IsHidden() {
+			got = append(got, fmt.Sprint(rn))
+		}
+	}
+	return strings.Join(got, " ")
+}
+
+func filterColumn(t *testing.T, af AutoFilter, col string) FilterColumn {
+	t.Helper()
+	fc, err := af.Column(col)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fc
+}
+
+func TestApplyAutoFilter(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		col  string
+		set  func(wb *Workbook, fc FilterColumn)
+		exp  string
+	}{
+		{"values", "A", func(wb *Workbook, fc FilterColumn) { fc.SetValues("APPLE", "") }, "2 6"},
+		{"formatted numbers", "B", func(wb *Workbook, fc FilterColumn) { fc.SetValues("20", "40") }, "3 5"},
+		{"date group", "E", func(wb *Workbook, fc FilterColumn) {
+			fc.AddDateGroup(time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), sml.ST_DateTimeGroupingMonth)
+		}, "2 4 7"},
+		{"date group by hour", "E", func(wb *Workbook, fc FilterColumn) {
+			fc.AddDateGroup(time.Date(2023, 3, 15, 18, 30, 0, 0, time.UTC), sml.ST_DateTimeGroupingHour)
+		}, "7"},
+
+		{"ends with", "A", func(wb *Workbook, fc FilterColumn) {
+			fc.SetCustomFilters(false, CustomFilter{sml.ST_FilterOperatorEqual, "*PIE"})
+		}, "7"},
+		{"escaped wildcard", "A", func(wb *Workbook, fc FilterColumn) {
+			fc.SetCustomFilters(false, CustomFilter{sml.ST_FilterOperatorEqual, "a~*b"})
+		}, "5"},
+		{"single character", "A", func(wb *Workbook, fc FilterColumn) {
+			fc.SetCustomFilters(false, CustomFilter{sml.ST_FilterOperatorEqual, "?ear"})
+		}, "3"},
+		{"doesn't begin with", "A", func(wb *Workbook, fc FilterColumn) {
+			fc.SetCustomFilters(false, CustomFilter{sml.ST_FilterOperatorNotEqual, "a*"})
+		}, "3 4 6"},
+		{"not blank", "A", func(wb *Workbook, fc FilterColumn) {
+			fc.SetCustomFilters(false, CustomFilter{sml.ST_FilterOperatorNotEqual, ""})
+		}, "2 3 4 5 7"},
+		{"text after", "A", func(wb *Workbook, fc FilterColumn) {
+			fc.SetCustomFilters(false, CustomFilter{sml.ST_FilterOperatorGreaterThan, "b"})
+		}, "3 4"},
+		{"and", "B", func(wb *Workbook, fc FilterColumn) {
+			fc.SetCustomFilters(true,
+				CustomFilter{sml.ST_FilterOperatorGreaterThanOrEqual, "20"},
+				CustomFilter{sml.ST_FilterOperatorLessThan, "50"})
+		}, "3 4 5"},
+		{"or", "B", func(wb *Workbook, fc FilterColumn) {
+			fc.SetCustomFilters(false,
+				CustomFilter{sml.ST_FilterOperatorLessThan, "15"},
+				CustomFilter{sml.ST_FilterOperatorGreaterThan, "45"})
+		}, "2 6"},
+
+		{"top", "B", func(wb *Workbook, fc FilterColumn) { fc.SetTop10(true, false, 2) }, "5 6"},
+		{"bottom percent", "B", func(wb *Workbook, fc FilterColumn) { fc.SetTop10(false, true, 40) }, "2 3"},
+		{"above average", "B", func(wb *Workbook, fc FilterColumn) {
+			fc.SetDynamicFilter(sml.ST_DynamicFilterTypeAboveAverage)
+		}, "5 6"},
+		{"below average", "B", func(wb *Workbook, fc FilterColumn) {
+			fc.SetDynamicFilter(sml.ST_DynamicFilterTypeBelowAverage)
+		}, "2 3"},
+		{"today", "C", func(wb *Workbook, fc FilterColumn) { fc.SetDynamicFilter(sml.ST_DynamicFilterTypeToday) }, "2"},
+		{"yesterday", "C", func(wb *Workbook, fc FilterColumn) { fc.SetDynamicFilter(sml.ST_DynamicFilterTypeYesterday) }, "3"},
+		{"tomorrow", "C", func(wb *Workbook, fc FilterColumn) { fc.SetDynamicFilter(sml.ST_DynamicFilterTypeTomorrow) }, "4"},
+		{"march", "E", func(wb *Workbook, fc FilterColumn) { fc.SetDynamicFilter(sml.ST_DynamicFilterTypeM3) }, "2 4 5 7"},
+		{"third quarter", "E", func(wb *Workbook, fc FilterColumn) { fc.SetDynamicFilter(sml.ST_DynamicFilterTypeQ3) }, "3"},
+
+		{"cell color", "D", func(wb *Workbook, fc FilterColumn) {
+			d := wb.StyleSheet.AddDifferentialStyle()
+			d.Fill().SetPatternFill().SetBgColor(color.Yellow)
+			fc.SetCellColorFilter(d)
+		}, "2 4"},
+		{"no cell color", "D", func(wb *Workbook, fc FilterColumn) {
+			fc.SetCellColorFilter(wb.StyleSheet.AddDifferentialStyle())
+		}, "3 5 6 7"},
+		{"font color", "D", func(wb *Workbook, fc FilterColumn) {
+			d := wb.StyleSheet.AddDifferentialStyle()
+			d.X().Font = sml.NewCT_Font()
+			d.X().Font.Color = []*sml.CT_Color{{RgbAttr: color.Red.AsRGBAString()}}
+			fc.SetFontColorFilter(d)
+		}, "3 6"},
+	} {
+		wb, s, af := filterSheet(t)
+		tc.set(wb, filterColumn(t, af, tc.col))
+		if err := s.ApplyAutoFilter(); err != nil {
+			t.Fatal(err)
+		}
+		if got := visibleRows(s); got != tc.exp {
+			t.Errorf("%s: expected the rows %q, got %q", tc.name, tc.exp, got)
+		}
+	}
+}
+
+func TestApplyAutoFilterColumns(t *testing.T) {
+	_, s, af := filterSheet(t)
+	// the criteria of all columns must be satisfied
+	filterColumn(t, af, "A").SetCustomFilters(false, CustomFilter{sml.ST_FilterOperatorEqual, "*a*"})
+	filterColumn(t, af, "B").SetTop10(true, false, 3)
+	if err := s.ApplyAutoFilter(); err != nil {
+		t.Fatal(err)
+	}
+	if got := visibleRows(s); got != "4 5" {
+		t.Errorf("expected the rows 4 and 5, got %q", got)
+	}
+	x := af.X()
+	if len(x.FilterColumn) != 2 || x.FilterColumn[1].ColIdAttr != 1 || x.FilterColumn[1].Top10.FilterValAttr == nil || *x.FilterColumn[1].Top10.FilterValAttr != 30 {
+		t.Errorf("expected the top 10 filter to record the value 30")
+	}
+	if p := s.X().SheetPr; p == nil || p.FilterModeAttr == nil || !*p.FilterModeAttr {
+		t.Errorf("expected the sheet to be in filter mode")
+	}
+
+	// rows are shown again once the filters are cleared
+	af.ClearFilters()
+	if err := s.ApplyAutoFilter(); err != nil {
+		t.Fatal(err)
+	}
+	if got := visibleRows(s); got != "2 3 4 5 6 7" {
+		t.Errorf("expected all rows, got %q", got)
+	}
+	if p := s.X().SheetPr; p.FilterModeAttr != nil {
+		t.Errorf("expected the sheet not to be in filter mode")
+	}
+
+	if _, err := af.Column("F"); err == nil {
+		t.Errorf("expected an error for a column outside of the autofilter")
+	}
+	if _, err := af.Column("1"); err == nil {
+		t.Errorf("expected an error for an invalid column")
+	}
+}
+
+func TestAutoFilterSortState(t *testing.T) {
+	wb, s, af := filterSheet(t)
+	sc, err := af.AddSortCondition("B", SortOrderDescending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc, err = af.AddSortCondition("A", SortOrderAscending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc.SetCustomList([]string{"Pear", "apple"})
+	d := wb.StyleSheet.AddDifferentialStyle()
+	d.Fill().SetPatternFill().SetBgColor(color.Yellow)
+	sc, err = af.AddSortCondition("D", SortOrderAscending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc.SetCellColor(d)
+	if _, err := af.AddSortCondition("F", SortOrderAscending); err == nil {
+		t.Errorf("expected an error sorting by a column outside of the autofilter")
+	}
+	filterColumn(t, af, "B").SetTop10(true, false, 2)
+	if err := s.ApplyAutoFilter(); err != nil {
+		t.Fatal(err)
+	}
+
+	s = saveAndRead(t, wb).Sheets()[0]
+	af, ok := s.AutoFilter()
+	if !ok || af.Reference() != "A1:E7" {
+		t.Fatalf("expected the autofilter to be saved")
+	}
+	ss := af.X().SortState
+	if ss == nil || ss.RefAttr != "A2:E7" || len(ss.SortCondition) != 3 {
+		t.Fatalf("expected the sort state of A2:E7 with three conditions, got %+v", ss)
+	}
+	c := ss.SortCondition
+	if c[0].RefAttr != "B2:B7" || c[0].DescendingAttr == nil || !*c[0].DescendingAttr {
+		t.Errorf("expected B2:B7 to be sorted descending, got %s", c[0].RefAttr)
+	}
+	if c[1].RefAttr != "A2:A7" || c[1].CustomListAttr == nil || *c[1].CustomListAttr != "Pear,apple" || c[1].DescendingAttr != nil {
+		t.Errorf("expected A2:A7 to be sorted by a custom list, got %s", c[1].RefAttr)
+	}
+	if c[2].SortByAttr != sml.ST_SortByCellColor || c[2].DxfIdAttr == nil || *c[2].DxfIdAttr != d.Index() {
+		t.Errorf("expected D2:D7 to be sorted by cell color, got %s", c[2].SortByAttr)
+	}
+	if got := visibleRows(s); got != "5 6" {
+		t.Errorf("expected the hidden rows to be saved, got %q", got)
+	}
+
+	af.ClearSortState()
+	if af.X().SortState != nil {
+		t.Errorf("expected the sort state to be removed")
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/unidoc/unioffice/color"
+	"github.com/unidoc/unioffice/schema/soo/dml"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/format"
+)
+
+// defaultThemeColors are the colors of the default Office theme in the order
+// of the theme indexes of styles.
+var defaultThemeColors = [...]uint32{
+	0xFFFFFF, 0x000000, 0xE7E6E6, 0x44546A, 0x4472C4, 0xED7D31,
+	0xA5A5A5, 0xFFC000, 0x5B9BD5, 0x70AD47, 0x0563C1, 0x954F72,
+}
+
+// rgb returns the color of a style as 0xRRGGBB, or false for automatic
+// colors.  Theme colors are those of the theme of the workbook, lightened or
+// darkened by their tint.
+func (wb *Workbook) rgb(c *sml.CT_Color) (uint32, bool) {
+	if c == nil || c.AutoAttr != nil && *c.AutoAttr {
+		return 0, false
+	}
+	var v uint32
+	switch {
+	case c.RgbAttr != nil:
+		s := *c.RgbAttr
+		if len(s) == 8 {
+			s = s[2:]
+		}
+		n, err := strconv.ParseUint(s, 16, 32)
+		if err != nil {
+			return 0, false
+		}
+		v = uint32(n)
+	case c.ThemeAttr != nil:
+		var ok bool
+		if v, ok = wb.themeColor(*c.ThemeAttr); !ok {
+			return 0, false
+		}
+	case c.IndexedAttr != nil:
+		var ok bool
+		if v, ok = wb.indexedColor(*c.IndexedAttr); !ok {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	if c.TintAttr != nil && *c.TintAttr != 0 {
+		v = tint(v, *c.TintAttr)
+	}
+	return v, true
+}
+
+// color returns the color of a style, which is color.Auto for automatic
+// colors.
+func (wb *Workbook) color(c *sml.CT_Color) color.Color {
+	v, ok := wb.rgb(c)
+	if !ok {
+		return color.Auto
+	}
+	return rgbColor(v)
+}
+
+func rgbColor(v uint32) color.Color {
+	return color.RGB(uint8(v>>16), uint8(v>>8), uint8(v))
+}
+
+// themeColor returns a color of the theme by its index in styles, where the
+// light and dark colors come in the opposite order from the theme.
+func (wb *Workbook) themeColor(i uint32) (uint32, bool) {
+	if i >= uint32(len(defaultThemeColors)) {
+		return 0, false
+	}
+	if len(wb._bgea) == 0 || wb._bgea[0].ThemeElements == nil || wb._bgea[0].ThemeElements.ClrScheme == nil {
+		return defaultThemeColors[i], true
+	}
+	cs := wb._bgea[0].ThemeElements.ClrScheme
+	colors := [...]*dml.CT_Color{cs.Lt1, cs.Dk1, cs.Lt2, cs.Dk2, cs.Accent1, cs.Accent2,
+		cs.Accent3, cs.Accent4, cs.Accent5, cs.Accent6, cs.Hlink, cs.FolHlink}
+	c := colors[i]
+	hex := ""
+	switch {
+	case c == nil:
+	case c.SrgbClr != nil:
+		hex = c.SrgbClr.ValAttr
+	case c.SysClr != nil && c.SysClr.LastClrAttr != nil:
+		hex = *c.SysClr.LastClrAttr
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return defaultThemeColors[i], true
+	}
+	return uint32(n), true
+}
+
+// indexedColor returns a color of the indexed palette of the workbook, which
+// is the default palette unless the styles replace it.
+func (wb *Workbook) indexedColor(i uint32) (uint32, bool) {
+	ss := wb.StyleSheet.X()
+	if ss.Colors != nil && ss.Colors.IndexedColors != nil && i < uint32(len(ss.Colors.IndexedColors.RgbColor)) {
+		if c := ss.Colors.IndexedColors.RgbColor[i]; c.RgbAttr != nil {
+			return wb.rgb(&sml.CT_Color{RgbAttr: c.RgbAttr})
+		}
+	}
+	c, ok := format.IndexedColor(i)
+	if !ok {
+		return 0, false
+	}
+	n, _ := strconv.ParseUint(*c.AsRGBString(), 16, 32)
+	return uint32(n), true
+}
+
+// tint lightens a color towards white for positive tints and darkens it
+// towards black for negative ones, changing its luminance as Excel does.
+func tint(v uint32, t float64) uint32 {
+	h, l, s := toHLS(v)
+	if t < 0 {
+		l *= 1 + t
+	} else {
+		l = l*(1-t) + t
+	}
+	return fromHLS(h, l, s)
+}
+
+func toHLS(v uint32) (float64, float64, float64) {
+	r, g, b := float64(v>>16&0xFF)/255, float64(v>>8&0xFF)/255, float64(v&0xFF)/255
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l := (max + min) / 2
+	if max == min {
+		return 0, l, 0
+	}
+	d := max - min
+	s := d / (max + min)
+	if l > 0.5 {
+		s = d / (2 - max - min)
+	}
+	var h float64
+	switch max {
+	case r:
+		h = (g - b) / d
+	case g:
+		h = 2 + (b-r)/d
+	default:
+		h = 4 + (r-g)/d
+	}
+	h /= 6
+	if h < 0 {
+		h++
+	}
+	return h, l, s
+}
+
+func fromHLS(h, l, s float64) uint32 {
+	if s == 0 {
+		c := uint32(math.Floor(l*255 + 0.5))
+		return c<<16 | c<<8 | c
+	}
+	q := l * (1 + s)
+	if l > 0.5 {
+		q = l + s - l*s
+	}
+	p := 2*l - q
+	channel := func(t float64) uint32 {
+		switch {
+		case t < 0:
+			t++
+		case t > 1:
+			t--
+		}
+		var c float64
+		switch {
+		case t < 1.0/6:
+			c = p + (q-p)*6*t
+		case t < 0.5:
+			c = q
+		case t < 2.0/3:
+			c = p + (q-p)*(2.0/3-t)*6
+		default:
+			c = p
+		}
+		return uint32(math.Floor(c*255 + 0.5))
+	}
+	return channel(h+1.0/3)<<16 | channel(h)<<8 | channel(h-1.0/3)
+}
+
+// mixRGB returns the color at a fraction of the way from a to b.
+func mixRGB(a, b uint32, f float64) uint32 {
+	mix := func(shift uint) uint32 {
+		x, y := float64(a>>shift&0xFF), float64(b>>shift&0xFF)
+		return uint32(math.Floor(x+(y-x)*f+0.5)) << shift
+	}
+	return mix(16) | mix(8) | mix(0)
+}
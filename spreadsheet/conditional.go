@@ -0,0 +1,689 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/unidoc/unioffice/color"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+)
+
+// ConditionalFormat is the formatting that the conditional formatting rules
+// of a sheet give a cell, as Excel shows it.
+type ConditionalFormat struct {
+	// Style is the differential style of the rules that apply.  If several
+	// apply, it combines their fonts, fills, borders, number formats and
+	// alignments, taking each from the rule with the highest priority that
+	// sets it, and isn't one of the styles of the workbook.  Its X() is nil
+	// if no rule applies.
+	Style DifferentialStyle
+	// Styles are the differential styles of the rules that apply, highest
+	// priority first.
+	Styles []DifferentialStyle
+
+	// Color is the color that a color scale gives the cell, or color.Auto.
+	Color color.Color
+
+	// DataBar is the length of a data bar as a fraction of the width of the
+	// cell and DataBarColor is its color, which is color.Auto if there is no
+	// data bar.
+	DataBar      float64
+	DataBarColor color.Color
+
+	// IconSet is the icon set that shows an icon in the cell, or
+	// sml.ST_IconSetTypeUnset.  Icon is the position of the icon in the set,
+	// where 0 is the icon for the lowest values of a set that isn't reversed
+	// (e.g. the red down arrow of 3Arrows).
+	IconSet sml.ST_IconSetType
+	Icon    int
+
+	// HideValue is true if a data bar or icon set is shown without the value
+	// of the cell.
+	HideValue bool
+}
+
+// ConditionalFormatter evaluates the conditional formatting of a sheet.  It
+// computes the statistics that rules such as top10, aboveAverage and color
+// scales need once for each rule, so it should be reused for the cells of a
+// sheet as long as their values don't change.
+type ConditionalFormatter struct {
+	s     *Sheet
+	rules []*cfRule
+	ctx   formula.Context
+	ev    formula.Evaluator
+	today int
+}
+
+// cfRule is a conditional formatting rule along with the ranges it formats.
+type cfRule struct {
+	rule  *sml.CT_CfRule
+	areas []area
+	// top is the first cell of the ranges, which the formulas of the rule
+	// are relative to.
+	top reference.CellReference
+
+	scanned bool
+	numbers []float64      // sorted numbers of the ranges
+	counts  map[string]int // number of times each value appears
+}
+
+// ConditionalFormatter returns a formatter for the conditional formatting
+// rules of the sheet, which it evaluates in order of priority.
+func (s *Sheet) ConditionalFormatter() *ConditionalFormatter {
+	cf := &ConditionalFormatter{s: s}
+	for _, x := range s._bcgb.ConditionalFormatting {
+		if x.SqrefAttr == nil {
+			continue
+		}
+		areas := []area{}
+		for _, ref := range *x.SqrefAttr {
+			if a, ok := s.areaOf(ref); ok {
+				areas = append(areas, a)
+			}
+		}
+		if len(areas) == 0 {
+			continue
+		}
+		top := reference.CellReference{Column: reference.IndexToColumn(areas[0].col1), ColumnIdx: areas[0].col1, RowIdx: areas[0].row1}
+		for _, r := range x.CfRule {
+			cf.rules = append(cf.rules, &cfRule{rule: r, areas: areas, top: top})
+		}
+	}
+	sort.SliceStable(cf.rules, func(i, j int) bool {
+		return cf.rules[i].rule.PriorityAttr < cf.rules[j].rule.PriorityAttr
+	})
+	if len(cf.rules) > 0 {
+		cf.ctx = s.FormulaContext()
+		cf.ev = formula.NewEvaluator()
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		cf.today = int(math.Round(today.Sub(s._bdb.Epoch()).Hours() / 24))
+	}
+	return cf
+}
+
+// ConditionalFormat returns the formatting that conditional formatting gives
+// a cell (e.g. "B2"), which needn't exist.  ConditionalFormatter should be
+// used instead to format many cells.
+func (s *Sheet) ConditionalFormat(cellRef string) ConditionalFormat {
+	return s.ConditionalFormatter().Format(cellRef)
+}
+
+// Format returns the formatting that conditional formatting gives a cell
+// (e.g. "B2"), which needn't exist.
+func (cf *ConditionalFormatter) Format(cellRef string) ConditionalFormat {
+	ref, err := reference.ParseCellReference(cellRef)
+	if err != nil {
+		return ConditionalFormat{Color: color.Auto, DataBarColor: color.Auto}
+	}
+	c := Cell{_ebb: cf.s._bdb, _dbag: cf.s}
+	c._dbd = cf.s.findCell(ref)
+	return cf.format(c, ref.ColumnIdx, ref.RowIdx)
+}
+
+// format returns the formatting of the cell at a column and row, which may
+// not exist.
+func (cf *ConditionalFormatter) format(c Cell, col, row uint32) ConditionalFormat {
+	res := ConditionalFormat{Color: color.Auto, DataBarColor: color.Auto}
+	k := cellKey{cf.s._bcgb, col, row}
+	v := formula.MakeEmptyResult()
+	if c._dbd != nil {
+		v = cellValue(c)
+	}
+	scale, bar, icons := false, false, false
+	for _, r := range cf.rules {
+		within := false
+		for _, a := range r.areas {
+			within = within || a.contains(k)
+		}
+		if !within {
+			continue
+		}
+		matched := false
+		switch r.rule.TypeAttr {
+		case sml.ST_CfTypeColorScale:
+			var rgb uint32
+			if rgb, matched = cf.colorScale(r, v); matched && !scale {
+				res.Color, scale = rgbColor(rgb), true
+			}
+		case sml.ST_CfTypeDataBar:
+			var length float64
+			if length, matched = cf.dataBar(r, v); matched && !bar {
+				res.DataBar, res.DataBarColor, bar = length, cf.s._bdb.color(r.rule.DataBar.Color), true
+				if res.DataBarColor.IsAuto() {
+					res.DataBarColor = rgbColor(0x638EC6)
+				}
+				res.HideValue = res.HideValue || r.rule.DataBar.ShowValueAttr != nil && !*r.rule.DataBar.ShowValueAttr
+			}
+		case sml.ST_CfTypeIconSet:
+			var icon int
+			if icon, matched = cf.icon(r, v); matched && !icons {
+				res.IconSet, res.Icon, icons = r.rule.IconSet.IconSetAttr, icon, true
+				if res.IconSet == sml.ST_IconSetTypeUnset {
+					res.IconSet = sml.ST_IconSetType3TrafficLights1
+				}
+				res.HideValue = res.HideValue || r.rule.IconSet.ShowValueAttr != nil && !*r.rule.IconSet.ShowValueAttr
+			}
+		default:
+			matched = cf.matches(r, v, col, row)
+		}
+		if !matched {
+			continue
+		}
+		if r.rule.DxfIdAttr != nil {
+			if dxfs := cf.s._bdb.StyleSheet.X().Dxfs; dxfs != nil && int(*r.rule.DxfIdAttr) < len(dxfs.Dxf) {
+				res.Styles = append(res.Styles, DifferentialStyle{dxfs.Dxf[*r.rule.DxfIdAttr], cf.s._bdb, dxfs})
+			}
+		}
+		if r.rule.StopIfTrueAttr != nil && *r.rule.StopIfTrueAttr {
+			break
+		}
+	}
+	switch len(res.Styles) {
+	case 0:
+	case 1:
+		res.Style = res.Styles[0]
+	default:
+		d := sml.NewCT_Dxf()
+		for i := len(res.Styles) - 1; i >= 0; i-- {
+			x := res.Styles[i].X()
+			if x.Font != nil {
+				d.Font = x.Font
+			}
+			if x.NumFmt != nil {
+				d.NumFmt = x.NumFmt
+			}
+			if x.Fill != nil {
+				d.Fill = x.Fill
+			}
+			if x.Alignment != nil {
+				d.Alignment = x.Alignment
+			}
+			if x.Border != nil {
+				d.Border = x.Border
+			}
+		}
+		res.Style = DifferentialStyle{d, cf.s._bdb, sml.NewCT_Dxfs()}
+	}
+	return res
+}
+
+// matches returns true if a rule that applies a style applies to a value.
+func (cf *ConditionalFormatter) matches(r *cfRule, v formula.Result, col, row uint32) bool {
+	rule := r.rule
+	text := strings.ToLower(v.Value())
+	switch rule.TypeAttr {
+	case sml.ST_CfTypeCellIs:
+		return cf.cellIs(r, v, col, row)
+	case sml.ST_CfTypeExpression:
+		return len(rule.Formula) > 0 && truthy(cf.eval(r, rule.Formula[0], col, row))
+	case sml.ST_CfTypeTop10:
+		return cf.top10(r, v)
+	case sml.ST_CfTypeAboveAverage:
+		return cf.aboveAverage(r, v)
+	case sml.ST_CfTypeDuplicateValues, sml.ST_CfTypeUniqueValues:
+		key, ok := valueKey(v)
+		if !ok {
+			return false
+		}
+		cf.scan(r)
+		return (r.counts[key] > 1) == (rule.TypeAttr == sml.ST_CfTypeDuplicateValues)
+	case sml.ST_CfTypeContainsText, sml.ST_CfTypeNotContainsText, sml.ST_CfTypeBeginsWith, sml.ST_CfTypeEndsWith:
+		if v.Type == formula.ResultTypeError || rule.TextAttr == nil {
+			return false
+		}
+		find := strings.ToLower(*rule.TextAttr)
+		switch rule.TypeAttr {
+		case sml.ST_CfTypeContainsText:
+			return strings.Contains(text, find)
+		case sml.ST_CfTypeNotContainsText:
+			return !strings.Contains(text, find)
+		case sml.ST_CfTypeBeginsWith:
+			return strings.HasPrefix(text, find)
+		}
+		return strings.HasSuffix(text, find)
+	case sml.ST_CfTypeContainsBlanks, sml.ST_CfTypeNotContainsBlanks:
+		blank := v.Type == formula.ResultTypeEmpty || v.Type == formula.ResultTypeString && strings.TrimSpace(text) == ""
+		return blank == (rule.TypeAttr == sml.ST_CfTypeContainsBlanks)
+	case sml.ST_CfTypeContainsErrors, sml.ST_CfTypeNotContainsErrors:
+		return (v.Type == formula.ResultTypeError) == (rule.TypeAttr == sml.ST_CfTypeContainsErrors)
+	case sml.ST_CfTypeTimePeriod:
+		return cf.timePeriod(rule.TimePeriodAttr, v)
+	}
+	return false
+}
+
+// cellValue returns the value of a cell, which is the cached result for
+// formulas.
+func cellValue(c Cell) formula.Result {
+	switch {
+	case c._dbd == nil:
+		return formula.MakeEmptyResult()
+	case c._dbd.TAttr == sml.ST_CellTypeStr:
+		if c._dbd.V == nil {
+			return formula.MakeStringResult("")
+		}
+		return formula.MakeStringResult(*c._dbd.V)
+	case c._dbd.F != nil && c._dbd.V == nil:
+		return formula.MakeEmptyResult()
+	}
+	return cellResult(c)
+}
+
+// isNumber returns true for numbers other than booleans.
+func isNumber(v formula.Result) bool {
+	return v.Type == formula.ResultTypeNumber && !v.IsBoolean
+}
+
+// eval evaluates a formula of a rule for the cell at a column and row.
+func (cf *ConditionalFormatter) eval(r *cfRule, f string, col, row uint32) formula.Result {
+	return evalRelative(cf.ev, cf.ctx, f, r.top, col, row)
+}
+
+// evalRelative evaluates a formula that is written for the cell top, such as
+// that of a rule for a range of cells, for the cell at a column and row.
+func evalRelative(ev formula.Evaluator, ctx formula.Context, f string, top reference.CellReference, col, row uint32) formula.Result {
+	f = strings.TrimPrefix(f, "=")
+	f = copiedFormula(f, int(col)-int(top.ColumnIdx), int(row)-int(top.RowIdx))
+	return ev.Eval(ctx, f)
+}
+
+// truthy returns true for the results that make a formula rule apply.
+func truthy(r formula.Result) bool {
+	switch r.Type {
+	case formula.ResultTypeNumber:
+		return r.ValueNumber != 0
+	case formula.ResultTypeString:
+		return strings.EqualFold(r.ValueString, "TRUE")
+	case formula.ResultTypeArray, formula.ResultTypeList:
+		if first := r.ListValues(); len(first) > 0 {
+			return truthy(first[0])
+		}
+	}
+	return false
+}
+
+// cellIs returns true if a value compares to the values of a rule as its
+// operator requires.
+func (cf *ConditionalFormatter) cellIs(r *cfRule, v formula.Result, col, row uint32) bool {
+	if len(r.rule.Formula) == 0 || v.Type == formula.ResultTypeError {
+		return false
+	}
+	operand := func(i int) (int, bool) {
+		if i >= len(r.rule.Formula) {
+			return 0, false
+		}
+		return compareResults(v, cf.eval(r, r.rule.Formula[i], col, row))
+	}
+	c, ok := operand(0)
+	if !ok {
+		return false
+	}
+	switch r.rule.OperatorAttr {
+	case sml.ST_ConditionalFormattingOperatorLessThan:
+		return c < 0
+	case sml.ST_ConditionalFormattingOperatorLessThanOrEqual:
+		return c <= 0
+	case sml.ST_ConditionalFormattingOperatorNotEqual:
+		return c != 0
+	case sml.ST_ConditionalFormattingOperatorGreaterThanOrEqual:
+		return c >= 0
+	case sml.ST_ConditionalFormattingOperatorGreaterThan:
+		return c > 0
+	case sml.ST_ConditionalFormattingOperatorBetween, sml.ST_ConditionalFormattingOperatorNotBetween:
+		d, ok := operand(1)
+		if !ok {
+			return false
+		}
+		// the bounds may be in either order
+		between := c >= 0 && d <= 0 || c <= 0 && d >= 0
+		return between == (r.rule.OperatorAttr == sml.ST_ConditionalFormattingOperatorBetween)
+	}
+	return c == 0
+}
+
+// compareResults compares two values as Excel does, where numbers come
+// before text and text before booleans, text is compared ignoring case and
+// empty values are zero or empty text.  It returns false if either is an
+// error.
+func compareResults(a, b formula.Result) (int, bool) {
+	rank := func(r formula.Result) int {
+		switch {
+		case r.Type == formula.ResultTypeEmpty:
+			return -1
+		case isNumber(r):
+			return 0
+		case r.Type == formula.ResultTypeString:
+			return 1
+		}
+		return 2
+	}
+	if a.Type == formula.ResultTypeError || b.Type == formula.ResultTypeError {
+		return 0, false
+	}
+	if l := b.ListValues(); (b.Type == formula.ResultTypeArray || b.Type == formula.ResultTypeList) && len(l) > 0 {
+		b = l[0]
+	}
+	ra, rb := rank(a), rank(b)
+	switch {
+	case ra == -1 && rb == -1:
+		return 0, true
+	case ra == -1 && rb == 1:
+		a, ra = formula.MakeStringResult(""), 1
+	case ra == -1:
+		a, ra = formula.MakeNumberResult(0), 0
+	case rb == -1 && ra == 1:
+		b, rb = formula.MakeStringResult(""), 1
+	case rb == -1:
+		b, rb = formula.MakeNumberResult(0), 0
+	}
+	if ra != rb {
+		return ra - rb, true
+	}
+	switch ra {
+	case 0:
+		switch {
+		case a.ValueNumber < b.ValueNumber:
+			return -1, true
+		case a.ValueNumber > b.ValueNumber:
+			return 1, true
+		}
+		return 0, true
+	case 1:
+		return strings.Compare(strings.ToLower(a.ValueString), strings.ToLower(b.ValueString)), true
+	}
+	return int(a.ValueNumber - b.ValueNumber), true
+}
+
+// valueKey returns a key for a value that is the same for the values that
+// duplicateValues rules consider equal, or false for empty values and
+// errors.
+func valueKey(v formula.Result) (string, bool) {
+	switch {
+	case v.Type == formula.ResultTypeEmpty || v.Type == formula.ResultTypeError:
+		return "", false
+	case v.IsBoolean:
+		return "b" + v.Value(), true
+	case v.Type == formula.ResultTypeNumber:
+		return "n" + strconv.FormatFloat(v.ValueNumber, 'g', -1, 64), true
+	}
+	return "s" + strings.ToLower(v.Value()), true
+}
+
+// scan collects the numbers and values of the cells formatted by a rule.
+func (cf *ConditionalFormatter) scan(r *cfRule) {
+	if r.scanned {
+		return
+	}
+	r.scanned = true
+	r.counts = map[string]int{}
+	for _, row := range cf.s._bcgb.SheetData.Row {
+		for _, c := range row.C {
+			if c.RAttr == nil {
+				continue
+			}
+			ref, err := reference.ParseCellReference(*c.RAttr)
+			if err != nil {
+				continue
+			}
+			for _, a := range r.areas {
+				if !a.contains(cellKey{a.ws, ref.ColumnIdx, ref.RowIdx}) {
+					continue
+				}
+				v := cellValue(Cell{cf.s._bdb, cf.s, row, c})
+				if isNumber(v) {
+					r.numbers = append(r.numbers, v.ValueNumber)
+				}
+				if key, ok := valueKey(v); ok {
+					r.counts[key]++
+				}
+				break
+			}
+		}
+	}
+	sort.Float64s(r.numbers)
+}
+
+// top10 returns true if a number is among the highest or lowest numbers of
+// the ranges of a rule, by count or by percentage.
+func (cf *ConditionalFormatter) top10(r *cfRule, v formula.Result) bool {
+	cf.scan(r)
+	if !isNumber(v) || len(r.numbers) == 0 {
+		return false
+	}
+	n := 10
+	if r.rule.RankAttr != nil {
+		n = int(*r.rule.RankAttr)
+	}
+	if r.rule.PercentAttr != nil && *r.rule.PercentAttr {
+		n = len(r.numbers) * n / 100
+		if n < 1 {
+			n = 1
+		}
+	}
+	if n < 1 {
+		return false
+	}
+	if n > len(r.numbers) {
+		n = len(r.numbers)
+	}
+	if r.rule.BottomAttr != nil && *r.rule.BottomAttr {
+		return v.ValueNumber <= r.numbers[n-1]
+	}
+	return v.ValueNumber >= r.numbers[len(r.numbers)-n]
+}
+
+// aboveAverage returns true if a number is above or below the average of
+// the ranges of a rule, or a number of standard deviations from it.
+func (cf *ConditionalFormatter) aboveAverage(r *cfRule, v formula.Result) bool {
+	cf.scan(r)
+	if !isNumber(v) || len(r.numbers) == 0 {
+		return false
+	}
+	sum := 0.0
+	for _, n := range r.numbers {
+		sum += n
+	}
+	avg := sum / float64(len(r.numbers))
+	limit := avg
+	above := r.rule.AboveAverageAttr == nil || *r.rule.AboveAverageAttr
+	if r.rule.StdDevAttr != nil && *r.rule.StdDevAttr > 0 {
+		if len(r.numbers) < 2 {
+			return false
+		}
+		ss := 0.0
+		for _, n := range r.numbers {
+			ss += (n - avg) * (n - avg)
+		}
+		sd := math.Sqrt(ss/float64(len(r.numbers)-1)) * float64(*r.rule.StdDevAttr)
+		if above {
+			limit += sd
+		} else {
+			limit -= sd
+		}
+	}
+	equal := r.rule.EqualAverageAttr != nil && *r.rule.EqualAverageAttr
+	switch {
+	case equal && v.ValueNumber == limit:
+		return true
+	case above:
+		return v.ValueNumber > limit
+	}
+	return v.ValueNumber < limit
+}
+
+// timePeriods are the dynamic filter periods that are the same as time
+// periods of rules.
+var timePeriods = map[sml.ST_TimePeriod]sml.ST_DynamicFilterType{
+	sml.ST_TimePeriodToday:     sml.ST_DynamicFilterTypeToday,
+	sml.ST_TimePeriodYesterday: sml.ST_DynamicFilterTypeYesterday,
+	sml.ST_TimePeriodTomorrow:  sml.ST_DynamicFilterTypeTomorrow,
+	sml.ST_TimePeriodThisWeek:  sml.ST_DynamicFilterTypeThisWeek,
+	sml.ST_TimePeriodLastWeek:  sml.ST_DynamicFilterTypeLastWeek,
+	sml.ST_TimePeriodNextWeek:  sml.ST_DynamicFilterTypeNextWeek,
+	sml.ST_TimePeriodThisMonth: sml.ST_DynamicFilterTypeThisMonth,
+	sml.ST_TimePeriodLastMonth: sml.ST_DynamicFilterTypeLastMonth,
+	sml.ST_TimePeriodNextMonth: sml.ST_DynamicFilterTypeNextMonth,
+}
+
+// timePeriod returns true if a date is within a period relative to today.
+func (cf *ConditionalFormatter) timePeriod(p sml.ST_TimePeriod, v formula.Result) bool {
+	if !isNumber(v) {
+		return false
+	}
+	day := int(math.Floor(v.ValueNumber))
+	if p == sml.ST_TimePeriodLast7Days {
+		return day > cf.today-7 && day <= cf.today
+	}
+	t, ok := timePeriods[p]
+	if !ok {
+		return false
+	}
+	epoch := cf.s._bdb.Epoch()
+	return inPeriod(t, epoch.AddDate(0, 0, day), epoch.AddDate(0, 0, cf.today))
+}
+
+// threshold returns the number of a value of a color scale, data bar or icon
+// set.
+func (cf *ConditionalFormatter) threshold(r *cfRule, v *sml.CT_Cfvo) (float64, bool) {
+	cf.scan(r)
+	nums := r.numbers
+	if len(nums) == 0 {
+		return 0, false
+	}
+	min, max := nums[0], nums[len(nums)-1]
+	val := func() (float64, bool) {
+		if v.ValAttr == nil {
+			return 0, false
+		}
+		if n, err := strconv.ParseFloat(*v.ValAttr, 64); err == nil {
+			return n, true
+		}
+		res := cf.eval(r, *v.ValAttr, r.top.ColumnIdx, r.top.RowIdx)
+		if !isNumber(res) {
+			return 0, false
+		}
+		return res.ValueNumber, true
+	}
+	switch v.TypeAttr {
+	case sml.ST_CfvoTypeMin:
+		return min, true
+	case sml.ST_CfvoTypeMax:
+		return max, true
+	case sml.ST_CfvoTypePercent:
+		p, ok := val()
+		return min + (max-min)*p/100, ok
+	case sml.ST_CfvoTypePercentile:
+		p, ok := val()
+		return percentile(nums, p/100), ok
+	}
+	return val()
+}
+
+// percentile returns a percentile of sorted numbers as PERCENTILE.INC does.
+func percentile(nums []float64, p float64) float64 {
+	p = math.Max(0, math.Min(1, p))
+	x := p * float64(len(nums)-1)
+	i := int(x)
+	if i+1 >= len(nums) {
+		return nums[len(nums)-1]
+	}
+	return nums[i] + (x-float64(i))*(nums[i+1]-nums[i])
+}
+
+// colorScale returns the color of a number in a color scale, or false if the
+// value isn't a number.
+func (cf *ConditionalFormatter) colorScale(r *cfRule, v formula.Result) (uint32, bool) {
+	cs := r.rule.ColorScale
+	if cs == nil || !isNumber(v) || len(cs.Cfvo) < 2 || len(cs.Color) < len(cs.Cfvo) {
+		return 0, false
+	}
+	points := make([]float64, len(cs.Cfvo))
+	colors := make([]uint32, len(cs.Cfvo))
+	for i, vo := range cs.Cfvo {
+		var ok bool
+		if points[i], ok = cf.threshold(r, vo); !ok {
+			return 0, false
+		}
+		if colors[i], ok = cf.s._bdb.rgb(cs.Color[i]); !ok {
+			return 0, false
+		}
+	}
+	x := v.ValueNumber
+	if x <= points[0] {
+		return colors[0], true
+	}
+	for i := 1; i < len(points); i++ {
+		if x < points[i] {
+			if points[i] == points[i-1] {
+				return colors[i], true
+			}
+			return mixRGB(colors[i-1], colors[i], (x-points[i-1])/(points[i]-points[i-1])), true
+		}
+	}
+	return colors[len(colors)-1], true
+}
+
+// dataBar returns the length of the data bar of a number as a fraction of
+// the width of the cell, or false if the value isn't a number.
+func (cf *ConditionalFormatter) dataBar(r *cfRule, v formula.Result) (float64, bool) {
+	db := r.rule.DataBar
+	if db == nil || !isNumber(v) || len(db.Cfvo) < 2 {
+		return 0, false
+	}
+	lo, ok := cf.threshold(r, db.Cfvo[0])
+	if !ok {
+		return 0, false
+	}
+	hi, ok := cf.threshold(r, db.Cfvo[1])
+	if !ok {
+		return 0, false
+	}
+	minLength, maxLength := 10.0, 90.0
+	if db.MinLengthAttr != nil {
+		minLength = float64(*db.MinLengthAttr)
+	}
+	if db.MaxLengthAttr != nil {
+		maxLength = float64(*db.MaxLengthAttr)
+	}
+	f := 1.0
+	if hi > lo {
+		f = math.Max(0, math.Min(1, (v.ValueNumber-lo)/(hi-lo)))
+	}
+	return (minLength + f*(maxLength-minLength)) / 100, true
+}
+
+// icon returns the position of the icon of a number in an icon set, or
+// false if the value isn't a number.
+func (cf *ConditionalFormatter) icon(r *cfRule, v formula.Result) (int, bool) {
+	is := r.rule.IconSet
+	if is == nil || !isNumber(v) || len(is.Cfvo) == 0 {
+		return 0, false
+	}
+	icon := 0
+	for i, vo := range is.Cfvo {
+		t, ok := cf.threshold(r, vo)
+		if !ok {
+			return 0, false
+		}
+		if i > 0 && (v.ValueNumber > t || v.ValueNumber == t && (vo.GteAttr == nil || *vo.GteAttr)) {
+			icon = i
+		}
+	}
+	if is.ReverseAttr != nil && *is.ReverseAttr {
+		icon = len(is.Cfvo) - 1 - icon
+	}
+	return icon, true
+}
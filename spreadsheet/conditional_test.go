@@ -0,0 +1,297 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/unidoc/unioffice"
+	"github.com/unidoc/unioffice/color"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+)
+
+// cfSheet returns a sheet with values in A1 and below, which are numbers,
+// strings or booleans.
+func cfSheet(values ...interface{}) (*Workbook, Sheet) {
+	wb := New()
+	s := wb.AddSheet()
+	for i, v := range values {
+		c := s.Cell(fmt.Sprintf("A%d", i+1))
+		switch v := v.(type) {
+		case float64:
+			c.SetNumber(v)
+		case int:
+			c.SetNumber(float64(v))
+		case bool:
+			c.SetBool(v)
+		case string:
+			c.SetString(v)
+		}
+	}
+	return wb, s
+}
+
+// addCFRule adds a rule of a type with a style to a range.
+func addCFRule(wb *Workbook, s Sheet, ref string, t sml.ST_CfType) ConditionalFormattingRule {
+	r := s.AddConditionalFormatting([]string{ref}).AddRule()
+	r.SetType(t)
+	r.SetStyle(wb.StyleSheet.AddDifferentialStyle())
+	return r
+}
+
+// styledCells returns the cells of A1 to A<n> that rules give a style.
+func styledCells(s Sheet, n int) string {
+	cf := s.ConditionalFormatter()
+	got := []string{}
+	for i := 1; i <= n; i++ {
+		ref := fmt.Sprintf("A%d", i)
+		if len(cf.Format(ref).Styles) > 0 {
+			got = append(got, ref)
+		}
+	}
+	return strings.Join(got, " ")
+}
+
+func TestConditionalFormatRules(t *testing.T) {
+	numbers := []interface{}{5, 10, 15, 20, 25, 30}
+	texts := []interface{}{"apple", "Apple", "pear", 1, 1, true}
+	for _, tc := range []struct {
+		name   string
+		values []interface{}
+		t      sml.ST_CfType
+		set    func(x *sml.CT_CfRule)
+		exp    string
+	}{
+		{"greater than", numbers, sml.ST_CfTypeCellIs, func(x *sml.CT_CfRule) {
+			x.OperatorAttr = sml.ST_ConditionalFormattingOperatorGreaterThan
+			x.Formula = []string{"15"}
+		}, "A4 A5 A6"},
+		{"between", numbers, sml.ST_CfTypeCellIs, func(x *sml.CT_CfRule) {
+			x.OperatorAttr = sml.ST_ConditionalFormattingOperatorBetween
+			x.Formula = []string{"10", "20"}
+		}, "A2 A3 A4"},
+		{"not between reversed", numbers, sml.ST_CfTypeCellIs, func(x *sml.CT_CfRule) {
+			x.OperatorAttr = sml.ST_ConditionalFormattingOperatorNotBetween
+			x.Formula = []string{"20", "10"}
+		}, "A1 A5 A6"},
+		{"equal to a formula", numbers, sml.ST_CfTypeCellIs, func(x *sml.CT_CfRule) {
+			x.OperatorAttr = sml.ST_ConditionalFormattingOperatorEqual
+			x.Formula = []string{"$A$1*4"}
+		}, "A4"},
+		{"equal text", texts, sml.ST_CfTypeCellIs, func(x *sml.CT_CfRule) {
+			x.OperatorAttr = sml.ST_ConditionalFormattingOperatorEqual
+			x.Formula = []string{`"APPLE"`}
+		}, "A1 A2"},
+		// text is greater than numbers
+		{"text greater than a number", texts, sml.ST_CfTypeCellIs, func(x *sml.CT_CfRule) {
+			x.OperatorAttr = sml.ST_ConditionalFormattingOperatorGreaterThan
+			x.Formula = []string{"100"}
+		}, "A1 A2 A3 A6"},
+
+		// formulas are relative to the first cell of the range
+		{"relative expression", numbers, sml.ST_CfTypeExpression, func(x *sml.CT_CfRule) {
+			x.Formula = []string{"MOD(A1,10)=0"}
+		}, "A2 A4 A6"},
+		{"absolute expression", numbers, sml.ST_CfTypeExpression, func(x *sml.CT_CfRule) {
+			x.Formula = []string{"A1>$A$3"}
+		}, "A4 A5 A6"},
+		{"expression to the previous row", numbers, sml.ST_CfTypeExpression, func(x *sml.CT_CfRule) {
+			x.Formula = []string{"A2-A1=5"}
+		}, "A1 A2 A3 A4 A5"},
+
+		{"top", numbers, sml.ST_CfTypeTop10, func(x *sml.CT_CfRule) {
+			x.RankAttr = unioffice.Uint32(2)
+		}, "A5 A6"},
+		{"bottom", numbers, sml.ST_CfTypeTop10, func(x *sml.CT_CfRule) {
+			x.RankAttr = unioffice.Uint32(2)
+			x.BottomAttr = unioffice.Bool(true)
+		}, "A1 A2"},
+		{"top percent", numbers, sml.ST_CfTypeTop10, func(x *sml.CT_CfRule) {
+			x.RankAttr = unioffice.Uint32(50)
+			x.PercentAttr = unioffice.Bool(true)
+		}, "A4 A5 A6"},
+		{"top of text", texts, sml.ST_CfTypeTop10, func(x *sml.CT_CfRule) {
+			x.RankAttr = unioffice.Uint32(1)
+		}, "A4 A5"},
+
+		{"above average", numbers, sml.ST_CfTypeAboveAverage, func(x *sml.CT_CfRule) {}, "A4 A5 A6"},
+		{"below average", numbers, sml.ST_CfTypeAboveAverage, func(x *sml.CT_CfRule) {
+			x.AboveAverageAttr = unioffice.Bool(false)
+		}, "A1 A2 A3"},
+		{"standard deviation above", numbers, sml.ST_CfTypeAboveAverage, func(x *sml.CT_CfRule) {
+			x.StdDevAttr = unioffice.Int32(1)
+		}, "A6"},
+		{"equal or below average", []interface{}{1, 2, 3}, sml.ST_CfTypeAboveAverage, func(x *sml.CT_CfRule) {
+			x.AboveAverageAttr = unioffice.Bool(false)
+			x.EqualAverageAttr = unioffice.Bool(true)
+		}, "A1 A2"},
+
+		// duplicates ignore case, and booleans aren't numbers
+		{"duplicates", texts, sml.ST_CfTypeDuplicateValues, func(x *sml.CT_CfRule) {}, "A1 A2 A4 A5"},
+		{"unique", texts, sml.ST_CfTypeUniqueValues, func(x *sml.CT_CfRule) {}, "A3 A6"},
+
+		{"contains", texts, sml.ST_CfTypeContainsText, func(x *sml.CT_CfRule) {
+			x.TextAttr = unioffice.String("PP")
+		}, "A1 A2"},
+		{"doesn't contain", texts, sml.ST_CfTypeNotContainsText, func(x *sml.CT_CfRule) {
+			x.TextAttr = unioffice.String("pp")
+		}, "A3 A4 A5 A6"},
+		{"begins with", texts, sml.ST_CfTypeBeginsWith, func(x *sml.CT_CfRule) {
+			x.TextAttr = unioffice.String("pe")
+		}, "A3"},
+		{"ends with", texts, sml.ST_CfTypeEndsWith, func(x *sml.CT_CfRule) {
+			x.TextAttr = unioffice.String("LE")
+		}, "A1 A2"},
+		{"blanks", []interface{}{"x", " ", nil, 0}, sml.ST_CfTypeContainsBlanks, func(x *sml.CT_CfRule) {}, "A2 A3"},
+		{"not blanks", []interface{}{"x", " ", nil, 0}, sml.ST_CfTypeNotContainsBlanks, func(x *sml.CT_CfRule) {}, "A1 A4"},
+	} {
+		wb, s := cfSheet(tc.values...)
+		r := addCFRule(wb, s, fmt.Sprintf("A1:A%d", len(tc.values)), tc.t)
+		tc.set(r.X())
+		if got := styledCells(s, len(tc.values)); got != tc.exp {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.exp, got)
+		}
+	}
+}
+
+func TestConditionalFormatTimePeriod(t *testing.T) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	wb, s := cfSheet()
+	for i, d := range []int{0, -1, 1, -6, -7, -40} {
+		s.Cell(fmt.Sprintf("A%d", i+1)).SetDate(today.AddDate(0, 0, d))
+	}
+	for p, exp := range map[sml.ST_TimePeriod]string{
+		sml.ST_TimePeriodToday:     "A1",
+		sml.ST_TimePeriodYesterday: "A2",
+		sml.ST_TimePeriodTomorrow:  "A3",
+		sml.ST_TimePeriodLast7Days: "A1 A2 A4",
+	} {
+		s.X().ConditionalFormatting = nil
+		r := addCFRule(wb, s, "A1:A6", sml.ST_CfTypeTimePeriod)
+		r.X().TimePeriodAttr = p
+		if got := styledCells(s, 6); got != exp {
+			t.Errorf("%s: expected %q, got %q", p, exp, got)
+		}
+	}
+}
+
+func TestConditionalFormatPriority(t *testing.T) {
+	wb, s := cfSheet(5, 15, 25)
+	cf := s.AddConditionalFormatting([]string{"A1:A3"})
+	// the rule added first has the lower priority
+	bold := cf.AddRule()
+	bold.SetType(sml.ST_CfTypeCellIs)
+	bold.SetOperator(sml.ST_ConditionalFormattingOperatorGreaterThan)
+	bold.SetConditionValue("20")
+	bold.SetPriority(2)
+	d := wb.StyleSheet.AddDifferentialStyle()
+	d.X().Font = sml.NewCT_Font()
+	d.X().Font.B = []*sml.CT_BooleanProperty{sml.NewCT_BooleanProperty()}
+	d.Fill().SetPatternFill().SetBgColor(color.Blue)
+	bold.SetStyle(d)
+	red := cf.AddRule()
+	red.SetType(sml.ST_CfTypeCellIs)
+	red.SetOperator(sml.ST_ConditionalFormattingOperatorGreaterThan)
+	red.SetConditionValue("10")
+	red.SetPriority(1)
+	redFill := wb.StyleSheet.AddDifferentialStyle()
+	redFill.Fill().SetPatternFill().SetBgColor(color.Red)
+	red.SetStyle(redFill)
+
+	res := s.ConditionalFormat("A3")
+	if len(res.Styles) != 2 || res.Styles[0].X() != redFill.X() || res.Styles[1].X() != d.X() {
+		t.Fatalf("expected the styles of both rules, the red fill first, got %d", len(res.Styles))
+	}
+	// the combined style takes each part from the rule with the highest
+	// priority that sets it
+	if x := res.Style.X(); x.Font != d.X().Font || x.Fill != redFill.X().Fill {
+		t.Errorf("expected a bold font and a red fill, got %+v", x)
+	}
+	if res := s.ConditionalFormat("A2"); len(res.Styles) != 1 || res.Style.X() != res.Styles[0].X() {
+		t.Errorf("expected the style of the red rule in A2, got %d", len(res.Styles))
+	}
+	if res := s.ConditionalFormat("A1"); len(res.Styles) != 0 || res.Style.X() != nil {
+		t.Errorf("expected no style in A1, got %d", len(res.Styles))
+	}
+
+	red.X().StopIfTrueAttr = unioffice.Bool(true)
+	if res := s.ConditionalFormat("A3"); len(res.Styles) != 1 || res.Style.X().Font != nil {
+		t.Errorf("expected no more rules to apply after one that stops, got %d", len(res.Styles))
+	}
+	// stopping only applies if the rule matches
+	red.SetConditionValue("30")
+	if res := s.ConditionalFormat("A3"); len(res.Styles) != 1 || res.Style.X().Font == nil {
+		t.Errorf("expected the bold rule to apply, got %d", len(res.Styles))
+	}
+}
+
+func TestConditionalFormatScales(t *testing.T) {
+	_, s := cfSheet(5, 10, 15, 20, 25, 30, "x")
+	cs := s.AddConditionalFormatting([]string{"A1:A7"}).AddRule().SetColorScale()
+	cs.AddFormatValue(sml.ST_CfvoTypeMin, "0")
+	cs.AddGradientStop(color.White)
+	cs.AddFormatValue(sml.ST_CfvoTypePercentile, "50")
+	cs.AddGradientStop(color.Yellow)
+	cs.AddFormatValue(sml.ST_CfvoTypeMax, "0")
+	cs.AddGradientStop(color.Red)
+
+	db := s.AddConditionalFormatting([]string{"A1:A7"}).AddRule().SetDataBar()
+	db.AddFormatValue(sml.ST_CfvoTypeMin, "0")
+	db.AddFormatValue(sml.ST_CfvoTypeMax, "0")
+	db.SetColor(color.Blue)
+
+	is := s.AddConditionalFormatting([]string{"A1:A7"}).AddRule().SetIcons()
+	is.SetIcons(sml.ST_IconSetType3Arrows)
+	is.AddFormatValue(sml.ST_CfvoTypePercent, "0")
+	is.AddFormatValue(sml.ST_CfvoTypePercent, "33")
+	is.AddFormatValue(sml.ST_CfvoTypePercent, "67")
+
+	cf := s.ConditionalFormatter()
+	for _, tc := range []struct {
+		ref   string
+		color color.Color
+		bar   float64
+		icon  int
+	}{
+		// the median is 17.5
+		{"A1", color.White, 0.1, 0},
+		{"A2", color.RGB(0xFF, 0xFF, 0x99), 0.26, 0},
+		{"A3", color.RGB(0xFF, 0xFF, 0x33), 0.42, 1},
+		{"A4", color.RGB(0xFF, 0xCC, 0x00), 0.58, 1},
+		{"A5", color.RGB(0xFF, 0x66, 0x00), 0.74, 2},
+		{"A6", color.Red, 0.9, 2},
+	} {
+		res := cf.Format(tc.ref)
+		if res.Color != tc.color {
+			t.Errorf("expected the color %v in %s, got %v", tc.color, tc.ref, res.Color)
+		}
+		if d := res.DataBar - tc.bar; d > 1e-9 || d < -1e-9 || res.DataBarColor != color.Blue {
+			t.Errorf("expected a blue data bar of %v in %s, got %v %v", tc.bar, tc.ref, res.DataBar, res.DataBarColor)
+		}
+		if res.IconSet != sml.ST_IconSetType3Arrows || res.Icon != tc.icon {
+			t.Errorf("expected the icon %d in %s, got %s %d", tc.icon, tc.ref, res.IconSet, res.Icon)
+		}
+		if res.HideValue {
+			t.Errorf("expected the value to be shown in %s", tc.ref)
+		}
+	}
+	// text has no color, bar or icon
+	if res := cf.Format("A7"); !res.Color.IsAuto() || !res.DataBarColor.IsAuto() || res.IconSet != sml.ST_IconSetTypeUnset {
+		t.Errorf("expected no formatting for text, got %+v", res)
+	}
+
+	is.X().ReverseAttr = unioffice.Bool(true)
+	is.X().ShowValueAttr = unioffice.Bool(false)
+	if res := s.ConditionalFormat("A1"); res.Icon != 2 || !res.HideValue {
+		t.Errorf("expected the reversed icon without the value, got %d %v", res.Icon, res.HideValue)
+	}
+}
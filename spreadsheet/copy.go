@@ -0,0 +1,597 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/unidoc/unioffice"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+	"github.com/unidoc/unioffice/spreadsheet/update"
+	"github.com/unidoc/unioffice/vmldrawing"
+)
+
+// CopyRange copies the cells of the range src (e.g. "A1:C3") to dst, which is
+// either the first cell of the destination (e.g. "E1") or a range whose size
+// is a multiple of the size of src that is filled by repeating src.  dst may
+// be on another sheet of the workbook (e.g. "Sheet2!E1").  Values, styles,
+// merged cells and comments are copied and the relative references of
+// formulas move along with them while absolute references (e.g. $A$1) are
+// left unchanged, the same as pasting in Excel.  The cells of the destination
+// are replaced, including by empty cells.  Copied formulas have no cached
+// values until they're recalculated, except that arrays spill again from the
+// copied formulas and from the formulas whose spill ranges the destination
+// overlaps.
+func (s *Sheet) CopyRange(src, dst string) error {
+	from, to, err := parseArea(src)
+	if err != nil {
+		return err
+	}
+	ds, dfrom, dto, err := s.destination(dst)
+	if err != nil {
+		return err
+	}
+	if dfrom == dto {
+		dto.ColumnIdx += to.ColumnIdx - from.ColumnIdx
+		dto.RowIdx += to.RowIdx - from.RowIdx
+	}
+	return s.copyRange(from, to, ds, dfrom, dto)
+}
+
+// MoveRange moves the cells of the range src (e.g. "A1:C3") so that its first
+// cell ends up at dst (e.g. "E1"), the same as cutting and pasting them in
+// Excel.  Values, styles, merged cells, comments, hyperlinks, conditional
+// formatting, data validations and tables within the range move along with
+// it.  References to the moved cells throughout the workbook follow them,
+// references to the cells that are moved over become #REF! and the formulas
+// of the moved cells keep referring to the same cells.  Cells can't be moved
+// to another sheet.
+func (s *Sheet) MoveRange(src, dst string) error {
+	from, to, err := parseArea(src)
+	if err != nil {
+		return err
+	}
+	ds, at, dto, err := s.destination(dst)
+	if err != nil {
+		return err
+	}
+	if ds._bcgb != s._bcgb {
+		return errors.New("can't move cells to another sheet")
+	}
+	cols, rows := to.ColumnIdx-from.ColumnIdx+1, to.RowIdx-from.RowIdx+1
+	if at != dto && (dto.ColumnIdx-at.ColumnIdx+1 != cols || dto.RowIdx-at.RowIdx+1 != rows) {
+		return fmt.Errorf("destination %s isn't the size of %s", dst, src)
+	}
+	if at.ColumnIdx+cols-1 > maxColumnIdx || at.RowIdx+rows-1 > maxRowIdx {
+		return fmt.Errorf("destination %s is outside of the sheet", dst)
+	}
+	if at.ColumnIdx == from.ColumnIdx && at.RowIdx == from.RowIdx {
+		return nil
+	}
+	sa := area{ws: s._bcgb, col1: from.ColumnIdx, row1: from.RowIdx, col2: to.ColumnIdx, row2: to.RowIdx}
+	da := sa.offset(int(at.ColumnIdx)-int(from.ColumnIdx), int(at.RowIdx)-int(from.RowIdx))
+	for _, a := range []area{sa, da} {
+		if err := s.checkMergedCells(a); err != nil {
+			return err
+		}
+	}
+	for _, t := range s.Tables() {
+		tf, tt, err := parseTableRange(t.Reference())
+		if err != nil {
+			continue
+		}
+		ta := area{ws: s._bcgb, col1: tf.ColumnIdx, row1: tf.RowIdx, col2: tt.ColumnIdx, row2: tt.RowIdx}
+		if (ta.overlaps(sa) || ta.overlaps(da)) && !ta.within(sa) {
+			return fmt.Errorf("can't move part of table %s", t.Name())
+		}
+	}
+	q := &update.UpdateQuery{
+		UpdateType:   update.UpdateActionMove,
+		ColumnIdx:    from.ColumnIdx,
+		RowIdx:       from.RowIdx,
+		Columns:      cols,
+		Rows:         rows,
+		ColumnOffset: int(at.ColumnIdx) - int(from.ColumnIdx),
+		RowOffset:    int(at.RowIdx) - int(from.RowIdx),
+	}
+	return s.updateReferences(q)
+}
+
+// FillDown copies the first row of the range ref (e.g. "A1:C10") to the other
+// rows of the range as CopyRange does, the same as filling down in Excel.
+func (s *Sheet) FillDown(ref string) error {
+	from, to, err := parseArea(ref)
+	if err != nil {
+		return err
+	}
+	if from.RowIdx == to.RowIdx {
+		return nil
+	}
+	last, start := to, from
+	last.RowIdx = from.RowIdx
+	start.RowIdx++
+	return s.copyRange(from, last, s, start, to)
+}
+
+// FillRight copies the first column of the range ref (e.g. "A1:E3") to the
+// other columns of the range as CopyRange does, the same as filling right in
+// Excel.
+func (s *Sheet) FillRight(ref string) error {
+	from, to, err := parseArea(ref)
+	if err != nil {
+		return err
+	}
+	if from.ColumnIdx == to.ColumnIdx {
+		return nil
+	}
+	last, start := to, from
+	last.ColumnIdx = from.ColumnIdx
+	start.ColumnIdx++
+	return s.copyRange(from, last, s, start, to)
+}
+
+// parseArea parses a cell or a range of cells, returning its first and last
+// cells.
+func parseArea(ref string) (reference.CellReference, reference.CellReference, error) {
+	if strings.Contains(ref, ":") {
+		return parseTableRange(ref)
+	}
+	c, err := reference.ParseCellReference(ref)
+	return c, c, err
+}
+
+// destination returns the sheet and the first and last cells of dst, which
+// may be prefixed by the name of another sheet of the workbook.
+func (s *Sheet) destination(dst string) (*Sheet, reference.CellReference, reference.CellReference, error) {
+	ds := s
+	if i := strings.LastIndex(dst, "!"); i >= 0 {
+		name := dst[:i]
+		if len(name) > 1 && strings.HasPrefix(name, "'") && strings.HasSuffix(name, "'") {
+			name = strings.Replace(name[1:len(name)-1], "''", "'", -1)
+		}
+		ws, err := s._bdb.GetSheet(name)
+		if err != nil {
+			return nil, reference.CellReference{}, reference.CellReference{}, fmt.Errorf("sheet %s: %s", name, err)
+		}
+		ds = &ws
+		dst = dst[i+1:]
+	}
+	from, to, err := parseArea(dst)
+	return ds, from, to, err
+}
+
+// copyRange copies the cells from:to to the range dfrom:dto of the sheet ds,
+// repeating them to fill it.
+func (s *Sheet) copyRange(from, to reference.CellReference, ds *Sheet, dfrom, dto reference.CellReference) error {
+	sa := area{ws: s._bcgb, col1: from.ColumnIdx, row1: from.RowIdx, col2: to.ColumnIdx, row2: to.RowIdx}
+	da := area{ws: ds._bcgb, col1: dfrom.ColumnIdx, row1: dfrom.RowIdx, col2: dto.ColumnIdx, row2: dto.RowIdx}
+	cols, rows := sa.col2-sa.col1+1, sa.row2-sa.row1+1
+	if (da.col2-da.col1+1)%cols != 0 || (da.row2-da.row1+1)%rows != 0 {
+		return fmt.Errorf("destination %s isn't a multiple of the size of %s", rangeString(dfrom, dto), rangeString(from, to))
+	}
+	if da.col2 > maxColumnIdx || da.row2 > maxRowIdx {
+		return fmt.Errorf("destination %s is outside of the sheet", rangeString(dfrom, dto))
+	}
+	if err := s.checkArrays(sa); err != nil {
+		return err
+	}
+	if err := ds.checkArrays(da); err != nil {
+		return err
+	}
+	if err := ds.checkMergedCells(da); err != nil {
+		return err
+	}
+	// everything is read before the destination is cleared as the ranges
+	// may overlap
+	cells := s.cellsWithin(sa)
+	merges := s.mergedCellsWithin(sa)
+	comments, authors := s.commentsWithin(sa)
+	// arrays copied along with their formulas spill again rather than being
+	// copied as values
+	copiedAnchors, copiedSpills := s.spillsWithin(sa)
+
+	touched := ds.spillsOver(da)
+	for _, x := range touched {
+		ds.clearSpill(x)
+	}
+	ds.expandSharedFormulas(da)
+	ds.clearArea(da)
+	var dc Comments
+	if len(comments) > 0 {
+		dc = ds.Comments()
+	}
+	vml := ds.commentDrawing()
+	placed := []*sml.CT_Cell{}
+	for r := da.row1; r <= da.row2; r += rows {
+		for c := da.col1; c <= da.col2; c += cols {
+			q := &update.UpdateQuery{
+				UpdateType:   update.UpdateActionCopy,
+				ColumnOffset: int(c) - int(sa.col1),
+				RowOffset:    int(r) - int(sa.row1),
+			}
+			for _, x := range cells {
+				if copiedSpills[*x.RAttr] {
+					continue
+				}
+				y := copyCell(x, q)
+				if copiedAnchors[*x.RAttr] {
+					touched = append(touched, y)
+				}
+				placed = append(placed, y)
+			}
+			for _, m := range merges {
+				if ref, ok := moveRef(m, q); ok {
+					mf, mt, _ := parseTableRange(ref)
+					ds.AddMergedCells(mf.String(), mt.String())
+				}
+			}
+			for i, cm := range comments {
+				ref, ok := moveRef(cm.RefAttr, q)
+				if !ok {
+					continue
+				}
+				y := deepCopy(reflect.ValueOf(cm)).Interface().(*sml.CT_Comment)
+				y.RefAttr = ref
+				y.AuthorIdAttr = dc.getOrCreateAuthor(authors[i])
+				dc._fcd.CommentList.Comment = append(dc._fcd.CommentList.Comment, y)
+				if cr, err := reference.ParseCellReference(ref); err == nil && vml != nil {
+					vml.Shape = append(vml.Shape, vmldrawing.NewCommentShape(int64(cr.ColumnIdx), int64(cr.RowIdx-1)))
+				}
+			}
+		}
+	}
+	ds.placeCells(placed)
+	ds.respill(touched)
+	return nil
+}
+
+// copyCell returns a copy of a cell moved by the offset of q, whose formula
+// is moved the same.  Formulas lose their cached values.
+func copyCell(c *sml.CT_Cell, q *update.UpdateQuery) *sml.CT_Cell {
+	y := deepCopy(reflect.ValueOf(c)).Interface().(*sml.CT_Cell)
+	if ref, ok := moveRef(*c.RAttr, q); ok {
+		y.RAttr = unioffice.String(ref)
+	}
+	if y.F == nil {
+		return y
+	}
+	y.F.Content = formula.UpdateFormula(y.F.Content, q)
+	if y.F.RefAttr != nil {
+		if ref, ok := moveRef(*y.F.RefAttr, q); ok {
+			y.F.RefAttr = unioffice.String(ref)
+		}
+	}
+	y.V = nil
+	return y
+}
+
+// moveRef moves a reference or a range by the offset of q, returning false if
+// it ends up outside of the sheet.
+func moveRef(ref string, q *update.UpdateQuery) (string, bool) {
+	moved := formula.UpdateFormula(ref, q)
+	return moved, !strings.Contains(moved, "#REF!")
+}
+
+// cellsWithin returns copies of the cells of the sheet within a, with shared
+// formulas replaced by the formulas of each cell.
+func (s *Sheet) cellsWithin(a area) []*sml.CT_Cell {
+	masters := s.sharedFormulas()
+	cells := []*sml.CT_Cell{}
+	for _, r := range s._bcgb.SheetData.Row {
+		if r.RAttr == nil || *r.RAttr < a.row1 || *r.RAttr > a.row2 {
+			continue
+		}
+		for _, c := range r.C {
+			if c.RAttr == nil {
+				continue
+			}
+			ref, err := reference.ParseCellReference(*c.RAttr)
+			if err != nil || !a.contains(cellKey{a.ws, ref.ColumnIdx, ref.RowIdx}) {
+				continue
+			}
+			y := deepCopy(reflect.ValueOf(c)).Interface().(*sml.CT_Cell)
+			if f := y.F; f != nil && f.TAttr == sml.ST_CellFormulaTypeShared {
+				y.F = sml.NewCT_CellFormula()
+				y.F.Content = sharedFormula(c, masters)
+			}
+			cells = append(cells, y)
+		}
+	}
+	return cells
+}
+
+// mergedCellsWithin returns the ranges of the merged cells of the sheet
+// within a.
+func (s *Sheet) mergedCellsWithin(a area) []string {
+	refs := []string{}
+	for _, m := range s.MergedCells() {
+		if ma, ok := s.areaOf(m.Reference()); ok && ma.within(a) {
+			refs = append(refs, m.Reference())
+		}
+	}
+	return refs
+}
+
+// commentsWithin returns copies of the comments of the sheet within a along
+// with the names of their authors.
+func (s *Sheet) commentsWithin(a area) ([]*sml.CT_Comment, []string) {
+	idx := s.index()
+	wb := s._bdb
+	if idx < 0 || idx >= len(wb._cbge) || wb._cbge[idx] == nil || wb._cbge[idx].CommentList == nil {
+		return nil, nil
+	}
+	cm := wb._cbge[idx]
+	comments, authors := []*sml.CT_Comment{}, []string{}
+	for _, c := range cm.CommentList.Comment {
+		if ca, ok := s.areaOf(c.RefAttr); ok && ca.within(a) {
+			author := ""
+			if int(c.AuthorIdAttr) < len(cm.Authors.Author) {
+				author = cm.Authors.Author[c.AuthorIdAttr]
+			}
+			comments = append(comments, deepCopy(reflect.ValueOf(c)).Interface().(*sml.CT_Comment))
+			authors = append(authors, author)
+		}
+	}
+	return comments, authors
+}
+
+// checkArrays returns an error if only part of an array formula of the sheet
+// is within a.
+func (s *Sheet) checkArrays(a area) error {
+	for _, r := range s._bcgb.SheetData.Row {
+		for _, c := range r.C {
+			if c.F == nil || c.F.TAttr != sml.ST_CellFormulaTypeArray {
+				continue
+			}
+			from, to, ok := s.arrayFormulaRange(c)
+			if !ok {
+				continue
+			}
+			fa := area{ws: s._bcgb, col1: from.ColumnIdx, row1: from.RowIdx, col2: to.ColumnIdx, row2: to.RowIdx}
+			if fa.overlaps(a) && !fa.within(a) {
+				return fmt.Errorf("can't change part of the array formula in %s", rangeString(from, to))
+			}
+		}
+	}
+	return nil
+}
+
+// checkMergedCells returns an error if only part of a merged cell of the
+// sheet is within a.
+func (s *Sheet) checkMergedCells(a area) error {
+	for _, m := range s.MergedCells() {
+		if ma, ok := s.areaOf(m.Reference()); ok && ma.overlaps(a) && !ma.within(a) {
+			return fmt.Errorf("can't change part of the merged cell %s", m.Reference())
+		}
+	}
+	return nil
+}
+
+// clearArea removes the cells, merged cells and comments of the sheet within
+// a.
+func (s *Sheet) clearArea(a area) {
+	s.removeCells(a)
+	if mc := s._bcgb.MergeCells; mc != nil {
+		kept := mc.MergeCell[:0]
+		for _, m := range mc.MergeCell {
+			if ma, ok := s.areaOf(m.RefAttr); !ok || !ma.within(a) {
+				kept = append(kept, m)
+			}
+		}
+		mc.MergeCell = kept
+		if len(kept) == 0 {
+			s._bcgb.MergeCells = nil
+		} else {
+			mc.CountAttr = unioffice.Uint32(uint32(len(kept)))
+		}
+	}
+	idx := s.index()
+	wb := s._bdb
+	if idx < 0 || idx >= len(wb._cbge) || wb._cbge[idx] == nil || wb._cbge[idx].CommentList == nil {
+		return
+	}
+	cl := wb._cbge[idx].CommentList
+	kept := cl.Comment[:0]
+	for _, c := range cl.Comment {
+		if ca, ok := s.areaOf(c.RefAttr); !ok || !ca.within(a) {
+			kept = append(kept, c)
+		}
+	}
+	cl.Comment = kept
+	vml := s.commentDrawing()
+	if vml == nil {
+		return
+	}
+	shapes := vml.Shape[:0]
+	for _, sh := range vml.Shape {
+		keep := true
+		for _, el := range sh.EG_ShapeElements {
+			if cd := el.ClientData; cd != nil && cd.Row != nil && cd.Column != nil &&
+				a.contains(cellKey{a.ws, uint32(*cd.Column), uint32(*cd.Row + 1)}) {
+				keep = false
+			}
+		}
+		if keep {
+			shapes = append(shapes, sh)
+		}
+	}
+	vml.Shape = shapes
+}
+
+// removeCells removes the cells of the sheet within a.
+func (s *Sheet) removeCells(a area) {
+	for _, r := range s._bcgb.SheetData.Row {
+		if r.RAttr == nil || *r.RAttr < a.row1 || *r.RAttr > a.row2 {
+			continue
+		}
+		kept := r.C[:0]
+		for _, c := range r.C {
+			if c.RAttr != nil {
+				if ref, err := reference.ParseCellReference(*c.RAttr); err == nil && a.contains(cellKey{a.ws, ref.ColumnIdx, ref.RowIdx}) {
+					continue
+				}
+			}
+			kept = append(kept, c)
+		}
+		r.C = kept
+	}
+}
+
+// placeCells adds cells to the sheet at their references, keeping the rows
+// and the cells of each row sorted.  The sheet must not have cells at the
+// same references.
+func (s *Sheet) placeCells(cells []*sml.CT_Cell) {
+	sd := s._bcgb.SheetData
+	rows := map[uint32]*sml.CT_Row{}
+	for _, r := range sd.Row {
+		if r.RAttr != nil {
+			rows[*r.RAttr] = r
+		}
+	}
+	added := false
+	touched := map[*sml.CT_Row]bool{}
+	for _, c := range cells {
+		ref, err := reference.ParseCellReference(*c.RAttr)
+		if err != nil {
+			continue
+		}
+		r := rows[ref.RowIdx]
+		if r == nil {
+			r = sml.NewCT_Row()
+			r.RAttr = unioffice.Uint32(ref.RowIdx)
+			sd.Row = append(sd.Row, r)
+			rows[ref.RowIdx] = r
+			added = true
+		}
+		r.C = append(r.C, c)
+		touched[r] = true
+	}
+	if added {
+		sort.SliceStable(sd.Row, func(i, j int) bool {
+			return rowNumber(sd.Row[i]) < rowNumber(sd.Row[j])
+		})
+	}
+	for r := range touched {
+		sort.SliceStable(r.C, func(i, j int) bool {
+			return columnOf(r.C[i]) < columnOf(r.C[j])
+		})
+	}
+}
+
+// rowNumber returns the number of a row, or zero if it isn't numbered.
+func rowNumber(r *sml.CT_Row) uint32 {
+	if r.RAttr == nil {
+		return 0
+	}
+	return *r.RAttr
+}
+
+// columnOf returns the column index of a cell, or zero if it has no
+// reference.
+func columnOf(c *sml.CT_Cell) uint32 {
+	if c.RAttr == nil {
+		return 0
+	}
+	ref, err := reference.ParseCellReference(*c.RAttr)
+	if err != nil {
+		return 0
+	}
+	return ref.ColumnIdx
+}
+
+// areaOf returns the area of the sheet covered by a cell or a range.
+func (s *Sheet) areaOf(ref string) (area, bool) {
+	from, to, err := parseArea(ref)
+	if err != nil {
+		return area{}, false
+	}
+	return area{ws: s._bcgb, col1: from.ColumnIdx, row1: from.RowIdx, col2: to.ColumnIdx, row2: to.RowIdx}, true
+}
+
+// offset returns the area moved by a number of columns and rows.
+func (a area) offset(cols, rows int) area {
+	a.col1 = uint32(int(a.col1) + cols)
+	a.col2 = uint32(int(a.col2) + cols)
+	a.row1 = uint32(int(a.row1) + rows)
+	a.row2 = uint32(int(a.row2) + rows)
+	return a
+}
+
+// containsCell returns true if a cell of the sheet of the area is within it.
+func (a area) containsCell(c *sml.CT_Cell) bool {
+	if c.RAttr == nil {
+		return false
+	}
+	ref, err := reference.ParseCellReference(*c.RAttr)
+	return err == nil && a.contains(cellKey{a.ws, ref.ColumnIdx, ref.RowIdx})
+}
+
+// overlaps returns true if the areas have cells in common.
+func (a area) overlaps(b area) bool {
+	return a.col1 <= b.col2 && b.col1 <= a.col2 && a.row1 <= b.row2 && b.row1 <= a.row2
+}
+
+// within returns true if all of the cells of a are within b.
+func (a area) within(b area) bool {
+	return a.col1 >= b.col1 && a.col2 <= b.col2 && a.row1 >= b.row1 && a.row2 <= b.row2
+}
+
+// deepCopy returns a copy of v that shares no pointers, slices or maps with
+// it.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Elem().Type())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, k := range v.MapKeys() {
+			c.SetMapIndex(k, deepCopy(v.MapIndex(k)))
+		}
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem()))
+		return c
+	}
+	return v
+}
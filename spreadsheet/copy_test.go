@@ -0,0 +1,267 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"fmt"
+	"testing"
+)
+
+// copySheet returns a sheet with numbers in A1:A2, a formula with relative,
+// absolute and mixed references in B1, text in C1, a styled cell, merged
+// cells and a comment.
+func copySheet(t *testing.T) (*Workbook, Sheet) {
+	t.Helper()
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetNumber(1)
+	s.Cell("A2").SetNumber(2)
+	s.Cell("B1").SetFormulaRaw("A1+$A$1+A$1+$A1")
+	s.Cell("C1").SetString("text")
+	cs := wb.StyleSheet.AddCellStyle()
+	cs.SetNumberFormat("0.00")
+	s.Cell("A1").SetStyle(cs)
+	s.AddMergedCells("D1", "E1")
+	s.Comments().AddCommentWithStyle("C1", "author", "note")
+	return wb, s
+}
+
+func checkFormulas(t *testing.T, s Sheet, exp map[string]string) {
+	t.Helper()
+	for ref, f := range exp {
+		if got := s.Cell(ref).GetFormula(); got != f {
+			t.Errorf("expected the formula %q in %s, got %q", f, ref, got)
+		}
+	}
+}
+
+func commentRefs(s Sheet) map[string]bool {
+	refs := map[string]bool{}
+	for _, c := range s.Comments().Comments() {
+		refs[c.CellReference()] = true
+	}
+	return refs
+}
+
+func mergedRefs(s Sheet) map[string]bool {
+	refs := map[string]bool{}
+	for _, m := range s.MergedCells() {
+		refs[m.Reference()] = true
+	}
+	return refs
+}
+
+func TestCopyRange(t *testing.T) {
+	wb, s := copySheet(t)
+	wb.RecalculateFormulas()
+	if err := s.CopyRange("A1:E1", "A5"); err != nil {
+		t.Fatal(err)
+	}
+	checkFormulas(t, s, map[string]string{"B5": "A5+$A$1+A$1+$A5", "B1": "A1+$A$1+A$1+$A1"})
+	checkCells(t, s, map[string]string{"A5": "1.00", "C5": "text"})
+	if s.Cell("B5").X().V != nil {
+		t.Errorf("expected the copied formula to have no cached value")
+	}
+	if s.Cell("A5").X().SAttr == nil || *s.Cell("A5").X().SAttr != *s.Cell("A1").X().SAttr {
+		t.Errorf("expected the style to be copied")
+	}
+	if m := mergedRefs(s); !m["D1:E1"] || !m["D5:E5"] {
+		t.Errorf("expected the merged cells to be copied, got %v", m)
+	}
+	if c := commentRefs(s); !c["C1"] || !c["C5"] {
+		t.Errorf("expected the comment to be copied, got %v", c)
+	}
+	wb.RecalculateFormulas()
+	checkCells(t, s, map[string]string{"B5": "4"})
+
+	// the destination is replaced, including by empty cells
+	s.Cell("B7").SetString("old")
+	if err := s.CopyRange("A1:B2", "A6"); err != nil {
+		t.Fatal(err)
+	}
+	checkCells(t, s, map[string]string{"A6": "1.00", "A7": "2", "B7": ""})
+	checkFormulas(t, s, map[string]string{"B6": "A6+$A$1+A$1+$A6"})
+
+	// overlapping ranges are read before being written
+	if err := s.CopyRange("A1:A2", "A2"); err != nil {
+		t.Fatal(err)
+	}
+	checkCells(t, s, map[string]string{"A1": "1.00", "A2": "1.00", "A3": "2"})
+}
+
+func TestCopyRangeRepeated(t *testing.T) {
+	_, s := copySheet(t)
+	if err := s.CopyRange("B1", "G1:H2"); err != nil {
+		t.Fatal(err)
+	}
+	checkFormulas(t, s, map[string]string{
+		"G1": "F1+$A$1+F$1+$A1",
+		"H1": "G1+$A$1+G$1+$A1",
+		"G2": "F2+$A$1+F$1+$A2",
+		"H2": "G2+$A$1+G$1+$A2",
+	})
+	if err := s.CopyRange("A1:A2", "J1:K4"); err != nil {
+		t.Fatal(err)
+	}
+	checkCells(t, s, map[string]string{"J1": "1.00", "K2": "2", "J3": "1.00", "K4": "2"})
+	if err := s.CopyRange("A1:A2", "J1:J3"); err == nil {
+		t.Errorf("expected an error copying to a range that isn't a multiple of the source")
+	}
+	if err := s.CopyRange("A1", "XFD2:XFE2"); err == nil {
+		t.Errorf("expected an error copying outside of the sheet")
+	}
+	if err := s.CopyRange("D1:E1", "D2:E3"); err != nil {
+		t.Fatal(err)
+	}
+	if m := mergedRefs(s); !m["D2:E2"] || !m["D3:E3"] {
+		t.Errorf("expected the merged cells to be repeated, got %v", m)
+	}
+	if err := s.CopyRange("A1", "E3"); err == nil {
+		t.Errorf("expected an error copying over part of merged cells")
+	}
+}
+
+func TestCopyRangeToSheet(t *testing.T) {
+	wb, s := copySheet(t)
+	other := wb.AddSheet()
+	other.SetName("Other Sheet")
+	if err := s.CopyRange("A1:C1", "'Other Sheet'!C3"); err != nil {
+		t.Fatal(err)
+	}
+	// references aren't prefixed so they refer to the destination sheet
+	checkFormulas(t, other, map[string]string{"D3": "C3+$A$1+C$1+$A3"})
+	checkCells(t, other, map[string]string{"C3": "1.00", "E3": "text"})
+	if c := commentRefs(other); !c["E3"] {
+		t.Errorf("expected the comment to be copied, got %v", c)
+	}
+	if len(s.Comments().Comments()) != 1 {
+		t.Errorf("expected the source comment to remain")
+	}
+	other.Cell("A1").SetNumber(10)
+	other.Cell("C1").SetNumber(100)
+	other.Cell("A3").SetNumber(1000)
+	wb.RecalculateFormulas()
+	checkCells(t, other, map[string]string{"D3": "1111"})
+
+	if err := s.CopyRange("A1", "Missing!A1"); err == nil {
+		t.Errorf("expected an error copying to a missing sheet")
+	}
+	rd := saveAndRead(t, wb)
+	checkFormulas(t, rd.Sheets()[1], map[string]string{"D3": "C3+$A$1+C$1+$A3"})
+}
+
+func TestFillDown(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	for i, v := range []float64{1, 2, 3} {
+		s.Cell(fmt.Sprintf("A%d", i+1)).SetNumber(v)
+	}
+	s.Cell("B1").SetFormulaRaw("A1*$A$1*2")
+	s.Cell("C1").SetString("x")
+	if err := s.FillDown("B1:C3"); err != nil {
+		t.Fatal(err)
+	}
+	checkFormulas(t, s, map[string]string{"B2": "A2*$A$1*2", "B3": "A3*$A$1*2"})
+	wb.RecalculateFormulas()
+	checkCells(t, s, map[string]string{"B3": "6", "C2": "x", "C3": "x"})
+	if err := s.FillDown("A1:B1"); err != nil {
+		t.Fatal(err)
+	}
+	checkCells(t, s, map[string]string{"A2": "2"})
+}
+
+func TestFillRight(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetNumber(2)
+	s.Cell("A2").SetFormulaRaw("A1+$A1+A$1")
+	if err := s.FillRight("A1:C2"); err != nil {
+		t.Fatal(err)
+	}
+	checkFormulas(t, s, map[string]string{"B2": "B1+$A1+B$1", "C2": "C1+$A1+C$1"})
+	wb.RecalculateFormulas()
+	checkCells(t, s, map[string]string{"C1": "2", "C2": "6"})
+	if err := s.FillRight("A1:A2"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMoveRange(t *testing.T) {
+	wb, s := copySheet(t)
+	s.Cell("A3").SetFormulaRaw("A1+C5")
+	s.Cell("C5").SetNumber(10)
+	s.Cell("G1").SetFormulaRaw("SUM(A1:A2)")
+	s.Cell("G2").SetFormulaRaw("$A$2")
+	other := wb.AddSheet()
+	other.Cell("A1").SetFormulaRaw("'Sheet 1'!A1*10")
+	hl := s.AddHyperlink("http://example.com")
+	s.Cell("C1").SetHyperlink(hl)
+
+	if err := s.MoveRange("A1:E3", "A11"); err != nil {
+		t.Fatal(err)
+	}
+	checkFormulas(t, s, map[string]string{
+		// the moved formulas keep referring to the same cells
+		"B11": "A11+$A$11+A$11+$A11",
+		"A13": "A11+C5",
+		// and so do references to them
+		"G1": "SUM(A11:A12)",
+		"G2": "$A$12",
+	})
+	checkFormulas(t, other, map[string]string{"A1": "'Sheet 1'!A11*10"})
+	checkCells(t, s, map[string]string{"A1": "", "B1": "", "A11": "1.00", "C11": "text"})
+	if m := mergedRefs(s); len(m) != 1 || !m["D11:E11"] {
+		t.Errorf("expected the merged cells to move, got %v", m)
+	}
+	if c := commentRefs(s); len(c) != 1 || !c["C11"] {
+		t.Errorf("expected the comment to move, got %v", c)
+	}
+	if hls := s.X().Hyperlinks; hls == nil || len(hls.Hyperlink) != 1 || hls.Hyperlink[0].RefAttr != "C11" {
+		t.Errorf("expected the hyperlink to move")
+	}
+	wb.RecalculateFormulas()
+	checkCells(t, s, map[string]string{"B11": "4", "A13": "11", "G1": "3", "G2": "2"})
+	checkCells(t, other, map[string]string{"A1": "10"})
+}
+
+func TestMoveRangeOverwrites(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetNumber(1)
+	s.Cell("C1").SetNumber(2)
+	s.Cell("D1").SetFormulaRaw("C1+A1")
+	s.Cell("D2").SetFormulaRaw("SUM(C1:C2)")
+	if err := s.MoveRange("A1", "C1"); err != nil {
+		t.Fatal(err)
+	}
+	// references to the cells that are moved over become #REF!, ranges that
+	// are only partly moved over are left as they are
+	checkFormulas(t, s, map[string]string{"D1": "#REF!+C1", "D2": "SUM(C1:C2)"})
+	checkCells(t, s, map[string]string{"A1": "", "C1": "1"})
+
+	if err := s.MoveRange("C1", "C1"); err != nil {
+		t.Errorf("expected moving a range onto itself to do nothing, got %s", err)
+	}
+	other := wb.AddSheet()
+	if err := s.MoveRange("C1", other.Name()+"!A1"); err == nil {
+		t.Errorf("expected an error moving to another sheet")
+	}
+	if err := s.MoveRange("C1:C2", "E1:E3"); err == nil {
+		t.Errorf("expected an error moving to a range of another size")
+	}
+	s.AddMergedCells("F1", "G1")
+	if err := s.MoveRange("F1", "H1"); err == nil {
+		t.Errorf("expected an error moving part of merged cells")
+	}
+	if err := s.MoveRange("C1", "XFD1048576"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.MoveRange("XFC1048576:XFD1048576", "XFD1"); err == nil {
+		t.Errorf("expected an error moving outside of the sheet")
+	}
+}
@@ -0,0 +1,557 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/format"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+)
+
+// CSVQuoteStyle determines which fields of CSV are quoted.
+type CSVQuoteStyle byte
+
+// CSVQuoteStyle types
+const (
+	// CSVQuoteMinimal quotes fields containing the delimiter, quotes, line
+	// breaks or leading or trailing spaces.
+	CSVQuoteMinimal CSVQuoteStyle = iota
+	// CSVQuoteAll quotes every field.
+	CSVQuoteAll
+	// CSVQuoteNonNumeric quotes the fields of cells which aren't numbers or
+	// booleans.  When importing, quoted fields are kept as text.
+	CSVQuoteNonNumeric
+	// CSVQuoteNone never quotes fields.  When importing, quotes are read as
+	// ordinary characters.
+	CSVQuoteNone
+)
+
+// CSVEncoding is a character encoding of CSV.
+type CSVEncoding byte
+
+// CSVEncoding types
+const (
+	CSVEncodingUTF8 CSVEncoding = iota
+	CSVEncodingUTF16LE
+	CSVEncodingUTF16BE
+	CSVEncodingLatin1 // ISO 8859-1
+	CSVEncodingWindows1252
+)
+
+// CSVOption is an option of importing or exporting CSV.
+type CSVOption func(*csvOptions)
+
+type csvOptions struct {
+	delimiter rune
+	quote     CSVQuoteStyle
+	encoding  CSVEncoding
+	bom       bool
+	infer     bool
+	locale    *format.Locale
+	raw       bool
+	ref       string
+	fillMerge bool
+}
+
+func newCSVOptions(opts []CSVOption) *csvOptions {
+	o := &csvOptions{delimiter: ',', infer: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithDelimiter sets the character separating fields, which is a comma by
+// default.  Use a tab for TSV.
+func WithDelimiter(r rune) CSVOption {
+	return func(o *csvOptions) { o.delimiter = r }
+}
+
+// WithQuoteStyle sets which fields are quoted, CSVQuoteMinimal by default.
+func WithQuoteStyle(q CSVQuoteStyle) CSVOption {
+	return func(o *csvOptions) { o.quote = q }
+}
+
+// WithEncoding sets the character encoding, which is UTF-8 by default.  When
+// importing, a byte order mark overrides the encoding.
+func WithEncoding(e CSVEncoding) CSVOption {
+	return func(o *csvOptions) { o.encoding = e }
+}
+
+// WithBOM makes ExportCSV start with a byte order mark, which makes Excel
+// detect UTF-8 and UTF-16.
+func WithBOM() CSVOption {
+	return func(o *csvOptions) { o.bom = true }
+}
+
+// WithTypeInference sets whether ImportCSV stores fields that look like
+// numbers, dates, times or booleans as such, which it does by default.
+// Otherwise all fields are stored as text.
+func WithTypeInference(infer bool) CSVOption {
+	return func(o *csvOptions) { o.infer = infer }
+}
+
+// WithLocale sets the locale of the numbers and dates read by ImportCSV (e.g.
+// 1.234,5 in German), which also becomes the locale of the workbook.
+func WithLocale(l *format.Locale) CSVOption {
+	return func(o *csvOptions) { o.locale = l }
+}
+
+// WithRawValues makes ExportCSV write the values of cells as stored (e.g.
+// 1234.5 and 45000 for a date) rather than as displayed.
+func WithRawValues() CSVOption {
+	return func(o *csvOptions) { o.raw = true }
+}
+
+// WithRange limits ExportCSV to a range of cells (e.g. B2:D10).  By default
+// it writes the cells from A1 to the last row and column with values.
+func WithRange(ref string) CSVOption {
+	return func(o *csvOptions) { o.ref = ref }
+}
+
+// WithMergedValues makes ExportCSV repeat the value of merged cells in each
+// of their fields.  By default it's written only in the field of the top left
+// cell.
+func WithMergedValues() CSVOption {
+	return func(o *csvOptions) { o.fillMerge = true }
+}
+
+// ImportCSV reads CSV into a new workbook with a single sheet.  With type
+// inference, fields with numbers, dates, times and booleans are stored with
+// SetNumber, SetDate, SetTime and SetBool, with a number format for dates,
+// times and percentages.  Numbers with leading zeros or more than 15 digits
+// (e.g. zip codes and card numbers) are kept as text, as they would change if
+// stored as numbers.
+func ImportCSV(r io.Reader, opts ...CSVOption) (*Workbook, error) {
+	o := newCSVOptions(opts)
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	wb := New()
+	if o.locale != nil {
+		wb.SetLocale(o.locale)
+	}
+	s := wb.AddSheet()
+	styles := map[StandardFormat]CellStyle{}
+	rowNum := uint32(0)
+	err = parseCSV(decodeCSV(b, o.encoding), o, func(fields []csvField) {
+		rowNum++
+		row := s.Row(rowNum)
+		for i, f := range fields {
+			if f.text == "" {
+				continue
+			}
+			c := row.AddNamedCell(reference.IndexToColumn(uint32(i)))
+			if !o.infer || f.quoted && o.quote == CSVQuoteNonNumeric {
+				c.SetString(f.text)
+				continue
+			}
+			if id := setInferred(c, f.text, wb.Locale()); id != 0 {
+				cs, ok := styles[id]
+				if !ok {
+					cs = wb.StyleSheet.AddCellStyle()
+					cs.SetNumberFormatStandard(id)
+					styles[id] = cs
+				}
+				c.SetStyle(cs)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wb, nil
+}
+
+// setInferred sets a cell to the value of a field, returning the number
+// format that displays it or zero for General.
+func setInferred(c Cell, text string, l *format.Locale) StandardFormat {
+	t := strings.TrimSpace(text)
+	switch {
+	case strings.EqualFold(t, "TRUE"):
+		c.SetBool(true)
+		return 0
+	case strings.EqualFold(t, "FALSE"):
+		c.SetBool(false)
+		return 0
+	case keepAsText(t):
+	default:
+		if v, ok := l.ParseNumber(t); ok {
+			c.SetNumber(v)
+			switch {
+			case strings.HasSuffix(t, "%") && strings.Contains(t, l.DecimalSeparator):
+				return StandardFormat10
+			case strings.HasSuffix(t, "%"):
+				return StandardFormatPercent
+			}
+			return 0
+		}
+		if v, ok := l.ParseDate(t); ok {
+			days, frac := math.Modf(v)
+			d := time.Date(1899, 12, 30+int(days), 0, 0, int(math.Floor(frac*86400+0.5)), 0, time.Local)
+			switch {
+			case frac == 0:
+				c.SetDate(d)
+				return StandardFormatDate
+			case days == 0 && d.Second() == 0:
+				c.SetTime(d)
+				return StandardFormat20
+			case days == 0:
+				c.SetTime(d)
+				return StandardFormat21
+			}
+			c.SetTime(d)
+			return StandardFormatDateTime
+		}
+	}
+	c.SetString(text)
+	return 0
+}
+
+// keepAsText returns true for numbers which change when stored as numbers,
+// i.e. with leading zeros or more digits than a number holds.
+func keepAsText(t string) bool {
+	t = strings.TrimLeft(t, "+-(")
+	if len(t) > 1 && t[0] == '0' && t[1] >= '0' && t[1] <= '9' {
+		return true
+	}
+	digits := 0
+	for _, r := range t {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == 'e' || r == 'E':
+			return false
+		}
+	}
+	return digits > 15
+}
+
+// csvField is a field of a record of CSV.
+type csvField struct {
+	text   string
+	quoted bool
+}
+
+// parseCSV splits CSV into records, calling fn with the fields of each.
+func parseCSV(text string, o *csvOptions, fn func([]csvField)) error {
+	line := 1
+	fields := []csvField{}
+	var field strings.Builder
+	quoted, inQuotes, start := false, false, true
+	end := func() {
+		fields = append(fields, csvField{field.String(), quoted})
+		field.Reset()
+		quoted, start = false, true
+	}
+	for i := 0; i < len(text); {
+		r, n := utf8.DecodeRuneInString(text[i:])
+		i += n
+		switch {
+		case inQuotes && r == '"':
+			if strings.HasPrefix(text[i:], `"`) {
+				field.WriteByte('"')
+				i++
+			} else {
+				inQuotes = false
+			}
+		case inQuotes:
+			if r == '\n' {
+				line++
+			}
+			field.WriteRune(r)
+		case r == '"' && start && o.quote != CSVQuoteNone:
+			quoted, inQuotes, start = true, true, false
+		case r == o.delimiter:
+			end()
+		case r == '\r' || r == '\n':
+			if r == '\r' && strings.HasPrefix(text[i:], "\n") {
+				i++
+			}
+			end()
+			if len(fields) == 1 && fields[0].text == "" && !fields[0].quoted {
+				fields = fields[:0]
+			}
+			fn(fields)
+			fields = fields[:0]
+			line++
+		default:
+			field.WriteRune(r)
+			start = false
+		}
+	}
+	if inQuotes {
+		return fmt.Errorf("unterminated quoted field on line %d", line)
+	}
+	if !start || len(fields) > 0 {
+		end()
+		fn(fields)
+	}
+	return nil
+}
+
+// decodeCSV converts text in an encoding to UTF-8, detecting the encoding
+// from a byte order mark.
+func decodeCSV(b []byte, e CSVEncoding) string {
+	switch {
+	case bytes.HasPrefix(b, []byte{0xEF, 0xBB, 0xBF}):
+		return string(b[3:])
+	case bytes.HasPrefix(b, []byte{0xFF, 0xFE}):
+		b, e = b[2:], CSVEncodingUTF16LE
+	case bytes.HasPrefix(b, []byte{0xFE, 0xFF}):
+		b, e = b[2:], CSVEncodingUTF16BE
+	}
+	switch e {
+	case CSVEncodingUTF16LE, CSVEncodingUTF16BE:
+		u := make([]uint16, len(b)/2)
+		for i := range u {
+			if e == CSVEncodingUTF16LE {
+				u[i] = uint16(b[2*i]) | uint16(b[2*i+1])<<8
+			} else {
+				u[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+			}
+		}
+		return string(utf16.Decode(u))
+	case CSVEncodingLatin1, CSVEncodingWindows1252:
+		rs := make([]rune, len(b))
+		for i, c := range b {
+			rs[i] = rune(c)
+			if e == CSVEncodingWindows1252 && c >= 0x80 && c < 0xA0 {
+				rs[i] = windows1252[c-0x80]
+			}
+		}
+		return string(rs)
+	}
+	return string(b)
+}
+
+// windows1252 are the characters of the bytes 0x80 to 0x9F in Windows-1252,
+// which are the control characters of ISO 8859-1 where undefined.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// csvWriter writes text in an encoding.
+type csvWriter struct {
+	w *bufio.Writer
+	e CSVEncoding
+}
+
+func (w csvWriter) WriteString(s string) {
+	switch w.e {
+	case CSVEncodingUTF16LE, CSVEncodingUTF16BE:
+		for _, u := range utf16.Encode([]rune(s)) {
+			if w.e == CSVEncodingUTF16LE {
+				w.w.Write([]byte{byte(u), byte(u >> 8)})
+			} else {
+				w.w.Write([]byte{byte(u >> 8), byte(u)})
+			}
+		}
+	case CSVEncodingLatin1, CSVEncodingWindows1252:
+		for _, r := range s {
+			w.w.WriteByte(encodeByte(r, w.e))
+		}
+	default:
+		w.w.WriteString(s)
+	}
+}
+
+// encodeByte returns the byte of a character in Latin-1 or Windows-1252, or
+// a question mark for characters that they lack.
+func encodeByte(r rune, e CSVEncoding) byte {
+	if e == CSVEncodingWindows1252 {
+		for i, c := range windows1252 {
+			if c == r {
+				return byte(0x80 + i)
+			}
+		}
+		if r >= 0x80 && r < 0xA0 {
+			return '?'
+		}
+	}
+	if r < 0x100 {
+		return byte(r)
+	}
+	return '?'
+}
+
+// ExportCSV writes the cells of the sheet as CSV, with a record per row and
+// lines ending in CRLF.  Fields have the values of cells as displayed by
+// GetFormattedValue unless WithRawValues is given.  Cells covered by a merged
+// cell other than its top left cell are empty unless WithMergedValues is
+// given.
+func (s *Sheet) ExportCSV(w io.Writer, opts ...CSVOption) error {
+	o := newCSVOptions(opts)
+	a, ok := s.usedArea()
+	if o.ref != "" {
+		if a, ok = s.areaOf(o.ref); !ok {
+			return fmt.Errorf("invalid range %s", o.ref)
+		}
+	}
+	bw := bufio.NewWriter(w)
+	cw := csvWriter{bw, o.encoding}
+	if o.bom {
+		cw.WriteString("\ufeff")
+	}
+	if !ok {
+		return bw.Flush()
+	}
+	merged := s.mergedValues(a, o.fillMerge)
+	rows := map[uint32]*sml.CT_Row{}
+	for _, r := range s._bcgb.SheetData.Row {
+		if r.RAttr != nil && *r.RAttr >= a.row1 && *r.RAttr <= a.row2 {
+			rows[*r.RAttr] = r
+		}
+	}
+	delim := string(o.delimiter)
+	fields := make([]Cell, a.col2-a.col1+1)
+	for rn := a.row1; rn <= a.row2; rn++ {
+		for i := range fields {
+			fields[i] = Cell{}
+		}
+		if r, ok := rows[rn]; ok {
+			for _, c := range r.C {
+				if c.RAttr == nil {
+					continue
+				}
+				ref, err := reference.ParseCellReference(*c.RAttr)
+				if err == nil && ref.ColumnIdx >= a.col1 && ref.ColumnIdx <= a.col2 {
+					fields[ref.ColumnIdx-a.col1] = Cell{s._bdb, s, r, c}
+				}
+			}
+		}
+		for i := range fields {
+			if m, ok := merged[cellKey{s._bcgb, a.col1 + uint32(i), rn}]; ok {
+				fields[i] = m
+			}
+		}
+		for i, c := range fields {
+			if i > 0 {
+				cw.WriteString(delim)
+			}
+			text, numeric := "", true
+			if c._dbd != nil && !c.IsEmpty() {
+				text, numeric = csvValue(c, o.raw)
+			}
+			q, err := o.quoteField(text, numeric)
+			if err != nil {
+				return fmt.Errorf("%s%d: %s", reference.IndexToColumn(a.col1+uint32(i)), rn, err)
+			}
+			cw.WriteString(q)
+		}
+		cw.WriteString("\r\n")
+	}
+	return bw.Flush()
+}
+
+// mergedValues returns the cells whose values appear in the cells covered by
+// merged cells within a, which is an empty cell unless fill is true.
+func (s *Sheet) mergedValues(a area, fill bool) map[cellKey]Cell {
+	cells := map[cellKey]Cell{}
+	for _, m := range s.MergedCells() {
+		ma, ok := s.areaOf(m.Reference())
+		if !ok || !ma.overlaps(a) {
+			continue
+		}
+		top := Cell{}
+		if fill {
+			ref := reference.CellReference{Column: reference.IndexToColumn(ma.col1), ColumnIdx: ma.col1, RowIdx: ma.row1}
+			if c := s.findCell(ref); c != nil {
+				top = Cell{s._bdb, s, nil, c}
+			}
+		}
+		for row := ma.row1; row <= ma.row2; row++ {
+			for col := ma.col1; col <= ma.col2; col++ {
+				if col != ma.col1 || row != ma.row1 {
+					cells[cellKey{s._bcgb, col, row}] = top
+				}
+			}
+		}
+	}
+	return cells
+}
+
+// csvValue returns the value of a cell as written to CSV and whether it's a
+// number or a boolean.
+func csvValue(c Cell, raw bool) (string, bool) {
+	numeric := c._dbd.TAttr == sml.ST_CellTypeN || c._dbd.TAttr == sml.ST_CellTypeB || c._dbd.TAttr == sml.ST_CellTypeUnset && c.IsNumber()
+	if !raw {
+		return c.GetFormattedValue(), numeric
+	}
+	switch c._dbd.TAttr {
+	case sml.ST_CellTypeS, sml.ST_CellTypeInlineStr, sml.ST_CellTypeStr:
+		return c.GetString(), false
+	case sml.ST_CellTypeB:
+		if b, _ := c.GetValueAsBool(); b {
+			return "TRUE", true
+		}
+		return "FALSE", true
+	}
+	v, _ := c.GetRawValue()
+	return v, numeric
+}
+
+// quoteField returns a field quoted as required by the quote style.
+func (o *csvOptions) quoteField(s string, numeric bool) (string, error) {
+	special := strings.ContainsRune(s, o.delimiter) || strings.ContainsAny(s, "\"\r\n") || strings.TrimSpace(s) != s
+	switch o.quote {
+	case CSVQuoteNone:
+		if strings.ContainsRune(s, o.delimiter) || strings.ContainsAny(s, "\r\n") {
+			return "", errors.New("value contains the delimiter or a line break and can't be written without quotes")
+		}
+		return s, nil
+	case CSVQuoteAll:
+		special = true
+	case CSVQuoteNonNumeric:
+		special = special || !numeric
+	}
+	if !special {
+		return s, nil
+	}
+	return `"` + strings.Replace(s, `"`, `""`, -1) + `"`, nil
+}
+
+// usedArea returns the area from A1 to the last row and column with cells,
+// or false if the sheet has no cells.
+func (s *Sheet) usedArea() (area, bool) {
+	a := area{ws: s._bcgb, col1: 0, row1: 1}
+	found := false
+	for _, r := range s._bcgb.SheetData.Row {
+		for _, c := range r.C {
+			if c.RAttr == nil {
+				continue
+			}
+			ref, err := reference.ParseCellReference(*c.RAttr)
+			if err != nil {
+				continue
+			}
+			found = true
+			if ref.ColumnIdx > a.col2 {
+				a.col2 = ref.ColumnIdx
+			}
+			if ref.RowIdx > a.row2 {
+				a.row2 = ref.RowIdx
+			}
+		}
+	}
+	return a, found
+}
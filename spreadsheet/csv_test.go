@@ -0,0 +1,254 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/format"
+)
+
+const csvInput = "name,amount,when,flag,zip,note\r\n" +
+	"apple,1.5,2023-03-15,TRUE,01234,\"a, b\"\r\n" +
+	"\"pear\",12%,14:30,false,4111111111111111,\"line1\nline2 \"\"q\"\"\"\n" +
+	",-3,3/15/2023 14:30,,1e3,  spaced  \n" +
+	"\n" +
+	"\"12\",12.50%,2:30:15 PM,(5),-0012,x\"y"
+
+func importCSV(t *testing.T, in string, opts ...CSVOption) Sheet {
+	t.Helper()
+	wb, err := ImportCSV(strings.NewReader(in), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wb.Sheets()[0]
+}
+
+func exportCSV(t *testing.T, s Sheet, opts ...CSVOption) string {
+	t.Helper()
+	buf := bytes.Buffer{}
+	if err := s.ExportCSV(&buf, opts...); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestImportCSV(t *testing.T) {
+	s := importCSV(t, csvInput)
+	checkCells(t, s, map[string]string{
+		"A1": "name", "F1": "note",
+		"A2": "apple", "B2": "1.5", "C2": "3/15/2023", "D2": "TRUE", "E2": "01234", "F2": "a, b",
+		"A3": "pear", "B3": "12%", "C3": "14:30", "D3": "FALSE", "E3": "4111111111111111", "F3": "line1\nline2 \"q\"",
+		"A4": "", "B4": "-3", "C4": "3/15/2023 14:30", "E4": "1000", "F4": "  spaced  ",
+		"A5": "",
+		"A6": "12", "B6": "12.50%", "C6": "14:30:15", "D6": "-5", "E6": "-0012", "F6": "x\"y",
+	})
+	for ref, exp := range map[string]sml.ST_CellType{
+		"B2": sml.ST_CellTypeN, "D2": sml.ST_CellTypeB, "A6": sml.ST_CellTypeN, "D6": sml.ST_CellTypeN,
+		// leading zeros and long numbers are kept as text
+		"E2": sml.ST_CellTypeS, "E3": sml.ST_CellTypeS, "E6": sml.ST_CellTypeS,
+	} {
+		if got := s.Cell(ref).X().TAttr; got != exp {
+			t.Errorf("expected the type %s in %s, got %s", exp, ref, got)
+		}
+	}
+	if v, _ := s.Cell("C2").GetValueAsNumber(); v != 45000 {
+		t.Errorf("expected the date 45000 in C2, got %v", v)
+	}
+	if len(s.Rows()) != 6 {
+		t.Errorf("expected 6 rows, got %d", len(s.Rows()))
+	}
+
+	// quoted fields are kept as text with CSVQuoteNonNumeric
+	s = importCSV(t, csvInput, WithQuoteStyle(CSVQuoteNonNumeric))
+	if got := s.Cell("A6").X().TAttr; got != sml.ST_CellTypeS {
+		t.Errorf("expected the quoted number in A6 to be text, got %s", got)
+	}
+
+	s = importCSV(t, csvInput, WithTypeInference(false))
+	checkCells(t, s, map[string]string{"B2": "1.5", "C2": "2023-03-15", "D3": "false", "E4": "1e3", "C6": "2:30:15 PM", "D6": "(5)"})
+	if got := s.Cell("B2").X().TAttr; got != sml.ST_CellTypeS {
+		t.Errorf("expected text in B2 without type inference, got %s", got)
+	}
+
+	// quotes are ordinary characters with CSVQuoteNone
+	s = importCSV(t, csvInput, WithQuoteStyle(CSVQuoteNone))
+	checkCells(t, s, map[string]string{"F2": `"a`, "G2": ` b"`, "A3": `"pear"`, "F3": `"line1`, "A4": `line2 ""q"""`})
+
+	if _, err := ImportCSV(strings.NewReader("a,\"b\nc")); err == nil || err.Error() != "unterminated quoted field on line 2" {
+		t.Errorf("expected an error for an unterminated quote, got %v", err)
+	}
+}
+
+func TestImportCSVLocale(t *testing.T) {
+	de, _ := format.LocaleByName("de-DE")
+	wb, err := ImportCSV(strings.NewReader("1.234,5;15.03.2023;12,5%;1,234.5;007\n"), WithDelimiter(';'), WithLocale(de))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wb.Locale() != de {
+		t.Errorf("expected the locale to be set on the workbook")
+	}
+	s := wb.Sheets()[0]
+	checkCells(t, s, map[string]string{"A1": "1234,5", "B1": "15.03.2023", "C1": "12,50%", "D1": "1,234.5", "E1": "007"})
+	for ref, exp := range map[string]float64{"A1": 1234.5, "B1": 45000, "C1": 0.125} {
+		if v, err := s.Cell(ref).GetValueAsNumber(); err != nil || v != exp {
+			t.Errorf("expected %v in %s, got %v", exp, ref, v)
+		}
+	}
+	if got := s.Cell("D1").X().TAttr; got != sml.ST_CellTypeS {
+		t.Errorf("expected a number in another locale to be text, got %s", got)
+	}
+	if got := exportCSV(t, s, WithDelimiter(';')); got != "1234,5;15.03.2023;12,50%;1,234.5;007\r\n" {
+		t.Errorf("unexpected CSV %q", got)
+	}
+	if got := exportCSV(t, s, WithDelimiter(';'), WithRawValues()); got != "1234.5;45000;0.125;1,234.5;007\r\n" {
+		t.Errorf("unexpected raw CSV %q", got)
+	}
+}
+
+func TestExportCSVQuoting(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetString("plain")
+	s.Cell("B1").SetNumber(1.5)
+	s.Cell("C1").SetBool(true)
+	s.Cell("D1").SetString("a, b")
+	s.Cell("E1").SetString("say \"hi\"")
+	s.Cell("F1").SetString("two\nlines")
+	s.Cell("G1").SetString(" padded")
+	s.Cell("B2").SetString("x")
+	for _, tc := range []struct {
+		name string
+		opts []CSVOption
+		exp  string
+	}{
+		{"minimal", nil, "plain,1.5,TRUE,\"a, b\",\"say \"\"hi\"\"\",\"two\nlines\",\" padded\"\r\n,x,,,,,\r\n"},
+		{"all", []CSVOption{WithQuoteStyle(CSVQuoteAll)}, "\"plain\",\"1.5\",\"TRUE\",\"a, b\",\"say \"\"hi\"\"\",\"two\nlines\",\" padded\"\r\n\"\",\"x\",\"\",\"\",\"\",\"\",\"\"\r\n"},
+		{"non-numeric", []CSVOption{WithQuoteStyle(CSVQuoteNonNumeric)}, "\"plain\",1.5,TRUE,\"a, b\",\"say \"\"hi\"\"\",\"two\nlines\",\" padded\"\r\n,\"x\",,,,,\r\n"},
+		{"tabs", []CSVOption{WithDelimiter('\t')}, "plain\t1.5\tTRUE\ta, b\t\"say \"\"hi\"\"\"\t\"two\nlines\"\t\" padded\"\r\n\tx\t\t\t\t\t\r\n"},
+	} {
+		if got := exportCSV(t, s, tc.opts...); got != tc.exp {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.exp, got)
+		}
+		// the export reads back the same
+		back := importCSV(t, exportCSV(t, s, tc.opts...), tc.opts...)
+		for _, ref := range []string{"A1", "D1", "E1", "F1", "G1", "B2"} {
+			if got := back.Cell(ref).GetString(); got != s.Cell(ref).GetString() {
+				t.Errorf("%s: expected %q in %s, got %q", tc.name, s.Cell(ref).GetString(), ref, got)
+			}
+		}
+	}
+
+	buf := bytes.Buffer{}
+	if err := s.ExportCSV(&buf, WithQuoteStyle(CSVQuoteNone)); err == nil || !strings.HasPrefix(err.Error(), "D1: ") {
+		t.Errorf("expected an error writing a delimiter without quotes, got %v", err)
+	}
+	s.Cell("D1").SetString("a b")
+	s.Cell("F1").Clear()
+	if got := exportCSV(t, s, WithQuoteStyle(CSVQuoteNone)); got != "plain,1.5,TRUE,a b,say \"hi\",, padded\r\n,x,,,,,\r\n" {
+		t.Errorf("unexpected CSV without quotes %q", got)
+	}
+}
+
+func TestCSVEncoding(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetString("Grüße")
+	s.Cell("B1").SetString("€5")
+	s.Cell("C1").SetString("😀")
+	for _, tc := range []struct {
+		name   string
+		opts   []CSVOption
+		prefix []byte
+	}{
+		{"utf-8", nil, []byte("Gr\xc3\xbc")},
+		{"utf-8 bom", []CSVOption{WithBOM()}, []byte{0xEF, 0xBB, 0xBF, 'G'}},
+		{"utf-16le", []CSVOption{WithEncoding(CSVEncodingUTF16LE)}, []byte{'G', 0, 'r', 0, 0xFC, 0}},
+		{"utf-16le bom", []CSVOption{WithEncoding(CSVEncodingUTF16LE), WithBOM()}, []byte{0xFF, 0xFE, 'G', 0}},
+		{"utf-16be bom", []CSVOption{WithEncoding(CSVEncodingUTF16BE), WithBOM()}, []byte{0xFE, 0xFF, 0, 'G'}},
+	} {
+		b := exportCSV(t, s, tc.opts...)
+		if !strings.HasPrefix(b, string(tc.prefix)) {
+			t.Errorf("%s: expected the prefix % x, got % x", tc.name, tc.prefix, b[:len(tc.prefix)])
+		}
+		// a byte order mark selects the encoding, without one it must be given
+		var back Sheet
+		if bytes.HasPrefix(tc.prefix, []byte{0xEF}) || bytes.HasPrefix(tc.prefix, []byte{0xFF}) || bytes.HasPrefix(tc.prefix, []byte{0xFE}) {
+			back = importCSV(t, b)
+		} else {
+			back = importCSV(t, b, tc.opts...)
+		}
+		checkCells(t, back, map[string]string{"A1": "Grüße", "B1": "€5", "C1": "😀"})
+	}
+
+	for _, tc := range []struct {
+		e   CSVEncoding
+		exp string
+	}{
+		{CSVEncodingLatin1, "Gr\xfc\xdfe,?5,?\r\n"},
+		{CSVEncodingWindows1252, "Gr\xfc\xdfe,\x805,?\r\n"},
+	} {
+		if got := exportCSV(t, s, WithEncoding(tc.e)); got != tc.exp {
+			t.Errorf("expected %q in encoding %d, got %q", tc.exp, tc.e, got)
+		}
+	}
+	back := importCSV(t, "Gr\xfc\xdfe,\x805,\x81\r\n", WithEncoding(CSVEncodingWindows1252))
+	checkCells(t, back, map[string]string{"A1": "Grüße", "B1": "€5", "C1": "\u0081"})
+	back = importCSV(t, "\x805\r\n", WithEncoding(CSVEncodingLatin1))
+	checkCells(t, back, map[string]string{"A1": "\u00805"})
+}
+
+func TestExportCSVRange(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetString("title")
+	s.AddMergedCells("A1", "C1")
+	s.Cell("A2").SetString("name")
+	s.Cell("B2").SetNumber(1234.5)
+	cs := wb.StyleSheet.AddCellStyle()
+	cs.SetNumberFormat("#,##0.00")
+	s.Cell("B2").SetStyle(cs)
+	date := wb.StyleSheet.AddCellStyle()
+	date.SetNumberFormatStandard(StandardFormatDate)
+	s.Cell("C2").SetNumber(45000)
+	s.Cell("C2").SetStyle(date)
+	s.Cell("B3").SetString("merged")
+	s.AddMergedCells("B3", "C4")
+	s.Cell("D5").SetNumber(1)
+
+	for _, tc := range []struct {
+		name string
+		opts []CSVOption
+		exp  string
+	}{
+		{"all", nil, "title,,,\r\nname,\"1,234.50\",3/15/2023,\r\n,merged,,\r\n,,,\r\n,,,1\r\n"},
+		{"raw", []CSVOption{WithRawValues()}, "title,,,\r\nname,1234.5,45000,\r\n,merged,,\r\n,,,\r\n,,,1\r\n"},
+		{"merged", []CSVOption{WithMergedValues()}, "title,title,title,\r\nname,\"1,234.50\",3/15/2023,\r\n,merged,merged,\r\n,merged,merged,\r\n,,,1\r\n"},
+		// covered cells repeat the value even if the top left cell is outside
+		// the range
+		{"range", []CSVOption{WithRange("B2:C4"), WithMergedValues()}, "\"1,234.50\",3/15/2023\r\nmerged,merged\r\nmerged,merged\r\n"},
+		{"range without merged values", []CSVOption{WithRange("C1:D4")}, ",\r\n3/15/2023,\r\n,\r\n,\r\n"},
+	} {
+		if got := exportCSV(t, s, tc.opts...); got != tc.exp {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.exp, got)
+		}
+	}
+	buf := bytes.Buffer{}
+	if err := s.ExportCSV(&buf, WithRange("B2:")); err == nil {
+		t.Errorf("expected an error for an invalid range")
+	}
+
+	empty := wb.AddSheet()
+	if got := exportCSV(t, empty, WithBOM()); got != "\ufeff" {
+		t.Errorf("expected only a byte order mark for an empty sheet, got %q", got)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package format
+
+import (
+	"testing"
+
+	"github.com/unidoc/unioffice/color"
+)
+
+func TestNumberResult(t *testing.T) {
+	for _, tc := range []struct {
+		v     float64
+		f     string
+		exp   string
+		color color.Color
+	}{
+		{1234.567, "#,##0.00", "1,234.57", color.Auto},
+		{0.5, "0%", "50%", color.Auto},
+		{1e6, `#,##0,,"M"`, "1M", color.Auto},
+
+		// sections and colours
+		{-1234.567, "#,##0.00;[Red](#,##0.00)", "(1,234.57)", color.Red},
+		{1234.567, "#,##0.00;[Red](#,##0.00)", "1,234.57", color.Auto},
+		{0, `#,##0.00;[Red](#,##0.00);"zero"`, "zero", color.Auto},
+		{-5, "0;-0;0;@", "-5", color.Auto},
+		{1234.5, "[Blue]0.0", "1234.5", color.Blue},
+		{1, "[Color10]0", "1", color.RGB(0, 0x80, 0)},
+
+		// conditional sections
+		{150, `[>100]"big";[<=-100]"neg";0`, "big", color.Auto},
+		{50, `[>100]"big";[<=-100]"neg";0`, "50", color.Auto},
+		{-150, `[>100]"big";[<=-100]"neg";0`, "neg", color.Auto},
+
+		// currency
+		{1234.5, "[$€-407] #,##0.00", "€ 1,234.50", color.Auto},
+		{1234.5, "#,##0.00 [$€-407]", "1,234.50 €", color.Auto},
+
+		// fractions
+		{0.75, "# ?/?", " 3/4", color.Auto},
+		{1.25, "# ?/?", "1 1/4", color.Auto},
+		{0.333, "# ??/??", "  1/3 ", color.Auto},
+		{5.5, "?/4", "22/4", color.Auto},
+
+		// exponents
+		{12345, "0.00E+00", "1.23E+04", color.Auto},
+		{12345, "##0.0E+0", "12.3E+3", color.Auto},
+		{0.00012345, "##0.0E+0", "123.5E-6", color.Auto},
+
+		// dates and elapsed times
+		{1.5, "[h]:mm:ss", "36:00:00", color.Auto},
+		{2.75, "[mm]:ss", "3960:00", color.Auto},
+		{0.5, "h:mm AM/PM", "12:00 PM", color.Auto},
+		{45000.25, "yyyy-mm-dd hh:mm", "2023-03-15 06:00", color.Auto},
+		{45000, "dddd, mmmm d", "Wednesday, March 15", color.Auto},
+	} {
+		got := NumberResult(tc.v, tc.f)
+		if got.Text != tc.exp {
+			t.Errorf("%v formatted with %s: expected %q, got %q", tc.v, tc.f, tc.exp, got.Text)
+		}
+		if got.Color != tc.color {
+			t.Errorf("%v formatted with %s: expected the colour %v, got %v", tc.v, tc.f, tc.color, got.Color)
+		}
+	}
+}
+
+func TestStringResult(t *testing.T) {
+	for _, tc := range []struct {
+		v, f, exp string
+		color     color.Color
+	}{
+		{"abc", `"pre "@" post"`, "pre abc post", color.Auto},
+		{"abc", `0;0;0;[Green]"<"@">"`, "<abc>", color.Lime},
+		// formats without a text section leave text unchanged
+		{"abc", "0.00", "abc", color.Auto},
+	} {
+		got := ValueResult(tc.v, tc.f)
+		if got.Text != tc.exp || got.Color != tc.color {
+			t.Errorf("%s formatted with %s: expected %q %v, got %q %v", tc.v, tc.f, tc.exp, tc.color, got.Text, got.Color)
+		}
+	}
+	if got := ValueResult("12", "0.00").Text; got != "12.00" {
+		t.Errorf("expected numeric text to be formatted as a number, got %q", got)
+	}
+}
+
+func TestResultPad(t *testing.T) {
+	for _, tc := range []struct {
+		v     string
+		f     string
+		text  string
+		fill  rune
+		width int
+		exp   string
+	}{
+		// _ reserves the width of the following character
+		{"5", "0_);(0)", "5 ", 0, 10, "5 "},
+		{"abc", "_(@_)", " abc ", 0, 10, " abc "},
+		// * repeats the following character to fill the cell
+		{"5", "* #,##0", "5", ' ', 10, "         5"},
+		{"5", "0*-", "5", '-', 10, "5---------"},
+		{"abc", "@*.", "abc", '.', 10, "abc......."},
+		{"abc", "@*.", "abc", '.', 2, "abc"},
+	} {
+		got := ValueResult(tc.v, tc.f)
+		if got.Text != tc.text || got.Fill != tc.fill {
+			t.Errorf("%s formatted with %s: expected %q filled with %q, got %q filled with %q", tc.v, tc.f, tc.text, tc.fill, got.Text, got.Fill)
+		}
+		if p := got.Pad(tc.width); p != tc.exp {
+			t.Errorf("%s formatted with %s: expected %q padded to %d, got %q", tc.v, tc.f, tc.exp, tc.width, p)
+		}
+	}
+}
+
+func TestIsDate(t *testing.T) {
+	for f, exp := range map[string]bool{
+		"yyyy-mm-dd":   true,
+		"[h]:mm:ss":    true,
+		"h:mm AM/PM":   true,
+		"0.00":         false,
+		`"day"0`:       false,
+		"[Red]#,##0":   false,
+		"General":      false,
+		"[$-409]mmm d": true,
+	} {
+		if got := IsDate(f); got != exp {
+			t.Errorf("IsDate(%q): expected %v, got %v", f, exp, got)
+		}
+	}
+}
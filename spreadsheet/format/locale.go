@@ -0,0 +1,433 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package format
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Locale holds the conventions of a language and region for displaying and
+// parsing numbers and dates.  Number formats always use . and , for the
+// decimal and the thousands separators, which are displayed as the separators
+// of the locale.
+type Locale struct {
+	// LCID is the Windows locale ID (e.g. 0x407 for German in Germany).
+	LCID uint32
+	// Name is the language tag (e.g. de-DE).
+	Name string
+
+	DecimalSeparator string
+	GroupSeparator   string
+
+	MonthNames      [12]string
+	ShortMonthNames [12]string
+	DayNames        [7]string // starting with Sunday
+	ShortDayNames   [7]string
+	AM, PM          string
+
+	// Formats are the codes of the built-in number formats that depend on the
+	// locale by their ID, which are the date and time formats 14 to 22.
+	Formats map[uint32]string
+}
+
+// newLocale returns a locale with the names given as space separated lists.
+func newLocale(lcid uint32, name, decimal, group, months, shortMonths, days, shortDays, am, pm string, formats map[uint32]string) *Locale {
+	l := &Locale{LCID: lcid, Name: name, DecimalSeparator: decimal, GroupSeparator: group, AM: am, PM: pm, Formats: formats}
+	copy(l.MonthNames[:], strings.Fields(months))
+	copy(l.ShortMonthNames[:], strings.Fields(shortMonths))
+	copy(l.DayNames[:], strings.Fields(days))
+	copy(l.ShortDayNames[:], strings.Fields(shortDays))
+	return l
+}
+
+// EnglishUS is the locale of English in the United States, which is used by
+// the functions of the package that don't take a locale.
+var EnglishUS = newLocale(0x409, "en-US", ".", ",",
+	"January February March April May June July August September October November December",
+	"Jan Feb Mar Apr May Jun Jul Aug Sep Oct Nov Dec",
+	"Sunday Monday Tuesday Wednesday Thursday Friday Saturday",
+	"Sun Mon Tue Wed Thu Fri Sat", "AM", "PM",
+	map[uint32]string{14: "m/d/yyyy", 22: "m/d/yyyy h:mm"})
+
+var locales = []*Locale{
+	EnglishUS,
+	newLocale(0x809, "en-GB", ".", ",",
+		"January February March April May June July August September October November December",
+		"Jan Feb Mar Apr May Jun Jul Aug Sep Oct Nov Dec",
+		"Sunday Monday Tuesday Wednesday Thursday Friday Saturday",
+		"Sun Mon Tue Wed Thu Fri Sat", "AM", "PM",
+		map[uint32]string{14: "dd/mm/yyyy", 15: "dd-mmm-yy", 16: "dd-mmm", 20: "hh:mm", 21: "hh:mm:ss", 22: "dd/mm/yyyy hh:mm"}),
+	newLocale(0x407, "de-DE", ",", ".",
+		"Januar Februar März April Mai Juni Juli August September Oktober November Dezember",
+		"Jan Feb Mär Apr Mai Jun Jul Aug Sep Okt Nov Dez",
+		"Sonntag Montag Dienstag Mittwoch Donnerstag Freitag Samstag",
+		"So Mo Di Mi Do Fr Sa", "AM", "PM",
+		map[uint32]string{14: "dd.mm.yyyy", 15: "dd. mmm yy", 16: "dd. mmm", 17: "mmm yy", 20: "hh:mm", 21: "hh:mm:ss", 22: "dd.mm.yyyy hh:mm"}),
+	newLocale(0x40c, "fr-FR", ",", " ",
+		"janvier février mars avril mai juin juillet août septembre octobre novembre décembre",
+		"janv. févr. mars avr. mai juin juil. août sept. oct. nov. déc.",
+		"dimanche lundi mardi mercredi jeudi vendredi samedi",
+		"dim. lun. mar. mer. jeu. ven. sam.", "AM", "PM",
+		map[uint32]string{14: "dd/mm/yyyy", 15: "dd-mmm-yy", 16: "dd-mmm", 20: "hh:mm", 21: "hh:mm:ss", 22: "dd/mm/yyyy hh:mm"}),
+	newLocale(0xc0a, "es-ES", ",", ".",
+		"enero febrero marzo abril mayo junio julio agosto septiembre octubre noviembre diciembre",
+		"ene feb mar abr may jun jul ago sept oct nov dic",
+		"domingo lunes martes miércoles jueves viernes sábado",
+		"dom lun mar mié jue vie sáb", "a. m.", "p. m.",
+		map[uint32]string{14: "dd/mm/yyyy", 15: "dd-mmm-yy", 16: "dd-mmm", 22: "dd/mm/yyyy h:mm"}),
+	newLocale(0x410, "it-IT", ",", ".",
+		"gennaio febbraio marzo aprile maggio giugno luglio agosto settembre ottobre novembre dicembre",
+		"gen feb mar apr mag giu lug ago set ott nov dic",
+		"domenica lunedì martedì mercoledì giovedì venerdì sabato",
+		"dom lun mar mer gio ven sab", "AM", "PM",
+		map[uint32]string{14: "dd/mm/yyyy", 15: "dd-mmm-yy", 16: "dd-mmm", 20: "hh:mm", 21: "hh:mm:ss", 22: "dd/mm/yyyy hh:mm"}),
+	newLocale(0x413, "nl-NL", ",", ".",
+		"januari februari maart april mei juni juli augustus september oktober november december",
+		"jan feb mrt apr mei jun jul aug sep okt nov dec",
+		"zondag maandag dinsdag woensdag donderdag vrijdag zaterdag",
+		"zo ma di wo do vr za", "AM", "PM",
+		map[uint32]string{14: "d-m-yyyy", 15: "d-mmm-yy", 16: "d-mmm", 20: "hh:mm", 21: "hh:mm:ss", 22: "d-m-yyyy hh:mm"}),
+	newLocale(0x416, "pt-BR", ",", ".",
+		"janeiro fevereiro março abril maio junho julho agosto setembro outubro novembro dezembro",
+		"jan fev mar abr mai jun jul ago set out nov dez",
+		"domingo segunda-feira terça-feira quarta-feira quinta-feira sexta-feira sábado",
+		"dom seg ter qua qui sex sáb", "AM", "PM",
+		map[uint32]string{14: "dd/mm/yyyy", 15: "dd-mmm-yy", 16: "dd-mmm", 20: "hh:mm", 21: "hh:mm:ss", 22: "dd/mm/yyyy hh:mm"}),
+}
+
+// LocaleByLCID returns the locale of a Windows locale ID, or of the same
+// language in another region if there's none for the region.
+func LocaleByLCID(lcid uint32) (*Locale, bool) {
+	for _, l := range locales {
+		if l.LCID == lcid&0xffff {
+			return l, true
+		}
+	}
+	for _, l := range locales {
+		if l.LCID&0x3ff == lcid&0x3ff {
+			return l, true
+		}
+	}
+	return nil, false
+}
+
+// LocaleByName returns the locale of a language tag (e.g. de-DE), or of the
+// same language in another region if there's none for the region.
+func LocaleByName(name string) (*Locale, bool) {
+	name = strings.Replace(name, "_", "-", -1)
+	for _, l := range locales {
+		if strings.EqualFold(l.Name, name) {
+			return l, true
+		}
+	}
+	lang := strings.SplitN(name, "-", 2)[0]
+	for _, l := range locales {
+		if strings.EqualFold(strings.SplitN(l.Name, "-", 2)[0], lang) {
+			return l, true
+		}
+	}
+	return nil, false
+}
+
+// FormatCode returns the code of a built-in number format in the locale, or
+// false if the format doesn't depend on the locale.
+func (l *Locale) FormatCode(id uint32) (string, bool) {
+	f, ok := l.Formats[id]
+	return f, ok
+}
+
+// ParseNumber parses a number as written in the locale (e.g. 1.234,5 in
+// German), with an optional sign, thousands separators, exponent and percent
+// sign.  Negative numbers may also be in parentheses.
+func (l *Locale) ParseNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	neg := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		neg = true
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	percent := strings.HasSuffix(s, "%")
+	if percent {
+		s = strings.TrimSpace(s[:len(s)-1])
+	}
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		neg = neg != (s[0] == '-')
+		s = s[1:]
+	}
+	exp := ""
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		s, exp = s[:i], s[i+1:]
+		if _, err := strconv.Atoi(exp); err != nil {
+			return 0, false
+		}
+	}
+	whole, frac := s, ""
+	if i := strings.Index(s, l.DecimalSeparator); i >= 0 {
+		whole, frac = s[:i], s[i+len(l.DecimalSeparator):]
+	}
+	if whole, ok := l.ungroup(whole); !ok || !isDigits(frac) || whole == "" && frac == "" {
+		return 0, false
+	} else {
+		s = whole
+	}
+	if frac != "" {
+		s += "." + frac
+	}
+	if exp != "" {
+		s += "e" + exp
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	if percent {
+		v /= 100
+	}
+	if neg {
+		v = -v
+	}
+	return v, true
+}
+
+// ungroup removes the thousands separators of the integer part of a number,
+// which must separate groups of three digits.
+func (l *Locale) ungroup(s string) (string, bool) {
+	seps := []string{l.GroupSeparator}
+	if strings.TrimSpace(l.GroupSeparator) == "" {
+		// spaces of any width are typed as thousands separators
+		seps = []string{l.GroupSeparator, " ", " ", " "}
+	}
+	for _, sep := range seps {
+		if sep == "" || !strings.Contains(s, sep) {
+			continue
+		}
+		groups := strings.Split(s, sep)
+		for i, g := range groups {
+			if i == 0 && (len(g) == 0 || len(g) > 3) || i > 0 && len(g) != 3 {
+				return "", false
+			}
+		}
+		s = strings.Join(groups, "")
+	}
+	return s, isDigits(s)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseDate parses a date, a time or both as written in the locale (e.g.
+// 15.03.2023 14:30 or 15. März 2023 in German), returning its serial number,
+// which is days since 1900-01-00.  Numeric dates are read in the order of
+// the short date format of the locale, and dates without a year are in the
+// current year.  Numbers, such as 3,5 or -12%, aren't dates.
+func (l *Locale) ParseDate(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	if strings.ContainsAny(s, "+%") || strings.HasPrefix(s, "-") {
+		return 0, false
+	}
+	if _, ok := l.ParseNumber(s); ok {
+		return 0, false
+	}
+	if i := strings.IndexByte(s, ':'); i >= 0 {
+		start := strings.LastIndexAny(s[:i], " T") + 1
+		var ok bool
+		var t float64
+		if t, ok = l.parseTime(s[start:]); !ok {
+			return 0, false
+		}
+		s = strings.TrimSpace(strings.TrimRight(s[:start], "T"))
+		if s == "" {
+			return t, true
+		}
+		d, ok := l.parseDay(s)
+		return d + t, ok
+	}
+	return l.parseDay(s)
+}
+
+// parseTime parses a time (e.g. 2:30 PM or 14:30:15.5), returning it as the
+// fraction of a day.
+func (l *Locale) parseTime(s string) (float64, bool) {
+	lower := strings.ToLower(strings.TrimSpace(s))
+	pm := -1
+	for i, d := range []string{l.AM, l.PM, "am", "pm", "a", "p"} {
+		if d = strings.ToLower(d); d != "" && strings.HasSuffix(lower, d) {
+			pm = i % 2
+			lower = strings.TrimSpace(lower[:len(lower)-len(d)])
+			break
+		}
+	}
+	parts := strings.Split(lower, ":")
+	if len(parts) > 3 {
+		return 0, false
+	}
+	var hms [3]float64
+	for i, p := range parts {
+		if i == len(parts)-1 && i > 0 {
+			p = strings.Replace(p, l.DecimalSeparator, ".", 1)
+		}
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil || v < 0 || p == "" || p[0] < '0' || p[0] > '9' {
+			return 0, false
+		}
+		hms[i] = v
+	}
+	h := hms[0]
+	switch {
+	case pm >= 0 && (h < 1 || h > 12):
+		return 0, false
+	case pm == 0 && h == 12:
+		h = 0
+	case pm == 1 && h < 12:
+		h += 12
+	}
+	if hms[1] >= 60 || hms[2] >= 60 {
+		return 0, false
+	}
+	return (h*3600 + hms[1]*60 + hms[2]) / 86400, true
+}
+
+// parseDay parses a date without a time, returning its serial number.
+func (l *Locale) parseDay(s string) (float64, bool) {
+	nums, month := []string{}, 0
+	for _, w := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.'
+	}) {
+		w = strings.Trim(w, ".")
+		switch {
+		case w == "":
+		case isDigits(w):
+			nums = append(nums, w)
+		case strings.Contains(w, "."):
+			// dates like 15.03.2023, in locales which write them so
+			if !strings.Contains(l.Formats[14], ".") {
+				return 0, false
+			}
+			for _, p := range strings.Split(w, ".") {
+				if !isDigits(p) || p == "" {
+					return 0, false
+				}
+				nums = append(nums, p)
+			}
+		default:
+			m := l.monthNumber(w)
+			if m == 0 || month != 0 {
+				return 0, false
+			}
+			month = m
+		}
+	}
+	year, day := time.Now().Year(), 0
+	atoi := func(s string) int { n, _ := strconv.Atoi(s); return n }
+	order := l.dateOrder()
+	switch {
+	case month != 0 && len(nums) == 1:
+		day = atoi(nums[0])
+	case month != 0 && len(nums) == 2 && len(nums[0]) > 2:
+		year, day = atoi(nums[0]), atoi(nums[1])
+	case month != 0 && len(nums) == 2:
+		day, year = atoi(nums[0]), twoDigitYear(nums[1])
+	case len(nums) == 3 && len(nums[0]) > 2:
+		year, month, day = atoi(nums[0]), atoi(nums[1]), atoi(nums[2])
+	case len(nums) == 3:
+		for i, c := range order {
+			switch c {
+			case 'd':
+				day = atoi(nums[i])
+			case 'm':
+				month = atoi(nums[i])
+			case 'y':
+				year = twoDigitYear(nums[i])
+			}
+		}
+	case len(nums) == 2 && atoi(nums[1]) > 31:
+		// a month of a year, e.g. 3/2023
+		month, day, year = atoi(nums[0]), 1, twoDigitYear(nums[1])
+	case len(nums) == 2:
+		md := strings.Replace(order, "y", "", 1)
+		for i, c := range md {
+			if c == 'd' {
+				day = atoi(nums[i])
+			} else {
+				month = atoi(nums[i])
+			}
+		}
+	default:
+		return 0, false
+	}
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if month < 1 || month > 12 || t.Day() != day || year < 1900 || year > 9999 {
+		return 0, false
+	}
+	return serialNumber(t), true
+}
+
+// monthNumber returns the number of a month name or its abbreviation in the
+// locale or in English, or 0 if it isn't one.
+func (l *Locale) monthNumber(w string) int {
+	for _, loc := range []*Locale{l, EnglishUS} {
+		for i := range loc.MonthNames {
+			if strings.EqualFold(w, loc.MonthNames[i]) || strings.EqualFold(w, strings.TrimRight(loc.ShortMonthNames[i], ".")) {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// dateOrder returns the order of the day, the month and the year in the short
+// date format of the locale (e.g. dmy).
+func (l *Locale) dateOrder() string {
+	f, ok := l.Formats[14]
+	if !ok {
+		f = "m/d/yyyy"
+	}
+	order := ""
+	for _, c := range strings.ToLower(f) {
+		if strings.ContainsRune("dmy", c) && !strings.ContainsRune(order, c) {
+			order += string(c)
+		}
+	}
+	if len(order) != 3 {
+		return "mdy"
+	}
+	return order
+}
+
+// twoDigitYear returns a year, where two digit years are from 1930 to 2029.
+func twoDigitYear(s string) int {
+	y, _ := strconv.Atoi(s)
+	switch {
+	case len(s) > 2:
+		return y
+	case y < 30:
+		return 2000 + y
+	}
+	return 1900 + y
+}
+
+// serialNumber returns the serial number of a date, the inverse of
+// serialDate.
+func serialNumber(t time.Time) float64 {
+	base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	days := math.Floor(t.Sub(base).Hours()/24 + 0.5)
+	if days < 61 {
+		days--
+	}
+	return days
+}
@@ -0,0 +1,170 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package format
+
+import "testing"
+
+func locale(t *testing.T, name string) *Locale {
+	t.Helper()
+	l, ok := LocaleByName(name)
+	if !ok {
+		t.Fatalf("expected a locale for %s", name)
+	}
+	return l
+}
+
+func TestLocaleLookup(t *testing.T) {
+	for lcid, exp := range map[uint32]string{
+		0x409: "en-US", 0x809: "en-GB", 0x407: "de-DE", 0x40c: "fr-FR",
+		// other regions fall back to the language, ignoring the sort ID
+		0x807: "de-DE", 0x1009: "en-US", 0x10407: "de-DE",
+	} {
+		if l, ok := LocaleByLCID(lcid); !ok || l.Name != exp {
+			t.Errorf("expected the locale %s for %#x, got %v", exp, lcid, l)
+		}
+	}
+	if l, ok := LocaleByLCID(0x411); ok {
+		t.Errorf("expected no locale for %#x, got %s", 0x411, l.Name)
+	}
+	for name, exp := range map[string]string{
+		"de-DE": "de-DE", "DE-de": "de-DE", "fr_FR": "fr-FR", "de": "de-DE",
+		"de-AT": "de-DE", "pt-PT": "pt-BR", "es": "es-ES",
+	} {
+		if l, ok := LocaleByName(name); !ok || l.Name != exp {
+			t.Errorf("expected the locale %s for %s, got %v", exp, name, l)
+		}
+	}
+	if l, ok := LocaleByName("ja-JP"); ok {
+		t.Errorf("expected no locale for ja-JP, got %s", l.Name)
+	}
+}
+
+func TestLocaleParseNumber(t *testing.T) {
+	for _, tc := range []struct {
+		locale string
+		s      string
+		exp    float64
+		ok     bool
+	}{
+		{"en-US", "1,234.5", 1234.5, true},
+		{"en-US", "1.23", 1.23, true},
+		{"en-US", " (12) ", -12, true},
+		{"en-US", "-3%", -0.03, true},
+		{"en-US", "1e3", 1000, true},
+		{"en-US", "1.234,5", 0, false},
+		{"en-US", "1,5E2", 0, false},
+		{"de-DE", "1.234,5", 1234.5, true},
+		{"de-DE", "12,34", 12.34, true},
+		{"de-DE", "1,5E2", 150, true},
+		{"de-DE", "1,234.5", 0, false},
+		{"de-DE", "1.23", 0, false},
+		// any space separates thousands where the separator is a space
+		{"fr-FR", "1\u00a0234,5", 1234.5, true},
+		{"fr-FR", "1 234,5", 1234.5, true},
+		{"fr-FR", "1\u202f234,5", 1234.5, true},
+		{"fr-FR", "12 34,5", 0, false},
+		{"en-US", "", 0, false},
+		{"en-US", "abc", 0, false},
+		{"en-US", "1.2.3", 0, false},
+		{"en-US", "1e", 0, false},
+	} {
+		got, ok := locale(t, tc.locale).ParseNumber(tc.s)
+		if ok != tc.ok || got != tc.exp {
+			t.Errorf("%s: expected %q to be %v, %v, got %v, %v", tc.locale, tc.s, tc.exp, tc.ok, got, ok)
+		}
+	}
+}
+
+func TestLocaleParseDate(t *testing.T) {
+	const (
+		day  = 45000 // 2023-03-15
+		time = 14.5 / 24
+	)
+	for _, tc := range []struct {
+		locale string
+		s      string
+		exp    float64
+		ok     bool
+	}{
+		{"en-US", "3/15/2023", day, true},
+		{"en-US", "March 15, 2023", day, true},
+		{"en-US", "15-mar-2023", day, true},
+		{"en-US", "2023-03-15", day, true},
+		{"en-US", "14:30", time, true},
+		{"en-US", "2:30 PM", time, true},
+		{"en-US", "15/03/2023", 0, false},
+		{"en-US", "15.03.2023", 0, false},
+		{"en-GB", "15/03/2023", day, true},
+		{"en-GB", "3/15/2023", 0, false},
+		{"de-DE", "15.03.2023", day, true},
+		{"de-DE", "15. März 2023", day, true},
+		{"de-DE", "15.03.2023 14:30", day + time, true},
+		{"de-DE", "2023-03-15", day, true},
+		{"fr-FR", "15/03/2023", day, true},
+		{"fr-FR", "15 mars 2023", day, true},
+		// numbers aren't dates
+		{"en-US", "1.23", 0, false},
+		{"de-DE", "12,34", 0, false},
+		{"en-US", "-3/15/2023", 0, false},
+		{"en-US", "abc", 0, false},
+	} {
+		got, ok := locale(t, tc.locale).ParseDate(tc.s)
+		if ok != tc.ok || ok && got != tc.exp {
+			t.Errorf("%s: expected %q to be %v, %v, got %v, %v", tc.locale, tc.s, tc.exp, tc.ok, got, ok)
+		}
+	}
+}
+
+func TestLocaleNumber(t *testing.T) {
+	for _, tc := range []struct {
+		locale, f string
+		v         float64
+		exp       string
+	}{
+		{"en-US", "#,##0.00", 1234567.891, "1,234,567.89"},
+		{"de-DE", "#,##0.00", 1234567.891, "1.234.567,89"},
+		{"fr-FR", "#,##0.00", 1234567.891, "1\u00a0234\u00a0567,89"},
+		{"de-DE", "0.0%", 0.125, "12,5%"},
+		{"en-US", "dddd d mmmm yyyy", 45000, "Wednesday 15 March 2023"},
+		{"de-DE", "dddd d mmmm yyyy", 45000, "Mittwoch 15 März 2023"},
+		{"fr-FR", "ddd d mmm", 45000, "mer. 15 mars"},
+		{"es-ES", "h:mm AM/PM", 0.75, "6:00 p. m."},
+	} {
+		if got := locale(t, tc.locale).Number(tc.v, tc.f); got != tc.exp {
+			t.Errorf("%s: expected %v formatted with %s to be %q, got %q", tc.locale, tc.v, tc.f, tc.exp, got)
+		}
+	}
+	// a locale in the format selects the names, but not the separators
+	if got := Number(45000, "[$-407]dddd, d. mmmm yyyy"); got != "Mittwoch, 15. März 2023" {
+		t.Errorf("expected the date in German, got %q", got)
+	}
+	if got := Number(1234.5, "#,##0.00 [$€-407]"); got != "1,234.50 €" {
+		t.Errorf("expected the number with a euro sign, got %q", got)
+	}
+}
+
+func TestLocaleFormatCode(t *testing.T) {
+	for _, tc := range []struct {
+		locale string
+		id     uint32
+		exp    string
+		ok     bool
+	}{
+		{"en-US", 14, "m/d/yyyy", true},
+		{"en-GB", 14, "dd/mm/yyyy", true},
+		{"de-DE", 14, "dd.mm.yyyy", true},
+		{"de-DE", 22, "dd.mm.yyyy hh:mm", true},
+		{"nl-NL", 15, "d-mmm-yy", true},
+		{"de-DE", 4, "", false},
+	} {
+		got, ok := locale(t, tc.locale).FormatCode(tc.id)
+		if got != tc.exp || ok != tc.ok {
+			t.Errorf("%s: expected the format %d to be %q, %v, got %q, %v", tc.locale, tc.id, tc.exp, tc.ok, got, ok)
+		}
+	}
+}
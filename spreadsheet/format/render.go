@@ -0,0 +1,1116 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package format
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/unidoc/unioffice/color"
+)
+
+// Result is a value formatted with a number format.
+type Result struct {
+	// Text is the value as Excel displays it.
+	Text string
+	// Color is the color selected by the format (e.g. with [Red]), or
+	// color.Auto if the format doesn't select one.
+	Color color.Color
+	// Fill is the character that a * in the format repeats to fill the width
+	// of the cell, which goes at the byte offset FillIndex of Text.  It is zero
+	// if the format has none.
+	Fill      rune
+	FillIndex int
+}
+
+// Pad returns the text with the fill character repeated so that it is width
+// characters long.
+func (r Result) Pad(width int) string {
+	n := width - utf8.RuneCountInString(r.Text)
+	if r.Fill == 0 || n <= 0 {
+		return r.Text
+	}
+	return r.Text[:r.FillIndex] + strings.Repeat(string(r.Fill), n) + r.Text[r.FillIndex:]
+}
+
+// NumberResult formats a number with a format string as Number does, also
+// returning the color selected by the format.
+func NumberResult(v float64, f string) Result {
+	return EnglishUS.NumberResult(v, f)
+}
+
+// NumberResult formats a number with a format string using the separators
+// and the names of the locale, also returning the color selected by the
+// format.
+func (l *Locale) NumberResult(v float64, f string) Result {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return Result{Text: "#NUM!", Color: color.Auto}
+	}
+	if isGeneral(f) {
+		return Result{Text: l.general(v), Color: color.Auto}
+	}
+	s, v := numberSection(parseSections(f), v)
+	if s == nil {
+		return Result{Text: l.general(v), Color: color.Auto}
+	}
+	return newResult(s.number(v, l), s.color)
+}
+
+// Number formats a number with a format string as Number does, using the
+// separators and the names of the locale.
+func (l *Locale) Number(v float64, f string) string {
+	return l.NumberResult(v, f).Text
+}
+
+// StringResult formats a string with a format string as String does, also
+// returning the color selected by the format.
+func StringResult(v string, f string) Result {
+	s := textSection(parseSections(f))
+	if s == nil {
+		return Result{Text: v, Color: color.Auto}
+	}
+	b := strings.Builder{}
+	for _, t := range s.tokens {
+		if t.kind == tokText {
+			b.WriteString(v)
+		} else {
+			b.WriteString(t.literal())
+		}
+	}
+	return newResult(b.String(), s.color)
+}
+
+// ValueResult formats a value as a number or string as Value does, also
+// returning the color selected by the format.
+func ValueResult(v string, f string) Result {
+	return EnglishUS.ValueResult(v, f)
+}
+
+// ValueResult formats a value as a number or string as Value does using the
+// locale, also returning the color selected by the format.
+func (l *Locale) ValueResult(v string, f string) Result {
+	if IsNumber(v) {
+		n, _ := strconv.ParseFloat(v, 64)
+		return l.NumberResult(n, f)
+	}
+	return StringResult(v, f)
+}
+
+// Value formats a value as a number or string as Value does using the locale.
+func (l *Locale) Value(v string, f string) string {
+	return l.ValueResult(v, f).Text
+}
+
+// general formats a number with the General format.
+func (l *Locale) general(v float64) string {
+	return strings.Replace(NumberGeneric(v), ".", l.DecimalSeparator, 1)
+}
+
+func isGeneral(f string) bool {
+	return f == "" || f == "@" || strings.EqualFold(f, "General")
+}
+
+// IsDate returns true if a format displays positive numbers as dates or
+// times.
+func IsDate(f string) bool {
+	if isGeneral(f) {
+		return false
+	}
+	s, _ := numberSection(parseSections(f), 1)
+	return s != nil && s.date
+}
+
+// fillMark marks the place of the fill character in formatted text.
+const fillMark = '\x00'
+
+func newResult(text string, c color.Color) Result {
+	r := Result{Text: text, Color: c}
+	i := strings.IndexByte(text, fillMark)
+	if i < 0 {
+		return r
+	}
+	b := strings.Builder{}
+	for j := 0; j < len(text); {
+		if text[j] != fillMark {
+			b.WriteByte(text[j])
+			j++
+			continue
+		}
+		f, n := utf8.DecodeRuneInString(text[j+1:])
+		if j == i {
+			r.Fill, r.FillIndex = f, b.Len()
+		}
+		j += 1 + n
+	}
+	r.Text = b.String()
+	return r
+}
+
+type tokenKind byte
+
+const (
+	tokLiteral   tokenKind = iota
+	tokDigit               // 0, # or ?
+	tokDecimal             // .
+	tokComma               // , before classifying it
+	tokPercent             // %
+	tokExponent            // E+, E-, e+ or e-
+	tokSlash               // /
+	tokText                // @
+	tokGeneral             // General
+	tokFill                // *x
+	tokDate                // runs of y, m, d, h, n (minutes) and s, e.g. yyyy
+	tokSubsecond           // .0, .00 or .000 after seconds
+	tokAmPm                // AM/PM or A/P
+	tokElapsed             // [h], [mm], [ss]...
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// literal returns the text of a token outside of the numbers and dates.
+func (t token) literal() string {
+	switch t.kind {
+	case tokLiteral, tokPercent, tokSlash, tokDecimal, tokComma, tokDigit:
+		return t.text
+	case tokFill:
+		return string(fillMark) + t.text
+	}
+	return ""
+}
+
+// condition is the condition of a section, e.g. [>1000].
+type condition struct {
+	op    string
+	value float64
+}
+
+func parseCondition(c string) *condition {
+	i := 0
+	for i < len(c) && strings.IndexByte("<>=", c[i]) >= 0 {
+		i++
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(c[i:]), 64)
+	if err != nil {
+		return nil
+	}
+	return &condition{c[:i], v}
+}
+
+func (c *condition) matches(v float64) bool {
+	switch c.op {
+	case "<":
+		return v < c.value
+	case "<=", "=<":
+		return v <= c.value
+	case ">":
+		return v > c.value
+	case ">=", "=>":
+		return v >= c.value
+	case "<>":
+		return v != c.value
+	}
+	return v == c.value
+}
+
+// negative returns true if only negative numbers match the condition, in
+// which case they are formatted without their sign as in the second section
+// of formats without conditions.
+func (c *condition) negative() bool {
+	return c.op == "<" && c.value <= 0 || (c.op == "<=" || c.op == "=<") && c.value < 0
+}
+
+// section is one of the up to four sections of a number format, separated by
+// semicolons, which are for positive numbers, negative numbers, zero and text.
+type section struct {
+	tokens    []token
+	color     color.Color
+	cond      *condition
+	locale    *Locale
+	date      bool
+	text      bool
+	general   bool
+	thousands bool
+	hours12   bool
+	percent   int
+	scale     int
+}
+
+// parseSections parses the sections of a number format.
+func parseSections(f string) []*section {
+	sections := []*section{}
+	s := &section{color: color.Auto}
+	rs := []rune(f)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r == ';':
+			sections = append(sections, s.finish())
+			s = &section{color: color.Auto}
+		case r == '"':
+			j := i + 1
+			for j < len(rs) && rs[j] != '"' {
+				j++
+			}
+			s.add(tokLiteral, string(rs[i+1:min(j, len(rs))]))
+			i = j
+		case r == '\\':
+			if i+1 < len(rs) {
+				i++
+				s.add(tokLiteral, string(rs[i]))
+			}
+		case r == '_':
+			// a space as wide as the next character
+			i++
+			s.add(tokLiteral, " ")
+		case r == '*':
+			if i+1 < len(rs) {
+				i++
+				s.add(tokFill, string(rs[i]))
+			}
+		case r == '[':
+			j := i + 1
+			for j < len(rs) && rs[j] != ']' {
+				j++
+			}
+			s.bracket(string(rs[i+1 : min(j, len(rs))]))
+			i = j
+		case r == '0' || r == '#' || r == '?':
+			s.add(tokDigit, string(r))
+		case r == '.':
+			j := i + 1
+			for j < len(rs) && rs[j] == '0' {
+				j++
+			}
+			if j > i+1 && s.lastDate() == 's' {
+				s.add(tokSubsecond, string(rs[i:j]))
+				i = j - 1
+			} else {
+				s.add(tokDecimal, ".")
+			}
+		case r == ',':
+			s.add(tokComma, ",")
+		case r == '%':
+			s.add(tokPercent, "%")
+		case (r == 'E' || r == 'e') && i+1 < len(rs) && (rs[i+1] == '+' || rs[i+1] == '-'):
+			s.add(tokExponent, string(rs[i:i+2]))
+			i++
+		case r == '/':
+			s.add(tokSlash, "/")
+		case r == '@':
+			s.add(tokText, "@")
+		case hasPrefixFold(rs[i:], "general"):
+			s.add(tokGeneral, "General")
+			i += len("general") - 1
+		case hasPrefixFold(rs[i:], "am/pm"):
+			s.add(tokAmPm, string(rs[i:i+5]))
+			i += 4
+		case hasPrefixFold(rs[i:], "a/p"):
+			s.add(tokAmPm, string(rs[i:i+3]))
+			i += 2
+		case strings.ContainsRune("yYmMdDhHsSeE", r):
+			c := unicode.ToLower(r)
+			j := i
+			for j < len(rs) && unicode.ToLower(rs[j]) == c {
+				j++
+			}
+			if c == 'e' {
+				s.add(tokDate, "yyyy")
+			} else {
+				s.add(tokDate, strings.Repeat(string(c), j-i))
+			}
+			i = j - 1
+		case (r == 'B' || r == 'b') && i+1 < len(rs) && (rs[i+1] == '1' || rs[i+1] == '2'):
+			// calendars
+			i++
+		default:
+			s.add(tokLiteral, string(r))
+		}
+	}
+	return append(sections, s.finish())
+}
+
+func hasPrefixFold(rs []rune, prefix string) bool {
+	return len(rs) >= len(prefix) && strings.EqualFold(string(rs[:len(prefix)]), prefix)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func (s *section) add(kind tokenKind, text string) {
+	s.tokens = append(s.tokens, token{kind, text})
+}
+
+// lastDate returns the letter of the last date or time token.
+func (s *section) lastDate() byte {
+	for i := len(s.tokens) - 1; i >= 0; i-- {
+		if t := s.tokens[i]; t.kind == tokDate || t.kind == tokElapsed {
+			return t.text[0]
+		}
+	}
+	return 0
+}
+
+// bracket handles the text between brackets, which is a color, a condition,
+// a currency or an elapsed time.
+func (s *section) bracket(c string) {
+	lc := strings.ToLower(c)
+	switch {
+	case c == "":
+	case strings.IndexByte("<>=", c[0]) >= 0:
+		s.cond = parseCondition(c)
+	case c[0] == '$':
+		// currency symbols and locales, e.g. [$€-407]
+		sym := c[1:]
+		if i := strings.IndexByte(sym, '-'); i >= 0 {
+			if lcid, err := strconv.ParseUint(sym[i+1:], 16, 32); err == nil {
+				s.locale, _ = LocaleByLCID(uint32(lcid))
+			}
+			sym = sym[:i]
+		}
+		if sym != "" {
+			s.add(tokLiteral, sym)
+		}
+	case strings.Trim(lc, "h") == "" || strings.Trim(lc, "m") == "" || strings.Trim(lc, "s") == "":
+		s.add(tokElapsed, lc)
+	default:
+		if col, ok := namedColor(lc); ok {
+			s.color = col
+		}
+	}
+}
+
+// finish classifies the tokens of a parsed section.
+func (s *section) finish() *section {
+	for _, t := range s.tokens {
+		switch t.kind {
+		case tokDate, tokElapsed, tokSubsecond:
+			s.date = true
+		case tokAmPm:
+			s.date = true
+			s.hours12 = true
+		case tokText:
+			s.text = true
+		case tokGeneral:
+			s.general = true
+		case tokPercent:
+			s.percent++
+		}
+	}
+	if s.date {
+		s.minutes()
+	} else {
+		s.commas()
+	}
+	return s
+}
+
+// minutes marks the m and mm that follow hours or precede seconds as minutes.
+func (s *section) minutes() {
+	for i, t := range s.tokens {
+		if t.kind != tokDate || t.text != "m" && t.text != "mm" {
+			continue
+		}
+		if s.dateAround(i, -1) == 'h' || s.dateAround(i, 1) == 's' {
+			s.tokens[i].text = strings.Repeat("n", len(t.text))
+		}
+	}
+}
+
+// dateAround returns the letter of the closest date or time token before
+// (dir -1) or after (dir 1) the token i.
+func (s *section) dateAround(i, dir int) byte {
+	for j := i + dir; j >= 0 && j < len(s.tokens); j += dir {
+		if t := s.tokens[j]; t.kind == tokDate || t.kind == tokElapsed {
+			return t.text[0]
+		}
+	}
+	return 0
+}
+
+// commas classifies the commas of a number section, which are thousands
+// separators between digits and divide the number by 1000 after the digits.
+func (s *section) commas() {
+	last := -1
+	for i, t := range s.tokens {
+		if t.kind == tokDigit {
+			last = i
+		}
+	}
+	digitBefore := false
+	for i, t := range s.tokens {
+		switch {
+		case t.kind == tokDigit:
+			digitBefore = true
+		case t.kind != tokComma:
+		case i > last && last >= 0 && s.onlyCommas(last+1, i):
+			s.scale++
+			s.tokens[i] = token{tokLiteral, ""}
+		case digitBefore && i < last:
+			s.thousands = true
+			s.tokens[i] = token{tokLiteral, ""}
+		default:
+			s.tokens[i].kind = tokLiteral
+		}
+	}
+}
+
+func (s *section) onlyCommas(from, to int) bool {
+	for _, t := range s.tokens[from:to] {
+		if t.kind != tokComma && !(t.kind == tokLiteral && t.text == "") {
+			return false
+		}
+	}
+	return true
+}
+
+// numberSection returns the section of a format for a number, which is
+// returned without its sign if the section is for negative numbers.
+func numberSection(sections []*section, v float64) (*section, float64) {
+	n := len(sections)
+	if n == 4 || n > 1 && sections[n-1].text {
+		n--
+	}
+	if n > 3 {
+		n = 3
+	}
+	if n == 1 && sections[0].text {
+		return nil, v
+	}
+	num := sections[:n]
+	if num[0].cond != nil || n > 1 && num[1].cond != nil {
+		i := 0
+		switch {
+		case num[0].cond != nil && num[0].cond.matches(v):
+		case n > 1 && num[1].cond != nil && num[1].cond.matches(v):
+			i = 1
+		case n > 1 && num[1].cond == nil:
+			i = 1
+		case n > 2:
+			i = 2
+		}
+		if c := num[i].cond; c != nil && c.negative() {
+			v = math.Abs(v)
+		}
+		return num[i], v
+	}
+	switch {
+	case n == 1 || v > 0 || v == 0 && n == 2:
+		return num[0], v
+	case v < 0:
+		return num[1], -v
+	}
+	return num[2], v
+}
+
+// textSection returns the section of a format for text.
+func textSection(sections []*section) *section {
+	switch n := len(sections); {
+	case n >= 4:
+		return sections[3]
+	case sections[n-1].text:
+		return sections[n-1]
+	}
+	return nil
+}
+
+// number formats a number with the section.
+func (s *section) number(v float64, l *Locale) string {
+	if s.date {
+		return s.dateTime(v, l)
+	}
+	x := math.Abs(v) * math.Pow10(2*s.percent-3*s.scale)
+	var text string
+	switch {
+	case s.general:
+		b := strings.Builder{}
+		for _, t := range s.tokens {
+			if t.kind == tokGeneral {
+				b.WriteString(l.general(x))
+			} else {
+				b.WriteString(t.literal())
+			}
+		}
+		text = b.String()
+	case s.index(tokExponent) >= 0:
+		text = s.exponent(x, l)
+	case s.fraction() >= 0:
+		text = s.fractionText(x, l)
+	default:
+		text = s.decimal(x, l)
+	}
+	if v < 0 {
+		return "-" + text
+	}
+	return text
+}
+
+func (s *section) index(kind tokenKind) int {
+	for i, t := range s.tokens {
+		if t.kind == kind {
+			return i
+		}
+	}
+	return -1
+}
+
+// decimal formats a number with the digits around a decimal point.
+func (s *section) decimal(x float64, l *Locale) string {
+	toks := s.tokens
+	point := s.index(tokDecimal)
+	if point < 0 {
+		ip, _ := decimalDigits(x, 0)
+		return formatInteger(toks, ip, s.group(l))
+	}
+	ip, fp := decimalDigits(x, countDigits(toks[point+1:]))
+	return formatInteger(toks[:point], ip, s.group(l)) + l.DecimalSeparator + formatDecimals(toks[point+1:], fp)
+}
+
+// group returns the thousands separator of the section, or an empty string
+// if it has none.
+func (s *section) group(l *Locale) string {
+	if s.thousands {
+		return l.GroupSeparator
+	}
+	return ""
+}
+
+// exponent formats a number in the scientific notation, where the exponent
+// is a multiple of the number of integer digits if they include a # (e.g.
+// ##0.0E+0).
+func (s *section) exponent(x float64, l *Locale) string {
+	e := s.index(tokExponent)
+	mant, exps := s.tokens[:e], s.tokens[e+1:]
+	point := len(mant)
+	for i, t := range mant {
+		if t.kind == tokDecimal {
+			point = i
+			break
+		}
+	}
+	intToks, fracToks := mant[:point], []token(nil)
+	if point < len(mant) {
+		fracToks = mant[point+1:]
+	}
+	digits, decimals := countDigits(intToks), countDigits(fracToks)
+	step := 1
+	for _, t := range intToks {
+		if t.kind == tokDigit && t.text == "#" && digits > 1 {
+			step = digits
+		}
+	}
+	exp := 0
+	if x != 0 {
+		e10 := decimalExponent(x)
+		switch {
+		case step > 1:
+			exp = floorDiv(e10, step) * step
+		case digits == 0:
+			exp = e10 + 1
+		default:
+			exp = e10 - digits + 1
+		}
+	}
+	ip, fp := decimalDigits(x*math.Pow10(-exp), decimals)
+	if digits == 0 && ip != "" || digits > 0 && len(ip) > digits {
+		exp += step
+		ip, fp = decimalDigits(x*math.Pow10(-exp), decimals)
+	}
+	b := strings.Builder{}
+	b.WriteString(formatInteger(intToks, ip, s.group(l)))
+	if point < len(mant) {
+		b.WriteString(l.DecimalSeparator)
+		b.WriteString(formatDecimals(fracToks, fp))
+	}
+	b.WriteByte(s.tokens[e].text[0])
+	switch {
+	case exp < 0:
+		b.WriteByte('-')
+		exp = -exp
+	case s.tokens[e].text[1] == '+':
+		b.WriteByte('+')
+	}
+	b.WriteString(formatInteger(exps, strconv.Itoa(exp), ""))
+	return b.String()
+}
+
+// fraction returns the index of the slash of a fraction (e.g. # ?/?), or -1.
+func (s *section) fraction() int {
+	for i, t := range s.tokens {
+		if t.kind == tokSlash && i > 0 && s.tokens[i-1].kind == tokDigit && i+1 < len(s.tokens) &&
+			(s.tokens[i+1].kind == tokDigit || isDigitLiteral(s.tokens[i+1])) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isDigitLiteral(t token) bool {
+	return t.kind == tokLiteral && len(t.text) == 1 && t.text[0] >= '0' && t.text[0] <= '9'
+}
+
+// fractionText formats a number as a fraction, either with a fixed
+// denominator (e.g. ?/8) or the closest one with as many digits as the
+// format has (e.g. ??/??).  The integer part is only separate if the format
+// has one (e.g. # ?/?).
+func (s *section) fractionText(x float64, l *Locale) string {
+	toks := s.tokens
+	slash := s.fraction()
+	numStart := slash
+	for numStart > 0 && toks[numStart-1].kind == tokDigit {
+		numStart--
+	}
+	denEnd := slash + 1
+	fixed := ""
+	for denEnd < len(toks) && (toks[denEnd].kind == tokDigit || isDigitLiteral(toks[denEnd])) {
+		if isDigitLiteral(toks[denEnd]) {
+			fixed += toks[denEnd].text
+		} else {
+			fixed += "0"
+		}
+		denEnd++
+	}
+	intToks, numToks, denToks := toks[:numStart], toks[numStart:slash], toks[slash+1:denEnd]
+	hasInt := countDigits(intToks) > 0
+
+	whole, f := 0.0, x
+	if hasInt {
+		whole = math.Floor(x)
+		f = x - whole
+	}
+	var num, den int64
+	if strings.Trim(fixed, "0") != "" {
+		den, _ = strconv.ParseInt(fixed, 10, 64)
+		num = int64(math.Floor(f*float64(den) + 0.5))
+	} else {
+		num, den = approximate(f, int64(math.Pow10(len(denToks)))-1)
+	}
+	if hasInt && num >= den {
+		whole++
+		num -= den
+	}
+
+	b := strings.Builder{}
+	if hasInt {
+		ip := ""
+		if whole > 0 || num == 0 {
+			ip = strconv.FormatFloat(whole, 'f', 0, 64)
+		}
+		b.WriteString(formatInteger(intToks, ip, s.group(l)))
+	} else {
+		for _, t := range intToks {
+			b.WriteString(t.literal())
+		}
+	}
+	frac := formatInteger(numToks, strconv.FormatInt(num, 10), "") + "/"
+	if fixed != "" && strings.Trim(fixed, "0") != "" {
+		frac += fixed
+	} else {
+		frac += formatDenominator(denToks, strconv.FormatInt(den, 10))
+	}
+	if hasInt && num == 0 {
+		frac = strings.Repeat(" ", utf8.RuneCountInString(frac))
+	}
+	b.WriteString(frac)
+	for _, t := range toks[denEnd:] {
+		b.WriteString(t.literal())
+	}
+	return b.String()
+}
+
+// approximate returns the fraction closest to f with a denominator up to
+// maxDen.
+func approximate(f float64, maxDen int64) (int64, int64) {
+	if maxDen < 1 {
+		maxDen = 1
+	}
+	num, den := int64(math.Floor(f+0.5)), int64(1)
+	best := math.Abs(f - float64(num))
+	for d := int64(2); d <= maxDen && best > 1e-12; d++ {
+		n := math.Floor(f*float64(d) + 0.5)
+		if e := math.Abs(f - n/float64(d)); e < best-1e-12 {
+			num, den, best = int64(n), d, e
+		}
+	}
+	return num, den
+}
+
+func countDigits(toks []token) int {
+	n := 0
+	for _, t := range toks {
+		if t.kind == tokDigit {
+			n++
+		}
+	}
+	return n
+}
+
+// formatInteger formats the integer digits (without leading zeros) with the
+// placeholders of toks, filling them from the right and separating groups of
+// three digits with group if it isn't empty.  The leftmost placeholder takes
+// all of the remaining digits.
+func formatInteger(toks []token, digits string, group string) string {
+	first := -1
+	for i, t := range toks {
+		if t.kind == tokDigit {
+			first = i
+			break
+		}
+	}
+	out := []string{}
+	count := 0
+	sep := false
+	digit := func(c byte) {
+		if sep {
+			out = append(out, group)
+		}
+		out = append(out, string(c))
+		count++
+		sep = group != "" && count%3 == 0
+	}
+	d := len(digits)
+	for i := len(toks) - 1; i >= 0; i-- {
+		t := toks[i]
+		if t.kind != tokDigit {
+			out = append(out, t.literal())
+			continue
+		}
+		switch {
+		case d > 0:
+			d--
+			digit(digits[d])
+		case t.text == "0":
+			digit('0')
+		case t.text == "?":
+			out = append(out, " ")
+			sep = false
+		}
+		for i == first && d > 0 {
+			d--
+			digit(digits[d])
+		}
+	}
+	b := strings.Builder{}
+	for i := len(out) - 1; i >= 0; i-- {
+		b.WriteString(out[i])
+	}
+	return b.String()
+}
+
+// formatDecimals formats the digits after the decimal point with the
+// placeholders of toks, where # hides and ? blanks trailing zeros.
+func formatDecimals(toks []token, digits string) string {
+	keep := len(digits)
+	for i := len(toks) - 1; i >= 0 && keep > 0; i-- {
+		if toks[i].kind != tokDigit {
+			continue
+		}
+		if digits[keep-1] != '0' || toks[i].text == "0" {
+			break
+		}
+		keep--
+	}
+	b := strings.Builder{}
+	d := 0
+	for _, t := range toks {
+		if t.kind != tokDigit {
+			b.WriteString(t.literal())
+			continue
+		}
+		switch {
+		case d < keep:
+			b.WriteByte(digits[d])
+		case t.text == "?":
+			b.WriteByte(' ')
+		}
+		d++
+	}
+	return b.String()
+}
+
+// formatDenominator formats the denominator of a fraction, which unlike the
+// numerator is aligned to the left.
+func formatDenominator(toks []token, digits string) string {
+	lead, trail := "", ""
+	for i := len(digits); i < len(toks); i++ {
+		switch toks[i].text {
+		case "0":
+			lead += "0"
+		case "?":
+			trail += " "
+		}
+	}
+	return lead + digits + trail
+}
+
+// decimalDigits returns the integer digits (without leading zeros) and the
+// given number of decimals of a number, rounded half away from zero after
+// the 15 significant digits that Excel keeps.
+func decimalDigits(x float64, decimals int) (string, string) {
+	s := strconv.FormatFloat(x, 'e', 14, 64)
+	e := strings.IndexByte(s, 'e')
+	exp, _ := strconv.Atoi(s[e+1:])
+	digits := s[:1] + s[2:e]
+	point := exp + 1
+	if point < 0 {
+		digits = strings.Repeat("0", -point) + digits
+		point = 0
+	}
+	if point > len(digits) {
+		digits += strings.Repeat("0", point-len(digits))
+	}
+	n := point + decimals
+	if n < len(digits) {
+		up := digits[n] >= '5'
+		digits = digits[:n]
+		if up {
+			var carry bool
+			digits, carry = increment(digits)
+			if carry {
+				point++
+			}
+		}
+	} else {
+		digits += strings.Repeat("0", n-len(digits))
+	}
+	return strings.TrimLeft(digits[:point], "0"), digits[point:]
+}
+
+// increment adds one to a string of digits, returning true if it became
+// longer.
+func increment(digits string) (string, bool) {
+	b := []byte(digits)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < '9' {
+			b[i]++
+			return string(b), false
+		}
+		b[i] = '0'
+	}
+	return "1" + string(b), true
+}
+
+// decimalExponent returns the exponent of a positive number in the
+// scientific notation.
+func decimalExponent(x float64) int {
+	s := strconv.FormatFloat(x, 'e', 14, 64)
+	exp, _ := strconv.Atoi(s[strings.IndexByte(s, 'e')+1:])
+	return exp
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// maxSerial is the serial number of the day after 9999-12-31.
+const maxSerial = 2958466
+
+// dateTime formats a serial date and time, which are days since 1900-01-00,
+// with the names of the locale of the section if it has one (e.g. [$-407]).
+// Dates before 1900 or after 9999 can't be displayed and become #####.
+func (s *section) dateTime(v float64, l *Locale) string {
+	names := l
+	if s.locale != nil {
+		names = s.locale
+	}
+	if v < 0 || v >= maxSerial {
+		return "#####"
+	}
+	precision := 0
+	for _, t := range s.tokens {
+		if t.kind == tokSubsecond && len(t.text)-1 > precision {
+			precision = min(len(t.text)-1, 3)
+		}
+	}
+	per := int64(math.Pow10(precision))
+	units := int64(math.Floor(v*86400*float64(per) + 0.5))
+	secs, sub := units/per, units%per
+	days, day := secs/86400, secs%86400
+	hour, minute, second := day/3600, day/60%60, day%60
+	date := serialDate(days)
+
+	b := strings.Builder{}
+	for _, t := range s.tokens {
+		switch t.kind {
+		case tokDate:
+			b.WriteString(s.datePart(t.text, names, date, hour, minute, second))
+		case tokSubsecond:
+			sd := strconv.FormatInt(sub+per, 10)[1:]
+			b.WriteString(l.DecimalSeparator + (sd + "000")[:len(t.text)-1])
+		case tokAmPm:
+			b.WriteString(names.designator(t.text, hour < 12))
+		case tokElapsed:
+			n := secs / 3600
+			switch t.text[0] {
+			case 'm':
+				n = secs / 60
+			case 's':
+				n = secs
+			}
+			b.WriteString(pad(n, len(t.text)))
+		default:
+			b.WriteString(t.literal())
+		}
+	}
+	return b.String()
+}
+
+// serialDate returns the date of a serial day number.
+func serialDate(days int64) time.Time {
+	// Excel counts 1900-02-29, which didn't exist
+	base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if days < 60 {
+		base = base.AddDate(0, 0, 1)
+	}
+	return base.AddDate(0, 0, int(days))
+}
+
+// designator returns the text of AM/PM, am/pm, A/P or a/p in the locale.
+func (l *Locale) designator(code string, am bool) string {
+	d := l.PM
+	if am {
+		d = l.AM
+	}
+	if len(code) == 3 {
+		r, _ := utf8.DecodeRuneInString(d)
+		d = string(r)
+	}
+	if unicode.IsLower(rune(code[0])) {
+		return strings.ToLower(d)
+	}
+	return d
+}
+
+func (s *section) datePart(code string, l *Locale, date time.Time, hour, minute, second int64) string {
+	switch code[0] {
+	case 'y':
+		if len(code) <= 2 {
+			return pad(int64(date.Year()%100), 2)
+		}
+		return pad(int64(date.Year()), 4)
+	case 'm':
+		month := date.Month()
+		switch len(code) {
+		case 1, 2:
+			return pad(int64(month), len(code))
+		case 3:
+			return l.ShortMonthNames[month-1]
+		case 5:
+			r, _ := utf8.DecodeRuneInString(l.MonthNames[month-1])
+			return string(r)
+		}
+		return l.MonthNames[month-1]
+	case 'd':
+		switch len(code) {
+		case 1, 2:
+			return pad(int64(date.Day()), len(code))
+		case 3:
+			return l.ShortDayNames[date.Weekday()]
+		}
+		return l.DayNames[date.Weekday()]
+	case 'h':
+		if s.hours12 {
+			if hour %= 12; hour == 0 {
+				hour = 12
+			}
+		}
+		return pad(hour, min(len(code), 2))
+	case 'n':
+		return pad(minute, min(len(code), 2))
+	case 's':
+		return pad(second, min(len(code), 2))
+	}
+	return ""
+}
+
+// pad returns a number with leading zeros up to the given number of digits.
+func pad(n int64, digits int) string {
+	s := strconv.FormatInt(n, 10)
+	if len(s) < digits {
+		s = strings.Repeat("0", digits-len(s)) + s
+	}
+	return s
+}
+
+// namedColor returns the color of a color name of number formats, which is
+// one of the eight basic colors or ColorN for the colors of the default
+// palette.
+func namedColor(name string) (color.Color, bool) {
+	switch name {
+	case "black":
+		return color.RGB(0x00, 0x00, 0x00), true
+	case "blue":
+		return color.RGB(0x00, 0x00, 0xFF), true
+	case "cyan":
+		return color.RGB(0x00, 0xFF, 0xFF), true
+	case "green":
+		return color.RGB(0x00, 0xFF, 0x00), true
+	case "magenta":
+		return color.RGB(0xFF, 0x00, 0xFF), true
+	case "red":
+		return color.RGB(0xFF, 0x00, 0x00), true
+	case "white":
+		return color.RGB(0xFF, 0xFF, 0xFF), true
+	case "yellow":
+		return color.RGB(0xFF, 0xFF, 0x00), true
+	}
+	if !strings.HasPrefix(name, "color") {
+		return color.Auto, false
+	}
+	n, err := strconv.Atoi(name[len("color"):])
+	if err != nil || n < 1 || n > len(palette) {
+		return color.Auto, false
+	}
+	c := palette[n-1]
+	return color.RGB(uint8(c>>16), uint8(c>>8), uint8(c)), true
+}
+
+// palette is the default palette of 56 colors.
+var palette = [...]uint32{
+	0x000000, 0xFFFFFF, 0xFF0000, 0x00FF00, 0x0000FF, 0xFFFF00, 0xFF00FF, 0x00FFFF,
+	0x800000, 0x008000, 0x000080, 0x808000, 0x800080, 0x008080, 0xC0C0C0, 0x808080,
+	0x9999FF, 0x993366, 0xFFFFCC, 0xCCFFFF, 0x660066, 0xFF8080, 0x0066CC, 0xCCCCFF,
+	0x000080, 0xFF00FF, 0xFFFF00, 0x00FFFF, 0x800080, 0x800000, 0x008080, 0x0000FF,
+	0x00CCFF, 0xCCFFFF, 0xCCFFCC, 0xFFFF99, 0x99CCFF, 0xFF99CC, 0xCC99FF, 0xFFCC99,
+	0x3366FF, 0x33CCCC, 0x99CC00, 0xFFCC00, 0xFF9900, 0xFF6600, 0x666699, 0x969696,
+	0x003366, 0x339966, 0x003300, 0x333300, 0x993300, 0x993366, 0x333399, 0x333333,
+}
+
+// IndexedColor returns a color of the indexed palette of styles, where 0 to
+// 7 are the eight basic colors, 8 to 63 are the colors of the default palette
+// and 64 and 65 are the system foreground and background colors.
+func IndexedColor(i uint32) (color.Color, bool) {
+	var c uint32
+	switch {
+	case i < 8:
+		c = palette[i]
+	case i < 64:
+		c = palette[i-8]
+	case i == 64:
+		c = 0x000000
+	case i == 65:
+		c = 0xFFFFFF
+	default:
+		return color.Auto, false
+	}
+	return color.RGB(uint8(c>>16), uint8(c>>8), uint8(c)), true
+}
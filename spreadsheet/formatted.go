@@ -0,0 +1,58 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"strconv"
+
+	"github.com/unidoc/unioffice/color"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/format"
+)
+
+// GetFormattedResult returns the formatted cell value as GetFormattedValue
+// does, along with the color that the number format selects for it (e.g.
+// red for negative numbers with #,##0;[Red]-#,##0).  Numbers and dates are
+// displayed in the locale of the workbook.
+func (c Cell) GetFormattedResult() format.Result {
+	l := c._ebb.Locale()
+	f := c.localeFormat(l)
+	switch c._dbd.TAttr {
+	case sml.ST_CellTypeB:
+		b, _ := c.GetValueAsBool()
+		if b {
+			return format.Result{Text: "TRUE", Color: color.Auto}
+		}
+		return format.Result{Text: "FALSE", Color: color.Auto}
+	case sml.ST_CellTypeN:
+		v, _ := c.GetValueAsNumber()
+		return l.NumberResult(v, f)
+	case sml.ST_CellTypeE:
+		if c._dbd.V != nil {
+			return format.Result{Text: *c._dbd.V, Color: color.Auto}
+		}
+		return format.Result{Color: color.Auto}
+	case sml.ST_CellTypeS, sml.ST_CellTypeInlineStr:
+		return format.StringResult(c.GetString(), f)
+	case sml.ST_CellTypeStr:
+		s := c.GetString()
+		if format.IsNumber(s) {
+			v, _ := strconv.ParseFloat(s, 64)
+			return l.NumberResult(v, f)
+		}
+		return format.StringResult(s, f)
+	}
+	raw, _ := c.GetRawValue()
+	if len(raw) == 0 {
+		return format.Result{Color: color.Auto}
+	}
+	if v, err := c.GetValueAsNumber(); err == nil {
+		return l.NumberResult(v, f)
+	}
+	return format.StringResult(raw, f)
+}
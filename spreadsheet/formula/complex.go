@@ -0,0 +1,334 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"math"
+	"math/cmplx"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	RegisterFunction("COMPLEX", Complex)
+	RegisterFunction("IMABS", imNumber("IMABS", cmplx.Abs))
+	RegisterFunction("IMAGINARY", imNumber("IMAGINARY", func(c complex128) float64 { return imag(c) }))
+	RegisterFunction("IMREAL", imNumber("IMREAL", func(c complex128) float64 { return real(c) }))
+	RegisterFunction("IMARGUMENT", ImArgument)
+	RegisterFunction("IMCONJUGATE", imComplex("IMCONJUGATE", cmplx.Conj, false))
+	RegisterFunction("IMCOS", imComplex("IMCOS", cmplx.Cos, false))
+	RegisterFunction("IMCOSH", imComplex("IMCOSH", cmplx.Cosh, false))
+	RegisterFunction("_xlfn.IMCOSH", imComplex("IMCOSH", cmplx.Cosh, false))
+	RegisterFunction("IMCOT", imComplex("IMCOT", cmplx.Cot, true))
+	RegisterFunction("_xlfn.IMCOT", imComplex("IMCOT", cmplx.Cot, true))
+	RegisterFunction("IMCSC", imComplex("IMCSC", func(c complex128) complex128 { return 1 / cmplx.Sin(c) }, true))
+	RegisterFunction("_xlfn.IMCSC", imComplex("IMCSC", func(c complex128) complex128 { return 1 / cmplx.Sin(c) }, true))
+	RegisterFunction("IMCSCH", imComplex("IMCSCH", func(c complex128) complex128 { return 1 / cmplx.Sinh(c) }, true))
+	RegisterFunction("_xlfn.IMCSCH", imComplex("IMCSCH", func(c complex128) complex128 { return 1 / cmplx.Sinh(c) }, true))
+	RegisterFunction("IMEXP", imComplex("IMEXP", cmplx.Exp, false))
+	RegisterFunction("IMLN", imComplex("IMLN", cmplx.Log, true))
+	RegisterFunction("IMLOG10", imComplex("IMLOG10", cmplx.Log10, true))
+	RegisterFunction("IMLOG2", imComplex("IMLOG2", func(c complex128) complex128 { return cmplx.Log(c) / complex(math.Ln2, 0) }, true))
+	RegisterFunction("IMSEC", imComplex("IMSEC", func(c complex128) complex128 { return 1 / cmplx.Cos(c) }, false))
+	RegisterFunction("_xlfn.IMSEC", imComplex("IMSEC", func(c complex128) complex128 { return 1 / cmplx.Cos(c) }, false))
+	RegisterFunction("IMSECH", imComplex("IMSECH", func(c complex128) complex128 { return 1 / cmplx.Cosh(c) }, false))
+	RegisterFunction("_xlfn.IMSECH", imComplex("IMSECH", func(c complex128) complex128 { return 1 / cmplx.Cosh(c) }, false))
+	RegisterFunction("IMSIN", imComplex("IMSIN", cmplx.Sin, false))
+	RegisterFunction("IMSINH", imComplex("IMSINH", cmplx.Sinh, false))
+	RegisterFunction("_xlfn.IMSINH", imComplex("IMSINH", cmplx.Sinh, false))
+	RegisterFunction("IMSQRT", imComplex("IMSQRT", cmplx.Sqrt, false))
+	RegisterFunction("IMTAN", imComplex("IMTAN", cmplx.Tan, false))
+	RegisterFunction("_xlfn.IMTAN", imComplex("IMTAN", cmplx.Tan, false))
+	RegisterFunction("IMDIV", ImDiv)
+	RegisterFunction("IMPOWER", ImPower)
+	RegisterFunction("IMSUB", ImSub)
+	RegisterFunction("IMSUM", imAggregate("IMSUM", 0, func(a, b complex128) complex128 { return a + b }))
+	RegisterFunction("IMPRODUCT", imAggregate("IMPRODUCT", 1, func(a, b complex128) complex128 { return a * b }))
+}
+
+// complexNumber is a complex number argument along with the suffix used for
+// its imaginary part.
+type complexNumber struct {
+	v      complex128
+	suffix string
+}
+
+// parseComplex parses a complex number in the text form used by Excel, e.g.
+// "3+4i", "-2.5j", "i" or "1E-3-2E+2i".
+func parseComplex(name string, r Result) (complexNumber, Result) {
+	bad := MakeErrorResultType(ErrorTypeNum, name+" requires a complex number")
+	switch r.Type {
+	case ResultTypeEmpty:
+		return complexNumber{suffix: "i"}, MakeEmptyResult()
+	case ResultTypeNumber:
+		if r.IsBoolean {
+			return complexNumber{}, MakeErrorResult(name + " requires a complex number")
+		}
+		return complexNumber{complex(r.ValueNumber, 0), "i"}, MakeEmptyResult()
+	case ResultTypeError:
+		return complexNumber{}, r
+	case ResultTypeString:
+	default:
+		return complexNumber{}, MakeErrorResult(name + " requires a complex number")
+	}
+	s := r.ValueString
+	if s == "" {
+		return complexNumber{suffix: "i"}, MakeEmptyResult()
+	}
+	c := complexNumber{suffix: "i"}
+	last := s[len(s)-1]
+	if last != 'i' && last != 'j' {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return c, bad
+		}
+		c.v = complex(v, 0)
+		return c, MakeEmptyResult()
+	}
+	c.suffix = string(last)
+	s = s[:len(s)-1]
+	// find the sign separating the real and imaginary parts, skipping exponent
+	// signs
+	split := -1
+	for i := len(s) - 1; i > 0; i-- {
+		if (s[i] == '+' || s[i] == '-') && s[i-1] != 'e' && s[i-1] != 'E' {
+			split = i
+			break
+		}
+	}
+	re, im := "", s
+	if split > 0 {
+		re, im = s[:split], s[split:]
+	}
+	switch im {
+	case "", "+":
+		im = "1"
+	case "-":
+		im = "-1"
+	}
+	iv, err := strconv.ParseFloat(im, 64)
+	if err != nil || strings.ContainsAny(im, " ") {
+		return c, bad
+	}
+	rv := 0.0
+	if re != "" {
+		if rv, err = strconv.ParseFloat(re, 64); err != nil {
+			return c, bad
+		}
+	}
+	c.v = complex(rv, iv)
+	return c, MakeEmptyResult()
+}
+
+// formatComplexPart formats a part of a complex number with up to 15
+// significant digits like Excel.
+func formatComplexPart(v float64) string {
+	return strconv.FormatFloat(v, 'G', 15, 64)
+}
+
+// makeComplexResult formats a complex number as text like Excel, omitting
+// zero parts and a coefficient of one for the imaginary part.
+func makeComplexResult(name string, v complex128, suffix string) Result {
+	re, im := real(v), imag(v)
+	if math.IsNaN(re) || math.IsNaN(im) || math.IsInf(re, 0) || math.IsInf(im, 0) {
+		return MakeErrorResultType(ErrorTypeNum, name+" result is out of range")
+	}
+	if im == 0 {
+		return MakeStringResult(formatComplexPart(re))
+	}
+	s := ""
+	if re != 0 {
+		s = formatComplexPart(re)
+	}
+	switch im {
+	case 1:
+		if s != "" {
+			s += "+"
+		}
+	case -1:
+		s += "-"
+	default:
+		if im > 0 && s != "" {
+			s += "+"
+		}
+		s += formatComplexPart(im)
+	}
+	return MakeStringResult(s + suffix)
+}
+
+// Complex is an implementation of the Excel COMPLEX() function which converts
+// real and imaginary coefficients into a complex number.
+func Complex(args []Result) Result {
+	if len(args) != 2 && len(args) != 3 {
+		return MakeErrorResult("COMPLEX requires two or three arguments")
+	}
+	re, res := statNumberArg("COMPLEX", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	im, res := statNumberArg("COMPLEX", args[1])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	suffix := "i"
+	if len(args) == 3 && args[2].Type != ResultTypeEmpty {
+		suffix = args[2].Value()
+		if suffix != "i" && suffix != "j" {
+			return MakeErrorResult("COMPLEX requires a suffix of i or j")
+		}
+	}
+	return makeComplexResult("COMPLEX", complex(re, im), suffix)
+}
+
+// imNumber returns a function of a complex number which returns a real number.
+func imNumber(name string, fn func(complex128) float64) Function {
+	return func(args []Result) Result {
+		if len(args) != 1 {
+			return MakeErrorResult(name + " requires one argument")
+		}
+		c, res := parseComplex(name, args[0])
+		if res.Type == ResultTypeError {
+			return res
+		}
+		return MakeNumberResult(fn(c.v))
+	}
+}
+
+// imComplex returns a function of a complex number which returns a complex
+// number.  If nonZero is set the function isn't defined for zero.
+func imComplex(name string, fn func(complex128) complex128, nonZero bool) Function {
+	return func(args []Result) Result {
+		if len(args) != 1 {
+			return MakeErrorResult(name + " requires one argument")
+		}
+		c, res := parseComplex(name, args[0])
+		if res.Type == ResultTypeError {
+			return res
+		}
+		if nonZero && c.v == 0 {
+			return MakeErrorResultType(ErrorTypeNum, name+" is not defined for zero")
+		}
+		return makeComplexResult(name, fn(c.v), c.suffix)
+	}
+}
+
+// ImArgument is an implementation of the Excel IMARGUMENT() function which
+// returns the angle of a complex number in radians.
+func ImArgument(args []Result) Result {
+	if len(args) != 1 {
+		return MakeErrorResult("IMARGUMENT requires one argument")
+	}
+	c, res := parseComplex("IMARGUMENT", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	if c.v == 0 {
+		return MakeErrorResultType(ErrorTypeDivideByZero, "IMARGUMENT is not defined for zero")
+	}
+	return MakeNumberResult(cmplx.Phase(c.v))
+}
+
+// complexPair parses two complex number arguments which must use the same
+// suffix.
+func complexPair(name string, args []Result) (complexNumber, complexNumber, Result) {
+	if len(args) != 2 {
+		return complexNumber{}, complexNumber{}, MakeErrorResult(name + " requires two arguments")
+	}
+	a, res := parseComplex(name, args[0])
+	if res.Type == ResultTypeError {
+		return a, a, res
+	}
+	b, res := parseComplex(name, args[1])
+	if res.Type == ResultTypeError {
+		return a, b, res
+	}
+	if imag(a.v) != 0 && imag(b.v) != 0 && a.suffix != b.suffix {
+		return a, b, MakeErrorResult(name + " requires the same suffix for both numbers")
+	}
+	if imag(a.v) == 0 {
+		a.suffix = b.suffix
+	}
+	return a, b, MakeEmptyResult()
+}
+
+// ImDiv is an implementation of the Excel IMDIV() function.
+func ImDiv(args []Result) Result {
+	a, b, res := complexPair("IMDIV", args)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	if b.v == 0 {
+		return MakeErrorResultType(ErrorTypeNum, "IMDIV divide by zero")
+	}
+	return makeComplexResult("IMDIV", a.v/b.v, a.suffix)
+}
+
+// ImSub is an implementation of the Excel IMSUB() function.
+func ImSub(args []Result) Result {
+	a, b, res := complexPair("IMSUB", args)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	return makeComplexResult("IMSUB", a.v-b.v, a.suffix)
+}
+
+// ImPower is an implementation of the Excel IMPOWER() function which raises a
+// complex number to a real power.
+func ImPower(args []Result) Result {
+	if len(args) != 2 {
+		return MakeErrorResult("IMPOWER requires two arguments")
+	}
+	c, res := parseComplex("IMPOWER", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	p, res := statNumberArg("IMPOWER", args[1])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	if c.v == 0 && p <= 0 {
+		return MakeErrorResultType(ErrorTypeNum, "IMPOWER is not defined for zero")
+	}
+	// use the polar form to keep integer powers of real numbers exact
+	r, theta := cmplx.Polar(c.v)
+	return makeComplexResult("IMPOWER", cmplx.Rect(math.Pow(r, p), theta*p), c.suffix)
+}
+
+// imAggregate returns IMSUM or IMPRODUCT which combine any number of complex
+// numbers, including ranges.
+func imAggregate(name string, init complex128, fn func(a, b complex128) complex128) Function {
+	return func(args []Result) Result {
+		if len(args) == 0 {
+			return MakeErrorResult(name + " requires at least one argument")
+		}
+		acc, suffix := init, ""
+		for _, arg := range args {
+			values := []Result{arg}
+			if arg.Type == ResultTypeArray || arg.Type == ResultTypeList {
+				values = arg.ListValues()
+			}
+			for _, v := range values {
+				c, res := parseComplex(name, v)
+				if res.Type == ResultTypeError {
+					return res
+				}
+				if imag(c.v) != 0 {
+					if suffix != "" && suffix != c.suffix {
+						return MakeErrorResult(name + " requires the same suffix for all numbers")
+					}
+					suffix = c.suffix
+				}
+				acc = fn(acc, c.v)
+			}
+		}
+		if suffix == "" {
+			suffix = "i"
+		}
+		return makeComplexResult(name, acc, suffix)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import "testing"
+
+func TestComplex(t *testing.T) {
+	testFormulas(t, InvalidReferenceContext, []formulaCase{
+		{"COMPLEX(3,4)", "3+4i"},
+		{"COMPLEX(3,4,\"j\")", "3+4j"},
+		{"COMPLEX(0,1)", "i"},
+		{"COMPLEX(3,-1,\"j\")", "3-j"},
+		{"COMPLEX(0,0)", "0"},
+		{"COMPLEX(1.5,0)", "1.5"},
+		{"IMREAL(\"6-9i\")", "6"},
+		{"IMAGINARY(\"3+4i\")", "4"},
+		{"IMAGINARY(\"-j\")", "-1"},
+		{"IMAGINARY(4)", "0"},
+		{"IMREAL(\"1E-3-2E+2i\")", "0.001"},
+		{"IMAGINARY(\"1E-3-2E+2i\")", "-200"},
+		{"IMABS(\"5+12i\")", "13"},
+		{"IMARGUMENT(\"3+4i\")", "0.92729522"},
+		{"IMCONJUGATE(\"3+4i\")", "3-4i"},
+		{"IMCONJUGATE(\"i\")", "-i"},
+		{"IMSUM(\"3+4i\",\"5-3i\")", "8+i"},
+		{"IMSUM({\"1+i\",\"2\"},\"3i\")", "3+4i"},
+		{"IMSUB(\"13+4j\",\"5+3j\")", "8+j"},
+		{"IMSUB(\"3\",\"3\")", "0"},
+		{"IMPRODUCT(\"3+4i\",\"5-3i\")", "27+11i"},
+		{"IMPRODUCT(\"1+2i\",30)", "30+60i"},
+		{"IMDIV(\"-238+240i\",\"10+24i\")", "5+12i"},
+		{"IMPOWER(\"2\",3)", "8"},
+		// like Excel, powers are computed in polar form
+		{"IMPOWER(\"i\",2)", "-1+1.22464679914735E-16i"},
+		{"IMSQRT(\"-4\")", "2i"},
+		{"IMSQRT(\"1+i\")", "1.09868411346781+0.455089860562227i"},
+		{"IMEXP(\"1+i\")", "1.46869393991589+2.28735528717884i"},
+		{"IMLN(\"3+4i\")", "1.6094379124341+0.927295218001612i"},
+		{"IMLOG10(\"100\")", "2"},
+		{"IMLOG2(\"8\")", "3"},
+		{"IMSIN(\"0\")", "0"},
+		{"IMCOS(\"1+i\")", "0.833730025131149-0.988897705762865i"},
+		{"IMSIN(\"4+3i\")", "-7.61923172032141-6.548120040911i"},
+		{"IMTAN(\"4+3i\")", "0.00490825806749606+1.00070953606723i"},
+		{"IMSINH(\"4+3i\")", "-27.0168132580039+3.85373803791938i"},
+		{"IMCOSH(\"4+3i\")", "-27.0349456030742+3.85115333481178i"},
+	})
+}
+
+func TestComplexErrors(t *testing.T) {
+	testFormulas(t, InvalidReferenceContext, []formulaCase{
+		{"COMPLEX(1,2,\"k\")", "#VALUE!"},
+		{"COMPLEX(\"a\",2)", "#VALUE!"},
+		{"IMABS(\"abc\")", "#NUM!"},
+		{"IMABS(\"1+2k\")", "#NUM!"},
+		{"IMABS(\"1 +2i\")", "#NUM!"},
+		{"IMABS(TRUE)", "#VALUE!"},
+		{"IMSUM(\"3+4i\",\"1+2j\")", "#VALUE!"},
+		{"IMSUB(\"3+4i\",\"1+2j\")", "#VALUE!"},
+		{"IMDIV(\"1\",\"0\")", "#NUM!"},
+		{"IMLN(\"0\")", "#NUM!"},
+		{"IMCOT(\"0\")", "#NUM!"},
+		{"IMARGUMENT(\"0\")", "#DIV/0!"},
+		{"IMPOWER(\"0\",-1)", "#NUM!"},
+	})
+}
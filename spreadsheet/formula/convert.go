@@ -0,0 +1,288 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import "math"
+
+func init() {
+	RegisterFunction("CONVERT", Convert)
+}
+
+// unitCategory is the kind of quantity measured by a unit.  Only units of
+// the same category can be converted into each other.
+type unitCategory byte
+
+const (
+	unitWeight unitCategory = iota
+	unitDistance
+	unitTime
+	unitPressure
+	unitForce
+	unitEnergy
+	unitPower
+	unitMagnetism
+	unitTemperature
+	unitVolume
+	unitArea
+	unitInformation
+	unitSpeed
+)
+
+// measureUnit is a unit of the CONVERT function.  The factor converts a value
+// in the unit to the base unit of its category.  Units that accept a prefix
+// have the prefix raised to their power, e.g. km2 is 10^6 m2.
+type measureUnit struct {
+	category unitCategory
+	factor   float64
+	prefix   bool
+	power    int
+}
+
+var measureUnits = map[string]measureUnit{}
+
+// addUnits registers units which have the same meaning under several names.
+func addUnits(u measureUnit, names ...string) {
+	if u.power == 0 {
+		u.power = 1
+	}
+	for _, n := range names {
+		measureUnits[n] = u
+	}
+}
+
+func init() {
+	// weight and mass, relative to grams
+	addUnits(measureUnit{category: unitWeight, factor: 1, prefix: true}, "g")
+	addUnits(measureUnit{category: unitWeight, factor: 14593.9029372064}, "sg")
+	addUnits(measureUnit{category: unitWeight, factor: 453.59237}, "lbm")
+	addUnits(measureUnit{category: unitWeight, factor: 1.66053886e-24, prefix: true}, "u")
+	addUnits(measureUnit{category: unitWeight, factor: 28.349523125}, "ozm")
+	addUnits(measureUnit{category: unitWeight, factor: 0.06479891}, "grain")
+	addUnits(measureUnit{category: unitWeight, factor: 45359.237}, "cwt", "shweight")
+	addUnits(measureUnit{category: unitWeight, factor: 50802.34544}, "uk_cwt", "lcwt", "hweight")
+	addUnits(measureUnit{category: unitWeight, factor: 6350.29318}, "stone")
+	addUnits(measureUnit{category: unitWeight, factor: 907184.74}, "ton")
+	addUnits(measureUnit{category: unitWeight, factor: 1016046.9088}, "uk_ton", "LTON", "brton")
+
+	// distance, relative to meters
+	addUnits(measureUnit{category: unitDistance, factor: 1, prefix: true}, "m")
+	addUnits(measureUnit{category: unitDistance, factor: 1609.344}, "mi")
+	addUnits(measureUnit{category: unitDistance, factor: 1852}, "Nmi")
+	addUnits(measureUnit{category: unitDistance, factor: 0.0254}, "in")
+	addUnits(measureUnit{category: unitDistance, factor: 0.3048}, "ft")
+	addUnits(measureUnit{category: unitDistance, factor: 0.9144}, "yd")
+	addUnits(measureUnit{category: unitDistance, factor: 1e-10, prefix: true}, "ang")
+	addUnits(measureUnit{category: unitDistance, factor: 1.143}, "ell")
+	addUnits(measureUnit{category: unitDistance, factor: 9.4607304725808e15, prefix: true}, "ly")
+	addUnits(measureUnit{category: unitDistance, factor: 3.08567758128155e16, prefix: true}, "parsec", "pc")
+	addUnits(measureUnit{category: unitDistance, factor: 0.0254 / 72}, "Picapt", "Pica")
+	addUnits(measureUnit{category: unitDistance, factor: 0.0254 / 6}, "pica")
+	addUnits(measureUnit{category: unitDistance, factor: 1609.34721869444}, "survey_mi")
+
+	// time, relative to seconds
+	addUnits(measureUnit{category: unitTime, factor: 31557600}, "yr")
+	addUnits(measureUnit{category: unitTime, factor: 86400}, "day", "d")
+	addUnits(measureUnit{category: unitTime, factor: 3600}, "hr")
+	addUnits(measureUnit{category: unitTime, factor: 60}, "mn", "min")
+	addUnits(measureUnit{category: unitTime, factor: 1, prefix: true}, "sec", "s")
+
+	// pressure, relative to pascals
+	addUnits(measureUnit{category: unitPressure, factor: 1, prefix: true}, "Pa", "p")
+	addUnits(measureUnit{category: unitPressure, factor: 101325, prefix: true}, "atm", "at")
+	addUnits(measureUnit{category: unitPressure, factor: 133.322, prefix: true}, "mmHg")
+	addUnits(measureUnit{category: unitPressure, factor: 6894.75729316836}, "psi")
+	addUnits(measureUnit{category: unitPressure, factor: 101325.0 / 760}, "Torr")
+
+	// force, relative to newtons
+	addUnits(measureUnit{category: unitForce, factor: 1, prefix: true}, "N")
+	addUnits(measureUnit{category: unitForce, factor: 1e-5, prefix: true}, "dyn", "dy")
+	addUnits(measureUnit{category: unitForce, factor: 4.4482216152605}, "lbf")
+	addUnits(measureUnit{category: unitForce, factor: 0.00980665, prefix: true}, "pond")
+
+	// energy, relative to joules
+	addUnits(measureUnit{category: unitEnergy, factor: 1, prefix: true}, "J")
+	addUnits(measureUnit{category: unitEnergy, factor: 1e-7, prefix: true}, "e")
+	addUnits(measureUnit{category: unitEnergy, factor: 4.184, prefix: true}, "c")
+	addUnits(measureUnit{category: unitEnergy, factor: 4.1868, prefix: true}, "cal")
+	addUnits(measureUnit{category: unitEnergy, factor: 1.602176487e-19, prefix: true}, "eV", "ev")
+	addUnits(measureUnit{category: unitEnergy, factor: 2684519.53769617}, "HPh", "hh")
+	addUnits(measureUnit{category: unitEnergy, factor: 3600, prefix: true}, "Wh", "wh")
+	addUnits(measureUnit{category: unitEnergy, factor: 1.3558179483314}, "flb")
+	addUnits(measureUnit{category: unitEnergy, factor: 1055.05585262}, "BTU", "btu")
+
+	// power, relative to watts
+	addUnits(measureUnit{category: unitPower, factor: 745.69987158227}, "HP", "h")
+	addUnits(measureUnit{category: unitPower, factor: 735.49875}, "PS")
+	addUnits(measureUnit{category: unitPower, factor: 1, prefix: true}, "W", "w")
+
+	// magnetism, relative to teslas
+	addUnits(measureUnit{category: unitMagnetism, factor: 1, prefix: true}, "T")
+	addUnits(measureUnit{category: unitMagnetism, factor: 1e-4, prefix: true}, "ga")
+
+	// temperature is converted by convertTemperature, only kelvin accepts a
+	// prefix
+	addUnits(measureUnit{category: unitTemperature}, "C", "cel", "F", "fah", "Rank", "Reau")
+	addUnits(measureUnit{category: unitTemperature, factor: 1, prefix: true}, "K", "kel")
+
+	// volume, relative to cubic meters
+	addUnits(measureUnit{category: unitVolume, factor: 4.92892159375e-6}, "tsp")
+	addUnits(measureUnit{category: unitVolume, factor: 5e-6}, "tspm")
+	addUnits(measureUnit{category: unitVolume, factor: 1.478676478125e-5}, "tbs")
+	addUnits(measureUnit{category: unitVolume, factor: 2.95735295625e-5}, "oz")
+	addUnits(measureUnit{category: unitVolume, factor: 2.365882365e-4}, "cup")
+	addUnits(measureUnit{category: unitVolume, factor: 4.73176473e-4}, "pt", "us_pt")
+	addUnits(measureUnit{category: unitVolume, factor: 5.6826125e-4}, "uk_pt")
+	addUnits(measureUnit{category: unitVolume, factor: 9.46352946e-4}, "qt")
+	addUnits(measureUnit{category: unitVolume, factor: 1.1365225e-3}, "uk_qt")
+	addUnits(measureUnit{category: unitVolume, factor: 3.785411784e-3}, "gal")
+	addUnits(measureUnit{category: unitVolume, factor: 4.54609e-3}, "uk_gal")
+	addUnits(measureUnit{category: unitVolume, factor: 1e-3, prefix: true}, "l", "L", "lt")
+	addUnits(measureUnit{category: unitVolume, factor: 1e-30, prefix: true, power: 3}, "ang3", "ang^3")
+	addUnits(measureUnit{category: unitVolume, factor: 0.158987294928}, "barrel")
+	addUnits(measureUnit{category: unitVolume, factor: 0.03523907016688}, "bushel")
+	addUnits(measureUnit{category: unitVolume, factor: 0.028316846592}, "ft3", "ft^3")
+	addUnits(measureUnit{category: unitVolume, factor: 1.6387064e-5}, "in3", "in^3")
+	addUnits(measureUnit{category: unitVolume, factor: 8.46786664623715e47, prefix: true, power: 3}, "ly3", "ly^3")
+	addUnits(measureUnit{category: unitVolume, factor: 1, prefix: true, power: 3}, "m3", "m^3")
+	addUnits(measureUnit{category: unitVolume, factor: 4168181825.44058}, "mi3", "mi^3")
+	addUnits(measureUnit{category: unitVolume, factor: 0.764554857984}, "yd3", "yd^3")
+	addUnits(measureUnit{category: unitVolume, factor: 6352182208}, "Nmi3", "Nmi^3")
+	addUnits(measureUnit{category: unitVolume, factor: 4.39039566186557e-11}, "Picapt3", "Picapt^3", "Pica3", "Pica^3")
+	addUnits(measureUnit{category: unitVolume, factor: 2.8316846592}, "GRT", "regton")
+	addUnits(measureUnit{category: unitVolume, factor: 1.13267386368}, "MTON")
+
+	// area, relative to square meters
+	addUnits(measureUnit{category: unitArea, factor: 4046.8564224}, "uk_acre")
+	addUnits(measureUnit{category: unitArea, factor: 4046.87260987425}, "us_acre")
+	addUnits(measureUnit{category: unitArea, factor: 1e-20, prefix: true, power: 2}, "ang2", "ang^2")
+	addUnits(measureUnit{category: unitArea, factor: 100, prefix: true}, "ar")
+	addUnits(measureUnit{category: unitArea, factor: 0.09290304}, "ft2", "ft^2")
+	addUnits(measureUnit{category: unitArea, factor: 10000}, "ha")
+	addUnits(measureUnit{category: unitArea, factor: 6.4516e-4}, "in2", "in^2")
+	addUnits(measureUnit{category: unitArea, factor: 8.95054210748189e31, prefix: true, power: 2}, "ly2", "ly^2")
+	addUnits(measureUnit{category: unitArea, factor: 1, prefix: true, power: 2}, "m2", "m^2")
+	addUnits(measureUnit{category: unitArea, factor: 2500}, "Morgen")
+	addUnits(measureUnit{category: unitArea, factor: 2589988.110336}, "mi2", "mi^2")
+	addUnits(measureUnit{category: unitArea, factor: 3429904}, "Nmi2", "Nmi^2")
+	addUnits(measureUnit{category: unitArea, factor: 1.24452160493827e-7}, "Picapt2", "Picapt^2", "Pica2", "Pica^2")
+	addUnits(measureUnit{category: unitArea, factor: 0.83612736}, "yd2", "yd^2")
+
+	// information, relative to bits
+	addUnits(measureUnit{category: unitInformation, factor: 1, prefix: true}, "bit")
+	addUnits(measureUnit{category: unitInformation, factor: 8, prefix: true}, "byte")
+
+	// speed, relative to meters per second
+	addUnits(measureUnit{category: unitSpeed, factor: 0.514773333333333}, "admkn")
+	addUnits(measureUnit{category: unitSpeed, factor: 1852.0 / 3600}, "kn")
+	addUnits(measureUnit{category: unitSpeed, factor: 1.0 / 3600, prefix: true}, "m/h", "m/hr")
+	addUnits(measureUnit{category: unitSpeed, factor: 1, prefix: true}, "m/s", "m/sec")
+	addUnits(measureUnit{category: unitSpeed, factor: 0.44704}, "mph")
+}
+
+// unitPrefixes are the metric prefixes accepted by CONVERT.
+var unitPrefixes = map[string]float64{
+	"Y": 1e24, "Z": 1e21, "E": 1e18, "P": 1e15, "T": 1e12, "G": 1e9, "M": 1e6,
+	"k": 1e3, "h": 1e2, "da": 1e1, "e": 1e1, "d": 1e-1, "c": 1e-2, "m": 1e-3,
+	"u": 1e-6, "n": 1e-9, "p": 1e-12, "f": 1e-15, "a": 1e-18, "z": 1e-21,
+	"y": 1e-24,
+}
+
+// binaryPrefixes are the binary prefixes which are only accepted for units of
+// information.
+var binaryPrefixes = map[string]float64{
+	"ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30, "Ti": 1 << 40,
+	"Pi": 1 << 50, "Ei": 1 << 60, "Zi": 1 << 70, "Yi": 1 << 80,
+}
+
+// lookupUnit returns a unit with the multiplier of its prefix.
+func lookupUnit(name string) (measureUnit, float64, bool) {
+	if u, ok := measureUnits[name]; ok {
+		return u, 1, true
+	}
+	for _, n := range []int{2, 1} {
+		if len(name) <= n {
+			continue
+		}
+		p, rest := name[:n], name[n:]
+		u, ok := measureUnits[rest]
+		if !ok || !u.prefix {
+			continue
+		}
+		if m, ok := unitPrefixes[p]; ok {
+			return u, math.Pow(m, float64(u.power)), true
+		}
+		if m, ok := binaryPrefixes[p]; ok && u.category == unitInformation {
+			return u, m, true
+		}
+	}
+	return measureUnit{}, 0, false
+}
+
+// toKelvin and fromKelvin convert temperatures to and from kelvin.
+func toKelvin(name string, v float64) float64 {
+	switch name {
+	case "C", "cel":
+		return v + 273.15
+	case "F", "fah":
+		return (v-32)*5/9 + 273.15
+	case "Rank":
+		return v * 5 / 9
+	case "Reau":
+		return v*1.25 + 273.15
+	}
+	return v
+}
+
+func fromKelvin(name string, v float64) float64 {
+	switch name {
+	case "C", "cel":
+		return v - 273.15
+	case "F", "fah":
+		return (v-273.15)*9/5 + 32
+	case "Rank":
+		return v * 9 / 5
+	case "Reau":
+		return (v - 273.15) / 1.25
+	}
+	return v
+}
+
+// Convert is an implementation of the Excel CONVERT() function which converts
+// a number from one measurement unit to another, e.g. CONVERT(1,"mi","km").
+func Convert(args []Result) Result {
+	if len(args) != 3 {
+		return MakeErrorResult("CONVERT requires three arguments")
+	}
+	v, res := statNumberArg("CONVERT", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	fromName, toName := args[1].Value(), args[2].Value()
+	from, fromScale, ok := lookupUnit(fromName)
+	if !ok {
+		return MakeErrorResultType(ErrorTypeNA, "CONVERT has an unknown unit "+fromName)
+	}
+	to, toScale, ok := lookupUnit(toName)
+	if !ok {
+		return MakeErrorResultType(ErrorTypeNA, "CONVERT has an unknown unit "+toName)
+	}
+	if from.category != to.category {
+		return MakeErrorResultType(ErrorTypeNA, "CONVERT requires units of the same kind")
+	}
+	if from.category == unitTemperature {
+		if _, ok := measureUnits[fromName]; !ok {
+			fromName = "K"
+		}
+		if _, ok := measureUnits[toName]; !ok {
+			toName = "K"
+		}
+		k := toKelvin(fromName, v*fromScale)
+		return MakeNumberResult(fromKelvin(toName, k) / toScale)
+	}
+	return MakeNumberResult(v * from.factor * fromScale / (to.factor * toScale))
+}
@@ -0,0 +1,59 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import "testing"
+
+func TestConvert(t *testing.T) {
+	testFormulas(t, InvalidReferenceContext, []formulaCase{
+		{"CONVERT(1,\"lbm\",\"kg\")", "0.45359237"},
+		{"CONVERT(68,\"F\",\"C\")", "20"},
+		{"CONVERT(100,\"C\",\"F\")", "212"},
+		{"CONVERT(100,\"C\",\"K\")", "373.15"},
+		{"CONVERT(0,\"K\",\"Rank\")", "0"},
+		{"CONVERT(80,\"Reau\",\"cel\")", "100"},
+		{"CONVERT(1,\"mK\",\"K\")", "0.001"},
+		{"CONVERT(6,\"tsp\",\"tbs\")", "2"},
+		{"CONVERT(6,\"gal\",\"l\")", "22.712470704"},
+		{"CONVERT(1,\"mi\",\"km\")", "1.609344"},
+		{"CONVERT(1,\"in\",\"cm\")", "2.54"},
+		{"CONVERT(1,\"Nmi\",\"m\")", "1852"},
+		{"CONVERT(100,\"ft2\",\"m2\")", "9.290304"},
+		{"CONVERT(1,\"km2\",\"m2\")", "1000000"},
+		{"CONVERT(1,\"ha\",\"m2\")", "10000"},
+		{"CONVERT(1,\"m3\",\"l\")", "1000"},
+		{"CONVERT(1,\"cm3\",\"ml\")", "1"},
+		{"CONVERT(1,\"hr\",\"mn\")", "60"},
+		{"CONVERT(1,\"day\",\"sec\")", "86400"},
+		{"CONVERT(1,\"atm\",\"Pa\")", "101325"},
+		{"CONVERT(1,\"kPa\",\"Pa\")", "1000"},
+		{"CONVERT(1,\"byte\",\"bit\")", "8"},
+		{"CONVERT(1,\"kibyte\",\"byte\")", "1024"},
+		{"CONVERT(1,\"kbyte\",\"byte\")", "1000"},
+		{"CONVERT(1,\"Mibit\",\"kibit\")", "1024"},
+		{"CONVERT(1,\"kn\",\"m/s\")", "0.514444444"},
+		{"CONVERT(1,\"mph\",\"km/h\")", "1.609344"},
+		{"CONVERT(1,\"HP\",\"W\")", "745.69987158"},
+		{"CONVERT(1,\"kWh\",\"J\")", "3600000"},
+		{"CONVERT(1,\"T\",\"ga\")", "10000"},
+		{"CONVERT(1,\"N\",\"dyn\")", "100000"},
+	})
+}
+
+func TestConvertErrors(t *testing.T) {
+	testFormulas(t, InvalidReferenceContext, []formulaCase{
+		{"CONVERT(2.5,\"ft\",\"sec\")", "#N/A"},
+		{"CONVERT(1,\"m\",\"xyz\")", "#N/A"},
+		{"CONVERT(1,\"xyz\",\"m\")", "#N/A"},
+		{"CONVERT(1,\"kft\",\"m\")", "#N/A"},
+		{"CONVERT(1,\"kim\",\"m\")", "#N/A"},
+		{"CONVERT(1,\"M\",\"m\")", "#N/A"},
+		{"CONVERT(\"a\",\"m\",\"ft\")", "#VALUE!"},
+		{"CONVERT(1,\"m\")", "#VALUE!"},
+	})
+}
@@ -0,0 +1,406 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+	"github.com/unidoc/unioffice/spreadsheet/update"
+)
+
+func init() {
+	RegisterFunctionComplex("DSUM", dbFunction("DSUM", dbSum))
+	RegisterFunctionComplex("DAVERAGE", dbFunction("DAVERAGE", dbAverage))
+	RegisterFunctionComplex("DCOUNT", dbFunction("DCOUNT", dbCount))
+	RegisterFunctionComplex("DCOUNTA", dbFunction("DCOUNTA", dbCountA))
+	RegisterFunctionComplex("DGET", dbFunction("DGET", dbGet))
+	RegisterFunctionComplex("DMAX", dbFunction("DMAX", dbMax))
+	RegisterFunctionComplex("DMIN", dbFunction("DMIN", dbMin))
+	RegisterFunctionComplex("DPRODUCT", dbFunction("DPRODUCT", dbProduct))
+	RegisterFunctionComplex("DSTDEV", dbFunction("DSTDEV", dbDeviation(true)))
+	RegisterFunctionComplex("DSTDEVP", dbFunction("DSTDEVP", dbDeviation(false)))
+	RegisterFunctionComplex("DVAR", dbFunction("DVAR", dbVariance(true)))
+	RegisterFunctionComplex("DVARP", dbFunction("DVARP", dbVariance(false)))
+}
+
+// database is the first argument of the database functions, a range whose
+// first row holds the column labels and whose other rows are the records.
+type database struct {
+	labels  []string
+	records [][]Result
+}
+
+// column returns the index of the column with a label, ignoring case.
+func (d database) column(label string) int {
+	label = strings.TrimSpace(label)
+	for i, l := range d.labels {
+		if strings.EqualFold(strings.TrimSpace(l), label) {
+			return i
+		}
+	}
+	return -1
+}
+
+// field returns the column of the field argument which is either a column
+// label or a one based column number.  An empty field returns -1.
+func (d database) field(name string, f Result) (int, Result) {
+	switch f.Type {
+	case ResultTypeEmpty:
+		return -1, MakeEmptyResult()
+	case ResultTypeString:
+		if i := d.column(f.ValueString); i >= 0 {
+			return i, MakeEmptyResult()
+		}
+	case ResultTypeNumber:
+		if i := int(f.ValueNumber) - 1; i >= 0 && i < len(d.labels) {
+			return i, MakeEmptyResult()
+		}
+	case ResultTypeError:
+		return 0, f
+	}
+	return 0, MakeErrorResult(name + " requires a valid field")
+}
+
+// dbCriterion is a condition on a database column, or a computed condition
+// given by a formula which is evaluated for each record.
+type dbCriterion struct {
+	column   int
+	criteria *criteriaParsed
+	formula  string
+}
+
+// dbCriteria are the rows of a criteria range.  A record matches if it
+// matches all of the conditions in any of the rows.
+type dbCriteria [][]dbCriterion
+
+// FormulaContext is implemented by contexts which can return the formulas of
+// cells, which computed criteria of the database functions need.  Without it
+// computed criteria are rejected.
+type FormulaContext interface {
+	// GetFormula returns the formula of a cell, or an empty string if the
+	// cell has no formula.
+	GetFormula(cellRef string) string
+}
+
+// formulaContext returns the FormulaContext of a context, looking through the
+// contexts of LET and LAMBDA.
+func formulaContext(ctx Context) (FormulaContext, bool) {
+	for {
+		switch c := ctx.(type) {
+		case FormulaContext:
+			return c, true
+		case *letContext:
+			ctx = c.Context
+		default:
+			return nil, false
+		}
+	}
+}
+
+// parseDBCriteria parses the criteria range.  Criteria whose label isn't a
+// column label of the database are computed criteria, their cells hold
+// formulas which refer to the first record and are evaluated for each record
+// by moving the formulas down.
+func parseDBCriteria(ctx Context, name string, db database, arg Result) (dbCriteria, Context, Result) {
+	rows := arrayRows(arg)
+	if len(rows) < 2 {
+		return nil, ctx, MakeErrorResult(name + " requires criteria with labels and at least one row")
+	}
+	// locate the criteria cells so their formulas can be read
+	critCtx, origin, hasOrigin := ctx, reference.CellReference{}, false
+	if arg.Ref.Type == ReferenceTypeRange {
+		ref := arg.Ref.Value
+		if i := strings.LastIndex(ref, "!"); i >= 0 {
+			critCtx = ctx.Sheet(strings.Trim(ref[:i], "'"))
+			ref = ref[i+1:]
+		}
+		if from, _, err := reference.ParseRangeReference(ref); err == nil {
+			origin, hasOrigin = from, true
+		}
+	}
+	ret := dbCriteria{}
+	for r, row := range rows[1:] {
+		conds := []dbCriterion{}
+		for c, v := range row {
+			if c >= len(rows[0]) {
+				break
+			}
+			col := db.column(rows[0][c].Value())
+			if col < 0 {
+				// computed criteria
+				formula := ""
+				if fc, ok := formulaContext(critCtx); ok && hasOrigin {
+					formula = fc.GetFormula(reference.IndexToColumn(origin.ColumnIdx+uint32(c)) + strconv.Itoa(int(origin.RowIdx)+r+1))
+				}
+				if formula == "" {
+					if v.Type == ResultTypeEmpty {
+						continue
+					}
+					return nil, ctx, MakeErrorResult(name + " has criteria with an unknown label " + rows[0][c].Value())
+				}
+				conds = append(conds, dbCriterion{column: -1, formula: formula})
+				continue
+			}
+			if v.Type == ResultTypeEmpty {
+				continue
+			}
+			if v.Type == ResultTypeString {
+				v = MakeStringResult(dbTextCriteria(v.ValueString))
+			}
+			conds = append(conds, dbCriterion{column: col, criteria: _cacg(v)})
+		}
+		ret = append(ret, conds)
+	}
+	return ret, critCtx, MakeEmptyResult()
+}
+
+// dbTextCriteria returns the criteria for text in a criteria range.  Unlike
+// COUNTIF, text without a comparison operator matches any value that begins
+// with the text.
+func dbTextCriteria(s string) string {
+	if s == "" || strings.ContainsAny(s[:1], "=<>") {
+		return s
+	}
+	return s + "*"
+}
+
+// matches returns true if the record with an index matches the criteria.
+func (c dbCriteria) matches(ctx Context, ev Evaluator, db database, idx int) bool {
+	for _, row := range c {
+		match := true
+		for _, cond := range row {
+			if cond.column < 0 {
+				res := ev.Eval(ctx, UpdateFormula(cond.formula, &update.UpdateQuery{UpdateType: update.UpdateActionCopy, RowOffset: idx}))
+				if !isTruthy(res) {
+					match = false
+					break
+				}
+				continue
+			}
+			v := MakeEmptyResult()
+			if cond.column < len(db.records[idx]) {
+				v = db.records[idx][cond.column]
+			}
+			if !_egaa(v, cond.criteria) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// dbFunction returns a database function which applies an aggregate to the
+// field values of the records matching the criteria.  The values passed to
+// the aggregate are nil if the field is omitted.
+func dbFunction(name string, aggregate func(name string, values []Result, count int) Result) FunctionComplex {
+	return func(ctx Context, ev Evaluator, args []Result) Result {
+		if len(args) != 3 {
+			return MakeErrorResult(name + " requires three arguments")
+		}
+		for _, a := range args {
+			if a.Type == ResultTypeError {
+				return a
+			}
+		}
+		rows := arrayRows(args[0])
+		if len(rows) < 1 {
+			return MakeErrorResult(name + " requires a database")
+		}
+		db := database{records: rows[1:]}
+		for _, l := range rows[0] {
+			db.labels = append(db.labels, l.Value())
+		}
+		field, res := db.field(name, args[1])
+		if res.Type == ResultTypeError {
+			return res
+		}
+		criteria, critCtx, res := parseDBCriteria(ctx, name, db, args[2])
+		if res.Type == ResultTypeError {
+			return res
+		}
+		values, count := []Result{}, 0
+		for i, rec := range db.records {
+			if !criteria.matches(critCtx, ev, db, i) {
+				continue
+			}
+			count++
+			if field >= 0 && field < len(rec) {
+				values = append(values, rec[field])
+			}
+		}
+		if field < 0 {
+			values = nil
+		}
+		return aggregate(name, values, count)
+	}
+}
+
+// dbNumbers returns the numeric values, ignoring text, booleans and blanks.
+func dbNumbers(values []Result) []float64 {
+	ret := []float64{}
+	for _, v := range values {
+		if v.Type == ResultTypeNumber && !v.IsBoolean {
+			ret = append(ret, v.ValueNumber)
+		}
+	}
+	return ret
+}
+
+func requireField(name string, values []Result) Result {
+	if values == nil {
+		return MakeErrorResult(name + " requires a field")
+	}
+	return MakeEmptyResult()
+}
+
+func dbSum(name string, values []Result, count int) Result {
+	if res := requireField(name, values); res.Type == ResultTypeError {
+		return res
+	}
+	sum := 0.0
+	for _, v := range dbNumbers(values) {
+		sum += v
+	}
+	return MakeNumberResult(sum)
+}
+
+func dbAverage(name string, values []Result, count int) Result {
+	if res := requireField(name, values); res.Type == ResultTypeError {
+		return res
+	}
+	vs := dbNumbers(values)
+	if len(vs) == 0 {
+		return MakeErrorResultType(ErrorTypeDivideByZero, name+" has no numeric values")
+	}
+	return MakeNumberResult(mean(vs))
+}
+
+// dbCount counts the numeric values, or the matching records if the field is
+// omitted.
+func dbCount(name string, values []Result, count int) Result {
+	if values == nil {
+		return MakeNumberResult(float64(count))
+	}
+	return MakeNumberResult(float64(len(dbNumbers(values))))
+}
+
+// dbCountA counts the non-blank values, or the matching records if the field
+// is omitted.
+func dbCountA(name string, values []Result, count int) Result {
+	if values == nil {
+		return MakeNumberResult(float64(count))
+	}
+	n := 0
+	for _, v := range values {
+		if v.Type != ResultTypeEmpty {
+			n++
+		}
+	}
+	return MakeNumberResult(float64(n))
+}
+
+// dbGet returns the value of the single matching record.
+func dbGet(name string, values []Result, count int) Result {
+	if res := requireField(name, values); res.Type == ResultTypeError {
+		return res
+	}
+	switch len(values) {
+	case 0:
+		return MakeErrorResult(name + " found no matching record")
+	case 1:
+		return values[0]
+	}
+	return MakeErrorResultType(ErrorTypeNum, name+" found more than one matching record")
+}
+
+func dbMax(name string, values []Result, count int) Result {
+	if res := requireField(name, values); res.Type == ResultTypeError {
+		return res
+	}
+	vs := dbNumbers(values)
+	if len(vs) == 0 {
+		return MakeNumberResult(0)
+	}
+	m := vs[0]
+	for _, v := range vs[1:] {
+		m = math.Max(m, v)
+	}
+	return MakeNumberResult(m)
+}
+
+func dbMin(name string, values []Result, count int) Result {
+	if res := requireField(name, values); res.Type == ResultTypeError {
+		return res
+	}
+	vs := dbNumbers(values)
+	if len(vs) == 0 {
+		return MakeNumberResult(0)
+	}
+	m := vs[0]
+	for _, v := range vs[1:] {
+		m = math.Min(m, v)
+	}
+	return MakeNumberResult(m)
+}
+
+func dbProduct(name string, values []Result, count int) Result {
+	if res := requireField(name, values); res.Type == ResultTypeError {
+		return res
+	}
+	vs := dbNumbers(values)
+	if len(vs) == 0 {
+		return MakeNumberResult(0)
+	}
+	p := 1.0
+	for _, v := range vs {
+		p *= v
+	}
+	return MakeNumberResult(p)
+}
+
+// dbVariance returns DVAR for samples or DVARP for populations.
+func dbVariance(sample bool) func(name string, values []Result, count int) Result {
+	return func(name string, values []Result, count int) Result {
+		if res := requireField(name, values); res.Type == ResultTypeError {
+			return res
+		}
+		vs := dbNumbers(values)
+		n := float64(len(vs))
+		if sample {
+			n--
+		}
+		if n <= 0 {
+			return MakeErrorResultType(ErrorTypeDivideByZero, name+" has too few numeric values")
+		}
+		m := mean(vs)
+		sum := 0.0
+		for _, v := range vs {
+			sum += (v - m) * (v - m)
+		}
+		return MakeNumberResult(sum / n)
+	}
+}
+
+// dbDeviation returns DSTDEV for samples or DSTDEVP for populations.
+func dbDeviation(sample bool) func(name string, values []Result, count int) Result {
+	variance := dbVariance(sample)
+	return func(name string, values []Result, count int) Result {
+		res := variance(name, values, count)
+		if res.Type == ResultTypeError {
+			return res
+		}
+		return MakeNumberResult(math.Sqrt(res.ValueNumber))
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import "testing"
+
+// formulaCellContext is a cell context which also returns the formulas of its
+// cells.
+type formulaCellContext struct {
+	cellContext
+	formulas map[string]string
+}
+
+func (c formulaCellContext) GetFormula(ref string) string { return c.formulas[ref] }
+
+// texts returns string results, empty strings are empty cells.
+func texts(vs ...string) []Result {
+	ret := []Result{}
+	for _, v := range vs {
+		if v == "" {
+			ret = append(ret, MakeEmptyResult())
+		} else {
+			ret = append(ret, MakeStringResult(v))
+		}
+	}
+	return ret
+}
+
+// dbContext holds the orchard database from the Excel documentation in A1:E7
+// and criteria ranges below it.
+//
+//	Tree    Height  Age  Yield  Profit
+//	Apple   18      20   14     105
+//	Pear    12      12   10     96
+//	Cherry  13      14   9      105
+//	Apple   14      15   10     75
+//	Pear    9       8    8      76.8
+//	Apple   8       9    6      45
+//
+// A9:F11 holds the criteria of the documentation, "=Apple" with a Height over
+// 10 and less than 16 or "=Pear".  A13:B14 holds "=Apple" and the computed
+// criterion E2>AVERAGE($E$2:$E$7) labelled Above.
+func dbContext() formulaCellContext {
+	cells := newCellContext(map[string][]Result{
+		"A": texts("Tree", "Apple", "Pear", "Cherry", "Apple", "Pear", "Apple", "",
+			"Tree", "=Apple", "=Pear", "", "Tree", "=Apple"),
+		"B": append(append([]Result{MakeStringResult("Height")}, numbers(18, 12, 13, 14, 9, 8)...),
+			texts("", "Height", ">10", "", "", "Above")...),
+		"C": append(append([]Result{MakeStringResult("Age")}, numbers(20, 12, 14, 15, 8, 9)...),
+			texts("", "Age")...),
+		"D": append(append([]Result{MakeStringResult("Yield")}, numbers(14, 10, 9, 10, 8, 6)...),
+			texts("", "Yield")...),
+		"E": append(append([]Result{MakeStringResult("Profit")}, numbers(105, 96, 105, 75, 76.8, 45)...),
+			texts("", "Profit")...),
+		"F": texts("", "", "", "", "", "", "", "", "Height", "<16"),
+	})
+	// the cached value of the computed criterion for the first record
+	cells.cells["B14"] = MakeBoolResult(true)
+	return formulaCellContext{cells, map[string]string{"B14": "E2>AVERAGE($E$2:$E$7)"}}
+}
+
+func TestDatabaseFunctions(t *testing.T) {
+	testFormulas(t, dbContext(), []formulaCase{
+		{"DCOUNT(A1:E7,\"Age\",A9:F10)", "1"},
+		{"DCOUNTA(A1:E7,\"Profit\",A9:F10)", "1"},
+		{"DMAX(A1:E7,\"Profit\",A9:A11)", "105"},
+		{"DMIN(A1:E7,\"Profit\",A9:B10)", "75"},
+		{"DSUM(A1:E7,\"Profit\",A9:A10)", "225"},
+		{"DSUM(A1:E7,\"Profit\",A9:F10)", "75"},
+		{"DPRODUCT(A1:E7,\"Yield\",A9:F10)", "10"},
+		{"DAVERAGE(A1:E7,\"Yield\",A9:B10)", "12"},
+		{"DAVERAGE(A1:E7,3,A1:E7)", "13"},
+		{"DSTDEV(A1:E7,\"Yield\",A9:A11)", "2.966479395"},
+		{"DSTDEVP(A1:E7,\"Yield\",A9:A11)", "2.653299832"},
+		{"DVAR(A1:E7,\"Yield\",A9:A11)", "8.8"},
+		{"DVARP(A1:E7,\"Yield\",A9:A11)", "7.04"},
+		{"DGET(A1:E7,\"Yield\",A9:A10)", "#NUM!"},
+		{"DGET(A1:E7,\"Yield\",A9:F10)", "10"},
+
+		// labels and fields are matched ignoring case, fields can be numbers
+		{"DSUM(A1:E7,\"profit\",A9:A10)", "225"},
+		{"DSUM(A1:E7,5,A9:A10)", "225"},
+		// the field can be omitted when counting records
+		{"DCOUNT(A1:E7,,A9:A11)", "5"},
+		{"DCOUNTA(A1:E7,,A9:F10)", "1"},
+		// text criteria without an operator match the beginning of values
+		{"DCOUNT(A1:E7,\"Age\",{\"Tree\";\"P\"})", "2"},
+		{"DCOUNT(A1:E7,\"Age\",{\"Tree\";\"=P\"})", "0"},
+		{"DCOUNT(A1:E7,\"Age\",{\"Tree\",\"Height\";\"Apple\",\">10\";\"Pear\",\"<10\"})", "3"},
+		// an empty criteria row matches every record
+		{"DCOUNT(A1:E7,\"Age\",C9:C10)", "6"},
+	})
+}
+
+func TestDatabaseOrCriteria(t *testing.T) {
+	testFormulas(t, dbContext(), []formulaCase{
+		// each row of the criteria is an alternative
+		{"DSUM(A1:E7,\"Profit\",A9:A11)", "397.8"},
+		{"DCOUNT(A1:E7,\"Profit\",A9:A11)", "5"},
+		// conditions in the same row must all match
+		{"DSUM(A1:E7,\"Profit\",A9:F11)", "247.8"},
+		{"DMAX(A1:E7,\"Height\",A9:F11)", "14"},
+		{"DMIN(A1:E7,\"Height\",A9:F11)", "9"},
+	})
+}
+
+func TestDatabaseComputedCriteria(t *testing.T) {
+	testFormulas(t, dbContext(), []formulaCase{
+		// the formula refers to the first record and is moved down for the
+		// others while the absolute references stay fixed
+		{"DSUM(A1:E7,\"Profit\",B13:B14)", "306"},
+		{"DCOUNT(A1:E7,\"Profit\",B13:B14)", "3"},
+		{"DSUM(A1:E7,\"Profit\",A13:B14)", "105"},
+		{"DGET(A1:E7,\"Height\",A13:B14)", "18"},
+		{"LET(x,1,DSUM(A1:E7,\"Profit\",B13:B14)+x)", "307"},
+	})
+
+	// without formulas the label isn't known
+	testFormulas(t, dbContext().cellContext, []formulaCase{
+		{"DSUM(A1:E7,\"Profit\",B13:B14)", "#VALUE!"},
+	})
+}
+
+func TestDatabaseErrors(t *testing.T) {
+	testFormulas(t, dbContext(), []formulaCase{
+		{"DSUM(A1:E7,\"Missing\",A9:A10)", "#VALUE!"},
+		{"DSUM(A1:E7,6,A9:A10)", "#VALUE!"},
+		{"DSUM(A1:E7,0,A9:A10)", "#VALUE!"},
+		{"DSUM(A1:E7,,A9:A10)", "#VALUE!"},
+		{"DSUM(A1:E7,\"Profit\",A9)", "#VALUE!"},
+		{"DSUM(A1:E7,\"Profit\")", "#VALUE!"},
+		{"DSUM(A1:E7,\"Profit\",{\"Missing\";1})", "#VALUE!"},
+		{"DGET(A1:E7,\"Yield\",{\"Tree\";\"=Plum\"})", "#VALUE!"},
+		{"DAVERAGE(A1:E7,\"Yield\",{\"Tree\";\"=Plum\"})", "#DIV/0!"},
+		{"DSTDEV(A1:E7,\"Yield\",{\"Tree\";\"=Cherry\"})", "#DIV/0!"},
+		{"DVARP(A1:E7,\"Yield\",{\"Tree\";\"=Plum\"})", "#DIV/0!"},
+		{"DSUM(A1:E7,\"Profit\",{\"Tree\";\"=Plum\"})", "0"},
+		{"DMAX(A1:E7,\"Profit\",{\"Tree\";\"=Plum\"})", "0"},
+		{"DSUM(A1:E7,NA(),A9:A10)", "#N/A"},
+	})
+}
@@ -0,0 +1,566 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+func init() {
+	RegisterFunction("HOUR", Hour)
+	RegisterFunction("SECOND", Second)
+	RegisterFunctionComplex("WEEKDAY", Weekday)
+	RegisterFunctionComplex("WEEKNUM", WeekNum)
+	RegisterFunctionComplex("ISOWEEKNUM", IsoWeekNum)
+	RegisterFunctionComplex("_xlfn.ISOWEEKNUM", IsoWeekNum)
+	RegisterFunctionComplex("WORKDAY", Workday)
+	RegisterFunctionComplex("WORKDAY.INTL", WorkdayIntl)
+	RegisterFunctionComplex("_xlfn.WORKDAY.INTL", WorkdayIntl)
+	RegisterFunctionComplex("NETWORKDAYS", NetworkDays)
+	RegisterFunctionComplex("NETWORKDAYS.INTL", NetworkDaysIntl)
+	RegisterFunctionComplex("_xlfn.NETWORKDAYS.INTL", NetworkDaysIntl)
+	RegisterFunctionComplex("DAYS360", Days360)
+}
+
+// epoch returns the time that date serial numbers of the context are relative
+// to, defaulting to the 1900 date system.
+func epoch(ctx Context) time.Time {
+	e := ctx.GetEpoch()
+	if e.IsZero() {
+		return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(e.Year(), e.Month(), e.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// date1904 is the number of days between the epochs of the 1900 and the 1904
+// date systems.
+const date1904 = 1462
+
+// is1904 returns true if the context uses the 1904 date system.
+func is1904(ctx Context) bool {
+	return epoch(ctx).Year() == 1904
+}
+
+// toEpoch converts a serial number of the 1900 date system, which the date
+// functions are implemented in, to the date system of the context.  Times
+// without a date are left as they are.
+func toEpoch(ctx Context, v float64) float64 {
+	if v < 1 || !is1904(ctx) {
+		return v
+	}
+	return v - date1904
+}
+
+// fromEpoch converts a serial number of the date system of the context to the
+// 1900 date system.
+func fromEpoch(ctx Context, v float64) float64 {
+	if !is1904(ctx) {
+		return v
+	}
+	return v + date1904
+}
+
+// inEpoch wraps a date function implemented in the 1900 date system so that
+// it works in the date system of the context.  The first dates arguments and,
+// if result is true, the result are date serial numbers to convert.
+func inEpoch(fn FunctionComplex, dates int, result bool) FunctionComplex {
+	return func(ctx Context, ev Evaluator, args []Result) Result {
+		if !is1904(ctx) {
+			return fn(ctx, ev, args)
+		}
+		conv := append([]Result(nil), args...)
+		for i := 0; i < dates && i < len(conv); i++ {
+			if conv[i].Type == ResultTypeNumber && !conv[i].IsBoolean {
+				conv[i] = MakeNumberResult(fromEpoch(ctx, conv[i].ValueNumber))
+			}
+		}
+		res := fn(ctx, ev, conv)
+		if !result || res.Type != ResultTypeNumber {
+			return res
+		}
+		v := toEpoch(ctx, res.ValueNumber)
+		if v < 0 {
+			return MakeErrorResultType(ErrorTypeNum, "date is before 1904")
+		}
+		return MakeNumberResult(v)
+	}
+}
+
+// complexFunction adapts a function that doesn't need the context.
+func complexFunction(fn Function) FunctionComplex {
+	return func(ctx Context, ev Evaluator, args []Result) Result {
+		return fn(args)
+	}
+}
+
+// serialDate returns the date of a serial number.  For the 1900 date system
+// the serial numbers before March 1900 are off by a day, as Excel treats 1900
+// as a leap year, but the days of the week match those Excel reports.
+func serialDate(ctx Context, v float64) time.Time {
+	return epoch(ctx).AddDate(0, 0, int(math.Floor(v)))
+}
+
+// weekday returns the day of the week of a serial number.
+func weekday(ctx Context, v float64) time.Weekday {
+	return serialDate(ctx, v).Weekday()
+}
+
+// dateArg converts an argument to a date serial number of the date system of
+// the context.  Strings are parsed as by DATEVALUE.
+func dateArg(ctx Context, name string, r Result) (float64, Result) {
+	switch r.Type {
+	case ResultTypeEmpty:
+		return 0, MakeEmptyResult()
+	case ResultTypeNumber:
+		if r.IsBoolean {
+			return 0, MakeErrorResult("Incorrect argument for " + name)
+		}
+		if r.ValueNumber < 0 {
+			return 0, MakeErrorResultType(ErrorTypeNum, "Incorrect argument for "+name)
+		}
+		return r.ValueNumber, MakeEmptyResult()
+	case ResultTypeString:
+		d := localeDateValue(ctx, nil, []Result{r})
+		if d.Type == ResultTypeError {
+			return 0, MakeErrorResult("Incorrect argument for " + name)
+		}
+		return toEpoch(ctx, d.ValueNumber), MakeEmptyResult()
+	case ResultTypeError:
+		return 0, r
+	}
+	return 0, MakeErrorResult("Incorrect argument for " + name)
+}
+
+// timeOfDay returns the time of a serial number or time string in seconds,
+// rounded to the nearest second.
+func timeOfDay(name string, r Result) (int, Result) {
+	var v float64
+	switch r.Type {
+	case ResultTypeEmpty:
+		return 0, MakeEmptyResult()
+	case ResultTypeNumber:
+		if r.ValueNumber < 0 {
+			return 0, MakeErrorResultType(ErrorTypeNum, "Incorrect argument for "+name)
+		}
+		v = r.ValueNumber
+	case ResultTypeString:
+		t := TimeValue([]Result{r})
+		if t.Type == ResultTypeError {
+			return 0, MakeErrorResult("Incorrect argument for " + name)
+		}
+		v = t.ValueNumber
+	case ResultTypeError:
+		return 0, r
+	default:
+		return 0, MakeErrorResult("Incorrect argument for " + name)
+	}
+	_, frac := math.Modf(v)
+	return int(math.Round(frac*86400)) % 86400, MakeEmptyResult()
+}
+
+// Hour is an implementation of the Excel HOUR() function.
+func Hour(args []Result) Result {
+	if len(args) != 1 {
+		return MakeErrorResult("HOUR requires one argument")
+	}
+	s, res := timeOfDay("HOUR", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	return MakeNumberResult(float64(s / 3600))
+}
+
+// Second is an implementation of the Excel SECOND() function.
+func Second(args []Result) Result {
+	if len(args) != 1 {
+		return MakeErrorResult("SECOND requires one argument")
+	}
+	s, res := timeOfDay("SECOND", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	return MakeNumberResult(float64(s % 60))
+}
+
+// weekStart maps the return types of WEEKDAY and WEEKNUM to the first day of
+// the week.
+var weekStart = map[int]time.Weekday{
+	1:  time.Sunday,
+	2:  time.Monday,
+	11: time.Monday,
+	12: time.Tuesday,
+	13: time.Wednesday,
+	14: time.Thursday,
+	15: time.Friday,
+	16: time.Saturday,
+	17: time.Sunday,
+}
+
+// Weekday is an implementation of the Excel WEEKDAY() function.
+func Weekday(ctx Context, ev Evaluator, args []Result) Result {
+	if len(args) != 1 && len(args) != 2 {
+		return MakeErrorResult("WEEKDAY requires one or two arguments")
+	}
+	d, res := dateArg(ctx, "WEEKDAY", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	typ := 1
+	if len(args) == 2 && args[1].Type != ResultTypeEmpty {
+		n := args[1].AsNumber()
+		if n.Type != ResultTypeNumber {
+			return MakeErrorResult("WEEKDAY requires return type to be a number")
+		}
+		typ = int(n.ValueNumber)
+	}
+	wd := int(weekday(ctx, d))
+	if typ == 3 {
+		return MakeNumberResult(float64((wd + 6) % 7))
+	}
+	start, ok := weekStart[typ]
+	if !ok {
+		return MakeErrorResultType(ErrorTypeNum, "WEEKDAY has an invalid return type")
+	}
+	return MakeNumberResult(float64((wd-int(start)+7)%7 + 1))
+}
+
+// WeekNum is an implementation of the Excel WEEKNUM() function.  The week
+// containing January 1 is the first week of the year, except for return type
+// 21 which uses ISO 8601 week numbers.
+func WeekNum(ctx Context, ev Evaluator, args []Result) Result {
+	if len(args) != 1 && len(args) != 2 {
+		return MakeErrorResult("WEEKNUM requires one or two arguments")
+	}
+	d, res := dateArg(ctx, "WEEKNUM", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	typ := 1
+	if len(args) == 2 && args[1].Type != ResultTypeEmpty {
+		n := args[1].AsNumber()
+		if n.Type != ResultTypeNumber {
+			return MakeErrorResult("WEEKNUM requires return type to be a number")
+		}
+		typ = int(n.ValueNumber)
+	}
+	t := serialDate(ctx, d)
+	if typ == 21 {
+		_, w := t.ISOWeek()
+		return MakeNumberResult(float64(w))
+	}
+	start, ok := weekStart[typ]
+	if !ok {
+		return MakeErrorResultType(ErrorTypeNum, "WEEKNUM has an invalid return type")
+	}
+	jan1 := time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(jan1.Weekday()) - int(start) + 7) % 7
+	return MakeNumberResult(float64((t.YearDay()-1+offset)/7 + 1))
+}
+
+// IsoWeekNum is an implementation of the Excel ISOWEEKNUM() function.
+func IsoWeekNum(ctx Context, ev Evaluator, args []Result) Result {
+	if len(args) != 1 {
+		return MakeErrorResult("ISOWEEKNUM requires one argument")
+	}
+	d, res := dateArg(ctx, "ISOWEEKNUM", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	_, w := serialDate(ctx, d).ISOWeek()
+	return MakeNumberResult(float64(w))
+}
+
+// weekendMask is the set of days of the week which aren't work days.
+type weekendMask [7]bool
+
+// weekendNumbers maps the weekend numbers of WORKDAY.INTL and NETWORKDAYS.INTL
+// to the days of the weekend.
+var weekendNumbers = map[int][]time.Weekday{
+	1:  {time.Saturday, time.Sunday},
+	2:  {time.Sunday, time.Monday},
+	3:  {time.Monday, time.Tuesday},
+	4:  {time.Tuesday, time.Wednesday},
+	5:  {time.Wednesday, time.Thursday},
+	6:  {time.Thursday, time.Friday},
+	7:  {time.Friday, time.Saturday},
+	11: {time.Sunday},
+	12: {time.Monday},
+	13: {time.Tuesday},
+	14: {time.Wednesday},
+	15: {time.Thursday},
+	16: {time.Friday},
+	17: {time.Saturday},
+}
+
+// parseWeekend parses the weekend argument of WORKDAY.INTL and
+// NETWORKDAYS.INTL, either a weekend number or a string of seven 0s and 1s
+// starting on Monday where 1 marks a weekend day (e.g. "0000011").
+func parseWeekend(name string, r Result) (weekendMask, Result) {
+	m := weekendMask{}
+	switch r.Type {
+	case ResultTypeEmpty:
+		m[time.Saturday], m[time.Sunday] = true, true
+		return m, MakeEmptyResult()
+	case ResultTypeString:
+		s := r.ValueString
+		if len(s) != 7 || s == "1111111" {
+			return m, MakeErrorResult(name + " has an invalid weekend string")
+		}
+		for i, c := range s {
+			switch c {
+			case '0':
+			case '1':
+				m[(i+1)%7] = true
+			default:
+				return m, MakeErrorResult(name + " has an invalid weekend string")
+			}
+		}
+		return m, MakeEmptyResult()
+	case ResultTypeNumber:
+		days, ok := weekendNumbers[int(r.ValueNumber)]
+		if !ok {
+			return m, MakeErrorResultType(ErrorTypeNum, name+" has an invalid weekend number")
+		}
+		for _, d := range days {
+			m[d] = true
+		}
+		return m, MakeEmptyResult()
+	case ResultTypeError:
+		return m, r
+	}
+	return m, MakeErrorResult(name + " has an invalid weekend argument")
+}
+
+// parseHolidays returns the serial numbers of the dates in a holidays
+// argument, which may be a single date or a range or array of dates.
+func parseHolidays(ctx Context, name string, r Result) (map[int]struct{}, Result) {
+	values := []Result{r}
+	if r.Type == ResultTypeArray || r.Type == ResultTypeList {
+		values = r.ListValues()
+	}
+	ret := map[int]struct{}{}
+	for _, v := range values {
+		if v.Type == ResultTypeEmpty {
+			continue
+		}
+		d, res := dateArg(ctx, name, v)
+		if res.Type == ResultTypeError {
+			return nil, res
+		}
+		ret[int(d)] = struct{}{}
+	}
+	return ret, MakeEmptyResult()
+}
+
+// workdays holds the parsed arguments of the work day functions.
+type workdays struct {
+	weekend  weekendMask
+	holidays map[int]struct{}
+	first    time.Weekday
+}
+
+// isWorkday returns true if a serial number is neither a weekend day nor a
+// holiday.
+func (w workdays) isWorkday(d int) bool {
+	if w.weekend[(int(w.first)+d%7+7)%7] {
+		return false
+	}
+	_, holiday := w.holidays[d]
+	return !holiday
+}
+
+// parseWorkdays parses the weekend and holidays arguments of the work day
+// functions.  The arguments are optional so either may be nil.
+func parseWorkdays(ctx Context, name string, weekend, holidays *Result) (workdays, Result) {
+	w := workdays{first: weekday(ctx, 0)}
+	res := MakeEmptyResult()
+	if weekend == nil {
+		weekend = &res
+	}
+	if w.weekend, res = parseWeekend(name, *weekend); res.Type == ResultTypeError {
+		return w, res
+	}
+	if holidays == nil {
+		w.holidays = map[int]struct{}{}
+		return w, MakeEmptyResult()
+	}
+	w.holidays, res = parseHolidays(ctx, name, *holidays)
+	return w, res
+}
+
+// Workday is an implementation of the Excel WORKDAY() function which returns
+// the date a number of work days before or after a start date, skipping
+// Saturdays, Sundays and holidays.
+func Workday(ctx Context, ev Evaluator, args []Result) Result {
+	if len(args) != 2 && len(args) != 3 {
+		return MakeErrorResult("WORKDAY requires two or three arguments")
+	}
+	var holidays *Result
+	if len(args) == 3 {
+		holidays = &args[2]
+	}
+	return workday(ctx, "WORKDAY", args[0], args[1], nil, holidays)
+}
+
+// WorkdayIntl is an implementation of the Excel WORKDAY.INTL() function which
+// is like WORKDAY with a configurable weekend.
+func WorkdayIntl(ctx Context, ev Evaluator, args []Result) Result {
+	if len(args) < 2 || len(args) > 4 {
+		return MakeErrorResult("WORKDAY.INTL requires two to four arguments")
+	}
+	var weekend, holidays *Result
+	if len(args) > 2 {
+		weekend = &args[2]
+	}
+	if len(args) > 3 {
+		holidays = &args[3]
+	}
+	return workday(ctx, "WORKDAY.INTL", args[0], args[1], weekend, holidays)
+}
+
+func workday(ctx Context, name string, start, days Result, weekend, holidays *Result) Result {
+	s, res := dateArg(ctx, name, start)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	n := days.AsNumber()
+	if n.Type != ResultTypeNumber {
+		return MakeErrorResult(name + " requires days to be a number")
+	}
+	w, res := parseWorkdays(ctx, name, weekend, holidays)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	d := int(s)
+	count := int(n.ValueNumber)
+	step := 1
+	if count < 0 {
+		step, count = -1, -count
+	}
+	for count > 0 {
+		d += step
+		if d < 0 {
+			return MakeErrorResultType(ErrorTypeNum, name+" result is before the first date")
+		}
+		if w.isWorkday(d) {
+			count--
+		}
+	}
+	return MakeNumberResult(float64(d))
+}
+
+// NetworkDays is an implementation of the Excel NETWORKDAYS() function which
+// returns the number of work days between two dates inclusive, skipping
+// Saturdays, Sundays and holidays.
+func NetworkDays(ctx Context, ev Evaluator, args []Result) Result {
+	if len(args) != 2 && len(args) != 3 {
+		return MakeErrorResult("NETWORKDAYS requires two or three arguments")
+	}
+	var holidays *Result
+	if len(args) == 3 {
+		holidays = &args[2]
+	}
+	return networkDays(ctx, "NETWORKDAYS", args[0], args[1], nil, holidays)
+}
+
+// NetworkDaysIntl is an implementation of the Excel NETWORKDAYS.INTL()
+// function which is like NETWORKDAYS with a configurable weekend.
+func NetworkDaysIntl(ctx Context, ev Evaluator, args []Result) Result {
+	if len(args) < 2 || len(args) > 4 {
+		return MakeErrorResult("NETWORKDAYS.INTL requires two to four arguments")
+	}
+	var weekend, holidays *Result
+	if len(args) > 2 {
+		weekend = &args[2]
+	}
+	if len(args) > 3 {
+		holidays = &args[3]
+	}
+	return networkDays(ctx, "NETWORKDAYS.INTL", args[0], args[1], weekend, holidays)
+}
+
+func networkDays(ctx Context, name string, start, end Result, weekend, holidays *Result) Result {
+	s, res := dateArg(ctx, name, start)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	e, res := dateArg(ctx, name, end)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	w, res := parseWorkdays(ctx, name, weekend, holidays)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	from, to, sign := int(s), int(e), 1
+	if from > to {
+		from, to, sign = to, from, -1
+	}
+	count := 0
+	for d := from; d <= to; d++ {
+		if w.isWorkday(d) {
+			count++
+		}
+	}
+	return MakeNumberResult(float64(sign * count))
+}
+
+// Days360 is an implementation of the Excel DAYS360() function which returns
+// the number of days between two dates based on a 360 day year.  If the method
+// is TRUE the European method is used, otherwise the US (NASD) method.
+func Days360(ctx Context, ev Evaluator, args []Result) Result {
+	if len(args) != 2 && len(args) != 3 {
+		return MakeErrorResult("DAYS360 requires two or three arguments")
+	}
+	s, res := dateArg(ctx, "DAYS360", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	e, res := dateArg(ctx, "DAYS360", args[1])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	european := false
+	if len(args) == 3 {
+		m := args[2]
+		if m.Type == ResultTypeString {
+			b, err := strconv.ParseBool(m.ValueString)
+			if err != nil {
+				return MakeErrorResult("DAYS360 requires method to be a boolean")
+			}
+			european = b
+		} else {
+			european = m.AsNumber().ValueNumber != 0
+		}
+	}
+	st, et := serialDate(ctx, s), serialDate(ctx, e)
+	sy, sm, sd := st.Date()
+	ey, em, ed := et.Date()
+	if european {
+		if sd == 31 {
+			sd = 30
+		}
+		if ed == 31 {
+			ed = 30
+		}
+	} else {
+		if sd == 31 || sm == time.February && isLastDayOfMonth(st) {
+			sd = 30
+		}
+		if ed == 31 && sd == 30 {
+			ed = 30
+		}
+	}
+	return MakeNumberResult(float64(360*(ey-sy) + 30*(int(em)-int(sm)) + ed - sd))
+}
+
+func isLastDayOfMonth(t time.Time) bool {
+	return t.AddDate(0, 0, 1).Day() == 1
+}
@@ -0,0 +1,49 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"testing"
+	"time"
+)
+
+// date1904Context is a context for a workbook using the 1904 date system.
+type date1904Context struct {
+	Context
+}
+
+func (date1904Context) GetEpoch() time.Time {
+	return time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDate1904(t *testing.T) {
+	ctx := date1904Context{InvalidReferenceContext}
+	ev := NewEvaluator()
+	for _, tc := range []struct {
+		formula string
+		exp     string
+	}{
+		{"DATE(2023,3,15)", "43538"},
+		{"DATEVALUE(\"2023-03-15\")", "43538"},
+		{"EOMONTH(DATE(2023,3,15),0)", "43554"},
+		{"EDATE(DATE(2023,3,15),1)", "43569"},
+		{"DAY(DATE(2023,3,15))", "15"},
+		{"MONTH(DATE(2023,3,15))", "3"},
+		{"YEAR(DATE(2023,3,15))", "2023"},
+		{"WEEKDAY(DATE(2023,3,15))", "4"},
+		{"WEEKDAY(\"2024-01-01\")", "2"},
+		{"WEEKDAY(0)", "6"},
+		{"DAY(0)", "1"},
+		{"DATEDIF(DATE(2020,1,1),DATE(2023,3,15),\"m\")", "38"},
+		{"DATE(1903,12,31)", "#NUM!"},
+	} {
+		if got := ev.Eval(ctx, tc.formula).Value(); got != tc.exp {
+			t.Errorf("%s: expected %s, got %s", tc.formula, tc.exp, got)
+		}
+	}
+}
@@ -0,0 +1,664 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/unidoc/unioffice/internal/wildcard"
+)
+
+func init() {
+	RegisterFunction("FILTER", Filter)
+	RegisterFunction("_xlfn._xlws.FILTER", Filter)
+	RegisterFunction("SORT", Sort)
+	RegisterFunction("_xlfn._xlws.SORT", Sort)
+	RegisterFunction("SORTBY", SortBy)
+	RegisterFunction("_xlfn.SORTBY", SortBy)
+	RegisterFunction("UNIQUE", Unique)
+	RegisterFunction("_xlfn.UNIQUE", Unique)
+	RegisterFunction("SEQUENCE", Sequence)
+	RegisterFunction("_xlfn.SEQUENCE", Sequence)
+	RegisterFunction("XLOOKUP", XLookup)
+	RegisterFunction("_xlfn.XLOOKUP", XLookup)
+	RegisterFunction("XMATCH", XMatch)
+	RegisterFunction("_xlfn.XMATCH", XMatch)
+	RegisterFunctionComplex("ANCHORARRAY", AnchorArray)
+	RegisterFunctionComplex("_xlfn.ANCHORARRAY", AnchorArray)
+}
+
+// arrayRows returns the values of a result as rows, treating a list as a
+// single row and a single value as a one by one array.
+func arrayRows(r Result) [][]Result {
+	switch r.Type {
+	case ResultTypeArray:
+		return r.ValueArray
+	case ResultTypeList:
+		return [][]Result{r.ValueList}
+	}
+	return [][]Result{{r}}
+}
+
+// transposeRows swaps the rows and columns of an array.
+func transposeRows(rows [][]Result) [][]Result {
+	if len(rows) == 0 {
+		return rows
+	}
+	ret := make([][]Result, len(rows[0]))
+	for c := range ret {
+		ret[c] = make([]Result, len(rows))
+		for r := range rows {
+			if c < len(rows[r]) {
+				ret[c][r] = rows[r][c]
+			} else {
+				ret[c][r] = MakeEmptyResult()
+			}
+		}
+	}
+	return ret
+}
+
+// makeRowsResult converts rows to a result, returning a single value for a one
+// by one array.
+func makeRowsResult(rows [][]Result) Result {
+	if len(rows) == 1 && len(rows[0]) == 1 {
+		return rows[0][0]
+	}
+	return MakeArrayResult(rows)
+}
+
+// vector returns the values of a one dimensional array and whether they were
+// laid out in a row rather than a column.
+func vector(r Result) ([]Result, bool, bool) {
+	rows := arrayRows(r)
+	switch {
+	case len(rows) == 1:
+		return rows[0], true, true
+	case len(rows) > 0 && len(rows[0]) == 1:
+		return transposeRows(rows)[0], false, true
+	}
+	return nil, false, false
+}
+
+// boolArg converts an optional boolean argument.
+func boolArg(args []Result, i int, def bool) bool {
+	if i >= len(args) || args[i].Type == ResultTypeEmpty {
+		return def
+	}
+	n := args[i].AsNumber()
+	return n.Type == ResultTypeNumber && n.ValueNumber != 0
+}
+
+// numberArg converts an optional numeric argument.
+func numberArg(name string, args []Result, i int, def float64) (float64, Result) {
+	if i >= len(args) || args[i].Type == ResultTypeEmpty {
+		return def, MakeEmptyResult()
+	}
+	return statNumberArg(name, args[i])
+}
+
+// isTruthy returns true if a value is a boolean TRUE or a non-zero number.
+func isTruthy(r Result) bool {
+	n := r.AsNumber()
+	return n.Type == ResultTypeNumber && n.ValueNumber != 0
+}
+
+// Sequence implements the SEQUENCE function which returns an array of
+// sequential numbers.
+func Sequence(args []Result) Result {
+	if len(args) < 1 || len(args) > 4 {
+		return MakeErrorResult("SEQUENCE requires one to four arguments")
+	}
+	var vs [4]float64
+	for i, def := range []float64{0, 1, 1, 1} {
+		v, res := numberArg("SEQUENCE", args, i, def)
+		if res.Type == ResultTypeError {
+			return res
+		}
+		vs[i] = v
+	}
+	rows, cols := int(vs[0]), int(vs[1])
+	if rows < 1 || cols < 1 {
+		return MakeErrorResultType(ErrorTypeCalc, "SEQUENCE requires rows and columns to be positive")
+	}
+	arr := make([][]Result, rows)
+	for r := range arr {
+		arr[r] = make([]Result, cols)
+		for c := range arr[r] {
+			arr[r][c] = MakeNumberResult(vs[2] + vs[3]*float64(r*cols+c))
+		}
+	}
+	return makeRowsResult(arr)
+}
+
+// Filter implements the FILTER function which returns the rows or columns of
+// an array for which the include array is true.
+func Filter(args []Result) Result {
+	if len(args) != 2 && len(args) != 3 {
+		return MakeErrorResult("FILTER requires two or three arguments")
+	}
+	for _, a := range args[:2] {
+		if a.Type == ResultTypeError {
+			return a
+		}
+	}
+	rows := arrayRows(args[0])
+	include, horizontal, ok := vector(args[1])
+	if !ok {
+		return MakeErrorResult("FILTER requires include to be a single row or column")
+	}
+	if horizontal && len(rows) > 1 || len(rows) == 1 && len(include) == len(rows[0]) && len(rows[0]) > 1 {
+		rows = transposeRows(rows)
+		horizontal = true
+	}
+	if len(include) != len(rows) {
+		return MakeErrorResult("FILTER requires include to match the size of the array")
+	}
+	ret := [][]Result{}
+	for i, inc := range include {
+		if inc.Type == ResultTypeError {
+			return inc
+		}
+		if isTruthy(inc) {
+			ret = append(ret, rows[i])
+		}
+	}
+	if len(ret) == 0 {
+		if len(args) == 3 {
+			return args[2]
+		}
+		return MakeErrorResultType(ErrorTypeCalc, "FILTER returned no values")
+	}
+	if horizontal {
+		ret = transposeRows(ret)
+	}
+	return makeRowsResult(ret)
+}
+
+// valueRank returns the position of the kind of a value in the sort order:
+// numbers, then text, then booleans, then errors and finally blanks.
+func valueRank(r Result) int {
+	switch {
+	case r.Type == ResultTypeNumber && !r.IsBoolean:
+		return 0
+	case r.Type == ResultTypeString:
+		return 1
+	case r.Type == ResultTypeNumber:
+		return 2
+	case r.Type == ResultTypeError:
+		return 3
+	}
+	return 4
+}
+
+// compareValues orders values the way Excel sorts them, comparing text
+// without regard to case.
+func compareValues(a, b Result) int {
+	ra, rb := valueRank(a), valueRank(b)
+	if ra != rb {
+		return ra - rb
+	}
+	switch ra {
+	case 0, 2:
+		if a.ValueNumber < b.ValueNumber {
+			return -1
+		} else if a.ValueNumber > b.ValueNumber {
+			return 1
+		}
+	case 1:
+		return strings.Compare(strings.ToLower(a.ValueString), strings.ToLower(b.ValueString))
+	}
+	return 0
+}
+
+// sortKey is a key to sort rows by.
+type sortKey struct {
+	values     []Result
+	descending bool
+}
+
+// sortRows sorts rows stably by multiple keys.  Blank values sort last in
+// either order.
+func sortRows(rows [][]Result, keys []sortKey) [][]Result {
+	idx := make([]int, len(rows))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		for _, k := range keys {
+			a, b := k.values[idx[i]], k.values[idx[j]]
+			ae, be := a.Type == ResultTypeEmpty, b.Type == ResultTypeEmpty
+			if ae || be {
+				if ae != be {
+					return be
+				}
+				continue
+			}
+			c := compareValues(a, b)
+			if c == 0 {
+				continue
+			}
+			return (c < 0) != k.descending
+		}
+		return false
+	})
+	ret := make([][]Result, len(rows))
+	for i, j := range idx {
+		ret[i] = rows[j]
+	}
+	return ret
+}
+
+// sortOrder converts a sort order argument, 1 for ascending and -1 for
+// descending.
+func sortOrder(name string, args []Result, i int) (bool, Result) {
+	order, res := numberArg(name, args, i, 1)
+	if res.Type == ResultTypeError {
+		return false, res
+	}
+	if order != 1 && order != -1 {
+		return false, MakeErrorResult(name + " requires sort order to be 1 or -1")
+	}
+	return order == -1, MakeEmptyResult()
+}
+
+// Sort implements the SORT function which sorts the rows or columns of an
+// array.
+func Sort(args []Result) Result {
+	if len(args) < 1 || len(args) > 4 {
+		return MakeErrorResult("SORT requires one to four arguments")
+	}
+	if args[0].Type == ResultTypeError {
+		return args[0]
+	}
+	rows := arrayRows(args[0])
+	index, res := numberArg("SORT", args, 1, 1)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	descending, res := sortOrder("SORT", args, 2)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	byCol := boolArg(args, 3, false)
+	if byCol {
+		rows = transposeRows(rows)
+	}
+	col := int(index) - 1
+	if len(rows) == 0 || col < 0 || col >= len(rows[0]) {
+		return MakeErrorResult("SORT requires sort index to be within the array")
+	}
+	key := sortKey{descending: descending}
+	for _, r := range rows {
+		key.values = append(key.values, r[col])
+	}
+	rows = sortRows(rows, []sortKey{key})
+	if byCol {
+		rows = transposeRows(rows)
+	}
+	return makeRowsResult(rows)
+}
+
+// SortBy implements the SORTBY function which sorts an array by the values of
+// other arrays.
+func SortBy(args []Result) Result {
+	if len(args) < 2 {
+		return MakeErrorResult("SORTBY requires at least two arguments")
+	}
+	if args[0].Type == ResultTypeError {
+		return args[0]
+	}
+	rows := arrayRows(args[0])
+	keys := []sortKey{}
+	byCol := false
+	for i := 1; i < len(args); i += 2 {
+		if args[i].Type == ResultTypeError {
+			return args[i]
+		}
+		values, horizontal, ok := vector(args[i])
+		if !ok {
+			return MakeErrorResult("SORTBY requires each by array to be a single row or column")
+		}
+		if i == 1 {
+			byCol = horizontal && len(values) > 1
+		} else if horizontal != byCol && len(values) > 1 {
+			return MakeErrorResult("SORTBY requires by arrays of the same orientation")
+		}
+		descending, res := sortOrder("SORTBY", args, i+1)
+		if res.Type == ResultTypeError {
+			return res
+		}
+		keys = append(keys, sortKey{values, descending})
+	}
+	if byCol {
+		rows = transposeRows(rows)
+	}
+	for _, k := range keys {
+		if len(k.values) != len(rows) {
+			return MakeErrorResult("SORTBY requires by arrays to match the size of the array")
+		}
+	}
+	rows = sortRows(rows, keys)
+	if byCol {
+		rows = transposeRows(rows)
+	}
+	return makeRowsResult(rows)
+}
+
+// rowKey returns a case insensitive key for the values of a row.
+func rowKey(row []Result) string {
+	b := strings.Builder{}
+	for _, v := range row {
+		b.WriteByte(byte(v.Type))
+		if v.IsBoolean {
+			b.WriteByte('b')
+		}
+		b.WriteString(strings.ToLower(v.Value()))
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// Unique implements the UNIQUE function which returns the distinct rows or
+// columns of an array.
+func Unique(args []Result) Result {
+	if len(args) < 1 || len(args) > 3 {
+		return MakeErrorResult("UNIQUE requires one to three arguments")
+	}
+	if args[0].Type == ResultTypeError {
+		return args[0]
+	}
+	rows := arrayRows(args[0])
+	byCol := boolArg(args, 1, false)
+	exactlyOnce := boolArg(args, 2, false)
+	if byCol {
+		rows = transposeRows(rows)
+	}
+	counts := map[string]int{}
+	for _, r := range rows {
+		counts[rowKey(r)]++
+	}
+	ret := [][]Result{}
+	for _, r := range rows {
+		k := rowKey(r)
+		if counts[k] == 0 || (exactlyOnce && counts[k] > 1) {
+			continue
+		}
+		counts[k] = 0
+		ret = append(ret, r)
+	}
+	if len(ret) == 0 {
+		return MakeErrorResultType(ErrorTypeCalc, "UNIQUE returned no values")
+	}
+	if byCol {
+		ret = transposeRows(ret)
+	}
+	return makeRowsResult(ret)
+}
+
+// lookupMatch returns the index of the value in values matching lookup, or -1
+// if there is no match.  The match and search modes are those of XLOOKUP and
+// XMATCH.
+func lookupMatch(lookup Result, values []Result, matchMode, searchMode int) int {
+	pattern := strings.ToLower(lookup.Value())
+	best := -1
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	if searchMode == -1 || searchMode == -2 {
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+	for _, i := range order {
+		v := values[i]
+		if v.Type == ResultTypeEmpty {
+			continue
+		}
+		if matchMode == 2 && lookup.Type == ResultTypeString && v.Type == ResultTypeString {
+			if wildcard.Match(pattern, strings.ToLower(v.ValueString)) {
+				return i
+			}
+			continue
+		}
+		if valueRank(v) != valueRank(lookup) {
+			continue
+		}
+		c := compareValues(v, lookup)
+		switch {
+		case c == 0:
+			return i
+		case matchMode == -1 && c < 0:
+			if best < 0 || compareValues(v, values[best]) > 0 {
+				best = i
+			}
+		case matchMode == 1 && c > 0:
+			if best < 0 || compareValues(v, values[best]) < 0 {
+				best = i
+			}
+		}
+	}
+	return best
+}
+
+// lookupModes converts the match and search mode arguments of XLOOKUP and
+// XMATCH.
+func lookupModes(name string, args []Result, i int) (int, int, Result) {
+	mm, res := numberArg(name, args, i, 0)
+	if res.Type == ResultTypeError {
+		return 0, 0, res
+	}
+	sm, res := numberArg(name, args, i+1, 1)
+	if res.Type == ResultTypeError {
+		return 0, 0, res
+	}
+	matchMode, searchMode := int(mm), int(sm)
+	if matchMode < -1 || matchMode > 2 {
+		return 0, 0, MakeErrorResult(name + " requires match mode to be -1, 0, 1 or 2")
+	}
+	if searchMode != 1 && searchMode != -1 && searchMode != 2 && searchMode != -2 {
+		return 0, 0, MakeErrorResult(name + " requires search mode to be 1, -1, 2 or -2")
+	}
+	return matchMode, searchMode, MakeEmptyResult()
+}
+
+// XLookup implements the XLOOKUP function which searches a row or column for a
+// value and returns the corresponding item of another row or column.
+func XLookup(args []Result) Result {
+	if len(args) < 3 || len(args) > 6 {
+		return MakeErrorResult("XLOOKUP requires three to six arguments")
+	}
+	for _, a := range args[:3] {
+		if a.Type == ResultTypeError {
+			return a
+		}
+	}
+	values, horizontal, ok := vector(args[1])
+	if !ok {
+		return MakeErrorResult("XLOOKUP requires lookup array to be a single row or column")
+	}
+	matchMode, searchMode, res := lookupModes("XLOOKUP", args, 4)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	rows := arrayRows(args[2])
+	if horizontal {
+		rows = transposeRows(rows)
+	}
+	if len(rows) != len(values) {
+		return MakeErrorResult("XLOOKUP requires return array to match the size of the lookup array")
+	}
+	idx := lookupMatch(args[0], values, matchMode, searchMode)
+	if idx < 0 {
+		if len(args) > 3 && args[3].Type != ResultTypeEmpty {
+			return args[3]
+		}
+		return MakeErrorResultType(ErrorTypeNA, "XLOOKUP found no match")
+	}
+	row := rows[idx]
+	if len(row) == 1 {
+		return row[0]
+	}
+	if horizontal {
+		return MakeArrayResult(transposeRows([][]Result{row}))
+	}
+	return MakeListResult(row)
+}
+
+// XMatch implements the XMATCH function which returns the position of a value
+// in a row or column.
+func XMatch(args []Result) Result {
+	if len(args) < 2 || len(args) > 4 {
+		return MakeErrorResult("XMATCH requires two to four arguments")
+	}
+	for _, a := range args[:2] {
+		if a.Type == ResultTypeError {
+			return a
+		}
+	}
+	values, _, ok := vector(args[1])
+	if !ok {
+		return MakeErrorResult("XMATCH requires lookup array to be a single row or column")
+	}
+	matchMode, searchMode, res := lookupModes("XMATCH", args, 2)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	idx := lookupMatch(args[0], values, matchMode, searchMode)
+	if idx < 0 {
+		return MakeErrorResultType(ErrorTypeNA, "XMATCH found no match")
+	}
+	return MakeNumberResult(float64(idx + 1))
+}
+
+// AnchorArray implements the spill range operator (e.g. A1#) which returns the
+// whole array that the formula of a cell spills.  Excel stores the operator as
+// a call to ANCHORARRAY.
+func AnchorArray(ctx Context, ev Evaluator, args []Result) Result {
+	if len(args) != 1 || args[0].Ref.Type != ReferenceTypeCell {
+		return MakeErrorResultType(ErrorTypeRef, "ANCHORARRAY requires a cell reference")
+	}
+	ref := args[0].Ref.Value
+	if i := strings.LastIndex(ref, "!"); i >= 0 {
+		ctx = ctx.Sheet(strings.Replace(strings.Trim(ref[:i], "'"), "''", "'", -1))
+		ref = ref[i+1:]
+	}
+	res := ctx.Cell(ref+"#", ev)
+	// a formula whose spill is blocked has no spill range
+	blocked := res.Type == ResultTypeError && res.ValueString == MakeErrorResultType(ErrorTypeSpill, "").ValueString
+	if res.Type != ResultTypeArray && res.Type != ResultTypeList && res.Type != ResultTypeError || blocked {
+		return MakeErrorResultType(ErrorTypeRef, ref+" doesn't spill")
+	}
+	return res
+}
+
+// rewriteSpillRefs replaces the spill range operator in a formula with the
+// ANCHORARRAY call that Excel stores it as, so A1# and Sheet1!$B$2# become
+// _xlfn.ANCHORARRAY(A1) and _xlfn.ANCHORARRAY(Sheet1!$B$2).
+func rewriteSpillRefs(s string) string {
+	if !strings.Contains(s, "#") {
+		return s
+	}
+	buf := strings.Builder{}
+	last := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\'':
+			q := s[i]
+			for i++; i < len(s); i++ {
+				if s[i] == q {
+					if i+1 < len(s) && s[i+1] == q {
+						i++
+						continue
+					}
+					break
+				}
+			}
+		case '[':
+			for depth := 0; i < len(s); i++ {
+				if s[i] == '[' {
+					depth++
+				} else if s[i] == ']' {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+		case '#':
+			start := spillRefStart(s[:i])
+			if start < 0 || start < last {
+				continue
+			}
+			buf.WriteString(s[last:start])
+			buf.WriteString("_xlfn.ANCHORARRAY(")
+			buf.WriteString(s[start:i])
+			buf.WriteString(")")
+			last = i + 1
+		}
+	}
+	if last == 0 {
+		return s
+	}
+	buf.WriteString(s[last:])
+	return buf.String()
+}
+
+// spillRefStart returns the start of the cell reference, including any sheet
+// prefix, which ends s or -1 if s doesn't end with a cell reference.
+func spillRefStart(s string) int {
+	i := len(s)
+	digits, letters := 0, 0
+	for i > 0 && s[i-1] >= '0' && s[i-1] <= '9' {
+		i--
+		digits++
+	}
+	if i > 0 && s[i-1] == '$' {
+		i--
+	}
+	for i > 0 && isASCIILetter(s[i-1]) {
+		i--
+		letters++
+	}
+	if i > 0 && s[i-1] == '$' {
+		i--
+	}
+	if digits == 0 || letters == 0 || letters > 3 {
+		return -1
+	}
+	if i == 0 || s[i-1] != '!' {
+		if i > 0 && (isASCIILetter(s[i-1]) || s[i-1] == '_' || s[i-1] == '.') {
+			return -1
+		}
+		return i
+	}
+	// sheet prefix
+	i--
+	if i > 0 && s[i-1] == '\'' {
+		for i--; i > 0; i-- {
+			if s[i-1] == '\'' {
+				if i > 1 && s[i-2] == '\'' {
+					i--
+					continue
+				}
+				return i - 1
+			}
+		}
+		return -1
+	}
+	for i > 0 && (isASCIILetter(s[i-1]) || s[i-1] >= '0' && s[i-1] <= '9' || s[i-1] == '_' || s[i-1] == '.') {
+		i--
+	}
+	return i
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,406 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	bases := []struct {
+		name string
+		base int
+	}{{"BIN", 2}, {"OCT", 8}, {"DEC", 10}, {"HEX", 16}}
+	for _, from := range bases {
+		for _, to := range bases {
+			if from.base == to.base {
+				continue
+			}
+			name := from.name + "2" + to.name
+			RegisterFunction(name, baseConversion(name, from.base, to.base))
+		}
+	}
+	RegisterFunction("BITAND", bitwise("BITAND", func(a, b uint64) uint64 { return a & b }))
+	RegisterFunction("_xlfn.BITAND", bitwise("BITAND", func(a, b uint64) uint64 { return a & b }))
+	RegisterFunction("BITOR", bitwise("BITOR", func(a, b uint64) uint64 { return a | b }))
+	RegisterFunction("_xlfn.BITOR", bitwise("BITOR", func(a, b uint64) uint64 { return a | b }))
+	RegisterFunction("BITXOR", bitwise("BITXOR", func(a, b uint64) uint64 { return a ^ b }))
+	RegisterFunction("_xlfn.BITXOR", bitwise("BITXOR", func(a, b uint64) uint64 { return a ^ b }))
+	RegisterFunction("BITLSHIFT", bitShift("BITLSHIFT", 1))
+	RegisterFunction("_xlfn.BITLSHIFT", bitShift("BITLSHIFT", 1))
+	RegisterFunction("BITRSHIFT", bitShift("BITRSHIFT", -1))
+	RegisterFunction("_xlfn.BITRSHIFT", bitShift("BITRSHIFT", -1))
+	RegisterFunction("DELTA", Delta)
+	RegisterFunction("GESTEP", GeStep)
+	RegisterFunction("ERF", Erf)
+	RegisterFunction("ERF.PRECISE", ErfPrecise)
+	RegisterFunction("_xlfn.ERF.PRECISE", ErfPrecise)
+	RegisterFunction("ERFC", Erfc)
+	RegisterFunction("ERFC.PRECISE", Erfc)
+	RegisterFunction("_xlfn.ERFC.PRECISE", Erfc)
+	RegisterFunction("BESSELJ", besselFunction("BESSELJ", math.Jn, false))
+	RegisterFunction("BESSELY", besselFunction("BESSELY", math.Yn, true))
+	RegisterFunction("BESSELI", besselFunction("BESSELI", besselI, false))
+	RegisterFunction("BESSELK", besselFunction("BESSELK", besselK, true))
+}
+
+// baseDigits is the number of digits of the two's complement representation
+// used by the base conversion functions.
+const baseDigits = 10
+
+// baseLimit returns the largest magnitude of a negative number that can be
+// represented in a base with baseDigits digits.
+func baseLimit(base int) int64 {
+	switch base {
+	case 2:
+		return 1 << 9
+	case 8:
+		return 1 << 29
+	case 16:
+		return 1 << 39
+	}
+	return 1 << 39
+}
+
+// parseBase parses a binary, octal or hexadecimal number of up to ten digits
+// where ten digit numbers with the highest bit set are negative.
+func parseBase(name string, r Result, base int) (int64, Result) {
+	var s string
+	switch r.Type {
+	case ResultTypeEmpty:
+		return 0, MakeEmptyResult()
+	case ResultTypeString:
+		s = strings.TrimSpace(r.ValueString)
+	case ResultTypeNumber:
+		if r.IsBoolean {
+			return 0, MakeErrorResult(name + " requires a number argument")
+		}
+		if r.ValueNumber != math.Trunc(r.ValueNumber) || r.ValueNumber < 0 {
+			return 0, MakeErrorResultType(ErrorTypeNum, name+" requires a valid number")
+		}
+		s = strconv.FormatFloat(r.ValueNumber, 'f', -1, 64)
+	case ResultTypeError:
+		return 0, r
+	default:
+		return 0, MakeErrorResult(name + " requires a number argument")
+	}
+	if s == "" {
+		return 0, MakeEmptyResult()
+	}
+	if len(s) > baseDigits {
+		return 0, MakeErrorResultType(ErrorTypeNum, name+" requires at most ten digits")
+	}
+	v, err := strconv.ParseInt(s, base, 64)
+	if err != nil {
+		return 0, MakeErrorResultType(ErrorTypeNum, name+" requires a valid number")
+	}
+	if len(s) == baseDigits {
+		// two's complement
+		full := int64(1)
+		for i := 0; i < baseDigits; i++ {
+			full *= int64(base)
+		}
+		if v >= full/2 {
+			v -= full
+		}
+	}
+	return v, MakeEmptyResult()
+}
+
+// formatBase formats a number in a base, padded to a number of places.
+// Negative numbers are formatted as ten digit two's complement numbers and
+// ignore the places.
+func formatBase(name string, v int64, base int, places Result) Result {
+	limit := baseLimit(base)
+	if v < -limit || v >= limit {
+		return MakeErrorResultType(ErrorTypeNum, name+" result is out of range")
+	}
+	if v < 0 {
+		full := int64(1)
+		for i := 0; i < baseDigits; i++ {
+			full *= int64(base)
+		}
+		return MakeStringResult(strings.ToUpper(strconv.FormatInt(full+v, base)))
+	}
+	s := strings.ToUpper(strconv.FormatInt(v, base))
+	if places.Type == ResultTypeEmpty {
+		return MakeStringResult(s)
+	}
+	p, res := statNumberArg(name, places)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	n := int(p)
+	if n < len(s) || n > baseDigits {
+		return MakeErrorResultType(ErrorTypeNum, name+" has an invalid number of places")
+	}
+	return MakeStringResult(strings.Repeat("0", n-len(s)) + s)
+}
+
+// baseConversion returns a function converting between binary, octal, decimal
+// and hexadecimal, e.g. HEX2DEC or DEC2BIN.
+func baseConversion(name string, from, to int) Function {
+	return func(args []Result) Result {
+		if len(args) < 1 || len(args) > 2 || to == 10 && len(args) != 1 {
+			return MakeErrorResult(name + " requires one or two arguments")
+		}
+		var v int64
+		if from == 10 {
+			f, res := statNumberArg(name, args[0])
+			if res.Type == ResultTypeError {
+				return res
+			}
+			v = int64(math.Trunc(f))
+			if limit := baseLimit(to); v < -limit || v >= limit {
+				return MakeErrorResultType(ErrorTypeNum, name+" argument is out of range")
+			}
+		} else {
+			var res Result
+			if v, res = parseBase(name, args[0], from); res.Type == ResultTypeError {
+				return res
+			}
+		}
+		if to == 10 {
+			return MakeNumberResult(float64(v))
+		}
+		places := MakeEmptyResult()
+		if len(args) == 2 {
+			places = args[1]
+		}
+		return formatBase(name, v, to, places)
+	}
+}
+
+// bitLimit is the exclusive upper bound for arguments of the bit functions.
+const bitLimit = 1 << 48
+
+// bitArg validates an argument of the bit functions which must be a non
+// negative integer less than 2^48.
+func bitArg(name string, r Result) (uint64, Result) {
+	v, res := statNumberArg(name, r)
+	if res.Type == ResultTypeError {
+		return 0, res
+	}
+	if v < 0 || v >= bitLimit || v != math.Trunc(v) {
+		return 0, MakeErrorResultType(ErrorTypeNum, name+" requires integers between 0 and 2^48")
+	}
+	return uint64(v), MakeEmptyResult()
+}
+
+func bitwise(name string, fn func(a, b uint64) uint64) Function {
+	return func(args []Result) Result {
+		if len(args) != 2 {
+			return MakeErrorResult(name + " requires two arguments")
+		}
+		a, res := bitArg(name, args[0])
+		if res.Type == ResultTypeError {
+			return res
+		}
+		b, res := bitArg(name, args[1])
+		if res.Type == ResultTypeError {
+			return res
+		}
+		return MakeNumberResult(float64(fn(a, b)))
+	}
+}
+
+// bitShift returns BITLSHIFT for a direction of 1 and BITRSHIFT for -1.  A
+// negative shift amount shifts in the other direction.
+func bitShift(name string, dir int) Function {
+	return func(args []Result) Result {
+		if len(args) != 2 {
+			return MakeErrorResult(name + " requires two arguments")
+		}
+		v, res := bitArg(name, args[0])
+		if res.Type == ResultTypeError {
+			return res
+		}
+		s, res := statNumberArg(name, args[1])
+		if res.Type == ResultTypeError {
+			return res
+		}
+		shift := int(math.Trunc(s)) * dir
+		if shift > 53 || shift < -53 {
+			return MakeErrorResultType(ErrorTypeNum, name+" shift amount must be at most 53")
+		}
+		if shift >= 0 {
+			v <<= uint(shift)
+		} else {
+			v >>= uint(-shift)
+		}
+		if v >= bitLimit {
+			return MakeErrorResultType(ErrorTypeNum, name+" result is out of range")
+		}
+		return MakeNumberResult(float64(v))
+	}
+}
+
+// Delta is an implementation of the Excel DELTA() function which returns 1 if
+// two numbers are equal and 0 otherwise.
+func Delta(args []Result) Result {
+	if len(args) != 1 && len(args) != 2 {
+		return MakeErrorResult("DELTA requires one or two arguments")
+	}
+	a, res := statNumberArg("DELTA", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	b, res := numberArg("DELTA", args, 1, 0)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	if a == b {
+		return MakeNumberResult(1)
+	}
+	return MakeNumberResult(0)
+}
+
+// GeStep is an implementation of the Excel GESTEP() function which returns 1 if
+// a number is greater than or equal to a step and 0 otherwise.
+func GeStep(args []Result) Result {
+	if len(args) != 1 && len(args) != 2 {
+		return MakeErrorResult("GESTEP requires one or two arguments")
+	}
+	n, res := statNumberArg("GESTEP", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	step, res := numberArg("GESTEP", args, 1, 0)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	if n >= step {
+		return MakeNumberResult(1)
+	}
+	return MakeNumberResult(0)
+}
+
+// Erf is an implementation of the Excel ERF() function which returns the error
+// function integrated between zero and a limit, or between two limits.
+func Erf(args []Result) Result {
+	if len(args) != 1 && len(args) != 2 {
+		return MakeErrorResult("ERF requires one or two arguments")
+	}
+	lower, res := statNumberArg("ERF", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	if len(args) == 1 || args[1].Type == ResultTypeEmpty {
+		return MakeNumberResult(math.Erf(lower))
+	}
+	upper, res := statNumberArg("ERF", args[1])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	return MakeNumberResult(math.Erf(upper) - math.Erf(lower))
+}
+
+// ErfPrecise is an implementation of the Excel ERF.PRECISE() function.
+func ErfPrecise(args []Result) Result {
+	if len(args) != 1 {
+		return MakeErrorResult("ERF.PRECISE requires one argument")
+	}
+	return Erf(args)
+}
+
+// Erfc is an implementation of the Excel ERFC() and ERFC.PRECISE() functions
+// which return the complementary error function.
+func Erfc(args []Result) Result {
+	if len(args) != 1 {
+		return MakeErrorResult("ERFC requires one argument")
+	}
+	x, res := statNumberArg("ERFC", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	return MakeNumberResult(math.Erfc(x))
+}
+
+// besselFunction returns a function implementing one of the BESSEL functions.
+// The order is truncated to an integer and must not be negative.  Bessel
+// functions of the second kind require x to be positive.
+func besselFunction(name string, fn func(n int, x float64) float64, positive bool) Function {
+	return func(args []Result) Result {
+		if len(args) != 2 {
+			return MakeErrorResult(name + " requires two arguments")
+		}
+		x, res := statNumberArg(name, args[0])
+		if res.Type == ResultTypeError {
+			return res
+		}
+		n, res := statNumberArg(name, args[1])
+		if res.Type == ResultTypeError {
+			return res
+		}
+		if n < 0 || positive && x <= 0 {
+			return MakeErrorResultType(ErrorTypeNum, name+" requires a valid order and x")
+		}
+		v := fn(int(n), x)
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return MakeErrorResultType(ErrorTypeNum, name+" result is out of range")
+		}
+		return MakeNumberResult(v)
+	}
+}
+
+// besselI returns the modified Bessel function of the first kind using its
+// power series.
+func besselI(n int, x float64) float64 {
+	half := x / 2
+	term := math.Pow(half, float64(n))
+	for k := 2; k <= n; k++ {
+		term /= float64(k)
+	}
+	sum := term
+	for k := 1; k < 500; k++ {
+		term *= half * half / (float64(k) * float64(k+n))
+		sum += term
+		if math.Abs(term) < math.Abs(sum)*1e-16 {
+			break
+		}
+	}
+	return sum
+}
+
+// besselK returns the modified Bessel function of the second kind using the
+// polynomial approximations of K0 and K1 from Abramowitz and Stegun and the
+// recurrence relation for higher orders.
+func besselK(n int, x float64) float64 {
+	k0, k1 := besselK0(x), besselK1(x)
+	if n == 0 {
+		return k0
+	}
+	for i := 1; i < n; i++ {
+		k0, k1 = k1, k0+2*float64(i)/x*k1
+	}
+	return k1
+}
+
+func besselK0(x float64) float64 {
+	if x <= 2 {
+		y := x * x / 4
+		return -math.Log(x/2)*besselI(0, x) + (-0.57721566 + y*(0.42278420+y*(0.23069756+
+			y*(0.3488590e-1+y*(0.262698e-2+y*(0.10750e-3+y*0.74e-5))))))
+	}
+	y := 2 / x
+	return math.Exp(-x) / math.Sqrt(x) * (1.25331414 + y*(-0.7832358e-1+y*(0.2189568e-1+
+		y*(-0.1062446e-1+y*(0.587872e-2+y*(-0.251540e-2+y*0.53208e-3))))))
+}
+
+func besselK1(x float64) float64 {
+	if x <= 2 {
+		y := x * x / 4
+		return math.Log(x/2)*besselI(1, x) + (1/x)*(1+y*(0.15443144+y*(-0.67278579+
+			y*(-0.18156897+y*(-0.1919402e-1+y*(-0.110404e-2+y*(-0.4686e-4)))))))
+	}
+	y := 2 / x
+	return math.Exp(-x) / math.Sqrt(x) * (1.25331414 + y*(0.23498619+y*(-0.3655620e-1+
+		y*(0.1504268e-1+y*(-0.780353e-2+y*(0.325614e-2+y*(-0.68245e-3)))))))
+}
@@ -0,0 +1,127 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import "testing"
+
+func TestBaseConversion(t *testing.T) {
+	testFormulas(t, InvalidReferenceContext, []formulaCase{
+		{"DEC2BIN(9)", "1001"},
+		{"DEC2BIN(9,4)", "1001"},
+		{"DEC2BIN(9,8)", "00001001"},
+		{"DEC2BIN(-100)", "1110011100"},
+		{"DEC2BIN(-100,2)", "1110011100"},
+		{"DEC2BIN(-512)", "1000000000"},
+		{"DEC2BIN(511)", "111111111"},
+		{"DEC2OCT(58,3)", "072"},
+		{"DEC2OCT(-100)", "7777777634"},
+		{"DEC2HEX(100,4)", "0064"},
+		{"DEC2HEX(-54)", "FFFFFFFFCA"},
+		{"DEC2HEX(28.9)", "1C"},
+		{"BIN2DEC(1100100)", "100"},
+		{"BIN2DEC(\"1111111111\")", "-1"},
+		{"BIN2DEC(\"1000000000\")", "-512"},
+		{"BIN2HEX(11111011,4)", "00FB"},
+		{"BIN2HEX(1110)", "E"},
+		{"BIN2HEX(\"1111111111\")", "FFFFFFFFFF"},
+		{"BIN2OCT(1001,3)", "011"},
+		{"BIN2OCT(1100100)", "144"},
+		{"BIN2OCT(\"1111111111\")", "7777777777"},
+		{"HEX2DEC(\"A5\")", "165"},
+		{"HEX2DEC(\"FFFFFFFF5B\")", "-165"},
+		{"HEX2DEC(\"3DA408B9\")", "1034160313"},
+		{"HEX2BIN(\"F\",8)", "00001111"},
+		{"HEX2BIN(\"B7\")", "10110111"},
+		{"HEX2BIN(\"FFFFFFFE00\")", "1000000000"},
+		{"HEX2OCT(\"F\",3)", "017"},
+		{"HEX2OCT(\"FFFFFFFF00\")", "7777777400"},
+		{"OCT2DEC(54)", "44"},
+		{"OCT2DEC(\"7777777533\")", "-165"},
+		{"OCT2BIN(3,3)", "011"},
+		{"OCT2BIN(\"7777777000\")", "1000000000"},
+		{"OCT2HEX(100,4)", "0040"},
+		{"OCT2HEX(\"7777777533\")", "FFFFFFFF5B"},
+		{"HEX2DEC(\"\")", "0"},
+	})
+}
+
+func TestBaseConversionErrors(t *testing.T) {
+	testFormulas(t, InvalidReferenceContext, []formulaCase{
+		{"DEC2BIN(512)", "#NUM!"},
+		{"DEC2BIN(-513)", "#NUM!"},
+		{"DEC2BIN(9,2)", "#NUM!"},
+		{"DEC2BIN(9,11)", "#NUM!"},
+		{"DEC2BIN(9,-1)", "#NUM!"},
+		{"DEC2HEX(549755813888)", "#NUM!"},
+		{"DEC2OCT(536870912)", "#NUM!"},
+		{"DEC2BIN(\"abc\")", "#VALUE!"},
+		{"DEC2BIN(9,\"abc\")", "#VALUE!"},
+		{"BIN2DEC(12)", "#NUM!"},
+		{"BIN2DEC(\"11111111111\")", "#NUM!"},
+		{"BIN2DEC(-1)", "#NUM!"},
+		{"BIN2DEC(1.5)", "#NUM!"},
+		{"HEX2DEC(\"G1\")", "#NUM!"},
+		{"HEX2BIN(\"200\")", "#NUM!"},
+		{"HEX2BIN(\"FFFFFFFDFF\")", "#NUM!"},
+		{"OCT2BIN(\"1000\")", "#NUM!"},
+		{"HEX2BIN(\"F\",3)", "#NUM!"},
+		{"OCT2DEC(8)", "#NUM!"},
+		{"BIN2DEC(TRUE)", "#VALUE!"},
+		{"BIN2DEC(1,2)", "#VALUE!"},
+	})
+}
+
+func TestBitFunctions(t *testing.T) {
+	testFormulas(t, InvalidReferenceContext, []formulaCase{
+		{"BITAND(1,5)", "1"},
+		{"BITAND(13,25)", "9"},
+		{"BITOR(23,10)", "31"},
+		{"BITXOR(5,3)", "6"},
+		{"BITLSHIFT(4,2)", "16"},
+		{"BITLSHIFT(4,-2)", "1"},
+		{"BITRSHIFT(13,2)", "3"},
+		{"BITRSHIFT(13,-2)", "52"},
+		{"_xlfn.BITAND(281474976710655,1)", "1"},
+		{"BITAND(-1,1)", "#NUM!"},
+		{"BITOR(281474976710656,1)", "#NUM!"},
+		{"BITXOR(1.5,1)", "#NUM!"},
+		{"BITLSHIFT(1,54)", "#NUM!"},
+		{"BITLSHIFT(1,48)", "#NUM!"},
+		{"BITAND(\"a\",1)", "#VALUE!"},
+	})
+}
+
+func TestEngineering(t *testing.T) {
+	testFormulas(t, InvalidReferenceContext, []formulaCase{
+		{"DELTA(5,4)", "0"},
+		{"DELTA(5,5)", "1"},
+		{"DELTA(0.5)", "0"},
+		{"DELTA(0)", "1"},
+		{"GESTEP(5,4)", "1"},
+		{"GESTEP(5,5)", "1"},
+		{"GESTEP(-4,-3)", "0"},
+		{"GESTEP(-1)", "0"},
+		{"DELTA(\"a\",1)", "#VALUE!"},
+		{"ERF(0.745)", "0.707928920"},
+		{"ERF(1)", "0.842700793"},
+		{"ERF(1.5)", "0.966105146"},
+		{"ERF(0,1.5)", "0.966105146"},
+		{"ERF(1,2)", "0.152621472"},
+		{"ERF.PRECISE(0.745)", "0.707928920"},
+		{"ERFC(1)", "0.157299207"},
+		{"ERFC.PRECISE(1)", "0.157299207"},
+		{"BESSELJ(1.9,2)", "0.32992573"},
+		{"BESSELY(2.5,1)", "0.14591814"},
+		{"BESSELI(1.5,1)", "0.98166643"},
+		{"BESSELK(1.5,1)", "0.27738780"},
+		{"BESSELJ(1.9,-1)", "#NUM!"},
+		{"BESSELY(-1,1)", "#NUM!"},
+		{"BESSELK(0,1)", "#NUM!"},
+		{"BESSELJ(\"a\",1)", "#VALUE!"},
+	})
+}
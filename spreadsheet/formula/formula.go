@@ -48,7 +48,7 @@ func IsErr (args []Result )Result {if len (args )!=1{MakeErrorResult ("\u0049\u0
 func Coupdaybs (args []Result )Result {_dbfe ,_gbag :=_dccda (args ,"\u0043O\u0055\u0050\u0044\u0041\u0059\u0042S");if _gbag .Type ==ResultTypeError {return _gbag ;};return MakeNumberResult (_fgeb (_dbfe ._ggbd ,_dbfe ._eedd ,_dbfe ._ecf ,_dbfe ._deagf ));};
 
 // NewPrefixHorizontalRange constructs a new full rows range with prefix.
-func NewPrefixHorizontalRange (pfx Expression ,v string )Expression {_gfdfb :=_ee .Split (v ,"\u003a");if len (_gfdfb )!=2{return nil ;};_fbbb ,_ :=_ff .Atoi (_gfdfb [0]);_aeag ,_ :=_ff .Atoi (_gfdfb [1]);return PrefixHorizontalRange {pfx ,_fbbb ,_aeag };};
+func NewPrefixHorizontalRange (pfx Expression ,v string )Expression {_gfdfb :=_ee .Split (v ,"\u003a");if len (_gfdfb )!=2{return nil ;};_fbbb ,_bgfeb :=rangeRow (_gfdfb [0]);_aeag ,_ddefa :=rangeRow (_gfdfb [1]);return PrefixHorizontalRange {pfx ,_fbbb ,_aeag ,_bgfeb ,_ddefa };};
 
 // Pricemat implements the Excel PRICEMAT function.
 func Pricemat (args []Result )Result {_efff :=len (args );if _efff !=5&&_efff !=6{return MakeErrorResult ("\u0050\u0052\u0049\u0043\u0045\u004d\u0041\u0054\u0020\u0072\u0065\u0071\u0075i\u0072\u0065\u0073\u0020\u0066\u0069v\u0065\u0020\u006f\u0072\u0020\u0073\u0069\u0078\u0020\u0061\u0072\u0067\u0075m\u0065\u006e\u0074\u0073");};_bdcg ,_gdc ,_bbga :=_dfd (args [0],args [1],"\u0050\u0052\u0049\u0043\u0045\u004d\u0041\u0054");if _bbga .Type ==ResultTypeError {return _bbga ;};_ccgbg ,_bbga :=_faeb (args [2],"\u0069\u0073\u0073\u0075\u0065\u0020\u0064\u0061\u0074\u0065","\u0050\u0052\u0049\u0043\u0045\u004d\u0041\u0054");if _bbga .Type ==ResultTypeError {return _bbga ;};if _ccgbg >=_bdcg {return MakeErrorResult ("\u0050\u0052\u0049\u0043E\u004d\u0041\u0054\u0020\u0072\u0065\u0071\u0075\u0069r\u0065\u0073\u0020\u0069\u0073\u0073\u0075\u0065\u0020\u0064\u0061\u0074\u0065\u0020\u0074\u006f\u0020\u0062e\u0020\u0062\u0065\u0066\u006fr\u0065\u0020\u0073\u0065\u0074\u0074\u006c\u0065\u006d\u0065\u006e\u0074\u0020\u0064\u0061\u0074\u0065");};if args [3].Type !=ResultTypeNumber {return MakeErrorResult ("\u0050\u0052I\u0043\u0045\u004d\u0041T\u0020\u0072e\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0072a\u0074\u0065\u0020\u006f\u0066\u0020\u0074\u0079\u0070\u0065\u0020\u006eu\u006d\u0062\u0065\u0072");};_aecf :=args [3].ValueNumber ;if _aecf < 0{return MakeErrorResultType (ErrorTypeNum ,"\u0050\u0052\u0049\u0043\u0045M\u0041\u0054\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0072a\u0074\u0065\u0020\u0074\u006f\u0020\u0062\u0065\u0020\u006e\u006f\u006e\u0020\u006e\u0065\u0067\u0061\u0074\u0069\u0076\u0065");};if args [4].Type !=ResultTypeNumber {return MakeErrorResult ("\u0050\u0052\u0049\u0043\u0045\u004d\u0041\u0054\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0079\u0069\u0065\u006c\u0064\u0020o\u0066\u0020\u0074\u0079\u0070e\u0020\u006eu\u006d\u0062\u0065\u0072");};_fbaga :=args [4].ValueNumber ;if _fbaga < 0{return MakeErrorResultType (ErrorTypeNum ,"\u0050\u0052\u0049C\u0045\u004d\u0041\u0054\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0079\u0069\u0065\u006c\u0064\u0020\u0074\u006f\u0020\u0062\u0065\u0020\u006e\u006f\u006e \u006e\u0065\u0067\u0061\u0074\u0069\u0076\u0065");};_ddac :=0;if _efff ==6&&args [5].Type !=ResultTypeEmpty {if args [5].Type !=ResultTypeNumber {return MakeErrorResult ("\u0050R\u0049\u0043E\u004d\u0041\u0054 \u0072\u0065\u0071\u0075\u0069\u0072\u0065s\u0020\u0062\u0061\u0073\u0069\u0073 \u0074\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006d\u0062\u0065r\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");};_ddac =int (args [5].ValueNumber );if !_cfee (_ddac ){return MakeErrorResultType (ErrorTypeNum ,"\u0049\u006ec\u006f\u0072\u0072\u0065c\u0074\u0020b\u0061\u0073\u0069\u0073\u0020\u0061\u0072\u0067u\u006d\u0065\u006e\u0074\u0020\u0066\u006f\u0072\u0020\u0050\u0052\u0049C\u0045\u004d\u0041\u0054");};};_ggdf ,_bbga :=_fea (_bdcg ,_gdc ,_ddac );if _bbga .Type ==ResultTypeError {return _bbga ;};_bbc ,_bbga :=_fea (_ccgbg ,_gdc ,_ddac );if _bbga .Type ==ResultTypeError {return _bbga ;};_gcag ,_bbga :=_fea (_ccgbg ,_bdcg ,_ddac );if _bbga .Type ==ResultTypeError {return _bbga ;};_dbbf :=1+_bbc *_aecf ;_ggeb :=1+_ggdf *_fbaga ;return MakeNumberResult ((_dbbf /_ggeb -_gcag *_aecf )*100);};
@@ -61,7 +61,7 @@ func (_ccc EmptyExpr )String ()string {return ""};
 func Not (args []Result )Result {if len (args )!=1{return MakeErrorResult ("\u004eO\u0054\u0020\u0072\u0065q\u0075\u0069\u0072\u0065\u0073 \u006fn\u0065 \u0061\u0072\u0067\u0075\u006d\u0065\u006et");};switch args [0].Type {case ResultTypeError :return args [0];case ResultTypeString ,ResultTypeList :return MakeErrorResult ("\u004e\u004f\u0054\u0020\u0065\u0078\u0070\u0065\u0063\u0074s\u0020\u0061\u0020\u006e\u0075\u006d\u0065r\u0069\u0063\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");case ResultTypeNumber :return MakeBoolResult (!(args [0].ValueNumber !=0));default:return MakeErrorResult ("u\u006e\u0068\u0061\u006e\u0064\u006ce\u0064\u0020\u004e\u004f\u0054\u0020\u0061\u0072\u0067u\u006d\u0065\u006et\u0020t\u0079\u0070\u0065");};};
 
 // PrefixHorizontalRange is a range expression that when evaluated returns a list of Results from references like Sheet1!1:4 (all cells from rows 1 to 4 of sheet 'Sheet1').
-type PrefixHorizontalRange struct{_eegae Expression ;_gfad ,_cfaf int ;};const _cfcb =57378;
+type PrefixHorizontalRange struct{_eegae Expression ;_gfad ,_cfaf int ;absFrom ,absTo bool ;};const _cfcb =57378;
 
 // NewEvaluator constructs a new defEval object which is the default formula evaluator.
 func NewEvaluator ()Evaluator {_ae :=&defEval {};_ae .evCache =_eb ();return _ae };
@@ -73,10 +73,10 @@ func (_agaaa String )Reference (ctx Context ,ev Evaluator )Reference {return Ref
 func Min (args []Result )Result {return _dgedb (args ,false )};
 
 // Update updates references in the PrefixHorizontalRange after removing a row/column.
-func (_fcafc PrefixHorizontalRange )Update (q *_cc .UpdateQuery )Expression {return _fcafc };
+func (_fcafc PrefixHorizontalRange )Update (q *_cc .UpdateQuery )Expression {return updatePrefixHorizontalRange (_fcafc ,q )};
 
 // Update updates references in the PrefixRangeExpr after removing a row/column.
-func (_ffddd PrefixRangeExpr )Update (q *_cc .UpdateQuery )Expression {_egeeg :=_ffddd ;_dgffc :=_ffddd ._cgegf .String ();if _dgffc ==q .SheetToUpdate {_daeef :=*q ;_daeef .UpdateCurrentSheet =true ;_egeeg ._gaga =_ffddd ._gaga .Update (&_daeef );_egeeg ._dbaf =_ffddd ._dbaf .Update (&_daeef );};return _egeeg ;};type yyParser interface{Parse (yyLexer )int ;Lookahead ()int ;};
+func (_ffddd PrefixRangeExpr )Update (q *_cc .UpdateQuery )Expression {return updatePrefixRange (_ffddd ,q );};type yyParser interface{Parse (yyLexer )int ;Lookahead ()int ;};
 
 // Update returns the same object as updating sheet references does not affect Error.
 func (_bga Error )Update (q *_cc .UpdateQuery )Expression {return _bga };
@@ -92,7 +92,7 @@ func (_cebcb HorizontalRange )Reference (ctx Context ,ev Evaluator )Reference {r
 func Quotient (args []Result )Result {if len (args )!=2{return MakeErrorResult ("\u0051\u0055\u004f\u0054\u0049E\u004e\u0054\u0028\u0029\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073 \u0074\u0077\u006f\u0020\u006e\u0075\u006d\u0065\u0072\u0069\u0063\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0073");};_bfb :=args [0].AsNumber ();_ccefg :=args [1].AsNumber ();if _bfb .Type !=ResultTypeNumber ||_ccefg .Type !=ResultTypeNumber {return MakeErrorResult ("\u0051\u0055\u004f\u0054\u0049E\u004e\u0054\u0028\u0029\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073 \u0074\u0077\u006f\u0020\u006e\u0075\u006d\u0065\u0072\u0069\u0063\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0073");};if _ccefg .ValueNumber ==0{return MakeErrorResultType (ErrorTypeDivideByZero ,"\u0051U\u004f\u0054\u0049\u0045N\u0054\u0028\u0029\u0020\u0064i\u0076i\u0064e\u0020\u0062\u0079\u0020\u007a\u0065\u0072o");};return MakeNumberResult (_dc .Trunc (_bfb .ValueNumber /_ccefg .ValueNumber ));};func _gfag (_fbbga []Result )[]float64 {_gfdea :=make ([]float64 ,0);for _ ,_gecd :=range _fbbga {if _gecd .Type ==ResultTypeEmpty {continue ;};_gecd =_gecd .AsNumber ();switch _gecd .Type {case ResultTypeNumber :if !_gecd .IsBoolean {_gfdea =append (_gfdea ,_gecd .ValueNumber );};case ResultTypeList ,ResultTypeArray :_gfdea =append (_gfdea ,_gfag (_gecd .ListValues ())...);case ResultTypeString :default:_ge .Log ("\u0075\u006e\u0068\u0061\u006ed\u006c\u0065\u0064\u0020\u0065\u0078\u0074\u0072\u0061\u0063\u0074\u004e\u0075m\u0062\u0065\u0072\u0073\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u0074\u0079\u0070\u0065\u0020\u0025\u0073",_gecd .Type );};};return _gfdea ;};
 
 // String returns an empty string for Error.
-func (_gdd Error )String ()string {return ""};
+func (_gdd Error )String ()string {return _gdd ._gag };
 
 // Averagea implements the AVERAGEA function, AVERAGEA counts cells that contain
 // text as a zero where AVERAGE ignores them entirely.
@@ -133,7 +133,7 @@ func TextJoin (args []Result )Result {if len (args )< 3{return MakeErrorResult (
 // ListValues converts an array to a list or returns a lists values. This is used
 // for functions that can accept an array, but don't care about ordering to
 // reuse the list function logic.
-func (_cfcf Result )ListValues ()[]Result {if _cfcf .Type ==ResultTypeArray {_gefdf :=[]Result {};for _ ,_fdcbd :=range _cfcf .ValueArray {for _ ,_bcdaa :=range _fdcbd {_gefdf =append (_gefdf ,_bcdaa );};};return _gefdf ;};if _cfcf .Type ==ResultTypeList {return _cfcf .ValueList ;};return nil ;};func init (){_bce ();RegisterFunction ("\u0044\u0041\u0054\u0045",Date );RegisterFunction ("\u0044A\u0054\u0045\u0044\u0049\u0046",DateDif );RegisterFunction ("\u0044A\u0054\u0045\u0056\u0041\u004c\u0055E",DateValue );RegisterFunction ("\u0044\u0041\u0059",Day );RegisterFunction ("\u0044\u0041\u0059\u0053",Days );RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0044\u0041\u0059\u0053",Days );RegisterFunction ("\u0045\u0044\u0041T\u0045",Edate );RegisterFunction ("\u0045O\u004d\u004f\u004e\u0054\u0048",Eomonth );RegisterFunction ("\u004d\u0049\u004e\u0055\u0054\u0045",Minute );RegisterFunction ("\u004d\u004f\u004eT\u0048",Month );RegisterFunction ("\u004e\u004f\u0057",Now );RegisterFunction ("\u0054\u0049\u004d\u0045",Time );RegisterFunction ("\u0054I\u004d\u0045\u0056\u0041\u004c\u0055E",TimeValue );RegisterFunction ("\u0054\u004f\u0044A\u0059",Today );RegisterFunctionComplex ("\u0059\u0045\u0041\u0052",Year );RegisterFunction ("\u0059\u0045\u0041\u0052\u0046\u0052\u0041\u0043",YearFrac );};
+func (_cfcf Result )ListValues ()[]Result {if _cfcf .Type ==ResultTypeArray {_gefdf :=[]Result {};for _ ,_fdcbd :=range _cfcf .ValueArray {for _ ,_bcdaa :=range _fdcbd {_gefdf =append (_gefdf ,_bcdaa );};};return _gefdf ;};if _cfcf .Type ==ResultTypeList {return _cfcf .ValueList ;};return nil ;};func init (){_bce ();RegisterFunctionComplex ("\u0044\u0041\u0054\u0045",inEpoch (complexFunction (Date ),0,true ));RegisterFunctionComplex ("\u0044A\u0054\u0045\u0044\u0049\u0046",inEpoch (complexFunction (DateDif ),2,false ));RegisterFunctionComplex ("\u0044\u0041\u0059",inEpoch (complexFunction (Day ),1,false ));RegisterFunctionComplex ("\u0044\u0041\u0059\u0053",inEpoch (complexFunction (Days ),2,false ));RegisterFunctionComplex ("\u005f\u0078\u006c\u0066\u006e\u002e\u0044\u0041\u0059\u0053",inEpoch (complexFunction (Days ),2,false ));RegisterFunctionComplex ("\u0045\u0044\u0041T\u0045",inEpoch (complexFunction (Edate ),1,true ));RegisterFunctionComplex ("\u0045O\u004d\u004f\u004e\u0054\u0048",inEpoch (complexFunction (Eomonth ),1,true ));RegisterFunction ("\u004d\u0049\u004e\u0055\u0054\u0045",Minute );RegisterFunctionComplex ("\u004d\u004f\u004eT\u0048",inEpoch (complexFunction (Month ),1,false ));RegisterFunction ("\u0054\u0049\u004d\u0045",Time );RegisterFunction ("\u0054I\u004d\u0045\u0056\u0041\u004c\u0055E",TimeValue );RegisterFunctionComplex ("\u0059\u0045\u0041\u0052",Year );RegisterFunctionComplex ("\u0059\u0045\u0041\u0052\u0046\u0052\u0041\u0043",inEpoch (complexFunction (YearFrac ),2,false ));};
 
 // NewBool constructs a new boolean expression.
 func NewBool (v string )Expression {_da ,_bgb :=_ff .ParseBool (v );if _bgb !=nil {_ge .Log ("\u0065\u0072\u0072\u006f\u0072\u0020p\u0061\u0072\u0073\u0069\u006e\u0067\u0020\u0066\u006f\u0072\u006d\u0075\u006ca\u0020\u0062\u006f\u006f\u006c\u0020\u0025s\u003a\u0020\u0025\u0073",v ,_bgb );};return Bool {_da };};
@@ -151,7 +151,8 @@ func IsError (args []Result )Result {if len (args )!=1{MakeErrorResult ("I\u0053
 // to retreive information from sheets.
 type Context interface{
 
-// Cell returns the result of evaluating a cell.
+// Cell returns the result of evaluating a cell.  A reference ending with
+// '#' (e.g. A1#) returns the whole array spilled by the cell's formula.
 Cell (_afb string ,_eaed Evaluator )Result ;
 
 // Sheet returns an evaluation context for a given sheet name.  This is used
@@ -261,7 +262,7 @@ func FloorPrecise (args []Result )Result {if len (args )==0{return MakeErrorResu
 func (_bab EmptyExpr )Reference (ctx Context ,ev Evaluator )Reference {return ReferenceInvalid };
 
 // Eval evaluates and returns the result of the NamedRangeRef reference.
-func (_gcgff NamedRangeRef )Eval (ctx Context ,ev Evaluator )Result {_cgbfe :=ctx .NamedRange (_gcgff ._bdggc );_cgea :=_cgbfe .Value ;if _ceaa ,_dafa :=ev .GetFromCache (_cgea );_dafa {return _ceaa ;};_gcgag :=_ee .Split (_cgea ,"\u0021");if len (_gcgag )!=2{return MakeErrorResult (_c .Sprintf ("\u0075\u006e\u0073\u0075\u0070\u0070\u006f\u0072\u0074\u0065\u0064\u0020\u006e\u0061\u006de\u0064 \u0072\u0061\u006e\u0067\u0065\u0020\u0076\u0061\u006c\u0075\u0065\u0020\u0025\u0073",_cgea ));};_begb :=ctx .Sheet (_gcgag [0]);_ebddd :=_ee .Split (_gcgag [1],"\u003a");switch len (_ebddd ){case 1:_ecabe :=ev .Eval (_begb ,_ebddd [0]);ev .SetCache (_cgea ,_ecabe );return _ecabe ;case 2:_bgcc :=_efbfc (_begb ,ev ,_ebddd [0],_ebddd [1]);ev .SetCache (_cgea ,_bgcc );return _bgcc ;};return MakeErrorResult (_c .Sprintf ("\u0075\u006es\u0075\u0070\u0070\u006f\u0072\u0074\u0065\u0064\u0020\u0072\u0065\u0066\u0065\u0072\u0065\u006e\u0063\u0065\u0020\u0074\u0079\u0070e \u0025\u0073",_cgbfe .Type ));};
+func (_gcgff NamedRangeRef )Eval (ctx Context ,ev Evaluator )Result {if _fcbf ,_fcbg :=boundValue (ctx ,_gcgff ._bdggc );_fcbg {return _fcbf ;};_cgbfe :=ctx .NamedRange (_gcgff ._bdggc );_cgea :=_cgbfe .Value ;if _ceaa ,_dafa :=ev .GetFromCache (_cgea );_dafa {return _ceaa ;};_gcgag :=_ee .Split (_cgea ,"\u0021");if len (_gcgag )!=2{return MakeErrorResult (_c .Sprintf ("\u0075\u006e\u0073\u0075\u0070\u0070\u006f\u0072\u0074\u0065\u0064\u0020\u006e\u0061\u006de\u0064 \u0072\u0061\u006e\u0067\u0065\u0020\u0076\u0061\u006c\u0075\u0065\u0020\u0025\u0073",_cgea ));};_begb :=ctx .Sheet (_gcgag [0]);_ebddd :=_ee .Split (_gcgag [1],"\u003a");switch len (_ebddd ){case 1:_ecabe :=ev .Eval (_begb ,_ebddd [0]);ev .SetCache (_cgea ,_ecabe );return _ecabe ;case 2:_bgcc :=_efbfc (_begb ,ev ,_ebddd [0],_ebddd [1]);ev .SetCache (_cgea ,_bgcc );return _bgcc ;};return MakeErrorResult (_c .Sprintf ("\u0075\u006es\u0075\u0070\u0070\u006f\u0072\u0074\u0065\u0064\u0020\u0072\u0065\u0066\u0065\u0072\u0065\u006e\u0063\u0065\u0020\u0074\u0079\u0070e \u0025\u0073",_cgbfe .Type ));};
 
 // Eval evaluates and returns the result of a constant array expression.
 func (_cfb ConstArrayExpr )Eval (ctx Context ,ev Evaluator )Result {_gea :=[][]Result {};for _ ,_gda :=range _cfb ._fdb {_fdc :=[]Result {};for _ ,_gba :=range _gda {_fdc =append (_fdc ,_gba .Eval (ctx ,ev ));};_gea =append (_gea ,_fdc );};return MakeArrayResult (_gea );};func _edbeb (_bgbeb []Result ,_ffada countMode )float64 {_edaf :=0.0;for _ ,_bfcc :=range _bgbeb {switch _bfcc .Type {case ResultTypeNumber :if _ffada ==_ecaa ||(_ffada ==_fdgfd &&!_bfcc .IsBoolean ){_edaf ++;};case ResultTypeList ,ResultTypeArray :_edaf +=_edbeb (_bfcc .ListValues (),_ffada );case ResultTypeString :if _ffada ==_ecaa {_edaf ++;};case ResultTypeEmpty :if _ffada ==_afe {_edaf ++;};};};return _edaf ;};
@@ -345,7 +346,7 @@ func (_bdeg Number )String ()string {return _ff .FormatFloat (_bdeg ._becb ,'f',
 func (_baa CellRef )Update (q *_cc .UpdateQuery )Expression {if q .UpdateCurrentSheet {_baa ._bda =_eef (_baa ._bda ,q );};return _baa ;};func _cbag (_cfeee []Result ,_gcbfa bool )(float64 ,float64 ){_becfa :=0.0;_edgc :=0.0;for _ ,_ebeg :=range _cfeee {switch _ebeg .Type {case ResultTypeNumber :if _gcbfa ||!_ebeg .IsBoolean {_edgc +=_ebeg .ValueNumber ;_becfa ++;};case ResultTypeList ,ResultTypeArray :_bfda ,_aaec :=_cbag (_ebeg .ListValues (),_gcbfa );_edgc +=_bfda ;_becfa +=_aaec ;case ResultTypeString :if _gcbfa {_becfa ++;};case ResultTypeEmpty :};};return _edgc ,_becfa ;};
 
 // Reference returns an invalid reference for Bool.
-func (_agdc Bool )Reference (ctx Context ,ev Evaluator )Reference {return ReferenceInvalid };func _ba (_ccg Result )bool {if _ccg .Type ==ResultTypeString {return _ccg .ValueString =="";};return _ccg .ValueNumber ==0;};const (ErrorTypeValue ErrorType =iota ;ErrorTypeNull ;ErrorTypeRef ;ErrorTypeName ;ErrorTypeNum ;ErrorTypeSpill ;ErrorTypeNA ;ErrorTypeDivideByZero ;);const _cecbb =57364;
+func (_agdc Bool )Reference (ctx Context ,ev Evaluator )Reference {return ReferenceInvalid };func _ba (_ccg Result )bool {if _ccg .Type ==ResultTypeString {return _ccg .ValueString =="";};return _ccg .ValueNumber ==0;};const (ErrorTypeValue ErrorType =iota ;ErrorTypeNull ;ErrorTypeRef ;ErrorTypeName ;ErrorTypeNum ;ErrorTypeSpill ;ErrorTypeNA ;ErrorTypeDivideByZero ;ErrorTypeCalc ;);const _cecbb =57364;
 
 // Product is an implementation of the Excel PRODUCT() function.
 func Product (args []Result )Result {_ebac :=1.0;for _ ,_cadda :=range args {_cadda =_cadda .AsNumber ();switch _cadda .Type {case ResultTypeNumber :_ebac *=_cadda .ValueNumber ;case ResultTypeList ,ResultTypeArray :_edea :=Product (_cadda .ListValues ());if _edea .Type !=ResultTypeNumber {return _edea ;};_ebac *=_edea .ValueNumber ;case ResultTypeString :case ResultTypeError :return _cadda ;case ResultTypeEmpty :default:return MakeErrorResult (_c .Sprintf ("\u0075\u006eha\u006e\u0064\u006ce\u0064\u0020\u0050\u0052ODU\u0043T(\u0029\u0020\u0061\u0072\u0067\u0075\u006den\u0074\u0020\u0074\u0079\u0070\u0065\u0020%\u0073",_cadda .Type ));};};return MakeNumberResult (_ebac );};
@@ -366,7 +367,7 @@ type Error struct{_gag string };func _acef (_dddd []Result ,_eea string )(*cumul
 func (_gcc Bool )Update (q *_cc .UpdateQuery )Expression {return _gcc };func _bceae (_dgee []Result ,_eecb bool )Result {var _fgaf string ;if _eecb {_fgaf ="\u004c\u0041\u0052G\u0045";}else {_fgaf ="\u0053\u004d\u0041L\u004c";};if len (_dgee )!=2{return MakeErrorResult (_fgaf +"\u0020\u0072\u0065qu\u0069\u0072\u0065\u0073\u0020\u0074\u0077\u006f\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0073");};_edeb :=_dgee [0];var _facgf [][]Result ;switch _edeb .Type {case ResultTypeArray :_facgf =_edeb .ValueArray ;case ResultTypeList :_facgf =[][]Result {_edeb .ValueList };default:return MakeErrorResult (_fgaf +"\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0066\u0069\u0072\u0073\u0074\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074 \u006f\u0066\u0020\u0074\u0079p\u0065\u0020a\u0072\u0072\u0061\u0079");};if len (_facgf )==0{return MakeErrorResult (_fgaf +"\u0020\u0072\u0065\u0071\u0075i\u0072\u0065\u0073\u0020\u0061\u0072\u0072\u0061\u0079\u0020\u0074\u006f\u0020c\u006f\u006e\u0074\u0061\u0069\u006e\u0020\u0061\u0074\u0020\u006c\u0065\u0061\u0073\u0074\u0020\u0031\u0020\u0072\u006f\u0077");};if _dgee [1].Type !=ResultTypeNumber {return MakeErrorResult (_fgaf +" \u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0073\u0065\u0063\u006f\u006e\u0064\u0020\u0061\u0072g\u0075\u006d\u0065\u006e\u0074\u0020\u006f\u0066\u0020\u0074yp\u0065\u0020\u006eu\u006db\u0065\u0072");};_cdbec :=_dgee [1].ValueNumber ;if _cdbec < 1{return MakeErrorResultType (ErrorTypeNum ,_fgaf +"\u0020\u0072e\u0071\u0075\u0069\u0072\u0065s\u0020\u0073\u0065\u0063\u006fn\u0064\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u006f\u0066\u0020\u0074\u0079\u0070\u0065\u0020\u006e\u0075\u006d\u0062\u0065\u0072\u0020\u006d\u006f\u0072\u0065\u0020\u0074\u0068\u0061\u006e\u0020\u0030");};_fafb :=int (_cdbec );if float64 (_fafb )!=_cdbec {return MakeErrorResultType (ErrorTypeNum ,_fgaf +"\u0020\u0072e\u0071\u0075\u0069\u0072\u0065s\u0020\u0073\u0065\u0063\u006fn\u0064\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u006f\u0066\u0020\u0074\u0079\u0070\u0065\u0020\u006e\u0075\u006d\u0062\u0065\u0072\u0020\u006d\u006f\u0072\u0065\u0020\u0074\u0068\u0061\u006e\u0020\u0030");};_dfded :=[]float64 {};for _ ,_edcg :=range _facgf {for _ ,_ebaf :=range _edcg {if _ebaf .Type ==ResultTypeNumber {_dfded =append (_dfded ,_ebaf .ValueNumber );};};};if _fafb > len (_dfded ){return MakeErrorResultType (ErrorTypeNum ,_fgaf +" \u0072\u0065\u0071u\u0069\u0072\u0065\u0073\u0020\u0073\u0065\u0063\u006f\u006e\u0064\u0020\u0061\u0072\u0067u\u006d\u0065\u006e\u0074\u0020\u006f\u0066\u0020\u0074\u0079\u0070\u0065\u0020n\u0075\u006d\u0062\u0065\u0072\u0020\u006c\u0065s\u0073\u0020\u006f\u0072\u0020\u0065\u0071\u0075\u0061\u006c\u0020\u0074\u0068\u0061\u006e\u0020t\u0068\u0065\u0020\u006e\u0075m\u0062\u0065\u0072\u0020\u006f\u0066\u0020\u006e\u0075\u006d\u0062\u0065\u0072s\u0020\u0069\u006e\u0020t\u0068\u0065\u0020\u0061\u0072\u0072\u0061\u0079");};_agbb :=_ea .MergeSort (_dfded );if _eecb {return MakeNumberResult (_agbb [len (_agbb )-_fafb ]);}else {return MakeNumberResult (_agbb [_fafb -1]);};};type durationArgs struct{_aedc float64 ;_cfa float64 ;_dcfg float64 ;_deec float64 ;_bfgg float64 ;_cadg int ;};
 
 // Columns implements the Excel COLUMNS function.
-func Columns (args []Result )Result {if len (args )< 1{return MakeErrorResult ("\u0043\u004fL\u0055\u004d\u004e\u0053\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u006f\u006e\u0065\u0020\u0061\u0072\u0067\u0075me\u006e\u0074");};_fcabe :=args [0];if _fcabe .Type !=ResultTypeArray &&_fcabe .Type !=ResultTypeList {return MakeErrorResult ("\u0043O\u004c\u0055M\u004e\u0053\u0020r\u0065\u0071\u0075\u0069\u0072\u0065\u0073 \u0066\u0069\u0072\u0073\u0074\u0020a\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u006f\u0066\u0020t\u0079\u0070\u0065\u0020\u0061\u0072\u0072\u0061\u0079");};_bfeb :=_fcabe .ValueArray ;if len (_bfeb )==0{return MakeErrorResult ("\u0043\u004f\u004c\u0055\u004d\u004e\u0053\u0020r\u0065\u0071\u0075ir\u0065\u0073\u0020\u0061\u0072\u0072a\u0079\u0020\u0074\u006f\u0020\u0063\u006f\u006e\u0074\u0061\u0069\u006e\u0020\u0061\u0074 \u006c\u0065\u0061\u0073\u0074\u0020\u0031\u0020r\u006f\u0077");};return MakeNumberResult (float64 (len (_bfeb [0])));};
+func Columns (args []Result )Result {if len (args )< 1{return MakeErrorResult ("\u0043\u004fL\u0055\u004d\u004e\u0053\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u006f\u006e\u0065\u0020\u0061\u0072\u0067\u0075me\u006e\u0074");};_fcabe :=args [0];if _fcabe .Type ==ResultTypeError {return _fcabe ;};if _fcabe .Type !=ResultTypeArray &&_fcabe .Type !=ResultTypeList {return MakeErrorResult ("\u0043O\u004c\u0055M\u004e\u0053\u0020r\u0065\u0071\u0075\u0069\u0072\u0065\u0073 \u0066\u0069\u0072\u0073\u0074\u0020a\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u006f\u0066\u0020t\u0079\u0070\u0065\u0020\u0061\u0072\u0072\u0061\u0079");};_bfeb :=_fcabe .ValueArray ;if len (_bfeb )==0{return MakeErrorResult ("\u0043\u004f\u004c\u0055\u004d\u004e\u0053\u0020r\u0065\u0071\u0075ir\u0065\u0073\u0020\u0061\u0072\u0072a\u0079\u0020\u0074\u006f\u0020\u0063\u006f\u006e\u0074\u0061\u0069\u006e\u0020\u0061\u0074 \u006c\u0065\u0061\u0073\u0074\u0020\u0031\u0020r\u006f\u0077");};return MakeNumberResult (float64 (len (_bfeb [0])));};
 
 // Index implements the Excel INDEX function.
 func Index (args []Result )Result {_adgb :=len (args );if _adgb < 2||_adgb > 3{return MakeErrorResult ("\u0049\u004e\u0044E\u0058\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0066\u0072\u006f\u006d\u0020\u006f\u006e\u0065\u0020\u0074\u006f\u0020\u0074\u0068\u0072\u0065\u0065\u0020a\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0073");};_dfeb :=args [0];if _dfeb .Type !=ResultTypeArray &&_dfeb .Type !=ResultTypeList {return MakeErrorResult ("\u0049\u004e\u0044\u0045\u0058\u0020\u0072e\u0071\u0075\u0069r\u0065\u0073\u0020\u0066i\u0072\u0073\u0074\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u006f\u0066\u0020\u0074\u0079\u0070\u0065\u0020\u0061\u0072\u0072\u0061\u0079");};_fefa :=args [1].AsNumber ();if _fefa .Type !=ResultTypeNumber {return MakeErrorResult ("I\u004e\u0044\u0045\u0058\u0020\u0072e\u0071\u0075\u0069\u0072\u0065\u0073 \u006e\u0075\u006d\u0065\u0072\u0069\u0063 \u0072\u006f\u0077\u0020\u0061\u0072\u0067\u0075\u006d\u0065n\u0074");};_dcad :=int (_fefa .ValueNumber )-1;_ceec :=-1;if _adgb ==3&&args [2].Type !=ResultTypeEmpty {_cgbd :=args [2].AsNumber ();if _cgbd .Type !=ResultTypeNumber {return MakeErrorResult ("I\u004e\u0044\u0045\u0058\u0020\u0072e\u0071\u0075\u0069\u0072\u0065\u0073 \u006e\u0075\u006d\u0065\u0072\u0069\u0063 \u0063\u006f\u006c\u0020\u0061\u0072\u0067\u0075\u006d\u0065n\u0074");};_ceec =int (_cgbd .ValueNumber )-1;};if _dcad ==-1&&_ceec ==-1{return MakeErrorResult ("\u0049\u004e\u0044EX\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073 \u0072o\u0077 \u006fr\u0020\u0063\u006f\u006c\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");};var _agfc []Result ;if _dfeb .Type ==ResultTypeArray {_facg :=_dfeb .ValueArray ;if _dcad < -1||_dcad >=len (_facg ){return MakeErrorResult ("\u0049\u004e\u0044\u0045\u0058\u0020\u0068\u0061\u0073\u0020\u0072o\u0077\u0020\u006f\u0075\u0074\u0020\u006f\u0066\u0020\u0072a\u006e\u0067\u0065");};if _dcad ==-1{if _ceec >=len (_facg [0]){return MakeErrorResult ("\u0049\u004e\u0044\u0045\u0058\u0020\u0068\u0061\u0073\u0020\u0063o\u006c\u0020\u006f\u0075\u0074\u0020\u006f\u0066\u0020\u0072a\u006e\u0067\u0065");};_ebgd :=[][]Result {};for _ ,_ebce :=range _facg {_dcfd :=_ebce [_ceec ];if _dcfd .Type ==ResultTypeEmpty {_dcfd =MakeNumberResult (0);};_ebgd =append (_ebgd ,[]Result {_dcfd });};return MakeArrayResult (_ebgd );};_agfc =_facg [_dcad ];}else {_caee :=_dfeb .ValueList ;if _dcad < -1||_dcad >=1{return MakeErrorResult ("\u0049\u004e\u0044\u0045\u0058\u0020\u0068\u0061\u0073\u0020\u0072o\u0077\u0020\u006f\u0075\u0074\u0020\u006f\u0066\u0020\u0072a\u006e\u0067\u0065");};if _dcad ==-1{if _ceec >=len (_caee ){return MakeErrorResult ("\u0049\u004e\u0044\u0045\u0058\u0020\u0068\u0061\u0073\u0020\u0063o\u006c\u0020\u006f\u0075\u0074\u0020\u006f\u0066\u0020\u0072a\u006e\u0067\u0065");};_afcbc :=_caee [_ceec ];if _afcbc .Type ==ResultTypeEmpty {_afcbc =MakeNumberResult (0);};return _afcbc ;};_agfc =_caee ;};if _ceec < -1||_ceec > len (_agfc ){return MakeErrorResult ("\u0049\u004e\u0044\u0045\u0058\u0020\u0068\u0061\u0073\u0020\u0063o\u006c\u0020\u006f\u0075\u0074\u0020\u006f\u0066\u0020\u0072a\u006e\u0067\u0065");};if _ceec ==-1{_acbc :=[]Result {};for _ ,_gcca :=range _agfc {if _gcca .Type ==ResultTypeEmpty {_acbc =append (_acbc ,MakeNumberResult (0));}else {_acbc =append (_acbc ,_gcca );};};return MakeArrayResult ([][]Result {_acbc });};_dbag :=_agfc [_ceec ];if _dbag .Type ==ResultTypeEmpty {return MakeNumberResult (0);};return _dbag ;};func _eaedc (_cedb []Result )(float64 ,float64 ,Result ){_cbfge :=0.0;_eceg :=1.0;for _ ,_cbee :=range _cedb {switch _cbee .Type {case ResultTypeNumber :_cbfge +=_cbee .ValueNumber ;_eceg *=_abgd (_cbee .ValueNumber );case ResultTypeList ,ResultTypeArray :_fgeba ,_gdcb ,_fcdgb :=_eaedc (_cbee .ListValues ());_cbfge +=_fgeba ;_eceg *=_abgd (_gdcb );if _fcdgb .Type ==ResultTypeError {return 0,0,_fcdgb ;};case ResultTypeString :return 0,0,MakeErrorResult ("M\u0055\u004c\u0054\u0049\u004e\u004f\u004d\u0049\u0041\u004c\u0028\u0029\u0020\u0072\u0065\u0071\u0075\u0069r\u0065\u0073\u0020\u006e\u0075\u006d\u0065\u0072\u0069\u0063 a\u0072\u0067\u0075m\u0065n\u0074\u0073");case ResultTypeError :return 0,0,_cbee ;};};return _cbfge ,_eceg ,_ffe ;};var _cdcea string =string ([]byte {92});
@@ -438,7 +439,7 @@ func (_bgaf HorizontalRange )Eval (ctx Context ,ev Evaluator )Result {_ggfa :=_b
 type PrefixVerticalRange struct{_edebe Expression ;_bgcag ,_gadad string ;};
 
 // Update updates references in the VerticalRange after removing a row/column.
-func (_egdef VerticalRange )Update (q *_cc .UpdateQuery )Expression {if q .UpdateType ==_cc .UpdateActionRemoveColumn {_fgeaf :=_egdef ;if q .UpdateCurrentSheet {_gebac :=q .ColumnIdx ;_fgeaf ._efdad =_cee (_egdef ._efdad ,_gebac );_fgeaf ._ddbe =_cee (_egdef ._ddbe ,_gebac );};return _fgeaf ;};return _egdef ;};
+func (_egdef VerticalRange )Update (q *_cc .UpdateQuery )Expression {return updateVerticalRange (_egdef ,q );};
 
 // And is an implementation of the Excel AND() function.
 func And (args []Result )Result {if len (args )==0{return MakeErrorResult ("\u0041\u004e\u0044 r\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0061t\u0020l\u0065a\u0073t\u0020\u006f\u006e\u0065\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");};_ddffd :=true ;for _ ,_dgebe :=range args {_dgebe =_dgebe .AsNumber ();switch _dgebe .Type {case ResultTypeList ,ResultTypeArray :_begc :=And (_dgebe .ListValues ());if _begc .Type ==ResultTypeError {return _begc ;};if _begc .ValueNumber ==0{_ddffd =false ;};case ResultTypeNumber :if _dgebe .ValueNumber ==0{_ddffd =false ;};case ResultTypeString :return MakeErrorResult ("\u0041\u004e\u0044\u0020\u0064\u006f\u0065\u0073\u006e\u0027t\u0020\u006f\u0070\u0065\u0072\u0061\u0074e\u0020\u006f\u006e\u0020\u0073\u0074\u0072\u0069\u006e\u0067\u0073");case ResultTypeError :return _dgebe ;default:return MakeErrorResult ("\u0075\u006e\u0073\u0075\u0070\u0070\u006f\u0072\u0074\u0065\u0064\u0020\u0061\u0072\u0067u\u006de\u006e\u0074\u0020\u0074\u0079\u0070\u0065\u0020\u0069\u006e\u0020\u0041\u004e\u0044");};};return MakeBoolResult (_ddffd );};
@@ -454,13 +455,13 @@ func Amorlinc (args []Result )Result {_gddg ,_dgac :=_aebc (args ,"\u0041\u004d\
 func Code (args []Result )Result {if len (args )!=1{return MakeErrorResult ("\u0043\u004f\u0044\u0045\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0061\u0020\u0073\u0069\u006e\u0067\u006c\u0065\u0020\u0073t\u0072\u0069\u006e\u0067\u0020a\u0072\u0067u\u006d\u0065\u006e\u0074");};_cfec :=args [0].AsString ();if _cfec .Type !=ResultTypeString {return MakeErrorResult ("\u0043\u004f\u0044\u0045\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0061\u0020\u0073\u0069\u006e\u0067\u006c\u0065\u0020\u0073t\u0072\u0069\u006e\u0067\u0020a\u0072\u0067u\u006d\u0065\u006e\u0074");};if len (_cfec .ValueString )==0{return MakeNumberResult (0);};return MakeNumberResult (float64 (_cfec .ValueString [0]));};func _dgedb (_gfcf []Result ,_edfef bool )Result {_cafef :="\u004d\u0049\u004e";if _edfef {_cafef ="\u004d\u0049\u004e\u0041";};if len (_gfcf )==0{return MakeErrorResult (_cafef +"\u0020\u0072\u0065q\u0075\u0069\u0072\u0065s\u0020\u0061\u0074\u0020\u006c\u0065\u0061s\u0074\u0020\u006f\u006e\u0065\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");};_gfbdb :=_dc .MaxFloat64 ;for _ ,_gcbg :=range _gfcf {switch _gcbg .Type {case ResultTypeNumber :if (_edfef ||!_gcbg .IsBoolean )&&_gcbg .ValueNumber < _gfbdb {_gfbdb =_gcbg .ValueNumber ;};case ResultTypeList ,ResultTypeArray :_cbgc :=_dgedb (_gcbg .ListValues (),_edfef );if _cbgc .ValueNumber < _gfbdb {_gfbdb =_cbgc .ValueNumber ;};case ResultTypeEmpty :case ResultTypeString :_edbc :=0.0;if _edfef {_edbc =_gcbg .AsNumber ().ValueNumber ;};if _edbc < _gfbdb {_gfbdb =_edbc ;};default:_ge .Log ("\u0075\u006e\u0068\u0061\u006e\u0064\u006c\u0065\u0064\u0020"+_cafef +"\u0028\u0029\u0020\u0061rg\u0075\u006d\u0065\u006e\u0074\u0020\u0074\u0079\u0070\u0065\u0020\u0025\u0073",_gcbg .Type );};};if _gfbdb ==_dc .MaxFloat64 {_gfbdb =0;};return MakeNumberResult (_gfbdb );};
 
 // Rows implements the Excel ROWS function.
-func Rows (args []Result )Result {if len (args )< 1{return MakeErrorResult ("\u0052\u004f\u0057\u0053\u0020\u0072\u0065\u0071\u0075\u0069\u0072e\u0073\u0020\u006f\u006e\u0065\u0020\u0061\u0072\u0067\u0075m\u0065\u006e\u0074");};_cdfa :=args [0];if _cdfa .Type !=ResultTypeArray &&_cdfa .Type !=ResultTypeList {return MakeErrorResult ("\u0052\u004f\u0057S\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0066\u0069\u0072\u0073\u0074\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u006f\u0066\u0020\u0074y\u0070\u0065\u0020\u0061\u0072\u0072\u0061\u0079");};_cdafg :=_cdfa .ValueArray ;if len (_cdafg )==0{return MakeErrorResult ("\u0052O\u0057\u0053 \u0072\u0065\u0071u\u0069\u0072\u0065\u0073\u0020\u0061\u0072r\u0061\u0079\u0020\u0074\u006f\u0020c\u006f\u006e\u0074\u0061\u0069\u006e\u0020\u0061\u0074\u0020\u006ce\u0061\u0073\u0074\u0020\u0031\u0020\u0072\u006f\u0077");};return MakeNumberResult (float64 (len (_cdafg )));};
+func Rows (args []Result )Result {if len (args )< 1{return MakeErrorResult ("\u0052\u004f\u0057\u0053\u0020\u0072\u0065\u0071\u0075\u0069\u0072e\u0073\u0020\u006f\u006e\u0065\u0020\u0061\u0072\u0067\u0075m\u0065\u006e\u0074");};_cdfa :=args [0];if _cdfa .Type ==ResultTypeError {return _cdfa ;};if _cdfa .Type !=ResultTypeArray &&_cdfa .Type !=ResultTypeList {return MakeErrorResult ("\u0052\u004f\u0057S\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0066\u0069\u0072\u0073\u0074\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u006f\u0066\u0020\u0074y\u0070\u0065\u0020\u0061\u0072\u0072\u0061\u0079");};_cdafg :=_cdfa .ValueArray ;if len (_cdafg )==0{return MakeErrorResult ("\u0052O\u0057\u0053 \u0072\u0065\u0071u\u0069\u0072\u0065\u0073\u0020\u0061\u0072r\u0061\u0079\u0020\u0074\u006f\u0020c\u006f\u006e\u0074\u0061\u0069\u006e\u0020\u0061\u0074\u0020\u006ce\u0061\u0073\u0074\u0020\u0031\u0020\u0072\u006f\u0077");};return MakeNumberResult (float64 (len (_cdafg )));};
 
 // Ifs is an implementation of the Excel IFS() function.
 func Ifs (args []Result )Result {if len (args )< 2{return MakeErrorResult ("I\u0046\u0053\u0020\u0072\u0065\u0071u\u0069\u0072\u0065\u0073\u0020\u0061t\u0020\u006c\u0065\u0061\u0073\u0074\u0020t\u0077\u006f\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006et\u0073");};for _ecaca :=0;_ecaca < len (args )-1;_ecaca +=2{if args [_ecaca ].ValueNumber ==1{return args [_ecaca +1];};};return MakeErrorResultType (ErrorTypeNA ,"");};
 
 // Minute is an implementation of the Excel MINUTE() function.
-func Minute (args []Result )Result {if len (args )!=1{return MakeErrorResult ("\u004d\u0049\u004e\u0055T\u0045\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073 \u006fn\u0065\u0020\u0061\u0072\u0067\u0075\u006de\u006e\u0074");};_ffb :=args [0];switch _ffb .Type {case ResultTypeEmpty :return MakeNumberResult (0);case ResultTypeNumber :_efd :=_fae (_ffb .ValueNumber );return MakeNumberResult (float64 (_efd .Minute ()));case ResultTypeString :_acd :=_ee .ToLower (_ffb .ValueString );if !_efab (_acd ){_ ,_ ,_ ,_feg ,_caa :=_dfb (_acd );if _caa .Type ==ResultTypeError {_caa .ErrorMessage ="\u0049\u006e\u0063\u006f\u0072\u0072\u0065\u0063\u0074\u0020a\u0072\u0067\u0075\u006d\u0065\u006e\u0074s\u0020\u0066\u006f\u0072\u0020\u004d\u0049\u004e\u0055\u0054\u0045";return _caa ;};if _feg {return MakeNumberResult (0);};};_ ,_ffdc ,_ ,_ ,_ ,_gge :=_ffge (_acd );if _gge .Type ==ResultTypeError {return _gge ;};return MakeNumberResult (float64 (_ffdc ));default:return MakeErrorResult ("\u0049\u006ec\u006f\u0072\u0072\u0065\u0063\u0074\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u0066\u006f\u0072\u0020\u004d\u0049NU\u0054\u0045");};};func Parse (r _e .Reader )Expression {_ffdg :=&plex {LexReader (r ),nil };_ffedd (_ffdg );return _ffdg ._deddc ;};
+func Minute (args []Result )Result {if len (args )!=1{return MakeErrorResult ("\u004d\u0049\u004e\u0055T\u0045\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073 \u006fn\u0065\u0020\u0061\u0072\u0067\u0075\u006de\u006e\u0074");};_ffb :=args [0];switch _ffb .Type {case ResultTypeEmpty :return MakeNumberResult (0);case ResultTypeNumber :_efd :=_fae (_ffb .ValueNumber );return MakeNumberResult (float64 (_efd .Minute ()));case ResultTypeString :_acd :=_ee .ToLower (_ffb .ValueString );if !_efab (_acd ){_ ,_ ,_ ,_feg ,_caa :=_dfb (_acd );if _caa .Type ==ResultTypeError {_caa .ErrorMessage ="\u0049\u006e\u0063\u006f\u0072\u0072\u0065\u0063\u0074\u0020a\u0072\u0067\u0075\u006d\u0065\u006e\u0074s\u0020\u0066\u006f\u0072\u0020\u004d\u0049\u004e\u0055\u0054\u0045";return _caa ;};if _feg {return MakeNumberResult (0);};};_ ,_ffdc ,_ ,_ ,_ ,_gge :=_ffge (_acd );if _gge .Type ==ResultTypeError {return _gge ;};return MakeNumberResult (float64 (_ffdc ));default:return MakeErrorResult ("\u0049\u006ec\u006f\u0072\u0072\u0065\u0063\u0074\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u0066\u006f\u0072\u0020\u004d\u0049NU\u0054\u0045");};};func Parse (r _e .Reader )Expression {_ffdg :=&plex {lexStructured (r ),nil };_ffedd (_ffdg );return _ffdg ._deddc ;};
 
 // NewRange constructs a new range.
 func NewRange (from ,to Expression )Expression {return Range {from ,to }};
@@ -551,10 +552,10 @@ func Ispmt (args []Result )Result {if len (args )!=4{return MakeErrorResult ("\u
 func IfNA (args []Result )Result {if len (args )!=2{return MakeErrorResult ("I\u0046\u004e\u0041\u0020\u0072\u0065q\u0075\u0069\u0072\u0065\u0073\u0020\u0074\u0077\u006f \u0061\u0072\u0067u\u006de\u006e\u0074\u0073");};if args [0].Type ==ResultTypeError &&args [0].ValueString =="\u0023\u004e\u002f\u0041"{return args [1];};return args [0];};
 
 // Eval evaluates and returns a boolean.
-func (_fd Bool )Eval (ctx Context ,ev Evaluator )Result {return MakeBoolResult (_fd ._gfbd )};func init (){_cdcb =_gb .New (_gb .NewSource (_ce .Now ().UnixNano ()));RegisterFunction ("\u0041\u0042\u0053",_ceggf ("\u0041\u0053\u0049\u004e",_dc .Abs ));RegisterFunction ("\u0041\u0043\u004f\u0053",_ceggf ("\u0041\u0053\u0049\u004e",_dc .Acos ));RegisterFunction ("\u0041\u0043\u004fS\u0048",_ceggf ("\u0041\u0053\u0049\u004e",_dc .Acosh ));RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0041\u0043\u004f\u0054",_ceggf ("\u0041\u0043\u004f\u0054",func (_dgeg float64 )float64 {return _dc .Pi /2-_dc .Atan (_dgeg )}));RegisterFunction ("_\u0078\u006c\u0066\u006e\u002e\u0041\u0043\u004f\u0054\u0048",_ceggf ("\u0041\u0043\u004fT\u0048",func (_cdffe float64 )float64 {return _dc .Atanh (1/_cdffe )}));RegisterFunction ("\u005f\u0078\u006cf\u006e\u002e\u0041\u0052\u0041\u0042\u0049\u0043",Arabic );RegisterFunction ("\u0041\u0053\u0049\u004e",_ceggf ("\u0041\u0053\u0049\u004e",_dc .Asin ));RegisterFunction ("\u0041\u0053\u0049N\u0048",_ceggf ("\u0041\u0053\u0049N\u0048",_dc .Asinh ));RegisterFunction ("\u0041\u0054\u0041\u004e",_ceggf ("\u0041\u0054\u0041\u004e",_dc .Atan ));RegisterFunction ("\u0041\u0054\u0041N\u0048",_ceggf ("\u0041\u0054\u0041N\u0048",_dc .Atanh ));RegisterFunction ("\u0041\u0054\u0041N\u0032",Atan2 );RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0042\u0041\u0053\u0045",Base );RegisterFunction ("\u0043E\u0049\u004c\u0049\u004e\u0047",Ceiling );RegisterFunction ("\u005fx\u006cf\u006e\u002e\u0043\u0045\u0049L\u0049\u004eG\u002e\u004d\u0041\u0054\u0048",CeilingMath );RegisterFunction ("_\u0078\u006c\u0066\u006e.C\u0045I\u004c\u0049\u004e\u0047\u002eP\u0052\u0045\u0043\u0049\u0053\u0045",CeilingPrecise );RegisterFunction ("\u0043\u004f\u004d\u0042\u0049\u004e",Combin );RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0043\u004f\u004d\u0042\u0049\u004e\u0041",Combina );RegisterFunction ("\u0043\u004f\u0053",_ceggf ("\u0043\u004f\u0053",_dc .Cos ));RegisterFunction ("\u0043\u004f\u0053\u0048",_ceggf ("\u0043\u004f\u0053\u0048",_dc .Cosh ));RegisterFunction ("\u005fx\u006c\u0066\u006e\u002e\u0043\u004fT",_cefc ("\u0043\u004f\u0054",_dc .Tan ));RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0043\u004f\u0054\u0048",_cefc ("\u0043\u004f\u0054\u0048",_dc .Tanh ));RegisterFunction ("\u005fx\u006c\u0066\u006e\u002e\u0043\u0053C",_cefc ("\u0043\u0053\u0043",_dc .Sin ));RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0043\u0053\u0043\u0048",_cefc ("\u0043\u0053\u0043",_dc .Sinh ));RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0044\u0045\u0043\u0049\u004d\u0041\u004c",Decimal );RegisterFunction ("\u0044E\u0047\u0052\u0045\u0045\u0053",Degrees );RegisterFunction ("\u0045\u0056\u0045\u004e",Even );RegisterFunction ("\u0045\u0058\u0050",_ceggf ("\u0045\u0058\u0050",_dc .Exp ));RegisterFunction ("\u0046\u0041\u0043\u0054",Fact );RegisterFunction ("\u0046\u0041\u0043\u0054\u0044\u004f\u0055\u0042\u004c\u0045",FactDouble );RegisterFunction ("\u0046\u004c\u004fO\u0052",Floor );RegisterFunction ("\u005f\u0078l\u0066\u006e\u002eF\u004c\u004f\u004f\u0052\u002e\u004d\u0041\u0054\u0048",FloorMath );RegisterFunction ("\u005f\u0078\u006c\u0066n.\u0046\u004c\u004f\u004f\u0052\u002e\u0050\u0052\u0045\u0043\u0049\u0053\u0045",FloorPrecise );RegisterFunction ("\u0047\u0043\u0044",GCD );RegisterFunction ("\u0049\u004e\u0054",Int );RegisterFunction ("I\u0053\u004f\u002e\u0043\u0045\u0049\u004c\u0049\u004e\u0047",CeilingPrecise );RegisterFunction ("\u004c\u0043\u004d",LCM );RegisterFunction ("\u004c\u004e",_ceggf ("\u004c\u004e",_dc .Log ));RegisterFunction ("\u004c\u004f\u0047",Log );RegisterFunction ("\u004c\u004f\u00471\u0030",_ceggf ("\u004c\u004f\u00471\u0030",_dc .Log10 ));RegisterFunction ("\u004dD\u0045\u0054\u0045\u0052\u004d",MDeterm );RegisterFunction ("\u004d\u004f\u0044",Mod );RegisterFunction ("\u004d\u0052\u004f\u0055\u004e\u0044",Mround );RegisterFunction ("M\u0055\u004c\u0054\u0049\u004e\u004f\u004d\u0049\u0041\u004c",Multinomial );RegisterFunction ("_\u0078\u006c\u0066\u006e\u002e\u004d\u0055\u004e\u0049\u0054",Munit );RegisterFunction ("\u004f\u0044\u0044",Odd );RegisterFunction ("\u0050\u0049",Pi );RegisterFunction ("\u0050\u004f\u0057E\u0052",Power );RegisterFunction ("\u0050R\u004f\u0044\u0055\u0043\u0054",Product );RegisterFunction ("\u0051\u0055\u004f\u0054\u0049\u0045\u004e\u0054",Quotient );RegisterFunction ("\u0052A\u0044\u0049\u0041\u004e\u0053",Radians );RegisterFunction ("\u0052\u0041\u004e\u0044",Rand );RegisterFunction ("R\u0041\u004e\u0044\u0042\u0045\u0054\u0057\u0045\u0045\u004e",RandBetween );RegisterFunction ("\u0052\u004f\u004dA\u004e",Roman );RegisterFunction ("\u0052\u004f\u0055N\u0044",Round );RegisterFunction ("\u0052O\u0055\u004e\u0044\u0044\u004f\u0057N",RoundDown );RegisterFunction ("\u0052O\u0055\u004e\u0044\u0055\u0050",RoundUp );RegisterFunction ("\u005fx\u006c\u0066\u006e\u002e\u0053\u0045C",_cefc ("\u0053\u0045\u0043",_dc .Cos ));RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0053\u0045\u0043\u0048",_cefc ("\u0053\u0045\u0043\u0048",_dc .Cosh ));RegisterFunction ("\u0053E\u0052\u0049\u0045\u0053\u0053\u0055M",SeriesSum );RegisterFunction ("\u0053\u0049\u0047\u004e",Sign );RegisterFunction ("\u0053\u0049\u004e",_ceggf ("\u0053\u0049\u004e",_dc .Sin ));RegisterFunction ("\u0053\u0049\u004e\u0048",_ceggf ("\u0053\u0049\u004e\u0048",_dc .Sinh ));RegisterFunction ("\u0053\u0051\u0052\u0054",_ceggf ("\u0053\u0051\u0052\u0054",_dc .Sqrt ));RegisterFunction ("\u0053\u0051\u0052\u0054\u0050\u0049",_ceggf ("\u0053\u0051\u0052\u0054\u0050\u0049",func (_fafc float64 )float64 {return _dc .Sqrt (_fafc *_dc .Pi )}));RegisterFunction ("\u0053\u0055\u004d",Sum );RegisterFunction ("\u0053\u0055\u004dI\u0046",SumIf );RegisterFunction ("\u0053\u0055\u004d\u0049\u0046\u0053",SumIfs );RegisterFunction ("\u0053\u0055\u004d\u0050\u0052\u004f\u0044\u0055\u0043\u0054",SumProduct );RegisterFunction ("\u0053\u0055\u004dS\u0051",SumSquares );RegisterFunction ("\u0054\u0041\u004e",_ceggf ("\u0054\u0041\u004e",_dc .Tan ));RegisterFunction ("\u0054\u0041\u004e\u0048",_ceggf ("\u0054\u0041\u004e\u0048",_dc .Tanh ));RegisterFunction ("\u0054\u0052\u0055N\u0043",Trunc );};func _faf (_fbfd ,_feab ,_afa ,_acee float64 ,_cage int )float64 {var _gafa float64 ;if _fbfd ==0{_gafa =(_afa +_acee )/_feab ;}else {_aaea :=_dc .Pow (1+_fbfd ,_feab );if _cage ==1{_gafa =(_acee *_fbfd /(_aaea -1)+_afa *_fbfd /(1-1/_aaea ))/(1+_fbfd );}else {_gafa =_acee *_fbfd /(_aaea -1)+_afa *_fbfd /(1-1/_aaea );};};return -_gafa ;};
+func (_fd Bool )Eval (ctx Context ,ev Evaluator )Result {return MakeBoolResult (_fd ._gfbd )};func init (){_cdcb =_gb .New (_gb .NewSource (_ce .Now ().UnixNano ()));RegisterFunction ("\u0041\u0042\u0053",_ceggf ("\u0041\u0053\u0049\u004e",_dc .Abs ));RegisterFunction ("\u0041\u0043\u004f\u0053",_ceggf ("\u0041\u0053\u0049\u004e",_dc .Acos ));RegisterFunction ("\u0041\u0043\u004fS\u0048",_ceggf ("\u0041\u0053\u0049\u004e",_dc .Acosh ));RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0041\u0043\u004f\u0054",_ceggf ("\u0041\u0043\u004f\u0054",func (_dgeg float64 )float64 {return _dc .Pi /2-_dc .Atan (_dgeg )}));RegisterFunction ("_\u0078\u006c\u0066\u006e\u002e\u0041\u0043\u004f\u0054\u0048",_ceggf ("\u0041\u0043\u004fT\u0048",func (_cdffe float64 )float64 {return _dc .Atanh (1/_cdffe )}));RegisterFunction ("\u005f\u0078\u006cf\u006e\u002e\u0041\u0052\u0041\u0042\u0049\u0043",Arabic );RegisterFunction ("\u0041\u0053\u0049\u004e",_ceggf ("\u0041\u0053\u0049\u004e",_dc .Asin ));RegisterFunction ("\u0041\u0053\u0049N\u0048",_ceggf ("\u0041\u0053\u0049N\u0048",_dc .Asinh ));RegisterFunction ("\u0041\u0054\u0041\u004e",_ceggf ("\u0041\u0054\u0041\u004e",_dc .Atan ));RegisterFunction ("\u0041\u0054\u0041N\u0048",_ceggf ("\u0041\u0054\u0041N\u0048",_dc .Atanh ));RegisterFunction ("\u0041\u0054\u0041N\u0032",Atan2 );RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0042\u0041\u0053\u0045",Base );RegisterFunction ("\u0043E\u0049\u004c\u0049\u004e\u0047",Ceiling );RegisterFunction ("\u005fx\u006cf\u006e\u002e\u0043\u0045\u0049L\u0049\u004eG\u002e\u004d\u0041\u0054\u0048",CeilingMath );RegisterFunction ("_\u0078\u006c\u0066\u006e.C\u0045I\u004c\u0049\u004e\u0047\u002eP\u0052\u0045\u0043\u0049\u0053\u0045",CeilingPrecise );RegisterFunction ("\u0043\u004f\u004d\u0042\u0049\u004e",Combin );RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0043\u004f\u004d\u0042\u0049\u004e\u0041",Combina );RegisterFunction ("\u0043\u004f\u0053",_ceggf ("\u0043\u004f\u0053",_dc .Cos ));RegisterFunction ("\u0043\u004f\u0053\u0048",_ceggf ("\u0043\u004f\u0053\u0048",_dc .Cosh ));RegisterFunction ("\u005fx\u006c\u0066\u006e\u002e\u0043\u004fT",_cefc ("\u0043\u004f\u0054",_dc .Tan ));RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0043\u004f\u0054\u0048",_cefc ("\u0043\u004f\u0054\u0048",_dc .Tanh ));RegisterFunction ("\u005fx\u006c\u0066\u006e\u002e\u0043\u0053C",_cefc ("\u0043\u0053\u0043",_dc .Sin ));RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0043\u0053\u0043\u0048",_cefc ("\u0043\u0053\u0043",_dc .Sinh ));RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0044\u0045\u0043\u0049\u004d\u0041\u004c",Decimal );RegisterFunction ("\u0044E\u0047\u0052\u0045\u0045\u0053",Degrees );RegisterFunction ("\u0045\u0056\u0045\u004e",Even );RegisterFunction ("\u0045\u0058\u0050",_ceggf ("\u0045\u0058\u0050",_dc .Exp ));RegisterFunction ("\u0046\u0041\u0043\u0054",Fact );RegisterFunction ("\u0046\u0041\u0043\u0054\u0044\u004f\u0055\u0042\u004c\u0045",FactDouble );RegisterFunction ("\u0046\u004c\u004fO\u0052",Floor );RegisterFunction ("\u005f\u0078l\u0066\u006e\u002eF\u004c\u004f\u004f\u0052\u002e\u004d\u0041\u0054\u0048",FloorMath );RegisterFunction ("\u005f\u0078\u006c\u0066n.\u0046\u004c\u004f\u004f\u0052\u002e\u0050\u0052\u0045\u0043\u0049\u0053\u0045",FloorPrecise );RegisterFunction ("\u0047\u0043\u0044",GCD );RegisterFunction ("\u0049\u004e\u0054",Int );RegisterFunction ("I\u0053\u004f\u002e\u0043\u0045\u0049\u004c\u0049\u004e\u0047",CeilingPrecise );RegisterFunction ("\u004c\u0043\u004d",LCM );RegisterFunction ("\u004c\u004e",_ceggf ("\u004c\u004e",_dc .Log ));RegisterFunction ("\u004c\u004f\u0047",Log );RegisterFunction ("\u004c\u004f\u00471\u0030",_ceggf ("\u004c\u004f\u00471\u0030",_dc .Log10 ));RegisterFunction ("\u004dD\u0045\u0054\u0045\u0052\u004d",MDeterm );RegisterFunction ("\u004d\u004f\u0044",Mod );RegisterFunction ("\u004d\u0052\u004f\u0055\u004e\u0044",Mround );RegisterFunction ("M\u0055\u004c\u0054\u0049\u004e\u004f\u004d\u0049\u0041\u004c",Multinomial );RegisterFunction ("_\u0078\u006c\u0066\u006e\u002e\u004d\u0055\u004e\u0049\u0054",Munit );RegisterFunction ("\u004f\u0044\u0044",Odd );RegisterFunction ("\u0050\u0049",Pi );RegisterFunction ("\u0050\u004f\u0057E\u0052",Power );RegisterFunction ("\u0050R\u004f\u0044\u0055\u0043\u0054",Product );RegisterFunction ("\u0051\u0055\u004f\u0054\u0049\u0045\u004e\u0054",Quotient );RegisterFunction ("\u0052A\u0044\u0049\u0041\u004e\u0053",Radians );RegisterFunction ("\u0052\u004f\u004dA\u004e",Roman );RegisterFunction ("\u0052\u004f\u0055N\u0044",Round );RegisterFunction ("\u0052O\u0055\u004e\u0044\u0044\u004f\u0057N",RoundDown );RegisterFunction ("\u0052O\u0055\u004e\u0044\u0055\u0050",RoundUp );RegisterFunction ("\u005fx\u006c\u0066\u006e\u002e\u0053\u0045C",_cefc ("\u0053\u0045\u0043",_dc .Cos ));RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0053\u0045\u0043\u0048",_cefc ("\u0053\u0045\u0043\u0048",_dc .Cosh ));RegisterFunction ("\u0053E\u0052\u0049\u0045\u0053\u0053\u0055M",SeriesSum );RegisterFunction ("\u0053\u0049\u0047\u004e",Sign );RegisterFunction ("\u0053\u0049\u004e",_ceggf ("\u0053\u0049\u004e",_dc .Sin ));RegisterFunction ("\u0053\u0049\u004e\u0048",_ceggf ("\u0053\u0049\u004e\u0048",_dc .Sinh ));RegisterFunction ("\u0053\u0051\u0052\u0054",_ceggf ("\u0053\u0051\u0052\u0054",_dc .Sqrt ));RegisterFunction ("\u0053\u0051\u0052\u0054\u0050\u0049",_ceggf ("\u0053\u0051\u0052\u0054\u0050\u0049",func (_fafc float64 )float64 {return _dc .Sqrt (_fafc *_dc .Pi )}));RegisterFunction ("\u0053\u0055\u004d",Sum );RegisterFunction ("\u0053\u0055\u004dI\u0046",SumIf );RegisterFunction ("\u0053\u0055\u004d\u0049\u0046\u0053",SumIfs );RegisterFunction ("\u0053\u0055\u004d\u0050\u0052\u004f\u0044\u0055\u0043\u0054",SumProduct );RegisterFunction ("\u0053\u0055\u004dS\u0051",SumSquares );RegisterFunction ("\u0054\u0041\u004e",_ceggf ("\u0054\u0041\u004e",_dc .Tan ));RegisterFunction ("\u0054\u0041\u004e\u0048",_ceggf ("\u0054\u0041\u004e\u0048",_dc .Tanh ));RegisterFunction ("\u0054\u0052\u0055N\u0043",Trunc );};func _faf (_fbfd ,_feab ,_afa ,_acee float64 ,_cage int )float64 {var _gafa float64 ;if _fbfd ==0{_gafa =(_afa +_acee )/_feab ;}else {_aaea :=_dc .Pow (1+_fbfd ,_feab );if _cage ==1{_gafa =(_acee *_fbfd /(_aaea -1)+_afa *_fbfd /(1-1/_aaea ))/(1+_fbfd );}else {_gafa =_acee *_fbfd /(_aaea -1)+_afa *_fbfd /(1-1/_aaea );};};return -_gafa ;};
 
 // String returns a string representation for Negate.
-func (_edfdc Negate )String ()string {return "\u002d"+_edfdc ._ggbdc .String ()};
+func (_edfdc Negate )String ()string {return "\u002d"+operand (_edfdc ._ggbdc ,negatePrecedence ,false )};
 
 // String returns a string representation of a vertical range with prefix.
 func (_fece PrefixVerticalRange )String ()string {return _c .Sprintf ("\u0025\u0073\u0021\u0025\u0073\u003a\u0025\u0073",_fece ._edebe .String (),_fece ._bgcag ,_fece ._gadad );};func _dacd (_fcggd ,_ggcdb []float64 ,_bace float64 )Result {_dbfdd :=false ;_fecg :=false ;for _fcde :=0;_fcde < len (_fcggd );_fcde ++{if _fcggd [_fcde ]> 0{_dbfdd =true ;};if _fcggd [_fcde ]< 0{_fecg =true ;};};if !_dbfdd ||!_fecg {return MakeErrorResultType (ErrorTypeNum ,"");};_ffga :=_bace ;_eebe :=1e-10;_ggcff :=0;_baab :=50;_daeec :=false ;for {_cegg :=_ggg (_fcggd ,_ggcdb ,_ffga );_fag :=_ffga -_cegg /_bbfbe (_fcggd ,_ggcdb ,_ffga );_cafg :=_dc .Abs (_fag -_ffga );_ffga =_fag ;_ggcff ++;if _cafg <=_eebe ||_dc .Abs (_cegg )<=_eebe {break ;};if _ggcff > _baab {_daeec =true ;break ;};};if _daeec ||_dc .IsNaN (_ffga )||_dc .IsInf (_ffga ,0){return MakeErrorResultType (ErrorTypeNum ,"");};return MakeNumberResult (_ffga );};
@@ -593,21 +594,21 @@ func Rand (args []Result )Result {if len (args )!=0{return MakeErrorResult ("R\u
 func SumSquares (args []Result )Result {_bfac :=MakeNumberResult (0);for _ ,_dcba :=range args {_dcba =_dcba .AsNumber ();switch _dcba .Type {case ResultTypeNumber :_bfac .ValueNumber +=_dcba .ValueNumber *_dcba .ValueNumber ;case ResultTypeList ,ResultTypeArray :_aade :=SumSquares (_dcba .ListValues ());if _aade .Type !=ResultTypeNumber {return _aade ;};_bfac .ValueNumber +=_aade .ValueNumber ;case ResultTypeString :case ResultTypeError :return _dcba ;case ResultTypeEmpty :default:return MakeErrorResult (_c .Sprintf ("\u0075\u006e\u0068\u0061\u006e\u0064\u006c\u0065\u0064\u0020\u0053\u0055\u004dS\u0051\u0055\u0041\u0052\u0045\u0053(\u0029\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u0074\u0079p\u0065\u0020\u0025\u0073",_dcba .Type ));};};return _bfac ;};type parsedReplaceObject struct{_ddcce string ;_ebaa int ;_gcdcf int ;_baef string ;};type yyParserImpl struct{_dgacb yySymType ;_gbead [_eacd ]yySymType ;_gafef int ;};
 
 // Update updates references in the Range after removing a row/column.
-func (_ecafb Range )Update (q *_cc .UpdateQuery )Expression {_fegad :=_ecafb ;if q .UpdateCurrentSheet {_fegad ._eadcf =_ecafb ._eadcf .Update (q );_fegad ._dfbgf =_ecafb ._dfbgf .Update (q );};return _fegad ;};
+func (_ecafb Range )Update (q *_cc .UpdateQuery )Expression {if q .UpdateCurrentSheet {return updateRangeExpr (_ecafb ._eadcf ,_ecafb ._dfbgf ,q );};return _ecafb ;};
 
 // Right implements the Excel RIGHT(string,[n]) function which returns the
 // rightmost n characters.
 func Right (args []Result )Result {_bbdgc :=1;switch len (args ){case 1:case 2:if args [1].Type !=ResultTypeNumber {return MakeErrorResult ("\u0052\u0049\u0047\u0048\u0054\u0020\u0065\u0078\u0070\u0065c\u0074\u0065\u0064\u0020\u006e\u0075\u006db\u0065\u0072\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");};_bbdgc =int (args [1].ValueNumber );if _bbdgc < 0{return MakeErrorResult ("R\u0049\u0047\u0048\u0054\u0020\u0065x\u0070\u0065\u0063\u0074\u0065\u0064 \u006e\u0075\u006d\u0062\u0065\u0072\u0020a\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u003e\u003d \u0030");};if _bbdgc ==0{return MakeStringResult ("");};default:return MakeErrorResult ("\u0052\u0049\u0047HT\u0020\u0061\u0063\u0063\u0065\u0070\u0074\u0073\u0020o\u006ee\u0020o\u0072 \u0074\u0077\u006f\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0073");};if args [0].Type ==ResultTypeList {return MakeErrorResult ("\u0052\u0049\u0047\u0048\u0054\u0020\u0063\u0061\u006e\u0027\u0074\u0020\u0062\u0065\u0020c\u0061l\u006c\u0065\u0064\u0020\u006f\u006e\u0020\u0061\u0020\u0072\u0061\u006e\u0067\u0065");};_ddcd :=args [0].Value ();_eebg :=len (_ddcd );if _bbdgc > _eebg {return MakeStringResult (_ddcd );};return MakeStringResult (_ddcd [_eebg -_bbdgc :_eebg ]);};
 
 // NewHorizontalRange constructs a new full rows range.
-func NewHorizontalRange (v string )Expression {_efdaf :=_ee .Split (v ,"\u003a");if len (_efdaf )!=2{return nil ;};_eggf ,_ :=_ff .Atoi (_efdaf [0]);_ggab ,_ :=_ff .Atoi (_efdaf [1]);return HorizontalRange {_eggf ,_ggab };};
+func NewHorizontalRange (v string )Expression {_efdaf :=_ee .Split (v ,"\u003a");if len (_efdaf )!=2{return nil ;};_eggf ,_bgfeb :=rangeRow (_efdaf [0]);_ggab ,_ddefa :=rangeRow (_efdaf [1]);return HorizontalRange {_eggf ,_ggab ,_bgfeb ,_ddefa };};
 
 // LCM implements the Excel LCM() function which returns the least common
 // multiple of a range of numbers.
 func LCM (args []Result )Result {if len (args )==0{return MakeErrorResult ("\u004c\u0043M(\u0029\u0020\u0072e\u0071\u0075\u0069\u0072es \u0061t \u006c\u0065\u0061\u0073\u0074\u0020\u006fne\u0020\u0061\u0072\u0067\u0075\u006d\u0065n\u0074");};_gaag :=[]float64 {};for _ ,_fgbf :=range args {switch _fgbf .Type {case ResultTypeString :_effa :=_fgbf .AsNumber ();if _effa .Type !=ResultTypeNumber {return MakeErrorResult ("\u004c\u0043M(\u0029\u0020\u006fn\u006c\u0079\u0020\u0061cce\u0070ts\u0020\u006e\u0075\u006d\u0065\u0072\u0069c \u0061\u0072\u0067\u0075\u006d\u0065\u006et\u0073");};_gaag =append (_gaag ,_effa .ValueNumber );case ResultTypeList :_acdc :=LCM (_fgbf .ValueList );if _acdc .Type !=ResultTypeNumber {return _acdc ;};_gaag =append (_gaag ,_acdc .ValueNumber );case ResultTypeNumber :_gaag =append (_gaag ,_fgbf .ValueNumber );case ResultTypeError :return _fgbf ;};};if _gaag [0]< 0{return MakeErrorResult ("\u004c\u0043M\u0028\u0029\u0020\u006fn\u006c\u0079 \u0061\u0063\u0063\u0065\u0070\u0074\u0073\u0020p\u006f\u0073\u0069\u0074\u0069\u0076\u0065\u0020\u0061\u0072\u0067\u0075m\u0065\u006e\u0074\u0073");};if len (_gaag )==1{return MakeNumberResult (_gaag [0]);};_daca :=_gaag [0];for _bdaa :=1;_bdaa < len (_gaag );_bdaa ++{if _gaag [_bdaa ]< 0{return MakeErrorResult ("\u004c\u0043M\u0028\u0029\u0020\u006fn\u006c\u0079 \u0061\u0063\u0063\u0065\u0070\u0074\u0073\u0020p\u006f\u0073\u0069\u0074\u0069\u0076\u0065\u0020\u0061\u0072\u0067\u0075m\u0065\u006e\u0074\u0073");};_daca =_cedd (_daca ,_gaag [_bdaa ]);};return MakeNumberResult (_daca );};func _gfgb (_bbed ,_dfbc _ce .Time ,_dff int )float64 {if _bbed .After (_dfbc ){_bbed ,_dfbc =_dfbc ,_bbed ;};_fbf :=0;_ffaa ,_gfbf ,_ecdc :=_bbed .Date ();_fgef ,_fdgd ,_dcee :=_dfbc .Date ();_add ,_ecbb :=int (_gfbf ),int (_fdgd );_gac ,_bgec :=_fgbb (_ffaa ,_add ,_ecdc ,_dff ),_fgbb (_fgef ,_ecbb ,_dcee ,_dff );if !_ecbad (_dff ){return _acb (_fgef ,_ecbb ,_bgec )-_acb (_ffaa ,_add ,_gac );};if _dff ==0{if (_add ==2||_gac < 30)&&_dcee ==31{_bgec =31;}else if _ecbb ==2&&_bgec ==_gfgc (_fgef ,_ecbb ){_bgec =_gfgc (_fgef ,2);};}else {if _add ==2&&_gac ==30{_gac =_gfgc (_ffaa ,2);};if _ecbb ==2&&_bgec ==30{_bgec =_gfgc (_fgef ,2);};};if _ffaa < _fgef ||(_ffaa ==_fgef &&_add < _ecbb ){_fbf =30-_gac +1;_ecdc =1;_gac =1;_ace :=_ce .Date (_ffaa ,_ce .Month (_add ),_ecdc ,0,0,0,0,_ce .UTC ).AddDate (0,1,0);if _ace .Year ()< _fgef {_fbf +=_bgac (_ace .Year (),int (_ace .Month ()),12,_dff );_ace =_ace .AddDate (0,13-int (_ace .Month ()),0);_fbf +=_agdcc (_ace .Year (),_fgef -1,_dff );};_fbf +=_bgac (_fgef ,int (_ace .Month ()),_ecbb -1,_dff );_ace =_ace .AddDate (0,_ecbb -int (_ace .Month ()),0);_add =_ace .Day ();};_fbf +=_bgec -_gac ;if _fbf > 0{return float64 (_fbf );}else {return 0;};};
 
 // String returns a string representation of FunctionCall expression.
-func (_cbdbdd FunctionCall )String ()string {_fefgf :=_d .Buffer {};_fefgf .WriteString (_cbdbdd ._fbae );_fefgf .WriteString ("\u0028");_bfcfe :=len (_cbdbdd ._agff )-1;for _gafc ,_ggcc :=range _cbdbdd ._agff {_fefgf .WriteString (_ggcc .String ());if _gafc !=_bfcfe {_fefgf .WriteString ("\u002c");};};_fefgf .WriteString ("\u0029");return _fefgf .String ();};func (_fabad *plex )Error (s string ){_ge .Log ("\u0070a\u0072s\u0065\u0020\u0065\u0072\u0072\u006f\u0072\u003a\u0020\u0025\u0073",s );};const _gca ="\u0028(\u005b0\u002d\u0039\u005d\u0029\u002b)\u0020\u0028a\u006d\u007c\u0070\u006d\u0029";func LexReader (r _e .Reader )chan*node {_gggd :=NewLexer ();go _gggd .lex (r );return _gggd ._daeg };const _bcab =57350;func _gbea (_gcfa []Result )Result {_gbdbg :=_gcfa [0].ValueArray ;if len (_gcfa )==1{_ccbb :=[][]Result {};for _ ,_agfg :=range _gbdbg {_ccbb =append (_ccbb ,_gdad ([]Result {MakeListResult (_agfg )}).ValueList );};return MakeArrayResult (_ccbb );}else if len (_gcfa )==2{_cffgg :=len (_gbdbg );_baed :=len (_gbdbg [0]);_fbec :=_aebcb (_gcfa [1],_cffgg ,_baed );_gfdf :=len (_fbec );_gaed :=[][]Result {};var _bdad []Result ;for _efbc ,_gbcf :=range _gbdbg {if _efbc < _gfdf {_bdad =_fbec [_efbc ];}else {_bdad =_gadf (MakeErrorResultType (ErrorTypeNA ,""),_baed );};_gaed =append (_gaed ,_gdad ([]Result {MakeListResult (_gbcf ),MakeListResult (_bdad )}).ValueList );};return MakeArrayResult (_gaed );}else if len (_gcfa )==3{_ddef :=len (_gbdbg );_fgda :=len (_gbdbg [0]);_gfbc :=_aebcb (_gcfa [1],_ddef ,_fgda );_agece :=_aebcb (_gcfa [2],_ddef ,_fgda );_bddea :=len (_gfbc );_ddfb :=len (_agece );_egcgc :=[][]Result {};var _geefg ,_edbf []Result ;for _babc ,_gggb :=range _gbdbg {if _babc < _bddea {_geefg =_gfbc [_babc ];}else {_geefg =_gadf (MakeErrorResultType (ErrorTypeNA ,""),_fgda );};if _babc < _ddfb {_edbf =_agece [_babc ];}else {_edbf =_gadf (MakeErrorResultType (ErrorTypeNA ,""),_fgda );};_egcgc =append (_egcgc ,_gdad ([]Result {MakeListResult (_gggb ),MakeListResult (_geefg ),MakeListResult (_edbf )}).ValueList );};return MakeArrayResult (_egcgc );};return MakeErrorResultType (ErrorTypeValue ,"");};
+func (_cbdbdd FunctionCall )String ()string {_fefgf :=_d .Buffer {};_bfcfe :=len (_cbdbdd ._agff )-1;_ebcfd :=_cbdbdd ._agff ;if _cbdbdd ._fbae ==lambdaCall &&len (_ebcfd )> 0{_fefgf .WriteString (_ebcfd [0].String ());_ebcfd =_ebcfd [1:];_bfcfe --;}else {_fefgf .WriteString (_cbdbdd ._fbae );};_fefgf .WriteString ("\u0028");for _gafc ,_ggcc :=range _ebcfd {_fefgf .WriteString (_ggcc .String ());if _gafc !=_bfcfe {_fefgf .WriteString ("\u002c");};};_fefgf .WriteString ("\u0029");return _fefgf .String ();};func (_fabad *plex )Error (s string ){_ge .Log ("\u0070a\u0072s\u0065\u0020\u0065\u0072\u0072\u006f\u0072\u003a\u0020\u0025\u0073",s );};const _gca ="\u0028(\u005b0\u002d\u0039\u005d\u0029\u002b)\u0020\u0028a\u006d\u007c\u0070\u006d\u0029";func LexReader (r _e .Reader )chan*node {_gggd :=NewLexer ();go _gggd .lex (r );return _gggd ._daeg };const _bcab =57350;func _gbea (_gcfa []Result )Result {_gbdbg :=_gcfa [0].ValueArray ;if len (_gcfa )==1{_ccbb :=[][]Result {};for _ ,_agfg :=range _gbdbg {_ccbb =append (_ccbb ,_gdad ([]Result {MakeListResult (_agfg )}).ValueList );};return MakeArrayResult (_ccbb );}else if len (_gcfa )==2{_cffgg :=len (_gbdbg );_baed :=len (_gbdbg [0]);_fbec :=_aebcb (_gcfa [1],_cffgg ,_baed );_gfdf :=len (_fbec );_gaed :=[][]Result {};var _bdad []Result ;for _efbc ,_gbcf :=range _gbdbg {if _efbc < _gfdf {_bdad =_fbec [_efbc ];}else {_bdad =_gadf (MakeErrorResultType (ErrorTypeNA ,""),_baed );};_gaed =append (_gaed ,_gdad ([]Result {MakeListResult (_gbcf ),MakeListResult (_bdad )}).ValueList );};return MakeArrayResult (_gaed );}else if len (_gcfa )==3{_ddef :=len (_gbdbg );_fgda :=len (_gbdbg [0]);_gfbc :=_aebcb (_gcfa [1],_ddef ,_fgda );_agece :=_aebcb (_gcfa [2],_ddef ,_fgda );_bddea :=len (_gfbc );_ddfb :=len (_agece );_egcgc :=[][]Result {};var _geefg ,_edbf []Result ;for _babc ,_gggb :=range _gbdbg {if _babc < _bddea {_geefg =_gfbc [_babc ];}else {_geefg =_gadf (MakeErrorResultType (ErrorTypeNA ,""),_fgda );};if _babc < _ddfb {_edbf =_agece [_babc ];}else {_edbf =_gadf (MakeErrorResultType (ErrorTypeNA ,""),_fgda );};_egcgc =append (_egcgc ,_gdad ([]Result {MakeListResult (_gggb ),MakeListResult (_geefg ),MakeListResult (_edbf )}).ValueList );};return MakeArrayResult (_egcgc );};return MakeErrorResultType (ErrorTypeValue ,"");};
 
 // Reference returns an invalid reference for Number.
 func (_gabb Number )Reference (ctx Context ,ev Evaluator )Reference {return ReferenceInvalid };func _bdbbb (_fcgae Context ,_gfdeg ,_dedbb int )(string ,string ){_ebefa :="\u0041"+_ff .Itoa (_gfdeg );_aacf :=_fcgae .LastColumn (_gfdeg ,_dedbb );_bffc :=_aacf +_ff .Itoa (_dedbb );return _ebefa ,_bffc ;};
@@ -636,7 +637,7 @@ func IsNA (args []Result )Result {if len (args )!=1{return MakeErrorResult ("\u0
 func (_ggbf *ivr )GetFormat (cellRef string )string {return ""};var _fcfe =[...]uint8 {0,16,29,43,56,68,80,91,102,113,125,137,148,163};const _afcdb =57352;const _bfaa =57366;func _aga (_agag ,_dgfd ,_dccg int )bool {if _dgfd < 1||_dgfd > 12{return false ;};if _dccg < 1{return false ;};return _dccg <=_gfgc (_agag ,_dgfd );};
 
 // Reference returns a string reference value to a named range.
-func (_dgaa NamedRangeRef )Reference (ctx Context ,ev Evaluator )Reference {return Reference {Type :ReferenceTypeNamedRange ,Value :_dgaa ._bdggc };};
+func (_dgaa NamedRangeRef )Reference (ctx Context ,ev Evaluator )Reference {if _ ,_fcbh :=boundValue (ctx ,_dgaa ._bdggc );_fcbh {return Reference {Type :ReferenceTypeInvalid };};return Reference {Type :ReferenceTypeNamedRange ,Value :_dgaa ._bdggc };};
 
 // NewString constructs a new string expression.
 func NewString (v string )Expression {v =_ee .Replace (v ,"\u0022\u0022","\u0022",-1);return String {v };};
@@ -719,13 +720,13 @@ func CountIfs (args []Result )Result {_efecef :=_gdgfe (args ,false ,"\u0043\u00
 func SupportedFunctions ()[]string {_gfgg :=[]string {};for _abfgd :=range _baad {_gfgg =append (_gfgg ,_abfgd );};for _dccdaa :=range _bedfa {_gfgg =append (_gfgg ,_dccdaa );};_a .Strings (_gfgg );return _gfgg ;};
 
 // Update updates references in the PrefixExpr after removing a row/column.
-func (_geafd PrefixExpr )Update (q *_cc .UpdateQuery )Expression {_cgac :=_geafd ;_gcfcc :=_geafd ._agaa .String ();if _gcfcc ==q .SheetToUpdate {_egeeb :=*q ;_egeeb .UpdateCurrentSheet =true ;_cgac ._ddfc =_geafd ._ddfc .Update (&_egeeb );};return _cgac ;};var _fgeed =[...]int {0,0,71,70,69,4,67,66,53,51,50,49,48,47,46,45,44,2};type ri struct{_bdgf float64 ;_eagead string ;};func _cbeg (_gebga Result )Result {if _gebga .Type ==ResultTypeEmpty {return _gebga ;};_gcada :=_gebga .AsString ();if _gcada .Type !=ResultTypeString {return MakeErrorResult ("\u004c\u004f\u0057\u0045\u0052\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065s\u0020\u0061\u0020\u0073\u0069\u006eg\u006c\u0065\u0020\u0073\u0074\u0072\u0069\u006e\u0067\u0020\u0061\u0072\u0067u\u006d\u0065\u006e\u0074");};if _gebga .IsBoolean {if _gcada .ValueString =="\u0031"{return MakeStringResult ("\u0074\u0072\u0075\u0065");}else if _gcada .ValueString =="\u0030"{return MakeStringResult ("\u0066\u0061\u006cs\u0065");}else {return MakeErrorResult ("\u0049\u006e\u0063\u006fr\u0072\u0065\u0063\u0074\u0020\u0061\u0072\u0067\u0075\u006de\u006et\u0020\u0066\u006f\u0072\u0020\u004c\u004fW\u0045\u0052");};}else {return MakeStringResult (_ee .ToLower (_gcada .ValueString ));};};const _dfbae =57344;
+func (_geafd PrefixExpr )Update (q *_cc .UpdateQuery )Expression {_cgac :=_geafd ;if _egeeb ,_gcfcc :=updateSheetExpr (_geafd ._agaa ,q );_gcfcc {_cgac ._ddfc =_geafd ._ddfc .Update (_egeeb );};return _cgac ;};var _fgeed =[...]int {0,0,71,70,69,4,67,66,53,51,50,49,48,47,46,45,44,2};type ri struct{_bdgf float64 ;_eagead string ;};func _cbeg (_gebga Result )Result {if _gebga .Type ==ResultTypeEmpty {return _gebga ;};_gcada :=_gebga .AsString ();if _gcada .Type !=ResultTypeString {return MakeErrorResult ("\u004c\u004f\u0057\u0045\u0052\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065s\u0020\u0061\u0020\u0073\u0069\u006eg\u006c\u0065\u0020\u0073\u0074\u0072\u0069\u006e\u0067\u0020\u0061\u0072\u0067u\u006d\u0065\u006e\u0074");};if _gebga .IsBoolean {if _gcada .ValueString =="\u0031"{return MakeStringResult ("\u0074\u0072\u0075\u0065");}else if _gcada .ValueString =="\u0030"{return MakeStringResult ("\u0066\u0061\u006cs\u0065");}else {return MakeErrorResult ("\u0049\u006e\u0063\u006fr\u0072\u0065\u0063\u0074\u0020\u0061\u0072\u0067\u0075\u006de\u006et\u0020\u0066\u006f\u0072\u0020\u004c\u004fW\u0045\u0052");};}else {return MakeStringResult (_ee .ToLower (_gcada .ValueString ));};};const _dfbae =57344;
 
 // Eval evaluates and returns an expression with prefix.
 func (_caegd PrefixExpr )Eval (ctx Context ,ev Evaluator )Result {_aeba :=_caegd ._agaa .Reference (ctx ,ev );switch _aeba .Type {case ReferenceTypeSheet :_dagfd :=ctx .Sheet (_aeba .Value );return _caegd ._ddfc .Eval (_dagfd ,ev );default:return MakeErrorResult (_c .Sprintf ("\u006e\u006f\u0020\u0073\u0075\u0070\u0070\u006f\u0072\u0074\u0020\u0066\u006f\u0072\u0020r\u0065f\u0065\u0072\u0065\u006e\u0063\u0065\u0020\u0074\u0079\u0070\u0065\u0020\u0025\u0073",_aeba .Type ));};};type countMode byte ;
 
 // Ppmt implements the Excel PPPMT function.
-func Ppmt (args []Result )Result {_cgee :=len (args );if _cgee < 4||_cgee > 6{return MakeErrorResult ("\u0050\u0050\u004d\u0054\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u006e\u0075\u006d\u0062\u0065\u0072 \u006f\u0066\u0020\u0061\u0072\u0067\u0075\u006d\u0065n\u0074\u0073\u0020\u0069\u006e\u0020\u0072\u0061\u006e\u0067\u0065\u0020\u006ff\u0020\u0066\u006f\u0075\u0072\u0020a\u006e\u0064\u0020s\u0069\u0078");};if args [0].Type !=ResultTypeNumber {return MakeErrorResult ("P\u0050\u004d\u0054\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0072\u0061\u0074\u0065\u0020t\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006d\u0062\u0065r \u0061\u0072\u0067u\u006de\u006e\u0074");};_edbe :=args [0].ValueNumber ;if args [1].Type !=ResultTypeNumber {return MakeErrorResult ("\u0050\u0050\u004dT\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0070\u0065\u0072\u0069\u006f\u0064\u0020\u0074\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006d\u0062\u0065\u0072 \u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");};_agdf :=args [1].ValueNumber ;if _agdf <=0{return MakeErrorResultType (ErrorTypeNum ,"P\u0050\u004d\u0054\u0020\u0072\u0065q\u0075\u0069\u0072\u0065\u0073\u0020p\u0065\u0072\u0069\u006f\u0064\u0020\u0074o\u0020\u0062\u0065\u0020\u0070\u006f\u0073\u0069\u0074\u0069v\u0065");};if args [2].Type !=ResultTypeNumber {return MakeErrorResult ("\u0050\u0050\u004d\u0054\u0020\u0072\u0065\u0071\u0075\u0069\u0072e\u0073\u0020\u006e\u0075\u006d\u0062\u0065\u0072 \u006ff\u0020\u0070\u0065\u0072\u0069\u006f\u0064\u0073\u0020\u0074\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006db\u0065\u0072\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");};_gdaac :=args [2].ValueNumber ;if _gdaac < _agdf {return MakeErrorResultType (ErrorTypeNum ,"\u0050\u0050\u004d\u0054\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u006e\u0075\u006d\u0062\u0065\u0072 \u006f\u0066\u0020\u0070\u0065\u0072\u0069\u006f\u0064s\u0020\u0074\u006f\u0020\u0062\u0065\u0020\u006e\u006f\u0074\u0020\u006c\u0065s\u0073\u0020\u0074\u0068\u0061\u006e \u0070\u0065\u0072i\u006f\u0064");};if args [3].Type !=ResultTypeNumber {return MakeErrorResult ("\u0050\u0050\u004d\u0054\u0020\u0072e\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0070\u0072\u0065\u0073\u0065\u006e\u0074\u0020\u0076\u0061\u006c\u0075\u0065 \u0074\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006d\u0062\u0065\u0072\u0020\u0061r\u0067u\u006d\u0065\u006e\u0074");};_geff :=args [3].ValueNumber ;_bagf :=0.0;if _cgee >=5&&args [4].Type !=ResultTypeEmpty {if args [4].Type !=ResultTypeNumber {return MakeErrorResult ("\u0050\u0050\u004d\u0054\u0020\u0072\u0065\u0071u\u0069\u0072\u0065s \u0066\u0075\u0074\u0075\u0072\u0065 \u0076\u0061\u006c\u0075\u0065\u0020\u0074\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006db\u0065\u0072\u0020\u0061\u0072\u0067\u0075\u006de\u006e\u0074");};_bagf =args [4].ValueNumber ;};_adbd :=0;if _cgee ==6&&args [5].Type !=ResultTypeEmpty {if args [5].Type !=ResultTypeNumber {return MakeErrorResult ("P\u0050\u004d\u0054\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0074\u0079\u0070\u0065\u0020t\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006d\u0062\u0065r \u0061\u0072\u0067u\u006de\u006e\u0074");};_adbd =int (args [5].ValueNumber );if _adbd !=0{_adbd =1;};};return MakeNumberResult (_faf (_edbe ,_gdaac ,_geff ,_bagf ,_adbd )-_cgfc (_edbe ,_agdf ,_gdaac ,_geff ,_bagf ,_adbd ));};func init (){RegisterFunction ("\u0043\u0048\u0041\u0052",Char );RegisterFunction ("\u0043\u004c\u0045A\u004e",Clean );RegisterFunction ("\u0043\u004f\u0044\u0045",Code );RegisterFunction ("C\u004f\u004e\u0043\u0041\u0054\u0045\u004e\u0041\u0054\u0045",Concat );RegisterFunction ("\u0043\u004f\u004e\u0043\u0041\u0054",Concat );RegisterFunction ("\u005f\u0078\u006cf\u006e\u002e\u0043\u004f\u004e\u0043\u0041\u0054",Concat );RegisterFunction ("\u0045\u0058\u0041C\u0054",Exact );RegisterFunction ("\u0046\u0049\u004e\u0044",Find );RegisterFunctionComplex ("\u0046\u0049\u004eD\u0042",Findb );RegisterFunction ("\u004c\u0045\u0046\u0054",Left );RegisterFunction ("\u004c\u0045\u0046T\u0042",Left );RegisterFunction ("\u004c\u0045\u004e",Len );RegisterFunction ("\u004c\u0045\u004e\u0042",Len );RegisterFunction ("\u004c\u004f\u0057E\u0052",Lower );RegisterFunction ("\u004d\u0049\u0044",Mid );RegisterFunction ("\u0050\u0052\u004f\u0050\u0045\u0052",Proper );RegisterFunction ("\u0052E\u0050\u004c\u0041\u0043\u0045",Replace );RegisterFunction ("\u0052\u0045\u0050\u0054",Rept );RegisterFunction ("\u0052\u0049\u0047H\u0054",Right );RegisterFunction ("\u0052\u0049\u0047\u0048\u0054\u0042",Right );RegisterFunction ("\u0053\u0045\u0041\u0052\u0043\u0048",Search );RegisterFunctionComplex ("\u0053E\u0041\u0052\u0043\u0048\u0042",Searchb );RegisterFunction ("\u0053\u0055\u0042\u0053\u0054\u0049\u0054\u0055\u0054\u0045",Substitute );RegisterFunction ("\u0054",T );RegisterFunction ("\u0054\u0045\u0058\u0054",Text );RegisterFunction ("\u0054\u0045\u0058\u0054\u004a\u004f\u0049\u004e",TextJoin );RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0054\u0045\u0058T\u004a\u004f\u0049\u004e",TextJoin );RegisterFunction ("\u0054\u0052\u0049\u004d",Trim );RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0055\u004e\u0049\u0043\u0048\u0041\u0052",Char );RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0055\u004e\u0049\u0043\u004f\u0044\u0045",Unicode );RegisterFunction ("\u0055\u0050\u0050E\u0052",Upper );RegisterFunction ("\u0056\u0041\u004cU\u0045",Value );};func _gbcc (_feee ,_fgcb float64 )bool {return _dc .Abs (_feee -_fgcb )< 1.0e-6};func _fegbd (){_fcaa =_gf .MustCompile ("\u005e\u0028\u005b\u0030\u002d\u0039\u005d\u002b\u0029\u0024");_ceae =_gf .MustCompile ("\u005e=\u0028\u002e\u002a\u0029\u0024");_bacdb =_gf .MustCompile ("\u005e<\u0028\u002e\u002a\u0029\u0024");_eacg =_gf .MustCompile ("\u005e>\u0028\u002e\u002a\u0029\u0024");_ecaee =_gf .MustCompile ("\u005e\u003c\u003d\u0028\u002e\u002a\u0029\u0024");_gfee =_gf .MustCompile ("\u005e\u003e\u003d\u0028\u002e\u002a\u0029\u0024");};
+func Ppmt (args []Result )Result {_cgee :=len (args );if _cgee < 4||_cgee > 6{return MakeErrorResult ("\u0050\u0050\u004d\u0054\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u006e\u0075\u006d\u0062\u0065\u0072 \u006f\u0066\u0020\u0061\u0072\u0067\u0075\u006d\u0065n\u0074\u0073\u0020\u0069\u006e\u0020\u0072\u0061\u006e\u0067\u0065\u0020\u006ff\u0020\u0066\u006f\u0075\u0072\u0020a\u006e\u0064\u0020s\u0069\u0078");};if args [0].Type !=ResultTypeNumber {return MakeErrorResult ("P\u0050\u004d\u0054\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0072\u0061\u0074\u0065\u0020t\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006d\u0062\u0065r \u0061\u0072\u0067u\u006de\u006e\u0074");};_edbe :=args [0].ValueNumber ;if args [1].Type !=ResultTypeNumber {return MakeErrorResult ("\u0050\u0050\u004dT\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0070\u0065\u0072\u0069\u006f\u0064\u0020\u0074\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006d\u0062\u0065\u0072 \u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");};_agdf :=args [1].ValueNumber ;if _agdf <=0{return MakeErrorResultType (ErrorTypeNum ,"P\u0050\u004d\u0054\u0020\u0072\u0065q\u0075\u0069\u0072\u0065\u0073\u0020p\u0065\u0072\u0069\u006f\u0064\u0020\u0074o\u0020\u0062\u0065\u0020\u0070\u006f\u0073\u0069\u0074\u0069v\u0065");};if args [2].Type !=ResultTypeNumber {return MakeErrorResult ("\u0050\u0050\u004d\u0054\u0020\u0072\u0065\u0071\u0075\u0069\u0072e\u0073\u0020\u006e\u0075\u006d\u0062\u0065\u0072 \u006ff\u0020\u0070\u0065\u0072\u0069\u006f\u0064\u0073\u0020\u0074\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006db\u0065\u0072\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");};_gdaac :=args [2].ValueNumber ;if _gdaac < _agdf {return MakeErrorResultType (ErrorTypeNum ,"\u0050\u0050\u004d\u0054\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u006e\u0075\u006d\u0062\u0065\u0072 \u006f\u0066\u0020\u0070\u0065\u0072\u0069\u006f\u0064s\u0020\u0074\u006f\u0020\u0062\u0065\u0020\u006e\u006f\u0074\u0020\u006c\u0065s\u0073\u0020\u0074\u0068\u0061\u006e \u0070\u0065\u0072i\u006f\u0064");};if args [3].Type !=ResultTypeNumber {return MakeErrorResult ("\u0050\u0050\u004d\u0054\u0020\u0072e\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0070\u0072\u0065\u0073\u0065\u006e\u0074\u0020\u0076\u0061\u006c\u0075\u0065 \u0074\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006d\u0062\u0065\u0072\u0020\u0061r\u0067u\u006d\u0065\u006e\u0074");};_geff :=args [3].ValueNumber ;_bagf :=0.0;if _cgee >=5&&args [4].Type !=ResultTypeEmpty {if args [4].Type !=ResultTypeNumber {return MakeErrorResult ("\u0050\u0050\u004d\u0054\u0020\u0072\u0065\u0071u\u0069\u0072\u0065s \u0066\u0075\u0074\u0075\u0072\u0065 \u0076\u0061\u006c\u0075\u0065\u0020\u0074\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006db\u0065\u0072\u0020\u0061\u0072\u0067\u0075\u006de\u006e\u0074");};_bagf =args [4].ValueNumber ;};_adbd :=0;if _cgee ==6&&args [5].Type !=ResultTypeEmpty {if args [5].Type !=ResultTypeNumber {return MakeErrorResult ("P\u0050\u004d\u0054\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0074\u0079\u0070\u0065\u0020t\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006d\u0062\u0065r \u0061\u0072\u0067u\u006de\u006e\u0074");};_adbd =int (args [5].ValueNumber );if _adbd !=0{_adbd =1;};};return MakeNumberResult (_faf (_edbe ,_gdaac ,_geff ,_bagf ,_adbd )-_cgfc (_edbe ,_agdf ,_gdaac ,_geff ,_bagf ,_adbd ));};func init (){RegisterFunction ("\u0043\u0048\u0041\u0052",Char );RegisterFunction ("\u0043\u004c\u0045A\u004e",Clean );RegisterFunction ("\u0043\u004f\u0044\u0045",Code );RegisterFunction ("C\u004f\u004e\u0043\u0041\u0054\u0045\u004e\u0041\u0054\u0045",Concat );RegisterFunction ("\u0043\u004f\u004e\u0043\u0041\u0054",Concat );RegisterFunction ("\u005f\u0078\u006cf\u006e\u002e\u0043\u004f\u004e\u0043\u0041\u0054",Concat );RegisterFunction ("\u0045\u0058\u0041C\u0054",Exact );RegisterFunction ("\u0046\u0049\u004e\u0044",Find );RegisterFunctionComplex ("\u0046\u0049\u004eD\u0042",Findb );RegisterFunction ("\u004c\u0045\u0046\u0054",Left );RegisterFunction ("\u004c\u0045\u0046T\u0042",Left );RegisterFunction ("\u004c\u0045\u004e",Len );RegisterFunction ("\u004c\u0045\u004e\u0042",Len );RegisterFunction ("\u004c\u004f\u0057E\u0052",Lower );RegisterFunction ("\u004d\u0049\u0044",Mid );RegisterFunction ("\u0050\u0052\u004f\u0050\u0045\u0052",Proper );RegisterFunction ("\u0052E\u0050\u004c\u0041\u0043\u0045",Replace );RegisterFunction ("\u0052\u0045\u0050\u0054",Rept );RegisterFunction ("\u0052\u0049\u0047H\u0054",Right );RegisterFunction ("\u0052\u0049\u0047\u0048\u0054\u0042",Right );RegisterFunction ("\u0053\u0045\u0041\u0052\u0043\u0048",Search );RegisterFunctionComplex ("\u0053E\u0041\u0052\u0043\u0048\u0042",Searchb );RegisterFunction ("\u0053\u0055\u0042\u0053\u0054\u0049\u0054\u0055\u0054\u0045",Substitute );RegisterFunction ("\u0054",T );RegisterFunction ("\u0054\u0045\u0058\u0054\u004a\u004f\u0049\u004e",TextJoin );RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0054\u0045\u0058T\u004a\u004f\u0049\u004e",TextJoin );RegisterFunction ("\u0054\u0052\u0049\u004d",Trim );RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0055\u004e\u0049\u0043\u0048\u0041\u0052",Char );RegisterFunction ("\u005f\u0078\u006c\u0066\u006e\u002e\u0055\u004e\u0049\u0043\u004f\u0044\u0045",Unicode );RegisterFunction ("\u0055\u0050\u0050E\u0052",Upper );};func _gbcc (_feee ,_fgcb float64 )bool {return _dc .Abs (_feee -_fgcb )< 1.0e-6};func _fegbd (){_fcaa =_gf .MustCompile ("\u005e\u0028\u005b\u0030\u002d\u0039\u005d\u002b\u0029\u0024");_ceae =_gf .MustCompile ("\u005e=\u0028\u002e\u002a\u0029\u0024");_bacdb =_gf .MustCompile ("\u005e<\u0028\u002e\u002a\u0029\u0024");_eacg =_gf .MustCompile ("\u005e>\u0028\u002e\u002a\u0029\u0024");_ecaee =_gf .MustCompile ("\u005e\u003c\u003d\u0028\u002e\u002a\u0029\u0024");_gfee =_gf .MustCompile ("\u005e\u003e\u003d\u0028\u002e\u002a\u0029\u0024");};
 
 // NewNegate constructs a new negate expression.
 func NewNegate (e Expression )Expression {return Negate {e }};
@@ -766,7 +767,7 @@ func (_dagb PrefixRangeExpr )Eval (ctx Context ,ev Evaluator )Result {_febd :=_d
 func Munit (args []Result )Result {if len (args )!=1{return MakeErrorResult ("\u004d\u0055\u004eIT\u0028\u0029\u0020\u0072\u0065\u0071\u0075\u0069\u0072e\u0073 \u006fn\u0065 \u006e\u0075\u006d\u0065\u0072\u0069\u0063\u0020\u0069\u006e\u0070\u0075\u0074");};_beabc :=args [0].AsNumber ();if _beabc .Type !=ResultTypeNumber {return MakeErrorResult ("\u004d\u0055\u004eIT\u0028\u0029\u0020\u0072\u0065\u0071\u0075\u0069\u0072e\u0073 \u006fn\u0065 \u006e\u0075\u006d\u0065\u0072\u0069\u0063\u0020\u0069\u006e\u0070\u0075\u0074");};_fefag :=int (_beabc .ValueNumber );_fbbgd :=make ([][]Result ,0,_fefag );for _cedaf :=0;_cedaf < _fefag ;_cedaf ++{_bgefd :=make ([]Result ,_fefag );for _aaab :=0;_aaab < _fefag ;_aaab ++{if _cedaf ==_aaab {_bgefd [_aaab ]=MakeNumberResult (1.0);}else {_bgefd [_aaab ]=MakeNumberResult (0.0);};};_fbbgd =append (_fbbgd ,_bgefd );};return MakeArrayResult (_fbbgd );};
 
 // Coupncd implements the Excel COUPNCD function.
-func Coupncd (args []Result )Result {_eebcg ,_gfd :=_dccda (args ,"\u0043O\u0055\u0050\u004e\u0043\u0044");if _gfd .Type ==ResultTypeError {return _gfd ;};_fce :=_fae (_eebcg ._ggbd );_aec :=_fae (_eebcg ._eedd );_gfde :=_eebcg ._ecf ;_abg :=_aacd (_fce ,_aec ,_gfde );_deef ,_gffa ,_cae :=_abg .Date ();return MakeNumberResult (_acb (_deef ,int (_gffa ),_cae ));};const _gefbd =57353;var (_fabd =0;_adgdg =false ;);const _ded ="\u0028\u0028\u005b\u0030\u002d\u0039\u005d\u0029\u002b\u0029\u002f\u0028\u0028\u005b\u0030-\u0039]\u0029\u002b\u0029\u002f\u0028\u0028\u005b\u0030\u002d\u0039\u005d\u0029\u002b\u0029";var _eccf =[...]int {123,-1000,-1000,74,163,103,163,163,-1000,-1000,-1000,-1000,163,-1000,-1000,-1000,-1000,-1000,-12,106,-1000,-1000,143,-1000,-1000,-1000,-1000,-1000,163,163,163,163,163,163,163,163,163,163,163,163,74,163,163,6,-28,74,-15,-15,60,10,-14,-1000,-1000,-1000,7,-1000,74,-15,-15,-23,-23,-1000,-8,-8,-8,-8,-8,-8,-4,33,-1000,163,163,-1000,-1000,10,-1000,163,-1000,-28,74,-1000,-1000,74};
+func Coupncd (args []Result )Result {_eebcg ,_gfd :=_dccda (args ,"\u0043O\u0055\u0050\u004e\u0043\u0044");if _gfd .Type ==ResultTypeError {return _gfd ;};_fce :=_fae (_eebcg ._ggbd );_aec :=_fae (_eebcg ._eedd );_gfde :=_eebcg ._ecf ;_abg :=_aacd (_fce ,_aec ,_gfde );_deef ,_gffa ,_cae :=_abg .Date ();return MakeNumberResult (_acb (_deef ,int (_gffa ),_cae ));};const _gefbd =57353;var (_fabd =0;_adgdg =true ;);const _ded ="\u0028\u0028\u005b\u0030\u002d\u0039\u005d\u0029\u002b\u0029\u002f\u0028\u0028\u005b\u0030-\u0039]\u0029\u002b\u0029\u002f\u0028\u0028\u005b\u0030\u002d\u0039\u005d\u0029\u002b\u0029";var _eccf =[...]int {123,-1000,-1000,74,163,103,163,163,-1000,-1000,-1000,-1000,163,-1000,-1000,-1000,-1000,-1000,-12,106,-1000,-1000,143,-1000,-1000,-1000,-1000,-1000,163,163,163,163,163,163,163,163,163,163,163,163,74,163,163,6,-28,74,-15,-15,60,10,-14,-1000,-1000,-1000,7,-1000,74,-15,-15,-23,-23,-1000,-8,-8,-8,-8,-8,-8,-4,33,-1000,163,163,-1000,-1000,10,-1000,163,-1000,-28,74,-1000,-1000,74};
 
 // String returns a string representation of a named range.
 func (_ebfgc NamedRangeRef )String ()string {return _ebfgc ._bdggc };
@@ -781,7 +782,7 @@ func Ddb (args []Result )Result {_aaga :=len (args );if _aaga !=4&&_aaga !=5{ret
 func (_fabfc NamedRangeRef )Update (q *_cc .UpdateQuery )Expression {return _fabfc };func (_cfbg *yyParserImpl )Lookahead ()int {return _cfbg ._gafef };const (BinOpTypeUnknown BinOpType =iota ;BinOpTypePlus ;BinOpTypeMinus ;BinOpTypeMult ;BinOpTypeDiv ;BinOpTypeExp ;BinOpTypeLT ;BinOpTypeGT ;BinOpTypeEQ ;BinOpTypeLEQ ;BinOpTypeGEQ ;BinOpTypeNE ;BinOpTypeConcat ;);
 
 // String returns a string representation of String.
-func (_gegbd String )String ()string {return "\u0022"+_gegbd ._abace +"\u0022"};
+func (_gegbd String )String ()string {return stringLiteral (_gegbd ._abace )};
 
 // Indirect is an implementation of the Excel INDIRECT function that returns the
 // contents of a cell.
@@ -798,7 +799,7 @@ func Rept (args []Result )Result {if len (args )!=2{return MakeErrorResult ("R\u
 func MakeBoolResult (b bool )Result {if b {return Result {Type :ResultTypeNumber ,ValueNumber :1,IsBoolean :true };};return Result {Type :ResultTypeNumber ,ValueNumber :0,IsBoolean :true };};func _eb ()evCache {_gfg :=evCache {};_gfg ._cda =make (map[string ]Result );_gfg ._deag =&_f .Mutex {};return _gfg ;};
 
 // Eval evaluates the binary expression using the context given.
-func (_fbe BinaryExpr )String ()string {_aac :="";switch _fbe ._dd {case BinOpTypePlus :_aac ="\u002b";case BinOpTypeMinus :_aac ="\u002d";case BinOpTypeMult :_aac ="\u002a";case BinOpTypeDiv :_aac ="\u002f";case BinOpTypeExp :_aac ="\u005e";case BinOpTypeLT :_aac ="\u003c";case BinOpTypeGT :_aac ="\u003e";case BinOpTypeEQ :_aac ="\u003d";case BinOpTypeLEQ :_aac ="\u003c\u003d";case BinOpTypeGEQ :_aac ="\u003e\u003d";case BinOpTypeNE :_aac ="\u003c\u003e";case BinOpTypeConcat :_aac ="\u0026";};return _fbe ._age .String ()+_aac +_fbe ._bd .String ();};func _cdgd (_faefb Result ,_edec *criteriaParsed )bool {_ggaa :=_ee .ToLower (_faefb .ValueString );_cbff :=_edec ._eeac ._fbffg ;_bgce :=_edec ._eeac ._fffea ;if _cbff ==_cgef {return _ggaa ==_bgce ||_ag .Match (_bgce ,_ggaa );};if _faefb .Type !=ResultTypeEmpty {if _ggaa ==_edec ._ebaec ||_ag .Match (_edec ._ebaec ,_ggaa ){return true ;};if _ ,_eagd :=_ff .ParseFloat (_bgce ,64);_eagd ==nil {return false ;};switch _cbff {case _cabdg :return _ggaa <=_bgce ;case _adcef :return _ggaa >=_bgce ;case _cfab :return _ggaa < _bgce ;case _baegf :return _ggaa > _bgce ;};};return false ;};
+func (_fbe BinaryExpr )String ()string {_aac :="";switch _fbe ._dd {case BinOpTypePlus :_aac ="\u002b";case BinOpTypeMinus :_aac ="\u002d";case BinOpTypeMult :_aac ="\u002a";case BinOpTypeDiv :_aac ="\u002f";case BinOpTypeExp :_aac ="\u005e";case BinOpTypeLT :_aac ="\u003c";case BinOpTypeGT :_aac ="\u003e";case BinOpTypeEQ :_aac ="\u003d";case BinOpTypeLEQ :_aac ="\u003c\u003d";case BinOpTypeGEQ :_aac ="\u003e\u003d";case BinOpTypeNE :_aac ="\u003c\u003e";case BinOpTypeConcat :_aac ="\u0026";};_cfbga :=precedence (_fbe );return operand (_fbe ._age ,_cfbga ,false )+_aac +operand (_fbe ._bd ,_cfbga ,true );};func _cdgd (_faefb Result ,_edec *criteriaParsed )bool {_ggaa :=_ee .ToLower (_faefb .ValueString );_cbff :=_edec ._eeac ._fbffg ;_bgce :=_edec ._eeac ._fffea ;if _cbff ==_cgef {return _ggaa ==_bgce ||_ag .Match (_bgce ,_ggaa );};if _faefb .Type !=ResultTypeEmpty {if _ggaa ==_edec ._ebaec ||_ag .Match (_edec ._ebaec ,_ggaa ){return true ;};if _ ,_eagd :=_ff .ParseFloat (_bgce ,64);_eagd ==nil {return false ;};switch _cbff {case _cabdg :return _ggaa <=_bgce ;case _adcef :return _ggaa >=_bgce ;case _cfab :return _ggaa < _bgce ;case _baegf :return _ggaa > _bgce ;};};return false ;};
 
 // VerticalRange is a range expression that when evaluated returns a list of Results from references like AA:IJ (all cells from columns AA to IJ).
 type VerticalRange struct{_efdad ,_ddbe string };var _egda =[]ri {{1000,"\u004d"},{950,"\u004c\u004d"},{900,"\u0043\u004d"},{500,"\u0044"},{450,"\u004c\u0044"},{400,"\u0043\u0044"},{100,"\u0043"},{95,"\u0056\u0043"},{90,"\u0058\u0043"},{50,"\u004c"},{45,"\u0056\u004c"},{40,"\u0058\u004c"},{10,"\u0058"},{9,"\u0049\u0058"},{5,"\u0056"},{4,"\u0049\u0056"},{1,"\u0049"}};var _befd =[...]int {0};
@@ -864,7 +865,7 @@ func (_dbege *ivr )IsBool (cellRef string )bool {return false };
 func (_bebde PrefixHorizontalRange )Reference (ctx Context ,ev Evaluator )Reference {_gcdb :=_bebde ._eegae .Reference (ctx ,ev );return Reference {Type :ReferenceTypeHorizontalRange ,Value :_bebde .horizontalRangeReference (_gcdb .Value )};};func _fae (_cfd float64 )_ce .Time {_ggf :=int64 ((_cfd -_fff )*_bbf );return _ce .Unix (0,_ggf ).UTC ()};
 
 // String returns a string representation of a horizontal range with prefix.
-func (_dedgc PrefixHorizontalRange )String ()string {return _c .Sprintf ("\u0025\u0073\u0021\u0025\u0064\u003a\u0025\u0064",_dedgc ._eegae .String (),_dedgc ._gfad ,_dedgc ._cfaf );};func _ecbad (_cbda int )bool {return _cbda ==0||_cbda ==4};
+func (_dedgc PrefixHorizontalRange )String ()string {return _dedgc ._eegae .String ()+"\u0021"+rowRangeText (_dedgc ._gfad ,_dedgc ._cfaf ,_dedgc .absFrom ,_dedgc .absTo );};func _ecbad (_cbda int )bool {return _cbda ==0||_cbda ==4};
 
 // MakeStringResult constructs a string result.
 func MakeStringResult (s string )Result {return Result {Type :ResultTypeString ,ValueString :s }};
@@ -911,7 +912,8 @@ func Tbilleq (args []Result )Result {if len (args )!=3{return MakeErrorResult ("
 func Oddlprice (args []Result )Result {if len (args )!=8&&len (args )!=9{return MakeErrorResult ("\u004f\u0044\u0044L\u0050\u0052\u0049\u0043\u0045\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0065\u0069\u0067\u0068\u0074\u0020\u006f\u0072\u0020\u006e\u0069\u006e\u0065\u0020a\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0073");};_bfge ,_aeef ,_gdgd :=_dfd (args [0],args [1],"\u004fD\u0044\u004c\u0050\u0052\u0049\u0043E");if _gdgd .Type ==ResultTypeError {return _gdgd ;};_abbeb ,_gdgd :=_faeb (args [2],"\u0069\u0073\u0073\u0075\u0065\u0020\u0064\u0061\u0074\u0065","\u004fD\u0044\u004c\u0050\u0052\u0049\u0043E");if _gdgd .Type ==ResultTypeError {return _gdgd ;};if _abbeb >=_bfge {return MakeErrorResultType (ErrorTypeNum ,"\u004c\u0061\u0073\u0074\u0020i\u006e\u0074\u0065\u0072\u0065\u0073\u0074\u0020\u0064\u0061\u0074\u0065\u0020s\u0068\u006f\u0075\u006c\u0064\u0020\u0062\u0065\u0020\u0062\u0065\u0066\u006f\u0072\u0065\u0020\u0073\u0065\u0074\u0074\u006c\u0065\u006d\u0065\u006e\u0074\u0020\u0064\u0061\u0074e");};_cfgd :=args [3];if _cfgd .Type !=ResultTypeNumber {return MakeErrorResult ("\u004f\u0044\u0044\u004c\u0050\u0052\u0049\u0043\u0045\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0072\u0061\u0074\u0065\u0020o\u0066\u0020\u0074\u0079\u0070e\u0020\u006eu\u006d\u0062\u0065\u0072");};_cabg :=_cfgd .ValueNumber ;if _cabg < 0{return MakeErrorResultType (ErrorTypeNum ,"R\u0061\u0074\u0065\u0020\u0073\u0068o\u0075\u006c\u0064\u0020\u0062\u0065\u0020\u006e\u006fn\u0020\u006e\u0065g\u0061t\u0069\u0076\u0065");};_fbc :=args [4];if _fbc .Type !=ResultTypeNumber {return MakeErrorResult ("\u004f\u0044\u0044\u004c\u0050\u0052\u0049\u0043\u0045\u0020\u0072\u0065\u0071u\u0069\u0072\u0065\u0073\u0020\u0079i\u0065\u006c\u0064\u0020\u006f\u0066\u0020\u0074\u0079\u0070\u0065\u0020\u006eu\u006d\u0062\u0065\u0072");};_fdae :=_fbc .ValueNumber ;if _fdae < 0{return MakeErrorResultType (ErrorTypeNum ,"\u0059\u0069\u0065\u006cd\u0020\u0073\u0068\u006f\u0075\u006c\u0064\u0020\u0062\u0065 \u006eo\u006e\u0020\u006e\u0065\u0067\u0061\u0074i\u0076\u0065");};_ebcc :=args [5];if _ebcc .Type !=ResultTypeNumber {return MakeErrorResult ("\u004fD\u0044\u004cP\u0052\u0049\u0043\u0045 \u0072\u0065\u0071u\u0069\u0072\u0065\u0073\u0020\u0072\u0065\u0064\u0065mp\u0074\u0069\u006fn\u0020\u006ff\u0020\u0074\u0079\u0070\u0065\u0020n\u0075\u006db\u0065\u0072");};_eefa :=_ebcc .ValueNumber ;if _eefa < 0{return MakeErrorResultType (ErrorTypeNum ,"\u0059\u0069\u0065\u006cd\u0020\u0073\u0068\u006f\u0075\u006c\u0064\u0020\u0062\u0065 \u006eo\u006e\u0020\u006e\u0065\u0067\u0061\u0074i\u0076\u0065");};_eaaa :=args [6];if _eaaa .Type !=ResultTypeNumber {return MakeErrorResult ("\u004f\u0044\u0044\u004c\u0050\u0052\u0049C\u0045\u0020\u0072e\u0071\u0075\u0069\u0072e\u0073\u0020\u0066\u0072\u0065\u0071\u0075\u0065\u006e\u0063\u0079\u0020\u006f\u0066\u0020\u0074\u0079\u0070\u0065\u0020\u006e\u0075\u006d\u0062\u0065\u0072");};_gcgg :=float64 (int (_eaaa .ValueNumber ));if !_bccga (_gcgg ){return MakeErrorResultType (ErrorTypeNum ,"\u0049n\u0063\u006f\u0072\u0072e\u0063\u0074\u0020\u0066\u0072e\u0071u\u0065n\u0063\u0065\u0020\u0076\u0061\u006c\u0075e");};_efgd :=0;if len (args )==8&&args [7].Type !=ResultTypeEmpty {_ddbd :=args [7];if _ddbd .Type !=ResultTypeNumber {return MakeErrorResult ("\u004f\u0044\u0044\u004c\u0050\u0052\u0049\u0043\u0045\u0020\u0072\u0065\u0071u\u0069\u0072\u0065\u0073\u0020\u0062a\u0073\u0069\u0073\u0020\u006f\u0066\u0020\u0074\u0079\u0070\u0065\u0020\u006eu\u006d\u0062\u0065\u0072");};_efgd =int (_ddbd .ValueNumber );if !_cfee (_efgd ){return MakeErrorResultType (ErrorTypeNum ,"I\u006e\u0063\u006f\u0072\u0072\u0065c\u0074\u0020\u0062\u0061\u0073\u0069s\u0020\u0076\u0061\u006c\u0075\u0065\u0020f\u006f\u0072\u0020\u004f\u0044\u0044\u004c\u0050\u0052\u0049C\u0045");};};_gafga ,_gdgd :=_fea (_abbeb ,_aeef ,_efgd );if _gdgd .Type ==ResultTypeError {return _gdgd ;};_gafga *=_gcgg ;_fbad ,_gdgd :=_fea (_bfge ,_aeef ,_efgd );if _gdgd .Type ==ResultTypeError {return _gdgd ;};_fbad *=_gcgg ;_bcba ,_gdgd :=_fea (_abbeb ,_bfge ,_efgd );if _gdgd .Type ==ResultTypeError {return _gdgd ;};_bcba *=_gcgg ;_cceg :=_eefa +_gafga *100*_cabg /_gcgg ;_cceg /=_fbad *_fdae /_gcgg +1;_cceg -=_bcba *100*_cabg /_gcgg ;return MakeNumberResult (_cceg );};
 
 // Counta implements the COUNTA function.
-func Counta (args []Result )Result {return MakeNumberResult (_edbeb (args ,_ecaa ))};func (_fbba *ivr )SetOffset (col ,row uint32 ){};func (_ecbg *Lexer )nextRaw ()*node {for len (_ecbg ._fgcg )!=0{_acccfc :=<-_ecbg ._fgcg [len (_ecbg ._fgcg )-1];if _acccfc !=nil {return _acccfc ;};_ecbg ._fgcg =_ecbg ._fgcg [0:len (_ecbg ._fgcg )-1];};return <-_ecbg ._daeg ;};const _fefgc =1;const _eacd =16;const _bgfd =57362;
+func Counta (args []Result )Result {return MakeNumberResult (_edbeb (args ,_ecaa ))};func (_fbba *ivr )SetOffset (col ,row uint32 ){};
+func (_ecbg *Lexer )nextRaw ()*node {for len (_ecbg ._fgcg )!=0{_acccfc :=<-_ecbg ._fgcg [len (_ecbg ._fgcg )-1];if _acccfc !=nil {return _acccfc ;};_ecbg ._fgcg =_ecbg ._fgcg [0:len (_ecbg ._fgcg )-1];};return <-_ecbg ._daeg ;};const _fefgc =1;const _eacd =16;const _bgfd =57362;
 
 // LastEvalIsRef returns if last evaluation with the evaluator was a reference.
 func (_cfcd *defEval )LastEvalIsRef ()bool {return _cfcd ._egb };func _cefc (_cbgg string ,_efcf func (_cefa float64 )float64 )Function {return func (_cdedc []Result )Result {if len (_cdedc )!=1{return MakeErrorResult (_cbgg +"\u0020\u0072\u0065\u0071ui\u0072\u0065\u0073\u0020\u006f\u006e\u0065\u0020\u0061\u0072\u0067\u0075\u006d\u0065n\u0074");};_bcecc :=_cdedc [0].AsNumber ();switch _bcecc .Type {case ResultTypeNumber :_bfadf :=_efcf (_bcecc .ValueNumber );if _dc .IsNaN (_bfadf ){return MakeErrorResult (_cbgg +"\u0020\u0072\u0065\u0074\u0075\u0072\u006e\u0065\u0064\u0020\u004e\u0061\u004e");};if _dc .IsInf (_bfadf ,0){return MakeErrorResult (_cbgg +"\u0020r\u0065t\u0075\u0072\u006e\u0065\u0064 \u0069\u006ef\u0069\u006e\u0069\u0074\u0079");};if _bfadf ==0{return MakeErrorResultType (ErrorTypeDivideByZero ,_cbgg +"\u0020d\u0069v\u0069\u0064\u0065\u0020\u0062\u0079\u0020\u007a\u0065\u0072\u006f");};return MakeNumberResult (1/_bfadf );case ResultTypeList ,ResultTypeString :return MakeErrorResult (_cbgg +"\u0020\u0072\u0065\u0071u\u0069\u0072\u0065\u0073\u0020\u0061\u0020\u006e\u0075\u006de\u0072i\u0063\u0020\u0061\u0072\u0067\u0075\u006de\u006e\u0074");case ResultTypeError :return _bcecc ;default:return MakeErrorResult (_c .Sprintf ("\u0075\u006e\u0068a\u006e\u0064\u006c\u0065d\u0020\u0025\u0073\u0028\u0029\u0020\u0061r\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u0074\u0079\u0070\u0065\u0020\u0025\u0073",_cbgg ,_bcecc .Type ));};};};
@@ -937,7 +939,7 @@ func If (args []Result )Result {if len (args )==0{return MakeErrorResult ("\u004
 func (_cfgbd Number )Update (q *_cc .UpdateQuery )Expression {return _cfgbd };
 
 // String returns a string representation of SheetPrefixExpr.
-func (_eegf SheetPrefixExpr )String ()string {return _eegf ._edaee };
+func (_eegf SheetPrefixExpr )String ()string {return quoteSheetName (_eegf ._edaee )};
 
 // Syd implements the Excel SYD function.
 func Syd (args []Result )Result {if len (args )!=4{return MakeErrorResult ("S\u0059\u0044\u0020\u0072\u0065\u0071u\u0069\u0072\u0065\u0073\u0020\u0066\u006f\u0075\u0072 \u0061\u0072\u0067u\u006de\u006e\u0074\u0073");};if args [0].Type !=ResultTypeNumber {return MakeErrorResult ("\u0053\u0059\u0044\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020c\u006f\u0073\u0074\u0020\u0074\u006f \u0062\u0065\u0020\u006e\u0075\u006d\u0062\u0065\u0072\u0020\u0061\u0072\u0067u\u006d\u0065\u006e\u0074");};_edada :=args [0].ValueNumber ;if args [1].Type !=ResultTypeNumber {return MakeErrorResult ("\u0053\u0059\u0044 \u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0073\u0061\u006c\u0076\u0061\u0067\u0065\u0020\u0074\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006d\u0062\u0065\u0072 \u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");};_dcda :=args [1].ValueNumber ;if args [2].Type !=ResultTypeNumber {return MakeErrorResult ("\u0053\u0059\u0044\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020l\u0069\u0066\u0065\u0020\u0074\u006f \u0062\u0065\u0020\u006e\u0075\u006d\u0062\u0065\u0072\u0020\u0061\u0072\u0067u\u006d\u0065\u006e\u0074");};_ccabb :=args [2].ValueNumber ;if _ccabb <=0{return MakeErrorResultType (ErrorTypeNum ,"\u0053\u0059\u0044\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u006c\u0069f\u0065 \u0074\u006f\u0020\u0062\u0065\u0020\u0070\u006f\u0073\u0069\u0074\u0069\u0076\u0065");};if args [3].Type !=ResultTypeNumber {return MakeErrorResult ("\u0053\u0059\u0044\u0020\u0072e\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0070\u0065\u0072\u0069\u006f\u0064 \u0074\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006d\u0062\u0065\u0072\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");};_ddaf :=args [3].ValueNumber ;if _ddaf <=0{return MakeErrorResultType (ErrorTypeNum ,"\u0053\u0059\u0044 r\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0070e\u0072i\u006fd\u0020t\u006f\u0020\u0062\u0065\u0020\u0070\u006f\u0073\u0069\u0074\u0069\u0076\u0065");};if _ddaf > _ccabb {return MakeErrorResultType (ErrorTypeNum ,"\u0053\u0059\u0044\u0020\u0072\u0065q\u0075\u0069\u0072\u0065\u0073\u0020\u0070\u0065\u0072\u0069\u006f\u0064\u0020\u0074\u006f\u0020\u0062\u0065\u0020\u0065q\u0075\u0061\u006c\u0020\u006f\u0072\u0020\u006c\u0065\u0073\u0073\u0020\u0074\u0068a\u006e \u006c\u0069\u0066\u0065");};_dbdb :=(_edada -_dcda )*(_ccabb -_ddaf +1)*2;_bacd :=_ccabb *(_ccabb +1);return MakeNumberResult (_dbdb /_bacd );};func (_gfbeb *yyParserImpl )Parse (yylex yyLexer )int {var _dbec int ;var _fcfac yySymType ;var _bcdag []yySymType ;_ =_bcdag ;_dbgaa :=_gfbeb ._gbead [:];Nerrs :=0;Errflag :=0;_acbcg :=0;_gfbeb ._gafef =-1;_cbcc :=-1;defer func (){_acbcg =-1;_gfbeb ._gafef =-1;_cbcc =-1}();_ggdac :=-1;goto _aaabb ;_gcgf :return 0;_fcdfc :return 1;_aaabb :if _fabd >=4{_c .Printf ("\u0063\u0068\u0061\u0072\u0020\u0025\u0076\u0020\u0069n\u0020\u0025\u0076\u000a",_ffdeg (_cbcc ),_ecec (_acbcg ));};_ggdac ++;if _ggdac >=len (_dbgaa ){_gbege :=make ([]yySymType ,len (_dbgaa )*2);copy (_gbege ,_dbgaa );_dbgaa =_gbege ;};_dbgaa [_ggdac ]=_fcfac ;_dbgaa [_ggdac ]._gbae =_acbcg ;_fbaec :_dbec =_eccf [_acbcg ];if _dbec <=_dfga {goto _eadaf ;};if _gfbeb ._gafef < 0{_gfbeb ._gafef ,_cbcc =_cdfdb (yylex ,&_gfbeb ._dgacb );};_dbec +=_cbcc ;if _dbec < 0||_dbec >=_daabe {goto _eadaf ;};_dbec =_aabgb [_dbec ];if _adbea [_dbec ]==_cbcc {_gfbeb ._gafef =-1;_cbcc =-1;_fcfac =_gfbeb ._dgacb ;_acbcg =_dbec ;if Errflag > 0{Errflag --;};goto _aaabb ;};_eadaf :_dbec =_ccee [_acbcg ];if _dbec ==-2{if _gfbeb ._gafef < 0{_gfbeb ._gafef ,_cbcc =_cdfdb (yylex ,&_gfbeb ._dgacb );};_baaa :=0;for {if _egeea [_baaa +0]==-1&&_egeea [_baaa +1]==_acbcg {break ;};_baaa +=2;};for _baaa +=2;;_baaa +=2{_dbec =_egeea [_baaa +0];if _dbec < 0||_dbec ==_cbcc {break ;};};_dbec =_egeea [_baaa +1];if _dbec < 0{goto _gcgf ;};};if _dbec ==0{switch Errflag {case 0:yylex .Error (_gbdgg (_acbcg ,_cbcc ));Nerrs ++;if _fabd >=1{_c .Printf ("\u0025\u0073",_ecec (_acbcg ));_c .Printf ("\u0020\u0073\u0061\u0077\u0020\u0025\u0073\u000a",_ffdeg (_cbcc ));};fallthrough;case 1,2:Errflag =3;for _ggdac >=0{_dbec =_eccf [_dbgaa [_ggdac ]._gbae ]+_effd ;if _dbec >=0&&_dbec < _daabe {_acbcg =_aabgb [_dbec ];if _adbea [_acbcg ]==_effd {goto _aaabb ;};};if _fabd >=2{_c .Printf ("\u0065\u0072r\u006f\u0072\u0020\u0072\u0065\u0063\u006f\u0076\u0065\u0072\u0079\u0020\u0070\u006f\u0070\u0073\u0020\u0073\u0074\u0061\u0074\u0065 %\u0064\u000a",_dbgaa [_ggdac ]._gbae );};_ggdac --;};goto _fcdfc ;case 3:if _fabd >=2{_c .Printf ("e\u0072\u0072\u006f\u0072\u0020\u0072e\u0063\u006f\u0076\u0065\u0072\u0079\u0020\u0064\u0069s\u0063\u0061\u0072d\u0073 \u0025\u0073\u000a",_ffdeg (_cbcc ));};if _cbcc ==_fefgc {goto _fcdfc ;};_gfbeb ._gafef =-1;_cbcc =-1;goto _fbaec ;};};if _fabd >=2{_c .Printf ("\u0072e\u0064u\u0063\u0065\u0020\u0025\u0076 \u0069\u006e:\u000a\u0009\u0025\u0076\u000a",_dbec ,_ecec (_acbcg ));};_gfabg :=_dbec ;_bgeb :=_ggdac ;_ =_bgeb ;_ggdac -=_eddb [_dbec ];if _ggdac +1>=len (_dbgaa ){_dgcd :=make ([]yySymType ,len (_dbgaa )*2);copy (_dgcd ,_dbgaa );_dbgaa =_dgcd ;};_fcfac =_dbgaa [_ggdac +1];_dbec =_gcde [_dbec ];_fagb :=_fgeed [_dbec ];_cagcf :=_fagb +_dbgaa [_ggdac ]._gbae +1;if _cagcf >=_daabe {_acbcg =_aabgb [_fagb ];}else {_acbcg =_aabgb [_cagcf ];if _adbea [_acbcg ]!=-_dbec {_acbcg =_aabgb [_fagb ];};};switch _gfabg {case 1:_bcdag =_dbgaa [_bgeb -1:_bgeb +1];{yylex .(*plex )._deddc =_fcfac ._aagaec ;};case 3:_bcdag =_dbgaa [_bgeb -2:_bgeb +1];{_fcfac ._aagaec =_bcdag [2]._aagaec ;};case 4:_bcdag =_dbgaa [_bgeb -4:_bgeb +1];{};case 5:_bcdag =_dbgaa [_bgeb -1:_bgeb +1];{_fcfac ._aagaec =NewBool (_bcdag [1]._dfcfe ._acccf );};case 6:_bcdag =_dbgaa [_bgeb -1:_bgeb +1];{_fcfac ._aagaec =NewNumber (_bcdag [1]._dfcfe ._acccf );};case 7:_bcdag =_dbgaa [_bgeb -1:_bgeb +1];{_fcfac ._aagaec =NewString (_bcdag [1]._dfcfe ._acccf );};case 8:_bcdag =_dbgaa [_bgeb -1:_bgeb +1];{_fcfac ._aagaec =NewError (_bcdag [1]._dfcfe ._acccf );};case 9:_bcdag =_dbgaa [_bgeb -2:_bgeb +1];{_fcfac ._aagaec =_bcdag [2]._aagaec ;};case 10:_bcdag =_dbgaa [_bgeb -2:_bgeb +1];{_fcfac ._aagaec =NewNegate (_bcdag [2]._aagaec );};case 15:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =_bcdag [2]._aagaec ;};case 17:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =NewConstArrayExpr (_bcdag [2]._aead );};case 18:_bcdag =_dbgaa [_bgeb -1:_bgeb +1];{_fcfac ._aead =append (_fcfac ._aead ,_bcdag [1]._efaac );};case 19:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aead =append (_bcdag [1]._aead ,_bcdag [3]._efaac );};case 20:_bcdag =_dbgaa [_bgeb -1:_bgeb +1];{_fcfac ._efaac =append (_fcfac ._efaac ,_bcdag [1]._aagaec );};case 21:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._efaac =append (_bcdag [1]._efaac ,_bcdag [3]._aagaec );};case 23:_bcdag =_dbgaa [_bgeb -2:_bgeb +1];{_fcfac ._aagaec =NewPrefixExpr (_bcdag [1]._aagaec ,_bcdag [2]._aagaec );};case 25:_bcdag =_dbgaa [_bgeb -1:_bgeb +1];{_fcfac ._aagaec =NewSheetPrefixExpr (_bcdag [1]._dfcfe ._acccf );};case 26:_bcdag =_dbgaa [_bgeb -1:_bgeb +1];{_fcfac ._aagaec =NewCellRef (_bcdag [1]._dfcfe ._acccf );};case 27:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =NewRange (_bcdag [1]._aagaec ,_bcdag [3]._aagaec );};case 28:_bcdag =_dbgaa [_bgeb -4:_bgeb +1];{_fcfac ._aagaec =NewPrefixRangeExpr (_bcdag [1]._aagaec ,_bcdag [2]._aagaec ,_bcdag [4]._aagaec );};case 29:_bcdag =_dbgaa [_bgeb -1:_bgeb +1];{_fcfac ._aagaec =NewNamedRangeRef (_bcdag [1]._dfcfe ._acccf );};case 30:_bcdag =_dbgaa [_bgeb -1:_bgeb +1];{_fcfac ._aagaec =NewHorizontalRange (_bcdag [1]._dfcfe ._acccf );};case 31:_bcdag =_dbgaa [_bgeb -1:_bgeb +1];{_fcfac ._aagaec =NewVerticalRange (_bcdag [1]._dfcfe ._acccf );};case 32:_bcdag =_dbgaa [_bgeb -2:_bgeb +1];{_fcfac ._aagaec =NewPrefixHorizontalRange (_bcdag [1]._aagaec ,_bcdag [2]._dfcfe ._acccf );};case 33:_bcdag =_dbgaa [_bgeb -2:_bgeb +1];{_fcfac ._aagaec =NewPrefixVerticalRange (_bcdag [1]._aagaec ,_bcdag [2]._dfcfe ._acccf );};case 34:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =NewBinaryExpr (_bcdag [1]._aagaec ,BinOpTypePlus ,_bcdag [3]._aagaec );};case 35:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =NewBinaryExpr (_bcdag [1]._aagaec ,BinOpTypeMinus ,_bcdag [3]._aagaec );};case 36:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =NewBinaryExpr (_bcdag [1]._aagaec ,BinOpTypeMult ,_bcdag [3]._aagaec );};case 37:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =NewBinaryExpr (_bcdag [1]._aagaec ,BinOpTypeDiv ,_bcdag [3]._aagaec );};case 38:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =NewBinaryExpr (_bcdag [1]._aagaec ,BinOpTypeExp ,_bcdag [3]._aagaec );};case 39:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =NewBinaryExpr (_bcdag [1]._aagaec ,BinOpTypeLT ,_bcdag [3]._aagaec );};case 40:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =NewBinaryExpr (_bcdag [1]._aagaec ,BinOpTypeGT ,_bcdag [3]._aagaec );};case 41:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =NewBinaryExpr (_bcdag [1]._aagaec ,BinOpTypeLEQ ,_bcdag [3]._aagaec );};case 42:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =NewBinaryExpr (_bcdag [1]._aagaec ,BinOpTypeGEQ ,_bcdag [3]._aagaec );};case 43:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =NewBinaryExpr (_bcdag [1]._aagaec ,BinOpTypeEQ ,_bcdag [3]._aagaec );};case 44:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =NewBinaryExpr (_bcdag [1]._aagaec ,BinOpTypeNE ,_bcdag [3]._aagaec );};case 45:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =NewBinaryExpr (_bcdag [1]._aagaec ,BinOpTypeConcat ,_bcdag [3]._aagaec );};case 47:_bcdag =_dbgaa [_bgeb -2:_bgeb +1];{_fcfac ._aagaec =NewFunction (_bcdag [1]._dfcfe ._acccf ,nil );};case 48:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._aagaec =NewFunction (_bcdag [1]._dfcfe ._acccf ,_bcdag [2]._efaac );};case 49:_bcdag =_dbgaa [_bgeb -1:_bgeb +1];{_fcfac ._efaac =append (_fcfac ._efaac ,_bcdag [1]._aagaec );};case 50:_bcdag =_dbgaa [_bgeb -3:_bgeb +1];{_fcfac ._efaac =append (_bcdag [1]._efaac ,_bcdag [3]._aagaec );};case 53:_bcdag =_dbgaa [_bgeb -0:_bgeb +1];{_fcfac ._aagaec =NewEmptyExpr ();};};goto _aaabb ;};
@@ -975,25 +977,25 @@ func Db (args []Result )Result {_dagd :=len (args );if _dagd !=4&&_dagd !=5{retu
 
 // MakeErrorResultType makes an error result of a given type with a specified
 // debug message
-func MakeErrorResultType (t ErrorType ,msg string )Result {switch t {case ErrorTypeNull :return Result {Type :ResultTypeError ,ValueString :"\u0023\u004e\u0055\u004c\u004c\u0021",ErrorMessage :msg };case ErrorTypeValue :return Result {Type :ResultTypeError ,ValueString :"\u0023V\u0041\u004c\u0055\u0045\u0021",ErrorMessage :msg };case ErrorTypeRef :return Result {Type :ResultTypeError ,ValueString :"\u0023\u0052\u0045F\u0021",ErrorMessage :msg };case ErrorTypeName :return Result {Type :ResultTypeError ,ValueString :"\u0023\u004e\u0041\u004d\u0045\u003f",ErrorMessage :msg };case ErrorTypeNum :return Result {Type :ResultTypeError ,ValueString :"\u0023\u004e\u0055M\u0021",ErrorMessage :msg };case ErrorTypeSpill :return Result {Type :ResultTypeError ,ValueString :"\u0023S\u0050\u0049\u004c\u004c\u0021",ErrorMessage :msg };case ErrorTypeNA :return Result {Type :ResultTypeError ,ValueString :"\u0023\u004e\u002f\u0041",ErrorMessage :msg };case ErrorTypeDivideByZero :return Result {Type :ResultTypeError ,ValueString :"\u0023D\u0049\u0056\u002f\u0030\u0021",ErrorMessage :msg };default:return Result {Type :ResultTypeError ,ValueString :"\u0023V\u0041\u004c\u0055\u0045\u0021",ErrorMessage :msg };};};
+func MakeErrorResultType (t ErrorType ,msg string )Result {switch t {case ErrorTypeNull :return Result {Type :ResultTypeError ,ValueString :"\u0023\u004e\u0055\u004c\u004c\u0021",ErrorMessage :msg };case ErrorTypeValue :return Result {Type :ResultTypeError ,ValueString :"\u0023V\u0041\u004c\u0055\u0045\u0021",ErrorMessage :msg };case ErrorTypeRef :return Result {Type :ResultTypeError ,ValueString :"\u0023\u0052\u0045F\u0021",ErrorMessage :msg };case ErrorTypeName :return Result {Type :ResultTypeError ,ValueString :"\u0023\u004e\u0041\u004d\u0045\u003f",ErrorMessage :msg };case ErrorTypeNum :return Result {Type :ResultTypeError ,ValueString :"\u0023\u004e\u0055M\u0021",ErrorMessage :msg };case ErrorTypeSpill :return Result {Type :ResultTypeError ,ValueString :"\u0023S\u0050\u0049\u004c\u004c\u0021",ErrorMessage :msg };case ErrorTypeNA :return Result {Type :ResultTypeError ,ValueString :"\u0023\u004e\u002f\u0041",ErrorMessage :msg };case ErrorTypeDivideByZero :return Result {Type :ResultTypeError ,ValueString :"\u0023D\u0049\u0056\u002f\u0030\u0021",ErrorMessage :msg };case ErrorTypeCalc :return Result {Type :ResultTypeError ,ValueString :"\u0023\u0043\u0041\u004c\u0043\u0021",ErrorMessage :msg };default:return Result {Type :ResultTypeError ,ValueString :"\u0023V\u0041\u004c\u0055\u0045\u0021",ErrorMessage :msg };};};
 
 // ISEVEN is an implementation of the Excel ISEVEN() function.
 func IsEven (args []Result )Result {if len (args )!=1{MakeErrorResult ("\u0049\u0053\u0045VE\u004e\u0028\u0029\u0020\u0061\u0063\u0063\u0065\u0070t\u0073 \u0061 \u0073i\u006e\u0067\u006c\u0065\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");};if args [0].Type !=ResultTypeNumber {return MakeErrorResult ("\u0049\u0053\u0045\u0056\u0045\u004e \u0061\u0063\u0063\u0065\u0070\u0074\u0073\u0020\u0061\u0020\u006e\u0075\u006de\u0072\u0069\u0063\u0020\u0061\u0072\u0067u\u006d\u0065\u006e\u0074");};_aeca :=int (args [0].ValueNumber );return MakeBoolResult (_aeca ==_aeca /2*2);};
 
 // NewNamedRangeRef constructs a new named range reference.
-func NewNamedRangeRef (v string )Expression {return NamedRangeRef {v }};func _egedb (_acbf string )string {_acbf =_ee .Replace (_acbf ,"\u000a","\u005c\u006e",-1);_acbf =_ee .Replace (_acbf ,"\u000d","\u005c\u0072",-1);_acbf =_ee .Replace (_acbf ,"\u0009","\u005c\u0074",-1);return _acbf ;};var _fff float64 =25569.0;func _gdfbf (_fggbf ,_dba Result ,_dafcf string )(*xargs ,Result ){if _fggbf .Type !=ResultTypeList &&_fggbf .Type !=ResultTypeArray {return nil ,MakeErrorResult (_dafcf +"\u0020\u0072eq\u0075\u0069\u0072e\u0073\u0020\u0076\u0061lue\u0073 t\u006f\u0020\u0062\u0065\u0020\u006f\u0066 a\u0072\u0072\u0061\u0079\u0020\u0074\u0079p\u0065");};_gacd :=_cdafd (_fggbf );_acf :=[]float64 {};for _ ,_eada :=range _gacd {for _ ,_gcff :=range _eada {if _gcff .Type ==ResultTypeNumber &&!_gcff .IsBoolean {_acf =append (_acf ,_gcff .ValueNumber );}else {return nil ,MakeErrorResult (_dafcf +"\u0072\u0065q\u0075\u0069\u0072\u0065\u0073\u0020\u0076\u0061\u006c\u0075\u0065\u0073\u0020\u0074\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006dbe\u0072\u0073");};};};_agab :=len (_acf );if len (_acf )< 2{return nil ,MakeErrorResultType (ErrorTypeNum ,"");};if _dba .Type !=ResultTypeList &&_dba .Type !=ResultTypeArray {return nil ,MakeErrorResult (_dafcf +" \u0072\u0065\u0071\u0075\u0069\u0072e\u0073\u0020\u0064\u0061\u0074\u0065s\u0020\u0074\u006f\u0020\u0062\u0065\u0020o\u0066\u0020\u0061\u0072\u0072\u0061\u0079\u0020\u0074\u0079p\u0065");};_ffbe :=_cdafd (_dba );_dace :=[]float64 {};_agce :=0.0;for _ ,_fccb :=range _ffbe {for _ ,_cfed :=range _fccb {if _cfed .Type ==ResultTypeNumber &&!_cfed .IsBoolean {_dgc :=float64 (int (_cfed .ValueNumber ));if _dgc < _agce {return nil ,MakeErrorResultType (ErrorTypeNum ,_dafcf +" \u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0064\u0061\u0074\u0065\u0073\u0020\u0074\u006f\u0020b\u0065\u0020\u0069\u006e\u0020\u0061\u0073\u0063\u0065\u006edi\u006e\u0067\u0020o\u0072d\u0065\u0072");};_dace =append (_dace ,_dgc );_agce =_dgc ;}else {return nil ,MakeErrorResult (_dafcf +"\u0072\u0065\u0071\u0075i\u0072\u0065\u0073\u0020\u0064\u0061\u0074\u0065\u0073\u0020t\u006f \u0062\u0065\u0020\u006e\u0075\u006d\u0062e\u0072\u0073");};};};if len (_dace )!=_agab {return nil ,MakeErrorResultType (ErrorTypeNum ,"");};return &xargs {_acf ,_dace },MakeEmptyResult ();};func (_gdae *evCache )SetCache (key string ,value Result ){_gdae ._deag .Lock ();_gdae ._cda [key ]=value ;_gdae ._deag .Unlock ();};
+func NewNamedRangeRef (v string )Expression {if _ee .ContainsRune (v ,'['){return NewStructuredRef (v );};return NamedRangeRef {v }};func _egedb (_acbf string )string {_acbf =_ee .Replace (_acbf ,"\u000a","\u005c\u006e",-1);_acbf =_ee .Replace (_acbf ,"\u000d","\u005c\u0072",-1);_acbf =_ee .Replace (_acbf ,"\u0009","\u005c\u0074",-1);return _acbf ;};var _fff float64 =25569.0;func _gdfbf (_fggbf ,_dba Result ,_dafcf string )(*xargs ,Result ){if _fggbf .Type !=ResultTypeList &&_fggbf .Type !=ResultTypeArray {return nil ,MakeErrorResult (_dafcf +"\u0020\u0072eq\u0075\u0069\u0072e\u0073\u0020\u0076\u0061lue\u0073 t\u006f\u0020\u0062\u0065\u0020\u006f\u0066 a\u0072\u0072\u0061\u0079\u0020\u0074\u0079p\u0065");};_gacd :=_cdafd (_fggbf );_acf :=[]float64 {};for _ ,_eada :=range _gacd {for _ ,_gcff :=range _eada {if _gcff .Type ==ResultTypeNumber &&!_gcff .IsBoolean {_acf =append (_acf ,_gcff .ValueNumber );}else {return nil ,MakeErrorResult (_dafcf +"\u0072\u0065q\u0075\u0069\u0072\u0065\u0073\u0020\u0076\u0061\u006c\u0075\u0065\u0073\u0020\u0074\u006f\u0020\u0062\u0065\u0020\u006e\u0075\u006dbe\u0072\u0073");};};};_agab :=len (_acf );if len (_acf )< 2{return nil ,MakeErrorResultType (ErrorTypeNum ,"");};if _dba .Type !=ResultTypeList &&_dba .Type !=ResultTypeArray {return nil ,MakeErrorResult (_dafcf +" \u0072\u0065\u0071\u0075\u0069\u0072e\u0073\u0020\u0064\u0061\u0074\u0065s\u0020\u0074\u006f\u0020\u0062\u0065\u0020o\u0066\u0020\u0061\u0072\u0072\u0061\u0079\u0020\u0074\u0079p\u0065");};_ffbe :=_cdafd (_dba );_dace :=[]float64 {};_agce :=0.0;for _ ,_fccb :=range _ffbe {for _ ,_cfed :=range _fccb {if _cfed .Type ==ResultTypeNumber &&!_cfed .IsBoolean {_dgc :=float64 (int (_cfed .ValueNumber ));if _dgc < _agce {return nil ,MakeErrorResultType (ErrorTypeNum ,_dafcf +" \u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0064\u0061\u0074\u0065\u0073\u0020\u0074\u006f\u0020b\u0065\u0020\u0069\u006e\u0020\u0061\u0073\u0063\u0065\u006edi\u006e\u0067\u0020o\u0072d\u0065\u0072");};_dace =append (_dace ,_dgc );_agce =_dgc ;}else {return nil ,MakeErrorResult (_dafcf +"\u0072\u0065\u0071\u0075i\u0072\u0065\u0073\u0020\u0064\u0061\u0074\u0065\u0073\u0020t\u006f \u0062\u0065\u0020\u006e\u0075\u006d\u0062e\u0072\u0073");};};};if len (_dace )!=_agab {return nil ,MakeErrorResultType (ErrorTypeNum ,"");};return &xargs {_acf ,_dace },MakeEmptyResult ();};func (_gdae *evCache )SetCache (key string ,value Result ){_gdae ._deag .Lock ();_gdae ._cda [key ]=value ;_gdae ._deag .Unlock ();};
 
 // String returns a string representation of ConstArrayExpr.
-func (_gbb ConstArrayExpr )String ()string {return ""};
+func (_gbb ConstArrayExpr )String ()string {return constArrayString (_gbb ._fdb )};
 
 // HorizontalRange is a range expression that when evaluated returns a list of Results from references like 1:4 (all cells from rows 1 to 4).
-type HorizontalRange struct{_ddgg ,_aabf int };
+type HorizontalRange struct{_ddgg ,_aabf int ;absFrom ,absTo bool ;};
 
 // LookupFunctionComplex looks up and returns a complex function or nil.
 func LookupFunctionComplex (name string )FunctionComplex {_fafbc .Lock ();defer _fafbc .Unlock ();if _ffced ,_fdbe :=_bedfa [name ];_fdbe {return _ffced ;};return nil ;};
 
 // NewPrefixExpr constructs an expression with prefix.
-func NewPrefixExpr (pfx ,exp Expression )Expression {return &PrefixExpr {pfx ,exp }};const _eggd =_dae +"\u0020\u0028\u0028[0\u002d\u0039\u005d\u0029\u002b\u0029\u002c\u0020\u0028\u0028\u005b\u0030\u002d\u0039\u005d\u0029\u002b\u0029";func (_gdaacg *plex )Lex (lval *yySymType )int {_adgdg =true ;_fecf :=<-_gdaacg ._cecgd ;if _fecf !=nil {lval ._dfcfe =_fecf ;return int (lval ._dfcfe ._faaa );};return 0;};
+func NewPrefixExpr (pfx ,exp Expression )Expression {return &PrefixExpr {pfx ,exp }};const _eggd =_dae +"\u0020\u0028\u0028[0\u002d\u0039\u005d\u0029\u002b\u0029\u002c\u0020\u0028\u0028\u005b\u0030\u002d\u0039\u005d\u0029\u002b\u0029";func (_gdaacg *plex )Lex (lval *yySymType )int {_fecf :=<-_gdaacg ._cecgd ;if _fecf !=nil {lval ._dfcfe =_fecf ;return int (lval ._dfcfe ._faaa );};return 0;};
 
 // FunctionCall is a function call expression.
 type FunctionCall struct{_fbae string ;_agff []Expression ;};var _cdd =[]*_gf .Regexp {};
@@ -1013,7 +1015,7 @@ func (_fg BinaryExpr )Reference (ctx Context ,ev Evaluator )Reference {return Re
 func CeilingPrecise (args []Result )Result {if len (args )==0{return MakeErrorResult ("\u0043\u0045\u0049\u004c\u0049\u004e\u0047\u002eP\u0052\u0045\u0043IS\u0045\u0028\u0029\u0020\u0072\u0065q\u0075\u0069\u0072\u0065\u0073\u0020\u0061\u0074\u0020\u006c\u0065\u0061\u0073\u0074\u0020o\u006e\u0065\u0020\u0061\u0072\u0067\u0075\u006de\u006e\u0074");};if len (args )> 2{return MakeErrorResult ("\u0043\u0045I\u004c\u0049\u004e\u0047\u002e\u0050\u0052\u0045\u0043\u0049\u0053\u0045\u0028\u0029\u0020\u0061\u006c\u006c\u006f\u0077\u0073\u0020\u0061\u0074\u0020\u006d\u006f\u0073\u0074\u0020\u0074\u0077\u006f\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0073");};_gggef :=args [0].AsNumber ();if _gggef .Type !=ResultTypeNumber {return MakeErrorResult ("\u0066\u0069r\u0073\u0074\u0020\u0061\u0072g\u0075\u006d\u0065\u006e\u0074 \u0074\u006f\u0020\u0043\u0045\u0049\u004c\u0049\u004e\u0047\u002e\u0050\u0052\u0045\u0043\u0049\u0053\u0045\u0028\u0029\u0020\u006d\u0075\u0073\u0074\u0020\u0062\u0065\u0020\u0061\u0020\u006e\u0075\u006d\u0062\u0065\u0072");};_ebde :=float64 (1);if _gggef .ValueNumber < 0{_ebde =-1;};if len (args )> 1{_ccdbg :=args [1].AsNumber ();if _ccdbg .Type !=ResultTypeNumber {return MakeErrorResult ("\u0073\u0065\u0063\u006f\u006e\u0064\u0020\u0061\u0072\u0067\u0075m\u0065\u006e\u0074\u0020\u0074\u006f\u0020\u0043E\u0049L\u0049\u004e\u0047\u002e\u0050\u0052\u0045\u0043\u0049\u0053\u0045\u0028\u0029\u0020\u006d\u0075\u0073\u0074 \u0062\u0065\u0020\u0061\u0020\u006e\u0075\u006d\u0062\u0065\u0072");};_ebde =_dc .Abs (_ccdbg .ValueNumber );};if len (args )==1{return MakeNumberResult (_dc .Ceil (_gggef .ValueNumber ));};_ccda :=_gggef .ValueNumber ;_ccda ,_cdgf :=_dc .Modf (_ccda /_ebde );if _cdgf !=0{if _gggef .ValueNumber > 0{_ccda ++;};};return MakeNumberResult (_ccda *_ebde );};const _efdc =57361;var _egbcg []byte =[]byte {0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,69,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0};
 
 // Eval evaluates and returns the result of a function call.
-func (_cagf FunctionCall )Eval (ctx Context ,ev Evaluator )Result {_fbeg :=LookupFunction (_cagf ._fbae );if _fbeg !=nil {_cffgbe :=make ([]Result ,len (_cagf ._agff ));for _bdbg ,_aece :=range _cagf ._agff {_cffgbe [_bdbg ]=_aece .Eval (ctx ,ev );_cffgbe [_bdbg ].Ref =_aece .Reference (ctx ,ev );};return _fbeg (_cffgbe );};_fabf :=LookupFunctionComplex (_cagf ._fbae );if _fabf !=nil {_eaaf :=make ([]Result ,len (_cagf ._agff ));for _afcec ,_gdfg :=range _cagf ._agff {_eaaf [_afcec ]=_gdfg .Eval (ctx ,ev );_eaaf [_afcec ].Ref =_gdfg .Reference (ctx ,ev );};return _fabf (ctx ,ev ,_eaaf );};return MakeErrorResult ("\u0075\u006e\u006b\u006e\u006f\u0077\u006e\u0020\u0066\u0075\u006e\u0063t\u0069\u006f\u006e\u0020"+_cagf ._fbae );};
+func (_cagf FunctionCall )Eval (ctx Context ,ev Evaluator )Result {if _fcbd ,_fcbe :=_cagf .evalSpecial (ctx ,ev );_fcbe {return _fcbd ;};_fbeg :=LookupFunction (_cagf ._fbae );if _fbeg !=nil {_cffgbe :=make ([]Result ,len (_cagf ._agff ));for _bdbg ,_aece :=range _cagf ._agff {_cffgbe [_bdbg ]=_aece .Eval (ctx ,ev );_cffgbe [_bdbg ].Ref =_aece .Reference (ctx ,ev );};return _fbeg (_cffgbe );};_fabf :=LookupFunctionComplex (_cagf ._fbae );if _fabf !=nil {_eaaf :=make ([]Result ,len (_cagf ._agff ));for _afcec ,_gdfg :=range _cagf ._agff {_eaaf [_afcec ]=_gdfg .Eval (ctx ,ev );_eaaf [_afcec ].Ref =_gdfg .Reference (ctx ,ev );};return _fabf (ctx ,ev ,_eaaf );};return MakeErrorResult ("\u0075\u006e\u006b\u006e\u006f\u0077\u006e\u0020\u0066\u0075\u006e\u0063t\u0069\u006f\u006e\u0020"+_cagf ._fbae );};
 
 // Eval evaluates a vertical range returning a list of results or an error.
 func (_ggaac VerticalRange )Eval (ctx Context ,ev Evaluator )Result {_dfff :=_ggaac .verticalRangeReference ();if _edag ,_ecddd :=ev .GetFromCache (_dfff );_ecddd {return _edag ;};_ecdf ,_gaadf :=_gfbef (ctx ,_ggaac ._efdad ,_ggaac ._ddbe );_afbaf :=_efbfc (ctx ,ev ,_ecdf ,_gaadf );ev .SetCache (_dfff ,_afbaf );return _afbaf ;};func _gfgc (_efbf ,_fcfc int )int {if _fcfc ==2&&_dfa (_efbf ){return 29;}else {return _fac [_fcfc -1];};};func _eebc (_geg int )int {if _geg < 1900{if _geg < 30{_geg +=2000;}else {_geg +=1900;};};return _geg ;};func _ffd (_ga BinOpType ,_cf ,_bf [][]Result )Result {_gd :=[][]Result {};for _ecc :=range _cf {_fga :=_fe (_ga ,_cf [_ecc ],_bf [_ecc ]);if _fga .Type ==ResultTypeError {return _fga ;};_gd =append (_gd ,_fga .ValueList );};return MakeArrayResult (_gd );};
@@ -1066,7 +1068,7 @@ func Arabic (args []Result )Result {if len (args )!=1{return MakeErrorResult ("\
 func (_fabgc VerticalRange )Reference (ctx Context ,ev Evaluator )Reference {return Reference {Type :ReferenceTypeVerticalRange ,Value :_fabgc .verticalRangeReference ()};};
 
 // Update updates references in the PrefixVerticalRange after removing a row/column.
-func (_aedcf PrefixVerticalRange )Update (q *_cc .UpdateQuery )Expression {if q .UpdateType ==_cc .UpdateActionRemoveColumn {_caaab :=_aedcf ;_cafee :=_aedcf ._edebe .String ();if _cafee ==q .SheetToUpdate {_befe :=q .ColumnIdx ;_caaab ._bgcag =_cee (_aedcf ._bgcag ,_befe );_caaab ._gadad =_cee (_aedcf ._gadad ,_befe );};return _caaab ;};return _aedcf ;};
+func (_aedcf PrefixVerticalRange )Update (q *_cc .UpdateQuery )Expression {return updatePrefixVerticalRange (_aedcf ,q );};
 
 // BinaryExpr is a binary expression.
 type BinaryExpr struct{_age ,_bd Expression ;_dd BinOpType ;};
@@ -1142,7 +1144,7 @@ type NamedRangeRef struct{_bdggc string };
 
 // Decimal is an implementation of the Excel function DECIMAL() that parses a string
 // in a given base and returns the numeric result.
-func Decimal (args []Result )Result {if len (args )!=2{return MakeErrorResult ("\u0044\u0045\u0043\u0049\u004d\u0041\u004c\u0028\u0029\u0020\u0072\u0065\u0071\u0075\u0069r\u0065s\u0020\u0074\u0077\u006f\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0073");};_dccbc :=args [0].AsString ();if _dccbc .Type !=ResultTypeString {return MakeErrorResult ("D\u0045\u0043\u0049\u004d\u0041\u004c\u0028\u0029\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020s\u0074\u0072\u0069\u006e\u0067\u0020\u0066\u0069\u0072\u0073t \u0061\u0072\u0067u\u006de\u006e\u0074");};_gacg :=args [1].AsNumber ();if _gacg .Type !=ResultTypeNumber {return MakeErrorResult ("\u0044\u0045\u0043\u0049\u004dA\u004c\u0028\u0029\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020n\u0075\u006d\u0062\u0065\u0072\u0020\u0073\u0065\u0063\u006f\u006e\u0064\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");};_cbea :=_dccbc .ValueString ;if len (_cbea )> 2&&(_ee .HasPrefix (_cbea ,"\u0030\u0078")||_ee .HasPrefix (_cbea ,"\u0030\u0058")){_cbea =_cbea [2:];};_gedg ,_aceb :=_ff .ParseInt (_cbea ,int (_gacg .ValueNumber ),64);if _aceb !=nil {return MakeErrorResult ("\u0044\u0045C\u0049\u004d\u0041\u004c\u0028\u0029\u0020\u0065\u0072\u0072\u006f\u0072\u0020\u0069\u006e\u0020\u0063\u006f\u006e\u0076\u0065\u0072si\u006f\u006e");};return MakeNumberResult (float64 (_gedg ));};func _efbfc (_bedf Context ,_fecbc Evaluator ,_gdfdf ,_ffeeg string )Result {_gfbeg ,_cbedg :=_cb .ParseCellReference (_gdfdf );if _cbedg !=nil {return MakeErrorResult (_c .Sprintf ("\u0075\u006e\u0061bl\u0065\u0020\u0074\u006f\u0020\u0070\u0061\u0072\u0073e\u0020r\u0061n\u0067e\u0020\u0025\u0073\u003a\u0020\u0065\u0072\u0072\u006f\u0072\u0020\u0025\u0073",_gdfdf ,_cbedg .Error ()));};_baabb ,_geeba :=_gfbeg .ColumnIdx ,_gfbeg .RowIdx ;_cbfdc ,_gdcd :=_cb .ParseCellReference (_ffeeg );if _gdcd !=nil {return MakeErrorResult (_c .Sprintf ("\u0075\u006e\u0061bl\u0065\u0020\u0074\u006f\u0020\u0070\u0061\u0072\u0073e\u0020r\u0061n\u0067e\u0020\u0025\u0073\u003a\u0020\u0065\u0072\u0072\u006f\u0072\u0020\u0025\u0073",_ffeeg ,_gdcd .Error ()));};_dagbc ,_efdcf :=_cbfdc .ColumnIdx ,_cbfdc .RowIdx ;_gdbcbf :=[][]Result {};for _baeba :=_geeba ;_baeba <=_efdcf ;_baeba ++{_dgbgb :=[]Result {};for _ecege :=_baabb ;_ecege <=_dagbc ;_ecege ++{_bdge :=_bedf .Cell (_c .Sprintf ("\u0025\u0073\u0025\u0064",_cb .IndexToColumn (_ecege ),_baeba ),_fecbc );_dgbgb =append (_dgbgb ,_bdge );};_gdbcbf =append (_gdbcbf ,_dgbgb );};if len (_gdbcbf )==1{if len (_gdbcbf [0])==1{return _gdbcbf [0][0];};return MakeListResult (_gdbcbf [0]);};return MakeArrayResult (_gdbcbf );};func _eef (_bff string ,_bb *_cc .UpdateQuery )string {_cd ,_gfa :=_cb .ParseCellReference (_bff );if _gfa !=nil {return "\u0023\u0052\u0045F\u0021";};if _bb .UpdateType ==_cc .UpdateActionRemoveColumn {_dcf :=_bb .ColumnIdx ;_bde :=_cd .ColumnIdx ;if _bde < _dcf {return _bff ;}else if _bde ==_dcf {return "\u0023\u0052\u0045F\u0021";}else {return _cd .Update (_cc .UpdateActionRemoveColumn ).String ();};};return _bff ;};func _fec (_dadgg ,_afca ,_aad ,_cff ,_feag float64 ,_gecc int )Result {_gccg ,_gccd :=_fea (_dadgg ,_afca ,_gecc );if _gccd .Type ==ResultTypeError {return _gccd ;};_ddga ,_afcd :=_ggca (_dadgg ,_afca ,int (_feag ),_gecc );if _afcd .Type ==ResultTypeError {return _afcd ;};_dcca :=0.0;_bfe :=0.0;_aad *=100/_feag ;_cff /=_feag ;_cff ++;_bcb :=_gccg *_feag -_ddga ;for _dfg :=1.0;_dfg < _ddga ;_dfg ++{_fgge :=_dfg +_bcb ;_becf :=_aad /_dc .Pow (_cff ,_fgge );_bfe +=_becf ;_dcca +=_fgge *_becf ;};_fadb :=(_aad +100)/_dc .Pow (_cff ,_ddga +_bcb );_bfe +=_fadb ;_dcca +=(_ddga +_bcb )*_fadb ;_dcca /=_bfe ;_dcca /=_feag ;return MakeNumberResult (_dcca );};func (_bbcc *Lexer )lex (_dcgeb _e .Reader ){_gfecad ,_bebgf ,_bgba :=0,0,0;_fgbff :=-1;_bdaac ,_dgcb ,_dbce :=0,0,0;_ =_dbce ;_fadgf :=1;_ =_fadgf ;_ddbga :=make ([]byte ,4096);_gfga :=false ;for !_gfga {_ecff :=0;if _bdaac > 0{_ecff =_bebgf -_bdaac ;};_bebgf =0;_eeggd ,_cadcd :=_dcgeb .Read (_ddbga [_ecff :]);if _eeggd ==0||_cadcd !=nil {_gfga =true ;};_bgba =_eeggd +_ecff ;if _bgba < len (_ddbga ){_fgbff =_bgba ;};{_gfecad =_dgbec ;_bdaac =0;_dgcb =0;_dbce =0;};{var _acffd int ;var _ccgd uint ;if _bebgf ==_bgba {goto _eecd ;};if _gfecad ==0{goto _cbed ;};_bbde :_acffd =int (_dadgd [_gfecad ]);_ccgd =uint (_cddd [_acffd ]);_acffd ++;for ;_ccgd > 0;_ccgd --{_acffd ++;switch _cddd [_acffd -1]{case 2:_bdaac =_bebgf ;};};switch _gfecad {case 30:switch _ddbga [_bebgf ]{case 34:goto _cagg ;case 35:goto _fabed ;case 36:goto _cfbda ;case 38:goto _dcgb ;case 39:goto _cfecb ;case 40:goto _face ;case 41:goto _gccb ;case 42:goto _dgebec ;case 43:goto _egga ;case 44:goto _ccecg ;case 45:goto _fgafd ;case 47:goto _fffd ;case 58:goto _aebf ;case 59:goto _bbfc ;case 60:goto _gbadb ;case 61:goto _fabgb ;case 62:goto _daacg ;case 63:goto _efaba ;case 70:goto _ecde ;case 84:goto _eeab ;case 92:goto _cddeb ;case 94:goto _acdg ;case 95:goto _gbfafd ;case 123:goto _fecb ;case 125:goto _fgbaf ;};switch {case _ddbga [_bebgf ]< 65:switch {case _ddbga [_bebgf ]> 37:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _cgcf ;};case _ddbga [_bebgf ]>=33:goto _efaba ;};case _ddbga [_bebgf ]> 90:switch {case _ddbga [_bebgf ]> 93:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _ccad ;};case _ddbga [_bebgf ]>=91:goto _efaba ;};default:goto _ecabd ;};goto _fede ;case 1:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _cdbg ;case 123:goto _cdbg ;case 125:goto _cdbg ;};switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _cdbg ;};case _ddbga [_bebgf ]> 45:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _cdbg ;};case _ddbga [_bebgf ]>=58:goto _cdbg ;};default:goto _cdbg ;};goto _fede ;case 0:goto _cbed ;case 2:if _ddbga [_bebgf ]==34{goto _dffa ;};goto _cagg ;case 31:if _ddbga [_bebgf ]==34{goto _cagg ;};goto _baeda ;case 3:switch _ddbga [_bebgf ]{case 78:goto _cbfc ;case 82:goto _bgcf ;};goto _efaba ;case 4:switch _ddbga [_bebgf ]{case 47:goto _aaed ;case 85:goto _efedd ;};goto _efaba ;case 5:if _ddbga [_bebgf ]==65{goto _ecaec ;};goto _efaba ;case 6:switch _ddbga [_bebgf ]{case 76:goto _bfff ;case 77:goto _fcecb ;};goto _efaba ;case 7:if _ddbga [_bebgf ]==76{goto _fcecb ;};goto _efaba ;case 8:if _ddbga [_bebgf ]==33{goto _ecaec ;};goto _efaba ;case 9:if _ddbga [_bebgf ]==69{goto _eagg ;};goto _efaba ;case 10:if _ddbga [_bebgf ]==70{goto _fged ;};goto _efaba ;case 11:if _ddbga [_bebgf ]==33{goto _cdbdd ;};goto _efaba ;case 12:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _efaba ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=45{goto _efaba ;};case _ddbga [_bebgf ]>=34:goto _efaba ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]< 65:if 58<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=63{goto _efaba ;};case _ddbga [_bebgf ]> 90:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _efaba ;};default:goto _fefaf ;};default:goto _cfcbg ;};goto _fede ;case 13:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _efaba ;case 58:goto _eagdb ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=45{goto _efaba ;};case _ddbga [_bebgf ]>=34:goto _efaba ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _efaba ;};case _ddbga [_bebgf ]>=59:goto _efaba ;};default:goto _cfcbg ;};goto _fede ;case 14:if _ddbga [_bebgf ]==36{goto _cdced ;};if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _ggace ;};goto _cdbg ;case 15:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _ggace ;};goto _cdbg ;case 32:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _ggace ;};goto _bcabb ;case 16:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 47:goto _efaba ;case 58:goto _bcbe ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 45:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _daegf ;};case _ddbga [_bebgf ]>=34:goto _efaba ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]> 90:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _efaba ;};case _ddbga [_bebgf ]>=65:goto _fefaf ;};default:goto _efaba ;};goto _fede ;case 17:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _cdbg ;case 123:goto _cdbg ;case 125:goto _cdbg ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=45{goto _cdbg ;};case _ddbga [_bebgf ]>=34:goto _cdbg ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _cdbg ;};case _ddbga [_bebgf ]>=58:goto _cdbg ;};default:goto _daegf ;};goto _fede ;case 33:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _agfcg ;case 123:goto _agfcg ;case 125:goto _agfcg ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=45{goto _agfcg ;};case _ddbga [_bebgf ]>=34:goto _agfcg ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _agfcg ;};case _ddbga [_bebgf ]>=58:goto _agfcg ;};default:goto _daegf ;};goto _fede ;case 18:if _ddbga [_bebgf ]==36{goto _gaadg ;};if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _bdcb ;};goto _cdbg ;case 19:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _bdcb ;};goto _cdbg ;case 34:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _bdcb ;};goto _gcfe ;case 20:switch _ddbga [_bebgf ]{case 39:goto _efaba ;case 42:goto _efaba ;case 47:goto _efaba ;case 58:goto _efaba ;case 63:goto _efaba ;};if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=93{goto _efaba ;};goto _ceaf ;case 21:switch _ddbga [_bebgf ]{case 39:goto _bdggd ;case 42:goto _efaba ;case 47:goto _efaba ;case 58:goto _efaba ;case 63:goto _efaba ;};if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=93{goto _efaba ;};goto _ceaf ;case 22:if _ddbga [_bebgf ]==33{goto _gabe ;};goto _efaba ;case 35:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _baea ;case 58:goto _eagdb ;case 101:goto _daae ;case 123:goto _efeb ;case 125:goto _efeb ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=47{goto _efeb ;};case _ddbga [_bebgf ]>=34:goto _efeb ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _efeb ;};case _ddbga [_bebgf ]>=59:goto _efeb ;};default:goto _cgcf ;};goto _fede ;case 36:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _efeb ;case 101:goto _daae ;case 123:goto _efeb ;case 125:goto _efeb ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=45{goto _efeb ;};case _ddbga [_bebgf ]>=34:goto _efeb ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _efeb ;};case _ddbga [_bebgf ]>=58:goto _efeb ;};default:goto _baea ;};goto _fede ;case 23:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _ccfd ;case 123:goto _ccfd ;case 125:goto _ccfd ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=45{goto _ccfd ;};case _ddbga [_bebgf ]>=34:goto _ccfd ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _ccfd ;};case _ddbga [_bebgf ]>=58:goto _ccfd ;};default:goto _cdbfe ;};goto _fede ;case 37:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _efeb ;case 123:goto _efeb ;case 125:goto _efeb ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=45{goto _efeb ;};case _ddbga [_bebgf ]>=34:goto _efeb ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _efeb ;};case _ddbga [_bebgf ]>=58:goto _efeb ;};default:goto _cdbfe ;};goto _fede ;case 38:switch _ddbga [_bebgf ]{case 61:goto _bagfc ;case 62:goto _edcgd ;};goto _bdeaa ;case 39:if _ddbga [_bebgf ]==61{goto _egbgb ;};goto _gcgea ;case 24:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _efaba ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _efaba ;};default:goto _efaba ;};goto _fede ;case 40:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 40:goto _bbef ;case 46:goto _eggg ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _eggg ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _eggg ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 41:switch _ddbga [_bebgf ]{case 46:goto _fege ;case 92:goto _fege ;case 95:goto _fege ;};switch {case _ddbga [_bebgf ]< 65:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fege ;};case _ddbga [_bebgf ]> 90:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fege ;};default:goto _fege ;};goto _ggfe ;case 42:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 43:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 40:goto _bbef ;case 46:goto _eggg ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _agfcg ;case 125:goto _agfcg ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _agfcg ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};default:goto _agfcg ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _eggg ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _agfcg ;};default:goto _agfcg ;};goto _fede ;case 44:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _cdbg ;case 125:goto _cdbg ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _cdbg ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _cdbg ;};default:goto _cdbg ;};goto _fede ;case 25:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 65:goto _dfgga ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _efaba ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 66<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _efaba ;};default:goto _efaba ;};goto _fede ;case 45:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 76:goto _ddad ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 46:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 83:goto _agabe ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 47:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 69:goto _dcgfb ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 26:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 79:goto _gbab ;case 82:goto _bdfgg ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _efaba ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _efaba ;};default:goto _efaba ;};goto _fede ;case 48:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 68:goto _bcfd ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 49:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 79:goto _adffb ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 50:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 85:goto _agabe ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 27:switch _ddbga [_bebgf ]{case 46:goto _fege ;case 92:goto _fege ;case 95:goto _fege ;};switch {case _ddbga [_bebgf ]< 65:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fege ;};case _ddbga [_bebgf ]> 90:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fege ;};default:goto _fege ;};goto _efaba ;case 28:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _fgfc ;case 120:goto _daabef ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _efaba ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _efaba ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _efaba ;};default:goto _efaba ;};goto _fede ;case 51:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _fgfc ;case 108:goto _dbbfd ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 52:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _fgfc ;case 102:goto _aecfg ;case 110:goto _aedfg ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 53:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _fgfc ;case 110:goto _gccaa ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 54:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _feeea ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 55:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _dadfb ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dadfb ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 56:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 40:goto _ddgbf ;case 46:goto _dadfb ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _dadfb ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dadfb ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 57:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _fgfc ;case 109:goto _gbbca ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 58:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _ebecb ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 59:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _cccac ;case 123:goto _cdbg ;case 125:goto _cdbg ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _cdbg ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _cdbg ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _cccac ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _cdbg ;};default:goto _cdbg ;};goto _fede ;case 29:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _efaba ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _efaba ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _efaba ;};default:goto _efaba ;};goto _fede ;};_efaba :_gfecad =0;goto _ceded ;_fede :_gfecad =1;goto _ceded ;_cagg :_gfecad =2;goto _ceded ;_fabed :_gfecad =3;goto _ceded ;_cbfc :_gfecad =4;goto _ceded ;_aaed :_gfecad =5;goto _ceded ;_efedd :_gfecad =6;goto _ceded ;_bfff :_gfecad =7;goto _ceded ;_fcecb :_gfecad =8;goto _ceded ;_bgcf :_gfecad =9;goto _ceded ;_eagg :_gfecad =10;goto _ceded ;_fged :_gfecad =11;goto _ceded ;_cfbda :_gfecad =12;goto _ceded ;_cfcbg :_gfecad =13;goto _ceded ;_eagdb :_gfecad =14;goto _ceded ;_cdced :_gfecad =15;goto _ceded ;_fefaf :_gfecad =16;goto _ceded ;_fggg :_gfecad =17;goto _ceded ;_bcbe :_gfecad =18;goto _ceded ;_gaadg :_gfecad =19;goto _ceded ;_cfecb :_gfecad =20;goto _ceded ;_ceaf :_gfecad =21;goto _ceded ;_bdggd :_gfecad =22;goto _ceded ;_daae :_gfecad =23;goto _ceded ;_ecabd :_gfecad =24;goto _ceded ;_ecde :_gfecad =25;goto _ceded ;_eeab :_gfecad =26;goto _ceded ;_cddeb :_gfecad =27;goto _ceded ;_gbfafd :_gfecad =28;goto _ceded ;_ccad :_gfecad =29;goto _ceded ;_cdbg :_gfecad =30;goto _bcag ;_dgcgg :_gfecad =30;goto _ecad ;_ecaec :_gfecad =30;goto _baaac ;_cdbdd :_gfecad =30;goto _caeede ;_gabe :_gfecad =30;goto _geddb ;_ccfd :_gfecad =30;goto _eaega ;_bbef :_gfecad =30;goto _gdbcb ;_dcgb :_gfecad =30;goto _bfeba ;_face :_gfecad =30;goto _gccgd ;_gccb :_gfecad =30;goto _cfbab ;_dgebec :_gfecad =30;goto _acbda ;_egga :_gfecad =30;goto _bafd ;_ccecg :_gfecad =30;goto _fddc ;_fgafd :_gfecad =30;goto _bfgb ;_fffd :_gfecad =30;goto _defea ;_aebf :_gfecad =30;goto _eddbc ;_bbfc :_gfecad =30;goto _cccgf ;_fabgb :_gfecad =30;goto _bdecg ;_acdg :_gfecad =30;goto _cbgdc ;_fecb :_gfecad =30;goto _fefe ;_fgbaf :_gfecad =30;goto _adee ;_baeda :_gfecad =30;goto _ffcg ;_bcabb :_gfecad =30;goto _ggcdf ;_agfcg :_gfecad =30;goto _ggbg ;_gcfe :_gfecad =30;goto _dadab ;_efeb :_gfecad =30;goto _fbab ;_bdeaa :_gfecad =30;goto _dbbab ;_bagfc :_gfecad =30;goto _gdbec ;_edcgd :_gfecad =30;goto _fcgec ;_gcgea :_gfecad =30;goto _dccc ;_egbgb :_gfecad =30;goto _fefgg ;_ggfe :_gfecad =30;goto _egegc ;_ddgbf :_gfecad =30;goto _ebdf ;_dffa :_gfecad =31;goto _dcaad ;_ggace :_gfecad =32;goto _ceded ;_daegf :_gfecad =33;goto _ggea ;_bdcb :_gfecad =34;goto _ceded ;_cgcf :_gfecad =35;goto _gfdd ;_baea :_gfecad =36;goto _gfdd ;_cdbfe :_gfecad =37;goto _gfdd ;_gbadb :_gfecad =38;goto _ceded ;_daacg :_gfecad =39;goto _ceded ;_eggg :_gfecad =40;goto _edfd ;_fege :_gfecad =41;goto _ceded ;_fgfc :_gfecad =42;goto _edfd ;_gbcga :_gfecad =43;goto _ggea ;_dddfa :_gfecad =44;goto _edfd ;_dcgfb :_gfecad =44;goto _edab ;_adffb :_gfecad =44;goto _fffca ;_dfgga :_gfecad =45;goto _edfd ;_ddad :_gfecad =46;goto _edfd ;_agabe :_gfecad =47;goto _edfd ;_gbab :_gfecad =48;goto _edfd ;_bcfd :_gfecad =49;goto _edfd ;_bdfgg :_gfecad =50;goto _edfd ;_daabef :_gfecad =51;goto _edfd ;_dbbfd :_gfecad =52;goto _edfd ;_aecfg :_gfecad =53;goto _edfd ;_gccaa :_gfecad =54;goto _edfd ;_feeea :_gfecad =55;goto _edfd ;_dadfb :_gfecad =56;goto _edfd ;_aedfg :_gfecad =57;goto _edfd ;_gbbca :_gfecad =58;goto _edfd ;_ebecb :_gfecad =59;goto _edfd ;_cccac :_gfecad =59;goto _cfga ;_baaac :_acffd =3;goto _gcgdf ;_caeede :_acffd =5;goto _gcgdf ;_ecad :_acffd =7;goto _gcgdf ;_geddb :_acffd =9;goto _gcgdf ;_gdbcb :_acffd =11;goto _gcgdf ;_ebdf :_acffd =13;goto _gcgdf ;_bfeba :_acffd =15;goto _gcgdf ;_fefe :_acffd =17;goto _gcgdf ;_adee :_acffd =19;goto _gcgdf ;_gccgd :_acffd =21;goto _gcgdf ;_cfbab :_acffd =23;goto _gcgdf ;_bafd :_acffd =25;goto _gcgdf ;_bfgb :_acffd =27;goto _gcgdf ;_acbda :_acffd =29;goto _gcgdf ;_defea :_acffd =31;goto _gcgdf ;_cbgdc :_acffd =33;goto _gcgdf ;_bdecg :_acffd =35;goto _gcgdf ;_gdbec :_acffd =37;goto _gcgdf ;_fefgg :_acffd =39;goto _gcgdf ;_fcgec :_acffd =41;goto _gcgdf ;_eddbc :_acffd =43;goto _gcgdf ;_cccgf :_acffd =45;goto _gcgdf ;_fddc :_acffd =47;goto _gcgdf ;_fbab :_acffd =49;goto _gcgdf ;_ggbg :_acffd =51;goto _gcgdf ;_ggcdf :_acffd =53;goto _gcgdf ;_dadab :_acffd =55;goto _gcgdf ;_egegc :_acffd =57;goto _gcgdf ;_ffcg :_acffd =59;goto _gcgdf ;_dbbab :_acffd =61;goto _gcgdf ;_dccc :_acffd =63;goto _gcgdf ;_eaega :_acffd =65;goto _gcgdf ;_bcag :_acffd =67;goto _gcgdf ;_edab :_acffd =72;goto _gcgdf ;_gfdd :_acffd =75;goto _gcgdf ;_ggea :_acffd =78;goto _gcgdf ;_fffca :_acffd =81;goto _gcgdf ;_cfga :_acffd =84;goto _gcgdf ;_edfd :_acffd =87;goto _gcgdf ;_dcaad :_acffd =90;goto _gcgdf ;_gcgdf :_ccgd =uint (_cddd [_acffd ]);_acffd ++;for ;_ccgd > 0;_ccgd --{_acffd ++;switch _cddd [_acffd -1]{case 3:_dgcb =_bebgf +1;case 4:_dbce =1;case 5:_dbce =2;case 6:_dbce =3;case 7:_dbce =4;case 8:_dbce =11;case 9:_dbce =14;case 10:_dbce =15;case 11:_dgcb =_bebgf +1;{_bbcc .emit (_egebg ,_ddbga [_bdaac :_dgcb ]);};case 12:_dgcb =_bebgf +1;{_bbcc .emit (_gaafa ,_ddbga [_bdaac :_dgcb ]);};case 13:_dgcb =_bebgf +1;{_bbcc .emit (_deecg ,_ddbga [_bdaac :_dgcb -1]);};case 14:_dgcb =_bebgf +1;{_bbcc .emit (_deecg ,_ddbga [_bdaac +1:_dgcb -2]);};case 15:_dgcb =_bebgf +1;{_bbcc .emit (_bggb ,_ddbga [_bdaac :_dgcb -1]);};case 16:_dgcb =_bebgf +1;{_bbcc .emit (_bggb ,_ddbga [_bdaac :_dgcb -1]);};case 17:_dgcb =_bebgf +1;{_bbcc .emit (_bacdbf ,_ddbga [_bdaac :_dgcb ]);};case 18:_dgcb =_bebgf +1;{_bbcc .emit (_bcgd ,_ddbga [_bdaac :_dgcb ]);};case 19:_dgcb =_bebgf +1;{_bbcc .emit (_efdc ,_ddbga [_bdaac :_dgcb ]);};case 20:_dgcb =_bebgf +1;{_bbcc .emit (_bgfd ,_ddbga [_bdaac :_dgcb ]);};case 21:_dgcb =_bebgf +1;{_bbcc .emit (_faggb ,_ddbga [_bdaac :_dgcb ]);};case 22:_dgcb =_bebgf +1;{_bbcc .emit (_cecbb ,_ddbga [_bdaac :_dgcb ]);};case 23:_dgcb =_bebgf +1;{_bbcc .emit (_fgbdf ,_ddbga [_bdaac :_dgcb ]);};case 24:_dgcb =_bebgf +1;{_bbcc .emit (_bfaa ,_ddbga [_bdaac :_dgcb ]);};case 25:_dgcb =_bebgf +1;{_bbcc .emit (_aedd ,_ddbga [_bdaac :_dgcb ]);};case 26:_dgcb =_bebgf +1;{_bbcc .emit (_gfdec ,_ddbga [_bdaac :_dgcb ]);};case 27:_dgcb =_bebgf +1;{_bbcc .emit (_gfdb ,_ddbga [_bdaac :_dgcb ]);};case 28:_dgcb =_bebgf +1;{_bbcc .emit (_gggbg ,_ddbga [_bdaac :_dgcb ]);};case 29:_dgcb =_bebgf +1;{_bbcc .emit (_gebb ,_ddbga [_bdaac :_dgcb ]);};case 30:_dgcb =_bebgf +1;{_bbcc .emit (_dfedd ,_ddbga [_bdaac :_dgcb ]);};case 31:_dgcb =_bebgf +1;{_bbcc .emit (_fbg ,_ddbga [_bdaac :_dgcb ]);};case 32:_dgcb =_bebgf +1;{_bbcc .emit (_cfcb ,_ddbga [_bdaac :_dgcb ]);};case 33:_dgcb =_bebgf +1;{_bbcc .emit (_cfaagc ,_ddbga [_bdaac :_dgcb ]);};case 34:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_gefbd ,_ddbga [_bdaac :_dgcb ]);};case 35:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_gdccc ,_ddbga [_bdaac :_dgcb ]);};case 36:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_deaca ,_ddbga [_bdaac :_dgcb ]);};case 37:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_gfcc ,_ddbga [_bdaac :_dgcb ]);};case 38:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_cdded ,_ddbga [_bdaac :_dgcb ]);};case 39:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_gefcf ,_ddbga [_bdaac +1:_dgcb -1]);};case 40:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_acaag ,_ddbga [_bdaac :_dgcb ]);};case 41:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_cdaba ,_ddbga [_bdaac :_dgcb ]);};case 42:_bebgf =(_dgcb )-1;{_bbcc .emit (_gefbd ,_ddbga [_bdaac :_dgcb ]);};case 43:switch _dbce {case 0:{_gfecad =0;goto _ceded ;};case 1:{_bebgf =(_dgcb )-1;_bbcc .emit (_afcdb ,_ddbga [_bdaac :_dgcb ]);};case 2:{_bebgf =(_dgcb )-1;_bbcc .emit (_gefbd ,_ddbga [_bdaac :_dgcb ]);};case 3:{_bebgf =(_dgcb )-1;_bbcc .emit (_gdccc ,_ddbga [_bdaac :_dgcb ]);};case 4:{_bebgf =(_dgcb )-1;_bbcc .emit (_gdbgc ,_ddbga [_bdaac :_dgcb ]);};case 11:{_bebgf =(_dgcb )-1;_bbcc .emit (_gbaf ,_ddbga [_bdaac :_dgcb ]);};case 14:{_bebgf =(_dgcb )-1;_bbcc .emit (_cdded ,_ddbga [_bdaac :_dgcb ]);};case 15:{_bebgf =(_dgcb )-1;_bbcc .emit (_gefcf ,_ddbga [_bdaac +1:_dgcb -1]);};};};};goto _ceded ;_ceded :_acffd =int (_egbcg [_gfecad ]);_ccgd =uint (_cddd [_acffd ]);_acffd ++;for ;_ccgd > 0;_ccgd --{_acffd ++;switch _cddd [_acffd -1]{case 0:_bdaac =0;case 1:_dbce =0;};};if _gfecad ==0{goto _cbed ;};if _bebgf ++;_bebgf !=_bgba {goto _bbde ;};_eecd :{};if _bebgf ==_fgbff {switch _gfecad {case 1:goto _cdbg ;case 2:goto _cdbg ;case 31:goto _baeda ;case 14:goto _cdbg ;case 15:goto _cdbg ;case 32:goto _bcabb ;case 17:goto _cdbg ;case 33:goto _agfcg ;case 18:goto _cdbg ;case 19:goto _cdbg ;case 34:goto _gcfe ;case 35:goto _efeb ;case 36:goto _efeb ;case 23:goto _ccfd ;case 37:goto _efeb ;case 38:goto _bdeaa ;case 39:goto _gcgea ;case 40:goto _ggfe ;case 41:goto _ggfe ;case 42:goto _ggfe ;case 43:goto _agfcg ;case 44:goto _cdbg ;case 45:goto _ggfe ;case 46:goto _ggfe ;case 47:goto _ggfe ;case 48:goto _ggfe ;case 49:goto _ggfe ;case 50:goto _ggfe ;case 51:goto _ggfe ;case 52:goto _ggfe ;case 53:goto _ggfe ;case 54:goto _ggfe ;case 55:goto _ggfe ;case 56:goto _ggfe ;case 57:goto _ggfe ;case 58:goto _ggfe ;case 59:goto _cdbg ;};};_cbed :{};};if _bdaac > 0{copy (_ddbga [0:],_ddbga [_bdaac :]);};};_ =_fgbff ;if _gfecad ==_acabf {_bbcc .emit (_bcab ,nil );};close (_bbcc ._daeg );};
+func Decimal (args []Result )Result {if len (args )!=2{return MakeErrorResult ("\u0044\u0045\u0043\u0049\u004d\u0041\u004c\u0028\u0029\u0020\u0072\u0065\u0071\u0075\u0069r\u0065s\u0020\u0074\u0077\u006f\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0073");};_dccbc :=args [0].AsString ();if _dccbc .Type !=ResultTypeString {return MakeErrorResult ("D\u0045\u0043\u0049\u004d\u0041\u004c\u0028\u0029\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020s\u0074\u0072\u0069\u006e\u0067\u0020\u0066\u0069\u0072\u0073t \u0061\u0072\u0067u\u006de\u006e\u0074");};_gacg :=args [1].AsNumber ();if _gacg .Type !=ResultTypeNumber {return MakeErrorResult ("\u0044\u0045\u0043\u0049\u004dA\u004c\u0028\u0029\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020n\u0075\u006d\u0062\u0065\u0072\u0020\u0073\u0065\u0063\u006f\u006e\u0064\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");};_cbea :=_dccbc .ValueString ;if len (_cbea )> 2&&(_ee .HasPrefix (_cbea ,"\u0030\u0078")||_ee .HasPrefix (_cbea ,"\u0030\u0058")){_cbea =_cbea [2:];};_gedg ,_aceb :=_ff .ParseInt (_cbea ,int (_gacg .ValueNumber ),64);if _aceb !=nil {return MakeErrorResult ("\u0044\u0045C\u0049\u004d\u0041\u004c\u0028\u0029\u0020\u0065\u0072\u0072\u006f\u0072\u0020\u0069\u006e\u0020\u0063\u006f\u006e\u0076\u0065\u0072si\u006f\u006e");};return MakeNumberResult (float64 (_gedg ));};func _efbfc (_bedf Context ,_fecbc Evaluator ,_gdfdf ,_ffeeg string )Result {_gfbeg ,_cbedg :=_cb .ParseCellReference (_gdfdf );if _cbedg !=nil {return MakeErrorResult (_c .Sprintf ("\u0075\u006e\u0061bl\u0065\u0020\u0074\u006f\u0020\u0070\u0061\u0072\u0073e\u0020r\u0061n\u0067e\u0020\u0025\u0073\u003a\u0020\u0065\u0072\u0072\u006f\u0072\u0020\u0025\u0073",_gdfdf ,_cbedg .Error ()));};_baabb ,_geeba :=_gfbeg .ColumnIdx ,_gfbeg .RowIdx ;_cbfdc ,_gdcd :=_cb .ParseCellReference (_ffeeg );if _gdcd !=nil {return MakeErrorResult (_c .Sprintf ("\u0075\u006e\u0061bl\u0065\u0020\u0074\u006f\u0020\u0070\u0061\u0072\u0073e\u0020r\u0061n\u0067e\u0020\u0025\u0073\u003a\u0020\u0065\u0072\u0072\u006f\u0072\u0020\u0025\u0073",_ffeeg ,_gdcd .Error ()));};_dagbc ,_efdcf :=_cbfdc .ColumnIdx ,_cbfdc .RowIdx ;_gdbcbf :=[][]Result {};for _baeba :=_geeba ;_baeba <=_efdcf ;_baeba ++{_dgbgb :=[]Result {};for _ecege :=_baabb ;_ecege <=_dagbc ;_ecege ++{_bdge :=_bedf .Cell (_c .Sprintf ("\u0025\u0073\u0025\u0064",_cb .IndexToColumn (_ecege ),_baeba ),_fecbc );_dgbgb =append (_dgbgb ,_bdge );};_gdbcbf =append (_gdbcbf ,_dgbgb );};if len (_gdbcbf )==1{if len (_gdbcbf [0])==1{return _gdbcbf [0][0];};return MakeListResult (_gdbcbf [0]);};return MakeArrayResult (_gdbcbf );};func _eef (_bff string ,_bb *_cc .UpdateQuery )string {return updateCellRef (_bff ,_bb );};func _fec (_dadgg ,_afca ,_aad ,_cff ,_feag float64 ,_gecc int )Result {_gccg ,_gccd :=_fea (_dadgg ,_afca ,_gecc );if _gccd .Type ==ResultTypeError {return _gccd ;};_ddga ,_afcd :=_ggca (_dadgg ,_afca ,int (_feag ),_gecc );if _afcd .Type ==ResultTypeError {return _afcd ;};_dcca :=0.0;_bfe :=0.0;_aad *=100/_feag ;_cff /=_feag ;_cff ++;_bcb :=_gccg *_feag -_ddga ;for _dfg :=1.0;_dfg < _ddga ;_dfg ++{_fgge :=_dfg +_bcb ;_becf :=_aad /_dc .Pow (_cff ,_fgge );_bfe +=_becf ;_dcca +=_fgge *_becf ;};_fadb :=(_aad +100)/_dc .Pow (_cff ,_ddga +_bcb );_bfe +=_fadb ;_dcca +=(_ddga +_bcb )*_fadb ;_dcca /=_bfe ;_dcca /=_feag ;return MakeNumberResult (_dcca );};func (_bbcc *Lexer )lex (_dcgeb _e .Reader ){_gfecad ,_bebgf ,_bgba :=0,0,0;_fgbff :=-1;_bdaac ,_dgcb ,_dbce :=0,0,0;_ =_dbce ;_fadgf :=1;_ =_fadgf ;_ddbga :=make ([]byte ,4096);_gfga :=false ;for !_gfga {_ecff :=0;if _bdaac > 0{_ecff =_bebgf -_bdaac ;};_bebgf =0;_eeggd ,_cadcd :=_dcgeb .Read (_ddbga [_ecff :]);if _eeggd ==0||_cadcd !=nil {_gfga =true ;};_bgba =_eeggd +_ecff ;if _bgba < len (_ddbga ){_fgbff =_bgba ;};{_gfecad =_dgbec ;_bdaac =0;_dgcb =0;_dbce =0;};{var _acffd int ;var _ccgd uint ;if _bebgf ==_bgba {goto _eecd ;};if _gfecad ==0{goto _cbed ;};_bbde :_acffd =int (_dadgd [_gfecad ]);_ccgd =uint (_cddd [_acffd ]);_acffd ++;for ;_ccgd > 0;_ccgd --{_acffd ++;switch _cddd [_acffd -1]{case 2:_bdaac =_bebgf ;};};switch _gfecad {case 30:switch _ddbga [_bebgf ]{case 34:goto _cagg ;case 35:goto _fabed ;case 36:goto _cfbda ;case 38:goto _dcgb ;case 39:goto _cfecb ;case 40:goto _face ;case 41:goto _gccb ;case 42:goto _dgebec ;case 43:goto _egga ;case 44:goto _ccecg ;case 45:goto _fgafd ;case 47:goto _fffd ;case 58:goto _aebf ;case 59:goto _bbfc ;case 60:goto _gbadb ;case 61:goto _fabgb ;case 62:goto _daacg ;case 63:goto _efaba ;case 70:goto _ecde ;case 84:goto _eeab ;case 92:goto _cddeb ;case 94:goto _acdg ;case 95:goto _gbfafd ;case 123:goto _fecb ;case 125:goto _fgbaf ;};switch {case _ddbga [_bebgf ]< 65:switch {case _ddbga [_bebgf ]> 37:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _cgcf ;};case _ddbga [_bebgf ]>=33:goto _efaba ;};case _ddbga [_bebgf ]> 90:switch {case _ddbga [_bebgf ]> 93:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _ccad ;};case _ddbga [_bebgf ]>=91:goto _efaba ;};default:goto _ecabd ;};goto _fede ;case 1:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _cdbg ;case 123:goto _cdbg ;case 125:goto _cdbg ;};switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _cdbg ;};case _ddbga [_bebgf ]> 45:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _cdbg ;};case _ddbga [_bebgf ]>=58:goto _cdbg ;};default:goto _cdbg ;};goto _fede ;case 0:goto _cbed ;case 2:if _ddbga [_bebgf ]==34{goto _dffa ;};goto _cagg ;case 31:if _ddbga [_bebgf ]==34{goto _cagg ;};goto _baeda ;case 3:switch _ddbga [_bebgf ]{case 78:goto _cbfc ;case 82:goto _bgcf ;};goto _efaba ;case 4:switch _ddbga [_bebgf ]{case 47:goto _aaed ;case 85:goto _efedd ;};goto _efaba ;case 5:if _ddbga [_bebgf ]==65{goto _ecaec ;};goto _efaba ;case 6:switch _ddbga [_bebgf ]{case 76:goto _bfff ;case 77:goto _fcecb ;};goto _efaba ;case 7:if _ddbga [_bebgf ]==76{goto _fcecb ;};goto _efaba ;case 8:if _ddbga [_bebgf ]==33{goto _ecaec ;};goto _efaba ;case 9:if _ddbga [_bebgf ]==69{goto _eagg ;};goto _efaba ;case 10:if _ddbga [_bebgf ]==70{goto _fged ;};goto _efaba ;case 11:if _ddbga [_bebgf ]==33{goto _cdbdd ;};goto _efaba ;case 12:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _efaba ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=45{goto _efaba ;};case _ddbga [_bebgf ]>=34:goto _efaba ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]< 65:if 58<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=63{goto _efaba ;};case _ddbga [_bebgf ]> 90:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _efaba ;};default:goto _fefaf ;};default:goto _cfcbg ;};goto _fede ;case 13:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _efaba ;case 58:goto _eagdb ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=45{goto _efaba ;};case _ddbga [_bebgf ]>=34:goto _efaba ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _efaba ;};case _ddbga [_bebgf ]>=59:goto _efaba ;};default:goto _cfcbg ;};goto _fede ;case 14:if _ddbga [_bebgf ]==36{goto _cdced ;};if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _ggace ;};goto _cdbg ;case 15:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _ggace ;};goto _cdbg ;case 32:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _ggace ;};goto _bcabb ;case 16:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 47:goto _efaba ;case 58:goto _bcbe ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 45:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _daegf ;};case _ddbga [_bebgf ]>=34:goto _efaba ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]> 90:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _efaba ;};case _ddbga [_bebgf ]>=65:goto _fefaf ;};default:goto _efaba ;};goto _fede ;case 17:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _cdbg ;case 123:goto _cdbg ;case 125:goto _cdbg ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=45{goto _cdbg ;};case _ddbga [_bebgf ]>=34:goto _cdbg ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _cdbg ;};case _ddbga [_bebgf ]>=58:goto _cdbg ;};default:goto _daegf ;};goto _fede ;case 33:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _agfcg ;case 123:goto _agfcg ;case 125:goto _agfcg ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=45{goto _agfcg ;};case _ddbga [_bebgf ]>=34:goto _agfcg ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _agfcg ;};case _ddbga [_bebgf ]>=58:goto _agfcg ;};default:goto _daegf ;};goto _fede ;case 18:if _ddbga [_bebgf ]==36{goto _gaadg ;};if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _bdcb ;};goto _cdbg ;case 19:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _bdcb ;};goto _cdbg ;case 34:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _bdcb ;};goto _gcfe ;case 20:switch _ddbga [_bebgf ]{case 39:goto _efaba ;case 42:goto _efaba ;case 47:goto _efaba ;case 58:goto _efaba ;case 63:goto _efaba ;};if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=93{goto _efaba ;};goto _ceaf ;case 21:switch _ddbga [_bebgf ]{case 39:goto _bdggd ;case 42:goto _efaba ;case 47:goto _efaba ;case 58:goto _efaba ;case 63:goto _efaba ;};if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=93{goto _efaba ;};goto _ceaf ;case 22:if _ddbga [_bebgf ]==33{goto _gabe ;};goto _efaba ;case 35:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _baea ;case 58:goto _eagdb ;case 101:goto _daae ;case 123:goto _efeb ;case 125:goto _efeb ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=47{goto _efeb ;};case _ddbga [_bebgf ]>=34:goto _efeb ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _efeb ;};case _ddbga [_bebgf ]>=59:goto _efeb ;};default:goto _cgcf ;};goto _fede ;case 36:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _efeb ;case 101:goto _daae ;case 123:goto _efeb ;case 125:goto _efeb ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=45{goto _efeb ;};case _ddbga [_bebgf ]>=34:goto _efeb ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _efeb ;};case _ddbga [_bebgf ]>=58:goto _efeb ;};default:goto _baea ;};goto _fede ;case 23:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _ccfd ;case 123:goto _ccfd ;case 125:goto _ccfd ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=45{goto _ccfd ;};case _ddbga [_bebgf ]>=34:goto _ccfd ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _ccfd ;};case _ddbga [_bebgf ]>=58:goto _ccfd ;};default:goto _cdbfe ;};goto _fede ;case 37:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 47:goto _efeb ;case 123:goto _efeb ;case 125:goto _efeb ;};switch {case _ddbga [_bebgf ]< 48:switch {case _ddbga [_bebgf ]> 35:if 37<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=45{goto _efeb ;};case _ddbga [_bebgf ]>=34:goto _efeb ;};case _ddbga [_bebgf ]> 57:switch {case _ddbga [_bebgf ]> 63:if 91<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=94{goto _efeb ;};case _ddbga [_bebgf ]>=58:goto _efeb ;};default:goto _cdbfe ;};goto _fede ;case 38:switch _ddbga [_bebgf ]{case 61:goto _bagfc ;case 62:goto _edcgd ;};goto _bdeaa ;case 39:if _ddbga [_bebgf ]==61{goto _egbgb ;};goto _gcgea ;case 24:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _efaba ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _efaba ;};default:goto _efaba ;};goto _fede ;case 40:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 40:goto _bbef ;case 46:goto _eggg ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _eggg ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _eggg ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 41:switch _ddbga [_bebgf ]{case 46:goto _fege ;case 92:goto _fege ;case 95:goto _fege ;};switch {case _ddbga [_bebgf ]< 65:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fege ;};case _ddbga [_bebgf ]> 90:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fege ;};default:goto _fege ;};goto _ggfe ;case 42:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 43:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 40:goto _bbef ;case 46:goto _eggg ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _agfcg ;case 125:goto _agfcg ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _agfcg ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};default:goto _agfcg ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _eggg ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _agfcg ;};default:goto _agfcg ;};goto _fede ;case 44:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _cdbg ;case 125:goto _cdbg ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _cdbg ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _cdbg ;};default:goto _cdbg ;};goto _fede ;case 25:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 65:goto _dfgga ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _efaba ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 66<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _efaba ;};default:goto _efaba ;};goto _fede ;case 45:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 76:goto _ddad ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 46:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 83:goto _agabe ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 47:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 69:goto _dcgfb ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 26:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 79:goto _gbab ;case 82:goto _bdfgg ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _efaba ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _efaba ;};default:goto _efaba ;};goto _fede ;case 48:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 68:goto _bcfd ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 49:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 79:goto _adffb ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 50:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 36:goto _fggg ;case 40:goto _bbef ;case 46:goto _eggg ;case 58:goto _bcbe ;case 85:goto _agabe ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 59:switch {case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _gbcga ;};case _ddbga [_bebgf ]>=34:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dddfa ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 27:switch _ddbga [_bebgf ]{case 46:goto _fege ;case 92:goto _fege ;case 95:goto _fege ;};switch {case _ddbga [_bebgf ]< 65:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fege ;};case _ddbga [_bebgf ]> 90:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fege ;};default:goto _fege ;};goto _efaba ;case 28:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _fgfc ;case 120:goto _daabef ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _efaba ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _efaba ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _efaba ;};default:goto _efaba ;};goto _fede ;case 51:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _fgfc ;case 108:goto _dbbfd ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 52:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _fgfc ;case 102:goto _aecfg ;case 110:goto _aedfg ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 53:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _fgfc ;case 110:goto _gccaa ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 54:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _feeea ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 55:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _dadfb ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dadfb ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 56:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 40:goto _ddgbf ;case 46:goto _dadfb ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _dadfb ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _dadfb ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 57:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _fgfc ;case 109:goto _gbbca ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 58:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _ebecb ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _ggfe ;case 125:goto _ggfe ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _ggfe ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _ggfe ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _ggfe ;};default:goto _ggfe ;};goto _fede ;case 59:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _cccac ;case 123:goto _cdbg ;case 125:goto _cdbg ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _cdbg ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _cdbg ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _cccac ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _cdbg ;};default:goto _cdbg ;};goto _fede ;case 29:switch _ddbga [_bebgf ]{case 33:goto _dgcgg ;case 46:goto _fgfc ;case 92:goto _fege ;case 95:goto _fgfc ;case 123:goto _efaba ;case 125:goto _efaba ;};switch {case _ddbga [_bebgf ]< 58:switch {case _ddbga [_bebgf ]< 37:if 34<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=35{goto _efaba ;};case _ddbga [_bebgf ]> 47:if 48<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=57{goto _fgfc ;};default:goto _efaba ;};case _ddbga [_bebgf ]> 63:switch {case _ddbga [_bebgf ]< 91:if 65<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=90{goto _fgfc ;};case _ddbga [_bebgf ]> 94:if 97<=_ddbga [_bebgf ]&&_ddbga [_bebgf ]<=122{goto _fgfc ;};default:goto _efaba ;};default:goto _efaba ;};goto _fede ;};_efaba :_gfecad =0;goto _ceded ;_fede :_gfecad =1;goto _ceded ;_cagg :_gfecad =2;goto _ceded ;_fabed :_gfecad =3;goto _ceded ;_cbfc :_gfecad =4;goto _ceded ;_aaed :_gfecad =5;goto _ceded ;_efedd :_gfecad =6;goto _ceded ;_bfff :_gfecad =7;goto _ceded ;_fcecb :_gfecad =8;goto _ceded ;_bgcf :_gfecad =9;goto _ceded ;_eagg :_gfecad =10;goto _ceded ;_fged :_gfecad =11;goto _ceded ;_cfbda :_gfecad =12;goto _ceded ;_cfcbg :_gfecad =13;goto _ceded ;_eagdb :_gfecad =14;goto _ceded ;_cdced :_gfecad =15;goto _ceded ;_fefaf :_gfecad =16;goto _ceded ;_fggg :_gfecad =17;goto _ceded ;_bcbe :_gfecad =18;goto _ceded ;_gaadg :_gfecad =19;goto _ceded ;_cfecb :_gfecad =20;goto _ceded ;_ceaf :_gfecad =21;goto _ceded ;_bdggd :_gfecad =22;goto _ceded ;_daae :_gfecad =23;goto _ceded ;_ecabd :_gfecad =24;goto _ceded ;_ecde :_gfecad =25;goto _ceded ;_eeab :_gfecad =26;goto _ceded ;_cddeb :_gfecad =27;goto _ceded ;_gbfafd :_gfecad =28;goto _ceded ;_ccad :_gfecad =29;goto _ceded ;_cdbg :_gfecad =30;goto _bcag ;_dgcgg :_gfecad =30;goto _ecad ;_ecaec :_gfecad =30;goto _baaac ;_cdbdd :_gfecad =30;goto _caeede ;_gabe :_gfecad =30;goto _geddb ;_ccfd :_gfecad =30;goto _eaega ;_bbef :_gfecad =30;goto _gdbcb ;_dcgb :_gfecad =30;goto _bfeba ;_face :_gfecad =30;goto _gccgd ;_gccb :_gfecad =30;goto _cfbab ;_dgebec :_gfecad =30;goto _acbda ;_egga :_gfecad =30;goto _bafd ;_ccecg :_gfecad =30;goto _fddc ;_fgafd :_gfecad =30;goto _bfgb ;_fffd :_gfecad =30;goto _defea ;_aebf :_gfecad =30;goto _eddbc ;_bbfc :_gfecad =30;goto _cccgf ;_fabgb :_gfecad =30;goto _bdecg ;_acdg :_gfecad =30;goto _cbgdc ;_fecb :_gfecad =30;goto _fefe ;_fgbaf :_gfecad =30;goto _adee ;_baeda :_gfecad =30;goto _ffcg ;_bcabb :_gfecad =30;goto _ggcdf ;_agfcg :_gfecad =30;goto _ggbg ;_gcfe :_gfecad =30;goto _dadab ;_efeb :_gfecad =30;goto _fbab ;_bdeaa :_gfecad =30;goto _dbbab ;_bagfc :_gfecad =30;goto _gdbec ;_edcgd :_gfecad =30;goto _fcgec ;_gcgea :_gfecad =30;goto _dccc ;_egbgb :_gfecad =30;goto _fefgg ;_ggfe :_gfecad =30;goto _egegc ;_ddgbf :_gfecad =30;goto _ebdf ;_dffa :_gfecad =31;goto _dcaad ;_ggace :_gfecad =32;goto _ceded ;_daegf :_gfecad =33;goto _ggea ;_bdcb :_gfecad =34;goto _ceded ;_cgcf :_gfecad =35;goto _gfdd ;_baea :_gfecad =36;goto _gfdd ;_cdbfe :_gfecad =37;goto _gfdd ;_gbadb :_gfecad =38;goto _ceded ;_daacg :_gfecad =39;goto _ceded ;_eggg :_gfecad =40;goto _edfd ;_fege :_gfecad =41;goto _ceded ;_fgfc :_gfecad =42;goto _edfd ;_gbcga :_gfecad =43;goto _ggea ;_dddfa :_gfecad =44;goto _edfd ;_dcgfb :_gfecad =44;goto _edab ;_adffb :_gfecad =44;goto _fffca ;_dfgga :_gfecad =45;goto _edfd ;_ddad :_gfecad =46;goto _edfd ;_agabe :_gfecad =47;goto _edfd ;_gbab :_gfecad =48;goto _edfd ;_bcfd :_gfecad =49;goto _edfd ;_bdfgg :_gfecad =50;goto _edfd ;_daabef :_gfecad =51;goto _edfd ;_dbbfd :_gfecad =52;goto _edfd ;_aecfg :_gfecad =53;goto _edfd ;_gccaa :_gfecad =54;goto _edfd ;_feeea :_gfecad =55;goto _edfd ;_dadfb :_gfecad =56;goto _edfd ;_aedfg :_gfecad =57;goto _edfd ;_gbbca :_gfecad =58;goto _edfd ;_ebecb :_gfecad =59;goto _edfd ;_cccac :_gfecad =59;goto _cfga ;_baaac :_acffd =3;goto _gcgdf ;_caeede :_acffd =5;goto _gcgdf ;_ecad :_acffd =7;goto _gcgdf ;_geddb :_acffd =9;goto _gcgdf ;_gdbcb :_acffd =11;goto _gcgdf ;_ebdf :_acffd =13;goto _gcgdf ;_bfeba :_acffd =15;goto _gcgdf ;_fefe :_acffd =17;goto _gcgdf ;_adee :_acffd =19;goto _gcgdf ;_gccgd :_acffd =21;goto _gcgdf ;_cfbab :_acffd =23;goto _gcgdf ;_bafd :_acffd =25;goto _gcgdf ;_bfgb :_acffd =27;goto _gcgdf ;_acbda :_acffd =29;goto _gcgdf ;_defea :_acffd =31;goto _gcgdf ;_cbgdc :_acffd =33;goto _gcgdf ;_bdecg :_acffd =35;goto _gcgdf ;_gdbec :_acffd =37;goto _gcgdf ;_fefgg :_acffd =39;goto _gcgdf ;_fcgec :_acffd =41;goto _gcgdf ;_eddbc :_acffd =43;goto _gcgdf ;_cccgf :_acffd =45;goto _gcgdf ;_fddc :_acffd =47;goto _gcgdf ;_fbab :_acffd =49;goto _gcgdf ;_ggbg :_acffd =51;goto _gcgdf ;_ggcdf :_acffd =53;goto _gcgdf ;_dadab :_acffd =55;goto _gcgdf ;_egegc :_acffd =57;goto _gcgdf ;_ffcg :_acffd =59;goto _gcgdf ;_dbbab :_acffd =61;goto _gcgdf ;_dccc :_acffd =63;goto _gcgdf ;_eaega :_acffd =65;goto _gcgdf ;_bcag :_acffd =67;goto _gcgdf ;_edab :_acffd =72;goto _gcgdf ;_gfdd :_acffd =75;goto _gcgdf ;_ggea :_acffd =78;goto _gcgdf ;_fffca :_acffd =81;goto _gcgdf ;_cfga :_acffd =84;goto _gcgdf ;_edfd :_acffd =87;goto _gcgdf ;_dcaad :_acffd =90;goto _gcgdf ;_gcgdf :_ccgd =uint (_cddd [_acffd ]);_acffd ++;for ;_ccgd > 0;_ccgd --{_acffd ++;switch _cddd [_acffd -1]{case 3:_dgcb =_bebgf +1;case 4:_dbce =1;case 5:_dbce =2;case 6:_dbce =3;case 7:_dbce =4;case 8:_dbce =11;case 9:_dbce =14;case 10:_dbce =15;case 11:_dgcb =_bebgf +1;{_bbcc .emit (_egebg ,_ddbga [_bdaac :_dgcb ]);};case 12:_dgcb =_bebgf +1;{_bbcc .emit (_gaafa ,_ddbga [_bdaac :_dgcb ]);};case 13:_dgcb =_bebgf +1;{_bbcc .emit (_deecg ,_ddbga [_bdaac :_dgcb -1]);};case 14:_dgcb =_bebgf +1;{_bbcc .emit (_deecg ,_ddbga [_bdaac +1:_dgcb -2]);};case 15:_dgcb =_bebgf +1;{_bbcc .emit (_bggb ,_ddbga [_bdaac :_dgcb -1]);};case 16:_dgcb =_bebgf +1;{_bbcc .emit (_bggb ,_ddbga [_bdaac :_dgcb -1]);};case 17:_dgcb =_bebgf +1;{_bbcc .emit (_bacdbf ,_ddbga [_bdaac :_dgcb ]);};case 18:_dgcb =_bebgf +1;{_bbcc .emit (_bcgd ,_ddbga [_bdaac :_dgcb ]);};case 19:_dgcb =_bebgf +1;{_bbcc .emit (_efdc ,_ddbga [_bdaac :_dgcb ]);};case 20:_dgcb =_bebgf +1;{_bbcc .emit (_bgfd ,_ddbga [_bdaac :_dgcb ]);};case 21:_dgcb =_bebgf +1;{_bbcc .emit (_faggb ,_ddbga [_bdaac :_dgcb ]);};case 22:_dgcb =_bebgf +1;{_bbcc .emit (_cecbb ,_ddbga [_bdaac :_dgcb ]);};case 23:_dgcb =_bebgf +1;{_bbcc .emit (_fgbdf ,_ddbga [_bdaac :_dgcb ]);};case 24:_dgcb =_bebgf +1;{_bbcc .emit (_bfaa ,_ddbga [_bdaac :_dgcb ]);};case 25:_dgcb =_bebgf +1;{_bbcc .emit (_aedd ,_ddbga [_bdaac :_dgcb ]);};case 26:_dgcb =_bebgf +1;{_bbcc .emit (_gfdec ,_ddbga [_bdaac :_dgcb ]);};case 27:_dgcb =_bebgf +1;{_bbcc .emit (_gfdb ,_ddbga [_bdaac :_dgcb ]);};case 28:_dgcb =_bebgf +1;{_bbcc .emit (_gggbg ,_ddbga [_bdaac :_dgcb ]);};case 29:_dgcb =_bebgf +1;{_bbcc .emit (_gebb ,_ddbga [_bdaac :_dgcb ]);};case 30:_dgcb =_bebgf +1;{_bbcc .emit (_dfedd ,_ddbga [_bdaac :_dgcb ]);};case 31:_dgcb =_bebgf +1;{_bbcc .emit (_fbg ,_ddbga [_bdaac :_dgcb ]);};case 32:_dgcb =_bebgf +1;{_bbcc .emit (_cfcb ,_ddbga [_bdaac :_dgcb ]);};case 33:_dgcb =_bebgf +1;{_bbcc .emit (_cfaagc ,_ddbga [_bdaac :_dgcb ]);};case 34:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_gefbd ,_ddbga [_bdaac :_dgcb ]);};case 35:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_gdccc ,_ddbga [_bdaac :_dgcb ]);};case 36:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_deaca ,_ddbga [_bdaac :_dgcb ]);};case 37:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_gfcc ,_ddbga [_bdaac :_dgcb ]);};case 38:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_cdded ,_ddbga [_bdaac :_dgcb ]);};case 39:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_gefcf ,_ddbga [_bdaac +1:_dgcb -1]);};case 40:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_acaag ,_ddbga [_bdaac :_dgcb ]);};case 41:_dgcb =_bebgf ;_bebgf --;{_bbcc .emit (_cdaba ,_ddbga [_bdaac :_dgcb ]);};case 42:_bebgf =(_dgcb )-1;{_bbcc .emit (_gefbd ,_ddbga [_bdaac :_dgcb ]);};case 43:switch _dbce {case 0:{_gfecad =0;goto _ceded ;};case 1:{_bebgf =(_dgcb )-1;_bbcc .emit (_afcdb ,_ddbga [_bdaac :_dgcb ]);};case 2:{_bebgf =(_dgcb )-1;_bbcc .emit (_gefbd ,_ddbga [_bdaac :_dgcb ]);};case 3:{_bebgf =(_dgcb )-1;_bbcc .emit (_gdccc ,_ddbga [_bdaac :_dgcb ]);};case 4:{_bebgf =(_dgcb )-1;_bbcc .emit (_gdbgc ,_ddbga [_bdaac :_dgcb ]);};case 11:{_bebgf =(_dgcb )-1;_bbcc .emit (_gbaf ,_ddbga [_bdaac :_dgcb ]);};case 14:{_bebgf =(_dgcb )-1;_bbcc .emit (_cdded ,_ddbga [_bdaac :_dgcb ]);};case 15:{_bebgf =(_dgcb )-1;_bbcc .emit (_gefcf ,_ddbga [_bdaac +1:_dgcb -1]);};};};};goto _ceded ;_ceded :_acffd =int (_egbcg [_gfecad ]);_ccgd =uint (_cddd [_acffd ]);_acffd ++;for ;_ccgd > 0;_ccgd --{_acffd ++;switch _cddd [_acffd -1]{case 0:_bdaac =0;case 1:_dbce =0;};};if _gfecad ==0{goto _cbed ;};if _bebgf ++;_bebgf !=_bgba {goto _bbde ;};_eecd :{};if _bebgf ==_fgbff {switch _gfecad {case 1:goto _cdbg ;case 2:goto _cdbg ;case 31:goto _baeda ;case 14:goto _cdbg ;case 15:goto _cdbg ;case 32:goto _bcabb ;case 17:goto _cdbg ;case 33:goto _agfcg ;case 18:goto _cdbg ;case 19:goto _cdbg ;case 34:goto _gcfe ;case 35:goto _efeb ;case 36:goto _efeb ;case 23:goto _ccfd ;case 37:goto _efeb ;case 38:goto _bdeaa ;case 39:goto _gcgea ;case 40:goto _ggfe ;case 41:goto _ggfe ;case 42:goto _ggfe ;case 43:goto _agfcg ;case 44:goto _cdbg ;case 45:goto _ggfe ;case 46:goto _ggfe ;case 47:goto _ggfe ;case 48:goto _ggfe ;case 49:goto _ggfe ;case 50:goto _ggfe ;case 51:goto _ggfe ;case 52:goto _ggfe ;case 53:goto _ggfe ;case 54:goto _ggfe ;case 55:goto _ggfe ;case 56:goto _ggfe ;case 57:goto _ggfe ;case 58:goto _ggfe ;case 59:goto _cdbg ;};};_cbed :{};};if _bdaac > 0{copy (_ddbga [0:],_ddbga [_bdaac :]);};};_ =_fgbff ;if _gfecad ==_acabf {_bbcc .emit (_bcab ,nil );};close (_bbcc ._daeg );};
 
 // Eval evaluates and returns the result of a sheet expression.
 func (_agbbf SheetPrefixExpr )Eval (ctx Context ,ev Evaluator )Result {return MakeErrorResult ("\u0073\u0068\u0065\u0065\u0074\u0020\u0070\u0072\u0065\u0066\u0069\u0078\u0020\u0073\u0068\u006f\u0075\u006c\u0064\u0020\u006e\u0065\u0076\u0065r\u0020\u0062\u0065\u0020\u0065v\u0061\u006cu\u0061\u0074\u0065\u0064");};
@@ -1157,7 +1159,7 @@ func (_acecd HorizontalRange )String ()string {return _acecd .horizontalRangeRef
 type CellRef struct{_bda string };
 
 // Update updates the horizontal range references after removing a row/column.
-func (_adcd HorizontalRange )Update (q *_cc .UpdateQuery )Expression {return _adcd };var _ddddbe =[...]struct{_fafgc int ;_bgfbg int ;_dbagf string ;}{};
+func (_adcd HorizontalRange )Update (q *_cc .UpdateQuery )Expression {return updateHorizontalRange (_adcd ,q )};var _ddddbe =[...]struct{_fafgc int ;_bgfbg int ;_dbagf string ;}{};
 
 // Coupdaysnc implements the Excel COUPDAYSNC function.
 func Coupdaysnc (args []Result )Result {_deb ,_bdfg :=_dccda (args ,"\u0043\u004f\u0055\u0050\u0044\u0041\u0059\u0053\u004e\u0043");if _bdfg .Type ==ResultTypeError {return _bdfg ;};return MakeNumberResult (_agg (_deb ._ggbd ,_deb ._eedd ,_deb ._ecf ,_deb ._deagf ));};func _ggca (_abag ,_bee float64 ,_bfca ,_gbade int )(float64 ,Result ){_acdb ,_baae :=_fae (_abag ),_fae (_bee );if _baae .After (_acdb ){_ffbb :=_dfcf (_acdb ,_baae ,_bfca ,_gbade );_gdaa :=(_baae .Year ()-_ffbb .Year ())*12+int (_baae .Month ())-int (_ffbb .Month ());return float64 (_gdaa *_bfca )/12.0,_ffe ;};return 0,MakeErrorResultType (ErrorTypeNum ,"\u0053\u0065t\u0074\u006c\u0065\u006d\u0065\u006e\u0074\u0020\u0064\u0061\u0074\u0065\u0020\u0073\u0068\u006f\u0075\u006c\u0064\u0020\u0062\u0065\u0020\u0062\u0065\u0066\u006f\u0072\u0065\u0020\u006d\u0061\u0074\u0075\u0072\u0069\u0074\u0079\u0020\u0064\u0061\u0074\u0065");};func (_eccd *evCache )GetFromCache (key string )(Result ,bool ){_eccd ._deag .Lock ();_ac ,_fdg :=_eccd ._cda [key ];_eccd ._deag .Unlock ();return _ac ,_fdg ;};
@@ -1188,7 +1190,7 @@ func Fvschedule (args []Result )Result {if len (args )!=2{return MakeErrorResult
 func CeilingMath (args []Result )Result {if len (args )==0{return MakeErrorResult ("\u0043E\u0049\u004cI\u004e\u0047\u002eM\u0041\u0054\u0048\u0028\u0029\u0020\u0072e\u0071\u0075\u0069\u0072\u0065\u0073 \u0061\u0074\u0020\u006c\u0065\u0061\u0073\u0074\u0020\u006f\u006ee\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074");};if len (args )> 3{return MakeErrorResult ("\u0043E\u0049\u004cI\u004e\u0047\u002eM\u0041\u0054\u0048\u0028\u0029\u0020\u0061l\u006c\u006f\u0077\u0073\u0020\u0061t\u0020\u006d\u006f\u0073\u0074\u0020\u0074\u0068\u0072\u0065\u0065 \u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0073");};_ecge :=args [0].AsNumber ();if _ecge .Type !=ResultTypeNumber {return MakeErrorResult ("\u0066\u0069\u0072\u0073\u0074\u0020a\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u0074\u006f\u0020\u0043\u0045\u0049\u004c\u0049\u004e\u0047\u002e\u004dA\u0054\u0048\u0028\u0029\u0020\u006d\u0075\u0073\u0074\u0020\u0062\u0065\u0020\u0061 \u006eu\u006d\u0062\u0065\u0072");};_ceeb :=float64 (1);if _ecge .ValueNumber < 0{_ceeb =-1;};if len (args )> 1{_bdgcc :=args [1].AsNumber ();if _bdgcc .Type !=ResultTypeNumber {return MakeErrorResult ("\u0073\u0065\u0063\u006f\u006e\u0064\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u0074\u006f \u0043\u0045\u0049\u004c\u0049\u004e\u0047.\u004d\u0041\u0054\u0048\u0028\u0029\u0020\u006d\u0075\u0073\u0074 \u0062\u0065\u0020\u0061\u0020\u006e\u0075\u006d\u0062\u0065\u0072");};_ceeb =_bdgcc .ValueNumber ;};_dfbge :=float64 (1);if len (args )> 2{_bfdga :=args [2].AsNumber ();if _bfdga .Type !=ResultTypeNumber {return MakeErrorResult ("\u0074\u0068\u0069\u0072\u0064\u0020a\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u0074\u006f\u0020\u0043\u0045\u0049\u004c\u0049\u004e\u0047\u002e\u004dA\u0054\u0048\u0028\u0029\u0020\u006d\u0075\u0073\u0074\u0020\u0062\u0065\u0020\u0061 \u006eu\u006d\u0062\u0065\u0072");};_dfbge =_bfdga .ValueNumber ;};if len (args )==1{return MakeNumberResult (_dc .Ceil (_ecge .ValueNumber ));};_fgag :=_ecge .ValueNumber ;_fgag ,_abddf :=_dc .Modf (_fgag /_ceeb );if _abddf !=0{if _ecge .ValueNumber > 0{_fgag ++;}else if _dfbge < 0{_fgag --;};};return MakeNumberResult (_fgag *_ceeb );};
 
 // Eval evaluates and returns the result of a formula.
-func (_ddc *defEval )Eval (ctx Context ,formula string )Result {_egg :=ParseString (formula );if _egg !=nil {_ddc .checkLastEvalIsRef (ctx ,_egg );_ced :=_egg .Eval (ctx ,_ddc );return _ced ;};return MakeErrorResult (_c .Sprintf ("\u0075\u006e\u0061\u0062\u006c\u0065\u0020\u0074\u006f\u0020\u0070a\u0072\u0073\u0065\u0020\u0066\u006f\u0072\u006d\u0075\u006ca\u0020\u0025\u0073",formula ));};func _ebcfc (_cbdbg []Result ,_dadf rmode )Result {if len (_cbdbg )!=2{return MakeErrorResult ("\u0052\u004f\u0055\u004e\u0044\u0028\u0029\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0074\u0077\u006f\u0020\u006e\u0075\u006de\u0072\u0069\u0063\u0020\u0061r\u0067\u0075m\u0065\u006e\u0074\u0073");};_abgae :=_cbdbg [0].AsNumber ();if _abgae .Type !=ResultTypeNumber {return MakeErrorResult ("\u0066\u0069\u0072s\u0074\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u0074\u006f\u0020\u0052\u004f\u0055\u004e\u0044\u0028\u0029\u0020\u006d\u0075\u0073\u0074\u0020\u0062\u0065 \u0061\u0020\u006e\u0075\u006d\u0062\u0065\u0072");};_ecga :=_cbdbg [1].AsNumber ();if _ecga .Type !=ResultTypeNumber {return MakeErrorResult ("\u0073\u0065\u0063\u006f\u006e\u0064\u0020a\u0072\u0067\u0075m\u0065\u006e\u0074\u0020t\u006f\u0020\u0052\u004f\u0055\u004e\u0044\u0028\u0029\u0020\u006d\u0075\u0073\u0074\u0020\u0062\u0065\u0020\u0061\u0020\u006e\u0075\u006d\u0062\u0065\u0072");};_bgad :=_ecga .ValueNumber ;_ddgc :=_abgae .ValueNumber ;_beae :=1.0;if _bgad > 0{_beae =_dc .Pow (1/10.0,_bgad );}else {_beae =_dc .Pow (10.0,-_bgad );};_ddgc ,_fcbb :=_dc .Modf (_ddgc /_beae );switch _dadf {case _aabe :const _gead =0.499999999;if _fcbb >=_gead {_ddgc ++;}else if _fcbb <=-_gead {_ddgc --;};case _eecgb :case _agcbg :if _fcbb > 0{_ddgc ++;}else if _fcbb < 0{_ddgc --;};};return MakeNumberResult (_ddgc *_beae );};func _bccga (_fgee float64 )bool {return _fgee ==1||_fgee ==2||_fgee ==4};func (_baedb HorizontalRange )horizontalRangeReference ()string {return _c .Sprintf ("\u0025\u0064\u003a%\u0064",_baedb ._ddgg ,_baedb ._aabf );};const _cfaagc =57376;
+func (_ddc *defEval )Eval (ctx Context ,formula string )Result {_egg :=ParseString (formula );if _egg !=nil {_ddc .checkLastEvalIsRef (ctx ,_egg );_ced :=_egg .Eval (ctx ,_ddc );return _ced ;};return MakeErrorResult (_c .Sprintf ("\u0075\u006e\u0061\u0062\u006c\u0065\u0020\u0074\u006f\u0020\u0070a\u0072\u0073\u0065\u0020\u0066\u006f\u0072\u006d\u0075\u006ca\u0020\u0025\u0073",formula ));};func _ebcfc (_cbdbg []Result ,_dadf rmode )Result {if len (_cbdbg )!=2{return MakeErrorResult ("\u0052\u004f\u0055\u004e\u0044\u0028\u0029\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0074\u0077\u006f\u0020\u006e\u0075\u006de\u0072\u0069\u0063\u0020\u0061r\u0067\u0075m\u0065\u006e\u0074\u0073");};_abgae :=_cbdbg [0].AsNumber ();if _abgae .Type !=ResultTypeNumber {return MakeErrorResult ("\u0066\u0069\u0072s\u0074\u0020\u0061\u0072\u0067\u0075\u006d\u0065\u006e\u0074\u0020\u0074\u006f\u0020\u0052\u004f\u0055\u004e\u0044\u0028\u0029\u0020\u006d\u0075\u0073\u0074\u0020\u0062\u0065 \u0061\u0020\u006e\u0075\u006d\u0062\u0065\u0072");};_ecga :=_cbdbg [1].AsNumber ();if _ecga .Type !=ResultTypeNumber {return MakeErrorResult ("\u0073\u0065\u0063\u006f\u006e\u0064\u0020a\u0072\u0067\u0075m\u0065\u006e\u0074\u0020t\u006f\u0020\u0052\u004f\u0055\u004e\u0044\u0028\u0029\u0020\u006d\u0075\u0073\u0074\u0020\u0062\u0065\u0020\u0061\u0020\u006e\u0075\u006d\u0062\u0065\u0072");};_bgad :=_ecga .ValueNumber ;_ddgc :=_abgae .ValueNumber ;_beae :=1.0;if _bgad > 0{_beae =_dc .Pow (1/10.0,_bgad );}else {_beae =_dc .Pow (10.0,-_bgad );};_ddgc ,_fcbb :=_dc .Modf (_ddgc /_beae );switch _dadf {case _aabe :const _gead =0.499999999;if _fcbb >=_gead {_ddgc ++;}else if _fcbb <=-_gead {_ddgc --;};case _eecgb :case _agcbg :if _fcbb > 0{_ddgc ++;}else if _fcbb < 0{_ddgc --;};};return MakeNumberResult (_ddgc *_beae );};func _bccga (_fgee float64 )bool {return _fgee ==1||_fgee ==2||_fgee ==4};func (_baedb HorizontalRange )horizontalRangeReference ()string {return rowRangeText (_baedb ._ddgg ,_baedb ._aabf ,_baedb .absFrom ,_baedb .absTo )};const _cfaagc =57376;
 
 // GetEpoch returns a null time object for the invalid reference context.
 func (_baefd *ivr )GetEpoch ()_ce .Time {return _ce .Time {}};
\ No newline at end of file
@@ -0,0 +1,407 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"strconv"
+	"strings"
+)
+
+// specialForm is a function that receives its arguments unevaluated.
+type specialForm func(ctx Context, ev Evaluator, args []Expression) Result
+
+var specialForms = map[string]specialForm{}
+
+func init() {
+	specialForms["LET"] = Let
+	specialForms["_XLFN.LET"] = Let
+	specialForms["LAMBDA"] = lambdaForm
+	specialForms["_XLFN.LAMBDA"] = lambdaForm
+}
+
+// lambda is a function defined with LAMBDA.  Its body is evaluated in the
+// context the function was defined in.
+type lambda struct {
+	params []string
+	body   Expression
+	ctx    Context
+}
+
+// letContext is a Context with names bound by LET or by calling a lambda.
+type letContext struct {
+	Context
+	values  map[string]Result
+	lambdas map[string]*lambda
+}
+
+// bindName normalizes a name bound by LET or LAMBDA, returning false if the
+// expression isn't a name.  Excel stores the names with a '_xlpm.' prefix.
+func bindName(e Expression) (string, bool) {
+	n, ok := e.(NamedRangeRef)
+	if !ok {
+		return "", false
+	}
+	return strings.ToLower(strings.TrimPrefix(n._bdggc, "_xlpm.")), true
+}
+
+// boundValue returns the value of a name bound in the context.
+func boundValue(ctx Context, name string) (Result, bool) {
+	lc, ok := ctx.(*letContext)
+	if !ok {
+		return Result{}, false
+	}
+	name = strings.ToLower(strings.TrimPrefix(name, "_xlpm."))
+	if v, ok := lc.values[name]; ok {
+		return v, true
+	}
+	if _, ok := lc.lambdas[name]; ok {
+		return MakeErrorResultType(ErrorTypeCalc, "lambda "+name+" must be called"), true
+	}
+	return Result{}, false
+}
+
+// bind returns a new context with a name bound to a value or lambda.
+func bind(ctx Context, name string, v Result, l *lambda) *letContext {
+	lc := &letContext{Context: ctx, values: map[string]Result{}, lambdas: map[string]*lambda{}}
+	if p, ok := ctx.(*letContext); ok {
+		lc.Context = p.Context
+		for k, pv := range p.values {
+			lc.values[k] = pv
+		}
+		for k, pl := range p.lambdas {
+			lc.lambdas[k] = pl
+		}
+	}
+	delete(lc.values, name)
+	delete(lc.lambdas, name)
+	if l != nil {
+		lc.lambdas[name] = l
+	} else {
+		lc.values[name] = v
+	}
+	return lc
+}
+
+// isLambda returns true if an expression defines a lambda.
+func isLambda(e Expression) bool {
+	fc, ok := e.(FunctionCall)
+	if !ok {
+		return false
+	}
+	n := strings.ToUpper(fc._fbae)
+	return n == "LAMBDA" || n == "_XLFN.LAMBDA"
+}
+
+// newLambda creates a lambda from the arguments of a LAMBDA call.
+func newLambda(ctx Context, args []Expression) (*lambda, Result) {
+	if len(args) < 1 {
+		return nil, MakeErrorResult("LAMBDA requires at least one argument")
+	}
+	l := &lambda{body: args[len(args)-1], ctx: ctx}
+	for _, a := range args[:len(args)-1] {
+		name, ok := bindName(a)
+		if !ok {
+			return nil, MakeErrorResult("LAMBDA requires parameters to be names")
+		}
+		l.params = append(l.params, name)
+	}
+	return l, MakeEmptyResult()
+}
+
+// call evaluates the lambda with the given arguments.
+func (l *lambda) call(ev Evaluator, args []Result) Result {
+	if len(args) != len(l.params) {
+		return MakeErrorResult("lambda requires " + strconv.Itoa(len(l.params)) + " arguments")
+	}
+	ctx := l.ctx
+	for i, p := range l.params {
+		ctx = bind(ctx, p, args[i], nil)
+	}
+	return l.body.Eval(ctx, ev)
+}
+
+// Let implements the LET function which binds names to values for use in a
+// calculation, e.g. LET(x,5,y,x*2,x+y).  The names are local to the formula.
+func Let(ctx Context, ev Evaluator, args []Expression) Result {
+	if len(args) < 3 || len(args)%2 == 0 {
+		return MakeErrorResult("LET requires an odd number of arguments, at least three")
+	}
+	for i := 0; i < len(args)-1; i += 2 {
+		name, ok := bindName(args[i])
+		if !ok {
+			return MakeErrorResult("LET requires names to bind")
+		}
+		if isLambda(args[i+1]) {
+			l, res := newLambda(ctx, args[i+1].(FunctionCall)._agff)
+			if res.Type == ResultTypeError {
+				return res
+			}
+			ctx = bind(ctx, name, Result{}, l)
+			continue
+		}
+		ctx = bind(ctx, name, args[i+1].Eval(ctx, ev), nil)
+	}
+	return args[len(args)-1].Eval(ctx, ev)
+}
+
+// lambdaForm evaluates a LAMBDA which isn't called.  Like Excel, this returns
+// a #CALC! error.
+func lambdaForm(ctx Context, ev Evaluator, args []Expression) Result {
+	if _, res := newLambda(ctx, args); res.Type == ResultTypeError {
+		return res
+	}
+	return MakeErrorResultType(ErrorTypeCalc, "LAMBDA must be called")
+}
+
+// findLambda returns the lambda a function call refers to, either bound by LET
+// or defined as a named range like 'Double' with a value of 'LAMBDA(x,x*2)'.
+func findLambda(ctx Context, name string) *lambda {
+	if lc, ok := ctx.(*letContext); ok {
+		if l, ok := lc.lambdas[strings.ToLower(strings.TrimPrefix(name, "_xlpm."))]; ok {
+			return l
+		}
+	}
+	ref := ctx.NamedRange(name)
+	if ref.Type == ReferenceTypeInvalid {
+		return nil
+	}
+	e := ParseString(ref.Value)
+	if e == nil || !isLambda(e) {
+		return nil
+	}
+	l, res := newLambda(ctx, e.(FunctionCall)._agff)
+	if res.Type == ResultTypeError {
+		return nil
+	}
+	return l
+}
+
+// evalSpecial evaluates the function calls that aren't made through the
+// function registry: LET, LAMBDA and calls to lambdas.  It returns false if
+// the call is to a regular function.
+func (f FunctionCall) evalSpecial(ctx Context, ev Evaluator) (Result, bool) {
+	if sf, ok := specialForms[strings.ToUpper(f._fbae)]; ok {
+		return sf(ctx, ev, f._agff), true
+	}
+	if LookupFunction(f._fbae) != nil || LookupFunctionComplex(f._fbae) != nil {
+		return Result{}, false
+	}
+	l := findLambda(ctx, f._fbae)
+	if l == nil {
+		return Result{}, false
+	}
+	args := make([]Result, len(f._agff))
+	for i, a := range f._agff {
+		args[i] = a.Eval(ctx, ev)
+	}
+	return l.call(ev, args), true
+}
+
+// lambdaCall is the name of the internal function that a LAMBDA called
+// directly, e.g. LAMBDA(x,x+1)(5), is parsed as.  Its first argument is the
+// LAMBDA and the others are the arguments of the call.
+const lambdaCall = "_xlcall"
+
+func init() {
+	specialForms[strings.ToUpper(lambdaCall)] = callLambda
+}
+
+// callLambda evaluates a LAMBDA that's called directly.
+func callLambda(ctx Context, ev Evaluator, args []Expression) Result {
+	if len(args) < 1 || !isLambda(args[0]) {
+		return MakeErrorResult("a LAMBDA is required to make a call")
+	}
+	l, res := newLambda(ctx, args[0].(FunctionCall)._agff)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	vals := make([]Result, len(args)-1)
+	for i, a := range args[1:] {
+		vals[i] = a.Eval(ctx, ev)
+	}
+	return l.call(ev, vals)
+}
+
+// closingParen returns the index of the parenthesis that closes the one at
+// s[open], or -1 if it isn't closed.
+func closingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\'':
+			i = skipQuoted(s, i) - 1
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitArgs splits the text of function arguments on the commas that aren't
+// nested in parentheses, strings, arrays or structured references.
+func splitArgs(s string) []string {
+	ret := []string{}
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\'':
+			i = skipQuoted(s, i) - 1
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				ret = append(ret, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(ret, s[start:])
+}
+
+// isNameByte returns true if the byte may be part of a name or a function.
+func isNameByte(b byte) bool {
+	return b == '_' || b == '.' || b == '\\' || b >= 0x80 ||
+		('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9')
+}
+
+// specialFormAt returns the name of the LET or LAMBDA call that starts at s[i]
+// and the index of its opening parenthesis, or -1 if there's none.
+func specialFormAt(s string, i int) (string, int) {
+	if i > 0 && isNameByte(s[i-1]) {
+		return "", -1
+	}
+	for _, fn := range []string{"LET", "LAMBDA", "_XLFN.LET", "_XLFN.LAMBDA"} {
+		end := i + len(fn)
+		if end < len(s) && s[end] == '(' && strings.EqualFold(s[i:end], fn) {
+			return strings.TrimPrefix(fn, "_XLFN."), end
+		}
+	}
+	return "", -1
+}
+
+// rewriteLambdaCalls replaces the calls made directly on a LAMBDA with the
+// lambdaCall function, so LAMBDA(x,x+1)(5) becomes
+// _xlcall(LAMBDA(x,x+1),5) which the parser can handle.
+func rewriteLambdaCalls(s string) string {
+	buf := strings.Builder{}
+	last := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\'' {
+			i = skipQuoted(s, i) - 1
+			continue
+		}
+		fn, open := specialFormAt(s, i)
+		if fn != "LAMBDA" {
+			continue
+		}
+		end := closingParen(s, open)
+		if end < 0 {
+			break
+		}
+		call := end + 1
+		for call < len(s) && s[call] == ' ' {
+			call++
+		}
+		if call >= len(s) || s[call] != '(' {
+			continue
+		}
+		callEnd := closingParen(s, call)
+		if callEnd < 0 {
+			break
+		}
+		buf.WriteString(s[last:i])
+		buf.WriteString(lambdaCall + "(")
+		buf.WriteString(s[i:open+1] + rewriteLambdaCalls(s[open+1:end+1]))
+		if args := rewriteLambdaCalls(s[call+1 : callEnd]); strings.TrimSpace(args) != "" {
+			buf.WriteString("," + args)
+		}
+		buf.WriteString(")")
+		last = callEnd + 1
+		i = callEnd
+	}
+	if last == 0 {
+		return s
+	}
+	buf.WriteString(s[last:])
+	return buf.String()
+}
+
+// boundNames returns the lower case names that LET and LAMBDA calls in the
+// formula bind.  The generated lexer doesn't recognize all the names Excel
+// allows to be bound, like single letters, so these are lexed separately.
+func boundNames(s string) map[string]bool {
+	ret := map[string]bool{}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\'' {
+			i = skipQuoted(s, i) - 1
+			continue
+		}
+		fn, open := specialFormAt(s, i)
+		if open < 0 {
+			continue
+		}
+		end := closingParen(s, open)
+		if end < 0 {
+			break
+		}
+		args := splitArgs(s[open+1 : end])
+		for j, a := range args[:len(args)-1] {
+			if fn == "LET" && j%2 == 1 {
+				continue
+			}
+			a = strings.TrimSpace(a)
+			name := strings.ToLower(strings.TrimPrefix(a, "_xlpm."))
+			if name == "" || strings.IndexFunc(a, func(r rune) bool { return r < 0x80 && !isNameByte(byte(r)) }) >= 0 {
+				continue
+			}
+			ret[name] = true
+		}
+	}
+	return ret
+}
+
+// splitBoundNames splits formula text into text and the occurrences of names
+// bound by LET or LAMBDA.  Odd elements of the returned slice are names.
+func splitBoundNames(s string, names map[string]bool) []string {
+	ret := []string{}
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\'':
+			i = skipQuoted(s, i) - 1
+			continue
+		case '[':
+			if end := closingParen(s, i); end >= 0 {
+				i = end
+			}
+			continue
+		}
+		if !isNameByte(s[i]) || (i > 0 && (isNameByte(s[i-1]) || strings.IndexByte("$!:]", s[i-1]) >= 0)) {
+			continue
+		}
+		end := i
+		for end < len(s) && isNameByte(s[end]) {
+			end++
+		}
+		if !names[strings.ToLower(s[i:end])] || (end < len(s) && strings.IndexByte("!:[", s[end]) >= 0) {
+			i = end - 1
+			continue
+		}
+		ret = append(ret, s[start:i], s[i:end])
+		start = end
+		i = end - 1
+	}
+	return append(ret, s[start:])
+}
@@ -0,0 +1,92 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"math"
+
+	"github.com/unidoc/unioffice/spreadsheet/format"
+)
+
+func init() {
+	RegisterFunctionComplex("TEXT", localeText)
+	RegisterFunctionComplex("VALUE", localeValue)
+	RegisterFunctionComplex("DATEVALUE", inEpoch(localeDateValue, 0, true))
+}
+
+// LocaleContext is implemented by contexts which format and parse values in a
+// locale, which is used by TEXT, VALUE and DATEVALUE.  Without it these
+// functions use the conventions of English in the United States.
+type LocaleContext interface {
+	// Locale returns the locale of the workbook.
+	Locale() *format.Locale
+}
+
+// localeContext returns the locale of a context, looking through the contexts
+// of LET and LAMBDA.
+func localeContext(ctx Context) (*format.Locale, bool) {
+	for {
+		switch c := ctx.(type) {
+		case LocaleContext:
+			l := c.Locale()
+			return l, l != nil
+		case *letContext:
+			ctx = c.Context
+		default:
+			return nil, false
+		}
+	}
+}
+
+func localeText(ctx Context, ev Evaluator, args []Result) Result {
+	l, ok := localeContext(ctx)
+	if !ok || len(args) != 2 || args[1].Type != ResultTypeString {
+		return Text(args)
+	}
+	code := args[1].ValueString
+	switch v := args[0]; v.Type {
+	case ResultTypeNumber:
+		return MakeStringResult(l.Number(v.ValueNumber, code))
+	case ResultTypeString:
+		if n, ok := l.ParseNumber(v.ValueString); ok {
+			return MakeStringResult(l.Number(n, code))
+		}
+		if d, ok := l.ParseDate(v.ValueString); ok {
+			return MakeStringResult(l.Number(d, code))
+		}
+		return MakeStringResult(format.StringResult(v.ValueString, code).Text)
+	case ResultTypeEmpty:
+		return MakeStringResult(l.Number(0, code))
+	}
+	return Text(args)
+}
+
+func localeValue(ctx Context, ev Evaluator, args []Result) Result {
+	l, ok := localeContext(ctx)
+	if !ok || len(args) != 1 || args[0].Type != ResultTypeString {
+		return Value(args)
+	}
+	if n, ok := l.ParseNumber(args[0].ValueString); ok {
+		return MakeNumberResult(n)
+	}
+	if d, ok := l.ParseDate(args[0].ValueString); ok {
+		return MakeNumberResult(d)
+	}
+	return Value(args)
+}
+
+func localeDateValue(ctx Context, ev Evaluator, args []Result) Result {
+	l, ok := localeContext(ctx)
+	if !ok || len(args) != 1 || args[0].Type != ResultTypeString {
+		return DateValue(args)
+	}
+	if d, ok := l.ParseDate(args[0].ValueString); ok {
+		return MakeNumberResult(math.Floor(d))
+	}
+	return DateValue(args)
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"testing"
+
+	"github.com/unidoc/unioffice/spreadsheet/format"
+)
+
+// localeCellContext is a cell context in a locale.
+type localeCellContext struct {
+	cellContext
+	locale *format.Locale
+}
+
+func (c localeCellContext) Locale() *format.Locale { return c.locale }
+
+// localeCells holds a number in A1, a number written in German in A2 and a
+// date and time in A3.
+func localeCells() cellContext {
+	return newCellContext(map[string][]Result{
+		"A": {MakeNumberResult(1234.5), MakeStringResult("1.234,5"), MakeNumberResult(45000.75)},
+	})
+}
+
+func TestLocaleFunctions(t *testing.T) {
+	de, _ := format.LocaleByName("de-DE")
+	testFormulas(t, localeCellContext{localeCells(), de}, []formulaCase{
+		{`TEXT(A1,"#,##0.00")`, "1.234,50"},
+		{`TEXT(A2,"0.0")`, "1234,5"},
+		{`TEXT(A3,"dddd, d. mmmm yyyy hh:mm")`, "Mittwoch, 15. März 2023 18:00"},
+		{`TEXT("15.03.2023","yyyy-mm-dd")`, "2023-03-15"},
+		{`TEXT(A9,"0.00")`, "0,00"},
+		{`TEXT("abc","""x""@")`, "xabc"},
+
+		{`VALUE("1.234,5")`, "1234.5"},
+		{`VALUE(A2)`, "1234.5"},
+		{`VALUE("12,5%")`, "0.125"},
+		{`VALUE("15.03.2023")`, "45000"},
+		{`VALUE("14:30")`, "0.6041666666666666"},
+		{`VALUE(12)`, "12"},
+		{`VALUE("1,234.5")`, "#VALUE!"},
+		{`VALUE("abc")`, "#VALUE!"},
+
+		{`DATEVALUE("15.03.2023")`, "45000"},
+		{`DATEVALUE("15.03.2023 14:30")`, "45000"},
+		{`DATEVALUE("15. März 2023")`, "45000"},
+		{`DATEVALUE("x")`, "#VALUE!"},
+
+		// the locale is found through the contexts of LET
+		{`LET(x,"1,5",VALUE(x))`, "1.5"},
+		{`LET(x,"15.03.2023",DATEVALUE(x))`, "45000"},
+	})
+}
+
+func TestLocaleFunctionsDefault(t *testing.T) {
+	// without a locale, or with a nil locale, text is read as in the US
+	for _, ctx := range []Context{localeCells(), localeCellContext{localeCells(), nil}} {
+		testFormulas(t, ctx, []formulaCase{
+			{`TEXT(A1,"#,##0.00")`, "1,234.50"},
+			{`TEXT(A3,"dddd, mmmm d")`, "Wednesday, March 15"},
+			{`VALUE("1.234,5")`, "#VALUE!"},
+			{`VALUE("12.5")`, "12.5"},
+			{`DATEVALUE("15.03.2023")`, "#VALUE!"},
+			{`DATEVALUE("3/15/2023")`, "45000"},
+		})
+	}
+}
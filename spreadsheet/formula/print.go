@@ -0,0 +1,114 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"strconv"
+	"strings"
+)
+
+// negatePrecedence is the precedence of negation.  The parser binds negation
+// less tightly than multiplication and exponentiation, so -2*3 is read as
+// -(2*3), and more tightly than addition.
+const negatePrecedence = 5
+
+// precedence returns how tightly the operator of an expression binds its
+// operands.  Expressions other than operators are never parenthesized.
+func precedence(e Expression) int {
+	switch n := e.(type) {
+	case BinaryExpr:
+		switch n._dd {
+		case BinOpTypeExp:
+			return 7
+		case BinOpTypeMult, BinOpTypeDiv:
+			return 6
+		case BinOpTypePlus, BinOpTypeMinus:
+			return 4
+		case BinOpTypeConcat:
+			return 2
+		}
+		return 0
+	case Negate:
+		return negatePrecedence
+	}
+	return 8
+}
+
+// operand returns the text of the operand of an operator with the precedence
+// prec, parenthesized if it binds less tightly.  Operators are left
+// associative so the right operand is parenthesized for the same precedence,
+// e.g. 1-(2-3), while a negation on the right needs no parentheses, e.g. 2*-3.
+func operand(e Expression, prec int, right bool) string {
+	p := precedence(e)
+	if _, ok := e.(Negate); ok && right {
+		p = prec + 1
+	}
+	if p < prec || right && p == prec {
+		return "(" + e.String() + ")"
+	}
+	return e.String()
+}
+
+// stringLiteral returns the text of a string in a formula, with its quotes
+// doubled.
+func stringLiteral(s string) string {
+	return "\"" + strings.Replace(s, "\"", "\"\"", -1) + "\""
+}
+
+// quoteSheetName returns a sheet name as written in a reference, quoted if
+// it isn't a plain word or it could be read as a reference (e.g. 'A1').
+func quoteSheetName(name string) string {
+	plain := name != "" && !(name[0] >= '0' && name[0] <= '9')
+	for i := 0; i < len(name) && plain; i++ {
+		plain = isNameByte(name[i]) && name[i] != '\\'
+	}
+	if plain {
+		if _, ok := parseRefWord(name); ok {
+			plain = false
+		} else if _, ok := parseR1C1(name, 0); ok {
+			plain = false
+		}
+	}
+	if plain {
+		return name
+	}
+	return "'" + strings.Replace(name, "'", "''", -1) + "'"
+}
+
+// constArrayString returns the text of a constant array (e.g. {1,2;3,4}).
+func constArrayString(rows [][]Expression) string {
+	buf := strings.Builder{}
+	buf.WriteByte('{')
+	for i, r := range rows {
+		if i > 0 {
+			buf.WriteByte(';')
+		}
+		for j, v := range r {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(v.String())
+		}
+	}
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+// rangeRow parses a row of a range of whole rows (e.g. $3 of $3:$5),
+// returning whether it's absolute.
+func rangeRow(s string) (int, bool) {
+	abs := strings.HasPrefix(s, "$")
+	n, _ := strconv.Atoi(strings.TrimPrefix(s, "$"))
+	return n, abs
+}
+
+// rowRangeText returns the text of a range of whole rows (e.g. $3:5).
+func rowRangeText(from, to int, absFrom, absTo bool) string {
+	r1, r2 := refWord{row: strconv.Itoa(from), absRow: absFrom}, refWord{row: strconv.Itoa(to), absRow: absTo}
+	return r1.String() + ":" + r2.String()
+}
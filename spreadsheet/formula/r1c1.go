@@ -0,0 +1,361 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+	"github.com/unidoc/unioffice/spreadsheet/update"
+)
+
+// ToR1C1 converts the references of a formula in the cell (e.g. "B2") from the
+// A1 notation to the R1C1 notation, where relative references are offsets
+// from the cell (e.g. A1 becomes R[-1]C[-1]) and absolute references are row
+// and column numbers (e.g. $A$1 becomes R1C1).  Formulas that are the same
+// when copied from one cell to another have the same R1C1 text.  The rest of
+// the formula is kept as is.
+func ToR1C1(formula, cell string) (string, error) {
+	origin, err := reference.ParseCellReference(cell)
+	if err != nil {
+		return "", err
+	}
+	return rewriteRefs(formula, func(prefix string, r1, r2 *refWord) string {
+		if r2 == nil {
+			return prefix + r1c1Word(r1, origin)
+		}
+		return prefix + r1c1Range(r1, r2, origin)
+	}), nil
+}
+
+// FromR1C1 converts the references of a formula in the cell (e.g. "B2") from
+// the R1C1 notation to the A1 notation.  References outside of the sheet
+// become #REF!, and it returns an error for invalid R1C1 references (e.g. R0C1
+// or R[).
+func FromR1C1(formula, cell string) (string, error) {
+	origin, err := reference.ParseCellReference(cell)
+	if err != nil {
+		return "", err
+	}
+	buf := strings.Builder{}
+	for i := 0; i < len(formula); {
+		c := formula[i]
+		switch {
+		case c == '"' || c == '\'':
+			j := skipQuoted(formula, i)
+			buf.WriteString(formula[i:j])
+			i = j
+		case c == '[':
+			// external workbooks
+			j := skipBrackets(formula, i)
+			buf.WriteString(formula[i:j])
+			i = j
+		case c == '#':
+			j := i + 1
+			for j < len(formula) && (isRefRune(formula[j]) || formula[j] == '/' || formula[j] == '!' || formula[j] == '?') {
+				j++
+			}
+			buf.WriteString(formula[i:j])
+			i = j
+		case isRefRune(c):
+			t1, ok := parseR1C1(formula, i)
+			if !ok && r1c1Shaped(formula, i) {
+				return "", fmt.Errorf("invalid R1C1 reference in %q", formula)
+			}
+			if !ok {
+				// names, functions and structured references
+				j := readWord(formula, i)
+				if j < len(formula) && formula[j] == '[' {
+					j = skipBrackets(formula, j)
+				}
+				buf.WriteString(formula[i:j])
+				i = j
+				continue
+			}
+			if t1.end+1 < len(formula) && formula[t1.end] == ':' {
+				if t2, ok := parseR1C1(formula, t1.end+1); ok && t1.sameShape(t2) {
+					buf.WriteString(a1Range(t1, t2, origin))
+					i = t2.end
+					continue
+				}
+			}
+			buf.WriteString(a1Range(t1, t1, origin))
+			i = t1.end
+		default:
+			buf.WriteByte(c)
+			i++
+		}
+	}
+	return buf.String(), nil
+}
+
+// ParseR1C1 parses a formula of the cell (e.g. "B2") in the R1C1 notation,
+// returning an error if it's invalid.
+func ParseR1C1(formula, cell string) (Expression, error) {
+	a1, err := FromR1C1(formula, cell)
+	if err != nil {
+		return nil, err
+	}
+	e := ParseString(a1)
+	if e == nil {
+		return nil, fmt.Errorf("invalid formula %q", formula)
+	}
+	return e, nil
+}
+
+// R1C1String returns the text of an expression of the cell (e.g. "B2") in the
+// R1C1 notation.
+func R1C1String(e Expression, cell string) (string, error) {
+	if e == nil {
+		return "", errors.New("no expression to write")
+	}
+	origin, err := reference.ParseCellReference(cell)
+	if err != nil {
+		return "", err
+	}
+	return Rewrite(e, func(n Expression) Expression {
+		return r1c1Expr(n, origin)
+	}).String(), nil
+}
+
+// r1c1Expr returns the references of an expression rewritten in the R1C1
+// notation relative to origin.  Other expressions are returned as they are,
+// with their children already rewritten.
+func r1c1Expr(e Expression, origin reference.CellReference) Expression {
+	switch n := e.(type) {
+	case CellRef:
+		if r, ok := parseRefWord(n._bda); ok && validRefWord(r) {
+			return r1c1Text(r1c1Word(&r, origin))
+		}
+	case VerticalRange:
+		return r1c1Text(r1c1Columns(n._efdad, n._ddbe, origin))
+	case PrefixVerticalRange:
+		return r1c1Text(n._edebe.String() + "!" + r1c1Columns(n._bgcag, n._gadad, origin))
+	case HorizontalRange:
+		return r1c1Text(r1c1Rows(n._ddgg, n._aabf, n.absFrom, n.absTo, origin))
+	case PrefixHorizontalRange:
+		return r1c1Text(n._eegae.String() + "!" + r1c1Rows(n._gfad, n._cfaf, n.absFrom, n.absTo, origin))
+	}
+	return e
+}
+
+// r1c1Columns returns a range of whole columns (e.g. A:C) in the R1C1
+// notation.
+func r1c1Columns(from, to string, origin reference.CellReference) string {
+	r1, _ := parseRefWord(from)
+	r2, _ := parseRefWord(to)
+	return r1c1Range(&r1, &r2, origin)
+}
+
+// r1c1Rows returns a range of whole rows (e.g. 1:3) in the R1C1 notation.
+func r1c1Rows(from, to int, absFrom, absTo bool, origin reference.CellReference) string {
+	r1 := refWord{row: strconv.Itoa(from), absRow: absFrom}
+	r2 := refWord{row: strconv.Itoa(to), absRow: absTo}
+	return r1c1Range(&r1, &r2, origin)
+}
+
+// r1c1Range returns a range in the R1C1 notation relative to origin.  Ranges
+// of a single whole column or row are written as that column or row (e.g. A:A
+// becomes C1) like Excel does.
+func r1c1Range(r1, r2 *refWord, origin reference.CellReference) string {
+	from, to := r1c1Word(r1, origin), r1c1Word(r2, origin)
+	if from == to && (r1.row == "" || r1.col == "") {
+		return from
+	}
+	return from + ":" + to
+}
+
+// r1c1Text is a reference written in the R1C1 notation, which is only used
+// for its text.
+type r1c1Text string
+
+// Eval returns a #REF! error as R1C1 references can't be evaluated.
+func (r r1c1Text) Eval(ctx Context, ev Evaluator) Result {
+	return MakeErrorResultType(ErrorTypeRef, "R1C1 references can't be evaluated")
+}
+
+// Reference returns an invalid reference.
+func (r r1c1Text) Reference(ctx Context, ev Evaluator) Reference {
+	return Reference{Type: ReferenceTypeInvalid}
+}
+
+// String returns the text of the reference.
+func (r r1c1Text) String() string { return string(r) }
+
+// Update returns the reference as it is.
+func (r r1c1Text) Update(q *update.UpdateQuery) Expression { return r }
+
+// r1c1Word returns a reference word in the R1C1 notation relative to origin.
+func r1c1Word(r *refWord, origin reference.CellReference) string {
+	s := ""
+	if r.row != "" {
+		n, _ := strconv.Atoi(r.row)
+		s += "R" + r1c1Offset(n, int(origin.RowIdx), r.absRow)
+	}
+	if r.col != "" {
+		idx := int(reference.ColumnToIndex(strings.ToUpper(r.col)))
+		s += "C" + r1c1Offset(idx+1, int(origin.ColumnIdx)+1, r.absCol)
+	}
+	return s
+}
+
+// r1c1Offset returns the number of a row or column in the R1C1 notation,
+// either as is if it's absolute or as an offset from origin.
+func r1c1Offset(n, origin int, abs bool) string {
+	switch {
+	case abs:
+		return strconv.Itoa(n)
+	case n == origin:
+		return ""
+	}
+	return "[" + strconv.Itoa(n-origin) + "]"
+}
+
+// r1c1Part is the row or the column of an R1C1 reference.
+type r1c1Part struct {
+	set, abs bool
+	n        int
+}
+
+// r1c1Ref is a cell (e.g. R1C[-1]), row (e.g. R[2]) or column (e.g. C3)
+// reference in the R1C1 notation.
+type r1c1Ref struct {
+	row, col r1c1Part
+	end      int
+}
+
+func (r r1c1Ref) sameShape(o r1c1Ref) bool {
+	return r.row.set == o.row.set && r.col.set == o.col.set
+}
+
+// parseR1C1 parses the R1C1 reference at i, returning false if the word at i
+// isn't one.
+func parseR1C1(formula string, i int) (r1c1Ref, bool) {
+	r := r1c1Ref{}
+	j := i
+	ok := true
+	if j < len(formula) && (formula[j] == 'R' || formula[j] == 'r') {
+		if r.row, j, ok = parseR1C1Part(formula, j+1); !ok {
+			return r, false
+		}
+	}
+	if j < len(formula) && (formula[j] == 'C' || formula[j] == 'c') {
+		if r.col, j, ok = parseR1C1Part(formula, j+1); !ok {
+			return r, false
+		}
+	}
+	if j == i {
+		return r, false
+	}
+	// longer words, functions and sheet names
+	if j < len(formula) && (isRefRune(formula[j]) || formula[j] == '(' || formula[j] == '[' || formula[j] == '!') {
+		return r, false
+	}
+	r.end = j
+	return r, true
+}
+
+// r1c1Shaped returns true if the word at i is shaped like an R1C1 reference,
+// whether or not it's a valid one, rather than like a name or a function.
+func r1c1Shaped(formula string, i int) bool {
+	j := i
+	for _, l := range []byte{'R', 'C'} {
+		if j >= len(formula) || formula[j]&^0x20 != l {
+			continue
+		}
+		j++
+		if j < len(formula) && formula[j] == '[' {
+			if k := strings.IndexByte(formula[j:], ']'); k >= 0 {
+				j += k + 1
+			} else {
+				j = len(formula)
+			}
+			continue
+		}
+		for j < len(formula) && formula[j] >= '0' && formula[j] <= '9' {
+			j++
+		}
+	}
+	return j > i && (j == len(formula) || !isRefRune(formula[j]) && formula[j] != '(' && formula[j] != '[' && formula[j] != '!')
+}
+
+// parseR1C1Part parses the number following R or C at i, which is either
+// absolute (e.g. R2), an offset (e.g. R[-2]) or missing for the row or column
+// of the cell itself.
+func parseR1C1Part(formula string, i int) (r1c1Part, int, bool) {
+	p := r1c1Part{set: true}
+	if i < len(formula) && formula[i] == '[' {
+		k := strings.IndexByte(formula[i:], ']')
+		if k < 0 {
+			return p, i, false
+		}
+		n, err := strconv.Atoi(formula[i+1 : i+k])
+		if err != nil {
+			return p, i, false
+		}
+		p.n = n
+		return p, i + k + 1, true
+	}
+	j := i
+	for j < len(formula) && formula[j] >= '0' && formula[j] <= '9' {
+		j++
+	}
+	if j > i {
+		n, err := strconv.Atoi(formula[i:j])
+		if err != nil || n < 1 {
+			return p, i, false
+		}
+		p.n, p.abs = n, true
+	}
+	return p, j, true
+}
+
+// a1Range returns the reference or the range r1:r2 in the A1 notation.  Rows
+// and columns that aren't part of a range become ranges of a single row or
+// column (e.g. R1 becomes 1:1).
+func a1Range(r1, r2 r1c1Ref, origin reference.CellReference) string {
+	w1, ok1 := a1Word(r1, origin)
+	w2, ok2 := a1Word(r2, origin)
+	if !ok1 || !ok2 {
+		return "#REF!"
+	}
+	if r1.end == r2.end && w1.col != "" && w1.row != "" {
+		return w1.String()
+	}
+	return w1.String() + ":" + w2.String()
+}
+
+// a1Word returns an R1C1 reference as a reference word, returning false if
+// it's outside of the sheet.
+func a1Word(r r1c1Ref, origin reference.CellReference) (refWord, bool) {
+	w := refWord{absRow: r.row.abs, absCol: r.col.abs}
+	if r.row.set {
+		n := r.row.n
+		if !r.row.abs {
+			n += int(origin.RowIdx)
+		}
+		if n < 1 || n > maxRowIdx {
+			return w, false
+		}
+		w.row = strconv.Itoa(n)
+	}
+	if r.col.set {
+		idx := r.col.n - 1
+		if !r.col.abs {
+			idx = int(origin.ColumnIdx) + r.col.n
+		}
+		if idx < 0 || idx > maxColumnIdx {
+			return w, false
+		}
+		w.col = reference.IndexToColumn(uint32(idx))
+	}
+	return w, true
+}
@@ -0,0 +1,110 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import "testing"
+
+func TestToR1C1(t *testing.T) {
+	for _, tc := range []struct {
+		formula string
+		exp     string
+	}{
+		{"A1+$B$2", "R[-1]C[-1]+R2C2"},
+		{"SUM($A1:B$3)", "SUM(R[-1]C1:R3C)"},
+		{"SUM(1:3)+SUM($B:B)", "SUM(R[-1]:R[1])+SUM(C2:C)"},
+		{"A2*1E+20", "RC[-1]*1E+20"},
+		{"A2%", "RC[-1]%"},
+		{"A2:B2 B2:C2", "RC[-1]:RC RC:RC[1]"},
+		{"'My Sheet'!A1&\"A1\"", "'My Sheet'!R[-1]C[-1]&\"A1\""},
+		{"Table1[Col]+LOG10(C1)", "Table1[Col]+LOG10(R[-1]C[1])"},
+		{"SUM(", "SUM("},
+		{"SUM(B:B)+SUM(2:2)", "SUM(C)+SUM(R)"},
+		{"SUM($B:$B)+SUM($2:$2)+SUM(B:$B)", "SUM(C2)+SUM(R2)+SUM(C:C2)"},
+		{"SUM(B2:B2)", "SUM(RC:RC)"},
+	} {
+		got, err := ToR1C1(tc.formula, "B2")
+		if err != nil {
+			t.Errorf("%s: %s", tc.formula, err)
+		} else if got != tc.exp {
+			t.Errorf("%s: expected %s, got %s", tc.formula, tc.exp, got)
+		}
+	}
+	if _, err := ToR1C1("A1", "1A"); err == nil {
+		t.Errorf("expected an error for an invalid cell")
+	}
+}
+
+func TestFromR1C1(t *testing.T) {
+	for _, tc := range []struct {
+		formula string
+		exp     string
+	}{
+		{"R[-1]C[-1]+R2C2", "A1+$B$2"},
+		{"SUM(RC1:R3C)", "SUM($A2:B$3)"},
+		{"SUM(R1)", "SUM($1:$1)"},
+		{"R[-2]C", "#REF!"},
+		{"RATE(1,2,3)+Revenue", "RATE(1,2,3)+Revenue"},
+		{"\"R1C1\"&'R1C1'!RC", "\"R1C1\"&'R1C1'!B2"},
+	} {
+		got, err := FromR1C1(tc.formula, "B2")
+		if err != nil {
+			t.Errorf("%s: %s", tc.formula, err)
+		} else if got != tc.exp {
+			t.Errorf("%s: expected %s, got %s", tc.formula, tc.exp, got)
+		}
+	}
+	for _, f := range []string{"R0C1", "R[", "RC[x]+1", "C0"} {
+		if got, err := FromR1C1(f, "B2"); err == nil {
+			t.Errorf("%s: expected an error, got %s", f, got)
+		}
+	}
+}
+
+func TestParseR1C1(t *testing.T) {
+	if _, err := ParseR1C1("SUM(", "B2"); err == nil {
+		t.Errorf("expected an error for an invalid formula")
+	}
+	e, err := ParseR1C1("SUM(R[-1]C[-1]:R[1]C[-1])*2", "B2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := R1C1String(e, "B2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "SUM(R[-1]C[-1]:R[1]C[-1])*2"; got != exp {
+		t.Errorf("expected %s, got %s", exp, got)
+	}
+}
+
+func TestR1C1WholeColumnsAndRows(t *testing.T) {
+	for _, tc := range []struct {
+		formula string
+		exp     string
+	}{
+		{"SUM(A:A)", "SUM(C[-2])"},
+		{"SUM(1:1)", "SUM(R[-4])"},
+		{"SUM(A:B)+SUM(1:2)", "SUM(C[-2]:C[-1])+SUM(R[-4]:R[-3])"},
+		{"SUM($A:$A)+SUM(Sheet2!$1:$1)", "SUM(C1)+SUM(Sheet2!R1)"},
+	} {
+		got, err := ToR1C1(tc.formula, "C5")
+		if err != nil {
+			t.Errorf("%s: %s", tc.formula, err)
+		} else if got != tc.exp {
+			t.Errorf("ToR1C1 %s: expected %s, got %s", tc.formula, tc.exp, got)
+		}
+		if got, err = R1C1String(ParseString(tc.formula), "C5"); err != nil {
+			t.Errorf("%s: %s", tc.formula, err)
+		} else if got != tc.exp {
+			t.Errorf("R1C1String %s: expected %s, got %s", tc.formula, tc.exp, got)
+		}
+		if a1, err := FromR1C1(tc.exp, "C5"); err != nil || a1 != tc.formula {
+			t.Errorf("FromR1C1 %s: expected %s, got %s (%v)", tc.exp, tc.formula, a1, err)
+		}
+	}
+}
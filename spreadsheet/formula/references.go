@@ -0,0 +1,109 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import "strings"
+
+// maxNameDepth limits how deeply defined names that refer to other defined
+// names are followed.
+const maxNameDepth = 16
+
+// volatileFunctions are the functions whose result can change without any of
+// the cells they refer to changing, or whose references are only known once
+// they are evaluated.
+var volatileFunctions = map[string]bool{
+	"CELL":        true,
+	"INDIRECT":    true,
+	"INFO":        true,
+	"NOW":         true,
+	"OFFSET":      true,
+	"RAND":        true,
+	"RANDARRAY":   true,
+	"RANDBETWEEN": true,
+	"TODAY":       true,
+}
+
+// References returns the cells and ranges that an expression refers to when
+// it is evaluated in a context.  Defined names and structured references are
+// resolved to the ranges they refer to.  References that are only known after
+// evaluation, like those computed by INDIRECT or OFFSET, are not returned; see
+// IsVolatile.
+func References(ctx Context, ev Evaluator, expr Expression) []Reference {
+	return appendReferences(nil, ctx, ev, expr, 0)
+}
+
+func appendReferences(refs []Reference, ctx Context, ev Evaluator, expr Expression, depth int) []Reference {
+	switch e := expr.(type) {
+	case CellRef, PrefixExpr, *PrefixExpr, HorizontalRange, VerticalRange, PrefixHorizontalRange, PrefixVerticalRange, StructuredRef:
+		if ref := e.Reference(ctx, ev); ref.Type != ReferenceTypeInvalid {
+			refs = append(refs, ref)
+		}
+	case Range:
+		if ref := e.Reference(ctx, ev); ref.Type != ReferenceTypeInvalid {
+			return append(refs, ref)
+		}
+		// ranges like A1:INDEX(B1:B10,3) are only known after evaluation
+		refs = appendReferences(refs, ctx, ev, e._eadcf, depth)
+		refs = appendReferences(refs, ctx, ev, e._dfbgf, depth)
+	case PrefixRangeExpr:
+		if ref := e.Reference(ctx, ev); ref.Type != ReferenceTypeInvalid {
+			return append(refs, ref)
+		}
+		refs = appendReferences(refs, ctx, ev, e._gaga, depth)
+		refs = appendReferences(refs, ctx, ev, e._dbaf, depth)
+	case NamedRangeRef:
+		ref := e.Reference(ctx, ev)
+		if ref.Type != ReferenceTypeNamedRange || depth >= maxNameDepth {
+			break
+		}
+		named := ctx.NamedRange(ref.Value)
+		if named.Type == ReferenceTypeInvalid {
+			break
+		}
+		if x := ParseString(strings.TrimPrefix(named.Value, "=")); x != nil {
+			refs = appendReferences(refs, ctx, ev, x, depth+1)
+		}
+	case BinaryExpr:
+		refs = appendReferences(refs, ctx, ev, e._age, depth)
+		refs = appendReferences(refs, ctx, ev, e._bd, depth)
+	case Negate:
+		refs = appendReferences(refs, ctx, ev, e._ggbdc, depth)
+	case FunctionCall:
+		for _, arg := range e._agff {
+			refs = appendReferences(refs, ctx, ev, arg, depth)
+		}
+	}
+	return refs
+}
+
+// IsVolatile returns true if an expression calls a volatile function like NOW,
+// RAND or INDIRECT, in which case it must be recalculated whenever any cell
+// changes.
+func IsVolatile(expr Expression) bool {
+	switch e := expr.(type) {
+	case Range:
+		return IsVolatile(e._eadcf) || IsVolatile(e._dfbgf)
+	case PrefixRangeExpr:
+		return IsVolatile(e._gaga) || IsVolatile(e._dbaf)
+	case BinaryExpr:
+		return IsVolatile(e._age) || IsVolatile(e._bd)
+	case Negate:
+		return IsVolatile(e._ggbdc)
+	case FunctionCall:
+		name := strings.TrimPrefix(strings.TrimPrefix(e._fbae, "_xlfn."), "_xlws.")
+		if volatileFunctions[strings.ToUpper(name)] {
+			return true
+		}
+		for _, arg := range e._agff {
+			if IsVolatile(arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
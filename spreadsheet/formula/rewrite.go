@@ -0,0 +1,222 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+// Visitor visits the nodes of an expression with Walk.  If the visitor w
+// returned by Visit isn't nil, the children of the node are visited with w.
+type Visitor interface {
+	Visit(e Expression) (w Visitor)
+}
+
+// Walk traverses an expression depth first, calling v.Visit for each node.
+func Walk(v Visitor, e Expression) {
+	if e == nil {
+		return
+	}
+	if v = v.Visit(e); v == nil {
+		return
+	}
+	for _, c := range Children(e) {
+		Walk(v, c)
+	}
+}
+
+type inspector func(Expression) bool
+
+func (f inspector) Visit(e Expression) Visitor {
+	if f(e) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an expression depth first, calling f for each node.  The
+// children of a node are only visited if f returns true.
+func Inspect(e Expression, f func(Expression) bool) {
+	Walk(inspector(f), e)
+}
+
+// Rewrite returns the expression with each of its nodes replaced by the
+// result of f.  The children of a node are rewritten before f is called for
+// the node, which then has the rewritten children.  Returning the node passed
+// to f keeps it as is.
+func Rewrite(e Expression, f func(Expression) Expression) Expression {
+	if e == nil {
+		return nil
+	}
+	if children := Children(e); len(children) > 0 {
+		rewritten := make([]Expression, len(children))
+		for i, c := range children {
+			rewritten[i] = Rewrite(c, f)
+		}
+		e = withChildren(e, rewritten)
+	}
+	return f(e)
+}
+
+// Children returns the sub-expressions of an expression, such as the
+// arguments of a function call or the operands of a binary expression.
+func Children(e Expression) []Expression {
+	switch n := e.(type) {
+	case Range:
+		return []Expression{n._eadcf, n._dfbgf}
+	case PrefixExpr:
+		return []Expression{n._agaa, n._ddfc}
+	case *PrefixExpr:
+		return []Expression{n._agaa, n._ddfc}
+	case PrefixRangeExpr:
+		return []Expression{n._cgegf, n._gaga, n._dbaf}
+	case PrefixVerticalRange:
+		return []Expression{n._edebe}
+	case PrefixHorizontalRange:
+		return []Expression{n._eegae}
+	case FunctionCall:
+		return append([]Expression(nil), n._agff...)
+	case BinaryExpr:
+		return []Expression{n._age, n._bd}
+	case Negate:
+		return []Expression{n._ggbdc}
+	case ConstArrayExpr:
+		return constArrayChildren(n._fdb)
+	case *ConstArrayExpr:
+		return constArrayChildren(n._fdb)
+	}
+	return nil
+}
+
+func constArrayChildren(rows [][]Expression) []Expression {
+	children := []Expression{}
+	for _, r := range rows {
+		children = append(children, r...)
+	}
+	return children
+}
+
+// withChildren returns a copy of an expression with the children returned by
+// Children replaced.
+func withChildren(e Expression, c []Expression) Expression {
+	switch n := e.(type) {
+	case Range:
+		return Range{c[0], c[1]}
+	case PrefixExpr:
+		return PrefixExpr{c[0], c[1]}
+	case *PrefixExpr:
+		return &PrefixExpr{c[0], c[1]}
+	case PrefixRangeExpr:
+		return PrefixRangeExpr{c[0], c[1], c[2]}
+	case PrefixVerticalRange:
+		n._edebe = c[0]
+		return n
+	case PrefixHorizontalRange:
+		n._eegae = c[0]
+		return n
+	case FunctionCall:
+		return FunctionCall{n._fbae, c}
+	case BinaryExpr:
+		n._age, n._bd = c[0], c[1]
+		return n
+	case Negate:
+		return Negate{c[0]}
+	case ConstArrayExpr:
+		return ConstArrayExpr{constArrayRows(n._fdb, c)}
+	case *ConstArrayExpr:
+		return &ConstArrayExpr{constArrayRows(n._fdb, c)}
+	}
+	return e
+}
+
+func constArrayRows(rows [][]Expression, c []Expression) [][]Expression {
+	res := make([][]Expression, len(rows))
+	for i, r := range rows {
+		res[i], c = c[:len(r):len(r)], c[len(r):]
+	}
+	return res
+}
+
+// Ref returns the cell reference (e.g. $A1).
+func (c CellRef) Ref() string { return c._bda }
+
+// From returns the first cell of the range.
+func (r Range) From() Expression { return r._eadcf }
+
+// To returns the last cell of the range.
+func (r Range) To() Expression { return r._dfbgf }
+
+// Prefix returns the sheet prefix of the expression.
+func (p PrefixExpr) Prefix() Expression { return p._agaa }
+
+// Expr returns the expression that's prefixed by the sheet.
+func (p PrefixExpr) Expr() Expression { return p._ddfc }
+
+// Prefix returns the sheet prefix of the range.
+func (p PrefixRangeExpr) Prefix() Expression { return p._cgegf }
+
+// From returns the first cell of the range.
+func (p PrefixRangeExpr) From() Expression { return p._gaga }
+
+// To returns the last cell of the range.
+func (p PrefixRangeExpr) To() Expression { return p._dbaf }
+
+// Sheet returns the name of the sheet.
+func (s SheetPrefixExpr) Sheet() string { return s._edaee }
+
+// Columns returns the first and the last column of the range (e.g. A and C
+// for A:C).
+func (v VerticalRange) Columns() (string, string) { return v._efdad, v._ddbe }
+
+// Rows returns the first and the last row of the range (e.g. 1 and 3 for
+// 1:3).
+func (h HorizontalRange) Rows() (int, int) { return h._ddgg, h._aabf }
+
+// Prefix returns the sheet prefix of the range.
+func (p PrefixVerticalRange) Prefix() Expression { return p._edebe }
+
+// Columns returns the first and the last column of the range.
+func (p PrefixVerticalRange) Columns() (string, string) { return p._bgcag, p._gadad }
+
+// Prefix returns the sheet prefix of the range.
+func (p PrefixHorizontalRange) Prefix() Expression { return p._eegae }
+
+// Rows returns the first and the last row of the range.
+func (p PrefixHorizontalRange) Rows() (int, int) { return p._gfad, p._cfaf }
+
+// Name returns the name of the function.
+func (f FunctionCall) Name() string { return f._fbae }
+
+// Args returns the arguments of the function call.
+func (f FunctionCall) Args() []Expression { return append([]Expression(nil), f._agff...) }
+
+// Left returns the left hand side of the binary expression.
+func (b BinaryExpr) Left() Expression { return b._age }
+
+// Right returns the right hand side of the binary expression.
+func (b BinaryExpr) Right() Expression { return b._bd }
+
+// Op returns the operator of the binary expression.
+func (b BinaryExpr) Op() BinOpType { return b._dd }
+
+// Operand returns the negated expression.
+func (n Negate) Operand() Expression { return n._ggbdc }
+
+// Values returns the rows of values of the constant array.
+func (c ConstArrayExpr) Values() [][]Expression { return c._fdb }
+
+// Name returns the name of the named range.
+func (n NamedRangeRef) Name() string { return n._bdggc }
+
+// Value returns the value of the boolean.
+func (b Bool) Value() bool { return b._gfbd }
+
+// Value returns the value of the number.
+func (n Number) Value() float64 { return n._becb }
+
+// Value returns the value of the string.
+func (s String) Value() string { return s._abace }
+
+// Value returns the error value (e.g. #N/A).
+func (e Error) Value() string { return e._gag }
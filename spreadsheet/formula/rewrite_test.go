@@ -0,0 +1,190 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// nodeTypes returns the types of the nodes of an expression in the order they
+// are visited.
+func nodeTypes(e Expression, f func(Expression) bool) string {
+	types := []string{}
+	Inspect(e, func(x Expression) bool {
+		types = append(types, strings.TrimPrefix(strings.TrimPrefix(fmt.Sprintf("%T", x), "*"), "formula."))
+		return f(x)
+	})
+	return strings.Join(types, " ")
+}
+
+func TestInspect(t *testing.T) {
+	all := func(Expression) bool { return true }
+	for _, tc := range []struct {
+		formula string
+		exp     string
+	}{
+		{"IF(A1>0,SUM(A1:A3,{1,2;3,4}),-'My Sheet'!A1)",
+			"FunctionCall BinaryExpr CellRef Number FunctionCall Range CellRef CellRef ConstArrayExpr Number Number Number Number " +
+				"Negate PrefixExpr SheetPrefixExpr CellRef"},
+		{"Sheet1!A1:B2", "PrefixRangeExpr SheetPrefixExpr CellRef CellRef"},
+		{"Sheet1!A:B", "PrefixVerticalRange SheetPrefixExpr"},
+		{"'My Sheet'!1:3", "PrefixHorizontalRange SheetPrefixExpr"},
+		{"SUM(A:A,1:1)", "FunctionCall VerticalRange HorizontalRange"},
+		{"{1,\"x\";TRUE,#N/A}", "ConstArrayExpr Number String Bool Error"},
+		{"Name1*2", "BinaryExpr NamedRangeRef Number"},
+		{"\"a\"&B1", "BinaryExpr String CellRef"},
+		{"PI()", "FunctionCall"},
+	} {
+		if got := nodeTypes(ParseString(tc.formula), all); got != tc.exp {
+			t.Errorf("%s: expected %s, got %s", tc.formula, tc.exp, got)
+		}
+	}
+
+	// the children of function calls aren't visited
+	e := ParseString("1+SUM(A1,MAX(B1,2))")
+	if got := nodeTypes(e, func(x Expression) bool {
+		_, ok := x.(FunctionCall)
+		return !ok
+	}); got != "BinaryExpr Number FunctionCall" {
+		t.Errorf("expected the function call to be skipped, got %s", got)
+	}
+	Inspect(nil, func(Expression) bool {
+		t.Errorf("expected nil not to be visited")
+		return true
+	})
+}
+
+// countVisitor counts the nodes it visits down to a depth.
+type countVisitor struct {
+	depth, max int
+	count      *int
+}
+
+func (v countVisitor) Visit(e Expression) Visitor {
+	*v.count++
+	if v.depth == v.max {
+		return nil
+	}
+	return countVisitor{v.depth + 1, v.max, v.count}
+}
+
+func TestWalk(t *testing.T) {
+	e := ParseString("SUM(A1,MAX(B1,-C1),D1:E2)")
+	for max, exp := range []int{1, 4, 8, 9} {
+		n := 0
+		Walk(countVisitor{0, max, &n}, e)
+		if n != exp {
+			t.Errorf("expected %d nodes down to depth %d, got %d", exp, max, n)
+		}
+	}
+}
+
+func TestChildren(t *testing.T) {
+	for _, tc := range []struct {
+		formula string
+		exp     []string
+	}{
+		{"SUM(A1,2,\"x\")", []string{"A1", "2", `"x"`}},
+		{"A1*(B1+2)", []string{"A1", "B1+2"}},
+		{"-A1", []string{"A1"}},
+		{"{1,2;3,4}", []string{"1", "2", "3", "4"}},
+		{"Sheet2!A1", []string{"Sheet2", "A1"}},
+		{"Sheet2!A1:B3", []string{"Sheet2", "A1", "B3"}},
+		{"A1:B3", []string{"A1", "B3"}},
+		{"A1", nil},
+		{"PI()", nil},
+	} {
+		got := []string{}
+		for _, c := range Children(ParseString(tc.formula)) {
+			got = append(got, c.String())
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tc.exp) && !(len(got) == 0 && tc.exp == nil) {
+			t.Errorf("%s: expected the children %v, got %v", tc.formula, tc.exp, got)
+		}
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	// rewriting each node to itself round-trips the formula
+	for _, f := range []string{
+		"IF(A1>0,SUM(A1:A3,{1,2;3,4}),-'My Sheet'!A1)",
+		"Sheet1!A1:B2",
+		"SUM(Sheet1!A:B,'My Sheet'!1:3)",
+		"{1,\"x\";TRUE,#N/A}",
+		"-(1+2)*3",
+		"A1*(B1+2)",
+		"\"a\"&B1",
+		"Name1*2",
+	} {
+		e := ParseString(f)
+		if got := Rewrite(e, func(x Expression) Expression { return x }).String(); got != e.String() {
+			t.Errorf("expected %s to be unchanged, got %s", e.String(), got)
+		}
+	}
+
+	double := func(x Expression) Expression {
+		if n, ok := x.(Number); ok {
+			return NewNumber(strconv.FormatFloat(n.Value()*2, 'f', -1, 64))
+		}
+		return x
+	}
+	renameSheet := func(x Expression) Expression {
+		if s, ok := x.(*SheetPrefixExpr); ok && s.Sheet() == "Sheet1" {
+			return NewSheetPrefixExpr("My Sheet")
+		}
+		return x
+	}
+	moveA1 := func(x Expression) Expression {
+		if c, ok := x.(CellRef); ok && c.Ref() == "A1" {
+			return NewCellRef("B2")
+		}
+		return x
+	}
+	upper := func(x Expression) Expression {
+		if f, ok := x.(FunctionCall); ok {
+			return NewFunction(strings.ToUpper(f.Name()), f.Args())
+		}
+		return x
+	}
+	for _, tc := range []struct {
+		formula string
+		fn      func(Expression) Expression
+		exp     string
+	}{
+		{"SUM({1,2;3,4})+MAX(1,-2)*3", double, "SUM({2,4;6,8})+MAX(2,-4)*6"},
+		{"IF(A1>1,{1,2},-A1)", double, "IF(A1>2,{2,4},-A1)"},
+		{"Sheet1!A1+SUM(Sheet1!A1:B2,Sheet1!C:D,Sheet1!1:2,Sheet2!A1)", renameSheet,
+			"'My Sheet'!A1+SUM('My Sheet'!A1:B2,'My Sheet'!C:D,'My Sheet'!1:2,Sheet2!A1)"},
+		{"A1+SUM(A1:C3,Sheet2!A1,-A1)", moveA1, "B2+SUM(B2:C3,Sheet2!B2,-B2)"},
+		{"sum(a1,max(b1,c1))", upper, "SUM(a1,MAX(b1,c1))"},
+	} {
+		e := ParseString(tc.formula)
+		before := e.String()
+		if got := Rewrite(e, tc.fn).String(); got != tc.exp {
+			t.Errorf("%s: expected %s, got %s", tc.formula, tc.exp, got)
+		}
+		if e.String() != before {
+			t.Errorf("%s: expected the original expression to be unchanged, got %s", tc.formula, e.String())
+		}
+	}
+
+	// the children passed to f have already been rewritten
+	e := ParseString("SUM(1,2)")
+	Rewrite(e, func(x Expression) Expression {
+		if f, ok := x.(FunctionCall); ok && f.Args()[0].String() != "2" {
+			t.Errorf("expected the arguments to be rewritten first, got %s", f.String())
+		}
+		return double(x)
+	})
+	if Rewrite(nil, double) != nil {
+		t.Errorf("expected nil to be rewritten to nil")
+	}
+}
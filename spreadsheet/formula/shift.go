@@ -0,0 +1,105 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+)
+
+// maxColumnIdx and maxRowIdx are the largest column index and row number of
+// a sheet.
+const (
+	maxColumnIdx = 16383
+	maxRowIdx    = 1048576
+)
+
+// isRefRune returns true for the characters that can be part of a cell,
+// column or row reference, a function name or a defined name.
+func isRefRune(c byte) bool {
+	return isASCIILetter(c) || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '$'
+}
+
+// refWord is a word of a formula shaped like a cell (e.g. $A1), column (e.g.
+// $A) or row (e.g. 3) reference.
+type refWord struct {
+	col, row       string
+	absCol, absRow bool
+}
+
+// parseRefWord parses a word of a formula as a reference, returning false if
+// it isn't shaped like one.
+func parseRefWord(w string) (refWord, bool) {
+	r := refWord{}
+	i := 0
+	if i < len(w) && w[i] == '$' {
+		r.absCol = true
+		i++
+	}
+	start := i
+	for i < len(w) && isASCIILetter(w[i]) {
+		i++
+	}
+	r.col = w[start:i]
+	if i < len(w) && w[i] == '$' && r.col != "" {
+		r.absRow = true
+		i++
+	}
+	j := i
+	for i < len(w) && w[i] >= '0' && w[i] <= '9' {
+		i++
+	}
+	r.row = w[j:i]
+	if i != len(w) || len(r.col) > 3 || r.col == "" && r.row == "" {
+		return r, false
+	}
+	if r.col == "" {
+		r.absCol, r.absRow = false, r.absCol
+	}
+	return r, true
+}
+
+// shift moves the relative parts of the reference, returning false if the
+// result is outside of the sheet.
+func (r *refWord) shift(cols, rows int) bool {
+	if r.col != "" && !r.absCol {
+		idx := int(reference.ColumnToIndex(strings.ToUpper(r.col))) + cols
+		if idx < 0 || idx > maxColumnIdx {
+			return false
+		}
+		r.col = reference.IndexToColumn(uint32(idx))
+	}
+	if r.row != "" && !r.absRow {
+		n, _ := strconv.Atoi(r.row)
+		n += rows
+		if n < 1 || n > maxRowIdx {
+			return false
+		}
+		r.row = strconv.Itoa(n)
+	}
+	return true
+}
+
+func (r refWord) String() string {
+	s := ""
+	if r.col != "" {
+		if r.absCol {
+			s += "$"
+		}
+		s += r.col
+	}
+	if r.row != "" {
+		if r.absRow {
+			s += "$"
+		}
+		s += r.row
+	}
+	return s
+}
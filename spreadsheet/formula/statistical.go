@@ -0,0 +1,714 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"math"
+	"sort"
+)
+
+func init() {
+	RegisterFunction("STDEV", StdevS)
+	RegisterFunction("STDEV.S", StdevS)
+	RegisterFunction("_xlfn.STDEV.S", StdevS)
+	RegisterFunction("STDEVP", StdevP)
+	RegisterFunction("STDEV.P", StdevP)
+	RegisterFunction("_xlfn.STDEV.P", StdevP)
+	RegisterFunction("VAR", VarS)
+	RegisterFunction("VAR.S", VarS)
+	RegisterFunction("_xlfn.VAR.S", VarS)
+	RegisterFunction("VARP", VarP)
+	RegisterFunction("VAR.P", VarP)
+	RegisterFunction("_xlfn.VAR.P", VarP)
+	RegisterFunction("CORREL", Correl)
+	RegisterFunction("COVAR", CovarianceP)
+	RegisterFunction("COVARIANCE.P", CovarianceP)
+	RegisterFunction("_xlfn.COVARIANCE.P", CovarianceP)
+	RegisterFunction("COVARIANCE.S", CovarianceS)
+	RegisterFunction("_xlfn.COVARIANCE.S", CovarianceS)
+	RegisterFunction("PERCENTILE", PercentileInc)
+	RegisterFunction("PERCENTILE.INC", PercentileInc)
+	RegisterFunction("_xlfn.PERCENTILE.INC", PercentileInc)
+	RegisterFunction("PERCENTILE.EXC", PercentileExc)
+	RegisterFunction("_xlfn.PERCENTILE.EXC", PercentileExc)
+	RegisterFunction("QUARTILE", QuartileInc)
+	RegisterFunction("QUARTILE.INC", QuartileInc)
+	RegisterFunction("_xlfn.QUARTILE.INC", QuartileInc)
+	RegisterFunction("QUARTILE.EXC", QuartileExc)
+	RegisterFunction("_xlfn.QUARTILE.EXC", QuartileExc)
+	RegisterFunction("RANK", RankEq)
+	RegisterFunction("RANK.EQ", RankEq)
+	RegisterFunction("_xlfn.RANK.EQ", RankEq)
+	RegisterFunction("RANK.AVG", RankAvg)
+	RegisterFunction("_xlfn.RANK.AVG", RankAvg)
+	RegisterFunction("MODE", ModeSngl)
+	RegisterFunction("MODE.SNGL", ModeSngl)
+	RegisterFunction("_xlfn.MODE.SNGL", ModeSngl)
+	RegisterFunction("MODE.MULT", ModeMult)
+	RegisterFunction("_xlfn.MODE.MULT", ModeMult)
+	RegisterFunction("AVERAGEIF", AverageIf)
+	RegisterFunction("AVERAGEIFS", AverageIfs)
+	RegisterFunction("FORECAST", Forecast)
+	RegisterFunction("FORECAST.LINEAR", Forecast)
+	RegisterFunction("_xlfn.FORECAST.LINEAR", Forecast)
+	RegisterFunction("SLOPE", Slope)
+	RegisterFunction("INTERCEPT", Intercept)
+	RegisterFunction("RSQ", Rsq)
+	RegisterFunction("NORMDIST", NormDist)
+	RegisterFunction("NORM.DIST", NormDist)
+	RegisterFunction("_xlfn.NORM.DIST", NormDist)
+	RegisterFunction("NORMINV", NormInv)
+	RegisterFunction("NORM.INV", NormInv)
+	RegisterFunction("_xlfn.NORM.INV", NormInv)
+	RegisterFunction("NORMSDIST", NormSDist)
+	RegisterFunction("NORM.S.DIST", NormSDist)
+	RegisterFunction("_xlfn.NORM.S.DIST", NormSDist)
+	RegisterFunction("NORMSINV", NormSInv)
+	RegisterFunction("NORM.S.INV", NormSInv)
+	RegisterFunction("_xlfn.NORM.S.INV", NormSInv)
+	RegisterFunction("SUBTOTAL", Subtotal)
+}
+
+// isReference returns true if an argument was passed as a cell or range
+// reference rather than typed directly into the formula.
+func isReference(arg Result) bool {
+	switch arg.Ref.Type {
+	case ReferenceTypeCell, ReferenceTypeRange, ReferenceTypeNamedRange,
+		ReferenceTypeHorizontalRange, ReferenceTypeVerticalRange:
+		return true
+	}
+	return false
+}
+
+// statNumbers collects the numbers from the arguments of a statistical
+// function the way Excel does.  Numbers, booleans and numeric text typed
+// directly as an argument are counted while only numbers are taken from
+// references and arrays, ignoring text, booleans and blanks.
+func statNumbers(name string, args []Result) ([]float64, Result) {
+	ret := []float64{}
+	for _, arg := range args {
+		switch arg.Type {
+		case ResultTypeError:
+			return nil, arg
+		case ResultTypeList, ResultTypeArray:
+			for _, v := range arg.ListValues() {
+				switch v.Type {
+				case ResultTypeError:
+					return nil, v
+				case ResultTypeNumber:
+					if !v.IsBoolean {
+						ret = append(ret, v.ValueNumber)
+					}
+				}
+			}
+		case ResultTypeNumber:
+			if isReference(arg) && arg.IsBoolean {
+				continue
+			}
+			ret = append(ret, arg.ValueNumber)
+		case ResultTypeString:
+			if isReference(arg) {
+				continue
+			}
+			n := arg.AsNumber()
+			if n.Type != ResultTypeNumber {
+				return nil, MakeErrorResultType(ErrorTypeValue, name+" requires numeric arguments")
+			}
+			ret = append(ret, n.ValueNumber)
+		}
+	}
+	return ret, MakeEmptyResult()
+}
+
+// statPairs collects the pairs of numbers from two equally sized arrays,
+// skipping pairs where either value isn't a number.
+func statPairs(name string, a, b Result) ([]float64, []float64, Result) {
+	for _, r := range []Result{a, b} {
+		if r.Type == ResultTypeError {
+			return nil, nil, r
+		}
+	}
+	av, bv := statValues(a), statValues(b)
+	if len(av) != len(bv) {
+		return nil, nil, MakeErrorResultType(ErrorTypeNA, name+" requires arrays of the same size")
+	}
+	xs, ys := []float64{}, []float64{}
+	for i := range av {
+		if av[i].Type == ResultTypeError {
+			return nil, nil, av[i]
+		}
+		if bv[i].Type == ResultTypeError {
+			return nil, nil, bv[i]
+		}
+		if av[i].Type != ResultTypeNumber || av[i].IsBoolean || bv[i].Type != ResultTypeNumber || bv[i].IsBoolean {
+			continue
+		}
+		xs = append(xs, av[i].ValueNumber)
+		ys = append(ys, bv[i].ValueNumber)
+	}
+	return xs, ys, MakeEmptyResult()
+}
+
+// statValues returns the values of an array argument, or the argument itself
+// if it's a single value.
+func statValues(r Result) []Result {
+	if r.Type == ResultTypeList || r.Type == ResultTypeArray {
+		return r.ListValues()
+	}
+	return []Result{r}
+}
+
+// statNumberArg converts an argument to a number, returning an error result if
+// it isn't numeric.
+func statNumberArg(name string, arg Result) (float64, Result) {
+	if arg.Type == ResultTypeError {
+		return 0, arg
+	}
+	n := arg.AsNumber()
+	if n.Type != ResultTypeNumber {
+		return 0, MakeErrorResultType(ErrorTypeValue, name+" requires numeric arguments")
+	}
+	return n.ValueNumber, MakeEmptyResult()
+}
+
+func mean(vs []float64) float64 {
+	sum := 0.0
+	for _, v := range vs {
+		sum += v
+	}
+	return sum / float64(len(vs))
+}
+
+// variance returns the variance of a sample or a population.
+func variance(name string, args []Result, sample bool) (float64, Result) {
+	vs, res := statNumbers(name, args)
+	if res.Type == ResultTypeError {
+		return 0, res
+	}
+	n := float64(len(vs))
+	if sample {
+		n--
+	}
+	if n <= 0 {
+		return 0, MakeErrorResultType(ErrorTypeDivideByZero, name+" requires more values")
+	}
+	m := mean(vs)
+	sum := 0.0
+	for _, v := range vs {
+		sum += (v - m) * (v - m)
+	}
+	return sum / n, MakeEmptyResult()
+}
+
+// StdevS implements the STDEV and STDEV.S functions which estimate the
+// standard deviation of a sample.
+func StdevS(args []Result) Result {
+	v, res := variance("STDEV", args, true)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	return MakeNumberResult(math.Sqrt(v))
+}
+
+// StdevP implements the STDEVP and STDEV.P functions which return the standard
+// deviation of a population.
+func StdevP(args []Result) Result {
+	v, res := variance("STDEVP", args, false)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	return MakeNumberResult(math.Sqrt(v))
+}
+
+// VarS implements the VAR and VAR.S functions which estimate the variance of a
+// sample.
+func VarS(args []Result) Result {
+	v, res := variance("VAR", args, true)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	return MakeNumberResult(v)
+}
+
+// VarP implements the VARP and VAR.P functions which return the variance of a
+// population.
+func VarP(args []Result) Result {
+	v, res := variance("VARP", args, false)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	return MakeNumberResult(v)
+}
+
+// pairStats holds the sums used by the functions operating on pairs of values.
+type pairStats struct {
+	n             float64
+	meanX, meanY  float64
+	sxx, syy, sxy float64
+}
+
+func newPairStats(name string, x, y Result) (pairStats, Result) {
+	xs, ys, res := statPairs(name, x, y)
+	if res.Type == ResultTypeError {
+		return pairStats{}, res
+	}
+	if len(xs) == 0 {
+		return pairStats{}, MakeErrorResultType(ErrorTypeDivideByZero, name+" requires numeric values")
+	}
+	ps := pairStats{n: float64(len(xs)), meanX: mean(xs), meanY: mean(ys)}
+	for i := range xs {
+		dx, dy := xs[i]-ps.meanX, ys[i]-ps.meanY
+		ps.sxx += dx * dx
+		ps.syy += dy * dy
+		ps.sxy += dx * dy
+	}
+	return ps, MakeEmptyResult()
+}
+
+// Correl implements the CORREL function which returns the correlation
+// coefficient of two arrays.
+func Correl(args []Result) Result {
+	if len(args) != 2 {
+		return MakeErrorResult("CORREL requires two arguments")
+	}
+	ps, res := newPairStats("CORREL", args[0], args[1])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	if ps.sxx == 0 || ps.syy == 0 {
+		return MakeErrorResultType(ErrorTypeDivideByZero, "CORREL divide by zero")
+	}
+	return MakeNumberResult(ps.sxy / math.Sqrt(ps.sxx*ps.syy))
+}
+
+// CovarianceP implements the COVAR and COVARIANCE.P functions which return the
+// population covariance of two arrays.
+func CovarianceP(args []Result) Result {
+	if len(args) != 2 {
+		return MakeErrorResult("COVARIANCE.P requires two arguments")
+	}
+	ps, res := newPairStats("COVARIANCE.P", args[0], args[1])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	return MakeNumberResult(ps.sxy / ps.n)
+}
+
+// CovarianceS implements the COVARIANCE.S function which returns the sample
+// covariance of two arrays.
+func CovarianceS(args []Result) Result {
+	if len(args) != 2 {
+		return MakeErrorResult("COVARIANCE.S requires two arguments")
+	}
+	ps, res := newPairStats("COVARIANCE.S", args[0], args[1])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	if ps.n < 2 {
+		return MakeErrorResultType(ErrorTypeDivideByZero, "COVARIANCE.S requires at least two pairs of values")
+	}
+	return MakeNumberResult(ps.sxy / (ps.n - 1))
+}
+
+// Slope implements the SLOPE function which returns the slope of the linear
+// regression line through the known y's and x's.
+func Slope(args []Result) Result {
+	if len(args) != 2 {
+		return MakeErrorResult("SLOPE requires two arguments")
+	}
+	ps, res := newPairStats("SLOPE", args[1], args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	if ps.sxx == 0 {
+		return MakeErrorResultType(ErrorTypeDivideByZero, "SLOPE divide by zero")
+	}
+	return MakeNumberResult(ps.sxy / ps.sxx)
+}
+
+// Intercept implements the INTERCEPT function which returns the point where
+// the linear regression line through the known y's and x's intersects the y
+// axis.
+func Intercept(args []Result) Result {
+	if len(args) != 2 {
+		return MakeErrorResult("INTERCEPT requires two arguments")
+	}
+	ps, res := newPairStats("INTERCEPT", args[1], args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	if ps.sxx == 0 {
+		return MakeErrorResultType(ErrorTypeDivideByZero, "INTERCEPT divide by zero")
+	}
+	return MakeNumberResult(ps.meanY - ps.sxy/ps.sxx*ps.meanX)
+}
+
+// Rsq implements the RSQ function which returns the square of the correlation
+// coefficient of the known y's and x's.
+func Rsq(args []Result) Result {
+	r := Correl(args)
+	if r.Type != ResultTypeNumber {
+		return r
+	}
+	return MakeNumberResult(r.ValueNumber * r.ValueNumber)
+}
+
+// Forecast implements the FORECAST and FORECAST.LINEAR functions which predict
+// a value along the linear regression line through the known y's and x's.
+func Forecast(args []Result) Result {
+	if len(args) != 3 {
+		return MakeErrorResult("FORECAST requires three arguments")
+	}
+	x, res := statNumberArg("FORECAST", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	ps, res := newPairStats("FORECAST", args[2], args[1])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	if ps.sxx == 0 {
+		return MakeErrorResultType(ErrorTypeDivideByZero, "FORECAST divide by zero")
+	}
+	slope := ps.sxy / ps.sxx
+	return MakeNumberResult(ps.meanY - slope*ps.meanX + slope*x)
+}
+
+// percentile returns the k-th percentile of sorted values, interpolating
+// between them.  With exclusive set, k is exclusive of 0 and 1.
+func percentile(name string, vs []float64, k float64, exclusive bool) Result {
+	n := float64(len(vs))
+	if n == 0 {
+		return MakeErrorResultType(ErrorTypeNum, name+" requires at least one value")
+	}
+	var pos float64
+	if exclusive {
+		pos = k*(n+1) - 1
+		if k <= 0 || k >= 1 || pos < 0 || pos > n-1 {
+			return MakeErrorResultType(ErrorTypeNum, name+" requires k to be within the range of the values")
+		}
+	} else {
+		if k < 0 || k > 1 {
+			return MakeErrorResultType(ErrorTypeNum, name+" requires k to be between 0 and 1")
+		}
+		pos = k * (n - 1)
+	}
+	sort.Float64s(vs)
+	i := int(pos)
+	if i >= len(vs)-1 {
+		return MakeNumberResult(vs[len(vs)-1])
+	}
+	return MakeNumberResult(vs[i] + (pos-float64(i))*(vs[i+1]-vs[i]))
+}
+
+func percentileFn(name string, args []Result, exclusive, quartile bool) Result {
+	if len(args) != 2 {
+		return MakeErrorResult(name + " requires two arguments")
+	}
+	vs, res := statNumbers(name, args[:1])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	k, res := statNumberArg(name, args[1])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	if quartile {
+		q := math.Trunc(k)
+		if q < 0 || q > 4 || (exclusive && (q < 1 || q > 3)) {
+			return MakeErrorResultType(ErrorTypeNum, name+" requires a valid quartile")
+		}
+		k = q / 4
+	}
+	return percentile(name, vs, k, exclusive)
+}
+
+// PercentileInc implements the PERCENTILE and PERCENTILE.INC functions which
+// return the k-th percentile of values where k is between 0 and 1 inclusive.
+func PercentileInc(args []Result) Result {
+	return percentileFn("PERCENTILE", args, false, false)
+}
+
+// PercentileExc implements the PERCENTILE.EXC function which returns the k-th
+// percentile of values where k is between 0 and 1 exclusive.
+func PercentileExc(args []Result) Result {
+	return percentileFn("PERCENTILE.EXC", args, true, false)
+}
+
+// QuartileInc implements the QUARTILE and QUARTILE.INC functions which return
+// a quartile of values based on percentiles from 0 to 1 inclusive.
+func QuartileInc(args []Result) Result {
+	return percentileFn("QUARTILE", args, false, true)
+}
+
+// QuartileExc implements the QUARTILE.EXC function which returns a quartile of
+// values based on percentiles from 0 to 1 exclusive.
+func QuartileExc(args []Result) Result {
+	return percentileFn("QUARTILE.EXC", args, true, true)
+}
+
+func rank(name string, args []Result, average bool) Result {
+	if len(args) != 2 && len(args) != 3 {
+		return MakeErrorResult(name + " requires two or three arguments")
+	}
+	x, res := statNumberArg(name, args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	if args[1].Type != ResultTypeList && args[1].Type != ResultTypeArray && !isReference(args[1]) {
+		return MakeErrorResult(name + " requires second argument to be a reference")
+	}
+	vs, res := statNumbers(name, args[1:2])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	ascending := false
+	if len(args) == 3 {
+		order, res := statNumberArg(name, args[2])
+		if res.Type == ResultTypeError {
+			return res
+		}
+		ascending = order != 0
+	}
+	before, equal := 0, 0
+	for _, v := range vs {
+		switch {
+		case v == x:
+			equal++
+		case (v < x) == ascending:
+			before++
+		}
+	}
+	if equal == 0 {
+		return MakeErrorResultType(ErrorTypeNA, name+" requires the number to be in the reference")
+	}
+	r := float64(before + 1)
+	if average {
+		r += float64(equal-1) / 2
+	}
+	return MakeNumberResult(r)
+}
+
+// RankEq implements the RANK and RANK.EQ functions which return the rank of a
+// number in a list of numbers, giving equal numbers the top rank.
+func RankEq(args []Result) Result { return rank("RANK", args, false) }
+
+// RankAvg implements the RANK.AVG function which returns the rank of a number
+// in a list of numbers, giving equal numbers their average rank.
+func RankAvg(args []Result) Result { return rank("RANK.AVG", args, true) }
+
+// modes returns the most frequently occurring values in the order they first
+// occur.
+func modes(name string, args []Result) ([]float64, Result) {
+	vs, res := statNumbers(name, args)
+	if res.Type == ResultTypeError {
+		return nil, res
+	}
+	counts := map[float64]int{}
+	max := 1
+	for _, v := range vs {
+		counts[v]++
+		if counts[v] > max {
+			max = counts[v]
+		}
+	}
+	if max == 1 {
+		return nil, MakeErrorResultType(ErrorTypeNA, name+" requires a repeated value")
+	}
+	ret := []float64{}
+	for _, v := range vs {
+		if counts[v] == max {
+			ret = append(ret, v)
+			counts[v] = 0
+		}
+	}
+	return ret, MakeEmptyResult()
+}
+
+// ModeSngl implements the MODE and MODE.SNGL functions which return the most
+// frequently occurring value.
+func ModeSngl(args []Result) Result {
+	ms, res := modes("MODE", args)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	return MakeNumberResult(ms[0])
+}
+
+// ModeMult implements the MODE.MULT function which returns a vertical array
+// of the most frequently occurring values.
+func ModeMult(args []Result) Result {
+	ms, res := modes("MODE.MULT", args)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	arr := [][]Result{}
+	for _, m := range ms {
+		arr = append(arr, []Result{MakeNumberResult(m)})
+	}
+	return MakeArrayResult(arr)
+}
+
+// AverageIf implements the AVERAGEIF function which averages the cells that
+// meet a criteria.
+func AverageIf(args []Result) Result {
+	if len(args) != 2 && len(args) != 3 {
+		return MakeErrorResult("AVERAGEIF requires two or three arguments")
+	}
+	rng := args[0]
+	if rng.Type != ResultTypeArray && rng.Type != ResultTypeList {
+		return MakeErrorResult("AVERAGEIF requires first argument of type array")
+	}
+	avgRng := rng
+	if len(args) == 3 {
+		avgRng = args[2]
+		if avgRng.Type != ResultTypeArray && avgRng.Type != ResultTypeList {
+			return MakeErrorResult("AVERAGEIF requires last argument of type array")
+		}
+	}
+	values := _cdafd(avgRng)
+	criteria := _cacg(args[1])
+	sum, count := 0.0, 0.0
+	for r, row := range _cdafd(rng) {
+		for c, v := range row {
+			if !_egaa(v, criteria) || r >= len(values) || c >= len(values[r]) {
+				continue
+			}
+			if av := values[r][c]; av.Type == ResultTypeNumber && !av.IsBoolean {
+				sum += av.ValueNumber
+				count++
+			}
+		}
+	}
+	if count == 0 {
+		return MakeErrorResultType(ErrorTypeDivideByZero, "AVERAGEIF divide by zero")
+	}
+	return MakeNumberResult(sum / count)
+}
+
+// AverageIfs implements the AVERAGEIFS function which averages the cells that
+// meet multiple criteria.
+func AverageIfs(args []Result) Result {
+	if res := _gdgfe(args, true, "AVERAGEIFS"); res.Type != ResultTypeEmpty {
+		return res
+	}
+	values := _cdafd(args[0])
+	sum, count := 0.0, 0.0
+	for _, idx := range _eabc(args[1:]) {
+		if v := values[idx._ffaab][idx._edga]; v.Type == ResultTypeNumber && !v.IsBoolean {
+			sum += v.ValueNumber
+			count++
+		}
+	}
+	if count == 0 {
+		return MakeErrorResultType(ErrorTypeDivideByZero, "AVERAGEIFS divide by zero")
+	}
+	return MakeNumberResult(sum / count)
+}
+
+// statNumberArgs converts all of the arguments to numbers.
+func statNumberArgs(name string, args []Result) ([]float64, Result) {
+	ret := make([]float64, len(args))
+	for i, arg := range args {
+		v, res := statNumberArg(name, arg)
+		if res.Type == ResultTypeError {
+			return nil, res
+		}
+		ret[i] = v
+	}
+	return ret, MakeEmptyResult()
+}
+
+func normDist(x, mean, sd float64, cumulative bool) float64 {
+	z := (x - mean) / sd
+	if cumulative {
+		return 0.5 * math.Erfc(-z/math.Sqrt2)
+	}
+	return math.Exp(-z*z/2) / (sd * math.Sqrt(2*math.Pi))
+}
+
+// NormDist implements the NORMDIST and NORM.DIST functions which return the
+// normal distribution for a mean and standard deviation.
+func NormDist(args []Result) Result {
+	if len(args) != 4 {
+		return MakeErrorResult("NORM.DIST requires four arguments")
+	}
+	vs, res := statNumberArgs("NORM.DIST", args)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	if vs[2] <= 0 {
+		return MakeErrorResultType(ErrorTypeNum, "NORM.DIST requires standard deviation to be positive")
+	}
+	return MakeNumberResult(normDist(vs[0], vs[1], vs[2], vs[3] != 0))
+}
+
+// NormSDist implements the NORMSDIST and NORM.S.DIST functions which return
+// the standard normal distribution.  NORMSDIST is always cumulative.
+func NormSDist(args []Result) Result {
+	if len(args) != 1 && len(args) != 2 {
+		return MakeErrorResult("NORM.S.DIST requires one or two arguments")
+	}
+	vs, res := statNumberArgs("NORM.S.DIST", args)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	return MakeNumberResult(normDist(vs[0], 0, 1, len(vs) == 1 || vs[1] != 0))
+}
+
+// NormInv implements the NORMINV and NORM.INV functions which return the
+// inverse of the cumulative normal distribution for a mean and standard
+// deviation.
+func NormInv(args []Result) Result {
+	if len(args) != 3 {
+		return MakeErrorResult("NORM.INV requires three arguments")
+	}
+	vs, res := statNumberArgs("NORM.INV", args)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	if vs[0] <= 0 || vs[0] >= 1 {
+		return MakeErrorResultType(ErrorTypeNum, "NORM.INV requires probability to be between 0 and 1")
+	}
+	if vs[2] <= 0 {
+		return MakeErrorResultType(ErrorTypeNum, "NORM.INV requires standard deviation to be positive")
+	}
+	return MakeNumberResult(vs[1] + vs[2]*math.Sqrt2*math.Erfinv(2*vs[0]-1))
+}
+
+// NormSInv implements the NORMSINV and NORM.S.INV functions which return the
+// inverse of the standard normal cumulative distribution.
+func NormSInv(args []Result) Result {
+	if len(args) != 1 {
+		return MakeErrorResult("NORM.S.INV requires one argument")
+	}
+	return NormInv([]Result{args[0], MakeNumberResult(0), MakeNumberResult(1)})
+}
+
+// Subtotal implements the SUBTOTAL function which applies one of the AVERAGE,
+// COUNT, COUNTA, MAX, MIN, PRODUCT, STDEV, STDEVP, SUM, VAR or VARP functions
+// selected by number to the remaining arguments.  Hidden rows aren't known to
+// the evaluator so the function numbers 101-111 are treated as 1-11.
+func Subtotal(args []Result) Result {
+	if len(args) < 2 {
+		return MakeErrorResult("SUBTOTAL requires at least two arguments")
+	}
+	fn, res := statNumberArg("SUBTOTAL", args[0])
+	if res.Type == ResultTypeError {
+		return res
+	}
+	fns := []Function{Average, Count, Counta, Max, Min, Product, StdevS, StdevP, Sum, VarS, VarP}
+	idx := int(fn)
+	if idx > 100 {
+		idx -= 100
+	}
+	if idx < 1 || idx > len(fns) {
+		return MakeErrorResultType(ErrorTypeValue, "SUBTOTAL requires a valid function number")
+	}
+	return fns[idx-1](args[1:])
+}
@@ -0,0 +1,194 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// cellContext is a context whose cells hold fixed values, all other cells are
+// empty.
+type cellContext struct {
+	Context
+	cells map[string]Result
+}
+
+func (c cellContext) Cell(ref string, ev Evaluator) Result {
+	if r, ok := c.cells[ref]; ok {
+		return r
+	}
+	return MakeEmptyResult()
+}
+
+// newCellContext returns a context with the values of a column of cells
+// starting at the first row, for each column.
+func newCellContext(cols map[string][]Result) cellContext {
+	ctx := cellContext{InvalidReferenceContext, map[string]Result{}}
+	for col, vs := range cols {
+		for i, v := range vs {
+			ctx.cells[col+strconv.Itoa(i+1)] = v
+		}
+	}
+	return ctx
+}
+
+func numbers(vs ...float64) []Result {
+	ret := []Result{}
+	for _, v := range vs {
+		ret = append(ret, MakeNumberResult(v))
+	}
+	return ret
+}
+
+// formulaCase is a formula and its expected value.  Numbers are compared to
+// the precision of the expected value so reference values can be copied from
+// Excel.
+type formulaCase struct {
+	formula string
+	exp     string
+}
+
+func testFormulas(t *testing.T, ctx Context, cases []formulaCase) {
+	t.Helper()
+	ev := NewEvaluator()
+	for _, tc := range cases {
+		got := ev.Eval(ctx, tc.formula)
+		if exp, err := strconv.ParseFloat(tc.exp, 64); err == nil && got.Type == ResultTypeNumber {
+			tol := 1e-12 * math.Max(1, math.Abs(exp))
+			if i := strings.IndexByte(tc.exp, '.'); i >= 0 && !strings.ContainsAny(tc.exp, "eE") {
+				tol = math.Max(tol, 0.5*math.Pow(10, -float64(len(tc.exp)-i-1)))
+			}
+			if math.Abs(got.ValueNumber-exp) > tol {
+				t.Errorf("%s: expected %s, got %v", tc.formula, tc.exp, got.ValueNumber)
+			}
+			continue
+		}
+		if got.Value() != tc.exp {
+			t.Errorf("%s: expected %s, got %s", tc.formula, tc.exp, got.Value())
+		}
+	}
+}
+
+// statContext holds the values 2, 4, 4, 4, 5, 5, 7 and 9 in A1:A8 and 1 to 8
+// in B1:B8, text and booleans in C1:C3 and an error in D1.
+func statContext() cellContext {
+	return newCellContext(map[string][]Result{
+		"A": numbers(2, 4, 4, 4, 5, 5, 7, 9),
+		"B": numbers(1, 2, 3, 4, 5, 6, 7, 8),
+		"C": {MakeStringResult("10"), MakeBoolResult(true), MakeNumberResult(3)},
+		"D": {MakeErrorResultType(ErrorTypeNA, "")},
+	})
+}
+
+func TestStatistical(t *testing.T) {
+	testFormulas(t, statContext(), []formulaCase{
+		{"STDEV(A1:A8)", "2.138089935299395"},
+		{"STDEV.S(A1:A8)", "2.138089935299395"},
+		{"STDEVP(A1:A8)", "2"},
+		{"STDEV.P(A1:A8)", "2"},
+		{"VAR(A1:A8)", "4.571428571428571"},
+		{"VAR.S(A1:A8)", "4.571428571428571"},
+		{"VARP(A1:A8)", "4"},
+		{"VAR.P(A1:A8)", "4"},
+		// text and booleans are ignored in references but counted when typed
+		{"STDEV(C1:C3,1)", "1.414213562373095"},
+		{"STDEV(\"10\",TRUE,3)", "4.725815626252608"},
+		{"VAR.P({1,2,\"x\",TRUE})", "0.25"},
+
+		{"CORREL({1,2,3,4,5},{2,4,5,4,5})", "0.7745966692414834"},
+		{"COVAR({1,2,3,4,5},{2,4,5,4,5})", "1.2"},
+		{"COVARIANCE.P({1,2,3,4,5},{2,4,5,4,5})", "1.2"},
+		{"COVARIANCE.S({1,2,3,4,5},{2,4,5,4,5})", "1.5"},
+		{"SLOPE({2,4,5,4,5},{1,2,3,4,5})", "0.6"},
+		{"INTERCEPT({2,4,5,4,5},{1,2,3,4,5})", "2.2"},
+		{"RSQ({2,4,5,4,5},{1,2,3,4,5})", "0.6"},
+		{"FORECAST(6,{2,4,5,4,5},{1,2,3,4,5})", "5.8"},
+		{"FORECAST.LINEAR(6,{2,4,5,4,5},{1,2,3,4,5})", "5.8"},
+
+		{"PERCENTILE({1,2,3,4},0.3)", "1.9"},
+		{"PERCENTILE.INC({1,2,3,4},1)", "4"},
+		{"PERCENTILE.EXC({1,2,3,4},0.3)", "1.5"},
+		{"QUARTILE(A1:A8,1)", "4"},
+		{"QUARTILE.INC({1,2,3,4},1)", "1.75"},
+		{"QUARTILE.EXC({1,2,3,4},1)", "1.25"},
+		{"QUARTILE.INC({1,2,3,4},4)", "4"},
+
+		{"RANK(4,A1:A8)", "5"},
+		{"RANK.EQ(4,A1:A8,1)", "2"},
+		{"RANK.AVG(4,A1:A8)", "6"},
+		{"RANK.AVG(5,A1:A8,1)", "5.5"},
+
+		{"MODE(A1:A8)", "4"},
+		{"MODE.SNGL({1,2,2,3,3})", "2"},
+		{"ROWS(MODE.MULT({1,1,2,2,3}))", "2"},
+		{"INDEX(MODE.MULT({1,1,2,2,3}),2)", "2"},
+
+		{"AVERAGEIF(A1:A8,\">4\")", "6.5"},
+		{"AVERAGEIF(A1:A8,4,B1:B8)", "3"},
+		{"AVERAGEIFS(B1:B8,A1:A8,\">4\",A1:A8,\"<9\")", "6"},
+
+		{"NORMDIST(42,40,1.5,TRUE)", "0.9087887802741321"},
+		{"NORM.DIST(42,40,1.5,FALSE)", "0.10934004978399575"},
+		{"NORMSDIST(1)", "0.8413447460685429"},
+		{"NORM.S.DIST(1,TRUE)", "0.8413447460685429"},
+		{"NORM.INV(0.908789,40,1.5)", "42.00000200956616"},
+		{"NORMSINV(0.95)", "1.6448536269514715"},
+		{"NORM.S.INV(0.908789)", "1.3333346730441071"},
+
+		{"SUBTOTAL(1,A1:A8)", "5"},
+		{"SUBTOTAL(2,A1:A8,C1:C3)", "9"},
+		{"SUBTOTAL(9,A1:A8)", "40"},
+		{"SUBTOTAL(104,A1:A8)", "9"},
+		{"SUBTOTAL(7,A1:A8)", "2.138089935299395"},
+	})
+}
+
+func TestStatisticalErrors(t *testing.T) {
+	testFormulas(t, statContext(), []formulaCase{
+		{"STDEV(1)", "#DIV/0!"},
+		{"VAR.S(C1:C2)", "#DIV/0!"},
+		{"VARP(C1:C2)", "#DIV/0!"},
+		{"STDEV(\"x\",1)", "#VALUE!"},
+		{"STDEV(A1:A8,D1)", "#N/A"},
+		{"CORREL({1,1,1},{1,2,3})", "#DIV/0!"},
+		{"CORREL({1,2},{1,2,3})", "#N/A"},
+		{"COVARIANCE.S({1},{2})", "#DIV/0!"},
+		{"SLOPE({1,2},{3,3})", "#DIV/0!"},
+		{"FORECAST(1,{1,2},{3,3})", "#DIV/0!"},
+		{"SLOPE(D1,B1)", "#N/A"},
+
+		{"PERCENTILE.INC({1,2,3,4},1.5)", "#NUM!"},
+		{"PERCENTILE.INC({1,2,3,4},-0.1)", "#NUM!"},
+		{"PERCENTILE.EXC({1,2,3,4},0.1)", "#NUM!"},
+		{"PERCENTILE.EXC({1,2,3,4},0)", "#NUM!"},
+		{"QUARTILE.INC({1,2,3,4},5)", "#NUM!"},
+		{"QUARTILE.EXC({1,2,3,4},0)", "#NUM!"},
+		{"PERCENTILE(C1:C2,0.5)", "#NUM!"},
+
+		{"RANK(3,A1:A8)", "#N/A"},
+		{"MODE({1,2,3})", "#N/A"},
+		{"MODE.MULT({1,2,3})", "#N/A"},
+
+		{"AVERAGEIF(A1:A8,\">100\")", "#DIV/0!"},
+		{"AVERAGEIFS(B1:B8,A1:A8,\">100\")", "#DIV/0!"},
+
+		{"NORM.DIST(1,0,0,TRUE)", "#NUM!"},
+		{"NORM.INV(0,40,1.5)", "#NUM!"},
+		{"NORM.INV(1,40,1.5)", "#NUM!"},
+		{"NORM.INV(0.5,40,-1)", "#NUM!"},
+		{"NORM.S.INV(1.5)", "#NUM!"},
+		{"NORM.DIST(\"x\",0,1,TRUE)", "#VALUE!"},
+
+		{"SUBTOTAL(0,A1:A8)", "#VALUE!"},
+		{"SUBTOTAL(12,A1:A8)", "#VALUE!"},
+		{"SUBTOTAL(9,D1)", "#N/A"},
+	})
+}
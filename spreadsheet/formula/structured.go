@@ -0,0 +1,464 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+	"github.com/unidoc/unioffice/spreadsheet/update"
+)
+
+// TableInfo describes the location of a table and is used to resolve
+// structured references like 'Table1[Amount]'.
+type TableInfo struct {
+	// Sheet is the name of the sheet that contains the table.
+	Sheet string
+	// Ref is the range of the table including any header and totals rows
+	// (e.g. 'A1:D10').
+	Ref string
+	// Columns are the names of the table columns from left to right.
+	Columns []string
+	// HeaderRowCount and TotalsRowCount are the number of header and totals
+	// rows of the table, zero or one.
+	HeaderRowCount, TotalsRowCount uint32
+	// ThisRow is the row of the cell being evaluated, used to resolve '@' and
+	// '#This Row' references.  It is zero if unknown.
+	ThisRow uint32
+}
+
+// TableContext is implemented by contexts which know the tables of the
+// workbook.  Without it structured references evaluate to #REF!.
+type TableContext interface {
+	// Table returns the table with a given name, or the table containing the
+	// cell being evaluated if the name is empty.
+	Table(name string) (TableInfo, bool)
+}
+
+// tableContext returns the TableContext of a context, looking through the
+// contexts of LET and LAMBDA.
+func tableContext(ctx Context) (TableContext, bool) {
+	for {
+		switch c := ctx.(type) {
+		case TableContext:
+			return c, true
+		case *letContext:
+			ctx = c.Context
+		default:
+			return nil, false
+		}
+	}
+}
+
+type tableItem byte
+
+const (
+	tableItemHeaders tableItem = 1 << iota
+	tableItemData
+	tableItemTotals
+	tableItemThisRow
+	tableItemAll = tableItemHeaders | tableItemData | tableItemTotals
+)
+
+var tableItems = map[string]tableItem{
+	"#all":      tableItemAll,
+	"#data":     tableItemData,
+	"#headers":  tableItemHeaders,
+	"#totals":   tableItemTotals,
+	"#this row": tableItemThisRow,
+}
+
+// StructuredRef is a structured reference to a part of a table like
+// 'Table1[Amount]', 'Table1[[#Totals],[Amount]]' or '[@Amount]'.  A reference
+// without a table name refers to the table containing the cell being
+// evaluated.
+type StructuredRef struct {
+	s        string
+	table    string
+	items    tableItem
+	from, to string
+}
+
+// NewStructuredRef constructs a new structured reference, returning an error
+// expression if the reference is not valid.
+func NewStructuredRef(v string) Expression {
+	if sr, ok := parseStructuredRef(v); ok {
+		return sr
+	}
+	return NewError("#REF!")
+}
+
+// parseStructuredRef parses a structured reference, returning false if v is
+// not a structured reference.
+func parseStructuredRef(v string) (StructuredRef, bool) {
+	idx := strings.IndexByte(v, '[')
+	if idx < 0 || !strings.HasSuffix(v, "]") {
+		return StructuredRef{}, false
+	}
+	sr := StructuredRef{s: v, table: v[:idx]}
+	spec := strings.TrimSpace(v[idx+1 : len(v)-1])
+	switch {
+	case spec == "":
+	case spec[0] == '@':
+		sr.items = tableItemThisRow
+		spec = strings.TrimSpace(spec[1:])
+		if spec == "" {
+			break
+		}
+		if spec[0] != '[' {
+			sr.from = unescapeTableColumn(spec)
+			break
+		}
+		parts, ok := splitTableSpecifiers(spec)
+		if !ok || len(parts) != 1 || strings.HasPrefix(parts[0][0], "#") {
+			return StructuredRef{}, false
+		}
+		sr.from, sr.to = parts[0][0], parts[0][len(parts[0])-1]
+	case spec[0] == '#':
+		it, ok := tableItems[strings.ToLower(spec)]
+		if !ok {
+			return StructuredRef{}, false
+		}
+		sr.items = it
+	case spec[0] == '[':
+		parts, ok := splitTableSpecifiers(spec)
+		if !ok {
+			return StructuredRef{}, false
+		}
+		for i, p := range parts {
+			if strings.HasPrefix(p[0], "#") {
+				it, ok := tableItems[strings.ToLower(p[0])]
+				if !ok || len(p) != 1 || sr.from != "" {
+					return StructuredRef{}, false
+				}
+				sr.items |= it
+				continue
+			}
+			// the column specifier must be the last one
+			if i != len(parts)-1 {
+				return StructuredRef{}, false
+			}
+			sr.from, sr.to = p[0], p[len(p)-1]
+		}
+		if sr.items&tableItemThisRow != 0 && sr.items != tableItemThisRow {
+			return StructuredRef{}, false
+		}
+	default:
+		sr.from = unescapeTableColumn(spec)
+	}
+	if sr.to == "" {
+		sr.to = sr.from
+	}
+	return sr, true
+}
+
+// splitTableSpecifiers splits a list of bracketed specifiers like
+// '[#Totals],[Amount]' or '[Col1]:[Col2]' into its comma separated parts,
+// each of which contains one or two (a column range) unescaped names.
+func splitTableSpecifiers(s string) ([][]string, bool) {
+	ret := [][]string{}
+	cur := []string{}
+	colon := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case ' ':
+		case '[':
+			if len(cur) == 2 || (len(cur) == 1 && !colon) {
+				return nil, false
+			}
+			name := strings.Builder{}
+			i++
+			for ; i < len(s) && s[i] != ']'; i++ {
+				if s[i] == '\'' && i+1 < len(s) {
+					i++
+				}
+				name.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, false
+			}
+			cur = append(cur, strings.TrimSpace(name.String()))
+		case ':':
+			if len(cur) != 1 || colon {
+				return nil, false
+			}
+			colon = true
+		case ',':
+			if len(cur) == 0 || (colon && len(cur) != 2) {
+				return nil, false
+			}
+			ret = append(ret, cur)
+			cur = []string{}
+			colon = false
+		default:
+			return nil, false
+		}
+	}
+	if len(cur) == 0 || (colon && len(cur) != 2) {
+		return nil, false
+	}
+	return append(ret, cur), true
+}
+
+// unescapeTableColumn removes the escape characters from a column name.
+func unescapeTableColumn(s string) string {
+	b := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\'' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// resolve returns the cell or range reference (e.g. 'Sheet1'!B2:B10) that the
+// structured reference refers to, or an error result if it can't be resolved.
+func (s StructuredRef) resolve(ctx Context) (string, Result) {
+	info, ok := TableInfo{}, false
+	if tc, has := tableContext(ctx); has {
+		info, ok = tc.Table(s.table)
+	}
+	if !ok {
+		if s.table == "" {
+			return "", MakeErrorResultType(ErrorTypeRef, "no table contains the cell for "+s.s)
+		}
+		return "", MakeErrorResultType(ErrorTypeRef, "unknown table "+s.table)
+	}
+	from, to, err := reference.ParseRangeReference(info.Ref)
+	if err != nil {
+		return "", MakeErrorResultType(ErrorTypeRef, fmt.Sprintf("invalid table range %s: %s", info.Ref, err))
+	}
+
+	colFrom, colTo := from.ColumnIdx, to.ColumnIdx
+	if s.from != "" {
+		fi, ti := -1, -1
+		for i, c := range info.Columns {
+			if strings.EqualFold(c, s.from) {
+				fi = i
+			}
+			if strings.EqualFold(c, s.to) {
+				ti = i
+			}
+		}
+		if fi < 0 || ti < 0 {
+			return "", MakeErrorResultType(ErrorTypeRef, "unknown table column in "+s.s)
+		}
+		if fi > ti {
+			fi, ti = ti, fi
+		}
+		colFrom, colTo = from.ColumnIdx+uint32(fi), from.ColumnIdx+uint32(ti)
+	}
+
+	dataFrom, dataTo := from.RowIdx+info.HeaderRowCount, to.RowIdx-info.TotalsRowCount
+	rowFrom, rowTo := dataFrom, dataTo
+	switch s.items {
+	case 0, tableItemData:
+	case tableItemAll:
+		rowFrom, rowTo = from.RowIdx, to.RowIdx
+	case tableItemThisRow:
+		if info.ThisRow < dataFrom || info.ThisRow > dataTo {
+			return "", MakeErrorResultType(ErrorTypeValue, s.s+" is not in a data row of the table")
+		}
+		rowFrom, rowTo = info.ThisRow, info.ThisRow
+	default:
+		if s.items&tableItemHeaders != 0 && info.HeaderRowCount == 0 {
+			return "", MakeErrorResultType(ErrorTypeRef, "table has no header row")
+		}
+		if s.items&tableItemTotals != 0 && info.TotalsRowCount == 0 {
+			return "", MakeErrorResultType(ErrorTypeRef, "table has no totals row")
+		}
+		switch s.items {
+		case tableItemHeaders:
+			rowFrom, rowTo = from.RowIdx, from.RowIdx
+		case tableItemTotals:
+			rowFrom, rowTo = to.RowIdx, to.RowIdx
+		case tableItemHeaders | tableItemData:
+			rowFrom = from.RowIdx
+		case tableItemData | tableItemTotals:
+			rowTo = to.RowIdx
+		default:
+			return "", MakeErrorResultType(ErrorTypeRef, "invalid combination of table items in "+s.s)
+		}
+	}
+
+	ref := fmt.Sprintf("%s%d", reference.IndexToColumn(colFrom), rowFrom)
+	if colFrom != colTo || rowFrom != rowTo {
+		ref += fmt.Sprintf(":%s%d", reference.IndexToColumn(colTo), rowTo)
+	}
+	return "'" + strings.Replace(info.Sheet, "'", "''", -1) + "'!" + ref, MakeEmptyResult()
+}
+
+// Eval evaluates and returns the result of the structured reference.
+func (s StructuredRef) Eval(ctx Context, ev Evaluator) Result {
+	ref, res := s.resolve(ctx)
+	if res.Type == ResultTypeError {
+		return res
+	}
+	return ParseString(ref).Eval(ctx, ev)
+}
+
+// Reference returns the cell or range reference the structured reference
+// refers to.
+func (s StructuredRef) Reference(ctx Context, ev Evaluator) Reference {
+	ref, res := s.resolve(ctx)
+	if res.Type == ResultTypeError {
+		return ReferenceInvalid
+	}
+	return ParseString(ref).Reference(ctx, ev)
+}
+
+// String returns a string representation of the structured reference.
+func (s StructuredRef) String() string { return s.s }
+
+// Update returns the same object as updating structured references doesn't
+// depend on the position of cells.
+func (s StructuredRef) Update(q *update.UpdateQuery) Expression { return s }
+
+// RenameTable returns a formula with its structured references to the table
+// named old changed to refer to the table named new.  Table names are compared
+// case insensitively and the rest of the formula is kept as is.
+func RenameTable(formula, old, new string) string {
+	parts := splitStructuredRefs(formula)
+	for i := 1; i < len(parts); i += 2 {
+		if idx := strings.IndexByte(parts[i], '['); idx > 0 && strings.EqualFold(parts[i][:idx], old) {
+			parts[i] = new + parts[i][idx:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// isTableNameRune returns true if r can be part of a table name.
+func isTableNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '\\'
+}
+
+// splitStructuredRefs splits a formula into text and the structured
+// references it contains.  Odd elements of the returned slice are structured
+// references.
+func splitStructuredRefs(s string) []string {
+	ret := []string{}
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\'':
+			// skip strings and quoted sheet names
+			q := s[i]
+			for i++; i < len(s); i++ {
+				if s[i] == q {
+					if i+1 < len(s) && s[i+1] == q {
+						i++
+						continue
+					}
+					break
+				}
+			}
+		case '[':
+			depth, end := 0, -1
+			for j := i; j < len(s) && end < 0; j++ {
+				switch s[j] {
+				case '\'':
+					j++
+				case '[':
+					depth++
+				case ']':
+					depth--
+					if depth == 0 {
+						end = j
+					}
+				}
+			}
+			if end < 0 {
+				continue
+			}
+			nameStart := i
+			for nameStart > start {
+				r, n := utf8.DecodeLastRuneInString(s[start:nameStart])
+				if !isTableNameRune(r) {
+					break
+				}
+				nameStart -= n
+			}
+			// external workbook references like [1]Sheet1!A1 are not
+			// structured references
+			if nameStart == i && strings.Trim(s[i+1:end], "0123456789") == "" {
+				i = end
+				continue
+			}
+			ret = append(ret, s[start:nameStart], s[nameStart:end+1])
+			start = end + 1
+			i = end
+		}
+	}
+	return append(ret, s[start:])
+}
+
+// lexStructured lexes a formula like LexReader, additionally recognizing
+// structured references, spill range operators, names bound by LET and LAMBDA
+// and calls of a LAMBDA which can't be expressed in the generated lexer.  The
+// text between structured references and bound names is lexed as usual and
+// each of them is emitted as a single named range token.
+// A name followed by a parenthesis, like _xlfn._xlws.SORT( or a call to a
+// lambda, is emitted as a function token.
+func lexStructured(r io.Reader) chan *node {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return LexReader(strings.NewReader(""))
+	}
+	text := rewriteLambdaCalls(rewriteSpillRefs(string(b)))
+	names := boundNames(text)
+	parts := splitStructuredRefs(text)
+	ch := make(chan *node)
+	go func() {
+		var pending *node
+		emit := func(n *node) {
+			if pending != nil {
+				if n._faaa == _bgfd { // tokenLParen
+					n = &node{_bggb, pending._acccf} // tokenFunctionBuiltin
+				} else {
+					ch <- pending
+				}
+				pending = nil
+			}
+			if n._faaa == _cdded { // tokenNamedRange
+				pending = n
+				return
+			}
+			ch <- n
+		}
+		for i, p := range parts {
+			if i%2 == 1 {
+				ch <- &node{_cdded, p} // tokenNamedRange
+				continue
+			}
+			for j, t := range splitBoundNames(p, names) {
+				if j%2 == 1 {
+					emit(&node{_cdded, t}) // tokenNamedRange
+					continue
+				}
+				if strings.TrimSpace(t) == "" {
+					continue
+				}
+				for n := range LexReader(strings.NewReader(t)) {
+					emit(n)
+				}
+			}
+			if pending != nil {
+				ch <- pending
+				pending = nil
+			}
+		}
+		close(ch)
+	}()
+	return ch
+}
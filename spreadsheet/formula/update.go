@@ -0,0 +1,548 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+	"github.com/unidoc/unioffice/spreadsheet/update"
+)
+
+// UpdateFormula updates the references of a formula after rows or columns are
+// inserted or removed as described by q.  References without a sheet prefix
+// are only updated if q.UpdateCurrentSheet is set.  The text of the formula is
+// otherwise kept as is, references to removed cells become #REF! and ranges
+// partially removed shrink, the same as in Excel.
+//
+// For UpdateActionCopy the relative references of the formula are moved by
+// the offset of q whatever sheet they refer to, as happens when a formula is
+// copied.  For UpdateActionMove references to the moved range follow it and
+// references to the cells it's moved over become #REF!.
+func UpdateFormula(formula string, q *update.UpdateQuery) string {
+	return rewriteRefs(formula, func(prefix string, r1, r2 *refWord) string {
+		apply := q.UpdateType == update.UpdateActionCopy || prefix == "" && q.UpdateCurrentSheet || prefix != "" && updatesSheet(prefix, q)
+		if apply && !updateRefWords(r1, r2, q) {
+			return prefix + "#REF!"
+		}
+		return prefix + refText(r1, r2)
+	})
+}
+
+// refRewriter returns the text that replaces a reference, or the range r1:r2
+// if r2 isn't nil, along with its sheet prefix (e.g. Sheet1!) if it has one.
+type refRewriter func(prefix string, r1, r2 *refWord) string
+
+// refText returns the text of a reference or the range r1:r2.
+func refText(r1, r2 *refWord) string {
+	if r2 == nil {
+		return r1.String()
+	}
+	return r1.String() + ":" + r2.String()
+}
+
+// rewriteRefs replaces the references of a formula by the text returned by
+// rw, keeping the rest of the formula as is.  Whole rows and columns are only
+// references as part of a range.  The prefix of the references of other
+// workbooks (e.g. [1]Sheet1!) and those spanning sheets (e.g. Sheet1:Sheet3!)
+// is passed to rw as is.
+func rewriteRefs(formula string, rw refRewriter) string {
+	buf := strings.Builder{}
+	for i := 0; i < len(formula); {
+		c := formula[i]
+		switch {
+		case c == '"':
+			j := skipQuoted(formula, i)
+			buf.WriteString(formula[i:j])
+			i = j
+		case c == '[':
+			// structured references and external workbooks, the sheet
+			// prefix of a reference follows the index of its workbook
+			j := skipBrackets(formula, i)
+			if j < len(formula) && (formula[j] == '\'' || isRefRune(formula[j])) {
+				i = rewriteReference(&buf, formula, i, j, rw)
+				continue
+			}
+			buf.WriteString(formula[i:j])
+			i = j
+		case c == '#':
+			// error values and the spill operator
+			j := i + 1
+			for j < len(formula) && (isRefRune(formula[j]) || formula[j] == '/' || formula[j] == '!' || formula[j] == '?') {
+				j++
+			}
+			buf.WriteString(formula[i:j])
+			i = j
+		case c == '\'' || isRefRune(c):
+			j := rewriteReference(&buf, formula, i, i, rw)
+			i = j
+		default:
+			buf.WriteByte(c)
+			i++
+		}
+	}
+	return buf.String()
+}
+
+// skipQuoted returns the end of the string or quoted sheet name starting at i.
+func skipQuoted(formula string, i int) int {
+	c := formula[i]
+	j := i + 1
+	for ; j < len(formula); j++ {
+		if formula[j] == c {
+			if j+1 < len(formula) && formula[j+1] == c {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return j
+}
+
+// skipBrackets returns the end of the bracketed block starting at i.
+func skipBrackets(formula string, i int) int {
+	depth := 0
+	for j := i; j < len(formula); j++ {
+		if formula[j] == '[' {
+			depth++
+		} else if formula[j] == ']' {
+			depth--
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return len(formula)
+}
+
+// sheetPrefixEnd returns the end of the sheet prefix (e.g. Sheet1! or 'My
+// Sheet'!) starting at i, returning false if there is none.
+func sheetPrefixEnd(formula string, i int) (int, bool) {
+	j := i
+	if formula[i] == '\'' {
+		j = skipQuoted(formula, i)
+	} else {
+		for j < len(formula) && isRefRune(formula[j]) {
+			j++
+		}
+	}
+	if j < len(formula) && formula[j] == '!' {
+		return j + 1, true
+	}
+	return i, false
+}
+
+// sheetSpanEnd returns the end of the prefix of a three dimensional reference
+// (e.g. Sheet1:Sheet3!) starting at i, returning false if there is none.
+func sheetSpanEnd(formula string, i int) (int, bool) {
+	j := readWord(formula, i)
+	if j == i || j >= len(formula) || formula[j] != ':' || j+1 >= len(formula) {
+		return i, false
+	}
+	if k, ok := sheetPrefixEnd(formula, j+1); ok && k > j+2 {
+		return k, true
+	}
+	return i, false
+}
+
+// sheetName returns the sheet name of a sheet prefix without the quotes and
+// the exclamation mark.
+func sheetName(prefix string) string {
+	prefix = strings.TrimSuffix(prefix, "!")
+	if strings.HasPrefix(prefix, "'") && strings.HasSuffix(prefix, "'") && len(prefix) > 1 {
+		prefix = strings.Replace(prefix[1:len(prefix)-1], "''", "'", -1)
+	}
+	return prefix
+}
+
+// updatesSheet returns true if references prefixed by the given sheet name are
+// updated by q.
+func updatesSheet(name string, q *update.UpdateQuery) bool {
+	return strings.EqualFold(sheetName(name), q.SheetToUpdate)
+}
+
+// readWord returns the end of the word of reference characters at i.
+func readWord(formula string, i int) int {
+	for i < len(formula) && isRefRune(formula[i]) {
+		i++
+	}
+	return i
+}
+
+// rewriteReference writes the optionally prefixed word or range starting at i
+// to buf, rewriting it if it's a reference, and returns where it ends.  The
+// text from start to i is the index of an external workbook (e.g. [1]) which
+// is part of the prefix.
+func rewriteReference(buf *strings.Builder, formula string, start, i int, rw refRewriter) int {
+	if j, ok := sheetPrefixEnd(formula, i); ok {
+		i = j
+	} else if j, ok := sheetSpanEnd(formula, i); ok {
+		i = j
+	} else {
+		// brackets which aren't followed by a sheet, like structured references
+		buf.WriteString(formula[start:i])
+		start = i
+		if formula[i] == '\'' {
+			j := skipQuoted(formula, i)
+			buf.WriteString(formula[i:j])
+			return j
+		}
+	}
+	prefix := formula[start:i]
+	end := readWord(formula, i)
+	if end == i {
+		buf.WriteString(formula[start:end])
+		return end
+	}
+	w1 := formula[i:end]
+	if end < len(formula) && formula[end] == '(' {
+		// function names
+		buf.WriteString(formula[start:end])
+		return end
+	}
+	r1, ok := parseRefWord(w1)
+	if !ok || !validRefWord(r1) {
+		buf.WriteString(formula[start:end])
+		return end
+	}
+	// the second part of a range
+	if end+1 < len(formula) && formula[end] == ':' && isRefRune(formula[end+1]) {
+		k := readWord(formula, end+1)
+		if k == len(formula) || formula[k] != '(' && formula[k] != '!' {
+			if r2, ok := parseRefWord(formula[end+1 : k]); ok && validRefWord(r2) && sameShape(r1, r2) {
+				buf.WriteString(rw(prefix, &r1, &r2))
+				return k
+			}
+		}
+	}
+	// whole rows and columns are only references as part of a range
+	if r1.col == "" || r1.row == "" {
+		buf.WriteString(formula[start:end])
+		return end
+	}
+	buf.WriteString(rw(prefix, &r1, nil))
+	return end
+}
+
+// validRefWord returns false if the reference is outside of the sheet.
+func validRefWord(r refWord) bool {
+	if r.col != "" && reference.ColumnToIndex(strings.ToUpper(r.col)) > maxColumnIdx {
+		return false
+	}
+	if r.row != "" {
+		n, err := strconv.Atoi(r.row)
+		if err != nil || n < 1 || n > maxRowIdx {
+			return false
+		}
+	}
+	return true
+}
+
+// sameShape returns true if both references are cells, columns or rows.
+func sameShape(a, b refWord) bool {
+	return (a.col == "") == (b.col == "") && (a.row == "") == (b.row == "")
+}
+
+// updateCount returns the number of rows or columns inserted or removed.
+func updateCount(q *update.UpdateQuery) int {
+	if q.Count == 0 {
+		return 1
+	}
+	return int(q.Count)
+}
+
+// updateSpan updates the span from-to of rows or columns after count of them
+// are inserted or removed at idx, returning false if the span is removed.
+// Single cells pushed off the sheet are removed, ranges are cut at max.
+func updateSpan(from, to, idx, count, max int, insert, single bool) (int, int, bool) {
+	if insert {
+		if from >= idx {
+			from += count
+		}
+		if to >= idx {
+			to += count
+		}
+		if from > max || single && to > max {
+			return from, to, false
+		}
+		if to > max {
+			to = max
+		}
+		return from, to, true
+	}
+	last := idx + count - 1
+	if from >= idx && to <= last {
+		return from, to, false
+	}
+	if from > last {
+		from -= count
+	} else if from >= idx {
+		from = idx
+	}
+	if to > last {
+		to -= count
+	} else if to >= idx {
+		to = idx - 1
+	}
+	return from, to, true
+}
+
+// updateRefWords updates a reference or the range r1:r2 (r2 is nil for a
+// single reference), returning false if it's removed.
+func updateRefWords(r1, r2 *refWord, q *update.UpdateQuery) bool {
+	single := r2 == nil
+	if single {
+		c := *r1
+		r2 = &c
+	}
+	count := updateCount(q)
+	switch q.UpdateType {
+	case update.UpdateActionRemoveColumn, update.UpdateActionInsertColumn:
+		if r1.col == "" {
+			return true
+		}
+		from := int(reference.ColumnToIndex(strings.ToUpper(r1.col)))
+		to := int(reference.ColumnToIndex(strings.ToUpper(r2.col)))
+		swapped := from > to
+		if swapped {
+			from, to = to, from
+		}
+		from, to, ok := updateSpan(from, to, int(q.ColumnIdx), count, maxColumnIdx, q.UpdateType == update.UpdateActionInsertColumn, single)
+		if !ok {
+			return false
+		}
+		if swapped {
+			from, to = to, from
+		}
+		r1.col = reference.IndexToColumn(uint32(from))
+		r2.col = reference.IndexToColumn(uint32(to))
+	case update.UpdateActionRemoveRow, update.UpdateActionInsertRow:
+		if r1.row == "" {
+			return true
+		}
+		from, _ := strconv.Atoi(r1.row)
+		to, _ := strconv.Atoi(r2.row)
+		swapped := from > to
+		if swapped {
+			from, to = to, from
+		}
+		from, to, ok := updateSpan(from, to, int(q.RowIdx), count, maxRowIdx, q.UpdateType == update.UpdateActionInsertRow, single)
+		if !ok {
+			return false
+		}
+		if swapped {
+			from, to = to, from
+		}
+		r1.row = strconv.Itoa(from)
+		r2.row = strconv.Itoa(to)
+	case update.UpdateActionCopy:
+		if !r1.shift(q.ColumnOffset, q.RowOffset) || !r2.shift(q.ColumnOffset, q.RowOffset) {
+			return false
+		}
+	case update.UpdateActionMove:
+		return moveRefWords(r1, r2, q)
+	}
+	return true
+}
+
+// refBounds returns the columns and rows spanned by the range r1:r2.
+func refBounds(r1, r2 *refWord) (c1, c2, rw1, rw2 int) {
+	c1 = int(reference.ColumnToIndex(strings.ToUpper(r1.col)))
+	c2 = int(reference.ColumnToIndex(strings.ToUpper(r2.col)))
+	rw1, _ = strconv.Atoi(r1.row)
+	rw2, _ = strconv.Atoi(r2.row)
+	if c1 > c2 {
+		c1, c2 = c2, c1
+	}
+	if rw1 > rw2 {
+		rw1, rw2 = rw2, rw1
+	}
+	return c1, c2, rw1, rw2
+}
+
+// moveRefWords updates the range r1:r2 after the range described by q is
+// moved.  References within the moved range follow it, including their
+// absolute parts, and references within the cells it's moved over are
+// removed.  Other references, including whole rows and columns, are left
+// unchanged.
+func moveRefWords(r1, r2 *refWord, q *update.UpdateQuery) bool {
+	if r1.col == "" || r1.row == "" {
+		return true
+	}
+	c1, c2, rw1, rw2 := refBounds(r1, r2)
+	fromCol, fromRow := int(q.ColumnIdx), int(q.RowIdx)
+	toCol, toRow := fromCol+int(q.Columns)-1, fromRow+int(q.Rows)-1
+	within := func(colOffset, rowOffset int) bool {
+		return c1 >= fromCol+colOffset && c2 <= toCol+colOffset && rw1 >= fromRow+rowOffset && rw2 <= toRow+rowOffset
+	}
+	switch {
+	case within(0, 0):
+		for _, r := range []*refWord{r1, r2} {
+			idx := int(reference.ColumnToIndex(strings.ToUpper(r.col))) + q.ColumnOffset
+			n, _ := strconv.Atoi(r.row)
+			if idx < 0 || idx > maxColumnIdx || n+q.RowOffset < 1 || n+q.RowOffset > maxRowIdx {
+				return false
+			}
+			r.col = reference.IndexToColumn(uint32(idx))
+			r.row = strconv.Itoa(n + q.RowOffset)
+		}
+	case within(q.ColumnOffset, q.RowOffset):
+		return false
+	}
+	return true
+}
+
+// updateCellRef updates a single cell reference, returning #REF! if the cell
+// is removed.
+func updateCellRef(ref string, q *update.UpdateQuery) string {
+	r, ok := parseRefWord(ref)
+	if !ok || r.col == "" || r.row == "" || !validRefWord(r) {
+		return "#REF!"
+	}
+	if !updateRefWords(&r, nil, q) {
+		return "#REF!"
+	}
+	return r.String()
+}
+
+// updateRangeExpr updates the range from:to, returning a #REF! reference if
+// it's removed.  Ranges which aren't between two references are updated one
+// end at a time.
+func updateRangeExpr(from, to Expression, q *update.UpdateQuery) Expression {
+	a, aok := from.(CellRef)
+	b, bok := to.(CellRef)
+	if !aok || !bok {
+		return Range{from.Update(q), to.Update(q)}
+	}
+	r1, ok1 := parseRefWord(a._bda)
+	r2, ok2 := parseRefWord(b._bda)
+	if !ok1 || !ok2 || !sameShape(r1, r2) || r1.col == "" || r1.row == "" {
+		return Range{from.Update(q), to.Update(q)}
+	}
+	if !updateRefWords(&r1, &r2, q) {
+		return CellRef{"#REF!"}
+	}
+	return Range{CellRef{r1.String()}, CellRef{r2.String()}}
+}
+
+// updateSheetExpr updates the references of an expression prefixed by a
+// sheet if it's the sheet updated by q.
+func updateSheetExpr(pfx Expression, q *update.UpdateQuery) (*update.UpdateQuery, bool) {
+	if q.UpdateType != update.UpdateActionCopy && !updatesSheet(pfx.String(), q) {
+		return nil, false
+	}
+	sq := *q
+	sq.UpdateCurrentSheet = true
+	return &sq, true
+}
+
+// updatePrefixRange updates a range prefixed by a sheet.
+func updatePrefixRange(r PrefixRangeExpr, q *update.UpdateQuery) Expression {
+	sq, ok := updateSheetExpr(r._cgegf, q)
+	if !ok {
+		return r
+	}
+	switch e := updateRangeExpr(r._gaga, r._dbaf, sq).(type) {
+	case Range:
+		r._gaga, r._dbaf = e._eadcf, e._dfbgf
+		return r
+	default:
+		return PrefixExpr{r._cgegf, e}
+	}
+}
+
+// updateVerticalRange updates a range of whole columns.
+func updateVerticalRange(r VerticalRange, q *update.UpdateQuery) Expression {
+	if !q.UpdateCurrentSheet {
+		return r
+	}
+	from, to, ok := updateColumnRange(r._efdad, r._ddbe, q)
+	if !ok {
+		return CellRef{"#REF!"}
+	}
+	r._efdad, r._ddbe = from, to
+	return r
+}
+
+// updatePrefixVerticalRange updates a range of whole columns prefixed by a
+// sheet.
+func updatePrefixVerticalRange(r PrefixVerticalRange, q *update.UpdateQuery) Expression {
+	sq, ok := updateSheetExpr(r._edebe, q)
+	if !ok {
+		return r
+	}
+	from, to, ok := updateColumnRange(r._bgcag, r._gadad, sq)
+	if !ok {
+		return PrefixExpr{r._edebe, CellRef{"#REF!"}}
+	}
+	r._bgcag, r._gadad = from, to
+	return r
+}
+
+// updateHorizontalRange updates a range of whole rows.
+func updateHorizontalRange(r HorizontalRange, q *update.UpdateQuery) Expression {
+	if !q.UpdateCurrentSheet {
+		return r
+	}
+	from, to, ok := updateRowRange(r._ddgg, r._aabf, r.absFrom, r.absTo, q)
+	if !ok {
+		return CellRef{"#REF!"}
+	}
+	r._ddgg, r._aabf = from, to
+	return r
+}
+
+// updatePrefixHorizontalRange updates a range of whole rows prefixed by a
+// sheet.
+func updatePrefixHorizontalRange(r PrefixHorizontalRange, q *update.UpdateQuery) Expression {
+	sq, ok := updateSheetExpr(r._eegae, q)
+	if !ok {
+		return r
+	}
+	from, to, ok := updateRowRange(r._gfad, r._cfaf, r.absFrom, r.absTo, sq)
+	if !ok {
+		return PrefixExpr{r._eegae, CellRef{"#REF!"}}
+	}
+	r._gfad, r._cfaf = from, to
+	return r
+}
+
+// updateColumnRange updates the columns of a range of whole columns (e.g.
+// A:C), returning false if they're removed.
+func updateColumnRange(from, to string, q *update.UpdateQuery) (string, string, bool) {
+	r1, ok1 := parseRefWord(from)
+	r2, ok2 := parseRefWord(to)
+	if !ok1 || !ok2 || r1.col == "" || r2.col == "" {
+		return from, to, true
+	}
+	r1.row, r2.row = "", ""
+	if !updateRefWords(&r1, &r2, q) {
+		return from, to, false
+	}
+	return r1.String(), r2.String(), true
+}
+
+// updateRowRange updates the rows of a range of whole rows (e.g. 1:3),
+// returning false if they're removed.
+func updateRowRange(from, to int, absFrom, absTo bool, q *update.UpdateQuery) (int, int, bool) {
+	switch q.UpdateType {
+	case update.UpdateActionRemoveRow, update.UpdateActionInsertRow, update.UpdateActionCopy:
+	default:
+		return from, to, true
+	}
+	r1 := refWord{row: strconv.Itoa(from), absRow: absFrom}
+	r2 := refWord{row: strconv.Itoa(to), absRow: absTo}
+	if !updateRefWords(&r1, &r2, q) {
+		return from, to, false
+	}
+	from, _ = strconv.Atoi(r1.row)
+	to, _ = strconv.Atoi(r2.row)
+	return from, to, true
+}
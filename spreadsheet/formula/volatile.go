@@ -0,0 +1,93 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package formula
+
+import (
+	"math/rand"
+	"time"
+)
+
+func init() {
+	RegisterFunctionComplex("NOW", inEpoch(volatileNow, 0, true))
+	RegisterFunctionComplex("TODAY", inEpoch(volatileToday, 0, true))
+	RegisterFunctionComplex("RAND", volatileRand)
+	RegisterFunctionComplex("RANDBETWEEN", volatileRandBetween)
+}
+
+// VolatileContext is implemented by contexts which supply the time and the
+// random numbers used by NOW, TODAY, RAND and RANDBETWEEN.  Without it these
+// functions use the current time and a shared source of random numbers.
+type VolatileContext interface {
+	// Now returns the time of the calculation.
+	Now() time.Time
+	// Rand returns the source of random numbers for the cell being
+	// evaluated.  It is only used by one formula at a time.
+	Rand() *rand.Rand
+}
+
+// volatileContext returns the VolatileContext of a context, looking through
+// the contexts of LET and LAMBDA.
+func volatileContext(ctx Context) (VolatileContext, bool) {
+	for {
+		switch c := ctx.(type) {
+		case VolatileContext:
+			return c, true
+		case *letContext:
+			ctx = c.Context
+		default:
+			return nil, false
+		}
+	}
+}
+
+func volatileNow(ctx Context, ev Evaluator, args []Result) Result {
+	vc, ok := volatileContext(ctx)
+	if !ok || len(args) > 0 {
+		return Now(args)
+	}
+	t := vc.Now()
+	_, offset := t.Zone()
+	return MakeNumberResult(_fff + float64(t.Unix()+int64(offset))/86400)
+}
+
+func volatileToday(ctx Context, ev Evaluator, args []Result) Result {
+	vc, ok := volatileContext(ctx)
+	if !ok || len(args) > 0 {
+		return Today(args)
+	}
+	t := vc.Now()
+	_, offset := t.Zone()
+	return MakeNumberResult(_aeac(_ccf, t.Unix()+int64(offset)) + 1)
+}
+
+func volatileRand(ctx Context, ev Evaluator, args []Result) Result {
+	vc, ok := volatileContext(ctx)
+	if !ok || len(args) != 0 {
+		return Rand(args)
+	}
+	return MakeNumberResult(vc.Rand().Float64())
+}
+
+func volatileRandBetween(ctx Context, ev Evaluator, args []Result) Result {
+	vc, ok := volatileContext(ctx)
+	if !ok {
+		return RandBetween(args)
+	}
+	if len(args) != 2 {
+		return MakeErrorResult("RANDBETWEEN() requires two numeric arguments")
+	}
+	from, to := args[0].AsNumber(), args[1].AsNumber()
+	if from.Type != ResultTypeNumber || to.Type != ResultTypeNumber {
+		return MakeErrorResult("RANDBETWEEN() requires two numeric arguments")
+	}
+	if to.ValueNumber < from.ValueNumber {
+		return MakeErrorResult("RANDBETWEEN() requires second argument to be larger")
+	}
+	lo, hi := int64(from.ValueNumber), int64(to.ValueNumber)
+	return MakeNumberResult(float64(vc.Rand().Int63n(hi-lo+1) + lo))
+}
@@ -0,0 +1,571 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/unidoc/unioffice/schema/soo/ofc/sharedTypes"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+)
+
+// HTMLOption is an option for rendering a sheet to HTML.
+type HTMLOption func(*htmlOptions)
+
+type htmlOptions struct {
+	ref   string
+	class string
+}
+
+// WithHTMLRange renders only a range of the sheet (e.g. "A1:D20") rather
+// than all of its used cells.
+func WithHTMLRange(ref string) HTMLOption {
+	return func(o *htmlOptions) { o.ref = ref }
+}
+
+// WithHTMLClass sets the class attribute of the rendered table.
+func WithHTMLClass(class string) HTMLOption {
+	return func(o *htmlOptions) { o.class = class }
+}
+
+// htmlStyle is a cell style translated to CSS.
+type htmlStyle struct {
+	css string
+	// aligned is true if the style sets the horizontal alignment rather than
+	// leaving it to depend on the value.
+	aligned  bool
+	rotation uint8
+}
+
+// RenderHTML writes the used cells of the sheet as an HTML table.  Cells show
+// their formatted values and their styles are translated to inline CSS,
+// along with the colors of conditional formatting.  Merged cells span rows
+// and columns, column widths and row heights are kept and hidden rows and
+// columns are left out.
+func (s *Sheet) RenderHTML(w io.Writer, opts ...HTMLOption) error {
+	o := &htmlOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	a, ok := s.usedArea()
+	if o.ref != "" {
+		if a, ok = s.areaOf(o.ref); !ok {
+			return fmt.Errorf("invalid range %s", o.ref)
+		}
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString("<table")
+	if o.class != "" {
+		fmt.Fprintf(bw, ` class="%s"`, html.EscapeString(o.class))
+	}
+	if !ok {
+		bw.WriteString(` style="border-collapse:collapse"></table>` + "\n")
+		return bw.Flush()
+	}
+
+	cols := []uint32{}
+	widths := map[uint32]int{}
+	total := 0
+	for col := a.col1; col <= a.col2; col++ {
+		c := s.colInfo(col)
+		if c != nil && c.HiddenAttr != nil && *c.HiddenAttr {
+			continue
+		}
+		cols = append(cols, col)
+		widths[col] = s.columnPixels(c)
+		total += widths[col]
+	}
+	rows := map[uint32]*sml.CT_Row{}
+	for _, r := range s._bcgb.SheetData.Row {
+		if r.RAttr != nil && *r.RAttr >= a.row1 && *r.RAttr <= a.row2 {
+			rows[*r.RAttr] = r
+		}
+	}
+	hiddenRow := func(rn uint32) bool {
+		if r, ok := rows[rn]; ok {
+			return r.HiddenAttr != nil && *r.HiddenAttr
+		}
+		f := s._bcgb.SheetFormatPr
+		return f != nil && f.ZeroHeightAttr != nil && *f.ZeroHeightAttr
+	}
+	visibleCols := map[uint32]bool{}
+	for _, col := range cols {
+		visibleCols[col] = true
+	}
+
+	// merged cells span the visible rows and columns they cover from their
+	// first visible cell, and the other cells they cover are left out
+	spans := map[cellKey][2]int{}
+	covered := map[cellKey]bool{}
+	for _, m := range s.MergedCells() {
+		ma, ok := s.areaOf(m.Reference())
+		if !ok || !ma.overlaps(a) {
+			continue
+		}
+		ma.col1, ma.col2 = maxUint32(ma.col1, a.col1), minUint32(ma.col2, a.col2)
+		ma.row1, ma.row2 = maxUint32(ma.row1, a.row1), minUint32(ma.row2, a.row2)
+		var anchor *cellKey
+		nrows, ncols := 0, 0
+		for col := ma.col1; col <= ma.col2; col++ {
+			if visibleCols[col] {
+				ncols++
+			}
+		}
+		for row := ma.row1; row <= ma.row2; row++ {
+			if hiddenRow(row) {
+				continue
+			}
+			nrows++
+			for col := ma.col1; col <= ma.col2; col++ {
+				k := cellKey{s._bcgb, col, row}
+				if anchor == nil && visibleCols[col] {
+					anchor = &k
+				}
+				covered[k] = true
+			}
+		}
+		if anchor != nil {
+			delete(covered, *anchor)
+			spans[*anchor] = [2]int{nrows, ncols}
+		}
+	}
+
+	fmt.Fprintf(bw, ` style="border-collapse:collapse;table-layout:fixed;width:%dpx">`+"\n", total)
+	bw.WriteString("<colgroup>")
+	for _, col := range cols {
+		fmt.Fprintf(bw, `<col style="width:%dpx">`, widths[col])
+	}
+	bw.WriteString("</colgroup>\n")
+
+	cf := s.ConditionalFormatter()
+	styles := map[uint32]htmlStyle{}
+	for rn := a.row1; rn <= a.row2; rn++ {
+		if hiddenRow(rn) {
+			continue
+		}
+		r := rows[rn]
+		cells := map[uint32]*sml.CT_Cell{}
+		if r != nil {
+			for _, c := range r.C {
+				cells[columnOf(c)] = c
+			}
+		}
+		fmt.Fprintf(bw, `<tr style="height:%spx">`, cssNumber(s.rowPoints(r)*4/3))
+		for _, col := range cols {
+			k := cellKey{s._bcgb, col, rn}
+			if covered[k] {
+				continue
+			}
+			c := Cell{s._bdb, s, r, cells[col]}
+			xf := s.styleIndex(r, c._dbd, col)
+			st, ok := styles[xf]
+			if !ok {
+				st = s._bdb.htmlStyle(xf)
+				styles[xf] = st
+			}
+			bw.WriteString("<td")
+			if span, ok := spans[k]; ok {
+				if span[0] > 1 {
+					fmt.Fprintf(bw, ` rowspan="%d"`, span[0])
+				}
+				if span[1] > 1 {
+					fmt.Fprintf(bw, ` colspan="%d"`, span[1])
+				}
+			}
+			text, css := s.htmlCell(c, col, rn, st, cf)
+			fmt.Fprintf(bw, ` style="%s">`, html.EscapeString(css))
+			text = html.EscapeString(text)
+			switch {
+			case st.rotation == 255:
+				text = `<div style="writing-mode:vertical-rl;text-orientation:upright">` + text + "</div>"
+			case st.rotation > 90 && st.rotation <= 180:
+				text = fmt.Sprintf(`<div style="transform:rotate(%ddeg)">%s</div>`, st.rotation-90, text)
+			case st.rotation > 0 && st.rotation <= 90:
+				text = fmt.Sprintf(`<div style="transform:rotate(-%ddeg)">%s</div>`, st.rotation, text)
+			}
+			bw.WriteString(text)
+			bw.WriteString("</td>")
+		}
+		bw.WriteString("</tr>\n")
+	}
+	bw.WriteString("</table>\n")
+	return bw.Flush()
+}
+
+// htmlCell returns the text of a cell, which may not exist, and its CSS with
+// the alignment that depends on its value, the color of its number format
+// and its conditional formatting, where data bars become gradients.
+func (s *Sheet) htmlCell(c Cell, col, row uint32, st htmlStyle, cf *ConditionalFormatter) (string, string) {
+	text, css := "", st.css
+	v := formula.MakeEmptyResult()
+	if c._dbd != nil {
+		v = cellValue(c)
+		res := c.GetFormattedResult()
+		text = res.Text
+		if !res.Color.IsAuto() {
+			css += ";color:#" + strings.ToUpper(*res.Color.AsRGBString())
+		}
+	}
+	if !st.aligned {
+		switch {
+		case c._dbd != nil && (c._dbd.TAttr == sml.ST_CellTypeB || c._dbd.TAttr == sml.ST_CellTypeE):
+			css += ";text-align:center"
+		case v.Type == formula.ResultTypeNumber:
+			css += ";text-align:right"
+		default:
+			css += ";text-align:left"
+		}
+	}
+	res := cf.format(c, col, row)
+	if !res.Color.IsAuto() {
+		css += ";background:#" + strings.ToUpper(*res.Color.AsRGBString())
+	}
+	if !res.DataBarColor.IsAuto() {
+		css += fmt.Sprintf(";background-image:linear-gradient(to right,#%s %s%%,transparent %[2]s%%)",
+			strings.ToUpper(*res.DataBarColor.AsRGBString()), cssNumber(res.DataBar*100))
+	}
+	if res.HideValue {
+		text = ""
+	}
+	// apply the styles of the rules with the highest priority last so that
+	// they win
+	for i := len(res.Styles) - 1; i >= 0; i-- {
+		d := res.Styles[i].X()
+		if d.NumFmt != nil && v.Type == formula.ResultTypeNumber {
+			text = s._bdb.Locale().NumberResult(v.ValueNumber, d.NumFmt.FormatCodeAttr).Text
+		}
+		if d.Font != nil {
+			css += s._bdb.fontCSS(d.Font)
+		}
+		if d.Fill != nil {
+			css += s._bdb.fillCSS(d.Fill, true)
+		}
+		if d.Border != nil {
+			css += s._bdb.borderCSS(d.Border)
+		}
+	}
+	return text, css
+}
+
+// colInfo returns the column information of a zero based column index, or
+// nil if there is none.
+func (s *Sheet) colInfo(col uint32) *sml.CT_Col {
+	for _, cs := range s._bcgb.Cols {
+		for _, c := range cs.Col {
+			if col+1 >= c.MinAttr && col+1 <= c.MaxAttr {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// columnPixels returns the width of a column in pixels, where a character is
+// seven pixels wide as with the default font.
+func (s *Sheet) columnPixels(c *sml.CT_Col) int {
+	if c != nil && c.WidthAttr != nil {
+		return int(math.Floor(*c.WidthAttr*7 + 0.5))
+	}
+	f := s._bcgb.SheetFormatPr
+	if f != nil && f.DefaultColWidthAttr != nil {
+		return int(math.Floor(*f.DefaultColWidthAttr*7 + 0.5))
+	}
+	base := 8
+	if f != nil && f.BaseColWidthAttr != nil {
+		base = int(*f.BaseColWidthAttr)
+	}
+	// the base width has five pixels of padding and is rounded up to a
+	// multiple of eight
+	return (base*7 + 5 + 7) / 8 * 8
+}
+
+// rowPoints returns the height of a row in points.
+func (s *Sheet) rowPoints(r *sml.CT_Row) float64 {
+	if r != nil && r.HtAttr != nil {
+		return *r.HtAttr
+	}
+	if f := s._bcgb.SheetFormatPr; f != nil && f.DefaultRowHeightAttr > 0 {
+		return f.DefaultRowHeightAttr
+	}
+	return 15
+}
+
+// styleIndex returns the style of a cell, which is the style of its row or
+// column if the cell doesn't exist.
+func (s *Sheet) styleIndex(r *sml.CT_Row, c *sml.CT_Cell, col uint32) uint32 {
+	switch {
+	case c != nil && c.SAttr != nil:
+		return *c.SAttr
+	case c != nil:
+		return 0
+	case r != nil && r.SAttr != nil && r.CustomFormatAttr != nil && *r.CustomFormatAttr:
+		return *r.SAttr
+	}
+	if ci := s.colInfo(col); ci != nil && ci.StyleAttr != nil {
+		return *ci.StyleAttr
+	}
+	return 0
+}
+
+// htmlStyle translates a cell style to CSS.
+func (wb *Workbook) htmlStyle(i uint32) htmlStyle {
+	st := htmlStyle{}
+	ss := wb.StyleSheet.X()
+	if ss.CellXfs == nil || i >= uint32(len(ss.CellXfs.Xf)) {
+		st.css = "vertical-align:bottom;white-space:pre;overflow:hidden"
+		return st
+	}
+	xf := ss.CellXfs.Xf[i]
+	b := strings.Builder{}
+	if xf.FontIdAttr != nil && ss.Fonts != nil && *xf.FontIdAttr < uint32(len(ss.Fonts.Font)) {
+		b.WriteString(wb.fontCSS(ss.Fonts.Font[*xf.FontIdAttr]))
+	}
+	if xf.FillIdAttr != nil && ss.Fills != nil && *xf.FillIdAttr < uint32(len(ss.Fills.Fill)) {
+		b.WriteString(wb.fillCSS(ss.Fills.Fill[*xf.FillIdAttr], false))
+	}
+	if xf.BorderIdAttr != nil && ss.Borders != nil && *xf.BorderIdAttr < uint32(len(ss.Borders.Border)) {
+		b.WriteString(wb.borderCSS(ss.Borders.Border[*xf.BorderIdAttr]))
+	}
+	al := xf.Alignment
+	if al == nil {
+		al = sml.NewCT_CellAlignment()
+	}
+	if h := horizontalCSS[al.HorizontalAttr]; h != "" {
+		st.aligned = true
+		b.WriteString(";text-align:" + h)
+	}
+	if v := verticalCSS[al.VerticalAttr]; v != "" {
+		b.WriteString(";vertical-align:" + v)
+	} else {
+		b.WriteString(";vertical-align:bottom")
+	}
+	if al.IndentAttr != nil && *al.IndentAttr > 0 {
+		side := "left"
+		if al.HorizontalAttr == sml.ST_HorizontalAlignmentRight {
+			side = "right"
+		}
+		fmt.Fprintf(&b, ";padding-%s:%dpx", side, *al.IndentAttr*9)
+	}
+	if al.WrapTextAttr != nil && *al.WrapTextAttr {
+		b.WriteString(";white-space:pre-wrap;overflow-wrap:break-word")
+	} else {
+		b.WriteString(";white-space:pre;overflow:hidden")
+	}
+	if al.TextRotationAttr != nil {
+		st.rotation = *al.TextRotationAttr
+	}
+	st.css = strings.TrimPrefix(b.String(), ";")
+	return st
+}
+
+var horizontalCSS = map[sml.ST_HorizontalAlignment]string{
+	sml.ST_HorizontalAlignmentLeft:             "left",
+	sml.ST_HorizontalAlignmentCenter:           "center",
+	sml.ST_HorizontalAlignmentRight:            "right",
+	sml.ST_HorizontalAlignmentFill:             "left",
+	sml.ST_HorizontalAlignmentJustify:          "justify",
+	sml.ST_HorizontalAlignmentCenterContinuous: "center",
+	sml.ST_HorizontalAlignmentDistributed:      "justify",
+}
+
+var verticalCSS = map[sml.ST_VerticalAlignment]string{
+	sml.ST_VerticalAlignmentTop:         "top",
+	sml.ST_VerticalAlignmentCenter:      "middle",
+	sml.ST_VerticalAlignmentBottom:      "bottom",
+	sml.ST_VerticalAlignmentJustify:     "middle",
+	sml.ST_VerticalAlignmentDistributed: "middle",
+}
+
+// fontCSS returns CSS declarations for the properties a font sets, each
+// preceded by a semicolon.
+func (wb *Workbook) fontCSS(f *sml.CT_Font) string {
+	b := strings.Builder{}
+	if len(f.Name) > 0 && f.Name[0].ValAttr != "" {
+		fmt.Fprintf(&b, ";font-family:'%s'", strings.Replace(f.Name[0].ValAttr, "'", "", -1))
+	}
+	if len(f.Sz) > 0 && f.Sz[0].ValAttr > 0 {
+		fmt.Fprintf(&b, ";font-size:%spt", cssNumber(f.Sz[0].ValAttr))
+	}
+	if len(f.B) > 0 {
+		if on(f.B[0]) {
+			b.WriteString(";font-weight:bold")
+		} else {
+			b.WriteString(";font-weight:normal")
+		}
+	}
+	if len(f.I) > 0 {
+		if on(f.I[0]) {
+			b.WriteString(";font-style:italic")
+		} else {
+			b.WriteString(";font-style:normal")
+		}
+	}
+	decoration := []string{}
+	if len(f.U) > 0 {
+		switch f.U[0].ValAttr {
+		case sml.ST_UnderlineValuesNone:
+		case sml.ST_UnderlineValuesDouble, sml.ST_UnderlineValuesDoubleAccounting:
+			decoration = append(decoration, "underline double")
+		default:
+			decoration = append(decoration, "underline")
+		}
+	}
+	if len(f.Strike) > 0 && on(f.Strike[0]) {
+		decoration = append(decoration, "line-through")
+	}
+	if len(f.U) > 0 || len(f.Strike) > 0 {
+		if len(decoration) == 0 {
+			decoration = append(decoration, "none")
+		}
+		b.WriteString(";text-decoration:" + strings.Join(decoration, " "))
+	}
+	if len(f.Color) > 0 {
+		if v, ok := wb.rgb(f.Color[0]); ok {
+			fmt.Fprintf(&b, ";color:#%06X", v)
+		}
+	}
+	if len(f.VertAlign) > 0 {
+		switch f.VertAlign[0].ValAttr {
+		case sharedTypes.ST_VerticalAlignRunSuperscript:
+			b.WriteString(";vertical-align:super")
+		case sharedTypes.ST_VerticalAlignRunSubscript:
+			b.WriteString(";vertical-align:sub")
+		}
+	}
+	return b.String()
+}
+
+// on returns the value of a boolean font property, which is true if it has
+// no value.
+func on(p *sml.CT_BooleanProperty) bool {
+	return p.ValAttr == nil || *p.ValAttr
+}
+
+// patternDensity is the fraction of a cell that the foreground color of a
+// pattern fill covers.
+var patternDensity = map[sml.ST_PatternType]float64{
+	sml.ST_PatternTypeSolid:      1,
+	sml.ST_PatternTypeDarkGray:   0.75,
+	sml.ST_PatternTypeMediumGray: 0.5,
+	sml.ST_PatternTypeLightGray:  0.25,
+	sml.ST_PatternTypeGray125:    0.125,
+	sml.ST_PatternTypeGray0625:   0.0625,
+}
+
+// fillCSS returns CSS declarations for a fill.  Patterns become the average
+// of their colors.  Solid fills of differential styles use their background
+// color rather than their foreground color.
+func (wb *Workbook) fillCSS(f *sml.CT_Fill, dxf bool) string {
+	if g := f.GradientFill; g != nil && len(g.Stop) > 0 {
+		stops := []string{}
+		for _, s := range g.Stop {
+			v, _ := wb.rgb(s.Color)
+			stops = append(stops, fmt.Sprintf("#%06X %s%%", v, cssNumber(s.PositionAttr*100)))
+		}
+		if g.TypeAttr == sml.ST_GradientTypePath {
+			return ";background:radial-gradient(" + strings.Join(stops, ",") + ")"
+		}
+		deg := 0.0
+		if g.DegreeAttr != nil {
+			deg = *g.DegreeAttr
+		}
+		return fmt.Sprintf(";background:linear-gradient(%sdeg,%s)", cssNumber(deg+90), strings.Join(stops, ","))
+	}
+	p := f.PatternFill
+	if p == nil {
+		return ""
+	}
+	if dxf && (p.PatternTypeAttr == sml.ST_PatternTypeUnset || p.PatternTypeAttr == sml.ST_PatternTypeSolid) {
+		c := p.BgColor
+		if c == nil {
+			c = p.FgColor
+		}
+		if v, ok := wb.rgb(c); ok {
+			return fmt.Sprintf(";background:#%06X", v)
+		}
+		return ""
+	}
+	if p.PatternTypeAttr == sml.ST_PatternTypeUnset || p.PatternTypeAttr == sml.ST_PatternTypeNone {
+		return ""
+	}
+	fg, ok := wb.rgb(p.FgColor)
+	if !ok {
+		fg = 0
+	}
+	bg, ok := wb.rgb(p.BgColor)
+	if !ok {
+		bg = 0xFFFFFF
+	}
+	d, ok := patternDensity[p.PatternTypeAttr]
+	if !ok {
+		d = 0.5
+	}
+	return fmt.Sprintf(";background:#%06X", mixRGB(bg, fg, d))
+}
+
+// borderCSS returns CSS declarations for the sides of a border.
+func (wb *Workbook) borderCSS(bd *sml.CT_Border) string {
+	b := strings.Builder{}
+	sides := []struct {
+		name string
+		pr   *sml.CT_BorderPr
+	}{{"top", bd.Top}, {"right", bd.Right}, {"bottom", bd.Bottom}, {"left", bd.Left}}
+	for _, side := range sides {
+		if side.pr == nil {
+			continue
+		}
+		style, ok := borderStyleCSS[side.pr.StyleAttr]
+		if !ok {
+			continue
+		}
+		v, _ := wb.rgb(side.pr.Color)
+		fmt.Fprintf(&b, ";border-%s:%s #%06X", side.name, style, v)
+	}
+	return b.String()
+}
+
+var borderStyleCSS = map[sml.ST_BorderStyle]string{
+	sml.ST_BorderStyleThin:             "1px solid",
+	sml.ST_BorderStyleMedium:           "2px solid",
+	sml.ST_BorderStyleDashed:           "1px dashed",
+	sml.ST_BorderStyleDotted:           "1px dotted",
+	sml.ST_BorderStyleThick:            "3px solid",
+	sml.ST_BorderStyleDouble:           "3px double",
+	sml.ST_BorderStyleHair:             "1px dotted",
+	sml.ST_BorderStyleMediumDashed:     "2px dashed",
+	sml.ST_BorderStyleDashDot:          "1px dashed",
+	sml.ST_BorderStyleMediumDashDot:    "2px dashed",
+	sml.ST_BorderStyleDashDotDot:       "1px dotted",
+	sml.ST_BorderStyleMediumDashDotDot: "2px dotted",
+	sml.ST_BorderStyleSlantDashDot:     "2px dashed",
+}
+
+// cssNumber formats a number for CSS with at most two decimals.
+func cssNumber(v float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
+
+func minUint32(a, b uint32) uint32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxUint32(a, b uint32) uint32 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,143 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/unidoc/unioffice/color"
+	"github.com/unidoc/unioffice/measurement"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+)
+
+// htmlDefault is the CSS of the default cell style.
+const htmlDefault = "font-family:&#39;Calibri&#39;;font-size:11pt;vertical-align:bottom;white-space:pre;overflow:hidden"
+
+// htmlWorkbook returns a sheet with a styled cell, a merged cell, a hidden
+// row and column and a conditional fill.
+func htmlWorkbook() Sheet {
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetString(`<b>&"x"`)
+	s.Cell("B1").SetNumber(1234.5)
+	cs := wb.StyleSheet.AddCellStyle()
+	f := wb.StyleSheet.AddFont()
+	f.SetName("Arial")
+	f.SetSize(12)
+	f.SetBold(true)
+	f.SetColor(color.Red)
+	cs.SetFont(f)
+	fill := wb.StyleSheet.Fills().AddFill()
+	pf := fill.SetPatternFill()
+	pf.SetPattern(sml.ST_PatternTypeSolid)
+	pf.SetFgColor(color.Yellow)
+	cs.SetFill(fill)
+	b := wb.StyleSheet.AddBorder()
+	b.SetBottom(sml.ST_BorderStyleThin, color.Black)
+	b.SetLeft(sml.ST_BorderStyleDouble, color.Blue)
+	cs.SetBorder(b)
+	cs.SetNumberFormat("#,##0.00")
+	s.Cell("B1").SetStyle(cs)
+
+	s.Cell("C1").SetString("hidden column")
+	s.Column(3).SetHidden(true)
+	s.Column(1).SetWidth(10 * measurement.Character)
+	s.Cell("D1").SetNumber(150)
+	s.Cell("D2").SetNumber(50)
+	neg := wb.StyleSheet.AddCellStyle()
+	neg.SetNumberFormat("0;[Red]-0")
+	s.Cell("E1").SetNumber(-5)
+	s.Cell("E1").SetStyle(neg)
+	s.Cell("E2").SetBool(true)
+	s.Cell("A2").SetString("merged")
+	s.AddMergedCells("A2", "B3")
+	s.Row(2).SetHeight(30 * measurement.Point)
+	s.Cell("A4").SetString("hidden row")
+	s.Row(4).SetHidden(true)
+	s.Cell("A5").SetString("last")
+
+	cf := s.AddConditionalFormatting([]string{"D1:D2"})
+	r := cf.AddRule()
+	r.SetType(sml.ST_CfTypeCellIs)
+	r.SetOperator(sml.ST_ConditionalFormattingOperatorGreaterThan)
+	r.SetConditionValue("100")
+	d := wb.StyleSheet.AddDifferentialStyle()
+	d.Fill().SetPatternFill().SetBgColor(color.Green)
+	r.SetStyle(d)
+	return s
+}
+
+func renderHTML(t *testing.T, s Sheet, opts ...HTMLOption) string {
+	t.Helper()
+	buf := bytes.Buffer{}
+	if err := s.RenderHTML(&buf, opts...); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestRenderHTML(t *testing.T) {
+	styled := "font-family:&#39;Arial&#39;;font-size:12pt;font-weight:bold;color:#FF0000;background:#FFFF00;" +
+		"border-bottom:1px solid #000000;border-left:3px double #0000FF;" +
+		"vertical-align:bottom;white-space:pre;overflow:hidden;text-align:right"
+	exp := `<table class="a&#34;b" style="border-collapse:collapse;table-layout:fixed;width:262px">` + "\n" +
+		`<colgroup><col style="width:70px"><col style="width:64px"><col style="width:64px"><col style="width:64px"></colgroup>` + "\n" +
+		`<tr style="height:20px">` +
+		`<td style="` + htmlDefault + `;text-align:left">&lt;b&gt;&amp;&#34;x&#34;</td>` +
+		`<td style="` + styled + `">1,234.50</td>` +
+		`<td style="` + htmlDefault + `;text-align:right;background:#008000">150</td>` +
+		`<td style="vertical-align:bottom;white-space:pre;overflow:hidden;color:#FF0000;text-align:right">-5</td>` +
+		"</tr>\n" +
+		`<tr style="height:40px">` +
+		`<td rowspan="2" colspan="2" style="` + htmlDefault + `;text-align:left">merged</td>` +
+		`<td style="` + htmlDefault + `;text-align:right">50</td>` +
+		`<td style="` + htmlDefault + `;text-align:center">TRUE</td>` +
+		"</tr>\n" +
+		`<tr style="height:20px">` +
+		`<td style="` + htmlDefault + `;text-align:left"></td>` +
+		`<td style="` + htmlDefault + `;text-align:left"></td>` +
+		"</tr>\n" +
+		`<tr style="height:20px">` +
+		`<td style="` + htmlDefault + `;text-align:left">last</td>` +
+		strings.Repeat(`<td style="`+htmlDefault+`;text-align:left"></td>`, 3) +
+		"</tr>\n" +
+		"</table>\n"
+	if got := renderHTML(t, htmlWorkbook(), WithHTMLClass(`a"b`)); got != exp {
+		t.Errorf("expected\n%s\ngot\n%s", exp, got)
+	}
+}
+
+func TestRenderHTMLRange(t *testing.T) {
+	s := htmlWorkbook()
+	// the merged cell is cut to the range, leaving a single cell
+	exp := `<table style="border-collapse:collapse;table-layout:fixed;width:128px">` + "\n" +
+		`<colgroup><col style="width:64px"><col style="width:64px"></colgroup>` + "\n" +
+		`<tr style="height:20px"><td style="`
+	got := renderHTML(t, s, WithHTMLRange("B1:D2"))
+	if !strings.HasPrefix(got, exp) {
+		t.Errorf("expected the prefix\n%s\ngot\n%s", exp, got)
+	}
+	row2 := `<tr style="height:40px">` +
+		`<td style="` + htmlDefault + `;text-align:left"></td>` +
+		`<td style="` + htmlDefault + `;text-align:right">50</td>` +
+		"</tr>\n"
+	if !strings.Contains(got, row2) || strings.Contains(got, "span") {
+		t.Errorf("expected the second row\n%s\ngot\n%s", row2, got)
+	}
+
+	buf := bytes.Buffer{}
+	if err := s.RenderHTML(&buf, WithHTMLRange("x")); err == nil {
+		t.Errorf("expected an error for an invalid range")
+	}
+	empty := New().AddSheet()
+	if got := renderHTML(t, empty); got != `<table style="border-collapse:collapse"></table>`+"\n" {
+		t.Errorf("unexpected table for an empty sheet %s", got)
+	}
+}
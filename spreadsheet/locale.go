@@ -0,0 +1,38 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import "github.com/unidoc/unioffice/spreadsheet/format"
+
+// SetLocale sets the locale used to display cell values and to parse text in
+// TEXT, VALUE and DATEVALUE.  The locale isn't saved with the workbook.
+func (wb *Workbook) SetLocale(l *format.Locale) { wb.locale = l }
+
+// Locale returns the locale of the workbook, which is English in the United
+// States unless set with SetLocale.
+func (wb *Workbook) Locale() *format.Locale {
+	if wb.locale == nil {
+		return format.EnglishUS
+	}
+	return wb.locale
+}
+
+// Locale returns the locale of the workbook for TEXT, VALUE and DATEVALUE.
+func (e *evalContext) Locale() *format.Locale { return e._afdd._bdb.Locale() }
+
+// localeFormat returns the number format of the cell, where the built-in
+// date and time formats are those of the locale.
+func (c Cell) localeFormat(l *format.Locale) string {
+	if c._dbd.SAttr != nil {
+		id := c._ebb.StyleSheet.GetCellStyle(*c._dbd.SAttr).NumberFormat()
+		if f, ok := l.FormatCode(id); ok {
+			return f
+		}
+	}
+	return c.getFormat()
+}
@@ -0,0 +1,62 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"testing"
+
+	"github.com/unidoc/unioffice/spreadsheet/format"
+)
+
+func TestWorkbookLocale(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	if wb.Locale() != format.EnglishUS {
+		t.Errorf("expected the default locale to be en-US, got %s", wb.Locale().Name)
+	}
+	de, _ := format.LocaleByName("de-DE")
+	wb.SetLocale(de)
+
+	date := wb.StyleSheet.AddCellStyle()
+	date.SetNumberFormatStandard(StandardFormat14)
+	number := wb.StyleSheet.AddCellStyle()
+	number.SetNumberFormat("#,##0.00")
+	dateTime := wb.StyleSheet.AddCellStyle()
+	dateTime.SetNumberFormatStandard(StandardFormat22)
+	s.Cell("A1").SetNumber(45000)
+	s.Cell("A1").SetStyle(date)
+	s.Cell("A2").SetNumber(1234.5)
+	s.Cell("A2").SetStyle(number)
+	s.Cell("A3").SetNumber(45000.5)
+	s.Cell("A3").SetStyle(dateTime)
+	s.Cell("A4").SetNumber(1.5)
+	s.Cell("A5").SetString("1.234,5")
+	s.Cell("B1").SetFormulaRaw(`TEXT(A2,"#,##0.00")`)
+	s.Cell("B2").SetFormulaRaw(`VALUE(A5)*2`)
+	s.Cell("B3").SetFormulaRaw(`DATEVALUE("15.03.2023")`)
+	s.Cell("B4").SetFormulaRaw(`TEXT(A1,"dddd")`)
+	if err := NewDependencyGraph(wb).Recalculate(); err != nil {
+		t.Fatal(err)
+	}
+	// the built-in date formats are those of the locale
+	checkCells(t, s, map[string]string{
+		"A1": "15.03.2023", "A2": "1.234,50", "A3": "15.03.2023 12:00", "A4": "1,5",
+		"B1": "1.234,50", "B2": "2469", "B3": "45000", "B4": "Mittwoch",
+	})
+
+	wb.SetLocale(nil)
+	checkCells(t, s, map[string]string{"A1": "3/15/2023", "A2": "1,234.50", "A3": "3/15/2023 12:00", "A4": "1.5"})
+
+	// the locale isn't saved, but the results of formulas are
+	wb.SetLocale(de)
+	rd := saveAndRead(t, wb)
+	if rd.Locale() != format.EnglishUS {
+		t.Errorf("expected the locale not to be saved, got %s", rd.Locale().Name)
+	}
+	checkCells(t, rd.Sheets()[0], map[string]string{"A1": "3/15/2023", "B1": "1.234,50", "B2": "2469"})
+}
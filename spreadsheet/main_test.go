@@ -0,0 +1,34 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// workbooks are saved without a license key in the tests
+	_bgab = true
+	os.Exit(m.Run())
+}
+
+// saveAndRead saves a workbook and reads it back.
+func saveAndRead(t *testing.T, wb *Workbook, opts ...SaveOption) *Workbook {
+	t.Helper()
+	buf := bytes.Buffer{}
+	if err := wb.Save(&buf, opts...); err != nil {
+		t.Fatalf("error saving: %s", err)
+	}
+	rd, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("error reading: %s", err)
+	}
+	return rd
+}
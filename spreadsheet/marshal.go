@@ -0,0 +1,652 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/unidoc/unioffice/measurement"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/format"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+)
+
+// UnmarshalError is returned by UnmarshalSheet when a cell can't be stored in
+// a field of a struct.
+type UnmarshalError struct {
+	Cell  reference.CellReference // the cell
+	Value string                  // the value of the cell
+	Field string                  // the name of the field
+	Type  reflect.Type            // the type of the field
+	Err   error                   // the error of a Scanner or TextUnmarshaler, if any
+}
+
+func (e *UnmarshalError) Error() string {
+	s := fmt.Sprintf("cell %s: cannot unmarshal %q into field %s of type %s", e.Cell.String(), e.Value, e.Field, e.Type)
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
+
+// sheetField is a field of a struct which maps to a column of a sheet.
+type sheetField struct {
+	index  []int
+	name   string
+	field  string
+	typ    reflect.Type
+	format string
+	width  float64
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	valuerType          = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType         = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// sheetFields returns the fields of a struct which map to columns, in order,
+// including those of embedded structs.
+func sheetFields(t reflect.Type, index []int) ([]sheetField, error) {
+	fields := []sheetField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, tagged := f.Tag.Lookup("xlsx")
+		if tag == "-" {
+			continue
+		}
+		idx := append(append([]int{}, index...), i)
+		if f.Anonymous && !tagged && f.Type.Kind() == reflect.Struct && !sheetValueType(f.Type) {
+			embedded, err := sheetFields(f.Type, idx)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, embedded...)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		sf := sheetField{index: idx, name: f.Name, field: f.Name, typ: f.Type}
+		if err := sf.parseTag(tag); err != nil {
+			return nil, fmt.Errorf("field %s: %s", f.Name, err)
+		}
+		if !sheetValueType(f.Type) {
+			return nil, fmt.Errorf("field %s has unsupported type %s", f.Name, f.Type)
+		}
+		fields = append(fields, sf)
+	}
+	return fields, nil
+}
+
+// parseTag parses an xlsx struct tag, which has the name of the column
+// followed by the options format and width, e.g. "Amount,format=#,##0.00".
+// The format may contain commas.
+func (f *sheetField) parseTag(tag string) error {
+	if tag == "" {
+		return nil
+	}
+	parts := strings.Split(tag, ",")
+	if parts[0] != "" {
+		f.name = parts[0]
+	}
+	opts := []string{}
+	for _, p := range parts[1:] {
+		switch {
+		case strings.HasPrefix(p, "format=") || strings.HasPrefix(p, "width="):
+			opts = append(opts, p)
+		case len(opts) > 0 && strings.HasPrefix(opts[len(opts)-1], "format="):
+			opts[len(opts)-1] += "," + p
+		default:
+			return fmt.Errorf("unknown option %q in tag", p)
+		}
+	}
+	for _, o := range opts {
+		kv := strings.SplitN(o, "=", 2)
+		switch kv[0] {
+		case "format":
+			f.format = kv[1]
+		case "width":
+			w, err := strconv.ParseFloat(kv[1], 64)
+			if err != nil || w < 0 {
+				return fmt.Errorf("invalid width %q in tag", kv[1])
+			}
+			f.width = w
+		}
+	}
+	return nil
+}
+
+// sheetValueType returns true for the types that can be stored in a cell.
+func sheetValueType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	pt := reflect.PtrTo(t)
+	switch {
+	case t == timeType:
+		return true
+	case (t.Implements(textMarshalerType) || pt.Implements(textMarshalerType)) && pt.Implements(textUnmarshalerType):
+		return true
+	case (t.Implements(valuerType) || pt.Implements(valuerType)) && pt.Implements(scannerType):
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// structType returns the struct type of the elements of a slice, which may
+// be pointers to structs.
+func structType(t reflect.Type) (reflect.Type, error) {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected a slice, got %s", t)
+	}
+	e := t.Elem()
+	if e.Kind() == reflect.Ptr {
+		e = e.Elem()
+	}
+	if e.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a slice of structs, got %s", t)
+	}
+	return e, nil
+}
+
+// MarshalSheet writes a slice of structs, or of pointers to structs, to a
+// sheet with a header row followed by a row per element.  The cells already
+// in those rows are removed first, so the rows only hold the columns of the
+// struct, while the rows below are left as they are.  Each exported field is a column, whose header is the
+// name of the field unless given by an xlsx struct tag, which also sets the
+// number format and the width in characters of the column, e.g.
+//
+//	Amount float64 `xlsx:"Amount,format=#,##0.00,width=12"`
+//
+// A tag of "-" skips the field and the fields of embedded structs are
+// columns of their own.  Fields may be numbers, strings, booleans, times or
+// pointers to them, along with types that implement driver.Valuer and
+// sql.Scanner (e.g. sql.NullInt64) or encoding.TextMarshaler and
+// encoding.TextUnmarshaler, which are written as text.  Nil pointers, nil
+// elements and invalid sql.Null values are written as empty cells.
+func MarshalSheet(s *Sheet, v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return errors.New("expected a slice")
+	}
+	st, err := structType(rv.Type())
+	if err != nil {
+		return err
+	}
+	fields, err := sheetFields(st, nil)
+	if err != nil {
+		return err
+	}
+	for i := 0; i <= rv.Len(); i++ {
+		s.Row(uint32(i) + 1).X().C = nil
+	}
+	header := s.Row(1)
+	for i, f := range fields {
+		header.Cell(reference.IndexToColumn(uint32(i))).SetString(f.name)
+		if f.width > 0 {
+			s.Column(uint32(i) + 1).SetWidth(measurement.Distance(f.width) * measurement.Character)
+		}
+	}
+	styles := map[string]CellStyle{}
+	style := func(code string, id StandardFormat) CellStyle {
+		key := code
+		if code == "" {
+			key = strconv.Itoa(int(id))
+		}
+		cs, ok := styles[key]
+		if !ok {
+			cs = s._bdb.StyleSheet.AddCellStyle()
+			if code != "" {
+				cs.SetNumberFormat(code)
+			} else {
+				cs.SetNumberFormatStandard(id)
+			}
+			styles[key] = cs
+		}
+		return cs
+	}
+	for i := 0; i < rv.Len(); i++ {
+		ev := rv.Index(i)
+		if ev.Kind() == reflect.Ptr {
+			ev = ev.Elem()
+		}
+		row := s.Row(uint32(i) + 2)
+		for j, f := range fields {
+			c := row.Cell(reference.IndexToColumn(uint32(j)))
+			fv, ok := fieldByIndex(ev, f.index)
+			if !ok {
+				c.Clear()
+				continue
+			}
+			id, err := marshalCell(c, fv)
+			if err != nil {
+				return fmt.Errorf("cell %s: field %s: %s", c.Reference(), f.field, err)
+			}
+			switch {
+			case f.format != "":
+				c.SetStyle(style(f.format, 0))
+			case id != 0:
+				c.SetStyle(style("", id))
+			}
+		}
+	}
+	return nil
+}
+
+// fieldByIndex returns a field of a struct, or false if it's within a nil
+// embedded pointer or the struct is a nil element.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	if !v.IsValid() {
+		return v, false
+	}
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
+
+// marshalCell sets a cell to a value, returning the number format needed to
+// display it or zero for General.
+func marshalCell(c Cell, v reflect.Value) (StandardFormat, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			c.Clear()
+			return 0, nil
+		}
+		v = v.Elem()
+	}
+	if v.Type() == timeType {
+		return marshalTime(c, v.Interface().(time.Time)), nil
+	}
+	if vr, ok := asInterface(v, valuerType); ok {
+		dv, err := vr.(driver.Valuer).Value()
+		if err != nil {
+			return 0, err
+		}
+		if dv == nil {
+			c.Clear()
+			return 0, nil
+		}
+		return marshalCell(c, reflect.ValueOf(dv))
+	}
+	if tm, ok := asInterface(v, textMarshalerType); ok {
+		b, err := tm.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return 0, err
+		}
+		c.SetString(string(b))
+		return 0, nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		c.SetBool(v.Bool())
+	case reflect.String:
+		c.SetString(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		c.SetNumber(float64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		c.SetNumber(float64(v.Uint()))
+	case reflect.Float32, reflect.Float64:
+		c.SetNumber(v.Float())
+	case reflect.Slice:
+		// []byte from a driver.Valuer
+		if b, ok := v.Interface().([]byte); ok {
+			c.SetString(string(b))
+			return 0, nil
+		}
+		fallthrough
+	default:
+		return 0, fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return 0, nil
+}
+
+// marshalTime sets a cell to the date and time on the clock of a time,
+// returning the number format that displays it.
+func marshalTime(c Cell, t time.Time) StandardFormat {
+	if t.IsZero() {
+		c.Clear()
+		return 0
+	}
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		c.SetDate(t)
+		return StandardFormatDate
+	}
+	c.SetTime(t)
+	return StandardFormatDateTime
+}
+
+// asInterface returns a value or a pointer to it as an interface it
+// implements.
+func asInterface(v reflect.Value, t reflect.Type) (interface{}, bool) {
+	if v.Type().Implements(t) {
+		return v.Interface(), true
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(t) {
+		return v.Addr().Interface(), true
+	}
+	if reflect.PtrTo(v.Type()).Implements(t) {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		return p.Interface(), true
+	}
+	return nil, false
+}
+
+// UnmarshalSheet reads the rows of a sheet into a pointer to a slice of
+// structs, or of pointers to structs, mapped to columns as described for
+// MarshalSheet.  The first row of the sheet is the header, whose names select
+// the columns of the fields, ignoring case if there's no exact match.
+// Fields without a column are left as their zero value, as are fields of
+// empty cells.  Each following row that isn't empty becomes an element of
+// the slice, which replaces its contents.  Cells that can't be stored in
+// their field stop reading with an UnmarshalError.
+func UnmarshalSheet(s *Sheet, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return errors.New("expected a pointer to a slice")
+	}
+	sv := rv.Elem()
+	st, err := structType(sv.Type())
+	if err != nil {
+		return err
+	}
+	fields, err := sheetFields(st, nil)
+	if err != nil {
+		return err
+	}
+	rows := s._bcgb.SheetData.Row
+	if len(rows) == 0 {
+		sv.Set(reflect.MakeSlice(sv.Type(), 0, 0))
+		return nil
+	}
+	cols := headerColumns(s, rows[0], fields)
+	out := reflect.MakeSlice(sv.Type(), 0, len(rows)-1)
+	for _, r := range rows[1:] {
+		cells := map[uint32]Cell{}
+		for _, c := range r.C {
+			if c.RAttr == nil {
+				continue
+			}
+			if ref, err := reference.ParseCellReference(*c.RAttr); err == nil {
+				if cell := (Cell{s._bdb, s, r, c}); !cell.IsEmpty() {
+					cells[ref.ColumnIdx] = cell
+				}
+			}
+		}
+		elem := reflect.New(st).Elem()
+		empty := true
+		for i, f := range fields {
+			col, ok := cols[i]
+			if !ok {
+				continue
+			}
+			c, ok := cells[col]
+			if !ok {
+				continue
+			}
+			empty = false
+			fv := fieldValue(elem, f.index)
+			if err := unmarshalCell(c, fv); err != nil {
+				ue := &UnmarshalError{Value: cellText(c), Field: f.field, Type: f.typ}
+				ue.Cell, _ = reference.ParseCellReference(c.Reference())
+				if err != errUnmarshal {
+					ue.Err = err
+				}
+				return ue
+			}
+		}
+		if empty {
+			continue
+		}
+		if sv.Type().Elem().Kind() == reflect.Ptr {
+			elem = elem.Addr()
+		}
+		out = reflect.Append(out, elem)
+	}
+	sv.Set(out)
+	return nil
+}
+
+// headerColumns returns the column indexes of the fields named in a header
+// row.
+func headerColumns(s *Sheet, header *sml.CT_Row, fields []sheetField) map[int]uint32 {
+	names, cols := []string{}, []uint32{}
+	for _, c := range header.C {
+		if c.RAttr == nil {
+			continue
+		}
+		ref, err := reference.ParseCellReference(*c.RAttr)
+		if err != nil {
+			continue
+		}
+		names = append(names, strings.TrimSpace(Cell{s._bdb, s, header, c}.GetString()))
+		cols = append(cols, ref.ColumnIdx)
+	}
+	fieldCols := map[int]uint32{}
+	for i, f := range fields {
+		for _, fold := range []bool{false, true} {
+			if j := indexName(names, f.name, fold); j >= 0 {
+				fieldCols[i] = cols[j]
+				break
+			}
+		}
+	}
+	return fieldCols
+}
+
+// indexName returns the index of the first of the names which matches a
+// name, or -1 if none do.
+func indexName(names []string, name string, fold bool) int {
+	for i, n := range names {
+		if n == name || fold && strings.EqualFold(n, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// fieldValue returns a field of a struct, allocating embedded pointers on
+// the way.
+func fieldValue(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v
+}
+
+// errUnmarshal is returned by unmarshalCell when a cell doesn't hold a value
+// of the type of a field.
+var errUnmarshal = errors.New("cannot unmarshal")
+
+// cellText returns the text of a cell, which is the string of text cells
+// and the raw value of others.
+func cellText(c Cell) string {
+	switch c._dbd.TAttr {
+	case sml.ST_CellTypeS, sml.ST_CellTypeInlineStr, sml.ST_CellTypeStr:
+		return c.GetString()
+	case sml.ST_CellTypeB:
+		if b, _ := c.GetValueAsBool(); b {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	if c._dbd.V == nil {
+		return ""
+	}
+	return *c._dbd.V
+}
+
+// cellNumber returns the number of a cell, parsing text in the locale of the
+// workbook.
+func cellNumber(c Cell) (float64, bool) {
+	switch c._dbd.TAttr {
+	case sml.ST_CellTypeS, sml.ST_CellTypeInlineStr, sml.ST_CellTypeStr:
+		return c._ebb.Locale().ParseNumber(c.GetString())
+	case sml.ST_CellTypeB, sml.ST_CellTypeE:
+		return 0, false
+	}
+	if c._dbd.V == nil {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(*c._dbd.V, 64)
+	return v, err == nil
+}
+
+// cellTime returns the time of a cell with a date or text of a date.
+func cellTime(c Cell) (time.Time, bool) {
+	v, ok := cellNumber(c)
+	if isText(c) {
+		if t, err := time.Parse(time.RFC3339, c.GetString()); err == nil {
+			return t, true
+		}
+		v, ok = c._ebb.Locale().ParseDate(c.GetString())
+	}
+	if !ok {
+		return time.Time{}, false
+	}
+	ms := int64(math.Floor(v*86400000 + 0.5))
+	t := c._ebb.Epoch().Add(time.Duration(ms) * time.Millisecond)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local), true
+}
+
+func isText(c Cell) bool {
+	switch c._dbd.TAttr {
+	case sml.ST_CellTypeS, sml.ST_CellTypeInlineStr, sml.ST_CellTypeStr:
+		return true
+	}
+	return false
+}
+
+// unmarshalCell stores the value of a cell that isn't empty in a field.
+func unmarshalCell(c Cell, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr {
+		p := reflect.New(v.Type().Elem())
+		if err := unmarshalCell(c, p.Elem()); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+	if c.IsError() {
+		return errUnmarshal
+	}
+	if v.Type() == timeType {
+		t, ok := cellTime(c)
+		if !ok {
+			return errUnmarshal
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if sc, ok := v.Addr().Interface().(sql.Scanner); ok {
+		return scanCell(c, sc)
+	}
+	if tu, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return tu.UnmarshalText([]byte(cellText(c)))
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		switch t := cellText(c); {
+		case c.IsBool():
+			v.SetBool(t == "TRUE")
+		case strings.EqualFold(t, "TRUE"):
+			v.SetBool(true)
+		case strings.EqualFold(t, "FALSE"):
+			v.SetBool(false)
+		default:
+			n, ok := cellNumber(c)
+			if !ok {
+				return errUnmarshal
+			}
+			v.SetBool(n != 0)
+		}
+	case reflect.String:
+		if isText(c) {
+			v.SetString(c.GetString())
+		} else {
+			v.SetString(c.GetFormattedValue())
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := cellNumber(c)
+		if !ok || n != math.Trunc(n) || n < -1<<63 || n >= 1<<63 || v.OverflowInt(int64(n)) {
+			return errUnmarshal
+		}
+		v.SetInt(int64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := cellNumber(c)
+		if !ok || n < 0 || n != math.Trunc(n) || n >= 1<<64 || v.OverflowUint(uint64(n)) {
+			return errUnmarshal
+		}
+		v.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		n, ok := cellNumber(c)
+		if !ok || v.OverflowFloat(n) {
+			return errUnmarshal
+		}
+		v.SetFloat(n)
+	default:
+		return errUnmarshal
+	}
+	return nil
+}
+
+// scanCell stores the value of a cell with a Scanner, which is given a
+// time.Time for dates, a float64 for other numbers, a bool or a string,
+// falling back to the text of the cell if the Scanner rejects the value.
+func scanCell(c Cell, sc sql.Scanner) error {
+	var src interface{}
+	switch {
+	case c.IsBool():
+		src = cellText(c) == "TRUE"
+	case isText(c):
+		src = c.GetString()
+	case format.IsDate(c.getFormat()):
+		src, _ = cellTime(c)
+	default:
+		src, _ = cellNumber(c)
+	}
+	err := sc.Scan(src)
+	if _, ok := src.(string); err != nil && !ok {
+		if sc.Scan(cellText(c)) == nil {
+			return nil
+		}
+	}
+	return err
+}
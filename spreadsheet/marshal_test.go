@@ -0,0 +1,221 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// level is marshaled as text.
+type level int
+
+func (l level) MarshalText() ([]byte, error) {
+	return []byte(strings.Repeat("*", int(l))), nil
+}
+
+func (l *level) UnmarshalText(b []byte) error {
+	if strings.Trim(string(b), "*") != "" {
+		return errors.New("not a level")
+	}
+	*l = level(len(b))
+	return nil
+}
+
+type marshalBase struct {
+	ID int `xlsx:"Id"`
+}
+
+type marshalRecord struct {
+	marshalBase
+	Name    string
+	Amount  float64 `xlsx:"Amount,format=#,##0.00,width=12"`
+	Count   *int
+	Active  bool
+	Day     time.Time
+	At      time.Time
+	Note    sql.NullString
+	Score   sql.NullInt64
+	Level   level
+	Skipped string `xlsx:"-"`
+	hidden  string
+}
+
+func marshalRecords() []*marshalRecord {
+	n := 3
+	return []*marshalRecord{
+		{
+			marshalBase: marshalBase{1}, Name: "one", Amount: 1234.5, Count: &n, Active: true,
+			Day:  time.Date(2020, 2, 29, 0, 0, 0, 0, time.Local),
+			At:   time.Date(2021, 3, 4, 13, 14, 15, 0, time.Local),
+			Note: sql.NullString{String: "note", Valid: true}, Score: sql.NullInt64{Int64: 42, Valid: true},
+			Level: 2, Skipped: "skipped", hidden: "hidden",
+		},
+		nil,
+		{marshalBase: marshalBase{3}, Name: "three"},
+	}
+}
+
+func TestMarshalSheet(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	if err := MarshalSheet(&s, marshalRecords()); err != nil {
+		t.Fatal(err)
+	}
+	for ref, exp := range map[string]string{
+		"A1": "Id", "B1": "Name", "C1": "Amount", "D1": "Count", "E1": "Active", "F1": "Day",
+		"G1": "At", "H1": "Note", "I1": "Score", "J1": "Level", "K1": "",
+		"A2": "1", "B2": "one", "C2": "1,234.50", "D2": "3", "E2": "TRUE",
+		"H2": "note", "I2": "42", "J2": "**",
+		"A3": "", "B3": "", "C3": "",
+		"A4": "3", "B4": "three", "C4": "0.00", "D4": "", "F4": "", "H4": "", "I4": "", "J4": "",
+	} {
+		if got := s.Cell(ref).GetFormattedValue(); got != exp {
+			t.Errorf("expected %q in %s, got %q", exp, ref, got)
+		}
+	}
+	if got, _ := s.Cell("F2").GetValueAsTime(); !got.Equal(time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the date 2020-02-29 in F2, got %s", got)
+	}
+	if got, _ := s.Cell("G2").GetValueAsTime(); !got.Equal(time.Date(2021, 3, 4, 13, 14, 15, 0, time.UTC)) {
+		t.Errorf("expected the time 2021-03-04 13:14:15 in G2, got %s", got)
+	}
+	if w := s.Column(3).X().WidthAttr; w == nil || *w != 12 {
+		t.Errorf("expected column C to be 12 characters wide, got %v", w)
+	}
+
+	if err := MarshalSheet(&s, 5); err == nil {
+		t.Errorf("expected an error marshaling a number")
+	}
+	if err := MarshalSheet(&s, []struct{ C chan int }{}); err == nil {
+		t.Errorf("expected an error marshaling an unsupported field")
+	}
+}
+
+func TestMarshalSheetReplacesRows(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetString("Name")
+	s.Cell("B1").SetString("Extra")
+	s.Cell("B2").SetString("old")
+	s.Cell("C3").SetString("old")
+	s.Cell("A4").SetString("below")
+	type x struct{ X int }
+	if err := MarshalSheet(&s, []x{{1}, {2}}); err != nil {
+		t.Fatal(err)
+	}
+	for ref, exp := range map[string]string{"A1": "X", "B1": "", "A2": "1", "B2": "", "A3": "2", "C3": "", "A4": "below"} {
+		if got := s.Cell(ref).GetFormattedValue(); got != exp {
+			t.Errorf("expected %q in %s, got %q", exp, ref, got)
+		}
+	}
+}
+
+func TestUnmarshalSheet(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	if err := MarshalSheet(&s, marshalRecords()); err != nil {
+		t.Fatal(err)
+	}
+	s = saveAndRead(t, wb).Sheets()[0]
+	got := []*marshalRecord{}
+	if err := UnmarshalSheet(&s, &got); err != nil {
+		t.Fatal(err)
+	}
+	// the nil element is an empty row, which is skipped
+	exp := marshalRecords()
+	exp = []*marshalRecord{exp[0], exp[2]}
+	exp[0].Skipped, exp[0].hidden = "", ""
+	if len(got) != len(exp) {
+		t.Fatalf("expected %d records, got %d", len(exp), len(got))
+	}
+	for i := range exp {
+		if !reflect.DeepEqual(got[i], exp[i]) {
+			t.Errorf("expected %+v, got %+v", exp[i], got[i])
+		}
+	}
+}
+
+func TestUnmarshalSheetHeaders(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	// columns are matched by name, ignoring case if there's no exact match
+	for i, h := range []string{"other", "AMOUNT", "name", "Name"} {
+		s.Cell(fmt.Sprintf("%c1", 'A'+i)).SetString(h)
+	}
+	s.Cell("A2").SetString("x")
+	s.Cell("B2").SetString("1.5")
+	s.Cell("C2").SetString("lower")
+	s.Cell("D2").SetString("exact")
+	s.Cell("B4").SetNumber(2)
+	type rec struct {
+		Name   string
+		Amount float32
+		Count  int
+	}
+	got := []rec{{Name: "replaced"}}
+	if err := UnmarshalSheet(&s, &got); err != nil {
+		t.Fatal(err)
+	}
+	exp := []rec{{"exact", 1.5, 0}, {"", 2, 0}}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected %+v, got %+v", exp, got)
+	}
+
+	if err := UnmarshalSheet(&s, got); err == nil {
+		t.Errorf("expected an error unmarshaling into a slice that isn't a pointer")
+	}
+	empty := wb.AddSheet()
+	if err := UnmarshalSheet(&empty, &got); err != nil || len(got) != 0 {
+		t.Errorf("expected no records from an empty sheet, got %v, %v", got, err)
+	}
+}
+
+func TestUnmarshalError(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		set   func(c Cell)
+		into  interface{}
+		value string
+		field string
+		err   bool
+	}{
+		{"overflow", func(c Cell) { c.SetNumber(300) }, &[]struct{ V int8 }{}, "300", "V", false},
+		{"negative", func(c Cell) { c.SetNumber(-1) }, &[]struct{ V uint }{}, "-1", "V", false},
+		{"fraction", func(c Cell) { c.SetNumber(1.5) }, &[]struct{ V int }{}, "1.5", "V", false},
+		{"float overflow", func(c Cell) { c.SetNumber(1e39) }, &[]struct{ V float32 }{}, "1" + strings.Repeat("0", 39), "V", false},
+		{"text", func(c Cell) { c.SetString("abc") }, &[]struct{ V float64 }{}, "abc", "V", false},
+		{"time", func(c Cell) { c.SetString("abc") }, &[]struct{ V time.Time }{}, "abc", "V", false},
+		{"bool", func(c Cell) { c.SetString("maybe") }, &[]struct{ V bool }{}, "maybe", "V", false},
+		{"unmarshaler", func(c Cell) { c.SetString("abc") }, &[]struct{ V level }{}, "abc", "V", true},
+		{"scanner", func(c Cell) { c.SetString("abc") }, &[]struct{ V sql.NullInt64 }{}, "abc", "V", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			wb := New()
+			s := wb.AddSheet()
+			s.Cell("A1").SetString("V")
+			tc.set(s.Cell("A3"))
+			err := UnmarshalSheet(&s, tc.into)
+			ue, ok := err.(*UnmarshalError)
+			if !ok {
+				t.Fatalf("expected an UnmarshalError, got %v", err)
+			}
+			if ue.Cell.String() != "A3" || ue.Value != tc.value || ue.Field != tc.field || (ue.Err != nil) != tc.err {
+				t.Errorf("unexpected error %+v", ue)
+			}
+			if !strings.HasPrefix(ue.Error(), fmt.Sprintf("cell A3: cannot unmarshal %q into field V of type ", tc.value)) {
+				t.Errorf("unexpected message %s", ue.Error())
+			}
+		})
+	}
+}
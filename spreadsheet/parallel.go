@@ -0,0 +1,379 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+)
+
+// RecalcOption is an option of a recalculation of formulas.
+type RecalcOption func(*recalcOptions)
+
+type recalcOptions struct {
+	ctx     context.Context
+	workers int
+	now     time.Time
+	seed    int64
+}
+
+func newRecalcOptions(opts []RecalcOption) *recalcOptions {
+	now := time.Now()
+	o := &recalcOptions{ctx: context.Background(), workers: 1, now: now, seed: now.UnixNano()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.workers <= 0 {
+		o.workers = runtime.GOMAXPROCS(0)
+	}
+	return o
+}
+
+// WithWorkers sets the number of goroutines that evaluate formulas which
+// don't depend on each other in parallel.  The default of one evaluates
+// formulas one at a time and zero or less uses one goroutine per CPU.  The
+// results are the same for any number of workers.
+func WithWorkers(n int) RecalcOption {
+	return func(o *recalcOptions) { o.workers = n }
+}
+
+// WithContext sets a context whose cancellation stops a recalculation.  The
+// results of formulas calculated before it stopped are kept.
+func WithContext(ctx context.Context) RecalcOption {
+	return func(o *recalcOptions) { o.ctx = ctx }
+}
+
+// WithTime sets the time returned by NOW and TODAY, which otherwise is the
+// time the recalculation started.
+func WithTime(t time.Time) RecalcOption {
+	return func(o *recalcOptions) { o.now = t }
+}
+
+// WithSeed sets the seed of the random numbers returned by RAND and
+// RANDBETWEEN, which otherwise is derived from the time.  Each cell draws
+// from its own sequence which depends on the seed and the position of the
+// cell, so recalculations with the same seed give the same results.
+func WithSeed(seed int64) RecalcOption {
+	return func(o *recalcOptions) { o.seed = seed }
+}
+
+// cellResult returns the value of a cell without a formula.
+func cellResult(c Cell) formula.Result {
+	switch {
+	case c.IsEmpty():
+		return formula.MakeEmptyResult()
+	case c.IsNumber():
+		v, _ := c.GetValueAsNumber()
+		return formula.MakeNumberResult(v)
+	case c.IsBool():
+		v, _ := c.GetValueAsBool()
+		return formula.MakeBoolResult(v)
+	}
+	v, _ := c.GetRawValue()
+	if c.IsError() {
+		r := formula.MakeErrorResult("")
+		r.ValueString = v
+		return r
+	}
+	return formula.MakeStringResult(v)
+}
+
+// cellRand is the source of the random numbers of the formula being
+// evaluated, created when it's first used.
+type cellRand struct {
+	seed int64
+	rand *rand.Rand
+}
+
+// cellSeed returns the seed of the random numbers of a cell.
+func (g *DependencyGraph) cellSeed(seed int64, k cellKey) int64 {
+	b := make([]byte, 20)
+	binary.LittleEndian.PutUint64(b, uint64(seed))
+	binary.LittleEndian.PutUint32(b[8:], uint32(g.order[k.ws]))
+	binary.LittleEndian.PutUint32(b[12:], k.col)
+	binary.LittleEndian.PutUint32(b[16:], k.row)
+	h := fnv.New64a()
+	h.Write(b)
+	return int64(h.Sum64())
+}
+
+// recalcContext is the context formulas are evaluated in during a
+// recalculation.  Formulas evaluated at the same time each have their own
+// context.  Cells are read from the results and the cell index of the graph
+// which don't change while formulas are evaluated, while anything that reads
+// the structure of a sheet, or may add cells to it, holds the lock of the
+// graph.
+type recalcContext struct {
+	*evalContext
+	g    *DependencyGraph
+	opts *recalcOptions
+	rand *cellRand
+}
+
+func (g *DependencyGraph) newRecalcContext(s *Sheet, o *recalcOptions, r *cellRand) *recalcContext {
+	ctx := _gba(s)
+	ctx.graph = g
+	return &recalcContext{evalContext: ctx, g: g, opts: o, rand: r}
+}
+
+// Now returns the time of the recalculation.
+func (c *recalcContext) Now() time.Time { return c.opts.now }
+
+// Rand returns the source of random numbers of the formula being evaluated.
+func (c *recalcContext) Rand() *rand.Rand {
+	if c.rand.rand == nil {
+		c.rand.rand = rand.New(rand.NewSource(c.rand.seed))
+	}
+	return c.rand.rand
+}
+
+// Cell returns the value of a cell.
+func (c *recalcContext) Cell(ref string, ev formula.Evaluator) formula.Result {
+	ws := c._afdd._bcgb
+	name := strings.TrimSuffix(ref, "#")
+	cr, err := reference.ParseCellReference(name)
+	if !c.g.covered[ws] || !_agfe(name) || err != nil {
+		return c.locked(func() formula.Result { return c.evalContext.Cell(ref, ev) })
+	}
+	if c._cga != 0 && !cr.AbsoluteColumn {
+		cr.ColumnIdx += c._cga
+	}
+	if c._fba != 0 && !cr.AbsoluteRow {
+		cr.RowIdx += c._fba
+	}
+	k := cellKey{ws, cr.ColumnIdx, cr.RowIdx}
+	if r, ok := c.g.results[k]; ok {
+		return spillResult(r, name != ref)
+	}
+	x, ok := c.g.cells[k]
+	if !ok {
+		return formula.MakeEmptyResult()
+	}
+	if x.F == nil {
+		return cellResult(Cell{c.g.wb, c._afdd, nil, x})
+	}
+	// formulas that aren't part of the graph, like the cells of a shared
+	// formula other than the first
+	return c.locked(func() formula.Result { return c.evalContext.Cell(ref, ev) })
+}
+
+func (c *recalcContext) locked(fn func() formula.Result) formula.Result {
+	c.g.mu.Lock()
+	defer c.g.mu.Unlock()
+	return fn()
+}
+
+// Sheet returns the context of another sheet.
+func (c *recalcContext) Sheet(name string) formula.Context {
+	if ws, ok := c.g.names[strings.ToLower(name)]; ok && c.g.sheets[ws].Name() == name {
+		return c.g.newRecalcContext(c.g.sheets[ws], c.opts, c.rand)
+	}
+	return formula.InvalidReferenceContext
+}
+
+// Table returns the location of a table, whose column names are read from
+// its header cells.
+func (c *recalcContext) Table(name string) (formula.TableInfo, bool) {
+	c.g.mu.Lock()
+	defer c.g.mu.Unlock()
+	return c.evalContext.Table(name)
+}
+
+// GetFormat returns the data format of a cell.
+func (c *recalcContext) GetFormat(cellRef string) string {
+	c.g.mu.Lock()
+	defer c.g.mu.Unlock()
+	return c.evalContext.GetFormat(cellRef)
+}
+
+// GetLabelPrefix returns the label prefix of a cell.
+func (c *recalcContext) GetLabelPrefix(cellRef string) string {
+	c.g.mu.Lock()
+	defer c.g.mu.Unlock()
+	return c.evalContext.GetLabelPrefix(cellRef)
+}
+
+// GetLocked returns whether a cell is locked.
+func (c *recalcContext) GetLocked(cellRef string) bool {
+	c.g.mu.Lock()
+	defer c.g.mu.Unlock()
+	return c.evalContext.GetLocked(cellRef)
+}
+
+// SetLocked sets whether a cell is locked.
+func (c *recalcContext) SetLocked(cellRef string, locked bool) {
+	c.g.mu.Lock()
+	defer c.g.mu.Unlock()
+	c.evalContext.SetLocked(cellRef, locked)
+}
+
+// HasFormula returns whether a cell has a formula.
+func (c *recalcContext) HasFormula(cellRef string) bool {
+	c.g.mu.Lock()
+	defer c.g.mu.Unlock()
+	return c.evalContext.HasFormula(cellRef)
+}
+
+// GetFormula returns the formula of a cell.
+func (c *recalcContext) GetFormula(cellRef string) string {
+	c.g.mu.Lock()
+	defer c.g.mu.Unlock()
+	return c.evalContext.GetFormula(cellRef)
+}
+
+// IsBool returns whether a cell has a boolean value.
+func (c *recalcContext) IsBool(cellRef string) bool {
+	c.g.mu.Lock()
+	defer c.g.mu.Unlock()
+	return c.evalContext.IsBool(cellRef)
+}
+
+// LastColumn returns the last column with data in a range of rows.
+func (c *recalcContext) LastColumn(rowFrom, rowTo int) string {
+	c.g.mu.Lock()
+	defer c.g.mu.Unlock()
+	return c.evalContext.LastColumn(rowFrom, rowTo)
+}
+
+// LastRow returns the last row with data in a column.
+func (c *recalcContext) LastRow(col string) int {
+	c.g.mu.Lock()
+	defer c.g.mu.Unlock()
+	return c.evalContext.LastRow(col)
+}
+
+// nodeContext returns a new context to evaluate the formula of a cell in.
+func (g *DependencyGraph) nodeContext(n *depNode, o *recalcOptions) *recalcContext {
+	ctx := g.newRecalcContext(g.sheets[n.key.ws], o, &cellRand{seed: g.cellSeed(o.seed, n.key)})
+	ctx.thisCell = n.cell.Reference()
+	return ctx
+}
+
+// evalNode evaluates the formula of a cell without recording the result.  It
+// is safe to call from several goroutines at once.
+func (g *DependencyGraph) evalNode(n *depNode, o *recalcOptions) formula.Result {
+	return formula.NewEvaluator().Eval(g.nodeContext(n, o), n.formula)
+}
+
+// evalNodes evaluates formulas which don't refer to each other using the
+// workers of a recalculation.
+func (g *DependencyGraph) evalNodes(nodes []*depNode, o *recalcOptions) ([]formula.Result, error) {
+	results := make([]formula.Result, len(nodes))
+	workers := o.workers
+	if workers > len(nodes) {
+		workers = len(nodes)
+	}
+	if workers <= 1 {
+		for i, n := range nodes {
+			if err := o.ctx.Err(); err != nil {
+				return nil, err
+			}
+			results[i] = g.evalNode(n, o)
+		}
+		return results, nil
+	}
+	next := int64(-1)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for o.ctx.Err() == nil {
+				j := int(atomic.AddInt64(&next, 1))
+				if j >= len(nodes) {
+					return
+				}
+				results[j] = g.evalNode(nodes[j], o)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := o.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// levels groups components so that the components of a level only refer to
+// those of earlier levels.  The components of a level can be calculated in
+// parallel.
+func (g *DependencyGraph) levels(comps []component, set map[*depNode]bool) [][]component {
+	compOf := make(map[*depNode]int, len(set))
+	for i, comp := range comps {
+		for _, n := range comp.nodes {
+			compOf[n] = i
+		}
+	}
+	level := make([]int, len(comps))
+	ret := [][]component{}
+	for i, comp := range comps {
+		for _, n := range comp.nodes {
+			for _, p := range g.precedents(n) {
+				if j, ok := compOf[p]; ok && set[p] && j != i && level[j]+1 > level[i] {
+					level[i] = level[j] + 1
+				}
+			}
+		}
+		for len(ret) <= level[i] {
+			ret = append(ret, nil)
+		}
+		ret[level[i]] = append(ret[level[i]], comp)
+	}
+	return ret
+}
+
+// calculateLevel evaluates the components of a level and stores their
+// results.  Formulas without circular references are evaluated in parallel,
+// then their results are stored in order.
+func (g *DependencyGraph) calculateLevel(comps []component, o *recalcOptions) error {
+	nodes := []*depNode{}
+	for _, comp := range comps {
+		if !comp.cyclic {
+			nodes = append(nodes, comp.nodes[0])
+		}
+	}
+	results, err := g.evalNodes(nodes, o)
+	if err != nil {
+		return err
+	}
+	for i, n := range nodes {
+		g.results[n.key] = results[i]
+		g.store(n, results[i], o)
+	}
+	for _, comp := range comps {
+		if !comp.cyclic {
+			continue
+		}
+		if err := o.ctx.Err(); err != nil {
+			return err
+		}
+		g.calculate(comp, o)
+	}
+	return nil
+}
+
+// index records a cell in the cell index of the graph.
+func (g *DependencyGraph) index(ws *sml.Worksheet, x *sml.CT_Cell) {
+	if x.RAttr == nil {
+		return
+	}
+	if ref, err := reference.ParseCellReference(*x.RAttr); err == nil {
+		g.cells[cellKey{ws, ref.ColumnIdx, ref.RowIdx}] = x
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// parallelWorkbook returns a workbook with chains of formulas that don't
+// depend on each other, along with formulas that combine them.
+func parallelWorkbook() *Workbook {
+	wb := New()
+	s := wb.AddSheet()
+	s.SetName("Data")
+	o := wb.AddSheet()
+	o.SetName("Totals")
+	for r := 1; r <= 50; r++ {
+		s.Cell(fmt.Sprintf("A%d", r)).SetNumber(float64(r))
+		s.Cell(fmt.Sprintf("B%d", r)).SetFormulaRaw(fmt.Sprintf("A%d*2", r))
+		s.Cell(fmt.Sprintf("C%d", r)).SetFormulaRaw(fmt.Sprintf("B%d+RANDBETWEEN(1,100)", r))
+		s.Cell(fmt.Sprintf("D%d", r)).SetFormulaRaw(fmt.Sprintf("IF(C%d>100,\"high\",\"low\")&TEXT(NOW(),\"yyyy\")", r))
+		o.Cell(fmt.Sprintf("A%d", r)).SetFormulaRaw(fmt.Sprintf("SUM(Data!C$1:C%d)", r))
+	}
+	o.Cell("B1").SetFormulaRaw("SUM(A1:A50)")
+	o.Cell("B2").SetFormulaRaw("COUNTIF(Data!D1:D50,\"high*\")")
+	return wb
+}
+
+// workbookValues returns the values of the cells of the sheets of a workbook.
+func workbookValues(wb *Workbook) map[string]string {
+	values := map[string]string{}
+	for _, s := range wb.Sheets() {
+		for _, r := range s.Rows() {
+			for _, c := range r.Cells() {
+				values[s.Name()+"!"+c.Reference()] = c.GetFormattedValue()
+			}
+		}
+	}
+	return values
+}
+
+func TestRecalculateWorkers(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	var exp map[string]string
+	for _, workers := range []int{1, 2, 8, 0} {
+		wb := parallelWorkbook()
+		if err := wb.RecalculateFormulasWithOptions(WithWorkers(workers), WithSeed(42), WithTime(now)); err != nil {
+			t.Fatal(err)
+		}
+		got := workbookValues(wb)
+		if got["Data!D1"] != "low2020" && got["Data!D1"] != "high2020" {
+			t.Fatalf("%d workers: unexpected value %s in D1", workers, got["Data!D1"])
+		}
+		if exp == nil {
+			exp = got
+			continue
+		}
+		for ref, v := range exp {
+			if got[ref] != v {
+				t.Errorf("%d workers: expected %s in %s, got %s", workers, v, ref, got[ref])
+			}
+		}
+	}
+
+	// incremental recalculation gives the same results in parallel
+	wb := parallelWorkbook()
+	g := NewDependencyGraph(wb)
+	if err := g.Recalculate(WithWorkers(4), WithSeed(42), WithTime(now)); err != nil {
+		t.Fatal(err)
+	}
+	s := wb.Sheets()[0]
+	s.Cell("A7").SetNumber(100)
+	g.MarkDirty(s.Cell("A7"))
+	if err := g.Recalculate(WithWorkers(4), WithSeed(42), WithTime(now)); err != nil {
+		t.Fatal(err)
+	}
+	seq := parallelWorkbook()
+	seq.Sheets()[0].Cell("A7").SetNumber(100)
+	if err := seq.RecalculateFormulasWithOptions(WithSeed(42), WithTime(now)); err != nil {
+		t.Fatal(err)
+	}
+	got, want := workbookValues(wb), workbookValues(seq)
+	for ref, v := range want {
+		if got[ref] != v {
+			t.Errorf("incremental: expected %s in %s, got %s", v, ref, got[ref])
+		}
+	}
+}
+
+func TestRecalculateCancelled(t *testing.T) {
+	wb := parallelWorkbook()
+	// RecalculateFormulas keeps its signature for callers using it as a value
+	var recalc func() = wb.RecalculateFormulas
+	recalc()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := wb.RecalculateFormulasWithOptions(WithContext(ctx), WithWorkers(4)); err != context.Canceled {
+		t.Errorf("expected the recalculation to be cancelled, got %v", err)
+	}
+}
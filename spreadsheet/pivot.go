@@ -0,0 +1,1799 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/unidoc/unioffice"
+	"github.com/unidoc/unioffice/common"
+	"github.com/unidoc/unioffice/schema/soo/pkg/relationships"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+	"github.com/unidoc/unioffice/zippkg"
+)
+
+// PivotFunction is the function used to summarize the values of a pivot table
+// data field.
+type PivotFunction byte
+
+// PivotFunction types
+const (
+	PivotFunctionSum PivotFunction = iota
+	PivotFunctionCount
+	PivotFunctionAverage
+	PivotFunctionMax
+	PivotFunctionMin
+	PivotFunctionProduct
+	PivotFunctionCountNums
+	PivotFunctionStdDev
+	PivotFunctionStdDevP
+	PivotFunctionVar
+	PivotFunctionVarP
+)
+
+var pivotFunctions = []struct {
+	typ     sml.ST_DataConsolidateFunction
+	caption string
+}{
+	PivotFunctionSum:       {sml.ST_DataConsolidateFunctionSum, "Sum"},
+	PivotFunctionCount:     {sml.ST_DataConsolidateFunctionCount, "Count"},
+	PivotFunctionAverage:   {sml.ST_DataConsolidateFunctionAverage, "Average"},
+	PivotFunctionMax:       {sml.ST_DataConsolidateFunctionMax, "Max"},
+	PivotFunctionMin:       {sml.ST_DataConsolidateFunctionMin, "Min"},
+	PivotFunctionProduct:   {sml.ST_DataConsolidateFunctionProduct, "Product"},
+	PivotFunctionCountNums: {sml.ST_DataConsolidateFunctionCountNums, "Count"},
+	PivotFunctionStdDev:    {sml.ST_DataConsolidateFunctionStdDev, "StdDev"},
+	PivotFunctionStdDevP:   {sml.ST_DataConsolidateFunctionStdDevp, "StdDevp"},
+	PivotFunctionVar:       {sml.ST_DataConsolidateFunctionVar, "Var"},
+	PivotFunctionVarP:      {sml.ST_DataConsolidateFunctionVarp, "Varp"},
+}
+
+func pivotFunctionFromX(t sml.ST_DataConsolidateFunction) PivotFunction {
+	for i, f := range pivotFunctions {
+		if f.typ == t {
+			return PivotFunction(i)
+		}
+	}
+	return PivotFunctionSum
+}
+
+// pivotCachePart is a pivot cache definition and its records as stored in
+// the package.
+type pivotCachePart struct {
+	def     *sml.PivotCacheDefinition
+	rels    common.Relationships
+	records *sml.PivotCacheRecords
+}
+
+// pivotTablePart is a pivot table definition as stored in the package.
+type pivotTablePart struct {
+	def  *sml.PivotTableDefinition
+	rels common.Relationships
+}
+
+// PivotCache is a snapshot of the source data of one or more pivot tables.
+type PivotCache struct {
+	wb *Workbook
+	p  *pivotCachePart
+}
+
+// X returns the inner wrapped XML type.
+func (c PivotCache) X() *sml.PivotCacheDefinition { return c.p.def }
+
+// Records returns the cached records, or nil if they weren't saved with the
+// workbook.
+func (c PivotCache) Records() *sml.PivotCacheRecords { return c.p.records }
+
+// ID returns the identifier that pivot tables use to refer to the cache.
+func (c PivotCache) ID() uint32 {
+	id, _ := c.wb.pivotCacheID(c.p)
+	return id
+}
+
+// SourceRange returns the worksheet range the cache was created from (e.g.
+// 'Sheet1'!A1:D20), or an empty string if the source isn't a worksheet range.
+func (c PivotCache) SourceRange() string {
+	src := c.p.def.CacheSource
+	if src == nil || src.WorksheetSource == nil {
+		return ""
+	}
+	ws := src.WorksheetSource
+	if ws.NameAttr != nil {
+		return *ws.NameAttr
+	}
+	if ws.RefAttr == nil {
+		return ""
+	}
+	if ws.SheetAttr == nil {
+		return *ws.RefAttr
+	}
+	return fmt.Sprintf("'%s'!%s", strings.Replace(*ws.SheetAttr, "'", "''", -1), *ws.RefAttr)
+}
+
+// FieldNames returns the names of the fields in the cache.
+func (c PivotCache) FieldNames() []string {
+	names := []string{}
+	if c.p.def.CacheFields != nil {
+		for _, f := range c.p.def.CacheFields.CacheField {
+			names = append(names, f.NameAttr)
+		}
+	}
+	return names
+}
+
+// Refresh re-reads the source range of the cache and re-renders the pivot
+// tables that use it.  Filters on page fields are reset.
+func (c PivotCache) Refresh() error {
+	ref := c.SourceRange()
+	if ref == "" {
+		return errors.New("pivot cache has no worksheet source")
+	}
+	if err := c.wb.fillPivotCache(c.p, ref); err != nil {
+		return err
+	}
+	for _, pt := range c.wb.pivotTablesFor(c.p) {
+		anchor, err := pt.anchor()
+		if err != nil {
+			return err
+		}
+		fields := pt.p.def.PivotFields
+		if fields != nil {
+			for _, pf := range fields.PivotField {
+				pf.Items = nil
+			}
+		}
+		if pt.p.def.PageFields != nil {
+			for _, pf := range pt.p.def.PageFields.PageField {
+				pf.ItemAttr = nil
+			}
+		}
+		if err := pt.render(anchor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddPivotCache creates a pivot cache from a range of cells (e.g.
+// Sheet1!A1:D20).  The first row of the range contains the field names and
+// each subsequent row is a record.
+func (wb *Workbook) AddPivotCache(sourceRange string) (PivotCache, error) {
+	p := &pivotCachePart{def: sml.NewPivotCacheDefinition(), rels: common.NewRelationships()}
+	if err := wb.fillPivotCache(p, sourceRange); err != nil {
+		return PivotCache{}, err
+	}
+	dt := unioffice.DocTypeSpreadsheet
+	idx := len(wb.pivotCaches) + 1
+	wb.pivotCaches = append(wb.pivotCaches, p)
+
+	rel := wb._adebd.AddAutoRelationship(dt, unioffice.OfficeDocumentType, idx, unioffice.PivotCacheDefinitionType)
+	id := uint32(1)
+	if wb._bbae.PivotCaches == nil {
+		wb._bbae.PivotCaches = sml.NewCT_PivotCaches()
+	}
+	for _, pc := range wb._bbae.PivotCaches.PivotCache {
+		if pc.CacheIdAttr >= id {
+			id = pc.CacheIdAttr + 1
+		}
+	}
+	ct := sml.NewCT_PivotCache()
+	ct.CacheIdAttr = id
+	ct.IdAttr = rel.ID()
+	wb._bbae.PivotCaches.PivotCache = append(wb._bbae.PivotCaches.PivotCache, ct)
+	wb.ContentTypes.AddOverride(unioffice.AbsoluteFilename(dt, unioffice.PivotCacheDefinitionType, idx), unioffice.PivotCacheDefinitionContentType)
+
+	recRel := p.rels.AddAutoRelationship(dt, unioffice.PivotCacheDefinitionType, idx, unioffice.PivotCacheRecordsType)
+	p.def.IdAttr = unioffice.String(recRel.ID())
+	wb.ContentTypes.AddOverride(unioffice.AbsoluteFilename(dt, unioffice.PivotCacheRecordsType, idx), unioffice.PivotCacheRecordsContentType)
+	return PivotCache{wb, p}, nil
+}
+
+// PivotCaches returns the pivot caches in the workbook.
+func (wb *Workbook) PivotCaches() []PivotCache {
+	ret := []PivotCache{}
+	for _, p := range wb.pivotCaches {
+		ret = append(ret, PivotCache{wb, p})
+	}
+	return ret
+}
+
+// pivotCacheID returns the cache ID that the workbook assigns to a cache part.
+func (wb *Workbook) pivotCacheID(p *pivotCachePart) (uint32, bool) {
+	if wb._bbae.PivotCaches == nil {
+		return 0, false
+	}
+	for _, pc := range wb._bbae.PivotCaches.PivotCache {
+		if wb.pivotCacheForRel(pc.IdAttr) == p {
+			return pc.CacheIdAttr, true
+		}
+	}
+	return 0, false
+}
+
+// pivotCacheForRel returns the cache part targeted by a workbook relationship.
+func (wb *Workbook) pivotCacheForRel(id string) *pivotCachePart {
+	dt := unioffice.DocTypeSpreadsheet
+	for _, r := range wb._adebd.X().Relationship {
+		if r.IdAttr != id || r.TypeAttr != unioffice.PivotCacheDefinitionType {
+			continue
+		}
+		for i, p := range wb.pivotCaches {
+			if r.TargetAttr == unioffice.RelativeFilename(dt, unioffice.OfficeDocumentType, unioffice.PivotCacheDefinitionType, i+1) {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+// pivotCacheByID returns the cache part with a given cache ID.
+func (wb *Workbook) pivotCacheByID(id uint32) *pivotCachePart {
+	if wb._bbae.PivotCaches == nil {
+		return nil
+	}
+	for _, pc := range wb._bbae.PivotCaches.PivotCache {
+		if pc.CacheIdAttr == id {
+			return wb.pivotCacheForRel(pc.IdAttr)
+		}
+	}
+	return nil
+}
+
+// pivotTablesFor returns the pivot tables that use a given cache.
+func (wb *Workbook) pivotTablesFor(p *pivotCachePart) []PivotTable {
+	ret := []PivotTable{}
+	for _, s := range wb.Sheets() {
+		for _, pt := range s.PivotTables() {
+			if wb.pivotCacheByID(pt.p.def.CacheIdAttr) == p {
+				ret = append(ret, pt)
+			}
+		}
+	}
+	return ret
+}
+
+// pivotValueKind is the type of a value stored in a pivot cache.  The order
+// matches the order in which shared items are serialized.
+type pivotValueKind byte
+
+const (
+	pivotValueMissing pivotValueKind = iota
+	pivotValueNumber
+	pivotValueBool
+	pivotValueError
+	pivotValueString
+)
+
+type pivotValue struct {
+	kind pivotValueKind
+	num  float64
+	str  string
+}
+
+func (v pivotValue) key() string {
+	if v.kind == pivotValueNumber {
+		return fmt.Sprintf("%d:%v", v.kind, v.num)
+	}
+	return fmt.Sprintf("%d:%s", v.kind, v.str)
+}
+
+func (v pivotValue) String() string {
+	switch v.kind {
+	case pivotValueMissing:
+		return "(blank)"
+	case pivotValueNumber:
+		return strconv.FormatFloat(v.num, 'f', -1, 64)
+	case pivotValueBool:
+		return strings.ToUpper(v.str)
+	}
+	return v.str
+}
+
+// less orders values the way pivot table items are sorted by default, numbers
+// before text and blanks last.
+func (v pivotValue) less(o pivotValue) bool {
+	rank := func(k pivotValueKind) int {
+		switch k {
+		case pivotValueNumber:
+			return 0
+		case pivotValueString:
+			return 1
+		case pivotValueBool:
+			return 2
+		case pivotValueError:
+			return 3
+		}
+		return 4
+	}
+	if rank(v.kind) != rank(o.kind) {
+		return rank(v.kind) < rank(o.kind)
+	}
+	if v.kind == pivotValueNumber {
+		return v.num < o.num
+	}
+	if l, r := strings.ToLower(v.str), strings.ToLower(o.str); l != r {
+		return l < r
+	}
+	return v.str < o.str
+}
+
+// pivotCellValue returns the value of a cell as it is stored in a pivot cache.
+func (wb *Workbook) pivotCellValue(c *sml.CT_Cell) pivotValue {
+	if c == nil {
+		return pivotValue{}
+	}
+	switch c.TAttr {
+	case sml.ST_CellTypeS, sml.ST_CellTypeInlineStr:
+		s := Cell{wb, nil, nil, c}.GetString()
+		if s == "" {
+			return pivotValue{}
+		}
+		return pivotValue{kind: pivotValueString, str: s}
+	case sml.ST_CellTypeB:
+		if c.V == nil {
+			return pivotValue{}
+		}
+		b, _ := strconv.ParseBool(*c.V)
+		return pivotValue{kind: pivotValueBool, str: strconv.FormatBool(b)}
+	case sml.ST_CellTypeE:
+		if c.V == nil {
+			return pivotValue{}
+		}
+		return pivotValue{kind: pivotValueError, str: *c.V}
+	}
+	if c.V == nil || *c.V == "" {
+		return pivotValue{}
+	}
+	if c.TAttr != sml.ST_CellTypeStr {
+		if f, err := strconv.ParseFloat(*c.V, 64); err == nil {
+			return pivotValue{kind: pivotValueNumber, num: f}
+		}
+	}
+	return pivotValue{kind: pivotValueString, str: *c.V}
+}
+
+// splitSheetRange splits a range of the form 'Sheet 1'!A1:B2 into the
+// unquoted sheet name and the range.
+func splitSheetRange(s string) (string, string, error) {
+	idx := strings.LastIndex(s, "!")
+	if idx <= 0 {
+		return "", "", fmt.Errorf("range %s must include a sheet name", s)
+	}
+	sheet := s[:idx]
+	if len(sheet) > 1 && sheet[0] == '\'' && sheet[len(sheet)-1] == '\'' {
+		sheet = strings.Replace(sheet[1:len(sheet)-1], "''", "'", -1)
+	}
+	return sheet, s[idx+1:], nil
+}
+
+// fillPivotCache reads the source range and replaces the fields and records
+// of the cache.
+func (wb *Workbook) fillPivotCache(p *pivotCachePart, sourceRange string) error {
+	sheetName, ref, err := splitSheetRange(sourceRange)
+	if err != nil {
+		return err
+	}
+	sheet, err := wb.GetSheet(sheetName)
+	if err != nil {
+		return fmt.Errorf("pivot cache source: %s", err)
+	}
+	from, to, err := reference.ParseRangeReference(ref)
+	if err != nil {
+		return fmt.Errorf("pivot cache source: %s", err)
+	}
+	if to.RowIdx < from.RowIdx {
+		from.RowIdx, to.RowIdx = to.RowIdx, from.RowIdx
+	}
+	if to.ColumnIdx < from.ColumnIdx {
+		from.ColumnIdx, to.ColumnIdx = to.ColumnIdx, from.ColumnIdx
+	}
+	if to.RowIdx == from.RowIdx {
+		return errors.New("pivot cache source must contain a header row and at least one record")
+	}
+
+	rows := map[uint32]map[uint32]*sml.CT_Cell{}
+	for _, r := range sheet.X().SheetData.Row {
+		if r.RAttr == nil || *r.RAttr < from.RowIdx || *r.RAttr > to.RowIdx {
+			continue
+		}
+		cells := map[uint32]*sml.CT_Cell{}
+		for _, c := range r.C {
+			if c.RAttr == nil {
+				continue
+			}
+			cref, err := reference.ParseCellReference(*c.RAttr)
+			if err != nil {
+				continue
+			}
+			cells[cref.ColumnIdx] = c
+		}
+		rows[*r.RAttr] = cells
+	}
+
+	numFields := int(to.ColumnIdx-from.ColumnIdx) + 1
+	names := []string{}
+	taken := map[string]bool{}
+	for i := 0; i < numFields; i++ {
+		name := fmt.Sprintf("Column%d", i+1)
+		if v := wb.pivotCellValue(rows[from.RowIdx][from.ColumnIdx+uint32(i)]); v.kind != pivotValueMissing {
+			name = v.String()
+		}
+		// field names must be unique
+		base := name
+		for n := 2; taken[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s%d", base, n)
+		}
+		taken[strings.ToLower(name)] = true
+		names = append(names, name)
+	}
+
+	records := [][]pivotValue{}
+	for r := from.RowIdx + 1; r <= to.RowIdx; r++ {
+		rec := make([]pivotValue, numFields)
+		for i := range rec {
+			rec[i] = wb.pivotCellValue(rows[r][from.ColumnIdx+uint32(i)])
+		}
+		records = append(records, rec)
+	}
+
+	def := p.def
+	def.RefreshOnLoadAttr = nil
+	def.CreatedVersionAttr = unioffice.Uint8(3)
+	def.RefreshedVersionAttr = unioffice.Uint8(3)
+	def.MinRefreshableVersionAttr = unioffice.Uint8(3)
+	def.RecordCountAttr = unioffice.Uint32(uint32(len(records)))
+	def.CacheSource = sml.NewCT_CacheSource()
+	def.CacheSource.TypeAttr = sml.ST_SourceTypeWorksheet
+	def.CacheSource.WorksheetSource = sml.NewCT_WorksheetSource()
+	def.CacheSource.WorksheetSource.RefAttr = unioffice.String(fmt.Sprintf("%s%d:%s%d",
+		reference.IndexToColumn(from.ColumnIdx), from.RowIdx, reference.IndexToColumn(to.ColumnIdx), to.RowIdx))
+	def.CacheSource.WorksheetSource.SheetAttr = unioffice.String(sheetName)
+	def.CacheFields = sml.NewCT_CacheFields()
+
+	// every field is stored as shared items so that records consist solely of
+	// indices, the items are grouped by type as the schema serializes them that
+	// way
+	indices := make([][]uint32, len(records))
+	for i := range indices {
+		indices[i] = make([]uint32, numFields)
+	}
+	for f, name := range names {
+		values := make([][]pivotValue, pivotValueString+1)
+		seenValues := map[string]bool{}
+		for _, rec := range records {
+			v := rec[f]
+			if !seenValues[v.key()] {
+				seenValues[v.key()] = true
+				values[v.kind] = append(values[v.kind], v)
+			}
+		}
+		idx := map[string]uint32{}
+		n := uint32(0)
+		for _, vs := range values {
+			for _, v := range vs {
+				idx[v.key()] = n
+				n++
+			}
+		}
+		for i, rec := range records {
+			indices[i][f] = idx[rec[f].key()]
+		}
+
+		cf := sml.NewCT_CacheField()
+		cf.NameAttr = name
+		cf.NumFmtIdAttr = unioffice.Uint32(0)
+		cf.SharedItems = newPivotSharedItems(values)
+		def.CacheFields.CacheField = append(def.CacheFields.CacheField, cf)
+	}
+	def.CacheFields.CountAttr = unioffice.Uint32(uint32(len(def.CacheFields.CacheField)))
+
+	p.records = sml.NewPivotCacheRecords()
+	for _, rec := range indices {
+		r := sml.NewCT_Record()
+		for _, x := range rec {
+			ix := sml.NewCT_Index()
+			ix.VAttr = x
+			r.X = append(r.X, ix)
+		}
+		p.records.R = append(p.records.R, r)
+	}
+	p.records.CountAttr = unioffice.Uint32(uint32(len(indices)))
+	return nil
+}
+
+// newPivotSharedItems constructs the shared items for a cache field from its
+// unique values grouped by type.
+func newPivotSharedItems(values [][]pivotValue) *sml.CT_SharedItems {
+	si := sml.NewCT_SharedItems()
+	types := 0
+	for k, vs := range values {
+		if len(vs) > 0 && pivotValueKind(k) != pivotValueMissing {
+			types++
+		}
+	}
+	hasBlank := len(values[pivotValueMissing]) > 0
+	hasNum := len(values[pivotValueNumber]) > 0
+	hasStr := len(values[pivotValueString]) > 0
+	if !hasStr && !hasBlank && len(values[pivotValueBool]) == 0 && len(values[pivotValueError]) == 0 {
+		si.ContainsSemiMixedTypesAttr = unioffice.Bool(false)
+	}
+	if !hasStr {
+		si.ContainsStringAttr = unioffice.Bool(false)
+	}
+	if hasBlank {
+		si.ContainsBlankAttr = unioffice.Bool(true)
+	}
+	if types > 1 {
+		si.ContainsMixedTypesAttr = unioffice.Bool(true)
+	}
+	if hasNum {
+		si.ContainsNumberAttr = unioffice.Bool(true)
+		isInt := true
+		min, max := math.Inf(1), math.Inf(-1)
+		for _, v := range values[pivotValueNumber] {
+			min = math.Min(min, v.num)
+			max = math.Max(max, v.num)
+			if v.num != math.Trunc(v.num) {
+				isInt = false
+			}
+		}
+		if isInt {
+			si.ContainsIntegerAttr = unioffice.Bool(true)
+		}
+		si.MinValueAttr = unioffice.Float64(min)
+		si.MaxValueAttr = unioffice.Float64(max)
+	}
+
+	for range values[pivotValueMissing] {
+		si.M = append(si.M, sml.NewCT_Missing())
+	}
+	for _, v := range values[pivotValueNumber] {
+		n := sml.NewCT_Number()
+		n.VAttr = v.num
+		si.N = append(si.N, n)
+	}
+	for _, v := range values[pivotValueBool] {
+		b := sml.NewCT_Boolean()
+		b.VAttr = v.str == "true"
+		si.B = append(si.B, b)
+	}
+	for _, v := range values[pivotValueError] {
+		e := sml.NewCT_Error()
+		e.VAttr = v.str
+		si.E = append(si.E, e)
+	}
+	for _, v := range values[pivotValueString] {
+		s := sml.NewCT_String()
+		s.VAttr = v.str
+		si.S = append(si.S, s)
+	}
+	n := len(si.M) + len(si.N) + len(si.B) + len(si.E) + len(si.S)
+	si.CountAttr = unioffice.Uint32(uint32(n))
+	return si
+}
+
+// pivotSharedItems returns the shared items of a cache field in index order.
+// Items of different types are indexed in the order the schema serializes
+// them, so caches mixing item types that were written with another order
+// can't be read reliably.
+func pivotSharedItems(si *sml.CT_SharedItems) []pivotValue {
+	ret := []pivotValue{}
+	if si == nil {
+		return ret
+	}
+	for range si.M {
+		ret = append(ret, pivotValue{})
+	}
+	for _, n := range si.N {
+		ret = append(ret, pivotValue{kind: pivotValueNumber, num: n.VAttr})
+	}
+	for _, b := range si.B {
+		ret = append(ret, pivotValue{kind: pivotValueBool, str: strconv.FormatBool(b.VAttr)})
+	}
+	for _, e := range si.E {
+		ret = append(ret, pivotValue{kind: pivotValueError, str: e.VAttr})
+	}
+	for _, s := range si.S {
+		ret = append(ret, pivotValue{kind: pivotValueString, str: s.VAttr})
+	}
+	for _, d := range si.D {
+		ret = append(ret, pivotValue{kind: pivotValueString, str: d.VAttr.Format("2006-01-02T15:04:05")})
+	}
+	return ret
+}
+
+// pivotCacheData is the decoded content of a pivot cache.
+type pivotCacheData struct {
+	items [][]pivotValue
+	// index holds the shared item index of each field of each record, or -1
+	// if the field has no shared items
+	index  [][]int
+	values [][]pivotValue
+}
+
+// decode decodes the records of the cache.
+func (p *pivotCachePart) decode() (*pivotCacheData, error) {
+	if p.records == nil {
+		return nil, errors.New("pivot cache has no records")
+	}
+	d := &pivotCacheData{}
+	var fields []*sml.CT_CacheField
+	if p.def.CacheFields != nil {
+		fields = p.def.CacheFields.CacheField
+	}
+	for _, f := range fields {
+		d.items = append(d.items, pivotSharedItems(f.SharedItems))
+	}
+	for ri, r := range p.records.R {
+		idx := make([]int, len(fields))
+		vals := make([]pivotValue, len(fields))
+		var nx, nn, nm, nb, ne, ns int
+		for f, cf := range fields {
+			idx[f] = -1
+			if len(d.items[f]) > 0 {
+				if nx >= len(r.X) || int(r.X[nx].VAttr) >= len(d.items[f]) {
+					return nil, fmt.Errorf("invalid pivot cache record %d", ri)
+				}
+				idx[f] = int(r.X[nx].VAttr)
+				vals[f] = d.items[f][idx[f]]
+				nx++
+				continue
+			}
+			// fields without shared items store values directly, since the
+			// values are grouped by type the type of each field is taken from
+			// the description of its shared items
+			si := cf.SharedItems
+			switch {
+			case si != nil && si.ContainsNumberAttr != nil && *si.ContainsNumberAttr && nn < len(r.N):
+				vals[f] = pivotValue{kind: pivotValueNumber, num: r.N[nn].VAttr}
+				nn++
+			case ns < len(r.S) && (si == nil || si.ContainsStringAttr == nil || *si.ContainsStringAttr):
+				vals[f] = pivotValue{kind: pivotValueString, str: r.S[ns].VAttr}
+				ns++
+			case nb < len(r.B):
+				vals[f] = pivotValue{kind: pivotValueBool, str: strconv.FormatBool(r.B[nb].VAttr)}
+				nb++
+			case ne < len(r.E):
+				vals[f] = pivotValue{kind: pivotValueError, str: r.E[ne].VAttr}
+				ne++
+			case nm < len(r.M):
+				nm++
+			case nn < len(r.N):
+				vals[f] = pivotValue{kind: pivotValueNumber, num: r.N[nn].VAttr}
+				nn++
+			}
+		}
+		d.index = append(d.index, idx)
+		d.values = append(d.values, vals)
+	}
+	return d, nil
+}
+
+// PivotTable is a pivot table on a sheet.
+type PivotTable struct {
+	wb    *Workbook
+	sheet Sheet
+	p     *pivotTablePart
+}
+
+// PivotDataField is a summarized field in the values area of a pivot table.
+type PivotDataField struct {
+	// Name is the caption of the field (e.g. 'Sum of Sales')
+	Name string
+	// Field is the name of the cache field that is summarized.
+	Field    string
+	Function PivotFunction
+}
+
+// AddPivotTable adds a pivot table that uses a given cache to the sheet.  The
+// top left corner of the pivot table, including any page fields, is placed at
+// anchor (e.g. 'F3').
+func (s *Sheet) AddPivotTable(cache PivotCache, anchor string) (PivotTable, error) {
+	if _, err := reference.ParseCellReference(anchor); err != nil {
+		return PivotTable{}, err
+	}
+	id, ok := s._bdb.pivotCacheID(cache.p)
+	if !ok || cache.wb != s._bdb {
+		return PivotTable{}, errors.New("pivot cache doesn't belong to the workbook")
+	}
+	sheetIdx := s.index()
+	if sheetIdx < 0 {
+		return PivotTable{}, errors.New("sheet not found in workbook")
+	}
+	wb := s._bdb
+	dt := unioffice.DocTypeSpreadsheet
+
+	p := &pivotTablePart{def: sml.NewPivotTableDefinition(), rels: common.NewRelationships()}
+	idx := len(wb.pivotTables) + 1
+	wb.pivotTables = append(wb.pivotTables, p)
+	cacheIdx := 0
+	for i, c := range wb.pivotCaches {
+		if c == cache.p {
+			cacheIdx = i + 1
+		}
+	}
+	p.rels.AddAutoRelationship(dt, unioffice.PivotTableType, cacheIdx, unioffice.PivotCacheDefinitionType)
+	wb._fdbe[sheetIdx].AddAutoRelationship(dt, unioffice.WorksheetType, idx, unioffice.PivotTableType)
+	wb.ContentTypes.AddOverride(unioffice.AbsoluteFilename(dt, unioffice.PivotTableType, idx), unioffice.PivotTableContentType)
+
+	names := map[string]struct{}{}
+	for _, pt := range wb.pivotTables {
+		names[pt.def.NameAttr] = struct{}{}
+	}
+	name := ""
+	for i := 1; ; i++ {
+		name = fmt.Sprintf("PivotTable%d", i)
+		if _, ok := names[name]; !ok {
+			break
+		}
+	}
+
+	def := p.def
+	def.NameAttr = name
+	def.CacheIdAttr = id
+	def.DataCaptionAttr = "Values"
+	def.ApplyNumberFormatsAttr = unioffice.Bool(false)
+	def.ApplyBorderFormatsAttr = unioffice.Bool(false)
+	def.ApplyFontFormatsAttr = unioffice.Bool(false)
+	def.ApplyPatternFormatsAttr = unioffice.Bool(false)
+	def.ApplyAlignmentFormatsAttr = unioffice.Bool(false)
+	def.ApplyWidthHeightFormatsAttr = unioffice.Bool(true)
+	def.UpdatedVersionAttr = unioffice.Uint8(3)
+	def.MinRefreshableVersionAttr = unioffice.Uint8(3)
+	def.CreatedVersionAttr = unioffice.Uint8(3)
+	def.UseAutoFormattingAttr = unioffice.Bool(true)
+	def.ItemPrintTitlesAttr = unioffice.Bool(true)
+	def.IndentAttr = unioffice.Uint32(0)
+	def.CompactAttr = unioffice.Bool(false)
+	def.CompactDataAttr = unioffice.Bool(false)
+	def.OutlineAttr = unioffice.Bool(true)
+	def.OutlineDataAttr = unioffice.Bool(true)
+	def.GridDropZonesAttr = unioffice.Bool(true)
+	def.MultipleFieldFiltersAttr = unioffice.Bool(false)
+	def.Location = sml.NewCT_Location()
+	def.PivotFields = sml.NewCT_PivotFields()
+	for range cache.FieldNames() {
+		def.PivotFields.PivotField = append(def.PivotFields.PivotField, newPivotField())
+	}
+	def.PivotFields.CountAttr = unioffice.Uint32(uint32(len(def.PivotFields.PivotField)))
+	def.PivotTableStyleInfo = sml.NewCT_PivotTableStyle()
+	def.PivotTableStyleInfo.NameAttr = unioffice.String("PivotStyleLight16")
+	def.PivotTableStyleInfo.ShowRowHeadersAttr = unioffice.Bool(true)
+	def.PivotTableStyleInfo.ShowColHeadersAttr = unioffice.Bool(true)
+	def.PivotTableStyleInfo.ShowRowStripesAttr = unioffice.Bool(false)
+	def.PivotTableStyleInfo.ShowColStripesAttr = unioffice.Bool(false)
+	def.PivotTableStyleInfo.ShowLastColumnAttr = unioffice.Bool(true)
+
+	pt := PivotTable{wb, *s, p}
+	if err := pt.render(anchor); err != nil {
+		return PivotTable{}, err
+	}
+	return pt, nil
+}
+
+func newPivotField() *sml.CT_PivotField {
+	pf := sml.NewCT_PivotField()
+	pf.CompactAttr = unioffice.Bool(false)
+	pf.OutlineAttr = unioffice.Bool(false)
+	pf.ShowAllAttr = unioffice.Bool(false)
+	return pf
+}
+
+// PivotTables returns the pivot tables on the sheet.
+func (s *Sheet) PivotTables() []PivotTable {
+	ret := []PivotTable{}
+	idx := s.index()
+	if idx < 0 {
+		return ret
+	}
+	dt := unioffice.DocTypeSpreadsheet
+	for _, r := range s._bdb._fdbe[idx].X().Relationship {
+		if r.TypeAttr != unioffice.PivotTableType {
+			continue
+		}
+		for i, p := range s._bdb.pivotTables {
+			if r.TargetAttr == unioffice.RelativeFilename(dt, unioffice.WorksheetType, unioffice.PivotTableType, i+1) {
+				ret = append(ret, PivotTable{s._bdb, *s, p})
+			}
+		}
+	}
+	return ret
+}
+
+// index returns the index of the sheet within the workbook.
+func (s *Sheet) index() int {
+	for i, ws := range s._bdb._fbed {
+		if ws == s._bcgb {
+			return i
+		}
+	}
+	return -1
+}
+
+// X returns the inner wrapped XML type.
+func (pt PivotTable) X() *sml.PivotTableDefinition { return pt.p.def }
+
+// Name returns the name of the pivot table.
+func (pt PivotTable) Name() string { return pt.p.def.NameAttr }
+
+// SetName sets the name of the pivot table.
+func (pt PivotTable) SetName(name string) { pt.p.def.NameAttr = name }
+
+// Location returns the range occupied by the pivot table, excluding the page
+// fields.
+func (pt PivotTable) Location() string {
+	if pt.p.def.Location == nil {
+		return ""
+	}
+	return pt.p.def.Location.RefAttr
+}
+
+// Cache returns the pivot cache used by the pivot table.
+func (pt PivotTable) Cache() (PivotCache, error) {
+	p := pt.wb.pivotCacheByID(pt.p.def.CacheIdAttr)
+	if p == nil {
+		return PivotCache{}, fmt.Errorf("pivot cache %d not found", pt.p.def.CacheIdAttr)
+	}
+	return PivotCache{pt.wb, p}, nil
+}
+
+func (pt PivotTable) fieldNames() []string {
+	c, err := pt.Cache()
+	if err != nil {
+		return nil
+	}
+	return c.FieldNames()
+}
+
+func (pt PivotTable) fieldName(idx int32) string {
+	names := pt.fieldNames()
+	if idx < 0 || int(idx) >= len(names) {
+		return ""
+	}
+	return names[idx]
+}
+
+// fieldIndex returns the index of the named cache field.
+func (pt PivotTable) fieldIndex(name string) (int, error) {
+	for i, n := range pt.fieldNames() {
+		if strings.EqualFold(n, name) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("pivot field %s not found", name)
+}
+
+// RowFields returns the names of the fields on the row axis.
+func (pt PivotTable) RowFields() []string {
+	ret := []string{}
+	if pt.p.def.RowFields != nil {
+		for _, f := range pt.p.def.RowFields.Field {
+			if f.XAttr >= 0 {
+				ret = append(ret, pt.fieldName(f.XAttr))
+			}
+		}
+	}
+	return ret
+}
+
+// ColumnFields returns the names of the fields on the column axis.
+func (pt PivotTable) ColumnFields() []string {
+	ret := []string{}
+	if pt.p.def.ColFields != nil {
+		for _, f := range pt.p.def.ColFields.Field {
+			if f.XAttr >= 0 {
+				ret = append(ret, pt.fieldName(f.XAttr))
+			}
+		}
+	}
+	return ret
+}
+
+// PageFields returns the names of the fields used as report filters.
+func (pt PivotTable) PageFields() []string {
+	ret := []string{}
+	if pt.p.def.PageFields != nil {
+		for _, f := range pt.p.def.PageFields.PageField {
+			ret = append(ret, pt.fieldName(f.FldAttr))
+		}
+	}
+	return ret
+}
+
+// DataFields returns the fields in the values area.
+func (pt PivotTable) DataFields() []PivotDataField {
+	ret := []PivotDataField{}
+	if pt.p.def.DataFields != nil {
+		for _, f := range pt.p.def.DataFields.DataField {
+			df := PivotDataField{Field: pt.fieldName(int32(f.FldAttr)), Function: pivotFunctionFromX(f.SubtotalAttr)}
+			if f.NameAttr != nil {
+				df.Name = *f.NameAttr
+			}
+			ret = append(ret, df)
+		}
+	}
+	return ret
+}
+
+// pivotField returns the pivot field for a cache field, adding pivot fields if
+// required.
+func (pt PivotTable) pivotField(idx int) *sml.CT_PivotField {
+	def := pt.p.def
+	if def.PivotFields == nil {
+		def.PivotFields = sml.NewCT_PivotFields()
+	}
+	for len(def.PivotFields.PivotField) <= idx {
+		def.PivotFields.PivotField = append(def.PivotFields.PivotField, newPivotField())
+	}
+	def.PivotFields.CountAttr = unioffice.Uint32(uint32(len(def.PivotFields.PivotField)))
+	return def.PivotFields.PivotField[idx]
+}
+
+// addAxisField places a field on the row, column or page axis.
+func (pt PivotTable) addAxisField(name string, axis sml.ST_Axis) error {
+	idx, err := pt.fieldIndex(name)
+	if err != nil {
+		return err
+	}
+	pf := pt.pivotField(idx)
+	if pf.AxisAttr != sml.ST_AxisUnset {
+		return fmt.Errorf("pivot field %s is already on an axis", name)
+	}
+	anchor, err := pt.anchor()
+	if err != nil {
+		return err
+	}
+	def := pt.p.def
+	pf.AxisAttr = axis
+	pf.DefaultSubtotalAttr = unioffice.Bool(false)
+	switch axis {
+	case sml.ST_AxisAxisRow:
+		if def.RowFields == nil {
+			def.RowFields = sml.NewCT_RowFields()
+		}
+		def.RowFields.Field = append(def.RowFields.Field, &sml.CT_Field{XAttr: int32(idx)})
+	case sml.ST_AxisAxisCol:
+		if def.ColFields == nil {
+			def.ColFields = sml.NewCT_ColFields()
+		}
+		// the values pseudo-field stays last
+		fields := []*sml.CT_Field{}
+		for _, f := range def.ColFields.Field {
+			if f.XAttr >= 0 {
+				fields = append(fields, f)
+			}
+		}
+		def.ColFields.Field = append(fields, &sml.CT_Field{XAttr: int32(idx)})
+	case sml.ST_AxisAxisPage:
+		if def.PageFields == nil {
+			def.PageFields = sml.NewCT_PageFields()
+		}
+		pgf := sml.NewCT_PageField()
+		pgf.FldAttr = int32(idx)
+		pgf.HierAttr = unioffice.Int32(-1)
+		def.PageFields.PageField = append(def.PageFields.PageField, pgf)
+	}
+	return pt.render(anchor)
+}
+
+// AddRowField adds a field to the row axis of the pivot table.
+func (pt PivotTable) AddRowField(name string) error {
+	return pt.addAxisField(name, sml.ST_AxisAxisRow)
+}
+
+// AddColumnField adds a field to the column axis of the pivot table.
+func (pt PivotTable) AddColumnField(name string) error {
+	return pt.addAxisField(name, sml.ST_AxisAxisCol)
+}
+
+// AddPageField adds a field to the report filter area of the pivot table.
+func (pt PivotTable) AddPageField(name string) error {
+	return pt.addAxisField(name, sml.ST_AxisAxisPage)
+}
+
+// SetPageFieldItem restricts the pivot table to the records where a page field
+// has a given value.  The value is compared to the text of the field's items,
+// an empty value removes the filter.
+func (pt PivotTable) SetPageFieldItem(name, value string) error {
+	idx, err := pt.fieldIndex(name)
+	if err != nil {
+		return err
+	}
+	var pgf *sml.CT_PageField
+	if pt.p.def.PageFields != nil {
+		for _, f := range pt.p.def.PageFields.PageField {
+			if int(f.FldAttr) == idx {
+				pgf = f
+			}
+		}
+	}
+	if pgf == nil {
+		return fmt.Errorf("%s is not a page field", name)
+	}
+	anchor, err := pt.anchor()
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		pgf.ItemAttr = nil
+		return pt.render(anchor)
+	}
+	c, err := pt.Cache()
+	if err != nil {
+		return err
+	}
+	data, err := c.p.decode()
+	if err != nil {
+		return err
+	}
+	for pos, x := range pt.fieldItems(idx, data) {
+		if data.items[idx][x].String() == value {
+			pgf.ItemAttr = unioffice.Uint32(uint32(pos))
+			return pt.render(anchor)
+		}
+	}
+	return fmt.Errorf("pivot field %s has no item %s", name, value)
+}
+
+// AddDataField adds a field to the values area of the pivot table, summarized
+// with a given function.
+func (pt PivotTable) AddDataField(name string, fn PivotFunction) error {
+	if int(fn) >= len(pivotFunctions) {
+		return fmt.Errorf("unsupported pivot function %d", fn)
+	}
+	idx, err := pt.fieldIndex(name)
+	if err != nil {
+		return err
+	}
+	anchor, err := pt.anchor()
+	if err != nil {
+		return err
+	}
+	def := pt.p.def
+	pf := pt.pivotField(idx)
+	pf.DataFieldAttr = unioffice.Bool(true)
+	if def.DataFields == nil {
+		def.DataFields = sml.NewCT_DataFields()
+	}
+	caption := fmt.Sprintf("%s of %s", pivotFunctions[fn].caption, pt.fieldName(int32(idx)))
+	for n := 2; ; n++ {
+		dup := false
+		for _, df := range pt.DataFields() {
+			if df.Name == caption {
+				dup = true
+			}
+		}
+		if !dup {
+			break
+		}
+		caption = fmt.Sprintf("%s of %s%d", pivotFunctions[fn].caption, pt.fieldName(int32(idx)), n)
+	}
+	df := sml.NewCT_DataField()
+	df.NameAttr = unioffice.String(caption)
+	df.FldAttr = uint32(idx)
+	df.SubtotalAttr = pivotFunctions[fn].typ
+	df.BaseFieldAttr = unioffice.Int32(0)
+	df.BaseItemAttr = unioffice.Uint32(0)
+	def.DataFields.DataField = append(def.DataFields.DataField, df)
+	def.DataFields.CountAttr = unioffice.Uint32(uint32(len(def.DataFields.DataField)))
+
+	// multiple data fields are shown as columns of the values pseudo-field
+	if len(def.DataFields.DataField) == 2 {
+		if def.ColFields == nil {
+			def.ColFields = sml.NewCT_ColFields()
+		}
+		def.ColFields.Field = append(def.ColFields.Field, &sml.CT_Field{XAttr: -2})
+	}
+	return pt.render(anchor)
+}
+
+// Update recomputes the pivot table from its cache and rewrites its cells.
+func (pt PivotTable) Update() error {
+	anchor, err := pt.anchor()
+	if err != nil {
+		return err
+	}
+	return pt.render(anchor)
+}
+
+// pageCount returns the number of page fields.
+func (pt PivotTable) pageCount() int {
+	if pt.p.def.PageFields == nil {
+		return 0
+	}
+	return len(pt.p.def.PageFields.PageField)
+}
+
+// anchor returns the top left cell of the pivot table including the page
+// fields.
+func (pt PivotTable) anchor() (string, error) {
+	loc := pt.p.def.Location
+	if loc == nil || loc.RefAttr == "" {
+		return "", errors.New("pivot table has no location")
+	}
+	ref := strings.Split(loc.RefAttr, ":")[0]
+	cref, err := reference.ParseCellReference(ref)
+	if err != nil {
+		return "", err
+	}
+	if n := pt.pageCount(); n > 0 {
+		// page fields are placed above the table, separated by an empty row
+		if uint32(n+1) < cref.RowIdx {
+			cref.RowIdx -= uint32(n + 1)
+		} else {
+			cref.RowIdx = 1
+		}
+	}
+	return fmt.Sprintf("%s%d", cref.Column, cref.RowIdx), nil
+}
+
+// fieldItems returns the shared item indices of a field in the order of the
+// pivot field items, creating the items if required.
+func (pt PivotTable) fieldItems(idx int, data *pivotCacheData) []int {
+	pf := pt.pivotField(idx)
+	items := data.items[idx]
+	if pf.Items != nil {
+		ret := []int{}
+		valid := true
+		for _, it := range pf.Items.Item {
+			if it.TAttr != sml.ST_ItemTypeUnset && it.TAttr != sml.ST_ItemTypeData {
+				continue
+			}
+			x := 0
+			if it.XAttr != nil {
+				x = int(*it.XAttr)
+			}
+			if x >= len(items) {
+				valid = false
+			}
+			ret = append(ret, x)
+		}
+		if valid && len(ret) == len(items) {
+			return ret
+		}
+	}
+	ret := make([]int, len(items))
+	for i := range ret {
+		ret[i] = i
+	}
+	sort.SliceStable(ret, func(i, j int) bool { return items[ret[i]].less(items[ret[j]]) })
+	pf.Items = sml.NewCT_Items()
+	for _, x := range ret {
+		it := sml.NewCT_Item()
+		it.XAttr = unioffice.Uint32(uint32(x))
+		pf.Items.Item = append(pf.Items.Item, it)
+	}
+	if pf.DefaultSubtotalAttr == nil || *pf.DefaultSubtotalAttr {
+		it := sml.NewCT_Item()
+		it.TAttr = sml.ST_ItemTypeDefault
+		pf.Items.Item = append(pf.Items.Item, it)
+	}
+	pf.Items.CountAttr = unioffice.Uint32(uint32(len(pf.Items.Item)))
+	return ret
+}
+
+// pivotAggregate accumulates the values summarized by a data field.
+type pivotAggregate struct {
+	count     int
+	nums      []float64
+	hasRecord bool
+}
+
+func (a *pivotAggregate) add(v pivotValue) {
+	a.hasRecord = true
+	if v.kind != pivotValueMissing {
+		a.count++
+	}
+	if v.kind == pivotValueNumber {
+		a.nums = append(a.nums, v.num)
+	}
+}
+
+// value returns the summarized value, or an error string for errors.
+func (a *pivotAggregate) value(fn PivotFunction) (float64, string) {
+	n := float64(len(a.nums))
+	sum := 0.0
+	for _, v := range a.nums {
+		sum += v
+	}
+	variance := func(sample bool) (float64, string) {
+		d := n
+		if sample {
+			d = n - 1
+		}
+		if d <= 0 {
+			return 0, "#DIV/0!"
+		}
+		mean := sum / n
+		ss := 0.0
+		for _, v := range a.nums {
+			ss += (v - mean) * (v - mean)
+		}
+		return ss / d, ""
+	}
+	switch fn {
+	case PivotFunctionCount:
+		return float64(a.count), ""
+	case PivotFunctionCountNums:
+		return n, ""
+	case PivotFunctionAverage:
+		if n == 0 {
+			return 0, "#DIV/0!"
+		}
+		return sum / n, ""
+	case PivotFunctionMax, PivotFunctionMin:
+		if n == 0 {
+			return 0, ""
+		}
+		r := a.nums[0]
+		for _, v := range a.nums {
+			if fn == PivotFunctionMax {
+				r = math.Max(r, v)
+			} else {
+				r = math.Min(r, v)
+			}
+		}
+		return r, ""
+	case PivotFunctionProduct:
+		if n == 0 {
+			return 0, ""
+		}
+		r := 1.0
+		for _, v := range a.nums {
+			r *= v
+		}
+		return r, ""
+	case PivotFunctionStdDev, PivotFunctionStdDevP:
+		v, e := variance(fn == PivotFunctionStdDev)
+		return math.Sqrt(v), e
+	case PivotFunctionVar, PivotFunctionVarP:
+		return variance(fn == PivotFunctionVar)
+	}
+	return sum, ""
+}
+
+// pivotLeaf is a column of the values area.
+type pivotLeaf struct {
+	key   []int
+	data  int
+	grand bool
+}
+
+func pivotKey(k []int) string {
+	s := make([]string, len(k))
+	for i, v := range k {
+		s[i] = strconv.Itoa(v)
+	}
+	return strings.Join(s, ",")
+}
+
+func pivotCommonPrefix(a, b []int) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+func pivotLessKey(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func newPivotItem(t sml.ST_ItemType, r, i int, xs []int) *sml.CT_I {
+	it := sml.NewCT_I()
+	it.TAttr = t
+	if r > 0 {
+		it.RAttr = unioffice.Uint32(uint32(r))
+	}
+	if i > 0 {
+		it.IAttr = unioffice.Uint32(uint32(i))
+	}
+	for _, x := range xs {
+		cx := sml.NewCT_X()
+		if x != 0 {
+			cx.VAttr = unioffice.Int32(int32(x))
+		}
+		it.X = append(it.X, cx)
+	}
+	return it
+}
+
+// render computes the pivot table from its cache and writes the result to the
+// sheet in tabular form, starting at anchor.
+func (pt PivotTable) render(anchor string) error {
+	cache, err := pt.Cache()
+	if err != nil {
+		return err
+	}
+	data, err := cache.p.decode()
+	if err != nil {
+		return err
+	}
+	def := pt.p.def
+	origin, err := reference.ParseCellReference(anchor)
+	if err != nil {
+		return err
+	}
+	pt.clear(origin)
+
+	var rowFields, colFields []int
+	valuesOnCols := false
+	if def.RowFields != nil {
+		for _, f := range def.RowFields.Field {
+			rowFields = append(rowFields, int(f.XAttr))
+		}
+	}
+	if def.ColFields != nil {
+		for _, f := range def.ColFields.Field {
+			if f.XAttr < 0 {
+				valuesOnCols = true
+				continue
+			}
+			colFields = append(colFields, int(f.XAttr))
+		}
+	}
+	dataFields := pt.DataFields()
+	var dataIdx []int
+	if def.DataFields != nil {
+		for _, df := range def.DataFields.DataField {
+			dataIdx = append(dataIdx, int(df.FldAttr))
+		}
+	}
+	valuesOnCols = valuesOnCols && len(dataFields) > 1
+	for _, f := range append(append([]int{}, rowFields...), colFields...) {
+		if f >= len(data.items) || len(data.items[f]) == 0 {
+			return fmt.Errorf("pivot field %s has no shared items", pt.fieldName(int32(f)))
+		}
+	}
+
+	// position of each shared item within the pivot field items
+	positions := map[int][]int{}
+	axisFields := append(append([]int{}, rowFields...), colFields...)
+	var pageFields []*sml.CT_PageField
+	if def.PageFields != nil {
+		pageFields = def.PageFields.PageField
+		for _, pf := range pageFields {
+			axisFields = append(axisFields, int(pf.FldAttr))
+		}
+	}
+	for _, f := range axisFields {
+		if f >= len(data.items) {
+			return fmt.Errorf("invalid pivot field %d", f)
+		}
+		pos := make([]int, len(data.items[f]))
+		for p, x := range pt.fieldItems(f, data) {
+			pos[x] = p
+		}
+		positions[f] = pos
+	}
+
+	// aggregate the records
+	type aggKey struct {
+		row, col string
+		data     int
+	}
+	aggs := map[aggKey]*pivotAggregate{}
+	agg := func(k aggKey) *pivotAggregate {
+		a, ok := aggs[k]
+		if !ok {
+			a = &pivotAggregate{}
+			aggs[k] = a
+		}
+		return a
+	}
+	rowKeys, colKeys := map[string][]int{}, map[string][]int{}
+	const total = "*"
+records:
+	for r, rec := range data.values {
+		for _, pf := range pageFields {
+			if pf.ItemAttr == nil {
+				continue
+			}
+			f := int(pf.FldAttr)
+			if data.index[r][f] < 0 || positions[f][data.index[r][f]] != int(*pf.ItemAttr) {
+				continue records
+			}
+		}
+		rk := make([]int, len(rowFields))
+		for i, f := range rowFields {
+			rk[i] = positions[f][data.index[r][f]]
+		}
+		ck := make([]int, len(colFields))
+		for i, f := range colFields {
+			ck[i] = positions[f][data.index[r][f]]
+		}
+		rs, cs := pivotKey(rk), pivotKey(ck)
+		rowKeys[rs] = rk
+		colKeys[cs] = ck
+		for d, f := range dataIdx {
+			v := pivotValue{}
+			if f < len(rec) {
+				v = rec[f]
+			}
+			agg(aggKey{rs, cs, d}).add(v)
+			agg(aggKey{rs, total, d}).add(v)
+			agg(aggKey{total, cs, d}).add(v)
+			agg(aggKey{total, total, d}).add(v)
+		}
+	}
+	sortedKeys := func(m map[string][]int) [][]int {
+		ret := [][]int{}
+		for _, k := range m {
+			ret = append(ret, k)
+		}
+		sort.Slice(ret, func(i, j int) bool { return pivotLessKey(ret[i], ret[j]) })
+		return ret
+	}
+	rows := sortedKeys(rowKeys)
+	cols := sortedKeys(colKeys)
+
+	// columns of the values area
+	numData := len(dataFields)
+	leaves := []pivotLeaf{}
+	dataCols := 1
+	if valuesOnCols {
+		dataCols = numData
+	}
+	if len(colFields) > 0 {
+		for _, c := range cols {
+			for d := 0; d < dataCols; d++ {
+				leaves = append(leaves, pivotLeaf{key: c, data: d})
+			}
+		}
+		for d := 0; d < dataCols; d++ {
+			leaves = append(leaves, pivotLeaf{data: d, grand: true})
+		}
+	} else if numData > 0 {
+		for d := 0; d < dataCols; d++ {
+			leaves = append(leaves, pivotLeaf{key: []int{}, data: d})
+		}
+	}
+	colLevels := len(colFields)
+	if valuesOnCols {
+		colLevels++
+	}
+
+	labelCols := len(rowFields)
+	if labelCols == 0 && colLevels > 0 {
+		labelCols = 1
+	}
+	headerRows := 1
+	if colLevels > 0 {
+		headerRows = 1 + colLevels
+	}
+	lines := len(rows)
+	if len(rowFields) > 0 {
+		lines++
+	} else {
+		lines = 1
+	}
+	width := labelCols + len(leaves)
+	if width == 0 {
+		width = 1
+	}
+
+	// page fields
+	top := origin.RowIdx
+	for i, pf := range pageFields {
+		f := int(pf.FldAttr)
+		pt.cell(origin.ColumnIdx, top+uint32(i)).SetString(pt.fieldName(int32(f)))
+		vc := pt.cell(origin.ColumnIdx+1, top+uint32(i))
+		if pf.ItemAttr == nil {
+			vc.SetString("(All)")
+		} else {
+			items := pt.fieldItems(f, data)
+			if int(*pf.ItemAttr) < len(items) {
+				pt.setValue(vc, data.items[f][items[*pf.ItemAttr]])
+			}
+		}
+	}
+	if len(pageFields) > 0 {
+		top += uint32(len(pageFields) + 1)
+	}
+	left := origin.ColumnIdx
+
+	// headers
+	dataName := func(d int) string {
+		if d < numData {
+			return dataFields[d].Name
+		}
+		return ""
+	}
+	if colLevels == 0 {
+		for i, f := range rowFields {
+			pt.cell(left+uint32(i), top).SetString(pt.fieldName(int32(f)))
+		}
+		if numData == 1 {
+			pt.cell(left+uint32(labelCols), top).SetString(dataName(0))
+		}
+	} else {
+		if numData == 1 {
+			pt.cell(left, top).SetString(dataName(0))
+		}
+		for i, f := range colFields {
+			pt.cell(left+uint32(labelCols+i), top).SetString(pt.fieldName(int32(f)))
+		}
+		if valuesOnCols {
+			pt.cell(left+uint32(labelCols+len(colFields)), top).SetString(def.DataCaptionAttr)
+		}
+		var prev []int
+		for l, leaf := range leaves {
+			col := left + uint32(labelCols+l)
+			if leaf.grand {
+				if valuesOnCols {
+					pt.cell(col, top+1).SetString("Total " + dataName(leaf.data))
+				} else {
+					pt.cell(col, top+1).SetString("Grand Total")
+				}
+				continue
+			}
+			key := leaf.key
+			if valuesOnCols {
+				key = append(append([]int{}, leaf.key...), leaf.data)
+			}
+			start := 0
+			if prev != nil {
+				start = pivotCommonPrefix(prev, key)
+			}
+			for lvl := start; lvl < len(key); lvl++ {
+				c := pt.cell(col, top+1+uint32(lvl))
+				if lvl < len(colFields) {
+					f := colFields[lvl]
+					pt.setValue(c, data.items[f][pt.fieldItems(f, data)[key[lvl]]])
+				} else {
+					c.SetString(dataName(key[lvl]))
+				}
+			}
+			prev = key
+		}
+		for i, f := range rowFields {
+			pt.cell(left+uint32(i), top+uint32(colLevels)).SetString(pt.fieldName(int32(f)))
+		}
+	}
+
+	// values
+	line := top + uint32(headerRows)
+	writeLine := func(rs string) {
+		for l, leaf := range leaves {
+			cs := total
+			if !leaf.grand {
+				cs = pivotKey(leaf.key)
+			}
+			a, ok := aggs[aggKey{rs, cs, leaf.data}]
+			if !ok || leaf.data >= numData {
+				continue
+			}
+			c := pt.cell(left+uint32(labelCols+l), line)
+			if v, e := a.value(dataFields[leaf.data].Function); e != "" {
+				c.SetError(e)
+			} else {
+				c.SetNumber(v)
+			}
+		}
+		line++
+	}
+	if len(rowFields) == 0 {
+		writeLine(total)
+	} else {
+		var prev []int
+		for _, rk := range rows {
+			start := 0
+			if prev != nil {
+				start = pivotCommonPrefix(prev, rk)
+			}
+			for lvl := start; lvl < len(rk); lvl++ {
+				f := rowFields[lvl]
+				pt.setValue(pt.cell(left+uint32(lvl), line), data.items[f][pt.fieldItems(f, data)[rk[lvl]]])
+			}
+			prev = rk
+			writeLine(pivotKey(rk))
+		}
+		pt.cell(left, line).SetString("Grand Total")
+		writeLine(total)
+	}
+
+	// pivot table definition
+	def.Location = sml.NewCT_Location()
+	def.Location.RefAttr = fmt.Sprintf("%s%d:%s%d", reference.IndexToColumn(left), top,
+		reference.IndexToColumn(left+uint32(width-1)), top+uint32(headerRows+lines-1))
+	def.Location.FirstHeaderRowAttr = 1
+	def.Location.FirstDataRowAttr = uint32(headerRows)
+	def.Location.FirstDataColAttr = uint32(labelCols)
+	if len(pageFields) > 0 {
+		def.Location.RowPageCountAttr = unioffice.Uint32(uint32(len(pageFields)))
+		def.Location.ColPageCountAttr = unioffice.Uint32(1)
+		def.PageFields.CountAttr = unioffice.Uint32(uint32(len(pageFields)))
+	}
+	if def.RowFields != nil {
+		def.RowFields.CountAttr = unioffice.Uint32(uint32(len(def.RowFields.Field)))
+	}
+	if def.ColFields != nil {
+		def.ColFields.CountAttr = unioffice.Uint32(uint32(len(def.ColFields.Field)))
+	}
+
+	def.RowItems = sml.NewCT_rowItems()
+	if len(rowFields) == 0 {
+		def.RowItems.I = append(def.RowItems.I, newPivotItem(sml.ST_ItemTypeUnset, 0, 0, nil))
+	} else {
+		var prev []int
+		for _, rk := range rows {
+			r := 0
+			if prev != nil {
+				r = pivotCommonPrefix(prev, rk)
+			}
+			def.RowItems.I = append(def.RowItems.I, newPivotItem(sml.ST_ItemTypeUnset, r, 0, rk[r:]))
+			prev = rk
+		}
+		def.RowItems.I = append(def.RowItems.I, newPivotItem(sml.ST_ItemTypeGrand, 0, 0, []int{0}))
+	}
+	def.RowItems.CountAttr = unioffice.Uint32(uint32(len(def.RowItems.I)))
+
+	def.ColItems = sml.NewCT_colItems()
+	if colLevels == 0 {
+		def.ColItems.I = append(def.ColItems.I, newPivotItem(sml.ST_ItemTypeUnset, 0, 0, nil))
+	} else {
+		var prev []int
+		for _, leaf := range leaves {
+			d := 0
+			if valuesOnCols {
+				d = leaf.data
+			}
+			if leaf.grand {
+				def.ColItems.I = append(def.ColItems.I, newPivotItem(sml.ST_ItemTypeGrand, 0, d, []int{0}))
+				continue
+			}
+			key := leaf.key
+			if valuesOnCols {
+				key = append(append([]int{}, leaf.key...), leaf.data)
+			}
+			r := 0
+			if prev != nil {
+				r = pivotCommonPrefix(prev, key)
+			}
+			def.ColItems.I = append(def.ColItems.I, newPivotItem(sml.ST_ItemTypeUnset, r, d, key[r:]))
+			prev = key
+		}
+	}
+	def.ColItems.CountAttr = unioffice.Uint32(uint32(len(def.ColItems.I)))
+	return nil
+}
+
+// cell returns the cell at a given column index and row number.
+func (pt PivotTable) cell(col, row uint32) Cell {
+	s := pt.sheet
+	return s.Cell(fmt.Sprintf("%s%d", reference.IndexToColumn(col), row))
+}
+
+// setValue writes a cache value to a cell.
+func (pt PivotTable) setValue(c Cell, v pivotValue) {
+	switch v.kind {
+	case pivotValueNumber:
+		c.SetNumber(v.num)
+	case pivotValueBool:
+		c.SetBool(v.str == "true")
+	case pivotValueError:
+		c.SetError(v.str)
+	default:
+		c.SetString(v.String())
+	}
+}
+
+// clear removes the cells that were previously written by the pivot table.
+func (pt PivotTable) clear(origin reference.CellReference) {
+	loc := pt.p.def.Location
+	if loc == nil || loc.RefAttr == "" {
+		return
+	}
+	_, to, err := reference.ParseRangeReference(loc.RefAttr)
+	if err != nil {
+		return
+	}
+	maxCol := to.ColumnIdx
+	if pt.pageCount() > 0 && maxCol < origin.ColumnIdx+1 {
+		maxCol = origin.ColumnIdx + 1
+	}
+	for _, r := range pt.sheet.X().SheetData.Row {
+		if r.RAttr == nil || *r.RAttr < origin.RowIdx || *r.RAttr > to.RowIdx {
+			continue
+		}
+		cells := r.C[:0]
+		for _, c := range r.C {
+			if c.RAttr != nil {
+				if cref, err := reference.ParseCellReference(*c.RAttr); err == nil &&
+					cref.ColumnIdx >= origin.ColumnIdx && cref.ColumnIdx <= maxCol {
+					continue
+				}
+			}
+			cells = append(cells, c)
+		}
+		r.C = cells
+	}
+}
+
+// onNewPivotRelationship handles the pivot table parts found while reading a
+// workbook.  Cache definitions are referenced both by the workbook and by the
+// pivot tables using them, so they are only decoded once.
+func (wb *Workbook) onNewPivotRelationship(dm *zippkg.DecodeMap, target, typ string, rel *relationships.Relationship, src zippkg.Target) {
+	dt := unioffice.DocTypeSpreadsheet
+	switch typ {
+	case unioffice.PivotCacheDefinitionType:
+		key := path.Clean(target)
+		p := &pivotCachePart{def: sml.NewPivotCacheDefinition(), rels: common.NewRelationships()}
+		idx := len(wb.pivotCaches)
+		if dm.AddTarget(target, p.def, typ, uint32(idx)) {
+			wb.pivotCaches = append(wb.pivotCaches, p)
+			dm.AddTarget(zippkg.RelationsPathFor(target), p.rels.X(), typ, uint32(idx))
+			dm.RecordIndex(key, idx)
+		} else {
+			idx = dm.IndexFor(key)
+		}
+		rel.TargetAttr = unioffice.RelativeFilename(dt, src.Typ, typ, idx+1)
+	case unioffice.PivotCacheRecordsType:
+		if int(src.Index) >= len(wb.pivotCaches) {
+			return
+		}
+		p := wb.pivotCaches[src.Index]
+		p.records = sml.NewPivotCacheRecords()
+		dm.AddTarget(target, p.records, typ, src.Index)
+		rel.TargetAttr = unioffice.RelativeFilename(dt, src.Typ, typ, int(src.Index)+1)
+	case unioffice.PivotTableType:
+		p := &pivotTablePart{def: sml.NewPivotTableDefinition(), rels: common.NewRelationships()}
+		idx := len(wb.pivotTables)
+		dm.AddTarget(target, p.def, typ, uint32(idx))
+		wb.pivotTables = append(wb.pivotTables, p)
+		dm.AddTarget(zippkg.RelationsPathFor(target), p.rels.X(), typ, uint32(idx))
+		rel.TargetAttr = unioffice.RelativeFilename(dt, src.Typ, typ, idx+1)
+	}
+}
+
+// savePivots writes the pivot caches and tables to the zip file.
+func (wb *Workbook) savePivots(z *zip.Writer) error {
+	dt := unioffice.DocTypeSpreadsheet
+	for i, p := range wb.pivotCaches {
+		fn := unioffice.AbsoluteFilename(dt, unioffice.PivotCacheDefinitionType, i+1)
+		if err := zippkg.MarshalXML(z, fn, p.def); err != nil {
+			return err
+		}
+		if !p.rels.IsEmpty() {
+			if err := zippkg.MarshalXML(z, zippkg.RelationsPathFor(fn), p.rels.X()); err != nil {
+				return err
+			}
+		}
+		if p.records != nil {
+			fn = unioffice.AbsoluteFilename(dt, unioffice.PivotCacheRecordsType, i+1)
+			if err := zippkg.MarshalXML(z, fn, p.records); err != nil {
+				return err
+			}
+		}
+	}
+	for i, p := range wb.pivotTables {
+		fn := unioffice.AbsoluteFilename(dt, unioffice.PivotTableType, i+1)
+		if err := zippkg.MarshalXML(z, fn, p.def); err != nil {
+			return err
+		}
+		if !p.rels.IsEmpty() {
+			if err := zippkg.MarshalXML(z, zippkg.RelationsPathFor(fn), p.rels.X()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
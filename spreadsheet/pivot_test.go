@@ -0,0 +1,250 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// pivotWorkbook returns a workbook with sales data on the first sheet, a
+// cache of the data and an empty second sheet for pivot tables.
+func pivotWorkbook(t *testing.T) (*Workbook, PivotCache) {
+	t.Helper()
+	wb := New()
+	s := wb.AddSheet()
+	s.SetName("Data")
+	for i, r := range [][]interface{}{
+		{"Region", "Product", "Year", "Sales"},
+		{"East", "A", 2020.0, 10.0},
+		{"East", "B", 2020.0, 20.0},
+		{"West", "A", 2020.0, 30.0},
+		{"West", "A", 2021.0, 40.0},
+		{"East", "A", 2021.0, 5.0},
+	} {
+		for j, v := range r {
+			c := s.Cell(fmt.Sprintf("%c%d", 'A'+j, i+1))
+			switch v := v.(type) {
+			case string:
+				c.SetString(v)
+			case float64:
+				c.SetNumber(v)
+			}
+		}
+	}
+	cache, err := wb.AddPivotCache("Data!A1:D6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps := wb.AddSheet()
+	ps.SetName("Pivot")
+	return wb, cache
+}
+
+func checkCells(t *testing.T, s Sheet, exp map[string]string) {
+	t.Helper()
+	for ref, v := range exp {
+		if got := s.Cell(ref).GetFormattedValue(); got != v {
+			t.Errorf("expected %q in %s, got %q", v, ref, got)
+		}
+	}
+}
+
+// pivotSalesTable adds a pivot table with every kind of field.
+func pivotSalesTable(t *testing.T, wb *Workbook, cache PivotCache) PivotTable {
+	t.Helper()
+	ps := wb.Sheets()[1]
+	pt, err := ps.AddPivotTable(cache, "A1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range []error{
+		pt.AddRowField("Region"),
+		pt.AddColumnField("Product"),
+		pt.AddPageField("Year"),
+		pt.AddDataField("Sales", PivotFunctionSum),
+		pt.AddDataField("Sales", PivotFunctionCount),
+		pt.AddDataField("Sales", PivotFunctionAverage),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return pt
+}
+
+var pivotAllYears = map[string]string{
+	"A1": "Year", "B1": "(All)",
+	"B3": "Product", "C3": "Values",
+	"B4": "A", "E4": "B", "H4": "Total Sum of Sales", "I4": "Total Count of Sales", "J4": "Total Average of Sales",
+	"A5": "Region", "B5": "Sum of Sales", "C5": "Count of Sales", "D5": "Average of Sales",
+	"A6": "East", "B6": "15", "C6": "2", "D6": "7.5", "E6": "20", "F6": "1", "G6": "20", "H6": "35", "I6": "3", "J6": "11.66666667",
+	"A7": "West", "B7": "70", "C7": "2", "D7": "35", "E7": "", "F7": "", "G7": "", "H7": "70", "I7": "2", "J7": "35",
+	"A8": "Grand Total", "B8": "85", "C8": "4", "D8": "21.25", "E8": "20", "F8": "1", "G8": "20", "H8": "105", "I8": "5", "J8": "21",
+}
+
+func TestPivotTable(t *testing.T) {
+	wb, cache := pivotWorkbook(t)
+	pt := pivotSalesTable(t, wb, cache)
+	checkCells(t, wb.Sheets()[1], pivotAllYears)
+	if got := pt.Location(); got != "A3:J8" {
+		t.Errorf("expected the location A3:J8, got %s", got)
+	}
+
+	rd := saveAndRead(t, wb)
+	caches := rd.PivotCaches()
+	if len(caches) != 1 {
+		t.Fatalf("expected one pivot cache, got %d", len(caches))
+	}
+	rc := caches[0]
+	if rc.SourceRange() != "'Data'!A1:D6" && rc.SourceRange() != "Data!A1:D6" {
+		t.Errorf("expected the source range Data!A1:D6, got %s", rc.SourceRange())
+	}
+	if got := rc.FieldNames(); !reflect.DeepEqual(got, []string{"Region", "Product", "Year", "Sales"}) {
+		t.Errorf("unexpected field names %v", got)
+	}
+	if rc.ID() != cache.ID() {
+		t.Errorf("expected the cache ID %d, got %d", cache.ID(), rc.ID())
+	}
+
+	// the records read back hold the source data
+	data, err := rc.p.decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := []string{}
+	for _, r := range data.values {
+		records = append(records, fmt.Sprint(r))
+	}
+	if got := fmt.Sprint(records); got != "[[East A 2020 10] [East B 2020 20] [West A 2020 30] [West A 2021 40] [East A 2021 5]]" {
+		t.Errorf("unexpected records %s", got)
+	}
+
+	ps := rd.Sheets()[1]
+	tables := ps.PivotTables()
+	if len(tables) != 1 {
+		t.Fatalf("expected one pivot table, got %d", len(tables))
+	}
+	rpt := tables[0]
+	if rpt.Name() != pt.Name() || rpt.Location() != pt.Location() {
+		t.Errorf("expected %s at %s, got %s at %s", pt.Name(), pt.Location(), rpt.Name(), rpt.Location())
+	}
+	if !reflect.DeepEqual(rpt.RowFields(), []string{"Region"}) ||
+		!reflect.DeepEqual(rpt.ColumnFields(), []string{"Product"}) ||
+		!reflect.DeepEqual(rpt.PageFields(), []string{"Year"}) {
+		t.Errorf("unexpected fields %v, %v and %v", rpt.RowFields(), rpt.ColumnFields(), rpt.PageFields())
+	}
+	if got, exp := rpt.DataFields(), pt.DataFields(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected the data fields %v, got %v", exp, got)
+	}
+	if c, err := rpt.Cache(); err != nil || c.ID() != rc.ID() {
+		t.Errorf("expected the pivot table to use the cache read, got %v", err)
+	}
+
+	// rendering the definition read back gives the same cells
+	if err := rpt.Update(); err != nil {
+		t.Fatal(err)
+	}
+	checkCells(t, ps, pivotAllYears)
+
+	if err := rpt.SetPageFieldItem("Year", "2021"); err != nil {
+		t.Fatal(err)
+	}
+	checkCells(t, ps, map[string]string{
+		"B1": "2021",
+		"B4": "A", "E4": "Total Sum of Sales",
+		"A6": "East", "B6": "5", "C6": "1", "D6": "5",
+		"A7": "West", "B7": "40", "C7": "1", "D7": "40",
+		"A8": "Grand Total", "B8": "45", "C8": "2", "D8": "22.5", "H8": "", "J8": "",
+	})
+	if err := rpt.SetPageFieldItem("Year", ""); err != nil {
+		t.Fatal(err)
+	}
+	checkCells(t, ps, pivotAllYears)
+}
+
+func TestPivotFunctions(t *testing.T) {
+	for _, tc := range []struct {
+		fn  PivotFunction
+		exp string
+	}{
+		{PivotFunctionSum, "105"},
+		{PivotFunctionCount, "5"},
+		{PivotFunctionAverage, "21"},
+		{PivotFunctionMax, "40"},
+		{PivotFunctionMin, "5"},
+		{PivotFunctionProduct, "1200000"},
+		{PivotFunctionCountNums, "5"},
+		{PivotFunctionStdDev, "14.31782106"},
+		{PivotFunctionStdDevP, "12.80624847"},
+		{PivotFunctionVar, "205"},
+		{PivotFunctionVarP, "164"},
+	} {
+		wb, cache := pivotWorkbook(t)
+		ps := wb.Sheets()[1]
+		pt, err := ps.AddPivotTable(cache, "B2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pt.AddDataField("Sales", tc.fn); err != nil {
+			t.Fatal(err)
+		}
+		if got := ps.Cell("B3").GetFormattedValue(); got != tc.exp {
+			t.Errorf("expected %s for %s, got %s", tc.exp, pt.DataFields()[0].Name, got)
+		}
+	}
+}
+
+func TestPivotCacheRefresh(t *testing.T) {
+	wb, cache := pivotWorkbook(t)
+	pt := pivotSalesTable(t, wb, cache)
+	if err := pt.SetPageFieldItem("Year", "2020"); err != nil {
+		t.Fatal(err)
+	}
+	wb.Sheets()[0].Cell("D2").SetNumber(100)
+	if err := cache.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	// refreshing resets the page field filter
+	checkCells(t, wb.Sheets()[1], map[string]string{"B1": "(All)", "B6": "105", "H6": "125", "H8": "195"})
+}
+
+func TestPivotTableErrors(t *testing.T) {
+	wb, cache := pivotWorkbook(t)
+	ps := wb.Sheets()[1]
+	if _, err := ps.AddPivotTable(cache, "not a cell"); err == nil {
+		t.Errorf("expected an error for an invalid anchor")
+	}
+	other, _ := pivotWorkbook(t)
+	if _, err := other.Sheets()[1].AddPivotTable(cache, "A1"); err == nil {
+		t.Errorf("expected an error for a cache of another workbook")
+	}
+	if _, err := wb.AddPivotCache("Missing!A1:B2"); err == nil {
+		t.Errorf("expected an error for a cache of a missing sheet")
+	}
+	pt, err := ps.AddPivotTable(cache, "A1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pt.AddRowField("Missing"); err == nil {
+		t.Errorf("expected an error for a missing field")
+	}
+	if err := pt.AddDataField("Sales", PivotFunction(100)); err == nil {
+		t.Errorf("expected an error for an unsupported function")
+	}
+	if err := pt.SetPageFieldItem("Year", "2020"); err == nil {
+		t.Errorf("expected an error filtering a field that isn't a page field")
+	}
+	if err := pt.AddPageField("Year"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pt.SetPageFieldItem("Year", "1999"); err == nil {
+		t.Errorf("expected an error filtering on a missing item")
+	}
+}
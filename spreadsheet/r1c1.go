@@ -0,0 +1,43 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+)
+
+// GetFormulaR1C1 returns the formula of the cell in the R1C1 notation (e.g.
+// R[-1]C+1 for A1+1 in A2).
+func (c Cell) GetFormulaR1C1() (string, error) {
+	return formula.ToR1C1(c.GetFormula(), c.Reference())
+}
+
+// SetFormulaR1C1 sets the formula of the cell from a formula in the R1C1
+// notation as SetFormulaRaw does.
+func (c Cell) SetFormulaR1C1(f string) error {
+	a1, err := formula.FromR1C1(f, c.Reference())
+	if err != nil {
+		return err
+	}
+	c.SetFormulaRaw(a1)
+	return nil
+}
+
+// formula returns the formula of a cell, which for the cells of a shared
+// formula is the formula of its first cell moved to the cell.
+func (e *evalContext) formula(c Cell) string {
+	x := c.X()
+	if x.F == nil || x.F.TAttr != sml.ST_CellFormulaTypeShared || x.F.Content != "" {
+		return c.GetFormula()
+	}
+	if e.masters == nil {
+		e.masters = e._afdd.sharedFormulas()
+	}
+	return sharedFormula(x, e.masters)
+}
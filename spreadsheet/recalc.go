@@ -0,0 +1,807 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+)
+
+const (
+	maxColumnIdx = 16383
+	maxRowIdx    = 1048576
+
+	// defaultIterateCount and defaultIterateDelta are the limits of iterative
+	// calculation when the calculation properties don't specify them.
+	defaultIterateCount = 100
+	defaultIterateDelta = 0.001
+
+	// wideAreaColumns is the width above which ranges are indexed by sheet
+	// rather than by column.
+	wideAreaColumns = 32
+
+	// maxSpillPasses limits the number of times formulas that refer to the
+	// cells of a spilled array are recalculated after the array spills.
+	maxSpillPasses = 8
+)
+
+// cellKey identifies a cell of a workbook by its zero based column index and
+// one based row number.
+type cellKey struct {
+	ws       *sml.Worksheet
+	col, row uint32
+}
+
+// area is a rectangular range of cells of a sheet.
+type area struct {
+	ws                     *sml.Worksheet
+	col1, row1, col2, row2 uint32
+}
+
+func (a area) single() bool { return a.col1 == a.col2 && a.row1 == a.row2 }
+
+func (a area) contains(k cellKey) bool {
+	return a.ws == k.ws && k.col >= a.col1 && k.col <= a.col2 && k.row >= a.row1 && k.row <= a.row2
+}
+
+// depNode is a formula cell of a dependency graph along with the cells that
+// its formula refers to.
+type depNode struct {
+	key      cellKey
+	cell     Cell
+	formula  string
+	areas    []area
+	volatile bool
+}
+
+// areaDep records that a formula refers to a range of cells.
+type areaDep struct {
+	area area
+	node *depNode
+}
+
+// component is a set of formulas that are evaluated together.  It is either
+// a single formula or a group of formulas that refer to each other.
+type component struct {
+	nodes  []*depNode
+	cyclic bool
+}
+
+// DependencyGraph records which cells the formulas of a workbook refer to so
+// that after cells change only the formulas that depend on them need to be
+// recalculated.  Formulas are recalculated in dependency order with each
+// formula evaluated once.  Circular references are reported as errors unless
+// iterative calculation is enabled in the calculation properties of the
+// workbook, in which case they are evaluated repeatedly up to the maximum
+// number of iterations or until the results change by less than the maximum
+// change.
+type DependencyGraph struct {
+	wb      *Workbook
+	sheets  map[*sml.Worksheet]*Sheet
+	names   map[string]*sml.Worksheet
+	order   map[*sml.Worksheet]int
+	covered map[*sml.Worksheet]bool
+	ctxs    map[*sml.Worksheet]*evalContext
+
+	nodes    map[cellKey]*depNode
+	results  map[cellKey]formula.Result
+	volatile map[*depNode]bool
+	// formulaRows holds the sorted rows of the formula cells of each column.
+	formulaRows map[*sml.Worksheet]map[uint32][]uint32
+	// cellDeps, colDeps and wideDeps index formulas by the cells they refer
+	// to.  Single cells are indexed directly, narrow ranges by each of their
+	// columns and wide ranges by their sheet.
+	cellDeps map[cellKey][]*depNode
+	colDeps  map[*sml.Worksheet]map[uint32][]areaDep
+	wideDeps map[*sml.Worksheet][]areaDep
+
+	dirty map[cellKey]bool
+
+	// cells indexes the cells of the sheets by their keys so they can be
+	// read while formulas are evaluated in parallel.
+	cells map[cellKey]*sml.CT_Cell
+	// masters holds the first cells of the shared formulas of each sheet,
+	// which the other cells of the formulas take their formulas from.
+	masters map[*sml.Worksheet]map[uint32]*sml.CT_Cell
+	// mu serializes evaluation that reads sheets directly.
+	mu sync.Mutex
+}
+
+// NewDependencyGraph constructs a dependency graph of the formulas of a
+// workbook.  Every formula of a new graph is marked as dirty so the first call
+// to Recalculate computes all of them.
+func NewDependencyGraph(wb *Workbook) *DependencyGraph {
+	return newDependencyGraph(wb, wb.Sheets())
+}
+
+// newDependencyGraph constructs a dependency graph of the formulas of some of
+// the sheets of a workbook.  Formulas on other sheets are evaluated when they
+// are referred to but are not recalculated.
+func newDependencyGraph(wb *Workbook, sheets []Sheet) *DependencyGraph {
+	g := &DependencyGraph{
+		wb:          wb,
+		sheets:      map[*sml.Worksheet]*Sheet{},
+		names:       map[string]*sml.Worksheet{},
+		order:       map[*sml.Worksheet]int{},
+		covered:     map[*sml.Worksheet]bool{},
+		ctxs:        map[*sml.Worksheet]*evalContext{},
+		nodes:       map[cellKey]*depNode{},
+		results:     map[cellKey]formula.Result{},
+		volatile:    map[*depNode]bool{},
+		formulaRows: map[*sml.Worksheet]map[uint32][]uint32{},
+		cellDeps:    map[cellKey][]*depNode{},
+		colDeps:     map[*sml.Worksheet]map[uint32][]areaDep{},
+		wideDeps:    map[*sml.Worksheet][]areaDep{},
+		dirty:       map[cellKey]bool{},
+		cells:       map[cellKey]*sml.CT_Cell{},
+		masters:     map[*sml.Worksheet]map[uint32]*sml.CT_Cell{},
+	}
+	all := wb.Sheets()
+	for i := range all {
+		s := &all[i]
+		g.sheets[s._bcgb] = s
+		g.names[strings.ToLower(s.Name())] = s._bcgb
+		g.order[s._bcgb] = i
+	}
+	for i := range sheets {
+		g.covered[sheets[i]._bcgb] = true
+	}
+	for i := range all {
+		s := &all[i]
+		if !g.covered[s._bcgb] {
+			continue
+		}
+		for _, r := range s.Rows() {
+			for _, c := range r.Cells() {
+				if k, ok := g.key(c); ok {
+					g.cells[k] = c.X()
+					g.update(c, k)
+					if _, ok := g.nodes[k]; ok {
+						g.dirty[k] = true
+					}
+				}
+			}
+		}
+	}
+	return g
+}
+
+// MarkDirty marks a cell whose value or formula has changed so that the next
+// call to Recalculate recomputes it and the formulas that depend on it.
+func (g *DependencyGraph) MarkDirty(c Cell) {
+	k, ok := g.key(c)
+	if !ok {
+		return
+	}
+	if g.covered[k.ws] {
+		g.cells[k] = c.X()
+	}
+	master := g.isMaster(c, k.ws)
+	g.update(c, k)
+	g.dirty[k] = true
+	if master || g.isMaster(c, k.ws) {
+		g.updateShared(k.ws)
+	}
+}
+
+// isMaster reports whether a cell is, or was when the graph last looked at the
+// shared formulas of its sheet, the first cell of a shared formula.
+func (g *DependencyGraph) isMaster(c Cell, ws *sml.Worksheet) bool {
+	if f := c.X().F; f != nil && f.TAttr == sml.ST_CellFormulaTypeShared && f.Content != "" {
+		return true
+	}
+	for _, m := range g.masters[ws] {
+		if m == c.X() {
+			return true
+		}
+	}
+	return false
+}
+
+// updateShared brings the graph up to date with the other cells of the shared
+// formulas of a sheet after the first cell of one of them has changed.
+func (g *DependencyGraph) updateShared(ws *sml.Worksheet) {
+	delete(g.masters, ws)
+	for _, r := range g.sheets[ws].Rows() {
+		for _, c := range r.Cells() {
+			f := c.X().F
+			if f == nil || f.TAttr != sml.ST_CellFormulaTypeShared || f.Content != "" {
+				continue
+			}
+			if k, ok := g.key(c); ok {
+				g.update(c, k)
+				g.dirty[k] = true
+			}
+		}
+	}
+}
+
+// Dependents returns the formula cells that refer directly to a cell.
+func (g *DependencyGraph) Dependents(c Cell) []Cell {
+	k, ok := g.key(c)
+	if !ok {
+		return nil
+	}
+	nodes := g.dependents(k)
+	g.sortNodes(nodes)
+	ret := []Cell{}
+	for i, n := range nodes {
+		if i == 0 || n != nodes[i-1] {
+			ret = append(ret, n.cell)
+		}
+	}
+	return ret
+}
+
+// CircularReferences returns the groups of formula cells that refer to each
+// other, directly or through other formulas.
+func (g *DependencyGraph) CircularReferences() [][]Cell {
+	nodes := make([]*depNode, 0, len(g.nodes))
+	affected := make(map[*depNode]bool, len(g.nodes))
+	for _, n := range g.nodes {
+		nodes = append(nodes, n)
+		affected[n] = true
+	}
+	g.sortNodes(nodes)
+	ret := [][]Cell{}
+	for _, comp := range g.components(nodes, affected) {
+		if !comp.cyclic {
+			continue
+		}
+		cells := []Cell{}
+		for _, n := range comp.nodes {
+			cells = append(cells, n.cell)
+		}
+		ret = append(ret, cells)
+	}
+	return ret
+}
+
+// Recalculate recomputes the formulas of the cells marked as dirty, the
+// formulas that depend on them and any volatile formulas, like those calling
+// NOW or INDIRECT, and stores their results in the cells.  Formulas that
+// don't depend on each other are evaluated in parallel if the options allow
+// more than one worker.  It returns the error of the context if the
+// recalculation is cancelled.
+func (g *DependencyGraph) Recalculate(opts ...RecalcOption) error {
+	o := newRecalcOptions(opts)
+	for pass := 0; pass < maxSpillPasses && (pass == 0 || len(g.dirty) > 0); pass++ {
+		dirty := g.dirty
+		g.dirty = map[cellKey]bool{}
+		affected := map[*depNode]bool{}
+		queue := []*depNode{}
+		visit := func(n *depNode) {
+			if !affected[n] {
+				affected[n] = true
+				queue = append(queue, n)
+			}
+		}
+		for k := range dirty {
+			if n, ok := g.nodes[k]; ok {
+				visit(n)
+				continue
+			}
+			for _, d := range g.dependents(k) {
+				visit(d)
+			}
+		}
+		if pass == 0 {
+			for n := range g.volatile {
+				visit(n)
+			}
+		}
+		for i := 0; i < len(queue); i++ {
+			for _, d := range g.dependents(queue[i].key) {
+				visit(d)
+			}
+		}
+		g.sortNodes(queue)
+		for _, level := range g.levels(g.components(queue, affected), affected) {
+			if err := g.calculateLevel(level, o); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// key returns the key of a cell.
+func (g *DependencyGraph) key(c Cell) (cellKey, bool) {
+	if c._dbag == nil {
+		return cellKey{}, false
+	}
+	ref, err := reference.ParseCellReference(c.Reference())
+	if err != nil {
+		return cellKey{}, false
+	}
+	return cellKey{c._dbag._bcgb, ref.ColumnIdx, ref.RowIdx}, true
+}
+
+// result returns the result calculated for a formula cell.  Evaluation
+// contexts of the graph use it instead of evaluating the formulas that a
+// formula refers to again.
+func (g *DependencyGraph) result(ws *sml.Worksheet, ref reference.CellReference) (formula.Result, bool) {
+	r, ok := g.results[cellKey{ws, ref.ColumnIdx, ref.RowIdx}]
+	return r, ok
+}
+
+// context returns the context used to evaluate the formulas of a sheet.
+func (g *DependencyGraph) context(ws *sml.Worksheet) *evalContext {
+	ctx, ok := g.ctxs[ws]
+	if !ok {
+		ctx = _gba(g.sheets[ws])
+		ctx.graph = g
+		g.ctxs[ws] = ctx
+	}
+	return ctx
+}
+
+// formulaOf returns the formula of a cell.  The cells of a shared formula
+// other than the first get the formula of the first cell moved to them, so
+// each of them is recalculated on its own.
+func (g *DependencyGraph) formulaOf(c Cell, ws *sml.Worksheet) string {
+	f := c.X().F
+	if f == nil {
+		return ""
+	}
+	if f.TAttr == sml.ST_CellFormulaTypeShared && f.Content == "" {
+		masters, ok := g.masters[ws]
+		if !ok {
+			masters = g.sheets[ws].sharedFormulas()
+			g.masters[ws] = masters
+		}
+		return sharedFormula(c.X(), masters)
+	}
+	return f.Content
+}
+
+// update brings the graph up to date with the formula of a cell.
+func (g *DependencyGraph) update(c Cell, k cellKey) {
+	f := g.formulaOf(c, k.ws)
+	if n, ok := g.nodes[k]; ok {
+		if n.formula == f {
+			n.cell = c
+			return
+		}
+		g.remove(n)
+	}
+	if f == "" || !g.covered[k.ws] {
+		return
+	}
+	n := &depNode{key: k, cell: c, formula: f}
+	if expr := formula.ParseString(f); expr != nil {
+		ctx := g.context(k.ws)
+		ctx.thisCell = c.Reference()
+		for _, ref := range formula.References(ctx, formula.NewEvaluator(), expr) {
+			if a, ok := g.area(k.ws, ref.Value); ok {
+				n.areas = append(n.areas, a)
+			}
+		}
+		n.volatile = formula.IsVolatile(expr)
+	}
+	g.add(n)
+}
+
+// add adds a formula cell to the graph and its indexes.
+func (g *DependencyGraph) add(n *depNode) {
+	g.nodes[n.key] = n
+	if n.volatile {
+		g.volatile[n] = true
+	}
+	cols := g.formulaRows[n.key.ws]
+	if cols == nil {
+		cols = map[uint32][]uint32{}
+		g.formulaRows[n.key.ws] = cols
+	}
+	rows := cols[n.key.col]
+	i := sort.Search(len(rows), func(i int) bool { return rows[i] >= n.key.row })
+	rows = append(rows, 0)
+	copy(rows[i+1:], rows[i:])
+	rows[i] = n.key.row
+	cols[n.key.col] = rows
+
+	for _, a := range n.areas {
+		switch {
+		case a.single():
+			k := cellKey{a.ws, a.col1, a.row1}
+			g.cellDeps[k] = append(g.cellDeps[k], n)
+		case a.col2-a.col1 >= wideAreaColumns:
+			g.wideDeps[a.ws] = append(g.wideDeps[a.ws], areaDep{a, n})
+		default:
+			deps := g.colDeps[a.ws]
+			if deps == nil {
+				deps = map[uint32][]areaDep{}
+				g.colDeps[a.ws] = deps
+			}
+			for c := a.col1; c <= a.col2; c++ {
+				deps[c] = append(deps[c], areaDep{a, n})
+			}
+		}
+	}
+}
+
+// remove removes a formula cell from the graph and its indexes.
+func (g *DependencyGraph) remove(n *depNode) {
+	delete(g.nodes, n.key)
+	delete(g.results, n.key)
+	delete(g.volatile, n)
+	rows := g.formulaRows[n.key.ws][n.key.col]
+	if i := sort.Search(len(rows), func(i int) bool { return rows[i] >= n.key.row }); i < len(rows) && rows[i] == n.key.row {
+		g.formulaRows[n.key.ws][n.key.col] = append(rows[:i], rows[i+1:]...)
+	}
+	for _, a := range n.areas {
+		switch {
+		case a.single():
+			k := cellKey{a.ws, a.col1, a.row1}
+			g.cellDeps[k] = removeDepNode(g.cellDeps[k], n)
+		case a.col2-a.col1 >= wideAreaColumns:
+			g.wideDeps[a.ws] = removeAreaDep(g.wideDeps[a.ws], n)
+		default:
+			for c := a.col1; c <= a.col2; c++ {
+				g.colDeps[a.ws][c] = removeAreaDep(g.colDeps[a.ws][c], n)
+			}
+		}
+	}
+}
+
+func removeDepNode(nodes []*depNode, n *depNode) []*depNode {
+	ret := nodes[:0]
+	for _, m := range nodes {
+		if m != n {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
+
+func removeAreaDep(deps []areaDep, n *depNode) []areaDep {
+	ret := deps[:0]
+	for _, d := range deps {
+		if d.node != n {
+			ret = append(ret, d)
+		}
+	}
+	return ret
+}
+
+// area returns the area of a reference like A1, $A$1:B2, 3:5, A:C or
+// 'Sheet 1'!A1:B2 in a formula on a sheet.
+func (g *DependencyGraph) area(ws *sml.Worksheet, ref string) (area, bool) {
+	if i := strings.LastIndex(ref, "!"); i >= 0 {
+		name := ref[:i]
+		if len(name) > 1 && name[0] == '\'' && name[len(name)-1] == '\'' {
+			name = strings.Replace(name[1:len(name)-1], "''", "'", -1)
+		}
+		var ok bool
+		if ws, ok = g.names[strings.ToLower(name)]; !ok {
+			return area{}, false
+		}
+		ref = ref[i+1:]
+	}
+	from, to := ref, ref
+	if i := strings.Index(ref, ":"); i >= 0 {
+		from, to = ref[:i], ref[i+1:]
+	}
+	c1, r1, ok1 := areaCorner(from)
+	c2, r2, ok2 := areaCorner(to)
+	if !ok1 || !ok2 {
+		return area{}, false
+	}
+	a := area{ws: ws, col1: 0, row1: 1, col2: maxColumnIdx, row2: maxRowIdx}
+	if c1 >= 0 && c2 >= 0 {
+		a.col1, a.col2 = uint32(c1), uint32(c2)
+	}
+	if r1 >= 0 && r2 >= 0 {
+		a.row1, a.row2 = uint32(r1), uint32(r2)
+	}
+	if a.col1 > a.col2 {
+		a.col1, a.col2 = a.col2, a.col1
+	}
+	if a.row1 > a.row2 {
+		a.row1, a.row2 = a.row2, a.row1
+	}
+	return a, true
+}
+
+// areaCorner parses a corner of a range like $A$1, A or 1, returning -1 for
+// the column or row if it's missing.
+func areaCorner(s string) (int, int, bool) {
+	s = strings.Replace(s, "$", "", -1)
+	i := 0
+	for i < len(s) && (s[i] >= 'A' && s[i] <= 'Z' || s[i] >= 'a' && s[i] <= 'z') {
+		i++
+	}
+	col, row := -1, -1
+	if i > 0 {
+		if i > 3 {
+			return 0, 0, false
+		}
+		col = int(reference.ColumnToIndex(s[:i]))
+	}
+	if i < len(s) {
+		n, err := strconv.Atoi(s[i:])
+		if err != nil || n < 1 {
+			return 0, 0, false
+		}
+		row = n
+	}
+	return col, row, col >= 0 || row >= 0
+}
+
+// dependents returns the formulas that refer to a cell.  A formula that refers
+// to the cell more than once is returned more than once.
+func (g *DependencyGraph) dependents(k cellKey) []*depNode {
+	ret := append([]*depNode{}, g.cellDeps[k]...)
+	for _, d := range g.colDeps[k.ws][k.col] {
+		if d.area.contains(k) {
+			ret = append(ret, d.node)
+		}
+	}
+	for _, d := range g.wideDeps[k.ws] {
+		if d.area.contains(k) {
+			ret = append(ret, d.node)
+		}
+	}
+	return ret
+}
+
+// precedents returns the formulas that a formula refers to.
+func (g *DependencyGraph) precedents(n *depNode) []*depNode {
+	ret := []*depNode{}
+	for _, a := range n.areas {
+		if a.single() {
+			if p, ok := g.nodes[cellKey{a.ws, a.col1, a.row1}]; ok {
+				ret = append(ret, p)
+			}
+			continue
+		}
+		cols := g.formulaRows[a.ws]
+		colIdxs := []uint32{}
+		if int(a.col2-a.col1) < len(cols) {
+			for c := a.col1; c <= a.col2; c++ {
+				if _, ok := cols[c]; ok {
+					colIdxs = append(colIdxs, c)
+				}
+			}
+		} else {
+			for c := range cols {
+				if c >= a.col1 && c <= a.col2 {
+					colIdxs = append(colIdxs, c)
+				}
+			}
+			sort.Slice(colIdxs, func(i, j int) bool { return colIdxs[i] < colIdxs[j] })
+		}
+		for _, c := range colIdxs {
+			rows := cols[c]
+			i := sort.Search(len(rows), func(i int) bool { return rows[i] >= a.row1 })
+			for ; i < len(rows) && rows[i] <= a.row2; i++ {
+				ret = append(ret, g.nodes[cellKey{a.ws, c, rows[i]}])
+			}
+		}
+	}
+	return ret
+}
+
+// sortNodes sorts formulas by sheet, row and column.
+func (g *DependencyGraph) sortNodes(nodes []*depNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := nodes[i].key, nodes[j].key
+		if a.ws != b.ws {
+			return g.order[a.ws] < g.order[b.ws]
+		}
+		if a.row != b.row {
+			return a.row < b.row
+		}
+		return a.col < b.col
+	})
+}
+
+// components returns the strongly connected components of the graph formed
+// by a set of formulas and the formulas of the set they refer to.  The
+// components are ordered so that the formulas a component refers to are in
+// earlier components.
+func (g *DependencyGraph) components(nodes []*depNode, set map[*depNode]bool) []component {
+	index := make(map[*depNode]int, len(nodes))
+	low := make(map[*depNode]int, len(nodes))
+	onStack := map[*depNode]bool{}
+	stack := []*depNode{}
+	ret := []component{}
+	var visit func(n *depNode)
+	visit = func(n *depNode) {
+		i := len(index)
+		index[n], low[n] = i, i
+		stack = append(stack, n)
+		onStack[n] = true
+		selfRef := false
+		for _, p := range g.precedents(n) {
+			if !set[p] {
+				continue
+			}
+			if p == n {
+				selfRef = true
+			}
+			if _, seen := index[p]; !seen {
+				visit(p)
+				if low[p] < low[n] {
+					low[n] = low[p]
+				}
+			} else if onStack[p] && index[p] < low[n] {
+				low[n] = index[p]
+			}
+		}
+		if low[n] != index[n] {
+			return
+		}
+		comp := component{cyclic: selfRef}
+		for {
+			m := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[m] = false
+			comp.nodes = append(comp.nodes, m)
+			if m == n {
+				break
+			}
+		}
+		if len(comp.nodes) > 1 {
+			comp.cyclic = true
+			g.sortNodes(comp.nodes)
+		}
+		ret = append(ret, comp)
+	}
+	for _, n := range nodes {
+		if _, seen := index[n]; !seen {
+			visit(n)
+		}
+	}
+	return ret
+}
+
+// calculate evaluates the formulas of a component and stores their results.
+func (g *DependencyGraph) calculate(comp component, o *recalcOptions) {
+	if !comp.cyclic {
+		n := comp.nodes[0]
+		g.store(n, g.eval(n, o), o)
+		return
+	}
+	if iterate, count, delta := g.iteration(); iterate {
+		g.iterate(comp.nodes, count, delta, o)
+		return
+	}
+	for _, n := range comp.nodes {
+		r := formula.MakeErrorResult("circular reference in " + n.cell.Reference())
+		g.results[n.key] = r
+		g.store(n, r, o)
+	}
+}
+
+// iteration returns whether iterative calculation is enabled along with the
+// maximum number of iterations and the maximum change.
+func (g *DependencyGraph) iteration() (bool, int, float64) {
+	pr := g.wb.X().CalcPr
+	if pr == nil || pr.IterateAttr == nil || !*pr.IterateAttr {
+		return false, 0, 0
+	}
+	count, delta := defaultIterateCount, defaultIterateDelta
+	if pr.IterateCountAttr != nil {
+		count = int(*pr.IterateCountAttr)
+	}
+	if pr.IterateDeltaAttr != nil {
+		delta = *pr.IterateDeltaAttr
+	}
+	return true, count, delta
+}
+
+// iterate evaluates formulas that refer to each other repeatedly, starting
+// from their previously calculated values, until no result changes by as much
+// as delta or the maximum number of iterations is reached.
+func (g *DependencyGraph) iterate(nodes []*depNode, count int, delta float64, o *recalcOptions) {
+	results := make([]formula.Result, len(nodes))
+	for i, n := range nodes {
+		results[i] = cachedResult(n.cell)
+		g.results[n.key] = results[i]
+	}
+	for it := 0; it < count; it++ {
+		change := 0.0
+		for i, n := range nodes {
+			r := g.eval(n, o)
+			change = math.Max(change, resultChange(results[i], r))
+			results[i] = r
+		}
+		if change < delta {
+			break
+		}
+	}
+	for i, n := range nodes {
+		g.store(n, results[i], o)
+	}
+}
+
+// cachedResult returns the result last calculated for a formula cell.  Cells
+// without a result are treated as zero.
+func cachedResult(c Cell) formula.Result {
+	x := c.X()
+	if x.V == nil {
+		return formula.MakeNumberResult(0)
+	}
+	switch x.TAttr {
+	case sml.ST_CellTypeE:
+		r := formula.MakeErrorResult("")
+		r.ValueString = *x.V
+		return r
+	case sml.ST_CellTypeB:
+		return formula.MakeBoolResult(*x.V == "1")
+	case sml.ST_CellTypeS:
+		return formula.MakeStringResult(c.GetString())
+	}
+	if f, err := strconv.ParseFloat(*x.V, 64); err == nil {
+		return formula.MakeNumberResult(f)
+	}
+	return formula.MakeStringResult(*x.V)
+}
+
+// resultChange returns how much a result changed from one iteration to the
+// next.
+func resultChange(a, b formula.Result) float64 {
+	if a.Type == formula.ResultTypeNumber && b.Type == formula.ResultTypeNumber {
+		return math.Abs(a.ValueNumber - b.ValueNumber)
+	}
+	if a.Type == b.Type && a.Value() == b.Value() {
+		return 0
+	}
+	return math.Inf(1)
+}
+
+// eval evaluates the formula of a cell and records the result for the
+// formulas that refer to it.
+func (g *DependencyGraph) eval(n *depNode, o *recalcOptions) formula.Result {
+	r := g.evalNode(n, o)
+	g.results[n.key] = r
+	return r
+}
+
+// store stores the result of a formula in its cell.  The cells that the
+// formula spilled into before or spills into now are marked as dirty, as
+// formulas may refer to them.
+func (g *DependencyGraph) store(n *depNode, r formula.Result, o *recalcOptions) {
+	s := g.sheets[n.key.ws]
+	spilled := s.clearSpill(n.cell.X())
+	// the other cells of a shared formula are nodes of their own, so only the
+	// first cell is stored here
+	f := n.cell.X().F
+	var shared *string
+	if f != nil && f.TAttr == sml.ST_CellFormulaTypeShared {
+		shared, f.RefAttr = f.RefAttr, nil
+	}
+	s._bfcga(g.nodeContext(n, o), n.cell, n.formula, r)
+	if shared != nil {
+		f.RefAttr = shared
+	}
+	if s.spillBlocked(n.cell.X(), r) {
+		// formulas that refer to a blocked spill see the #SPILL! error
+		g.results[n.key] = formula.MakeErrorResultType(formula.ErrorTypeSpill, "")
+	}
+	for c := range g.wb.spills[n.key.ws][n.cell.X()] {
+		g.index(n.key.ws, c)
+		spilled = append(spilled, c)
+	}
+	for _, c := range spilled {
+		if c.RAttr == nil {
+			continue
+		}
+		if ref, err := reference.ParseCellReference(*c.RAttr); err == nil {
+			g.dirty[cellKey{n.key.ws, ref.ColumnIdx, ref.RowIdx}] = true
+		}
+	}
+}
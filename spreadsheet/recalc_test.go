@@ -0,0 +1,196 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/unidoc/unioffice"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+)
+
+func TestMarkDirty(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetNumber(1)
+	s.Cell("B1").SetFormulaRaw("A1*2")
+	s.Cell("C1").SetFormulaRaw("B1+1")
+	s.Cell("D1").SetFormulaRaw("SUM(A1:A3)")
+	s.Cell("E1").SetFormulaRaw("5*2")
+	g := NewDependencyGraph(wb)
+	if err := g.Recalculate(); err != nil {
+		t.Fatal(err)
+	}
+	for ref, v := range map[string]string{"B1": "2", "C1": "3", "D1": "1", "E1": "10"} {
+		if got := s.Cell(ref).GetFormattedValue(); got != v {
+			t.Errorf("expected %s in %s, got %s", v, ref, got)
+		}
+	}
+	if got := g.Dependents(s.Cell("A1")); len(got) != 2 || got[0].Reference() != "B1" || got[1].Reference() != "D1" {
+		t.Errorf("expected B1 and D1 to depend on A1, got %v", got)
+	}
+
+	// only the formulas that depend on the changed cells are recalculated
+	s.Cell("E1").X().V = nil
+	s.Cell("A1").SetNumber(3)
+	g.MarkDirty(s.Cell("A1"))
+	s.Cell("A2").SetNumber(4)
+	g.MarkDirty(s.Cell("A2"))
+	if err := g.Recalculate(); err != nil {
+		t.Fatal(err)
+	}
+	for ref, v := range map[string]string{"B1": "6", "C1": "7", "D1": "7", "E1": ""} {
+		if got := s.Cell(ref).GetFormattedValue(); got != v {
+			t.Errorf("expected %s in %s, got %s", v, ref, got)
+		}
+	}
+
+	// changed formulas are recalculated along with their dependents
+	s.Cell("B1").SetFormulaRaw("A1*10")
+	g.MarkDirty(s.Cell("B1"))
+	if err := g.Recalculate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Cell("C1").GetFormattedValue(); got != "31" {
+		t.Errorf("expected 31 in C1, got %s", got)
+	}
+	if got := g.Dependents(s.Cell("A1")); len(got) != 2 {
+		t.Errorf("expected two dependents of A1, got %v", got)
+	}
+}
+
+func TestCircularReferences(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetFormulaRaw("B1+1")
+	s.Cell("B1").SetFormulaRaw("C1+1")
+	s.Cell("C1").SetFormulaRaw("A1+1")
+	s.Cell("D1").SetFormulaRaw("D1")
+	s.Cell("E1").SetFormulaRaw("A1+1")
+	g := NewDependencyGraph(wb)
+	groups := []string{}
+	for _, cells := range g.CircularReferences() {
+		refs := []string{}
+		for _, c := range cells {
+			refs = append(refs, c.Reference())
+		}
+		sort.Strings(refs)
+		groups = append(groups, strings.Join(refs, " "))
+	}
+	sort.Strings(groups)
+	if got := strings.Join(groups, ", "); got != "A1 B1 C1, D1" {
+		t.Errorf("expected circular references A1 B1 C1, D1, got %s", got)
+	}
+	if err := g.Recalculate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, ref := range []string{"A1", "B1", "C1", "D1"} {
+		cr, _ := reference.ParseCellReference(ref)
+		if got, _ := g.result(s.X(), cr); got.Type != formula.ResultTypeError || !strings.Contains(got.ErrorMessage, "circular reference") {
+			t.Errorf("expected a circular reference error in %s, got %s", ref, got.Value())
+		}
+	}
+
+	s.Cell("C1").SetNumber(1)
+	s.Cell("D1").SetNumber(1)
+	g.MarkDirty(s.Cell("C1"))
+	g.MarkDirty(s.Cell("D1"))
+	if got := g.CircularReferences(); len(got) != 0 {
+		t.Errorf("expected no circular references, got %v", got)
+	}
+	if err := g.Recalculate(); err != nil {
+		t.Fatal(err)
+	}
+	for ref, v := range map[string]string{"A1": "3", "B1": "2", "E1": "4"} {
+		if got := s.Cell(ref).GetFormattedValue(); got != v {
+			t.Errorf("expected %s in %s, got %s", v, ref, got)
+		}
+	}
+}
+
+func TestIterativeCalculation(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetFormulaRaw("B1/2+1")
+	s.Cell("B1").SetFormulaRaw("A1")
+	s.Cell("C1").SetFormulaRaw("A1*10")
+	pr := sml.NewCT_CalcPr()
+	pr.IterateAttr = unioffice.Bool(true)
+	pr.IterateCountAttr = unioffice.Uint32(100)
+	pr.IterateDeltaAttr = unioffice.Float64(0.0001)
+	wb.X().CalcPr = pr
+	wb.RecalculateFormulas()
+	// A1 converges on the solution of A1 = A1/2+1
+	for ref, v := range map[string]float64{"A1": 2, "B1": 2, "C1": 20} {
+		got, err := s.Cell(ref).GetValueAsNumber()
+		if err != nil || got < v-0.001 || got > v+0.001 {
+			t.Errorf("expected %g in %s, got %g", v, ref, got)
+		}
+	}
+
+	// the number of iterations is limited
+	pr.IterateCountAttr = unioffice.Uint32(1)
+	s.Cell("A1").X().V = nil
+	s.Cell("B1").X().V = nil
+	wb.RecalculateFormulas()
+	if got, _ := s.Cell("A1").GetValueAsNumber(); got != 1 {
+		t.Errorf("expected 1 in A1 after one iteration, got %g", got)
+	}
+}
+
+func TestSharedFormulaDependencies(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	for i, v := range []float64{1, 2, 3} {
+		s.Cell("A" + string(rune('1'+i))).SetNumber(v)
+	}
+	if err := s.Cell("B1").SetFormulaShared("A1*2", 2, 0); err != nil {
+		t.Fatal(err)
+	}
+	s.Cell("C1").SetFormulaRaw("B3+1")
+	g := NewDependencyGraph(wb)
+	if err := g.Recalculate(); err != nil {
+		t.Fatal(err)
+	}
+	for ref, v := range map[string]string{"B1": "2", "B2": "4", "B3": "6", "C1": "7"} {
+		if got := s.Cell(ref).GetFormattedValue(); got != v {
+			t.Errorf("expected %s in %s, got %s", v, ref, got)
+		}
+	}
+	if got := g.Dependents(s.Cell("A3")); len(got) != 1 || got[0].Reference() != "B3" {
+		t.Errorf("expected B3 to depend on A3, got %v", got)
+	}
+
+	// the cells of a shared formula are recalculated on their own
+	s.Cell("A3").SetNumber(10)
+	g.MarkDirty(s.Cell("A3"))
+	if err := g.Recalculate(); err != nil {
+		t.Fatal(err)
+	}
+	for ref, v := range map[string]string{"B1": "2", "B2": "4", "B3": "20", "C1": "21"} {
+		if got := s.Cell(ref).GetFormattedValue(); got != v {
+			t.Errorf("expected %s in %s, got %s", v, ref, got)
+		}
+	}
+
+	// changing the first cell changes the formulas of the others
+	s.Cell("B1").X().F.Content = "A1*3"
+	g.MarkDirty(s.Cell("B1"))
+	if err := g.Recalculate(); err != nil {
+		t.Fatal(err)
+	}
+	for ref, v := range map[string]string{"B1": "3", "B2": "6", "B3": "30", "C1": "31"} {
+		if got := s.Cell(ref).GetFormattedValue(); got != v {
+			t.Errorf("expected %s in %s, got %s", v, ref, got)
+		}
+	}
+}
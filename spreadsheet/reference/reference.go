@@ -30,11 +30,11 @@ type CellReference struct{RowIdx uint32 ;ColumnIdx uint32 ;Column string ;Absolu
 // '$C', etc.
 type ColumnReference struct{ColumnIdx uint32 ;Column string ;AbsoluteColumn bool ;SheetName string ;};
 
-// Update updates reference to point one of the neighboring cells with respect to the update type after removing a row/column.
-func (_ge *CellReference )Update (updateType _cf .UpdateAction )*CellReference {switch updateType {case _cf .UpdateActionRemoveColumn :_a :=_ge ;_a .ColumnIdx =_ge .ColumnIdx -1;_a .Column =IndexToColumn (_a .ColumnIdx );return _a ;default:return _ge ;};};
+// Update updates reference to point one of the neighboring cells with respect to the update type after removing or inserting a row/column.
+func (_ge *CellReference )Update (updateType _cf .UpdateAction )*CellReference {switch updateType {case _cf .UpdateActionRemoveColumn :_a :=_ge ;_a .ColumnIdx =_ge .ColumnIdx -1;_a .Column =IndexToColumn (_a .ColumnIdx );return _a ;case _cf .UpdateActionInsertColumn :_a :=_ge ;_a .ColumnIdx =_ge .ColumnIdx +1;_a .Column =IndexToColumn (_a .ColumnIdx );return _a ;case _cf .UpdateActionRemoveRow :_a :=_ge ;_a .RowIdx =_ge .RowIdx -1;return _a ;case _cf .UpdateActionInsertRow :_a :=_ge ;_a .RowIdx =_ge .RowIdx +1;return _a ;default:return _ge ;};};
 
-// Update updates reference to point one of the neighboring columns with respect to the update type after removing a row/column.
-func (_ag *ColumnReference )Update (updateType _cf .UpdateAction )*ColumnReference {switch updateType {case _cf .UpdateActionRemoveColumn :_ec :=_ag ;_ec .ColumnIdx =_ag .ColumnIdx -1;_ec .Column =IndexToColumn (_ec .ColumnIdx );return _ec ;default:return _ag ;};};func _gg (_gcg string )(string ,string ,error ){_dbe :="";_eef :=_c .LastIndex (_gcg ,"\u0021");if _eef > -1{_dbe =_gcg [:_eef ];_gcg =_gcg [_eef +1:];if _dbe ==""{return "","",_f .New ("\u0049n\u0076a\u006c\u0069\u0064\u0020\u0073h\u0065\u0065t\u0020\u006e\u0061\u006d\u0065");};};return _dbe ,_gcg ,nil ;};
+// Update updates reference to point one of the neighboring columns with respect to the update type after removing or inserting a row/column.
+func (_ag *ColumnReference )Update (updateType _cf .UpdateAction )*ColumnReference {switch updateType {case _cf .UpdateActionRemoveColumn :_ec :=_ag ;_ec .ColumnIdx =_ag .ColumnIdx -1;_ec .Column =IndexToColumn (_ec .ColumnIdx );return _ec ;case _cf .UpdateActionInsertColumn :_ec :=_ag ;_ec .ColumnIdx =_ag .ColumnIdx +1;_ec .Column =IndexToColumn (_ec .ColumnIdx );return _ec ;default:return _ag ;};};func _gg (_gcg string )(string ,string ,error ){_dbe :="";_eef :=_c .LastIndex (_gcg ,"\u0021");if _eef > -1{_dbe =_gcg [:_eef ];_gcg =_gcg [_eef +1:];if _dbe ==""{return "","",_f .New ("\u0049n\u0076a\u006c\u0069\u0064\u0020\u0073h\u0065\u0065t\u0020\u006e\u0061\u006d\u0065");};};return _dbe ,_gcg ,nil ;};
 
 // String returns a string representation of CellReference.
 func (_gb CellReference )String ()string {_e :=make ([]byte ,0,4);if _gb .AbsoluteColumn {_e =append (_e ,'$');};_e =append (_e ,_gb .Column ...);if _gb .AbsoluteRow {_e =append (_e ,'$');};_e =_gc .AppendInt (_e ,int64 (_gb .RowIdx ),10);return string (_e );};
@@ -0,0 +1,269 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"sort"
+
+	"github.com/unidoc/unioffice"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+)
+
+// SaveOption is an option of saving a workbook.
+type SaveOption func(*saveOptions)
+
+type saveOptions struct {
+	shareFormulas bool
+}
+
+func newSaveOptions(opts []SaveOption) *saveOptions {
+	o := &saveOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithSharedFormulas makes Save write runs of cells whose formulas are copies
+// of one another as shared formulas, as ShareFormulas does, which can make
+// files with many copied formulas much smaller.  The workbook itself is left
+// as is.
+func WithSharedFormulas() SaveOption {
+	return func(o *saveOptions) { o.shareFormulas = true }
+}
+
+// shareFormulasForSave shares the formulas of the sheets of the workbook,
+// returning a function that restores their formulas.
+func (wb *Workbook) shareFormulasForSave() func() {
+	old := map[*sml.CT_Cell]*sml.CT_CellFormula{}
+	for _, ws := range wb._fbed {
+		if _, ok := wb.streamingSheets[ws]; ok {
+			continue
+		}
+		s := Sheet{wb, nil, ws}
+		for c, f := range s.sharedFormulaPlan() {
+			old[c] = c.F
+			c.F = f
+		}
+	}
+	return func() {
+		for c, f := range old {
+			c.F = f
+		}
+	}
+}
+
+// expandSharedFormulas gives each cell of the shared formulas of the workbook
+// a formula of its own, as read workbooks are kept.
+func (wb *Workbook) expandSharedFormulas() {
+	all := area{col1: 0, row1: 1, col2: maxColumnIdx, row2: maxRowIdx}
+	for _, ws := range wb._fbed {
+		if ws.SheetData == nil {
+			continue
+		}
+		s := Sheet{wb, nil, ws}
+		all.ws = ws
+		s.expandSharedFormulas(all)
+	}
+}
+
+// cellFormula returns the formula of a cell, which for the cells of a shared
+// formula is the formula of its first cell moved to the cell.
+func cellFormula(c Cell) string {
+	f := c._dbd.F
+	if f.TAttr != sml.ST_CellFormulaTypeShared || f.Content != "" || c._dbag == nil || c._dbag._bcgb == nil {
+		return f.Content
+	}
+	return sharedFormula(c._dbd, c._dbag.sharedFormulas())
+}
+
+// ShareFormulas replaces runs of adjacent cells down a column, or else along a
+// row, whose formulas are copies of one another (i.e. are the same in the
+// R1C1 notation) by shared formulas, which store the formula once for the
+// whole run.  Only cells whose formulas are exactly the formula of the first
+// cell of the run copied to them are shared.  The cached values of the cells
+// are kept.
+func (s *Sheet) ShareFormulas() {
+	for c, f := range s.sharedFormulaPlan() {
+		c.F = f
+	}
+}
+
+// sharedFormulaPlan returns the formulas that ShareFormulas gives the cells of
+// the sheet, leaving the sheet as is.
+func (s *Sheet) sharedFormulaPlan() map[*sml.CT_Cell]*sml.CT_CellFormula {
+	type formulaCell struct {
+		x        *sml.CT_Cell
+		col, row uint32
+		r1c1     string
+	}
+	cells := []*formulaCell{}
+	si := uint32(0)
+	anchors := s._bdb.spills[s._bcgb]
+	for _, r := range s._bcgb.SheetData.Row {
+		for _, c := range r.C {
+			f := c.F
+			if f == nil {
+				continue
+			}
+			if f.SiAttr != nil && *f.SiAttr >= si {
+				si = *f.SiAttr + 1
+			}
+			if f.TAttr != sml.ST_CellFormulaTypeUnset && f.TAttr != sml.ST_CellFormulaTypeNormal ||
+				f.Content == "" || c.RAttr == nil || anchors[c] != nil {
+				continue
+			}
+			ref, err := reference.ParseCellReference(*c.RAttr)
+			if err != nil {
+				continue
+			}
+			r1c1, err := formula.ToR1C1(f.Content, *c.RAttr)
+			if err != nil {
+				continue
+			}
+			cells = append(cells, &formulaCell{c, ref.ColumnIdx, ref.RowIdx, r1c1})
+		}
+	}
+	// the R1C1 text groups the cells, but only those whose formulas are
+	// exactly the formula of the first cell copied to them are shared, as
+	// their formulas are read back that way
+	copies := func(first, c *formulaCell) bool {
+		return c.r1c1 == first.r1c1 &&
+			copiedFormula(first.x.F.Content, int(c.col)-int(first.col), int(c.row)-int(first.row)) == c.x.F.Content
+	}
+	plan := map[*sml.CT_Cell]*sml.CT_CellFormula{}
+	shared := map[*formulaCell]bool{}
+	share := func(run []*formulaCell) {
+		if len(run) < 2 {
+			return
+		}
+		first, last := run[0], run[len(run)-1]
+		m := *first.x.F
+		m.TAttr = sml.ST_CellFormulaTypeShared
+		m.RefAttr = unioffice.String(rangeString(
+			reference.CellReference{ColumnIdx: first.col, RowIdx: first.row},
+			reference.CellReference{ColumnIdx: last.col, RowIdx: last.row}))
+		m.SiAttr = unioffice.Uint32(si)
+		plan[first.x] = &m
+		for _, c := range run {
+			shared[c] = true
+			if c != first {
+				f := sml.NewCT_CellFormula()
+				f.TAttr = sml.ST_CellFormulaTypeShared
+				f.SiAttr = unioffice.Uint32(si)
+				plan[c.x] = f
+			}
+		}
+		si++
+	}
+	// runs down columns first, then runs along rows of the cells left over
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].col != cells[j].col {
+			return cells[i].col < cells[j].col
+		}
+		return cells[i].row < cells[j].row
+	})
+	start := 0
+	for i := 1; i <= len(cells); i++ {
+		if i < len(cells) && cells[i].col == cells[i-1].col && cells[i].row == cells[i-1].row+1 && copies(cells[start], cells[i]) {
+			continue
+		}
+		share(cells[start:i])
+		start = i
+	}
+	left := cells[:0]
+	for _, c := range cells {
+		if !shared[c] {
+			left = append(left, c)
+		}
+	}
+	sort.Slice(left, func(i, j int) bool {
+		if left[i].row != left[j].row {
+			return left[i].row < left[j].row
+		}
+		return left[i].col < left[j].col
+	})
+	start = 0
+	for i := 1; i <= len(left); i++ {
+		if i < len(left) && left[i].row == left[i-1].row && left[i].col == left[i-1].col+1 && copies(left[start], left[i]) {
+			continue
+		}
+		share(left[start:i])
+		start = i
+	}
+	return plan
+}
+
+// sharedFormulas returns the first cells of the shared formulas of the sheet,
+// which hold the formulas, by their shared index.
+func (s *Sheet) sharedFormulas() map[uint32]*sml.CT_Cell {
+	masters := map[uint32]*sml.CT_Cell{}
+	for _, r := range s._bcgb.SheetData.Row {
+		for _, c := range r.C {
+			if f := c.F; f != nil && f.TAttr == sml.ST_CellFormulaTypeShared && f.SiAttr != nil && f.Content != "" {
+				masters[*f.SiAttr] = c
+			}
+		}
+	}
+	return masters
+}
+
+// sharedFormula returns the formula of a cell that's part of a shared
+// formula, which is the formula of the first cell moved to the cell.
+func sharedFormula(c *sml.CT_Cell, masters map[uint32]*sml.CT_Cell) string {
+	if c.F.Content != "" || c.F.SiAttr == nil {
+		return c.F.Content
+	}
+	m := masters[*c.F.SiAttr]
+	if m == nil || m.RAttr == nil || c.RAttr == nil {
+		return ""
+	}
+	dc, dr, ok := cellOffset(*m.RAttr, *c.RAttr)
+	if !ok {
+		return ""
+	}
+	return copiedFormula(m.F.Content, dc, dr)
+}
+
+// expandSharedFormulas gives each cell of the shared formulas with cells
+// within a formula of its own.
+func (s *Sheet) expandSharedFormulas(a area) {
+	groups := map[uint32][]*sml.CT_Cell{}
+	within := map[uint32]bool{}
+	for _, r := range s._bcgb.SheetData.Row {
+		for _, c := range r.C {
+			f := c.F
+			if f == nil || f.TAttr != sml.ST_CellFormulaTypeShared || f.SiAttr == nil {
+				continue
+			}
+			groups[*f.SiAttr] = append(groups[*f.SiAttr], c)
+			if c.RAttr == nil {
+				continue
+			}
+			if ca, ok := s.areaOf(*c.RAttr); ok && ca.overlaps(a) {
+				within[*f.SiAttr] = true
+			}
+		}
+	}
+	masters := s.sharedFormulas()
+	for si := range within {
+		formulas := make([]string, len(groups[si]))
+		for i, c := range groups[si] {
+			formulas[i] = sharedFormula(c, masters)
+		}
+		for i, c := range groups[si] {
+			if formulas[i] == "" {
+				continue
+			}
+			c.F = sml.NewCT_CellFormula()
+			c.F.Content = formulas[i]
+		}
+	}
+}
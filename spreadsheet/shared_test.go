@@ -0,0 +1,65 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"testing"
+
+	"github.com/unidoc/unioffice/schema/soo/sml"
+)
+
+var sharedFormulaCells = map[string]string{
+	"B1": "A1*1E+20", "B2": "A2*1",
+	"C1": "A1%", "C2": "A2",
+	"D1": "A1:B1 B1:C1", "D2": "A2:B2",
+	"E1": "SUM($A$1:A1)", "E2": "SUM($A$1:A2)", "E3": "SUM($A$1:A3)",
+	"F1": "A1+1", "G1": "B1+1", "H1": "C1+1",
+	"I1": "\"A1\"&A1", "I2": "\"A1\"&A2",
+}
+
+func sharedFormulaSheet() (*Workbook, Sheet) {
+	wb := New()
+	s := wb.AddSheet()
+	for ref, f := range sharedFormulaCells {
+		s.Cell(ref).SetFormulaRaw(f)
+	}
+	return wb, s
+}
+
+func TestSaveWithSharedFormulas(t *testing.T) {
+	wb, _ := sharedFormulaSheet()
+	rd := saveAndRead(t, wb, WithSharedFormulas())
+	s := rd.Sheets()[0]
+	for ref, f := range sharedFormulaCells {
+		if got := s.Cell(ref).GetFormula(); got != f {
+			t.Errorf("%s: expected %s, got %s", ref, f, got)
+		}
+	}
+}
+
+func TestShareFormulas(t *testing.T) {
+	_, s := sharedFormulaSheet()
+	s.ShareFormulas()
+	for ref, shared := range map[string]bool{
+		"B1": false, "B2": false, "C1": false, "C2": false, "D1": false, "D2": false,
+		"E1": true, "E2": true, "E3": true, "F1": true, "G1": true, "H1": true,
+		"I1": true, "I2": true,
+	} {
+		if got := s.Cell(ref).X().F.TAttr == sml.ST_CellFormulaTypeShared; got != shared {
+			t.Errorf("%s: expected shared %v, got %v", ref, shared, got)
+		}
+	}
+	if ref := s.Cell("E1").X().F.RefAttr; ref == nil || *ref != "E1:E3" {
+		t.Errorf("expected the shared formula of E1 to span E1:E3, got %v", ref)
+	}
+	for ref, f := range sharedFormulaCells {
+		if got := s.Cell(ref).GetFormula(); got != f {
+			t.Errorf("%s: expected %s, got %s", ref, f, got)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/unidoc/unioffice"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+	"github.com/unidoc/unioffice/spreadsheet/update"
+)
+
+// SortBy is what a key of a sort compares.
+type SortBy byte
+
+// SortBy types
+const (
+	// SortByValue sorts by the values of the cells.
+	SortByValue SortBy = iota
+	// SortByCellColor sorts the cells with the fill color of the key first.
+	SortByCellColor
+	// SortByFontColor sorts the cells with the font color of the key first.
+	SortByFontColor
+)
+
+// SortKey is a level of a sort by SortRange or SortColumns.  Each key only
+// orders the rows or columns that the keys before it consider equal.
+type SortKey struct {
+	// Column is the column to sort rows by (e.g. "B") and Row is the row
+	// number to sort columns by.
+	Column string
+	Row    uint32
+	Order  SortOrder
+	By     SortBy
+	// Color is the differential style whose fill or font color comes first
+	// when sorting by colors, or last if descending.
+	Color DifferentialStyle
+	// CustomList sorts values by their order in a list (e.g. "Jan", "Feb",
+	// ...), where values that aren't in the list come after them.
+	CustomList []string
+}
+
+// SortRange sorts the rows of the range ref (e.g. "A2:D10") by one or more
+// keys, the same as sorting in Excel.  Unlike Sort, it only moves the cells
+// within the range.  Values are sorted as numbers, then text ignoring case,
+// then booleans and then errors, and blank cells always come last.  Formulas,
+// styles, hyperlinks, comments and merged cells move with their rows, where
+// relative references of formulas follow them as when copying.  The sort is
+// recorded as the sort state of the sheet, or of its autofilter if the range
+// is within it, so that Excel shows it.
+func (s *Sheet) SortRange(ref string, keys ...SortKey) error {
+	return s.sortRange(ref, false, keys)
+}
+
+// SortColumns sorts the columns of the range ref (e.g. "B1:H4") from left to
+// right by one or more keys, the same as SortRange sorts rows.
+func (s *Sheet) SortColumns(ref string, keys ...SortKey) error {
+	return s.sortRange(ref, true, keys)
+}
+
+// sortValue is the value of a cell for a sort key, where rank orders the
+// kinds of values and the positions of custom lists.
+type sortValue struct {
+	blank bool
+	rank  int
+	v     formula.Result
+}
+
+func (s *Sheet) sortRange(ref string, byColumns bool, keys []SortKey) error {
+	a, ok := s.areaOf(strings.Replace(ref, "$", "", -1))
+	if !ok {
+		return fmt.Errorf("invalid range %s", ref)
+	}
+	if len(keys) == 0 {
+		return errors.New("no sort keys")
+	}
+	first, lo, hi := a.row1, a.col1, a.col2
+	if byColumns {
+		first, lo, hi = a.col1, a.row1, a.row2
+	}
+	lines := int(a.row2 - a.row1 + 1)
+	if byColumns {
+		lines = int(a.col2 - a.col1 + 1)
+	}
+	pos := make([]uint32, len(keys))
+	for i, k := range keys {
+		name := k.Column
+		pos[i] = reference.ColumnToIndex(k.Column)
+		if byColumns {
+			name, pos[i] = strconv.Itoa(int(k.Row)), k.Row
+		}
+		if pos[i] < lo || pos[i] > hi {
+			return fmt.Errorf("sort key %s is outside of %s", name, ref)
+		}
+		if k.By != SortByValue && k.Color.X() == nil {
+			return fmt.Errorf("sort key %s has no color", name)
+		}
+	}
+	if err := s.checkMergedCells(a); err != nil {
+		return err
+	}
+	if err := s.checkArrays(a); err != nil {
+		return err
+	}
+	if err := s.checkSortLines(a, byColumns); err != nil {
+		return err
+	}
+
+	rows := map[uint32]*sml.CT_Row{}
+	cells := map[cellKey]*sml.CT_Cell{}
+	for _, r := range s._bcgb.SheetData.Row {
+		if r.RAttr == nil || *r.RAttr < a.row1 || *r.RAttr > a.row2 {
+			continue
+		}
+		rows[*r.RAttr] = r
+		for _, c := range r.C {
+			if c.RAttr == nil {
+				continue
+			}
+			if cr, err := reference.ParseCellReference(*c.RAttr); err == nil {
+				cells[cellKey{s._bcgb, cr.ColumnIdx, cr.RowIdx}] = c
+			}
+		}
+	}
+	values := make([][]sortValue, lines)
+	for i := range values {
+		values[i] = make([]sortValue, len(keys))
+		for j, k := range keys {
+			col, row := pos[j], first+uint32(i)
+			if byColumns {
+				col, row = row, col
+			}
+			values[i][j] = s.sortKeyValue(k, rows[row], cells[cellKey{s._bcgb, col, row}], col)
+		}
+	}
+	order := make([]int, lines)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		for k, key := range keys {
+			if c := compareSortValues(values[order[i]][k], values[order[j]][k], key.Order); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	moved := false
+	offsets := make([]int, lines)
+	for i, o := range order {
+		offsets[o] = i - o
+		moved = moved || i != o
+	}
+	if moved {
+		s.moveSortedLines(a, byColumns, offsets)
+	}
+	s.setSortState(a, byColumns, keys, pos)
+	return nil
+}
+
+// checkSortLines returns an error if a merged cell or an array formula within
+// a spans several of the rows, or columns, that are sorted.
+func (s *Sheet) checkSortLines(a area, byColumns bool) error {
+	spans := func(b area) bool {
+		if byColumns {
+			return b.col1 != b.col2
+		}
+		return b.row1 != b.row2
+	}
+	for _, m := range s.mergedCellsWithin(a) {
+		if ma, ok := s.areaOf(m); ok && spans(ma) {
+			return fmt.Errorf("can't sort the merged cell %s", m)
+		}
+	}
+	for _, r := range s._bcgb.SheetData.Row {
+		for _, c := range r.C {
+			if c.F == nil || c.F.TAttr != sml.ST_CellFormulaTypeArray {
+				continue
+			}
+			from, to, ok := s.arrayFormulaRange(c)
+			fa := area{ws: s._bcgb, col1: from.ColumnIdx, row1: from.RowIdx, col2: to.ColumnIdx, row2: to.RowIdx}
+			if ok && fa.within(a) && spans(fa) {
+				return fmt.Errorf("can't sort the array formula in %s", rangeString(from, to))
+			}
+		}
+	}
+	return nil
+}
+
+// sortKeyValue returns the value of the cell of a row at a column for a sort
+// key, where the row and cell may not exist.
+func (s *Sheet) sortKeyValue(k SortKey, r *sml.CT_Row, c *sml.CT_Cell, col uint32) sortValue {
+	switch k.By {
+	case SortByCellColor, SortByFontColor:
+		dxf := k.Color.X()
+		fill, font := s.cellColors(r, c, col)
+		have, want, ok := fill, uint32(0), false
+		if k.By == SortByCellColor {
+			if dxf.Fill != nil {
+				want, ok = s._bdb.dxfFillRGB(dxf.Fill)
+			}
+		} else {
+			have = font
+			if dxf.Font != nil && len(dxf.Font.Color) > 0 {
+				want, ok = s._bdb.rgb(dxf.Font.Color[0])
+			}
+		}
+		if have.ok == ok && (!ok || have.rgb == want) {
+			return sortValue{}
+		}
+		return sortValue{rank: 1}
+	}
+	v := cellValue(Cell{s._bdb, s, r, c})
+	if v.Type == formula.ResultTypeEmpty || v.Type == formula.ResultTypeString && v.ValueString == "" {
+		return sortValue{blank: true}
+	}
+	rank := 2
+	switch {
+	case isNumber(v):
+		rank = 0
+	case v.Type == formula.ResultTypeString:
+		rank = 1
+	case v.Type == formula.ResultTypeError:
+		rank = 3
+	}
+	if len(k.CustomList) > 0 && v.Type != formula.ResultTypeError {
+		for i, item := range k.CustomList {
+			if strings.EqualFold(strings.TrimSpace(item), v.Value()) {
+				return sortValue{rank: i, v: v}
+			}
+		}
+		rank += len(k.CustomList)
+	}
+	return sortValue{rank: rank, v: v}
+}
+
+// compareSortValues compares the values of two cells for a sort key, where
+// blank cells come last in either order.
+func compareSortValues(a, b sortValue, order SortOrder) int {
+	switch {
+	case a.blank && b.blank:
+		return 0
+	case a.blank:
+		return 1
+	case b.blank:
+		return -1
+	}
+	c := a.rank - b.rank
+	if c == 0 && a.v.Type != formula.ResultTypeError {
+		c, _ = compareResults(a.v, b.v)
+	}
+	if order == SortOrderDescending {
+		c = -c
+	}
+	return c
+}
+
+// moveSortedLines moves the rows, or columns, of a by their offsets, along
+// with their merged cells, hyperlinks and comments.
+func (s *Sheet) moveSortedLines(a area, byColumns bool, offsets []int) {
+	query := func(col, row uint32) *update.UpdateQuery {
+		q := &update.UpdateQuery{UpdateType: update.UpdateActionCopy}
+		if byColumns {
+			q.ColumnOffset = offsets[col-a.col1]
+		} else {
+			q.RowOffset = offsets[row-a.row1]
+		}
+		return q
+	}
+	move := func(ref string) string {
+		ra, ok := s.areaOf(ref)
+		if !ok || !ra.within(a) {
+			return ref
+		}
+		if moved, ok := moveRef(ref, query(ra.col1, ra.row1)); ok {
+			return moved
+		}
+		return ref
+	}
+
+	recalc := len(s._bdb.spills[s._bcgb]) > 0
+	s.clearSpills()
+	s.expandSharedFormulas(a)
+	cells := []*sml.CT_Cell{}
+	for _, c := range s.cellsWithin(a) {
+		cr, err := reference.ParseCellReference(*c.RAttr)
+		if err != nil {
+			continue
+		}
+		recalc = recalc || c.F != nil
+		cells = append(cells, copyCell(c, query(cr.ColumnIdx, cr.RowIdx)))
+	}
+	s.removeCells(a)
+	s.placeCells(cells)
+
+	if mc := s._bcgb.MergeCells; mc != nil {
+		for _, m := range mc.MergeCell {
+			m.RefAttr = move(m.RefAttr)
+		}
+	}
+	if hls := s._bcgb.Hyperlinks; hls != nil {
+		for _, hl := range hls.Hyperlink {
+			hl.RefAttr = move(hl.RefAttr)
+		}
+	}
+	idx := s.index()
+	wb := s._bdb
+	if idx >= 0 && idx < len(wb._cbge) && wb._cbge[idx] != nil && wb._cbge[idx].CommentList != nil {
+		for _, c := range wb._cbge[idx].CommentList.Comment {
+			c.RefAttr = move(c.RefAttr)
+		}
+	}
+	if vml := s.commentDrawing(); vml != nil {
+		for _, sh := range vml.Shape {
+			for _, el := range sh.EG_ShapeElements {
+				cd := el.ClientData
+				if cd == nil || cd.Row == nil || cd.Column == nil {
+					continue
+				}
+				col, row := uint32(*cd.Column), uint32(*cd.Row+1)
+				if !a.contains(cellKey{a.ws, col, row}) {
+					continue
+				}
+				q := query(col, row)
+				*cd.Column += int64(q.ColumnOffset)
+				*cd.Row += int64(q.RowOffset)
+			}
+		}
+	}
+	if recalc {
+		s.RecalculateFormulas()
+	}
+}
+
+// setSortState records the keys of a sort of a as the sort state of the
+// autofilter of the sheet if a is within its rows, or else of the sheet.
+func (s *Sheet) setSortState(a area, byColumns bool, keys []SortKey, pos []uint32) {
+	ss := sml.NewCT_SortState()
+	ss.RefAttr = areaReference(a)
+	if byColumns {
+		ss.ColumnSortAttr = unioffice.Bool(true)
+	}
+	for i, k := range keys {
+		line := a
+		if byColumns {
+			line.row1, line.row2 = pos[i], pos[i]
+		} else {
+			line.col1, line.col2 = pos[i], pos[i]
+		}
+		sc := SortCondition{sml.NewCT_SortCondition()}
+		sc.x.RefAttr = areaReference(line)
+		if k.Order == SortOrderDescending {
+			sc.x.DescendingAttr = unioffice.Bool(true)
+		}
+		switch k.By {
+		case SortByCellColor:
+			sc.SetCellColor(k.Color)
+		case SortByFontColor:
+			sc.SetFontColor(k.Color)
+		default:
+			if len(k.CustomList) > 0 {
+				sc.SetCustomList(k.CustomList)
+			}
+		}
+		ss.SortCondition = append(ss.SortCondition, sc.x)
+	}
+	if af := s._bcgb.AutoFilter; af != nil && af.RefAttr != nil && !byColumns {
+		if fa, ok := s.areaOf(*af.RefAttr); ok && fa.row1 < a.row1 && a.within(fa) {
+			af.SortState = ss
+			return
+		}
+	}
+	s._bcgb.SortState = ss
+}
@@ -0,0 +1,247 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/unidoc/unioffice/color"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+)
+
+// columnValues returns the formatted values of a column from row 1 to n.
+func columnValues(s Sheet, col string, n int) string {
+	vals := make([]string, n)
+	for i := range vals {
+		vals[i] = s.Cell(fmt.Sprintf("%s%d", col, i+1)).GetFormattedValue()
+	}
+	return strings.Join(vals, ",")
+}
+
+// sortSheet returns a sheet with the values of each column from row 1.
+func sortSheet(cols map[string][]interface{}) (*Workbook, Sheet) {
+	wb := New()
+	s := wb.AddSheet()
+	for col, vals := range cols {
+		for i, v := range vals {
+			c := s.Cell(fmt.Sprintf("%s%d", col, i+1))
+			switch v := v.(type) {
+			case int:
+				c.SetNumber(float64(v))
+			case bool:
+				c.SetBool(v)
+			case string:
+				if strings.HasPrefix(v, "=") {
+					c.SetFormulaRaw(v[1:])
+				} else if v != "" {
+					c.SetString(v)
+				}
+			}
+		}
+	}
+	return wb, s
+}
+
+func TestSortRange(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		keys []SortKey
+		exp  map[string]string
+	}{
+		{"keys", []SortKey{{Column: "A"}, {Column: "B", Order: SortOrderDescending}},
+			map[string]string{"A": "A,a,a,b,b,", "B": "9,2,1,3,1,5"}},
+		{"stable", []SortKey{{Column: "A"}},
+			map[string]string{"A": "a,A,a,b,b,", "B": "2,9,1,3,1,5"}},
+		{"kinds", []SortKey{{Column: "C"}},
+			map[string]string{"C": "1,2,x,Y,TRUE,", "A": "A,a,b,a,b,"}},
+		{"kinds descending", []SortKey{{Column: "C", Order: SortOrderDescending}},
+			map[string]string{"C": "TRUE,Y,x,2,1,", "A": "b,a,b,a,A,"}},
+		{"custom list", []SortKey{{Column: "D", CustomList: []string{"Jan", " feb", "Mar"}}},
+			map[string]string{"D": "jan,Feb,Mar,Apr,Dec,", "A": "a,b,A,a,b,"}},
+		{"custom list descending", []SortKey{{Column: "D", Order: SortOrderDescending, CustomList: []string{"Jan", "Feb", "Mar"}}},
+			map[string]string{"D": "Dec,Apr,Mar,Feb,jan,", "A": "b,a,A,b,a,"}},
+	} {
+		_, s := sortSheet(map[string][]interface{}{
+			"A": {"b", "a", "b", "", "A", "a"},
+			"B": {3, 2, 1, 5, 9, 1},
+			"C": {"x", 2, true, "", 1, "Y"},
+			"D": {"Feb", "Apr", "Dec", "", "Mar", "jan"},
+		})
+		if err := s.SortRange("A1:D6", tc.keys...); err != nil {
+			t.Fatal(err)
+		}
+		for col, exp := range tc.exp {
+			if got := columnValues(s, col, 6); got != exp {
+				t.Errorf("%s: expected %s in column %s, got %s", tc.name, exp, col, got)
+			}
+		}
+	}
+}
+
+func TestSortRangeColors(t *testing.T) {
+	wb, s := sortSheet(map[string][]interface{}{
+		"A": {1, 2, 3, 4, 5},
+	})
+	yellow := wb.StyleSheet.AddCellStyle()
+	fill := wb.StyleSheet.Fills().AddFill()
+	pf := fill.SetPatternFill()
+	pf.SetPattern(sml.ST_PatternTypeSolid)
+	pf.SetFgColor(color.Yellow)
+	yellow.SetFill(fill)
+	red := wb.StyleSheet.AddCellStyle()
+	f := wb.StyleSheet.AddFont()
+	f.SetColor(color.Red)
+	red.SetFont(f)
+	s.Cell("A2").SetStyle(red)
+	s.Cell("A3").SetStyle(yellow)
+	s.Cell("A5").SetStyle(yellow)
+
+	byFill := wb.StyleSheet.AddDifferentialStyle()
+	byFill.Fill().SetPatternFill().SetBgColor(color.Yellow)
+	if err := s.SortRange("A1:A5", SortKey{Column: "A", By: SortByCellColor, Color: byFill}); err != nil {
+		t.Fatal(err)
+	}
+	if got := columnValues(s, "A", 5); got != "3,5,1,2,4" {
+		t.Errorf("expected the yellow cells first, got %s", got)
+	}
+	if err := s.SortRange("A1:A5", SortKey{Column: "A", By: SortByCellColor, Color: byFill, Order: SortOrderDescending}); err != nil {
+		t.Fatal(err)
+	}
+	if got := columnValues(s, "A", 5); got != "1,2,4,3,5" {
+		t.Errorf("expected the yellow cells last, got %s", got)
+	}
+
+	byFont := wb.StyleSheet.AddDifferentialStyle()
+	byFont.X().Font = sml.NewCT_Font()
+	byFont.X().Font.Color = []*sml.CT_Color{{RgbAttr: color.Red.AsRGBAString()}}
+	if err := s.SortRange("A1:A5", SortKey{Column: "A", By: SortByFontColor, Color: byFont}); err != nil {
+		t.Fatal(err)
+	}
+	if got := columnValues(s, "A", 5); got != "2,1,4,3,5" {
+		t.Errorf("expected the red cell first, got %s", got)
+	}
+
+	ss := s.X().SortState
+	if ss == nil || ss.RefAttr != "A1:A5" || len(ss.SortCondition) != 1 {
+		t.Fatalf("expected the sort state of A1:A5")
+	}
+	if sc := ss.SortCondition[0]; sc.SortByAttr != sml.ST_SortByFontColor || sc.DxfIdAttr == nil || *sc.DxfIdAttr != byFont.Index() {
+		t.Errorf("expected the sort state to be by font color, got %s", sc.SortByAttr)
+	}
+}
+
+func TestSortRangeMovesCells(t *testing.T) {
+	_, s := sortSheet(map[string][]interface{}{
+		"A": {"Name", "c", "a", "b"},
+		"B": {"Total", "=C2*10", "=C3*10", "=SUM($C$2:C4)"},
+		"C": {"", 1, 2, 3},
+		"E": {"=B2", "=SUM(B2:B4)"},
+	})
+	s.AddMergedCells("C4", "D4")
+	s.Cell("A3").SetHyperlink(s.AddHyperlink("http://example.com"))
+	s.Comments().AddCommentWithStyle("A2", "author", "note")
+	s.RecalculateFormulas()
+
+	if err := s.SortRange("A2:D4", SortKey{Column: "A"}); err != nil {
+		t.Fatal(err)
+	}
+	checkCells(t, s, map[string]string{"A2": "a", "A3": "b", "A4": "c", "B2": "20", "B3": "5", "B4": "10"})
+	checkFormulas(t, s, map[string]string{
+		// relative references follow the rows as when copying
+		"B2": "C2*10",
+		"B3": "SUM($C$2:C3)",
+		"B4": "C4*10",
+		// references from outside of the range are unchanged
+		"E1": "B2",
+	})
+	if got := s.Cell("E1").GetFormattedValue(); got != "20" {
+		t.Errorf("expected the formulas referring to the range to be recalculated, got %s", got)
+	}
+	if m := mergedRefs(s); len(m) != 1 || !m["C3:D3"] {
+		t.Errorf("expected the merged cells to move, got %v", m)
+	}
+	if hls := s.X().Hyperlinks; hls == nil || len(hls.Hyperlink) != 1 || hls.Hyperlink[0].RefAttr != "A2" {
+		t.Errorf("expected the hyperlink to move")
+	}
+	if c := commentRefs(s); len(c) != 1 || !c["A4"] {
+		t.Errorf("expected the comment to move, got %v", c)
+	}
+}
+
+func TestSortColumns(t *testing.T) {
+	wb, s := sortSheet(map[string][]interface{}{
+		"A": {"Key", "Value"},
+		"B": {"c", 3},
+		"C": {"a", 1},
+		"D": {"b", 2},
+	})
+	if err := s.SortColumns("B1:D2", SortKey{Row: 1, CustomList: []string{"b"}}); err != nil {
+		t.Fatal(err)
+	}
+	checkCells(t, s, map[string]string{"A1": "Key", "B1": "b", "C1": "a", "D1": "c", "B2": "2", "C2": "1", "D2": "3"})
+
+	s = saveAndRead(t, wb).Sheets()[0]
+	ss := s.X().SortState
+	if ss == nil || ss.RefAttr != "B1:D2" || ss.ColumnSortAttr == nil || !*ss.ColumnSortAttr {
+		t.Fatalf("expected the column sort state of B1:D2 to be saved")
+	}
+	if sc := ss.SortCondition; len(sc) != 1 || sc[0].RefAttr != "B1:D1" || sc[0].CustomListAttr == nil || *sc[0].CustomListAttr != "b" {
+		t.Errorf("expected the sort condition of B1:D1 with a custom list")
+	}
+}
+
+func TestSortRangeAutoFilter(t *testing.T) {
+	wb, s := sortSheet(map[string][]interface{}{
+		"A": {"Name", "b", "a"},
+		"B": {"Amount", 1, 2},
+	})
+	s.SetAutoFilter("A1:B3")
+	if err := s.SortRange("A2:B3", SortKey{Column: "B", Order: SortOrderDescending}); err != nil {
+		t.Fatal(err)
+	}
+	s = saveAndRead(t, wb).Sheets()[0]
+	if s.X().SortState != nil {
+		t.Errorf("expected the sort state to be of the autofilter")
+	}
+	af, _ := s.AutoFilter()
+	ss := af.X().SortState
+	if ss == nil || ss.RefAttr != "A2:B3" || len(ss.SortCondition) != 1 {
+		t.Fatalf("expected the sort state of A2:B3 to be saved")
+	}
+	if sc := ss.SortCondition[0]; sc.RefAttr != "B2:B3" || sc.DescendingAttr == nil || !*sc.DescendingAttr {
+		t.Errorf("expected B2:B3 to be sorted descending, got %s", sc.RefAttr)
+	}
+	checkCells(t, s, map[string]string{"A2": "a", "A3": "b"})
+}
+
+func TestSortRangeErrors(t *testing.T) {
+	_, s := sortSheet(map[string][]interface{}{
+		"A": {1, 2, 3},
+		"B": {1, 2, 3},
+	})
+	s.AddMergedCells("B2", "B3")
+	for _, tc := range []struct {
+		ref  string
+		keys []SortKey
+	}{
+		{"A1:A", []SortKey{{Column: "A"}}},
+		{"A1:A3", nil},
+		{"A1:A3", []SortKey{{Column: "B"}}},
+		{"A1:A3", []SortKey{{Column: "A", By: SortByCellColor}}},
+		{"A1:B3", []SortKey{{Column: "A"}}},
+	} {
+		if err := s.SortRange(tc.ref, tc.keys...); err == nil {
+			t.Errorf("expected an error sorting %s by %v", tc.ref, tc.keys)
+		}
+	}
+	if got := columnValues(s, "A", 3); got != "1,2,3" {
+		t.Errorf("expected the cells not to move, got %s", got)
+	}
+}
@@ -0,0 +1,445 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/unidoc/unioffice"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+)
+
+// spillRows returns the rows of an array result that spills.  Lists are
+// spilled as a single row.
+func spillRows(r formula.Result) [][]formula.Result {
+	switch r.Type {
+	case formula.ResultTypeArray:
+		return r.ValueArray
+	case formula.ResultTypeList:
+		return [][]formula.Result{r.ValueList}
+	}
+	return nil
+}
+
+// spillResult returns the result of a cell as seen by a reference to it.  A
+// reference to the anchor of a spilled array (e.g. A1) sees the top-left value,
+// while the spill range operator (e.g. A1#) sees the whole array.
+func spillResult(r formula.Result, spill bool) formula.Result {
+	if spill {
+		return r
+	}
+	rows := spillRows(r)
+	if len(rows) == 0 {
+		return r
+	}
+	if len(rows[0]) == 0 {
+		return formula.MakeEmptyResult()
+	}
+	return rows[0][0]
+}
+
+// clearSpills removes the values that a previous recalculation spilled into
+// the sheet.  Cells that have been modified since are left untouched.
+func (s *Sheet) clearSpills() {
+	for anchor := range s._bdb.spills[s._bcgb] {
+		s.clearSpill(anchor)
+	}
+	delete(s._bdb.spills, s._bcgb)
+}
+
+// clearSpill removes the values spilled from a formula cell, returning the
+// cells that were spilled into.
+func (s *Sheet) clearSpill(anchor *sml.CT_Cell) []*sml.CT_Cell {
+	spilled := s._bdb.spills[s._bcgb][anchor]
+	cells := make([]*sml.CT_Cell, 0, len(spilled))
+	for c, v := range spilled {
+		if c.F == nil && c.V == v {
+			c.V = nil
+			c.TAttr = sml.ST_CellTypeUnset
+		}
+		cells = append(cells, c)
+	}
+	delete(s._bdb.spills[s._bcgb], anchor)
+	return cells
+}
+
+// spillsWithin returns the references of the formula cells within an area
+// whose arrays spilled along with the references of the cells within the area
+// that they spilled into.
+func (s *Sheet) spillsWithin(a area) (map[string]bool, map[string]bool) {
+	anchors, spilled := map[string]bool{}, map[string]bool{}
+	for anchor, cells := range s._bdb.spills[s._bcgb] {
+		if anchor.RAttr == nil || !a.containsCell(anchor) {
+			continue
+		}
+		anchors[*anchor.RAttr] = true
+		for c := range cells {
+			if c.RAttr != nil && a.containsCell(c) {
+				spilled[*c.RAttr] = true
+			}
+		}
+	}
+	return anchors, spilled
+}
+
+// spillsOver returns the formula cells whose arrays spilled into or from an
+// area, along with those above and to the left of it whose spills were
+// blocked, as the area may have blocked them.
+func (s *Sheet) spillsOver(a area) []*sml.CT_Cell {
+	anchors := []*sml.CT_Cell{}
+	for anchor, cells := range s._bdb.spills[s._bcgb] {
+		over := a.containsCell(anchor)
+		for c := range cells {
+			over = over || a.containsCell(c)
+		}
+		if over {
+			anchors = append(anchors, anchor)
+		}
+	}
+	blocked := formula.MakeErrorResultType(formula.ErrorTypeSpill, "").ValueString
+	for _, r := range s._bcgb.SheetData.Row {
+		if r.RAttr == nil || *r.RAttr > a.row2 {
+			continue
+		}
+		for _, c := range r.C {
+			if c.F != nil && c.TAttr == sml.ST_CellTypeE && c.V != nil && *c.V == blocked && columnOf(c) <= a.col2 {
+				anchors = append(anchors, c)
+			}
+		}
+	}
+	return anchors
+}
+
+// spillBlocked returns whether the array result of the formula of a cell
+// couldn't spill because the range it spills into isn't empty.
+func (s *Sheet) spillBlocked(anchor *sml.CT_Cell, r formula.Result) bool {
+	if f := anchor.F; f == nil || f.TAttr == sml.ST_CellFormulaTypeArray || f.TAttr == sml.ST_CellFormulaTypeShared {
+		return false
+	}
+	if rows := spillRows(r); len(rows) == 0 || len(rows[0]) == 0 {
+		return false
+	}
+	_, ok := s._bdb.spills[s._bcgb][anchor]
+	return !ok
+}
+
+// findCell returns the cell with the given reference without creating it.
+func (s *Sheet) findCell(ref reference.CellReference) *sml.CT_Cell {
+	name := ref.String()
+	for _, r := range s._bcgb.SheetData.Row {
+		if r.RAttr == nil || *r.RAttr != ref.RowIdx {
+			continue
+		}
+		for _, c := range r.C {
+			if c.RAttr != nil && *c.RAttr == name {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// spill writes an array result of the formula in a cell into the range below
+// and to the right of it, replacing any values it spilled before.  If any cell
+// of the range isn't empty, nothing is written and the formula cell is set to
+// a #SPILL! error.
+func (s *Sheet) spill(c Cell, r formula.Result) {
+	s.clearSpill(c.X())
+	rows := spillRows(r)
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return
+	}
+	anchor, err := reference.ParseCellReference(c.Reference())
+	if err != nil {
+		return
+	}
+	for i, row := range rows {
+		for j := range row {
+			if i == 0 && j == 0 {
+				continue
+			}
+			ref := reference.CellReference{RowIdx: anchor.RowIdx + uint32(i), ColumnIdx: anchor.ColumnIdx + uint32(j)}
+			ref.Column = reference.IndexToColumn(ref.ColumnIdx)
+			if x := s.findCell(ref); x != nil && !(Cell{c._ebb, s, nil, x}).IsEmpty() {
+				unioffice.Log("formula in %s can't spill into %s", c.Reference(), ref.String())
+				c.X().TAttr = sml.ST_CellTypeE
+				c.X().V = unioffice.String(formula.MakeErrorResultType(formula.ErrorTypeSpill, "").ValueString)
+				return
+			}
+		}
+	}
+	spilled := map[*sml.CT_Cell]*string{}
+	s.setSpilled(c.X(), spilled)
+	for i, row := range rows {
+		for j, v := range row {
+			if i == 0 && j == 0 {
+				setFormulaResult(c, v)
+				continue
+			}
+			sc := s.Cell(fmt.Sprintf("%s%d", reference.IndexToColumn(anchor.ColumnIdx+uint32(j)), anchor.RowIdx+uint32(i)))
+			setSpilledValue(sc, v)
+			spilled[sc.X()] = sc.X().V
+		}
+	}
+}
+
+// setSpilled records the cells that a formula cell spilled into, along with
+// the values it spilled into them.
+func (s *Sheet) setSpilled(anchor *sml.CT_Cell, spilled map[*sml.CT_Cell]*string) {
+	if s._bdb.spills == nil {
+		s._bdb.spills = map[*sml.Worksheet]map[*sml.CT_Cell]map[*sml.CT_Cell]*string{}
+	}
+	if s._bdb.spills[s._bcgb] == nil {
+		s._bdb.spills[s._bcgb] = map[*sml.CT_Cell]map[*sml.CT_Cell]*string{}
+	}
+	s._bdb.spills[s._bcgb][anchor] = spilled
+}
+
+// setFormulaResult sets the cached result of a formula cell with the type of
+// the result.
+func setFormulaResult(c Cell, v formula.Result) {
+	x := c.X()
+	switch {
+	case v.Type == formula.ResultTypeError:
+		x.TAttr = sml.ST_CellTypeE
+		x.V = unioffice.String(v.ValueString)
+	case v.IsBoolean:
+		x.TAttr = sml.ST_CellTypeB
+		x.V = unioffice.String(strconv.Itoa(int(v.ValueNumber)))
+	case v.Type == formula.ResultTypeNumber, v.Type == formula.ResultTypeEmpty:
+		x.TAttr = sml.ST_CellTypeN
+		x.V = unioffice.String(strconv.FormatFloat(v.ValueNumber, 'f', -1, 64))
+	default:
+		x.TAttr = sml.ST_CellTypeStr
+		x.V = unioffice.String(v.Value())
+	}
+}
+
+// setSpilledValue sets a cell to a value spilled from an array.  Blanks in
+// the array spill as zero like they do in Excel.
+func setSpilledValue(c Cell, v formula.Result) {
+	switch {
+	case v.Type == formula.ResultTypeError:
+		c.SetError(v.ValueString)
+	case v.IsBoolean:
+		c.SetBool(v.ValueNumber != 0)
+	case v.Type == formula.ResultTypeNumber, v.Type == formula.ResultTypeEmpty:
+		c.SetNumber(v.ValueNumber)
+	default:
+		c.SetString(v.Value())
+	}
+}
+
+const (
+	sheetMetadataType        = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/sheetMetadata"
+	sheetMetadataContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheetMetadata+xml"
+	sheetMetadataFilename    = "xl/metadata.xml"
+
+	// dynamicArrayMetadata is the metadata that Excel writes to mark formulas
+	// as dynamic arrays, where cells with the cell metadata 1 are dynamic
+	// arrays.
+	dynamicArrayMetadata = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<metadata xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:xda="http://schemas.microsoft.com/office/spreadsheetml/2017/dynamicarray"><metadataTypes count="1"><metadataType name="XLDAPR" minSupportedVersion="120000" copy="1" pasteAll="1" pasteValues="1" merge="1" splitFirst="1" rowColShift="1" clearFormats="1" clearComments="1" assign="1" coerce="1" cellMeta="1"/></metadataTypes><futureMetadata name="XLDAPR" count="1"><bk><extLst><ext uri="{bdbb8cdc-fa1e-496e-a857-3c3f30c029c3}"><xda:dynamicArrayProperties fDynamic="1" fCollapsed="0"/></ext></extLst></bk></futureMetadata><cellMetadata count="1"><bk><rc t="1" v="0"/></bk></cellMetadata></metadata>`
+)
+
+// metadataFile returns the path within the package of the sheet metadata of
+// a workbook, returning false if it has none.
+func (wb *Workbook) metadataFile() (string, bool) {
+	for _, r := range wb._adebd.X().Relationship {
+		if r.TypeAttr != sheetMetadataType {
+			continue
+		}
+		if strings.HasPrefix(r.TargetAttr, "/") {
+			return r.TargetAttr[1:], true
+		}
+		return path.Join("xl", r.TargetAttr), true
+	}
+	return "", false
+}
+
+// dynamicArrayMetadataIndex returns the index of the cell metadata that marks
+// dynamic arrays in the sheet metadata that a workbook was read with, or zero
+// if there isn't one.
+func (wb *Workbook) dynamicArrayMetadataIndex() uint32 {
+	fn, ok := wb.metadataFile()
+	if !ok {
+		return 0
+	}
+	for _, f := range wb.ExtraFiles {
+		if f.ZipPath != fn {
+			continue
+		}
+		b, err := ioutil.ReadFile(f.DiskPath)
+		if err != nil {
+			return 0
+		}
+		md := struct {
+			Types []struct {
+				Name string `xml:"name,attr"`
+			} `xml:"metadataTypes>metadataType"`
+			Cells []struct {
+				Records []struct {
+					Type uint32 `xml:"t,attr"`
+				} `xml:"rc"`
+			} `xml:"cellMetadata>bk"`
+		}{}
+		if err := xml.Unmarshal(b, &md); err != nil {
+			return 0
+		}
+		for i, bk := range md.Cells {
+			for _, rc := range bk.Records {
+				if rc.Type >= 1 && int(rc.Type) <= len(md.Types) && md.Types[rc.Type-1].Name == "XLDAPR" {
+					return uint32(i + 1)
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// readDynamicArrays turns the dynamic array formulas of a read workbook into
+// formulas that spill, so that the values they spilled before are replaced
+// rather than block them when they're recalculated.
+func (wb *Workbook) readDynamicArrays() {
+	cm := wb.dynamicArrayMetadataIndex()
+	if cm == 0 {
+		return
+	}
+	for _, ws := range wb._fbed {
+		if ws.SheetData == nil {
+			continue
+		}
+		s := Sheet{wb, nil, ws}
+		for _, r := range ws.SheetData.Row {
+			for _, c := range r.C {
+				f := c.F
+				if c.CmAttr == nil || *c.CmAttr != cm || f == nil || f.TAttr != sml.ST_CellFormulaTypeArray || f.RefAttr == nil {
+					continue
+				}
+				a, ok := s.areaOf(*f.RefAttr)
+				f.TAttr = sml.ST_CellFormulaTypeUnset
+				f.RefAttr = nil
+				c.CmAttr = nil
+				if ok {
+					s.setSpilled(c, s.cellsIn(a, c))
+				}
+			}
+		}
+	}
+}
+
+// cellsIn returns the cells without formulas within an area other than the
+// anchor along with their values.
+func (s *Sheet) cellsIn(a area, anchor *sml.CT_Cell) map[*sml.CT_Cell]*string {
+	cells := map[*sml.CT_Cell]*string{}
+	for _, r := range s._bcgb.SheetData.Row {
+		if r.RAttr == nil || *r.RAttr < a.row1 || *r.RAttr > a.row2 {
+			continue
+		}
+		for _, c := range r.C {
+			if col := columnOf(c); c != anchor && c.F == nil && col >= a.col1 && col <= a.col2 {
+				cells[c] = c.V
+			}
+		}
+	}
+	return cells
+}
+
+// dynamicArraysForSave marks the formulas that spill as dynamic arrays, which
+// Excel stores as array formulas over the range they spill into along with
+// cell metadata.  It returns a function that writes the metadata if the
+// workbook doesn't have any, and a function that restores the formulas.  If
+// the workbook was read with metadata that doesn't mark dynamic arrays, the
+// formulas are stored as plain array formulas.
+func (wb *Workbook) dynamicArraysForSave() (func(*zip.Writer) error, func()) {
+	type saved struct {
+		f  *sml.CT_CellFormula
+		cm *uint32
+	}
+	old := map[*sml.CT_Cell]saved{}
+	var cm *uint32
+	write, remove := func(*zip.Writer) error { return nil }, func() {}
+	for _, ws := range wb._fbed {
+		if _, ok := wb.streamingSheets[ws]; ok {
+			continue
+		}
+		for anchor, spilled := range wb.spills[ws] {
+			if anchor.F == nil || anchor.RAttr == nil {
+				continue
+			}
+			ref, err := reference.ParseCellReference(*anchor.RAttr)
+			if err != nil {
+				continue
+			}
+			if len(old) == 0 {
+				cm, write, remove = wb.metadataForSave()
+			}
+			to := ref
+			for c := range spilled {
+				if cr, err := reference.ParseCellReference(*c.RAttr); err == nil {
+					if cr.ColumnIdx > to.ColumnIdx {
+						to.ColumnIdx = cr.ColumnIdx
+					}
+					if cr.RowIdx > to.RowIdx {
+						to.RowIdx = cr.RowIdx
+					}
+				}
+			}
+			old[anchor] = saved{anchor.F, anchor.CmAttr}
+			f := *anchor.F
+			f.TAttr = sml.ST_CellFormulaTypeArray
+			f.RefAttr = unioffice.String(rangeString(ref, to))
+			anchor.F = &f
+			anchor.CmAttr = cm
+		}
+	}
+	return write, func() {
+		for c, o := range old {
+			c.F, c.CmAttr = o.f, o.cm
+		}
+		remove()
+	}
+}
+
+// metadataForSave returns the index of the cell metadata that marks dynamic
+// arrays, adding the metadata to the workbook while it's saved if it has
+// none, along with functions that write and remove the metadata.  The index
+// is nil if the metadata the workbook was read with doesn't mark dynamic
+// arrays.
+func (wb *Workbook) metadataForSave() (*uint32, func(*zip.Writer) error, func()) {
+	if _, ok := wb.metadataFile(); ok {
+		var cm *uint32
+		if idx := wb.dynamicArrayMetadataIndex(); idx != 0 {
+			cm = unioffice.Uint32(idx)
+		}
+		return cm, func(*zip.Writer) error { return nil }, func() {}
+	}
+	rel := wb._adebd.AddRelationship("metadata.xml", sheetMetadataType)
+	wb.ContentTypes.AddOverride(sheetMetadataFilename, sheetMetadataContentType)
+	write := func(z *zip.Writer) error {
+		w, err := z.Create(sheetMetadataFilename)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write([]byte(dynamicArrayMetadata))
+		return err
+	}
+	remove := func() {
+		wb._adebd.Remove(rel)
+		wb.ContentTypes.RemoveOverride(sheetMetadataFilename)
+	}
+	return unioffice.Uint32(1), write, remove
+}
@@ -0,0 +1,175 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+)
+
+// spillValues returns the values of the cells of a column.
+func spillValues(s Sheet, col string, rows int) []string {
+	values := []string{}
+	for i := 1; i <= rows; i++ {
+		values = append(values, s.Cell(col+string(rune('0'+i))).GetFormattedValue())
+	}
+	return values
+}
+
+func TestSpill(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	for i, v := range []string{"b", "a", "b", "c", "a"} {
+		s.Cell("A" + string(rune('1'+i))).SetString(v)
+	}
+	s.Cell("D1").SetFormulaRaw("SORT(UNIQUE(A1:A5))")
+	wb.RecalculateFormulas()
+	exp := []string{"a", "b", "c", ""}
+	check := func(s Sheet, label string) {
+		t.Helper()
+		got := spillValues(s, "D", 4)
+		for i := range exp {
+			if got[i] != exp[i] {
+				t.Errorf("%s: expected %v, got %v", label, exp, got)
+				break
+			}
+		}
+	}
+	check(s, "spilled")
+
+	s.Cell("D2").SetString("x")
+	wb.RecalculateFormulas()
+	if got := s.Cell("D1").GetFormattedValue(); got != "#SPILL!" {
+		t.Errorf("expected a blocked spill, got %s", got)
+	}
+	s.Cell("D2").Clear()
+	wb.RecalculateFormulas()
+	check(s, "unblocked")
+}
+
+func TestSpillSaved(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	for i, v := range []string{"b", "a", "b", "c", "a"} {
+		s.Cell("A" + string(rune('1'+i))).SetString(v)
+	}
+	s.Cell("D1").SetFormulaRaw("SORT(UNIQUE(A1:A5))")
+	wb.RecalculateFormulas()
+
+	buf := bytes.Buffer{}
+	if err := wb.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if f := s.Cell("D1").X().F; f.TAttr != sml.ST_CellFormulaTypeUnset || f.RefAttr != nil {
+		t.Errorf("expected the formula to be restored after saving")
+	}
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, f := range z.File {
+		found = found || f.Name == sheetMetadataFilename
+	}
+	if !found {
+		t.Errorf("expected %s to be saved", sheetMetadataFilename)
+	}
+
+	// the spill is read back as a dynamic array
+	for i := 0; i < 2; i++ {
+		wb = saveAndRead(t, wb)
+		rs := wb.Sheets()[0]
+		c := rs.Cell("D1").X()
+		if c.F.TAttr != sml.ST_CellFormulaTypeUnset || c.CmAttr != nil {
+			t.Errorf("expected a plain formula after reading, got %v", c.F.TAttr)
+		}
+		wb.RecalculateFormulas()
+		if got := spillValues(rs, "D", 4); got[0] != "a" || got[1] != "b" || got[2] != "c" || got[3] != "" {
+			t.Errorf("save %d: expected [a b c ], got %v", i+1, got)
+		}
+	}
+}
+
+func TestSpillBlocked(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetFormulaRaw("SEQUENCE(3)")
+	s.Cell("A2").SetNumber(9)
+	s.Cell("B1").SetFormulaRaw("A1+100")
+	s.Cell("C1").SetFormulaRaw("SUM(A1#)")
+	s.Cell("D1").SetFormulaRaw("ROWS(A1#)")
+	for _, workers := range []int{1, 4} {
+		g := NewDependencyGraph(wb)
+		if err := g.Recalculate(WithWorkers(workers)); err != nil {
+			t.Fatal(err)
+		}
+		if got := s.Cell("A1").GetFormattedValue(); got != "#SPILL!" {
+			t.Errorf("%d workers: expected a blocked spill, got %s", workers, got)
+		}
+		for ref, v := range map[string]string{"A1": "#SPILL!", "B1": "#SPILL!", "C1": "#REF!", "D1": "#REF!"} {
+			cr, _ := reference.ParseCellReference(ref)
+			if got, _ := g.result(s.X(), cr); got.Type != formula.ResultTypeError || got.ValueString != v {
+				t.Errorf("%d workers: expected %s in %s, got %s", workers, v, ref, got.Value())
+			}
+		}
+	}
+
+	s.Cell("A2").Clear()
+	wb.RecalculateFormulas()
+	for ref, v := range map[string]string{"A2": "2", "B1": "101", "C1": "6", "D1": "3"} {
+		if got := s.Cell(ref).GetFormattedValue(); got != v {
+			t.Errorf("unblocked: expected %s in %s, got %s", v, ref, got)
+		}
+	}
+}
+
+func TestSpillCopied(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetFormulaRaw("SEQUENCE(3)")
+	s.Cell("B1").SetFormulaRaw("1+1")
+	wb.RecalculateFormulas()
+	// B1 keeps its cached value as only arrays spill again
+	s.Cell("B1").X().V = nil
+
+	if err := s.CopyRange("A1:A3", "C1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := spillValues(s, "C", 4); got[0] != "1" || got[1] != "2" || got[2] != "3" || got[3] != "" {
+		t.Errorf("expected the copy to spill [1 2 3 ], got %v", got)
+	}
+	if got := s.Cell("C2").X().F; got != nil {
+		t.Errorf("expected the spilled values not to be copied")
+	}
+	if err := s.CopyRange("B1", "C3"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Cell("C1").GetFormattedValue(); got != "#SPILL!" {
+		t.Errorf("expected the copy to block the spill, got %s", got)
+	}
+	if got := s.Cell("C2").GetFormattedValue(); got != "" {
+		t.Errorf("expected the spilled values to be cleared, got %s", got)
+	}
+	if err := s.CopyRange("D1", "C3"); err != nil {
+		t.Fatal(err)
+	}
+	if got := spillValues(s, "C", 3); got[0] != "1" || got[1] != "2" || got[2] != "3" {
+		t.Errorf("expected the spill to be unblocked, got %v", got)
+	}
+	if got := s.Cell("A1").GetFormattedValue(); got != "1" {
+		t.Errorf("expected the source to spill, got %s", got)
+	}
+	if s.Cell("B1").X().V != nil {
+		t.Errorf("expected B1 not to be recalculated")
+	}
+}
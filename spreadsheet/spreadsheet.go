@@ -195,6 +195,9 @@ func (_adc Comment )X ()*_ggd .CT_Comment {return _adc ._gbfb };func (_agec Data
 // HasFormula returns true if the cell contains formula.
 func (_bbc *evalContext )HasFormula (cellRef string )bool {return _bbc ._afdd .Cell (cellRef ).HasFormula ();};
 
+// GetFormula returns the formula of a cell.
+func (_bbca *evalContext )GetFormula (cellRef string )string {return _bbca ._afdd .Cell (cellRef ).GetFormula ();};
+
 // X returns the inner wrapped XML type.
 func (_egad Comments )X ()*_ggd .Comments {return _egad ._fcd };func (_fcb RichTextRun )ensureRpr (){if _fcb ._aggb .RPr ==nil {_fcb ._aggb .RPr =_ggd .NewCT_RPrElt ();};};
 
@@ -236,10 +239,10 @@ func (_gbg Cell )AddHyperlink (url string ){for _baf ,_aagg :=range _gbg ._ebb .
 func (_dggfa SheetView )SetTopLeft (cellRef string ){_dggfa .ensurePane ();_dggfa ._baef .Pane .TopLeftCellAttr =&cellRef ;};func (_deg CellStyle )SetShrinkToFit (b bool ){if _deg ._cae .Alignment ==nil {_deg ._cae .Alignment =_ggd .NewCT_CellAlignment ();};_deg ._cae .ApplyAlignmentAttr =_d .Bool (true );if !b {_deg ._cae .Alignment .ShrinkToFitAttr =nil ;}else {_deg ._cae .Alignment .ShrinkToFitAttr =_d .Bool (b );};};
 
 // Open opens and reads a workbook from a file (.xlsx).
-func Open (filename string )(*Workbook ,error ){_agga ,_ege :=_b .Open (filename );if _ege !=nil {return nil ,_c .Errorf ("e\u0072r\u006f\u0072\u0020\u006f\u0070\u0065\u006e\u0069n\u0067\u0020\u0025\u0073: \u0025\u0073",filename ,_ege );};defer _agga .Close ();_bae ,_ege :=_b .Stat (filename );if _ege !=nil {return nil ,_c .Errorf ("e\u0072r\u006f\u0072\u0020\u006f\u0070\u0065\u006e\u0069n\u0067\u0020\u0025\u0073: \u0025\u0073",filename ,_ege );};_gac ,_ege :=Read (_agga ,_bae .Size ());if _ege !=nil {return nil ,_ege ;};_bca ,_ :=_f .Abs (_f .Dir (filename ));_gac ._bbeed =_f .Join (_bca ,filename );return _gac ,nil ;};func (_ede *evalContext )NamedRange (ref string )_aec .Reference {for _ ,_gcbf :=range _ede ._afdd ._bdb .DefinedNames (){if _gcbf .Name ()==ref {return _aec .MakeRangeReference (_gcbf .Content ());};};for _ ,_aefe :=range _ede ._afdd ._bdb .Tables (){if _aefe .Name ()==ref {return _aec .MakeRangeReference (_c .Sprintf ("\u0025\u0073\u0021%\u0073",_ede ._afdd .Name (),_aefe .Reference ()));};};return _aec .ReferenceInvalid ;};
+func Open (filename string )(*Workbook ,error ){_agga ,_ege :=_b .Open (filename );if _ege !=nil {return nil ,_c .Errorf ("e\u0072r\u006f\u0072\u0020\u006f\u0070\u0065\u006e\u0069n\u0067\u0020\u0025\u0073: \u0025\u0073",filename ,_ege );};defer _agga .Close ();_bae ,_ege :=_b .Stat (filename );if _ege !=nil {return nil ,_c .Errorf ("e\u0072r\u006f\u0072\u0020\u006f\u0070\u0065\u006e\u0069n\u0067\u0020\u0025\u0073: \u0025\u0073",filename ,_ege );};_gac ,_ege :=Read (_agga ,_bae .Size ());if _ege !=nil {return nil ,_ege ;};_bca ,_ :=_f .Abs (_f .Dir (filename ));_gac ._bbeed =_f .Join (_bca ,filename );return _gac ,nil ;};func (_ede *evalContext )NamedRange (ref string )_aec .Reference {for _ ,_gcbf :=range _ede ._afdd ._bdb .DefinedNames (){if _gcbf .Name ()==ref {return _aec .MakeRangeReference (_gcbf .Content ());};};for _ ,_aefe :=range _ede ._afdd ._bdb .Tables (){if _aefe .Name ()==ref {if _bdgca ,_fcebe :=_aefe .sheet ();_fcebe ==nil {return _aec .MakeRangeReference (_c .Sprintf ("\u0025\u0073\u0021%\u0073",_bdgca .Name (),_aefe .Reference ()));};};};return _aec .ReferenceInvalid ;};
 
-// SaveToFile writes the workbook out to a file.
-func (_fdgg *Workbook )SaveToFile (path string )error {_fdbc ,_gfcd :=_b .Create (path );if _gfcd !=nil {return _gfcd ;};defer _fdbc .Close ();return _fdgg .Save (_fdbc );};
+// SaveToFile writes the workbook out to a file, see Save for the options.
+func (_fdgg *Workbook )SaveToFile (path string ,opts ...SaveOption )error {_fdbc ,_gfcd :=_b .Create (path );if _gfcd !=nil {return _gfcd ;};defer _fdbc .Close ();return _fdgg .Save (_fdbc ,opts ...);};
 
 // AddView adds a sheet view.
 func (_fad *Sheet )AddView ()SheetView {if _fad ._bcgb .SheetViews ==nil {_fad ._bcgb .SheetViews =_ggd .NewCT_SheetViews ();};_dfa :=_ggd .NewCT_SheetView ();_fad ._bcgb .SheetViews .SheetView =append (_fad ._bcgb .SheetViews .SheetView ,_dfa );return SheetView {_dfa };};func (_bbe Comments )getOrCreateAuthor (_dbe string )uint32 {for _bfg ,_ebbb :=range _bbe ._fcd .Authors .Author {if _ebbb ==_dbe {return uint32 (_bfg );};};_acc :=uint32 (len (_bbe ._fcd .Authors .Author ));_bbe ._fcd .Authors .Author =append (_bbe ._fcd .Authors .Author ,_dbe );return _acc ;};
@@ -271,7 +274,8 @@ func (_cagb SheetProtection )LockSheet (b bool ){if !b {_cagb ._bcef .SheetAttr
 
 // InsertRow inserts a new row into a spreadsheet at a particular row number.  This
 // row will now be the row number specified, and any rows after it will be renumbed.
-func (_adead *Sheet )InsertRow (rowNum int )Row {_baab :=uint32 (rowNum );for _ ,_eec :=range _adead .Rows (){if _eec ._dggg .RAttr !=nil &&*_eec ._dggg .RAttr >=_baab {*_eec ._dggg .RAttr ++;for _ ,_gff :=range _eec .Cells (){_aff ,_aga :=_eg .ParseCellReference (_gff .Reference ());if _aga !=nil {continue ;};_aff .RowIdx ++;_gff ._dbd .RAttr =_d .String (_aff .String ());};};};for _ ,_gcf :=range _adead .MergedCells (){_fbdf ,_aeca ,_fcfg :=_eg .ParseRangeReference (_gcf .Reference ());if _fcfg !=nil {continue ;};if int (_fbdf .RowIdx )>=rowNum {_fbdf .RowIdx ++;};if int (_aeca .RowIdx )>=rowNum {_aeca .RowIdx ++;};_beac :=_c .Sprintf ("\u0025\u0073\u003a%\u0073",_fbdf ,_aeca );_gcf .SetReference (_beac );};return _adead .AddNumberedRow (_baab );};func (_edca SortOrder )String ()string {if _edca >=SortOrder (len (_aeae )-1){return _c .Sprintf ("\u0053\u006f\u0072\u0074\u004f\u0072\u0064\u0065\u0072\u0028\u0025\u0064\u0029",_edca );};return _cdgd [_aeae [_edca ]:_aeae [_edca +1]];};
+// References to the moved cells are updated as for InsertRows.
+func (_adead *Sheet )InsertRow (rowNum int )Row {if _baab :=_adead .InsertRows (rowNum ,1);_baab !=nil {_d .Log ("unable to insert row: %s",_baab );};return _adead .Row (uint32 (rowNum ));};func (_edca SortOrder )String ()string {if _edca >=SortOrder (len (_aeae )-1){return _c .Sprintf ("\u0053\u006f\u0072\u0074\u004f\u0072\u0064\u0065\u0072\u0028\u0025\u0064\u0029",_edca );};return _cdgd [_aeae [_edca ]:_aeae [_edca +1]];};
 
 // AddSheet adds a new sheet to a workbook.
 func (_cada *Workbook )AddSheet ()Sheet {_fcbd :=_ggd .NewCT_Sheet ();_fcbd .SheetIdAttr =1;for _ ,_decea :=range _cada ._bbae .Sheets .Sheet {if _fcbd .SheetIdAttr <=_decea .SheetIdAttr {_fcbd .SheetIdAttr =_decea .SheetIdAttr +1;};};_cada ._bbae .Sheets .Sheet =append (_cada ._bbae .Sheets .Sheet ,_fcbd );_fcbd .NameAttr =_c .Sprintf ("\u0053\u0068\u0065\u0065\u0074\u0020\u0025\u0064",_fcbd .SheetIdAttr );_gcgf :=_ggd .NewWorksheet ();_gcgf .Dimension =_ggd .NewCT_SheetDimension ();_gcgf .Dimension .RefAttr ="\u0041\u0031";_cada ._fbed =append (_cada ._fbed ,_gcgf );_ecbg :=_cb .NewRelationships ();_cada ._fdbe =append (_cada ._fdbe ,_ecbg );_gcgf .SheetData =_ggd .NewCT_SheetData ();_cada ._cbge =append (_cada ._cbge ,nil );_gcace :=_d .DocTypeSpreadsheet ;_gefaa :=_cada ._adebd .AddAutoRelationship (_gcace ,_d .OfficeDocumentType ,len (_cada ._bbae .Sheets .Sheet ),_d .WorksheetType );_fcbd .IdAttr =_gefaa .ID ();_cada .ContentTypes .AddOverride (_d .AbsoluteFilename (_gcace ,_d .WorksheetContentType ,len (_cada ._bbae .Sheets .Sheet )),_d .WorksheetContentType );return Sheet {_cada ,_fcbd ,_gcgf };};
@@ -316,8 +320,9 @@ func (_edec DataValidationList )SetValues (values []string ){_edec ._eea .Formul
 // Excel. This involves determining the format string to apply, parsing it, and
 // then formatting the value according to the format string.  This should only
 // be used if you care about replicating what Excel would show, otherwise
-// GetValueAsNumber()/GetValueAsTime
-func (_dfd Cell )GetFormattedValue ()string {_cbfa :=_dfd .getFormat ();switch _dfd ._dbd .TAttr {case _ggd .ST_CellTypeB :_gcc ,_ :=_dfd .GetValueAsBool ();if _gcc {return "\u0054\u0052\u0055\u0045";};return "\u0046\u0041\u004cS\u0045";case _ggd .ST_CellTypeN :_bba ,_ :=_dfd .GetValueAsNumber ();return _ga .Number (_bba ,_cbfa );case _ggd .ST_CellTypeE :if _dfd ._dbd .V !=nil {return *_dfd ._dbd .V ;};return "";case _ggd .ST_CellTypeS ,_ggd .ST_CellTypeInlineStr :return _ga .String (_dfd .GetString (),_cbfa );case _ggd .ST_CellTypeStr :_cgdd :=_dfd .GetString ();if _ga .IsNumber (_cgdd ){_ccf ,_ :=_de .ParseFloat (_cgdd ,64);return _ga .Number (_ccf ,_cbfa );};return _ga .String (_cgdd ,_cbfa );case _ggd .ST_CellTypeUnset :fallthrough;default:_abe ,_ :=_dfd .GetRawValue ();if len (_abe )==0{return "";};_cgc ,_ece :=_dfd .GetValueAsNumber ();if _ece ==nil {return _ga .Number (_cgc ,_cbfa );};return _ga .String (_abe ,_cbfa );};};
+// GetValueAsNumber()/GetValueAsTime.  GetFormattedResult also returns the color
+// selected by the format.
+func (_dfd Cell )GetFormattedValue ()string {return _dfd .GetFormattedResult ().Text ;};
 
 // DataValidationList is just a view on a DataValidation configured as a list.
 // It presents a drop-down combo box for spreadsheet users to select values. The
@@ -365,7 +370,7 @@ func (_cacfg Row )IsHidden ()bool {return _cacfg ._dggg .HiddenAttr !=nil &&*_ca
 func (_gfba *Sheet )Column (idx uint32 )Column {for _ ,_beedg :=range _gfba ._bcgb .Cols {for _ ,_aefb :=range _beedg .Col {if idx >=_aefb .MinAttr &&idx <=_aefb .MaxAttr {return Column {_aefb };};};};var _bceg *_ggd .CT_Cols ;if len (_gfba ._bcgb .Cols )==0{_bceg =_ggd .NewCT_Cols ();_gfba ._bcgb .Cols =append (_gfba ._bcgb .Cols ,_bceg );}else {_bceg =_gfba ._bcgb .Cols [0];};_dgag :=_ggd .NewCT_Col ();_dgag .MinAttr =idx ;_dgag .MaxAttr =idx ;_bceg .Col =append (_bceg .Col ,_dgag );return Column {_dgag };};
 
 // Tables returns a slice of all defined tables in the workbook.
-func (_dacff *Workbook )Tables ()[]Table {if _dacff ._caaa ==nil {return nil ;};_gae :=[]Table {};for _ ,_ccdf :=range _dacff ._caaa {_gae =append (_gae ,Table {_ccdf });};return _gae ;};
+func (_dacff *Workbook )Tables ()[]Table {if _dacff ._caaa ==nil {return nil ;};_gae :=[]Table {};for _ ,_ccdf :=range _dacff ._caaa {_gae =append (_gae ,Table {_ccdf ,_dacff });};return _gae ;};
 
 // Sort sorts all of the rows within a sheet by the contents of a column. As the
 // file format doesn't suppot indicating that a column should be sorted by the
@@ -375,7 +380,7 @@ func (_dacff *Workbook )Tables ()[]Table {if _dacff ._caaa ==nil {return nil ;};
 // specifies the column to sort by. The firstRow is a 1-based index and
 // specifies the firstRow to include in the sort, allowing skipping over a
 // header row.
-func (_daga *Sheet )Sort (column string ,firstRow uint32 ,order SortOrder ){_cead :=_daga ._bcgb .SheetData .Row ;_eabc :=_daga .Rows ();for _gbeg ,_dcedg :=range _eabc {if _dcedg .RowNumber ()==firstRow {_cead =_daga ._bcgb .SheetData .Row [_gbeg :];break ;};};_aafbd :=Comparer {Order :order };_a .Slice (_cead ,func (_febc ,_afgg int )bool {return _aafbd .LessRows (column ,Row {_daga ._bdb ,_daga ,_cead [_febc ]},Row {_daga ._bdb ,_daga ,_cead [_afgg ]});});for _ggag ,_gcfb :=range _daga .Rows (){_cag :=uint32 (_ggag +1);if _gcfb .RowNumber ()!=_cag {_gcfb .renumberAs (_cag );};};};type Table struct{_cbab *_ggd .Table };
+func (_daga *Sheet )Sort (column string ,firstRow uint32 ,order SortOrder ){_cead :=_daga ._bcgb .SheetData .Row ;_eabc :=_daga .Rows ();for _gbeg ,_dcedg :=range _eabc {if _dcedg .RowNumber ()==firstRow {_cead =_daga ._bcgb .SheetData .Row [_gbeg :];break ;};};_aafbd :=Comparer {Order :order };_a .Slice (_cead ,func (_febc ,_afgg int )bool {return _aafbd .LessRows (column ,Row {_daga ._bdb ,_daga ,_cead [_febc ]},Row {_daga ._bdb ,_daga ,_cead [_afgg ]});});for _ggag ,_gcfb :=range _daga .Rows (){_cag :=uint32 (_ggag +1);if _gcfb .RowNumber ()!=_cag {_gcfb .renumberAs (_cag );};};};type Table struct{_cbab *_ggd .Table ;wb *Workbook ;};
 
 // SetContent sets the defined name content.
 func (_fgfb DefinedName )SetContent (s string ){_fgfb ._cecc .Content =s };
@@ -402,8 +407,9 @@ func (_deae Drawing )AddChart (at AnchorType )(_dee .Chart ,Anchor ){_gbdb :=_ba
 // SetHeightCells is a no-op.
 func (_ddb AbsoluteAnchor )SetHeightCells (int32 ){};
 
-// Save writes the workbook out to a writer in the zipped xlsx format.
-func (_bgff *Workbook )Save (w _ec .Writer )error {if !_gg .GetLicenseKey ().IsLicensed ()&&!_bgab {_c .Println ("\u0055\u006e\u006ci\u0063\u0065\u006e\u0073e\u0064\u0020\u0076\u0065\u0072\u0073\u0069o\u006e\u0020\u006f\u0066\u0020\u0055\u006e\u0069\u004f\u0066\u0066\u0069\u0063\u0065");_c .Println ("\u002d\u0020\u0047e\u0074\u0020\u0061\u0020\u0074\u0072\u0069\u0061\u006c\u0020\u006c\u0069\u0063\u0065\u006e\u0073\u0065\u0020\u006f\u006e\u0020\u0068\u0074\u0074\u0070\u0073\u003a\u002f\u002fu\u006e\u0069\u0064\u006f\u0063\u002e\u0069\u006f");return _gb .New ("\u0075\u006e\u0069\u006f\u0066\u0066\u0069\u0063\u0065\u0020\u006ci\u0063\u0065\u006e\u0073\u0065\u0020\u0072\u0065\u0071\u0075i\u0072\u0065\u0064");};_ecgc :=_aa .NewWriter (w );defer _ecgc .Close ();_bada :=_d .DocTypeSpreadsheet ;if _cdcba :=_ad .MarshalXML (_ecgc ,_d .BaseRelsFilename ,_bgff .Rels .X ());_cdcba !=nil {return _cdcba ;};if _bgfg :=_ad .MarshalXMLByType (_ecgc ,_bada ,_d .ExtendedPropertiesType ,_bgff .AppProperties .X ());_bgfg !=nil {return _bgfg ;};if _dbca :=_ad .MarshalXMLByType (_ecgc ,_bada ,_d .CorePropertiesType ,_bgff .CoreProperties .X ());_dbca !=nil {return _dbca ;};_gbce :=_d .AbsoluteFilename (_bada ,_d .OfficeDocumentType ,0);if _aceee :=_ad .MarshalXML (_ecgc ,_gbce ,_bgff ._bbae );_aceee !=nil {return _aceee ;};if _gbef :=_ad .MarshalXML (_ecgc ,_ad .RelationsPathFor (_gbce ),_bgff ._adebd .X ());_gbef !=nil {return _gbef ;};if _ggcba :=_ad .MarshalXMLByType (_ecgc ,_bada ,_d .StylesType ,_bgff .StyleSheet .X ());_ggcba !=nil {return _ggcba ;};for _cgbf ,_fce :=range _bgff ._bgea {if _adce :=_ad .MarshalXMLByTypeIndex (_ecgc ,_bada ,_d .ThemeType ,_cgbf +1,_fce );_adce !=nil {return _adce ;};};for _cgdf ,_ggbdc :=range _bgff ._fbed {_ggbdc .Dimension .RefAttr =Sheet {_bgff ,nil ,_ggbdc }.Extents ();_gefg :=_d .AbsoluteFilename (_bada ,_d .WorksheetType ,_cgdf +1);_ad .MarshalXML (_ecgc ,_gefg ,_ggbdc );_ad .MarshalXML (_ecgc ,_ad .RelationsPathFor (_gefg ),_bgff ._fdbe [_cgdf ].X ());};if _defd :=_ad .MarshalXMLByType (_ecgc ,_bada ,_d .SharedStringsType ,_bgff .SharedStrings .X ());_defd !=nil {return _defd ;};if _bgff .CustomProperties .X ()!=nil {if _gdga :=_ad .MarshalXMLByType (_ecgc ,_bada ,_d .CustomPropertiesType ,_bgff .CustomProperties .X ());_gdga !=nil {return _gdga ;};};if _bgff .Thumbnail !=nil {_bdga :=_d .AbsoluteFilename (_bada ,_d .ThumbnailType ,0);_gabf ,_agcf :=_ecgc .Create (_bdga );if _agcf !=nil {return _agcf ;};if _eggc :=_dg .Encode (_gabf ,_bgff .Thumbnail ,nil );_eggc !=nil {return _eggc ;};};for _agca ,_adec :=range _bgff ._fgcda {_gaaa :=_d .AbsoluteFilename (_bada ,_d .ChartType ,_agca +1);_ad .MarshalXML (_ecgc ,_gaaa ,_adec );};for _fcdc ,_abcc :=range _bgff ._caaa {_bbcf :=_d .AbsoluteFilename (_bada ,_d .TableType ,_fcdc +1);_ad .MarshalXML (_ecgc ,_bbcf ,_abcc );};for _acgca ,_aace :=range _bgff ._cefe {_aece :=_d .AbsoluteFilename (_bada ,_d .DrawingType ,_acgca +1);_ad .MarshalXML (_ecgc ,_aece ,_aace );if !_bgff ._fcbeb [_acgca ].IsEmpty (){_ad .MarshalXML (_ecgc ,_ad .RelationsPathFor (_aece ),_bgff ._fcbeb [_acgca ].X ());};};for _gabc ,_gefc :=range _bgff ._cbbfe {_ad .MarshalXML (_ecgc ,_d .AbsoluteFilename (_bada ,_d .VMLDrawingType ,_gabc +1),_gefc );};for _fbedc ,_bbeg :=range _bgff .Images {if _eccc :=_cb .AddImageToZip (_ecgc ,_bbeg ,_fbedc +1,_d .DocTypeSpreadsheet );_eccc !=nil {return _eccc ;};};if _cegd :=_ad .MarshalXML (_ecgc ,_d .ContentTypesFilename ,_bgff .ContentTypes .X ());_cegd !=nil {return _cegd ;};for _ceeb ,_bebe :=range _bgff ._cbge {if _bebe ==nil {continue ;};_ad .MarshalXML (_ecgc ,_d .AbsoluteFilename (_bada ,_d .CommentsType ,_ceeb +1),_bebe );};if _cdcg :=_bgff .WriteExtraFiles (_ecgc );_cdcg !=nil {return _cdcg ;};return _ecgc .Close ();};func (_dfcg PatternFill )SetBgColor (c _cg .Color ){_dfcg ._aaac .BgColor =_ggd .NewCT_Color ();_dfcg ._aaac .BgColor .RgbAttr =c .AsRGBAString ();};
+// Save writes the workbook out to a writer in the zipped xlsx format.  With
+// WithSharedFormulas, copied formulas are written as shared formulas.
+func (_bgff *Workbook )Save (w _ec .Writer ,opts ...SaveOption )error {if !_gg .GetLicenseKey ().IsLicensed ()&&!_bgab {_c .Println ("\u0055\u006e\u006ci\u0063\u0065\u006e\u0073e\u0064\u0020\u0076\u0065\u0072\u0073\u0069o\u006e\u0020\u006f\u0066\u0020\u0055\u006e\u0069\u004f\u0066\u0066\u0069\u0063\u0065");_c .Println ("\u002d\u0020\u0047e\u0074\u0020\u0061\u0020\u0074\u0072\u0069\u0061\u006c\u0020\u006c\u0069\u0063\u0065\u006e\u0073\u0065\u0020\u006f\u006e\u0020\u0068\u0074\u0074\u0070\u0073\u003a\u002f\u002fu\u006e\u0069\u0064\u006f\u0063\u002e\u0069\u006f");return _gb .New ("\u0075\u006e\u0069\u006f\u0066\u0066\u0069\u0063\u0065\u0020\u006ci\u0063\u0065\u006e\u0073\u0065\u0020\u0072\u0065\u0071\u0075i\u0072\u0065\u0064");};_bgff .syncTableColumns ();if newSaveOptions (opts ).shareFormulas {defer _bgff .shareFormulasForSave ()();};_fgde ,_cdfbg :=_bgff .dynamicArraysForSave ();defer _cdfbg ();_ecgc :=_aa .NewWriter (w );defer _ecgc .Close ();_bada :=_d .DocTypeSpreadsheet ;if _cdcba :=_ad .MarshalXML (_ecgc ,_d .BaseRelsFilename ,_bgff .Rels .X ());_cdcba !=nil {return _cdcba ;};if _bgfg :=_ad .MarshalXMLByType (_ecgc ,_bada ,_d .ExtendedPropertiesType ,_bgff .AppProperties .X ());_bgfg !=nil {return _bgfg ;};if _dbca :=_ad .MarshalXMLByType (_ecgc ,_bada ,_d .CorePropertiesType ,_bgff .CoreProperties .X ());_dbca !=nil {return _dbca ;};_gbce :=_d .AbsoluteFilename (_bada ,_d .OfficeDocumentType ,0);if _aceee :=_ad .MarshalXML (_ecgc ,_gbce ,_bgff ._bbae );_aceee !=nil {return _aceee ;};if _gbef :=_ad .MarshalXML (_ecgc ,_ad .RelationsPathFor (_gbce ),_bgff ._adebd .X ());_gbef !=nil {return _gbef ;};if _ggcba :=_ad .MarshalXMLByType (_ecgc ,_bada ,_d .StylesType ,_bgff .StyleSheet .X ());_ggcba !=nil {return _ggcba ;};for _cgbf ,_fce :=range _bgff ._bgea {if _adce :=_ad .MarshalXMLByTypeIndex (_ecgc ,_bada ,_d .ThemeType ,_cgbf +1,_fce );_adce !=nil {return _adce ;};};for _cgdf ,_ggbdc :=range _bgff ._fbed {_gefg :=_d .AbsoluteFilename (_bada ,_d .WorksheetType ,_cgdf +1);if _fcdgb ,_bgcf :=_bgff .streamingSheets [_ggbdc ];_bgcf {if _cdbe :=_fcdgb .writeTo (_ecgc ,_gefg );_cdbe !=nil {return _cdbe ;};}else {_ggbdc .Dimension .RefAttr =Sheet {_bgff ,nil ,_ggbdc }.Extents ();_ad .MarshalXML (_ecgc ,_gefg ,_ggbdc );};_ad .MarshalXML (_ecgc ,_ad .RelationsPathFor (_gefg ),_bgff ._fdbe [_cgdf ].X ());};if _defd :=_ad .MarshalXMLByType (_ecgc ,_bada ,_d .SharedStringsType ,_bgff .SharedStrings .X ());_defd !=nil {return _defd ;};if _bgff .CustomProperties .X ()!=nil {if _gdga :=_ad .MarshalXMLByType (_ecgc ,_bada ,_d .CustomPropertiesType ,_bgff .CustomProperties .X ());_gdga !=nil {return _gdga ;};};if _bgff .Thumbnail !=nil {_bdga :=_d .AbsoluteFilename (_bada ,_d .ThumbnailType ,0);_gabf ,_agcf :=_ecgc .Create (_bdga );if _agcf !=nil {return _agcf ;};if _eggc :=_dg .Encode (_gabf ,_bgff .Thumbnail ,nil );_eggc !=nil {return _eggc ;};};for _agca ,_adec :=range _bgff ._fgcda {_gaaa :=_d .AbsoluteFilename (_bada ,_d .ChartType ,_agca +1);_ad .MarshalXML (_ecgc ,_gaaa ,_adec );};for _fcdc ,_abcc :=range _bgff ._caaa {_bbcf :=_d .AbsoluteFilename (_bada ,_d .TableType ,_fcdc +1);_ad .MarshalXML (_ecgc ,_bbcf ,_abcc );};if _fgbca :=_bgff .savePivots (_ecgc );_fgbca !=nil {return _fgbca ;};if _bgfde :=_fgde (_ecgc );_bgfde !=nil {return _bgfde ;};for _acgca ,_aace :=range _bgff ._cefe {_aece :=_d .AbsoluteFilename (_bada ,_d .DrawingType ,_acgca +1);_ad .MarshalXML (_ecgc ,_aece ,_aace );if !_bgff ._fcbeb [_acgca ].IsEmpty (){_ad .MarshalXML (_ecgc ,_ad .RelationsPathFor (_aece ),_bgff ._fcbeb [_acgca ].X ());};};for _gabc ,_gefc :=range _bgff ._cbbfe {_ad .MarshalXML (_ecgc ,_d .AbsoluteFilename (_bada ,_d .VMLDrawingType ,_gabc +1),_gefc );};for _fbedc ,_bbeg :=range _bgff .Images {if _eccc :=_cb .AddImageToZip (_ecgc ,_bbeg ,_fbedc +1,_d .DocTypeSpreadsheet );_eccc !=nil {return _eccc ;};};if _cegd :=_ad .MarshalXML (_ecgc ,_d .ContentTypesFilename ,_bgff .ContentTypes .X ());_cegd !=nil {return _cegd ;};for _ceeb ,_bebe :=range _bgff ._cbge {if _bebe ==nil {continue ;};_ad .MarshalXML (_ecgc ,_d .AbsoluteFilename (_bada ,_d .CommentsType ,_ceeb +1),_bebe );};if _cdcg :=_bgff .WriteExtraFiles (_ecgc );_cdcg !=nil {return _cdcg ;};return _ecgc .Close ();};func (_dfcg PatternFill )SetBgColor (c _cg .Color ){_dfcg ._aaac .BgColor =_ggd .NewCT_Color ();_dfcg ._aaac .BgColor .RgbAttr =c .AsRGBAString ();};
 
 // SetConditionValue sets the condition value to be used for style applicaton.
 func (_ecda ConditionalFormattingRule )SetConditionValue (v string ){_ecda ._dbed .Formula =[]string {v }};func (_cbcc Cell )getRawSortValue ()(string ,bool ){if _cbcc .HasFormula (){_gga :=_cbcc .GetCachedFormulaResult ();return _gga ,_ga .IsNumber (_gga );};_cdga ,_ :=_cbcc .GetRawValue ();return _cdga ,_ga .IsNumber (_cdga );};
@@ -413,7 +419,7 @@ func (_ecda ConditionalFormattingRule )SetConditionValue (v string ){_ecda ._dbe
 func (_ea CellStyle )NumberFormat ()uint32 {if _ea ._cae .NumFmtIdAttr ==nil {return 0;};return *_ea ._cae .NumFmtIdAttr ;};
 
 // IsWindowLocked returns whether the workbook windows are locked.
-func (_efda WorkbookProtection )IsWindowLocked ()bool {return _efda ._decb .LockWindowsAttr !=nil &&*_efda ._decb .LockWindowsAttr ;};func NewPatternFill (fills *_ggd .CT_Fills )PatternFill {_agfa :=_ggd .NewCT_Fill ();_agfa .PatternFill =_ggd .NewCT_PatternFill ();return PatternFill {_agfa .PatternFill ,_agfa };};func (_afdc *Sheet )removeColumnFromNamedRanges (_egdg uint32 )error {for _ ,_gadfd :=range _afdc ._bdb .DefinedNames (){_bcag :=_gadfd .Name ();_cdcb :=_gadfd .Content ();_gagf :=_dgd .Split (_cdcb ,"\u0021");if len (_gagf )!=2{return _gb .New ("\u0049\u006e\u0063\u006frr\u0065\u0063\u0074\u0020\u006e\u0061\u006d\u0065\u0064\u0020\u0072\u0061\u006e\u0067e\u003a"+_cdcb );};_cbag :=_gagf [0];if _afdc .Name ()==_cbag {_eccd :=_afdc ._bdb .RemoveDefinedName (_gadfd );if _eccd !=nil {return _eccd ;};_ffgc :=_cfab (_gagf [1],_egdg ,true );if _ffgc !=""{_cgcd :=_cbag +"\u0021"+_ffgc ;_afdc ._bdb .AddDefinedName (_bcag ,_cgcd );};};};_bcbg :=0;if _afdc ._bcgb .TableParts !=nil &&_afdc ._bcgb .TableParts .TablePart !=nil {_bcbg =len (_afdc ._bcgb .TableParts .TablePart );};if _bcbg !=0{_dgca :=0;for _ ,_dacf :=range _afdc ._bdb .Sheets (){if _dacf .Name ()==_afdc .Name (){break ;}else {if _dacf ._bcgb .TableParts !=nil &&_dacf ._bcgb .TableParts .TablePart !=nil {_dgca +=len (_dacf ._bcgb .TableParts .TablePart );};};};_ccca :=_afdc ._bdb ._caaa [_dgca :_dgca +_bcbg ];for _edaf ,_fcbe :=range _ccca {_gbge :=_fcbe ;_gbge .RefAttr =_cfab (_gbge .RefAttr ,_egdg ,false );_afdc ._bdb ._caaa [_dgca +_edaf ]=_gbge ;};};return nil ;};func (_geg *evalContext )Sheet (name string )_aec .Context {for _ ,_cfg :=range _geg ._afdd ._bdb .Sheets (){if _cfg .Name ()==name {return _cfg .FormulaContext ();};};return _aec .InvalidReferenceContext ;};
+func (_efda WorkbookProtection )IsWindowLocked ()bool {return _efda ._decb .LockWindowsAttr !=nil &&*_efda ._decb .LockWindowsAttr ;};func NewPatternFill (fills *_ggd .CT_Fills )PatternFill {_agfa :=_ggd .NewCT_Fill ();_agfa .PatternFill =_ggd .NewCT_PatternFill ();return PatternFill {_agfa .PatternFill ,_agfa };};func (_afdc *Sheet )removeColumnFromNamedRanges (_egdg uint32 )error {for _ ,_gadfd :=range _afdc ._bdb .DefinedNames (){_bcag :=_gadfd .Name ();_cdcb :=_gadfd .Content ();_gagf :=_dgd .Split (_cdcb ,"\u0021");if len (_gagf )!=2{return _gb .New ("\u0049\u006e\u0063\u006frr\u0065\u0063\u0074\u0020\u006e\u0061\u006d\u0065\u0064\u0020\u0072\u0061\u006e\u0067e\u003a"+_cdcb );};_cbag :=_gagf [0];if _afdc .Name ()==_cbag {_eccd :=_afdc ._bdb .RemoveDefinedName (_gadfd );if _eccd !=nil {return _eccd ;};_ffgc :=_cfab (_gagf [1],_egdg ,true );if _ffgc !=""{_cgcd :=_cbag +"\u0021"+_ffgc ;_afdc ._bdb .AddDefinedName (_bcag ,_cgcd );};};};_bcbg :=0;if _afdc ._bcgb .TableParts !=nil &&_afdc ._bcgb .TableParts .TablePart !=nil {_bcbg =len (_afdc ._bcgb .TableParts .TablePart );};if _bcbg !=0{_dgca :=0;for _ ,_dacf :=range _afdc ._bdb .Sheets (){if _dacf .Name ()==_afdc .Name (){break ;}else {if _dacf ._bcgb .TableParts !=nil &&_dacf ._bcgb .TableParts .TablePart !=nil {_dgca +=len (_dacf ._bcgb .TableParts .TablePart );};};};_ccca :=_afdc ._bdb ._caaa [_dgca :_dgca +_bcbg ];for _edaf ,_fcbe :=range _ccca {_gbge :=_fcbe ;_gbge .RefAttr =_cfab (_gbge .RefAttr ,_egdg ,false );_afdc ._bdb ._caaa [_dgca +_edaf ]=_gbge ;};};return nil ;};func (_geg *evalContext )Sheet (name string )_aec .Context {for _ ,_cfg :=range _geg ._afdd ._bdb .Sheets (){if _cfg .Name ()==name {_fgfb :=_gba (&_cfg );_fgfb .graph =_geg .graph ;return _fgfb ;};};return _aec .InvalidReferenceContext ;};
 
 // Close closes the workbook, removing any temporary files that might have been
 // created when opening a document.
@@ -431,7 +437,7 @@ func (_agg Comment )SetCellReference (cellRef string ){_agg ._gbfb .RefAttr =cel
 
 // Cells returns a slice of cells.  The cells can be manipulated, but appending
 // to the slice will have no effect.
-func (_deaa Row )Cells ()[]Cell {_bgc :=[]Cell {};_fdfa :=-1;for _ ,_dag :=range _deaa ._dggg .C {if _dag .RAttr ==nil {_d .Log ("\u0052\u0041\u0074tr\u0020\u0069\u0073\u0020\u006e\u0069\u006c\u0020\u0066o\u0072 \u0061 \u0063e\u006c\u006c\u002c\u0020\u0073\u006b\u0069\u0070\u0070\u0069\u006e\u0067\u002e");continue ;};_gefb ,_eadb :=_eg .ParseCellReference (*_dag .RAttr );if _eadb !=nil {_d .Log ("\u0052\u0041\u0074t\u0072\u0020\u0069\u0073 \u0069\u006e\u0063\u006f\u0072\u0072\u0065c\u0074\u0020\u0066\u006f\u0072\u0020\u0061\u0020\u0063\u0065\u006c\u006c\u003a\u0020"+*_dag .RAttr +",\u0020\u0073\u006b\u0069\u0070\u0070\u0069\u006e\u0067\u002e");continue ;};_fbfd :=int (_gefb .ColumnIdx );if _fbfd -_fdfa > 1{for _abed :=_fdfa +1;_abed < _fbfd ;_abed ++{_bgc =append (_bgc ,_deaa .Cell (_eg .IndexToColumn (uint32 (_abed ))));};};_fdfa =_fbfd ;_bgc =append (_bgc ,Cell {_deaa ._fcba ,_deaa ._dafa ,_deaa ._dggg ,_dag });};return _bgc ;};
+func (_deaa Row )Cells ()[]Cell {_bgc :=[]Cell {};_fdfa :=-1;for _ ,_dag :=range append ([]*_ggd .CT_Cell (nil ),_deaa ._dggg .C ...){if _dag .RAttr ==nil {_d .Log ("\u0052\u0041\u0074tr\u0020\u0069\u0073\u0020\u006e\u0069\u006c\u0020\u0066o\u0072 \u0061 \u0063e\u006c\u006c\u002c\u0020\u0073\u006b\u0069\u0070\u0070\u0069\u006e\u0067\u002e");continue ;};_gefb ,_eadb :=_eg .ParseCellReference (*_dag .RAttr );if _eadb !=nil {_d .Log ("\u0052\u0041\u0074t\u0072\u0020\u0069\u0073 \u0069\u006e\u0063\u006f\u0072\u0072\u0065c\u0074\u0020\u0066\u006f\u0072\u0020\u0061\u0020\u0063\u0065\u006c\u006c\u003a\u0020"+*_dag .RAttr +",\u0020\u0073\u006b\u0069\u0070\u0070\u0069\u006e\u0067\u002e");continue ;};_fbfd :=int (_gefb .ColumnIdx );if _fbfd -_fdfa > 1{for _abed :=_fdfa +1;_abed < _fbfd ;_abed ++{_bgc =append (_bgc ,_deaa .Cell (_eg .IndexToColumn (uint32 (_abed ))));};};_fdfa =_fbfd ;_bgc =append (_bgc ,Cell {_deaa ._fcba ,_deaa ._dafa ,_deaa ._dggg ,_dag });};return _bgc ;};
 
 // SetMaxLength sets the maximum bar length in percent.
 func (_dgg DataBarScale )SetMaxLength (l uint32 ){_dgg ._edfg .MaxLengthAttr =_d .Uint32 (l )};
@@ -470,7 +476,7 @@ type Column struct{_eccb *_ggd .CT_Col };func (_cdae PatternFill )X ()*_ggd .CT_
 type DVCompareType byte ;func (_agfg *Sheet )setArray (_ccagc string ,_gfda _aec .Result )error {_afg ,_bcdg :=_eg .ParseCellReference (_ccagc );if _bcdg !=nil {return _bcdg ;};for _dbbd ,_dbeb :=range _gfda .ValueArray {_cbga :=_agfg .Row (_afg .RowIdx +uint32 (_dbbd ));for _ggff ,_gdce :=range _dbeb {_cbfag :=_cbga .Cell (_eg .IndexToColumn (_afg .ColumnIdx +uint32 (_ggff )));if _gdce .Type !=_aec .ResultTypeEmpty {if _gdce .IsBoolean {_cbfag .SetBool (_gdce .ValueNumber !=0);}else {_cbfag .SetCachedFormulaResult (_gdce .String ());};};};};return nil ;};const (StandardFormatGeneral StandardFormat =0;StandardFormat0 StandardFormat =0;StandardFormatWholeNumber StandardFormat =1;StandardFormat1 StandardFormat =1;StandardFormat2 StandardFormat =2;StandardFormat3 StandardFormat =3;StandardFormat4 StandardFormat =4;StandardFormatPercent StandardFormat =9;StandardFormat9 StandardFormat =9;StandardFormat10 StandardFormat =10;StandardFormat11 StandardFormat =11;StandardFormat12 StandardFormat =12;StandardFormat13 StandardFormat =13;StandardFormatDate StandardFormat =14;StandardFormat14 StandardFormat =14;StandardFormat15 StandardFormat =15;StandardFormat16 StandardFormat =16;StandardFormat17 StandardFormat =17;StandardFormat18 StandardFormat =18;StandardFormatTime StandardFormat =19;StandardFormat19 StandardFormat =19;StandardFormat20 StandardFormat =20;StandardFormat21 StandardFormat =21;StandardFormatDateTime StandardFormat =22;StandardFormat22 StandardFormat =22;StandardFormat37 StandardFormat =37;StandardFormat38 StandardFormat =38;StandardFormat39 StandardFormat =39;StandardFormat40 StandardFormat =40;StandardFormat45 StandardFormat =45;StandardFormat46 StandardFormat =46;StandardFormat47 StandardFormat =47;StandardFormat48 StandardFormat =48;StandardFormat49 StandardFormat =49;);
 
 // NewSharedStrings constructs a new Shared Strings table.
-func NewSharedStrings ()SharedStrings {return SharedStrings {_gce :_ggd .NewSst (),_daff :make (map[string ]int )};};func (_gde *Sheet )setShared (_acgc string ,_egbg ,_cecb _eg .CellReference ,_bddfd string ){_accc :=_gde .FormulaContext ();_aafbf :=_aec .NewEvaluator ();for _egae :=_egbg .RowIdx ;_egae <=_cecb .RowIdx ;_egae ++{for _ccfg :=_egbg .ColumnIdx ;_ccfg <=_cecb .ColumnIdx ;_ccfg ++{_dced :=_egae -_egbg .RowIdx ;_cacc :=_ccfg -_egbg .ColumnIdx ;_accc .SetOffset (_cacc ,_dced );_egba :=_aafbf .Eval (_accc ,_bddfd );_bfbf :=_c .Sprintf ("\u0025\u0073\u0025\u0064",_eg .IndexToColumn (_ccfg ),_egae );_facec :=_gde .Cell (_bfbf );if _egba .Type ==_aec .ResultTypeNumber {_facec .X ().TAttr =_ggd .ST_CellTypeN ;}else {_facec .X ().TAttr =_ggd .ST_CellTypeInlineStr ;};_facec .X ().V =_d .String (_egba .Value ());};};_ =_aafbf ;_ =_accc ;};
+func NewSharedStrings ()SharedStrings {return SharedStrings {_gce :_ggd .NewSst (),_daff :make (map[string ]int )};};func (_gde *Sheet )setShared (_accc _aec .Context ,_acgc string ,_egbg ,_cecb _eg .CellReference ,_bddfd string ){_aafbf :=_aec .NewEvaluator ();for _egae :=_egbg .RowIdx ;_egae <=_cecb .RowIdx ;_egae ++{for _ccfg :=_egbg .ColumnIdx ;_ccfg <=_cecb .ColumnIdx ;_ccfg ++{_dced :=_egae -_egbg .RowIdx ;_cacc :=_ccfg -_egbg .ColumnIdx ;_accc .SetOffset (_cacc ,_dced );_egba :=_aafbf .Eval (_accc ,_bddfd );_bfbf :=_c .Sprintf ("\u0025\u0073\u0025\u0064",_eg .IndexToColumn (_ccfg ),_egae );_facec :=_gde .Cell (_bfbf );if _egba .Type ==_aec .ResultTypeNumber {_facec .X ().TAttr =_ggd .ST_CellTypeN ;}else {_facec .X ().TAttr =_ggd .ST_CellTypeInlineStr ;};_facec .X ().V =_d .String (_egba .Value ());};};_ =_aafbf ;_ =_accc ;};
 
 // SetAllowBlank controls if blank values are accepted.
 func (_fda DataValidation )SetAllowBlank (b bool ){if !b {_fda ._fcc .AllowBlankAttr =nil ;}else {_fda ._fcc .AllowBlankAttr =_d .Bool (true );};};
@@ -509,7 +515,7 @@ func (_adcc OneCellAnchor )TopLeft ()CellMarker {return CellMarker {_adcc ._bbaa
 func (_edcb Sheet )Name ()string {return _edcb ._adae .NameAttr };
 
 // Read reads a workbook from an io.Reader(.xlsx).
-func Read (r _ec .ReaderAt ,size int64 )(*Workbook ,error ){_gdde :=New ();_faff ,_ddde :=_bb .TempDir ("\u0075\u006e\u0069\u006f\u0066\u0066\u0069\u0063\u0065-\u0078\u006c\u0073\u0078");if _ddde !=nil {return nil ,_ddde ;};_gdde .TmpPath =_faff ;_cgad ,_ddde :=_aa .NewReader (r ,size );if _ddde !=nil {return nil ,_c .Errorf ("\u0070a\u0072s\u0069\u006e\u0067\u0020\u007a\u0069\u0070\u003a\u0020\u0025\u0073",_ddde );};_beg :=[]*_aa .File {};_beg =append (_beg ,_cgad .File ...);_cba :=false ;for _ ,_cbff :=range _beg {if _cbff .FileHeader .Name =="\u0064\u006f\u0063\u0050ro\u0070\u0073\u002f\u0063\u0075\u0073\u0074\u006f\u006d\u002e\u0078\u006d\u006c"{_cba =true ;break ;};};if _cba {_gdde .createCustomProperties ();};_gag :=_ad .DecodeMap {};_gag .SetOnNewRelationshipFunc (_gdde .onNewRelationship );_gag .AddTarget (_d .ContentTypesFilename ,_gdde .ContentTypes .X (),"",0);_gag .AddTarget (_d .BaseRelsFilename ,_gdde .Rels .X (),"",0);if _cff :=_gag .Decode (_beg );_cff !=nil {return nil ,_cff ;};for _ ,_face :=range _beg {if _face ==nil {continue ;};if _egd :=_gdde .AddExtraFileFromZip (_face );_egd !=nil {return nil ,_egd ;};};if _cba {_bccb :=false ;for _ ,_bcbb :=range _gdde .Rels .X ().Relationship {if _bcbb .TargetAttr =="\u0064\u006f\u0063\u0050ro\u0070\u0073\u002f\u0063\u0075\u0073\u0074\u006f\u006d\u002e\u0078\u006d\u006c"{_bccb =true ;break ;};};if !_bccb {_gdde .addCustomRelationships ();};};return _gdde ,nil ;};
+func Read (r _ec .ReaderAt ,size int64 )(*Workbook ,error ){_gdde :=New ();_faff ,_ddde :=_bb .TempDir ("\u0075\u006e\u0069\u006f\u0066\u0066\u0069\u0063\u0065-\u0078\u006c\u0073\u0078");if _ddde !=nil {return nil ,_ddde ;};_gdde .TmpPath =_faff ;_cgad ,_ddde :=_aa .NewReader (r ,size );if _ddde !=nil {return nil ,_c .Errorf ("\u0070a\u0072s\u0069\u006e\u0067\u0020\u007a\u0069\u0070\u003a\u0020\u0025\u0073",_ddde );};_beg :=[]*_aa .File {};_beg =append (_beg ,_cgad .File ...);_cba :=false ;for _ ,_cbff :=range _beg {if _cbff .FileHeader .Name =="\u0064\u006f\u0063\u0050ro\u0070\u0073\u002f\u0063\u0075\u0073\u0074\u006f\u006d\u002e\u0078\u006d\u006c"{_cba =true ;break ;};};if _cba {_gdde .createCustomProperties ();};_gag :=_ad .DecodeMap {};_gag .SetOnNewRelationshipFunc (_gdde .onNewRelationship );_gag .AddTarget (_d .ContentTypesFilename ,_gdde .ContentTypes .X (),"",0);_gag .AddTarget (_d .BaseRelsFilename ,_gdde .Rels .X (),"",0);if _cff :=_gag .Decode (_beg );_cff !=nil {return nil ,_cff ;};for _ ,_face :=range _beg {if _face ==nil {continue ;};if _egd :=_gdde .AddExtraFileFromZip (_face );_egd !=nil {return nil ,_egd ;};};if _cba {_bccb :=false ;for _ ,_bcbb :=range _gdde .Rels .X ().Relationship {if _bcbb .TargetAttr =="\u0064\u006f\u0063\u0050ro\u0070\u0073\u002f\u0063\u0075\u0073\u0074\u006f\u006d\u002e\u0078\u006d\u006c"{_bccb =true ;break ;};};if !_bccb {_gdde .addCustomRelationships ();};};_gdde .expandSharedFormulas ();_gdde .readDynamicArrays ();return _gdde ,nil ;};
 
 // DefinedNames returns a slice of all defined names in the workbook.
 func (_ecgg *Workbook )DefinedNames ()[]DefinedName {if _ecgg ._bbae .DefinedNames ==nil {return nil ;};_edecb :=[]DefinedName {};for _ ,_eaec :=range _ecgg ._bbae .DefinedNames .DefinedName {_edecb =append (_edecb ,DefinedName {_eaec });};return _edecb ;};func _gcd (_gfg _dga .Time )_dga .Time {_gfg =_gfg .UTC ();return _dga .Date (_gfg .Year (),_gfg .Month (),_gfg .Day (),_gfg .Hour (),_gfg .Minute (),_gfg .Second (),_gfg .Nanosecond (),_dga .Local );};const (_gbfc ="\u0053\u0074\u0061\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061tGe\u006e\u0065\u0072\u0061\u006cS\u0074a\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061\u0074\u0057\u0068\u006f\u006ce\u004e\u0075\u006d\u0062\u0065\u0072\u0053\u0074\u0061\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061\u0074\u0032\u0053\u0074\u0061\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006da\u0074\u0033\u0053\u0074\u0061\u006e\u0064\u0061\u0072\u0064F\u006f\u0072\u006d\u0061\u0074\u0034";_fgdf ="\u0053\u0074\u0061\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061\u0074P\u0065\u0072\u0063\u0065\u006e\u0074\u0053\u0074\u0061nd\u0061r\u0064F\u006fr\u006d\u0061\u0074\u0031\u0030\u0053\u0074\u0061\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061t\u0031\u0031\u0053\u0074\u0061\u006e\u0064\u0061\u0072\u0064F\u006f\u0072\u006d\u0061\u0074\u0031\u0032\u0053\u0074a\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061\u0074\u0031\u0033\u0053t\u0061\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061\u0074\u0044\u0061\u0074\u0065\u0053\u0074\u0061\u006e\u0064\u0061\u0072\u0064\u0046o\u0072\u006d\u0061\u0074\u00315\u0053\u0074\u0061\u006e\u0064a\u0072\u0064\u0046\u006f\u0072\u006d\u0061\u0074\u0031\u0036\u0053\u0074\u0061\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061\u0074\u0031\u0037S\u0074\u0061\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061\u0074\u0031\u0038\u0053\u0074\u0061n\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061\u0074\u0054\u0069\u006d\u0065\u0053\u0074\u0061\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061\u0074\u00320\u0053\u0074a\u006e\u0064a\u0072\u0064\u0046\u006f\u0072\u006d\u0061t\u0032\u0031\u0053\u0074\u0061\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061\u0074\u0044\u0061t\u0065\u0054\u0069\u006d\u0065";_dbeg ="\u0053\u0074\u0061\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061\u0074\u0033\u0037\u0053t\u0061\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006da\u0074\u0033\u0038\u0053\u0074\u0061\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061\u0074\u00339\u0053\u0074\u0061\u006e\u0064\u0061r\u0064\u0046o\u0072\u006da\u00744\u0030";_fecb ="\u0053t\u0061\u006e\u0064a\u0072\u0064\u0046o\u0072ma\u0074\u0034\u0035\u0053\u0074\u0061\u006ed\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061\u0074\u0034\u0036\u0053\u0074\u0061\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061\u0074\u0034\u0037\u0053ta\u006ed\u0061\u0072\u0064\u0046\u006f\u0072m\u0061\u0074\u0034\u0038\u0053t\u0061\u006e\u0064\u0061\u0072\u0064\u0046\u006f\u0072\u006d\u0061t\u0034\u0039";);
@@ -525,7 +531,9 @@ func (_agab *Workbook )AddDefinedName (name ,ref string )DefinedName {if _agab .
 func (_egca *Workbook )Validate ()error {if _egca ==nil ||_egca ._bbae ==nil {return _gb .New ("\u0077o\u0072\u006bb\u006f\u006f\u006b\u0020n\u006f\u0074\u0020i\u006e\u0069\u0074\u0069\u0061\u006c\u0069\u007a\u0065d \u0063\u006f\u0072r\u0065\u0063t\u006c\u0079\u002c\u0020\u006e\u0069l\u0020\u0062a\u0073\u0065");};_faab :=uint32 (0);for _ ,_cagc :=range _egca ._bbae .Sheets .Sheet {if _cagc .SheetIdAttr > _faab {_faab =_cagc .SheetIdAttr ;};};if _faab !=uint32 (len (_egca ._fbed )){return _c .Errorf ("\u0066\u006f\u0075\u006e\u0064\u0020%\u0064\u0020\u0077\u006f\u0072\u006b\u0073\u0068\u0065\u0065\u0074\u0020\u0064\u0065\u0073\u0063\u0072\u0069\u0070\u0074i\u006f\u006e\u0073\u0020\u0061\u006e\u0064\u0020\u0025\u0064\u0020\u0077\u006f\u0072k\u0073h\u0065\u0065\u0074\u0073",_faab ,len (_egca ._fbed ));};_ecac :=map[string ]struct{}{};for _bace ,_cfdb :=range _egca ._bbae .Sheets .Sheet {_gdfg :=Sheet {_egca ,_cfdb ,_egca ._fbed [_bace ]};if _ ,_beae :=_ecac [_gdfg .Name ()];_beae {return _c .Errorf ("\u0077\u006f\u0072k\u0062\u006f\u006f\u006b\u002f\u0053\u0068\u0065\u0065\u0074\u005b\u0025\u0064\u005d\u0020\u0068\u0061\u0073\u0020\u0064\u0075\u0070\u006c\u0069\u0063\u0061\u0074\u0065\u0020n\u0061\u006d\u0065\u0020\u0027\u0025\u0073\u0027",_bace ,_gdfg .Name ());};_ecac [_gdfg .Name ()]=struct{}{};if _aeec :=_gdfg .ValidateWithPath (_c .Sprintf ("\u0077o\u0072k\u0062\u006f\u006f\u006b\u002fS\u0068\u0065e\u0074\u005b\u0025\u0064\u005d",_bace ));_aeec !=nil {return _aeec ;};if _aeaf :=_gdfg .Validate ();_aeaf !=nil {return _aeaf ;};};return nil ;};func (_cdd *Sheet )addNumberedRowFast (_gfe uint32 )Row {_ffgb :=_ggd .NewCT_Row ();_ffgb .RAttr =_d .Uint32 (_gfe );_cdd ._bcgb .SheetData .Row =append (_cdd ._bcgb .SheetData .Row ,_ffgb );return Row {_cdd ._bdb ,_cdd ,_ffgb };};
 
 // RemoveColumn removes column from the sheet and moves all columns to the right of the removed column one step left.
-func (_facgb *Sheet )RemoveColumn (column string )error {_bfbb ,_dacc :=_facgb .getAllCellsInFormulaArraysForColumn ();if _dacc !=nil {return _dacc ;};_fgfg :=_eg .ColumnToIndex (column );for _ ,_cdgg :=range _facgb .Rows (){_ebfa :=_c .Sprintf ("\u0025\u0073\u0025\u0064",column ,*_cdgg .X ().RAttr );if _ ,_acbb :=_bfbb [_ebfa ];_acbb {return nil ;};};for _ ,_cfed :=range _facgb .Rows (){_gece :=_cfed ._dggg .C ;for _agecb ,_ffcd :=range _gece {_bacd ,_egcge :=_eg .ParseCellReference (*_ffcd .RAttr );if _egcge !=nil {return _egcge ;};if _bacd .ColumnIdx ==_fgfg {_cfed ._dggg .C =append (_gece [:_agecb ],_facgb .slideCellsLeft (_gece [_agecb +1:])...);break ;}else if _bacd .ColumnIdx > _fgfg {_cfed ._dggg .C =append (_gece [:_agecb ],_facgb .slideCellsLeft (_gece [_agecb :])...);break ;};};};_dacc =_facgb .updateAfterRemove (_fgfg ,_gaf .UpdateActionRemoveColumn );if _dacc !=nil {return _dacc ;};_dacc =_facgb .removeColumnFromNamedRanges (_fgfg );if _dacc !=nil {return _dacc ;};_dacc =_facgb .removeColumnFromMergedCells (_fgfg );if _dacc !=nil {return _dacc ;};for _ ,_aagc :=range _facgb ._bdb .Sheets (){_aagc .RecalculateFormulas ();};return nil ;};
+// References to the removed cells become #REF! as for RemoveColumns. Unlike RemoveColumns, the formulas of every sheet
+// are then recalculated.
+func (_facgb *Sheet )RemoveColumn (column string )error {if _dacc :=_facgb .RemoveColumns (column ,1);_dacc !=nil {return _dacc ;};for _ ,_aagc :=range _facgb ._bdb .Sheets (){_aagc .RecalculateFormulas ();};return nil ;};
 
 // AddBorder creates a new empty border that can be applied to a cell style.
 func (_fbfb StyleSheet )AddBorder ()Border {_begbg :=_ggd .NewCT_Border ();_fbfb ._gcac .Borders .Border =append (_fbfb ._gcac .Borders .Border ,_begbg );_fbfb ._gcac .Borders .CountAttr =_d .Uint32 (uint32 (len (_fbfb ._gcac .Borders .Border )));return Border {_begbg ,_fbfb ._gcac .Borders };};
@@ -546,7 +554,7 @@ func (_ebgc *Sheet )SetBorder (cellRange string ,border Border )error {_edcc ,_g
 // supported,  if formula execution fails either due to a parse error or missing
 // function, or erorr in the result (even if expected) the cached value will be
 // left empty allowing Excel to recompute it on load.
-func (_gcab *Sheet )RecalculateFormulas (){_bfbd :=_aec .NewEvaluator ();_fedc :=_gcab .FormulaContext ();for _ ,_cdca :=range _gcab .Rows (){for _ ,_fgbc :=range _cdca .Cells (){if _fgbc .X ().F !=nil {_gdba :=_fgbc .X ().F .Content ;if _fgbc .X ().F .TAttr ==_ggd .ST_CellFormulaTypeShared &&len (_gdba )==0{continue ;};_bgcb :=_bfbd .Eval (_fedc ,_gdba ).AsString ();if _bgcb .Type ==_aec .ResultTypeError {_d .Log ("\u0065\u0072\u0072o\u0072\u0020\u0065\u0076a\u0075\u006c\u0061\u0074\u0069\u006e\u0067 \u0066\u006f\u0072\u006d\u0075\u006c\u0061\u0020\u0025\u0073\u003a\u0020\u0025\u0073",_gdba ,_bgcb .ErrorMessage );_fgbc .X ().V =nil ;}else {if _bgcb .Type ==_aec .ResultTypeNumber {_fgbc .X ().TAttr =_ggd .ST_CellTypeN ;}else {_fgbc .X ().TAttr =_ggd .ST_CellTypeInlineStr ;};_fgbc .X ().V =_d .String (_bgcb .Value ());if _fgbc .X ().F .TAttr ==_ggd .ST_CellFormulaTypeArray {if _bgcb .Type ==_aec .ResultTypeArray {_gcab .setArray (_fgbc .Reference (),_bgcb );}else if _bgcb .Type ==_aec .ResultTypeList {_gcab .setList (_fgbc .Reference (),_bgcb );};}else if _fgbc .X ().F .TAttr ==_ggd .ST_CellFormulaTypeShared &&_fgbc .X ().F .RefAttr !=nil {_fdedf ,_gdcf ,_efbd :=_eg .ParseRangeReference (*_fgbc .X ().F .RefAttr );if _efbd !=nil {_g .Printf ("\u0065\u0072r\u006f\u0072\u0020\u0069n\u0020\u0073h\u0061\u0072\u0065\u0064\u0020\u0066\u006f\u0072m\u0075\u006c\u0061\u0020\u0072\u0065\u0066\u0065\u0072\u0065\u006e\u0063e\u003a\u0020\u0025\u0073",_efbd );continue ;};_gcab .setShared (_fgbc .Reference (),_fdedf ,_gdcf ,_gdba );};};};};};};
+func (_gcab *Sheet )RecalculateFormulas (){_gcab .clearSpills ();_cfdeb :=newDependencyGraph (_gcab ._bdb ,[]Sheet {*_gcab });_cfdeb .Recalculate ();};func (_gcab *Sheet )_bfcga (_bcfe _aec .Context ,_fgbc Cell ,_gdba string ,_bgcb _aec .Result ){_bgcb =_bgcb .AsString ();if _bgcb .Type ==_aec .ResultTypeError {_d .Log ("\u0065\u0072\u0072o\u0072\u0020\u0065\u0076a\u0075\u006c\u0061\u0074\u0069\u006e\u0067 \u0066\u006f\u0072\u006d\u0075\u006c\u0061\u0020\u0025\u0073\u003a\u0020\u0025\u0073",_gdba ,_bgcb .ErrorMessage );_fgbc .X ().V =nil ;}else {if _bgcb .Type ==_aec .ResultTypeNumber {_fgbc .X ().TAttr =_ggd .ST_CellTypeN ;}else {_fgbc .X ().TAttr =_ggd .ST_CellTypeInlineStr ;};_fgbc .X ().V =_d .String (_bgcb .Value ());if _fgbc .X ().F .TAttr ==_ggd .ST_CellFormulaTypeArray {if _bgcb .Type ==_aec .ResultTypeArray {_gcab .setArray (_fgbc .Reference (),_bgcb );}else if _bgcb .Type ==_aec .ResultTypeList {_gcab .setList (_fgbc .Reference (),_bgcb );};}else if (_bgcb .Type ==_aec .ResultTypeArray ||_bgcb .Type ==_aec .ResultTypeList )&&_fgbc .X ().F .TAttr !=_ggd .ST_CellFormulaTypeShared {_gcab .spill (_fgbc ,_bgcb );}else if _fgbc .X ().F .TAttr ==_ggd .ST_CellFormulaTypeShared &&_fgbc .X ().F .RefAttr !=nil {_fdedf ,_gdcf ,_efbd :=_eg .ParseRangeReference (*_fgbc .X ().F .RefAttr );if _efbd !=nil {_g .Printf ("\u0065\u0072r\u006f\u0072\u0020\u0069n\u0020\u0073h\u0061\u0072\u0065\u0064\u0020\u0066\u006f\u0072m\u0075\u006c\u0061\u0020\u0072\u0065\u0066\u0065\u0072\u0065\u006e\u0063e\u003a\u0020\u0025\u0073",_efbd );return ;};_gcab .setShared (_bcfe ,_fgbc .Reference (),_fdedf ,_gdcf ,_gdba );};};};
 
 // MoveTo moves the top-left of the anchored object.
 func (_caba OneCellAnchor )MoveTo (col ,row int32 ){_caba .TopLeft ().SetCol (col );_caba .TopLeft ().SetRow (row );};func (_bafg Font )SetBold (b bool ){if b {_bafg ._beba .B =[]*_ggd .CT_BooleanProperty {{}};}else {_bafg ._beba .B =nil ;};};
@@ -556,7 +564,7 @@ func (_caba OneCellAnchor )MoveTo (col ,row int32 ){_caba .TopLeft ().SetCol (co
 func (_cgeb *Workbook )RemoveCalcChain (){var _gffc string ;for _ ,_gceg :=range _cgeb ._adebd .Relationships (){if _gceg .Type ()=="ht\u0074\u0070\u003a\u002f\u002f\u0073\u0063he\u006d\u0061\u0073\u002e\u006f\u0070\u0065\u006e\u0078\u006d\u006c\u0066\u006f\u0072\u006da\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u006f\u0066\u0066\u0069\u0063\u0065\u0044\u006f\u0063\u0075\u006d\u0065\u006et\u002f\u0032\u0030\u0030\u0036\u002fr\u0065\u006c\u0061\u0074\u0069\u006f\u006e\u0073\u0068i\u0070s\u002f\u0063\u0061\u006c\u0063\u0043\u0068\u0061\u0069\u006e"{_gffc ="\u0078\u006c\u002f"+_gceg .Target ();_cgeb ._adebd .Remove (_gceg );break ;};};if _gffc ==""{return ;};_cgeb .ContentTypes .RemoveOverride (_gffc );for _eeab ,_ebacd :=range _cgeb .ExtraFiles {if _ebacd .ZipPath ==_gffc {_cgeb .ExtraFiles [_eeab ]=_cgeb .ExtraFiles [len (_cgeb .ExtraFiles )-1];_cgeb .ExtraFiles =_cgeb .ExtraFiles [:len (_cgeb .ExtraFiles )-1];return ;};};};
 
 // Workbook is the top level container item for a set of spreadsheets.
-type Workbook struct{_cb .DocBase ;_bbae *_ggd .Workbook ;StyleSheet StyleSheet ;SharedStrings SharedStrings ;_cbge []*_ggd .Comments ;_fbed []*_ggd .Worksheet ;_fdbe []_cb .Relationships ;_adebd _cb .Relationships ;_bgea []*_fe .Theme ;_cefe []*_ce .WsDr ;_fcbeb []_cb .Relationships ;_cbbfe []*_cc .Container ;_fgcda []*_ba .ChartSpace ;_caaa []*_ggd .Table ;_bbeed string ;};
+type Workbook struct{_cb .DocBase ;_bbae *_ggd .Workbook ;StyleSheet StyleSheet ;SharedStrings SharedStrings ;_cbge []*_ggd .Comments ;_fbed []*_ggd .Worksheet ;_fdbe []_cb .Relationships ;_adebd _cb .Relationships ;_bgea []*_fe .Theme ;_cefe []*_ce .WsDr ;_fcbeb []_cb .Relationships ;_cbbfe []*_cc .Container ;_fgcda []*_ba .ChartSpace ;_caaa []*_ggd .Table ;_bbeed string ;streamingSheets map[*_ggd .Worksheet ]*StreamingSheet ;pivotCaches []*pivotCachePart ;pivotTables []*pivotTablePart ;spills map[*_ggd .Worksheet ]map[*_ggd .CT_Cell ]map[*_ggd .CT_Cell ]*string ;locale *_ga .Locale ;};
 
 // MaxColumnIdx returns the max used column of the sheet.
 func (_bgca Sheet )MaxColumnIdx ()uint32 {_cfeg :=uint32 (0);for _ ,_eed :=range _bgca .Rows (){_dgef :=_eed ._dggg .C ;if len (_dgef )> 0{_aggba :=_dgef [len (_dgef )-1];_ggab ,_ :=_eg .ParseCellReference (*_aggba .RAttr );if _cfeg < _ggab .ColumnIdx {_cfeg =_ggab .ColumnIdx ;};};};return _cfeg ;};
@@ -614,7 +622,7 @@ func (_bbea *Sheet )Row (rowNum uint32 )Row {for _ ,_gbgd :=range _bbea ._bcgb .
 func (_gfb Cell )GetValueAsBool ()(bool ,error ){if _gfb ._dbd .TAttr !=_ggd .ST_CellTypeB {return false ,_gb .New ("\u0063e\u006c\u006c\u0020\u0069\u0073\u0020\u006e\u006f\u0074\u0020\u006ff\u0020\u0062\u006f\u006f\u006c\u0020\u0074\u0079\u0070\u0065");};if _gfb ._dbd .V ==nil {return false ,_gb .New ("\u0063\u0065\u006c\u006c\u0020\u0068\u0061\u0073\u0020\u006e\u006f\u0020v\u0061\u006c\u0075\u0065");};return _de .ParseBool (*_gfb ._dbd .V );};
 
 // Epoch returns the point at which the dates/times in the workbook are relative to.
-func (_fafa *Workbook )Epoch ()_dga .Time {if _fafa .Uses1904Dates (){_dga .Date (1904,1,1,0,0,0,0,_dga .UTC );};return _dga .Date (1899,12,30,0,0,0,0,_dga .UTC );};
+func (_fafa *Workbook )Epoch ()_dga .Time {if _fafa .Uses1904Dates (){return _dga .Date (1904,1,1,0,0,0,0,_dga .UTC );};return _dga .Date (1899,12,30,0,0,0,0,_dga .UTC );};
 
 // SetFont applies a font to a cell style.  The font is referenced by its
 // index so modifying the font afterward will affect all styles that reference
@@ -628,7 +636,7 @@ func (_ecce StyleSheet )Fonts ()[]Font {_ffga :=[]Font {};for _ ,_bgdc :=range _
 func (_fea Cell )IsNumber ()bool {switch _fea ._dbd .TAttr {case _ggd .ST_CellTypeN :return true ;case _ggd .ST_CellTypeS ,_ggd .ST_CellTypeB :return false ;};return _fea ._dbd .V !=nil &&_ga .IsNumber (*_fea ._dbd .V );};
 
 // SetMinLength sets the minimum bar length in percent.
-func (_abc DataBarScale )SetMinLength (l uint32 ){_abc ._edfg .MinLengthAttr =_d .Uint32 (l )};func (_cgag *Workbook )onNewRelationship (_aggag *_ad .DecodeMap ,_fedbd ,_dcag string ,_geea []*_aa .File ,_gcdf *_aag .Relationship ,_aaga _ad .Target )error {_ccdee :=_d .DocTypeSpreadsheet ;switch _dcag {case _d .OfficeDocumentType :_cgag ._bbae =_ggd .NewWorkbook ();_aggag .AddTarget (_fedbd ,_cgag ._bbae ,_dcag ,0);_cgag ._adebd =_cb .NewRelationships ();_aggag .AddTarget (_ad .RelationsPathFor (_fedbd ),_cgag ._adebd .X (),_dcag ,0);_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,0);case _d .CorePropertiesType :_aggag .AddTarget (_fedbd ,_cgag .CoreProperties .X (),_dcag ,0);_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,0);case _d .CustomPropertiesType :_aggag .AddTarget (_fedbd ,_cgag .CustomProperties .X (),_dcag ,0);_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,0);case _d .ExtendedPropertiesType :_aggag .AddTarget (_fedbd ,_cgag .AppProperties .X (),_dcag ,0);_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,0);case _d .WorksheetType :_aagcf :=_ggd .NewWorksheet ();_fecf :=uint32 (len (_cgag ._fbed ));_cgag ._fbed =append (_cgag ._fbed ,_aagcf );_aggag .AddTarget (_fedbd ,_aagcf ,_dcag ,_fecf );_ddag :=_cb .NewRelationships ();_aggag .AddTarget (_ad .RelationsPathFor (_fedbd ),_ddag .X (),_dcag ,0);_cgag ._fdbe =append (_cgag ._fdbe ,_ddag );_cgag ._cbge =append (_cgag ._cbge ,nil );_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,len (_cgag ._fbed ));case _d .StylesType :_cgag .StyleSheet =NewStyleSheet (_cgag );_aggag .AddTarget (_fedbd ,_cgag .StyleSheet .X (),_dcag ,0);_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,0);case _d .ThemeType :_eadbg :=_fe .NewTheme ();_cgag ._bgea =append (_cgag ._bgea ,_eadbg );_aggag .AddTarget (_fedbd ,_eadbg ,_dcag ,0);_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,len (_cgag ._bgea ));case _d .SharedStringsType :_cgag .SharedStrings =NewSharedStrings ();_aggag .AddTarget (_fedbd ,_cgag .SharedStrings .X (),_dcag ,0);_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,0);case _d .ThumbnailType :for _dgaf ,_bdff :=range _geea {if _bdff ==nil {continue ;};if _bdff .Name ==_fedbd {_fdcda ,_dbaa :=_bdff .Open ();if _dbaa !=nil {return _c .Errorf ("e\u0072\u0072\u006f\u0072\u0020\u0072e\u0061\u0064\u0069\u006e\u0067\u0020\u0074\u0068\u0075m\u0062\u006e\u0061i\u006c:\u0020\u0025\u0073",_dbaa );};_cgag .Thumbnail ,_ ,_dbaa =_db .Decode (_fdcda );_fdcda .Close ();if _dbaa !=nil {return _c .Errorf ("\u0065\u0072\u0072\u006fr\u0020\u0064\u0065\u0063\u006f\u0064\u0069\u006e\u0067\u0020t\u0068u\u006d\u0062\u006e\u0061\u0069\u006c\u003a \u0025\u0073",_dbaa );};_geea [_dgaf ]=nil ;};};case _d .ImageType :for _cfce ,_dfge :=range _geea {if _dfge ==nil {continue ;};if _dfge .Name ==_fedbd {_aced ,_bagc :=_ad .ExtractToDiskTmp (_dfge ,_cgag .TmpPath );if _bagc !=nil {return _bagc ;};_cgaf ,_bagc :=_cb .ImageFromStorage (_aced );if _bagc !=nil {return _bagc ;};_cefdb :=_cb .MakeImageRef (_cgaf ,&_cgag .DocBase ,_cgag ._adebd );_cgag .Images =append (_cgag .Images ,_cefdb );_geea [_cfce ]=nil ;};};_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,len (_cgag .Images ));case _d .DrawingType :_gegf :=_ce .NewWsDr ();_afeef :=uint32 (len (_cgag ._cefe ));_aggag .AddTarget (_fedbd ,_gegf ,_dcag ,_afeef );_cgag ._cefe =append (_cgag ._cefe ,_gegf );_cbcab :=_cb .NewRelationships ();_aggag .AddTarget (_ad .RelationsPathFor (_fedbd ),_cbcab .X (),_dcag ,_afeef );_cgag ._fcbeb =append (_cgag ._fcbeb ,_cbcab );_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,len (_cgag ._cefe ));case _d .VMLDrawingType :_feggf :=_cc .NewContainer ();_eggf :=uint32 (len (_cgag ._cbbfe ));_aggag .AddTarget (_fedbd ,_feggf ,_dcag ,_eggf );_cgag ._cbbfe =append (_cgag ._cbbfe ,_feggf );case _d .CommentsType :_cgag ._cbge [_aaga .Index ]=_ggd .NewComments ();_aggag .AddTarget (_fedbd ,_cgag ._cbge [_aaga .Index ],_dcag ,_aaga .Index );_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,len (_cgag ._cbge ));case _d .ChartType :_ecaf :=_ba .NewChartSpace ();_deaab :=uint32 (len (_cgag ._fgcda ));_aggag .AddTarget (_fedbd ,_ecaf ,_dcag ,_deaab );_cgag ._fgcda =append (_cgag ._fgcda ,_ecaf );_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,len (_cgag ._fgcda ));case _d .TableType :_faba :=_ggd .NewTable ();_aegd :=uint32 (len (_cgag ._caaa ));_aggag .AddTarget (_fedbd ,_faba ,_dcag ,_aegd );_cgag ._caaa =append (_cgag ._caaa ,_faba );_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,len (_cgag ._caaa ));default:_d .Log ("\u0075\u006e\u0073\u0075\u0070\u0070\u006f\u0072\u0074\u0065d\u0020\u0072\u0065\u006c\u0061\u0074\u0069o\u006e\u0073\u0068\u0069\u0070\u0020\u0025\u0073\u0020\u0025\u0073",_fedbd ,_dcag );};return nil ;};
+func (_abc DataBarScale )SetMinLength (l uint32 ){_abc ._edfg .MinLengthAttr =_d .Uint32 (l )};func (_cgag *Workbook )onNewRelationship (_aggag *_ad .DecodeMap ,_fedbd ,_dcag string ,_geea []*_aa .File ,_gcdf *_aag .Relationship ,_aaga _ad .Target )error {_ccdee :=_d .DocTypeSpreadsheet ;switch _dcag {case _d .OfficeDocumentType :_cgag ._bbae =_ggd .NewWorkbook ();_aggag .AddTarget (_fedbd ,_cgag ._bbae ,_dcag ,0);_cgag ._adebd =_cb .NewRelationships ();_aggag .AddTarget (_ad .RelationsPathFor (_fedbd ),_cgag ._adebd .X (),_dcag ,0);_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,0);case _d .CorePropertiesType :_aggag .AddTarget (_fedbd ,_cgag .CoreProperties .X (),_dcag ,0);_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,0);case _d .CustomPropertiesType :_aggag .AddTarget (_fedbd ,_cgag .CustomProperties .X (),_dcag ,0);_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,0);case _d .ExtendedPropertiesType :_aggag .AddTarget (_fedbd ,_cgag .AppProperties .X (),_dcag ,0);_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,0);case _d .WorksheetType :_aagcf :=_ggd .NewWorksheet ();_fecf :=uint32 (len (_cgag ._fbed ));_cgag ._fbed =append (_cgag ._fbed ,_aagcf );_aggag .AddTarget (_fedbd ,_aagcf ,_dcag ,_fecf );_ddag :=_cb .NewRelationships ();_aggag .AddTarget (_ad .RelationsPathFor (_fedbd ),_ddag .X (),_dcag ,0);_cgag ._fdbe =append (_cgag ._fdbe ,_ddag );_cgag ._cbge =append (_cgag ._cbge ,nil );_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,len (_cgag ._fbed ));case _d .StylesType :_cgag .StyleSheet =NewStyleSheet (_cgag );_aggag .AddTarget (_fedbd ,_cgag .StyleSheet .X (),_dcag ,0);_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,0);case _d .ThemeType :_eadbg :=_fe .NewTheme ();_cgag ._bgea =append (_cgag ._bgea ,_eadbg );_aggag .AddTarget (_fedbd ,_eadbg ,_dcag ,0);_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,len (_cgag ._bgea ));case _d .SharedStringsType :_cgag .SharedStrings =NewSharedStrings ();_aggag .AddTarget (_fedbd ,_cgag .SharedStrings .X (),_dcag ,0);_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,0);case _d .ThumbnailType :for _dgaf ,_bdff :=range _geea {if _bdff ==nil {continue ;};if _bdff .Name ==_fedbd {_fdcda ,_dbaa :=_bdff .Open ();if _dbaa !=nil {return _c .Errorf ("e\u0072\u0072\u006f\u0072\u0020\u0072e\u0061\u0064\u0069\u006e\u0067\u0020\u0074\u0068\u0075m\u0062\u006e\u0061i\u006c:\u0020\u0025\u0073",_dbaa );};_cgag .Thumbnail ,_ ,_dbaa =_db .Decode (_fdcda );_fdcda .Close ();if _dbaa !=nil {return _c .Errorf ("\u0065\u0072\u0072\u006fr\u0020\u0064\u0065\u0063\u006f\u0064\u0069\u006e\u0067\u0020t\u0068u\u006d\u0062\u006e\u0061\u0069\u006c\u003a \u0025\u0073",_dbaa );};_geea [_dgaf ]=nil ;};};case _d .ImageType :for _cfce ,_dfge :=range _geea {if _dfge ==nil {continue ;};if _dfge .Name ==_fedbd {_aced ,_bagc :=_ad .ExtractToDiskTmp (_dfge ,_cgag .TmpPath );if _bagc !=nil {return _bagc ;};_cgaf ,_bagc :=_cb .ImageFromStorage (_aced );if _bagc !=nil {return _bagc ;};_cefdb :=_cb .MakeImageRef (_cgaf ,&_cgag .DocBase ,_cgag ._adebd );_cgag .Images =append (_cgag .Images ,_cefdb );_geea [_cfce ]=nil ;};};_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,len (_cgag .Images ));case _d .DrawingType :_gegf :=_ce .NewWsDr ();_afeef :=uint32 (len (_cgag ._cefe ));_aggag .AddTarget (_fedbd ,_gegf ,_dcag ,_afeef );_cgag ._cefe =append (_cgag ._cefe ,_gegf );_cbcab :=_cb .NewRelationships ();_aggag .AddTarget (_ad .RelationsPathFor (_fedbd ),_cbcab .X (),_dcag ,_afeef );_cgag ._fcbeb =append (_cgag ._fcbeb ,_cbcab );_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,len (_cgag ._cefe ));case _d .VMLDrawingType :_feggf :=_cc .NewContainer ();_eggf :=uint32 (len (_cgag ._cbbfe ));_aggag .AddTarget (_fedbd ,_feggf ,_dcag ,_eggf );_cgag ._cbbfe =append (_cgag ._cbbfe ,_feggf );case _d .CommentsType :_cgag ._cbge [_aaga .Index ]=_ggd .NewComments ();_aggag .AddTarget (_fedbd ,_cgag ._cbge [_aaga .Index ],_dcag ,_aaga .Index );_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,len (_cgag ._cbge ));case _d .ChartType :_ecaf :=_ba .NewChartSpace ();_deaab :=uint32 (len (_cgag ._fgcda ));_aggag .AddTarget (_fedbd ,_ecaf ,_dcag ,_deaab );_cgag ._fgcda =append (_cgag ._fgcda ,_ecaf );_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,len (_cgag ._fgcda ));case _d .TableType :_faba :=_ggd .NewTable ();_aegd :=uint32 (len (_cgag ._caaa ));_aggag .AddTarget (_fedbd ,_faba ,_dcag ,_aegd );_cgag ._caaa =append (_cgag ._caaa ,_faba );_gcdf .TargetAttr =_d .RelativeFilename (_ccdee ,_aaga .Typ ,_dcag ,len (_cgag ._caaa ));case _d .PivotCacheDefinitionType ,_d .PivotCacheRecordsType ,_d .PivotTableType :_cgag .onNewPivotRelationship (_aggag ,_fedbd ,_dcag ,_gcdf ,_aaga );default:_d .Log ("\u0075\u006e\u0073\u0075\u0070\u0070\u006f\u0072\u0074\u0065d\u0020\u0072\u0065\u006c\u0061\u0074\u0069o\u006e\u0073\u0068\u0069\u0070\u0020\u0025\u0073\u0020\u0025\u0073",_fedbd ,_dcag );};return nil ;};
 
 // Cell retrieves or adds a new cell to a row. Col is the column (e.g. 'A', 'B')
 func (_ccab Row )Cell (col string )Cell {_feb :=_c .Sprintf ("\u0025\u0073\u0025\u0064",col ,_ccab .RowNumber ());for _ ,_fdac :=range _ccab ._dggg .C {if _fdac .RAttr !=nil &&*_fdac .RAttr ==_feb {return Cell {_ccab ._fcba ,_ccab ._dafa ,_ccab ._dggg ,_fdac };};};return _ccab .AddNamedCell (col );};
@@ -742,7 +750,7 @@ func (_daf CellMarker )Row ()int32 {return _daf ._bcb .Row };
 func (_cad Comment )Author ()string {if _cad ._gbfb .AuthorIdAttr < uint32 (len (_cad ._adb .Authors .Author )){return _cad ._adb .Authors .Author [_cad ._gbfb .AuthorIdAttr ];};return "";};
 
 // Type returns the type of anchor
-func (_ab AbsoluteAnchor )Type ()AnchorType {return AnchorTypeAbsolute };type evalContext struct{_afdd *Sheet ;_cga ,_fba uint32 ;_acde map[string ]struct{};};
+func (_ab AbsoluteAnchor )Type ()AnchorType {return AnchorTypeAbsolute };type evalContext struct{_afdd *Sheet ;_cga ,_fba uint32 ;_acde map[string ]struct{};thisCell string ;graph *DependencyGraph ;masters map[uint32 ]*_ggd .CT_Cell ;};
 
 // AddFormatValue adds a format value to be used in determining which icons to display.
 func (_gddd IconScale )AddFormatValue (t _ggd .ST_CfvoType ,val string ){_bbcb :=_ggd .NewCT_Cfvo ();_bbcb .TypeAttr =t ;_bbcb .ValAttr =_d .String (val );_gddd ._adcf .Cfvo =append (_gddd ._adcf .Cfvo ,_bbcb );};
@@ -751,8 +759,9 @@ func (_gddd IconScale )AddFormatValue (t _ggd .ST_CfvoType ,val string ){_bbcb :
 //go:generate stringer -type=StandardFormat
 type StandardFormat uint32 ;
 
-// GetFormula returns the formula for a cell.
-func (_ebf Cell )GetFormula ()string {if _ebf ._dbd .F !=nil {return _ebf ._dbd .F .Content ;};return "";};
+// GetFormula returns the formula for a cell.  For the cells of a shared
+// formula, it is the formula of the first cell moved to the cell.
+func (_ebf Cell )GetFormula ()string {if _ebf ._dbd .F !=nil {return cellFormula (_ebf );};return "";};
 
 // Comments is the container for comments for a single sheet.
 type Comments struct{_aeff *Workbook ;_fcd *_ggd .Comments ;};func (_dcc Sheet )validateSheetNames ()error {if len (_dcc .Name ())> 31{return _c .Errorf ("\u0073\u0068\u0065\u0065\u0074 \u006e\u0061\u006d\u0065\u0020\u0027\u0025\u0073\u0027\u0020\u0068\u0061\u0073 \u0025\u0064\u0020\u0063\u0068\u0061\u0072\u0061\u0063\u0074\u0065\u0072\u0073\u002c\u0020\u006d\u0061\u0078\u0020\u006c\u0065\u006e\u0067\u0074\u0068\u0020\u0069\u0073\u0020\u00331",_dcc .Name (),len (_dcc .Name ()));};return nil ;};
@@ -1005,7 +1014,7 @@ func (_gaa Cell )SetString (s string )int {_gaa ._ebb .ensureSharedStringsRelati
 func (_ebbgd SheetProtection )SetPassword (pw string ){_ebbgd .SetPasswordHash (PasswordHash (pw ))};
 
 // SetRowOffset sets the row offset of the top-left of the image in fixed units.
-func (_dec AbsoluteAnchor )SetRowOffset (m _ae .Distance ){_dec ._cd .Pos .YAttr .ST_CoordinateUnqualified =_d .Int64 (int64 (m /_ae .EMU ));};func (_cgb *evalContext )Cell (ref string ,ev _aec .Evaluator )_aec .Result {if !_agfe (ref ){return _aec .MakeErrorResultType (_aec .ErrorTypeName ,"");};_dcf :=_cgb ._afdd .Name ()+"\u0021"+ref ;if _fddab ,_bbgd :=ev .GetFromCache (_dcf );_bbgd {return _fddab ;};_ccbe ,_ddg :=_eg .ParseCellReference (ref );if _ddg !=nil {return _aec .MakeErrorResult (_c .Sprintf ("e\u0072r\u006f\u0072\u0020\u0070\u0061\u0072\u0073\u0069n\u0067\u0020\u0025\u0073: \u0025\u0073",ref ,_ddg ));};if _cgb ._cga !=0&&!_ccbe .AbsoluteColumn {_ccbe .ColumnIdx +=_cgb ._cga ;_ccbe .Column =_eg .IndexToColumn (_ccbe .ColumnIdx );};if _cgb ._fba !=0&&!_ccbe .AbsoluteRow {_ccbe .RowIdx +=_cgb ._fba ;};_fffb :=_cgb ._afdd .Cell (_ccbe .String ());if _fffb .HasFormula (){if _ ,_gbgf :=_cgb ._acde [ref ];_gbgf {return _aec .MakeErrorResult ("r\u0065\u0063\u0075\u0072\u0073\u0069\u006f\u006e\u0020\u0064\u0065\u0074\u0065\u0063\u0074\u0065\u0064\u0020d\u0075\u0072\u0069\u006e\u0067\u0020\u0065\u0076\u0061\u006cua\u0074\u0069\u006fn\u0020o\u0066\u0020"+ref );};_cgb ._acde [ref ]=struct{}{};_dac :=ev .Eval (_cgb ,_fffb .GetFormula ());delete (_cgb ._acde ,ref );ev .SetCache (_dcf ,_dac );return _dac ;};if _fffb .IsEmpty (){_eefe :=_aec .MakeEmptyResult ();ev .SetCache (_dcf ,_eefe );return _eefe ;}else if _fffb .IsNumber (){_ccda ,_ :=_fffb .GetValueAsNumber ();_ffg :=_aec .MakeNumberResult (_ccda );ev .SetCache (_dcf ,_ffg );return _ffg ;}else if _fffb .IsBool (){_dgaa ,_ :=_fffb .GetValueAsBool ();_gea :=_aec .MakeBoolResult (_dgaa );ev .SetCache (_dcf ,_gea );return _gea ;};_bbd ,_ :=_fffb .GetRawValue ();if _fffb .IsError (){_bbde :=_aec .MakeErrorResult ("");_bbde .ValueString =_bbd ;ev .SetCache (_dcf ,_bbde );return _bbde ;};_dddd :=_aec .MakeStringResult (_bbd );ev .SetCache (_dcf ,_dddd );return _dddd ;};func (_cgd Cell )getLocked ()bool {if _cgd ._dbd .SAttr ==nil {return false ;};_gfc :=*_cgd ._dbd .SAttr ;_ecaa :=_cgd ._ebb .StyleSheet .GetCellStyle (_gfc );return *_ecaa ._cae .Protection .LockedAttr ;};
+func (_dec AbsoluteAnchor )SetRowOffset (m _ae .Distance ){_dec ._cd .Pos .YAttr .ST_CoordinateUnqualified =_d .Int64 (int64 (m /_ae .EMU ));};func (_cgb *evalContext )Cell (ref string ,ev _aec .Evaluator )_aec .Result {_gfcb :=_dgd .HasSuffix (ref ,"#");ref =_dgd .TrimSuffix (ref ,"#");if !_agfe (ref ){return _aec .MakeErrorResultType (_aec .ErrorTypeName ,"");};_ccbe ,_ddg :=_eg .ParseCellReference (ref );if _ddg !=nil {return _aec .MakeErrorResult (_c .Sprintf ("e\u0072r\u006f\u0072\u0020\u0070\u0061\u0072\u0073\u0069n\u0067\u0020\u0025\u0073: \u0025\u0073",ref ,_ddg ));};if _cgb ._cga !=0&&!_ccbe .AbsoluteColumn {_ccbe .ColumnIdx +=_cgb ._cga ;_ccbe .Column =_eg .IndexToColumn (_ccbe .ColumnIdx );};if _cgb ._fba !=0&&!_ccbe .AbsoluteRow {_ccbe .RowIdx +=_cgb ._fba ;};_dcf :=_cgb ._afdd .Name ()+"\u0021"+_dgd .ToUpper (_ccbe .Column )+_de .Itoa (int (_ccbe .RowIdx ));if _fddab ,_bbgd :=ev .GetFromCache (_dcf );_bbgd {return spillResult (_fddab ,_gfcb );};if _cgb .graph !=nil {if _bgbd ,_fcag :=_cgb .graph .result (_cgb ._afdd ._bcgb ,_ccbe );_fcag {return spillResult (_bgbd ,_gfcb );};};_fffb :=_cgb ._afdd .Cell (_ccbe .String ());if _fffb .HasFormula (){if _ ,_gbgf :=_cgb ._acde [ref ];_gbgf {return _aec .MakeErrorResult ("r\u0065\u0063\u0075\u0072\u0073\u0069\u006f\u006e\u0020\u0064\u0065\u0074\u0065\u0063\u0074\u0065\u0064\u0020d\u0075\u0072\u0069\u006e\u0067\u0020\u0065\u0076\u0061\u006cua\u0074\u0069\u006fn\u0020o\u0066\u0020"+ref );};_cgb ._acde [ref ]=struct{}{};_cgfda :=_cgb .thisCell ;_cgb .thisCell =_ccbe .String ();_bgfe ,_cagf :=_cgb ._cga ,_cgb ._fba ;_cgb ._cga ,_cgb ._fba =0,0;_dac :=ev .Eval (_cgb ,_cgb .formula (_fffb ));_cgb ._cga ,_cgb ._fba =_bgfe ,_cagf ;_cgb .thisCell =_cgfda ;delete (_cgb ._acde ,ref );ev .SetCache (_dcf ,_dac );return spillResult (_dac ,_gfcb );};_dddd :=cellResult (_fffb );ev .SetCache (_dcf ,_dddd );return _dddd ;};func (_cgd Cell )getLocked ()bool {if _cgd ._dbd .SAttr ==nil {return false ;};_gfc :=*_cgd ._dbd .SAttr ;_ecaa :=_cgd ._ebb .StyleSheet .GetCellStyle (_gfc );return *_ecaa ._cae .Protection .LockedAttr ;};
 
 // X returns the inner wrapped XML type.
 func (_edecg IconScale )X ()*_ggd .CT_IconSet {return _edecg ._adcf };
@@ -1018,7 +1027,15 @@ func (_bddf OneCellAnchor )SetWidthCells (int32 ){};
 // supported, if formula execution fails either due to a parse error or missing
 // function, or erorr in the result (even if expected) the cached value will be
 // left empty allowing Excel to recompute it on load.
-func (_feed *Workbook )RecalculateFormulas (){for _ ,_gcfg :=range _feed .Sheets (){_gcfg .RecalculateFormulas ();};};
+func (_feed *Workbook )RecalculateFormulas (){_feed .RecalculateFormulasWithOptions ();};
+
+// RecalculateFormulasWithOptions re-computes the formulas of the workbook as
+// RecalculateFormulas does. Options can set the number of workers that evaluate
+// independent formulas in parallel and a context to cancel the recalculation,
+// see WithWorkers and WithContext. The results don't depend on the number of
+// workers. It returns the error of the context if the recalculation is
+// cancelled.
+func (_feed *Workbook )RecalculateFormulasWithOptions (opts ...RecalcOption )error {for _ ,_gcfg :=range _feed .Sheets (){_gcfg .clearSpills ();};return NewDependencyGraph (_feed ).Recalculate (opts ...);};
 
 // GetString returns the string in a cell if it's an inline or string table
 // string. Otherwise it returns an empty string.
@@ -0,0 +1,265 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/unidoc/unioffice/common/tempstorage"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+	"github.com/unidoc/unioffice/zippkg"
+)
+
+// ErrStreamClosed is returned when rows are written to a streaming sheet after
+// the workbook containing it has been saved.
+var ErrStreamClosed = errors.New("streaming sheet is closed")
+
+// StreamingSheet is a sheet whose rows are written to temporary storage as they
+// are added instead of being kept in memory until the workbook is saved. Only a
+// single row is held in memory at a time, so rows must be added in ascending
+// order and can't be modified once a subsequent row has been started.
+//
+// Everything outside of the sheet data (column widths and styles, merged cells,
+// views, etc.) is kept in memory and written when the workbook is saved, so it
+// can be configured at any point before saving.
+type StreamingSheet struct {
+	sheet    Sheet
+	file     tempstorage.File
+	buf      *bufio.Writer
+	enc      *xml.Encoder
+	firstRow uint32
+	lastRow  uint32
+	minCol   uint32
+	maxCol   uint32
+	hasData  bool
+	closed   bool
+	err      error
+}
+
+// AddStreamingSheet adds a new sheet to the workbook whose rows are streamed to
+// temporary storage as they are added.  The sheet data is copied into the
+// output when the workbook is saved, after which no further rows can be added.
+// Temporary files are removed by Workbook.Close.
+func (wb *Workbook) AddStreamingSheet() (*StreamingSheet, error) {
+	if wb.TmpPath == "" {
+		tmp, err := tempstorage.TempDir("unioffice-xlsx")
+		if err != nil {
+			return nil, err
+		}
+		wb.TmpPath = tmp
+	}
+	f, err := tempstorage.TempFile(wb.TmpPath, "sheetdata")
+	if err != nil {
+		return nil, err
+	}
+	sheet := wb.AddSheet()
+	ss := &StreamingSheet{sheet: sheet, file: f}
+	ss.buf = bufio.NewWriter(f)
+	ss.enc = xml.NewEncoder(ss.buf)
+	if wb.streamingSheets == nil {
+		wb.streamingSheets = map[*sml.Worksheet]*StreamingSheet{}
+	}
+	wb.streamingSheets[sheet.X()] = ss
+	return ss, nil
+}
+
+// Name returns the sheet name.
+func (s *StreamingSheet) Name() string { return s.sheet.Name() }
+
+// SetName sets the sheet name.
+func (s *StreamingSheet) SetName(name string) { s.sheet.SetName(name) }
+
+// X returns the inner wrapped XML type.  The SheetData element only contains
+// the row that is currently being written.
+func (s *StreamingSheet) X() *sml.Worksheet { return s.sheet.X() }
+
+// Column returns or creates a column that with a given index (1-N).  Columns
+// can be used to set the width and style of a column.
+func (s *StreamingSheet) Column(idx uint32) Column { return s.sheet.Column(idx) }
+
+// AddMergedCells merges cells within a sheet.
+func (s *StreamingSheet) AddMergedCells(fromRef, toRef string) MergedCell {
+	return s.sheet.AddMergedCells(fromRef, toRef)
+}
+
+// SetFrozen removes any existing sheet views and creates a new single view with
+// either the first row, first column or both frozen.
+func (s *StreamingSheet) SetFrozen(firstRow, firstCol bool) { s.sheet.SetFrozen(firstRow, firstCol) }
+
+// SetAutoFilter creates autofilters on the sheet.
+func (s *StreamingSheet) SetAutoFilter(rangeRef string) { s.sheet.SetAutoFilter(rangeRef) }
+
+// AddRow flushes the current row and starts a new row numbered immediately
+// after it.
+func (s *StreamingSheet) AddRow() Row { return s.AddNumberedRow(s.lastRow + 1) }
+
+// AddNumberedRow flushes the current row and starts a new row with the given
+// row number.  Rows must be added in ascending order.  If rowNum is not greater
+// than the last row added, or the sheet has already been written, the row
+// returned isn't part of the sheet, so whatever is set on it is ignored, and
+// Err reports the cause.
+func (s *StreamingSheet) AddNumberedRow(rowNum uint32) Row {
+	if s.closed {
+		s.setErr(ErrStreamClosed)
+		return s.detachedRow(rowNum)
+	}
+	if rowNum <= s.lastRow {
+		s.setErr(fmt.Errorf("row %d must be greater than the last row written (%d)", rowNum, s.lastRow))
+		return s.detachedRow(rowNum)
+	}
+	if err := s.flush(); err != nil {
+		return s.detachedRow(rowNum)
+	}
+	s.lastRow = rowNum
+	return s.sheet.AddNumberedRow(rowNum)
+}
+
+// detachedRow returns a row of a sheet that isn't part of the workbook, which
+// is never written.
+func (s *StreamingSheet) detachedRow(rowNum uint32) Row {
+	ws := sml.NewWorksheet()
+	if ws.SheetData == nil {
+		ws.SheetData = sml.NewCT_SheetData()
+	}
+	detached := Sheet{s.sheet._bdb, nil, ws}
+	return detached.AddNumberedRow(rowNum)
+}
+
+// Err returns the first error encountered while writing rows.
+func (s *StreamingSheet) Err() error { return s.err }
+
+// LastRow returns the number of the last row that was started.
+func (s *StreamingSheet) LastRow() uint32 { return s.lastRow }
+
+func (s *StreamingSheet) setErr(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// flush encodes any rows held by the sheet to temporary storage.
+func (s *StreamingSheet) flush() error {
+	if s.err != nil {
+		return s.err
+	}
+	sd := s.sheet.X().SheetData
+	start := xml.StartElement{Name: xml.Name{Local: "ma:row"}}
+	for _, r := range sd.Row {
+		if r.RAttr != nil && (s.firstRow == 0 || *r.RAttr < s.firstRow) {
+			s.firstRow = *r.RAttr
+		}
+		for _, c := range r.C {
+			if c.RAttr == nil {
+				continue
+			}
+			cref, err := reference.ParseCellReference(*c.RAttr)
+			if err != nil {
+				continue
+			}
+			if !s.hasData || cref.ColumnIdx < s.minCol {
+				s.minCol = cref.ColumnIdx
+			}
+			if !s.hasData || cref.ColumnIdx > s.maxCol {
+				s.maxCol = cref.ColumnIdx
+			}
+			s.hasData = true
+		}
+		if err := s.enc.EncodeElement(r, start); err != nil {
+			s.setErr(err)
+			return err
+		}
+	}
+	sd.Row = nil
+	return nil
+}
+
+// close flushes the remaining rows and closes the temporary file.
+func (s *StreamingSheet) close() error {
+	if s.closed {
+		return s.err
+	}
+	s.flush()
+	if err := s.enc.Flush(); err != nil {
+		s.setErr(err)
+	}
+	if err := s.buf.Flush(); err != nil {
+		s.setErr(err)
+	}
+	if err := s.file.Close(); err != nil {
+		s.setErr(err)
+	}
+	s.closed = true
+	return s.err
+}
+
+// dimension returns the extents of the data written to the sheet.
+func (s *StreamingSheet) dimension() string {
+	if !s.hasData {
+		return "A1"
+	}
+	return fmt.Sprintf("%s%d:%s%d", reference.IndexToColumn(s.minCol), s.firstRow,
+		reference.IndexToColumn(s.maxCol), s.lastRow)
+}
+
+// writeTo writes the worksheet to the zip file, copying the sheet data from
+// temporary storage.
+func (s *StreamingSheet) writeTo(z *zip.Writer, filename string) error {
+	if err := s.close(); err != nil {
+		return err
+	}
+	ws := s.sheet.X()
+	ws.Dimension.RefAttr = s.dimension()
+
+	// encode the sheet without any rows and split it around the empty
+	// sheetData element so the streamed rows can be inserted
+	skel := bytes.Buffer{}
+	if err := xml.NewEncoder(&skel).Encode(ws); err != nil {
+		return fmt.Errorf("marshaling %s: %s", filename, err)
+	}
+	const sheetDataStart = "<ma:sheetData>"
+	idx := bytes.Index(skel.Bytes(), []byte(sheetDataStart+"</ma:sheetData>"))
+	if idx < 0 {
+		return fmt.Errorf("marshaling %s: sheetData not found", filename)
+	}
+	head := skel.Bytes()[:idx+len(sheetDataStart)]
+	tail := skel.Bytes()[idx+len(sheetDataStart):]
+
+	fh := &zip.FileHeader{Name: filename, Method: zip.Deflate}
+	fh.SetModTime(time.Now())
+	w, err := z.CreateHeader(fh)
+	if err != nil {
+		return fmt.Errorf("creating %s in zip: %s", filename, err)
+	}
+	if _, err := io.WriteString(w, zippkg.XMLHeader); err != nil {
+		return err
+	}
+	if _, err := w.Write(head); err != nil {
+		return err
+	}
+	f, err := tempstorage.Open(s.file.Name())
+	if err != nil {
+		return fmt.Errorf("opening sheet data for %s: %s", filename, err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(w, f); err != nil {
+		return fmt.Errorf("copying sheet data for %s: %s", filename, err)
+	}
+	if _, err := w.Write(tail); err != nil {
+		return err
+	}
+	_, err = w.Write([]byte{'\r', '\n'})
+	return err
+}
@@ -0,0 +1,159 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/unidoc/unioffice/measurement"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+)
+
+func TestStreamingSheet(t *testing.T) {
+	wb := New()
+	defer wb.Close()
+	regular := wb.AddSheet()
+	regular.SetName("Regular")
+	ss, err := wb.AddStreamingSheet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss.SetName("Streamed")
+	cs := wb.StyleSheet.AddCellStyle()
+	cs.SetNumberFormat("0.00")
+	ss.Column(1).SetWidth(20 * measurement.Character)
+
+	r := ss.AddRow()
+	r.Cell("A").SetString("name")
+	r.Cell("B").SetString("amount")
+	for i := 0; i < 100; i++ {
+		r = ss.AddRow()
+		if len(ss.X().SheetData.Row) != 1 {
+			t.Fatalf("expected a single row in memory, got %d", len(ss.X().SheetData.Row))
+		}
+		r.Cell("A").SetString(fmt.Sprintf("item %d", i%3))
+		c := r.Cell("B")
+		c.SetNumber(float64(i) + 0.5)
+		c.SetStyle(cs)
+	}
+	r = ss.AddNumberedRow(110)
+	r.Cell("A").SetInlineString("inline")
+	r.Cell("C").SetFormulaRaw("SUM(B2:B101)")
+	ss.AddMergedCells("A110", "B110")
+	ss.SetFrozen(true, false)
+	if ss.LastRow() != 110 {
+		t.Errorf("expected the last row to be 110, got %d", ss.LastRow())
+	}
+
+	rd := saveAndRead(t, wb)
+	if err := ss.Err(); err != nil {
+		t.Fatal(err)
+	}
+	s, err := rd.GetSheet("Streamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ref, exp := range map[string]string{
+		"A1": "name", "B1": "amount",
+		"A2": "item 0", "A3": "item 1", "A100": "item 2", "A101": "item 0",
+		"B2": "0.50", "B101": "99.50",
+		"A110": "inline",
+	} {
+		if got := s.Cell(ref).GetFormattedValue(); got != exp {
+			t.Errorf("expected %q in %s, got %q", exp, ref, got)
+		}
+	}
+	if got := s.Cell("C110").GetFormula(); got != "SUM(B2:B101)" {
+		t.Errorf("expected the formula SUM(B2:B101) in C110, got %s", got)
+	}
+	if got := s.Cell("A110").X().TAttr; got != sml.ST_CellTypeInlineStr {
+		t.Errorf("expected an inline string in A110, got %s", got)
+	}
+	if got := len(rd.SharedStrings.X().Si); got != 5 {
+		t.Errorf("expected 5 shared strings, got %d", got)
+	}
+	if got := s.X().Dimension.RefAttr; got != "A1:C110" {
+		t.Errorf("expected the dimension A1:C110, got %s", got)
+	}
+	if got := len(s.Rows()); got != 102 {
+		t.Errorf("expected 102 rows, got %d", got)
+	}
+	if mc := s.MergedCells(); len(mc) != 1 || mc[0].Reference() != "A110:B110" {
+		t.Errorf("expected the merged cells A110:B110, got %v", mc)
+	}
+	if w := s.Column(1).X().WidthAttr; w == nil || *w != 20 {
+		t.Errorf("expected column A to be 20 characters wide, got %v", w)
+	}
+	if sv := s.X().SheetViews; sv == nil || len(sv.SheetView) != 1 || sv.SheetView[0].Pane == nil {
+		t.Errorf("expected a frozen pane")
+	}
+	if _, err := rd.GetSheet("Regular"); err != nil {
+		t.Errorf("expected the regular sheet to be saved, got %s", err)
+	}
+}
+
+func TestStreamingSheetRowOrder(t *testing.T) {
+	wb := New()
+	defer wb.Close()
+	ss, err := wb.AddStreamingSheet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss.AddNumberedRow(2).Cell("A").SetString("two")
+	// rows that are out of order are detached so their cells aren't written
+	ss.AddNumberedRow(2).Cell("A").SetString("again")
+	ss.AddNumberedRow(1).Cell("A").SetString("one")
+	if ss.Err() == nil {
+		t.Fatalf("expected an error adding rows out of order")
+	}
+	if ss.LastRow() != 2 {
+		t.Errorf("expected the last row to remain 2, got %d", ss.LastRow())
+	}
+	// rows are no longer written once an error has occurred
+	ss.AddRow().Cell("A").SetString("three")
+
+	buf := bytes.Buffer{}
+	if err := wb.Save(&buf); err == nil {
+		t.Errorf("expected the error to be returned when saving")
+	}
+}
+
+func TestStreamingSheetClosed(t *testing.T) {
+	wb := New()
+	defer wb.Close()
+	ss, err := wb.AddStreamingSheet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss.AddRow().Cell("A").SetNumber(1)
+	rd := saveAndRead(t, wb)
+	r := ss.AddRow()
+	r.Cell("A").SetNumber(2)
+	if ss.Err() != ErrStreamClosed {
+		t.Errorf("expected ErrStreamClosed, got %v", ss.Err())
+	}
+	if len(ss.X().SheetData.Row) != 0 {
+		t.Errorf("expected the detached row not to be added to the sheet")
+	}
+	if got := rd.Sheets()[0].Cell("A1").GetFormattedValue(); got != "1" {
+		t.Errorf("expected 1 in A1, got %s", got)
+	}
+
+	empty := New()
+	defer empty.Close()
+	es, err := empty.AddStreamingSheet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rd = saveAndRead(t, empty)
+	if got := rd.Sheets()[0].X().Dimension.RefAttr; got != "A1" || es.Err() != nil {
+		t.Errorf("expected the dimension of an empty sheet to be A1, got %s, %v", got, es.Err())
+	}
+}
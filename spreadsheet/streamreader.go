@@ -0,0 +1,591 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/unidoc/unioffice"
+	"github.com/unidoc/unioffice/common/tempstorage"
+	"github.com/unidoc/unioffice/schema/soo/pkg/relationships"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/zippkg"
+)
+
+// ErrSheetNotFound is returned when a sheet with a given name doesn't exist.
+var ErrSheetNotFound = errors.New("sheet not found")
+
+// StreamReader provides read-only, low memory access to the rows of the sheets
+// in a workbook.  Unlike Open/Read, worksheets are never decoded in full.  Rows
+// are decoded one at a time as they are requested and the shared string table
+// and style sheet are only loaded once a cell that needs them is encountered.
+//
+// The shared string table is copied to temporary storage with an index of
+// where each string starts, and strings are read back from there as cells
+// refer to them, keeping only a bounded number of recently used strings in
+// memory.  Cells with shared strings are returned as inline strings holding
+// their text.  If the temporary storage doesn't support reading files at an
+// offset, as with memstore, the table is loaded in memory instead.  The
+// temporary files are removed by Close.
+type StreamReader struct {
+	zr     *zip.Reader
+	closer io.Closer
+	files  map[string]*zip.File
+	wb     *Workbook
+	sheets []streamSheetInfo
+
+	sstPath      string
+	stylesPath   string
+	sstLoaded    bool
+	stylesLoaded bool
+	strings      *streamStrings
+}
+
+type streamSheetInfo struct {
+	ct   *sml.CT_Sheet
+	path string
+}
+
+// OpenStreamReader opens an xlsx file for reading rows with a StreamReader.
+// The StreamReader must be closed to release the file.
+func OpenStreamReader(filename string) (*StreamReader, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s: %s", filename, err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("error opening %s: %s", filename, err)
+	}
+	sr, err := NewStreamReader(f, fi.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	sr.closer = f
+	return sr, nil
+}
+
+// NewStreamReader constructs a StreamReader reading an xlsx file from r.
+func NewStreamReader(r io.ReaderAt, size int64) (*StreamReader, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("parsing zip: %s", err)
+	}
+	sr := &StreamReader{zr: zr, files: map[string]*zip.File{}}
+	for _, f := range zr.File {
+		sr.files[f.Name] = f
+	}
+
+	rels, err := sr.decodeRels(unioffice.BaseRelsFilename)
+	if err != nil {
+		return nil, err
+	}
+	wbPath := ""
+	for _, rel := range rels.Relationship {
+		if rel.TypeAttr == unioffice.OfficeDocumentType {
+			wbPath = resolveTarget("", rel.TargetAttr)
+		}
+	}
+	if wbPath == "" {
+		return nil, errors.New("no workbook found")
+	}
+
+	sr.wb = New()
+	if err := sr.decode(wbPath, sr.wb.X()); err != nil {
+		return nil, err
+	}
+	wbRels, err := sr.decodeRels(zippkg.RelationsPathFor(wbPath))
+	if err != nil {
+		return nil, err
+	}
+	targets := map[string]string{}
+	dir := path.Dir(wbPath)
+	for _, rel := range wbRels.Relationship {
+		tgt := resolveTarget(dir, rel.TargetAttr)
+		switch rel.TypeAttr {
+		case unioffice.WorksheetType:
+			targets[rel.IdAttr] = tgt
+		case unioffice.SharedStringsType:
+			sr.sstPath = tgt
+		case unioffice.StylesType:
+			sr.stylesPath = tgt
+		}
+	}
+	if sr.wb.X().Sheets != nil {
+		for _, s := range sr.wb.X().Sheets.Sheet {
+			if tgt, ok := targets[s.IdAttr]; ok {
+				sr.sheets = append(sr.sheets, streamSheetInfo{s, tgt})
+			}
+		}
+	}
+	return sr, nil
+}
+
+// resolveTarget resolves a relationship target relative to the directory
+// containing the source part.
+func resolveTarget(dir, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Clean(path.Join(dir, target))
+}
+
+func (sr *StreamReader) decode(name string, dest interface{}) error {
+	f, ok := sr.files[name]
+	if !ok {
+		return fmt.Errorf("%s not found", name)
+	}
+	return zippkg.Decode(f, dest)
+}
+
+func (sr *StreamReader) decodeRels(name string) (*relationships.Relationships, error) {
+	rels := relationships.NewRelationships()
+	if _, ok := sr.files[name]; !ok {
+		return rels, nil
+	}
+	if err := sr.decode(name, rels); err != nil {
+		return nil, err
+	}
+	return rels, nil
+}
+
+// Close removes the temporary copy of the shared string table and closes the
+// underlying file if the StreamReader was constructed with OpenStreamReader.
+func (sr *StreamReader) Close() error {
+	var err error
+	if sr.strings != nil {
+		err = sr.strings.close()
+		sr.strings = nil
+	}
+	if sr.closer != nil {
+		if cerr := sr.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// SheetNames returns the names of the sheets in the workbook.
+func (sr *StreamReader) SheetNames() []string {
+	names := []string{}
+	for _, s := range sr.sheets {
+		names = append(names, s.ct.NameAttr)
+	}
+	return names
+}
+
+// Uses1904Dates returns true if the workbook uses dates relative to 1 Jan 1904.
+func (sr *StreamReader) Uses1904Dates() bool { return sr.wb.Uses1904Dates() }
+
+// IterateRows calls fn for each row of the named sheet in document order,
+// stopping at the first error returned by fn.  The Row passed to fn is only
+// valid until fn returns.
+func (sr *StreamReader) IterateRows(sheetName string, fn func(Row) error) error {
+	rs, err := sr.RowScanner(sheetName)
+	if err != nil {
+		return err
+	}
+	defer rs.Close()
+	for rs.Next() {
+		if err := fn(rs.Row()); err != nil {
+			return err
+		}
+	}
+	return rs.Err()
+}
+
+// RowScanner returns a scanner over the rows of the named sheet.
+func (sr *StreamReader) RowScanner(sheetName string) (*RowScanner, error) {
+	for _, s := range sr.sheets {
+		if s.ct.NameAttr != sheetName {
+			continue
+		}
+		f, ok := sr.files[s.path]
+		if !ok {
+			return nil, fmt.Errorf("%s not found", s.path)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %s", f.Name, err)
+		}
+		ws := sml.NewWorksheet()
+		ws.SheetData = sml.NewCT_SheetData()
+		sheet := &Sheet{sr.wb, s.ct, ws}
+		return &RowScanner{sr: sr, sheet: sheet, rc: rc, dec: xml.NewDecoder(rc),
+			masters: map[uint32]*sml.CT_Cell{}}, nil
+	}
+	return nil, ErrSheetNotFound
+}
+
+// ensureSharedStrings copies the shared string table to temporary storage on
+// first use, or loads it in memory if the storage can't be read at an offset.
+func (sr *StreamReader) ensureSharedStrings() error {
+	if sr.sstLoaded {
+		return nil
+	}
+	sr.sstLoaded = true
+	f, ok := sr.files[sr.sstPath]
+	if sr.sstPath == "" || !ok {
+		return nil
+	}
+	ss, err := newStreamStrings()
+	if err != nil {
+		return err
+	}
+	if ss == nil {
+		sst := sr.wb.SharedStrings.X()
+		err := scanSharedStrings(f, func(s string) error {
+			rst := sml.NewCT_Rst()
+			rst.T = unioffice.String(s)
+			sst.Si = append(sst.Si, rst)
+			return nil
+		})
+		sst.CountAttr = unioffice.Uint32(uint32(len(sst.Si)))
+		sst.UniqueCountAttr = sst.CountAttr
+		return err
+	}
+	sr.strings = ss
+	if err := scanSharedStrings(f, ss.add); err != nil {
+		return err
+	}
+	return ss.flush()
+}
+
+// sharedString returns the text of a shared string.
+func (sr *StreamReader) sharedString(idx int) (string, error) {
+	if err := sr.ensureSharedStrings(); err != nil {
+		return "", err
+	}
+	if sr.strings != nil {
+		return sr.strings.get(idx)
+	}
+	return sr.wb.SharedStrings.GetString(idx)
+}
+
+// scanSharedStrings calls fn with the text of each item of the shared string
+// table in a zip file.  Rich text runs are flattened and phonetic runs are
+// dropped.
+func scanSharedStrings(f *zip.File, fn func(string) error) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("error reading %s: %s", f.Name, err)
+	}
+	defer rc.Close()
+
+	dec := xml.NewDecoder(rc)
+	var cur *strings.Builder
+	inText, inPhonetic := false, false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error decoding %s: %s", f.Name, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				cur = &strings.Builder{}
+			case "rPh":
+				inPhonetic = true
+			case "t":
+				inText = cur != nil && !inPhonetic
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "si":
+				if err := fn(cur.String()); err != nil {
+					return err
+				}
+				cur = nil
+			case "rPh":
+				inPhonetic = false
+			case "t":
+				inText = false
+			}
+		case xml.CharData:
+			if inText {
+				cur.Write(t)
+			}
+		}
+	}
+}
+
+// streamStringsCacheSize is the number of shared strings kept in memory by a
+// StreamReader.
+const streamStringsCacheSize = 4096
+
+// streamStrings is a shared string table copied to temporary storage.  The
+// text of the strings is stored one after another in one file and the offset
+// of each string in another, as fixed size integers, so the text of a string
+// can be read without reading the others.  Recently read strings are cached.
+type streamStrings struct {
+	dir         string
+	data, index tempstorage.File
+	dataR       io.ReaderAt
+	indexR      io.ReaderAt
+	dataW       *bufio.Writer
+	indexW      *bufio.Writer
+	offset      uint64
+	count       int
+	cache       map[int]string
+	cached      []int
+	next        int
+}
+
+// newStreamStrings creates the temporary files of a shared string table,
+// returning nil if the temporary storage can't be read at an offset.
+func newStreamStrings() (*streamStrings, error) {
+	dir, err := tempstorage.TempDir("unioffice-sst")
+	if err != nil {
+		return nil, err
+	}
+	ss := &streamStrings{dir: dir, cache: map[int]string{}}
+	if ss.data, err = tempstorage.TempFile(dir, "strings"); err != nil {
+		ss.close()
+		return nil, err
+	}
+	if ss.index, err = tempstorage.TempFile(dir, "index"); err != nil {
+		ss.close()
+		return nil, err
+	}
+	var ok1, ok2 bool
+	ss.dataR, ok1 = ss.data.(io.ReaderAt)
+	ss.indexR, ok2 = ss.index.(io.ReaderAt)
+	if !ok1 || !ok2 {
+		ss.close()
+		return nil, nil
+	}
+	ss.dataW = bufio.NewWriter(ss.data)
+	ss.indexW = bufio.NewWriter(ss.index)
+	return ss, nil
+}
+
+// add appends a string to the table.
+func (ss *streamStrings) add(s string) error {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], ss.offset)
+	if _, err := ss.indexW.Write(b[:]); err != nil {
+		return err
+	}
+	if _, err := ss.dataW.WriteString(s); err != nil {
+		return err
+	}
+	ss.offset += uint64(len(s))
+	ss.count++
+	return nil
+}
+
+// flush writes the end of the last string to the index and the buffered
+// strings to the files.
+func (ss *streamStrings) flush() error {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], ss.offset)
+	if _, err := ss.indexW.Write(b[:]); err != nil {
+		return err
+	}
+	if err := ss.dataW.Flush(); err != nil {
+		return err
+	}
+	return ss.indexW.Flush()
+}
+
+// get returns the text of a string, reading it from the files if it isn't
+// cached.
+func (ss *streamStrings) get(idx int) (string, error) {
+	if s, ok := ss.cache[idx]; ok {
+		return s, nil
+	}
+	if idx < 0 || idx >= ss.count {
+		return "", fmt.Errorf("invalid string index %d, table has %d strings", idx, ss.count)
+	}
+	var b [16]byte
+	if _, err := ss.indexR.ReadAt(b[:], int64(idx)*8); err != nil {
+		return "", err
+	}
+	from := binary.LittleEndian.Uint64(b[:8])
+	to := binary.LittleEndian.Uint64(b[8:])
+	buf := make([]byte, to-from)
+	if _, err := ss.dataR.ReadAt(buf, int64(from)); err != nil && !(err == io.EOF && len(buf) == 0) {
+		return "", err
+	}
+	s := string(buf)
+	// the oldest string is evicted once the cache is full
+	if len(ss.cached) < streamStringsCacheSize {
+		ss.cached = append(ss.cached, idx)
+	} else {
+		delete(ss.cache, ss.cached[ss.next])
+		ss.cached[ss.next] = idx
+		ss.next = (ss.next + 1) % streamStringsCacheSize
+	}
+	ss.cache[idx] = s
+	return s, nil
+}
+
+// close closes and removes the temporary files.
+func (ss *streamStrings) close() error {
+	for _, f := range []tempstorage.File{ss.data, ss.index} {
+		if f != nil {
+			f.Close()
+		}
+	}
+	return tempstorage.RemoveAll(ss.dir)
+}
+
+// ensureStyles loads the style sheet on first use.
+func (sr *StreamReader) ensureStyles() error {
+	if sr.stylesLoaded {
+		return nil
+	}
+	sr.stylesLoaded = true
+	if _, ok := sr.files[sr.stylesPath]; sr.stylesPath == "" || !ok {
+		return nil
+	}
+	ss := StyleSheet{sr.wb, sml.NewStyleSheet()}
+	if err := sr.decode(sr.stylesPath, ss.X()); err != nil {
+		return err
+	}
+	sr.wb.StyleSheet = ss
+	return nil
+}
+
+// RowScanner decodes the rows of a worksheet one at a time.  It is created by
+// StreamReader.RowScanner and should be closed when no longer needed.
+type RowScanner struct {
+	sr    *StreamReader
+	sheet *Sheet
+	rc    io.ReadCloser
+	dec   *xml.Decoder
+	row   *sml.CT_Row
+	err   error
+	done  bool
+
+	// the first cells of the shared formulas by si
+	masters map[uint32]*sml.CT_Cell
+}
+
+// Next advances to the next row, returning false when there are no more rows
+// or an error occurs.
+func (rs *RowScanner) Next() bool {
+	if rs.done {
+		return false
+	}
+	for {
+		tok, err := rs.dec.Token()
+		if err == io.EOF {
+			rs.done = true
+			return false
+		}
+		if err != nil {
+			rs.err = err
+			rs.done = true
+			return false
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "row" {
+			continue
+		}
+		row := sml.NewCT_Row()
+		if err := rs.dec.DecodeElement(row, &se); err != nil {
+			rs.err = err
+			rs.done = true
+			return false
+		}
+		if err := rs.resolve(row); err != nil {
+			rs.err = err
+			rs.done = true
+			return false
+		}
+		rs.row = row
+		return true
+	}
+}
+
+// resolve replaces the shared strings of a row with their text, loads the
+// styles if the row requires them and gives the cells of shared formulas
+// formulas of their own.
+func (rs *RowScanner) resolve(row *sml.CT_Row) error {
+	needsStyles := row.SAttr != nil
+	for _, c := range row.C {
+		if f := c.F; f != nil && f.TAttr == sml.ST_CellFormulaTypeShared && f.SiAttr != nil {
+			if f.Content != "" {
+				rs.masters[*f.SiAttr] = c
+			} else if content := sharedFormula(c, rs.masters); content != "" {
+				c.F = sml.NewCT_CellFormula()
+				c.F.Content = content
+			}
+		}
+		if c.TAttr == sml.ST_CellTypeS && c.V != nil {
+			// shared strings become inline strings so the table isn't needed
+			// to read them
+			idx, err := strconv.Atoi(*c.V)
+			if err != nil {
+				return fmt.Errorf("invalid shared string index %s: %s", *c.V, err)
+			}
+			text, err := rs.sr.sharedString(idx)
+			if err != nil {
+				return err
+			}
+			c.TAttr = sml.ST_CellTypeInlineStr
+			c.Is = sml.NewCT_Rst()
+			c.Is.T = unioffice.String(text)
+			c.V = nil
+		}
+		if c.SAttr != nil {
+			needsStyles = true
+		}
+	}
+	if needsStyles {
+		return rs.sr.ensureStyles()
+	}
+	return nil
+}
+
+// Row returns the current row.
+func (rs *RowScanner) Row() Row {
+	return Row{rs.sr.wb, rs.sheet, rs.row}
+}
+
+// Err returns the first error encountered while scanning.
+func (rs *RowScanner) Err() error { return rs.err }
+
+// Close releases the resources associated with the scanner.
+func (rs *RowScanner) Close() error {
+	rs.done = true
+	return rs.rc.Close()
+}
+
+// IterateRows calls fn for each row of the named sheet of an in-memory
+// workbook, stopping at the first error returned by fn.  To read large files
+// without decoding them completely, use a StreamReader instead.
+func (wb *Workbook) IterateRows(sheetName string, fn func(Row) error) error {
+	s, err := wb.GetSheet(sheetName)
+	if err != nil {
+		return ErrSheetNotFound
+	}
+	for _, r := range s.Rows() {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
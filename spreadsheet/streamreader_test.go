@@ -0,0 +1,254 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/unidoc/unioffice"
+	"github.com/unidoc/unioffice/common/tempstorage/diskstore"
+	"github.com/unidoc/unioffice/common/tempstorage/memstore"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+)
+
+// streamWorkbook returns a saved workbook with shared strings, including one
+// with rich text and phonetic runs, styles and a shared formula.
+func streamWorkbook(t *testing.T) []byte {
+	t.Helper()
+	wb := New()
+	s := wb.AddSheet()
+	s.SetName("Data")
+	s.Cell("A1").SetString("name")
+	s.Cell("B1").SetString("amount")
+	s.Cell("A2").SetString("apple")
+	s.Cell("B2").SetNumber(1.5)
+	s.Cell("A3").SetString("pear")
+	s.Cell("B3").SetNumber(2)
+	s.Cell("A4").SetString("apple")
+	s.Cell("B4").SetNumber(3)
+	cs := wb.StyleSheet.AddCellStyle()
+	cs.SetNumberFormat("0.00")
+	s.Cell("B2").SetStyle(cs)
+	if err := s.Cell("C2").SetFormulaShared("B2*2", 2, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	rst := sml.NewCT_Rst()
+	for _, text := range []string{"rich ", "text"} {
+		r := sml.NewCT_RElt()
+		r.T = text
+		rst.R = append(rst.R, r)
+	}
+	ph := sml.NewCT_PhoneticRun()
+	ph.T = "phonetic"
+	rst.RPh = append(rst.RPh, ph)
+	sst := wb.SharedStrings.X()
+	sst.Si = append(sst.Si, rst)
+	c := s.Cell("A5")
+	c.X().TAttr = sml.ST_CellTypeS
+	c.X().V = unioffice.String(fmt.Sprintf("%d", len(sst.Si)-1))
+
+	empty := wb.AddSheet()
+	empty.SetName("Empty")
+	buf := bytes.Buffer{}
+	if err := wb.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func streamRows(t *testing.T, sr *StreamReader, sheet string) map[string]string {
+	t.Helper()
+	got := map[string]string{}
+	err := sr.IterateRows(sheet, func(r Row) error {
+		for _, c := range r.Cells() {
+			got[c.Reference()] = c.GetFormattedValue()
+			if c.HasFormula() {
+				got[c.Reference()+"="] = c.GetFormula()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestStreamReader(t *testing.T) {
+	for _, storage := range []struct {
+		name string
+		set  func()
+	}{
+		{"disk", diskstore.SetAsStorage},
+		{"memory", memstore.SetAsStorage},
+	} {
+		t.Run(storage.name, func(t *testing.T) {
+			storage.set()
+			defer diskstore.SetAsStorage()
+			b := streamWorkbook(t)
+			sr, err := NewStreamReader(bytes.NewReader(b), int64(len(b)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fmt.Sprint(sr.SheetNames()); got != "[Data Empty]" {
+				t.Errorf("expected sheets [Data Empty], got %s", got)
+			}
+			got := streamRows(t, sr, "Data")
+			for ref, exp := range map[string]string{
+				"A1": "name", "A2": "apple", "A3": "pear", "A4": "apple", "A5": "rich text",
+				"B2": "1.50", "B3": "2",
+				"C2=": "B2*2", "C3=": "B3*2", "C4=": "B4*2",
+			} {
+				if got[ref] != exp {
+					t.Errorf("expected %q in %s, got %q", exp, ref, got[ref])
+				}
+			}
+			if sr.strings != nil && storage.name == "memory" || sr.strings == nil && storage.name == "disk" {
+				t.Errorf("expected the shared strings to be read from disk only with disk storage")
+			}
+			dir := ""
+			if sr.strings != nil {
+				dir = sr.strings.dir
+			}
+			if err := sr.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if dir != "" {
+				if _, err := os.Stat(dir); !os.IsNotExist(err) {
+					t.Errorf("expected the temporary strings to be removed on closing")
+				}
+			}
+		})
+	}
+}
+
+func TestStreamReaderLoadsOnDemand(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetNumber(1)
+	s.Cell("A2").SetString("text")
+	buf := bytes.Buffer{}
+	if err := wb.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	sr, err := NewStreamReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sr.Close()
+	rs, err := sr.RowScanner("Sheet 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rs.Close()
+	if !rs.Next() || sr.sstLoaded {
+		t.Fatalf("expected the shared strings to be loaded only once needed")
+	}
+	if !rs.Next() || !sr.sstLoaded {
+		t.Fatalf("expected the shared strings to be loaded for the second row")
+	}
+	c := rs.Row().Cells()[0]
+	if c.X().TAttr != sml.ST_CellTypeInlineStr || c.GetString() != "text" {
+		t.Errorf("expected the shared string to be read as an inline string, got %s", c.GetString())
+	}
+	if rs.Next() || rs.Err() != nil {
+		t.Errorf("expected two rows, got %v", rs.Err())
+	}
+}
+
+func TestStreamReaderStringCache(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	n := streamStringsCacheSize + 100
+	for i := 0; i < n; i++ {
+		s.Cell(fmt.Sprintf("A%d", i+1)).SetString(fmt.Sprintf("s%d", i))
+		// strings read again after being evicted from the cache
+		s.Cell(fmt.Sprintf("B%d", i+1)).SetString(fmt.Sprintf("s%d", n-1-i))
+	}
+	buf := bytes.Buffer{}
+	if err := wb.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	sr, err := NewStreamReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sr.Close()
+	row := 0
+	err = sr.IterateRows("Sheet 1", func(r Row) error {
+		cells := r.Cells()
+		if cells[0].GetString() != fmt.Sprintf("s%d", row) || cells[1].GetString() != fmt.Sprintf("s%d", n-1-row) {
+			return fmt.Errorf("unexpected strings %s and %s in row %d", cells[0].GetString(), cells[1].GetString(), row+1)
+		}
+		row++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row != n || len(sr.strings.cache) > streamStringsCacheSize {
+		t.Errorf("expected %d rows with at most %d cached strings, got %d and %d", n, streamStringsCacheSize, row, len(sr.strings.cache))
+	}
+}
+
+func TestStreamReaderErrors(t *testing.T) {
+	b := streamWorkbook(t)
+	sr, err := NewStreamReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sr.Close()
+	if _, err := sr.RowScanner("Missing"); err != ErrSheetNotFound {
+		t.Errorf("expected ErrSheetNotFound, got %v", err)
+	}
+	if err := sr.IterateRows("Missing", func(Row) error { return nil }); err != ErrSheetNotFound {
+		t.Errorf("expected ErrSheetNotFound, got %v", err)
+	}
+	stop := errors.New("stop")
+	rows := 0
+	err = sr.IterateRows("Data", func(Row) error {
+		rows++
+		return stop
+	})
+	if err != stop || rows != 1 {
+		t.Errorf("expected iteration to stop at the first error, got %v after %d rows", err, rows)
+	}
+	if got := streamRows(t, sr, "Empty"); len(got) != 0 {
+		t.Errorf("expected no cells in an empty sheet, got %v", got)
+	}
+	if _, err := NewStreamReader(bytes.NewReader([]byte("not a zip")), 9); err == nil {
+		t.Errorf("expected an error reading a file that isn't a zip")
+	}
+}
+
+func TestWorkbookIterateRows(t *testing.T) {
+	b := streamWorkbook(t)
+	wb, err := Read(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := []uint32{}
+	err = wb.IterateRows("Data", func(r Row) error {
+		rows = append(rows, r.RowNumber())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(rows); got != "[1 2 3 4 5]" {
+		t.Errorf("expected rows 1 to 5, got %s", got)
+	}
+	if err := wb.IterateRows("Missing", func(Row) error { return nil }); err != ErrSheetNotFound {
+		t.Errorf("expected ErrSheetNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,817 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/unidoc/unioffice"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+)
+
+// TableTotalFunction is the function used to compute a column of the totals
+// row of a table.
+type TableTotalFunction byte
+
+// TableTotalFunction types
+const (
+	TableTotalFunctionNone TableTotalFunction = iota
+	TableTotalFunctionSum
+	TableTotalFunctionMin
+	TableTotalFunctionMax
+	TableTotalFunctionAverage
+	TableTotalFunctionCount
+	TableTotalFunctionCountNums
+	TableTotalFunctionStdDev
+	TableTotalFunctionVar
+)
+
+// tableTotalFunctions maps the totals row functions to the schema and to the
+// SUBTOTAL function number used in the totals row formula.
+var tableTotalFunctions = []struct {
+	typ      sml.ST_TotalsRowFunction
+	subtotal int
+}{
+	TableTotalFunctionNone:      {sml.ST_TotalsRowFunctionNone, 0},
+	TableTotalFunctionSum:       {sml.ST_TotalsRowFunctionSum, 109},
+	TableTotalFunctionMin:       {sml.ST_TotalsRowFunctionMin, 105},
+	TableTotalFunctionMax:       {sml.ST_TotalsRowFunctionMax, 104},
+	TableTotalFunctionAverage:   {sml.ST_TotalsRowFunctionAverage, 101},
+	TableTotalFunctionCount:     {sml.ST_TotalsRowFunctionCount, 103},
+	TableTotalFunctionCountNums: {sml.ST_TotalsRowFunctionCountNums, 102},
+	TableTotalFunctionStdDev:    {sml.ST_TotalsRowFunctionStdDev, 107},
+	TableTotalFunctionVar:       {sml.ST_TotalsRowFunctionVar, 110},
+}
+
+// DefaultTableStyle is the style applied to new tables.
+const DefaultTableStyle = "TableStyleMedium2"
+
+// AddTable creates a table over a range of cells (e.g. 'A1:D10').  The first
+// row of the range is the header row, the text of its cells is used for the
+// column names and cells without text are given a default name.
+func (s *Sheet) AddTable(ref string) (Table, error) {
+	from, to, err := parseTableRange(ref)
+	if err != nil {
+		return Table{}, err
+	}
+	if to.RowIdx == from.RowIdx {
+		return Table{}, errors.New("table must contain a header row and at least one row of data")
+	}
+	if err := s.checkTableOverlap(nil, from, to); err != nil {
+		return Table{}, err
+	}
+	sheetIdx := s.index()
+	if sheetIdx < 0 {
+		return Table{}, errors.New("sheet not found in workbook")
+	}
+	wb := s._bdb
+	dt := unioffice.DocTypeSpreadsheet
+
+	tbl := sml.NewTable()
+	tbl.IdAttr = 1
+	for _, t := range wb._caaa {
+		if t.IdAttr >= tbl.IdAttr {
+			tbl.IdAttr = t.IdAttr + 1
+		}
+	}
+	name := ""
+	for i := len(wb._caaa) + 1; ; i++ {
+		name = fmt.Sprintf("Table%d", i)
+		if !wb.tableNameUsed(name, nil) {
+			break
+		}
+	}
+	tbl.NameAttr = unioffice.String(name)
+	tbl.DisplayNameAttr = name
+	tbl.RefAttr = rangeString(from, to)
+	tbl.TotalsRowShownAttr = unioffice.Bool(false)
+	tbl.AutoFilter = sml.NewCT_AutoFilter()
+	tbl.AutoFilter.RefAttr = unioffice.String(tbl.RefAttr)
+	tbl.TableColumns = sml.NewCT_TableColumns()
+	tbl.TableStyleInfo = sml.NewCT_TableStyleInfo()
+	tbl.TableStyleInfo.NameAttr = unioffice.String(DefaultTableStyle)
+	tbl.TableStyleInfo.ShowFirstColumnAttr = unioffice.Bool(false)
+	tbl.TableStyleInfo.ShowLastColumnAttr = unioffice.Bool(false)
+	tbl.TableStyleInfo.ShowRowStripesAttr = unioffice.Bool(true)
+	tbl.TableStyleInfo.ShowColumnStripesAttr = unioffice.Bool(false)
+	t := Table{tbl, wb}
+	t.updateColumns(*s, from, to, nil)
+
+	// the table is added last and then moved into sheet order, which is the
+	// order the workbook keeps tables in
+	wb._caaa = append(wb._caaa, tbl)
+	rel := wb._fdbe[sheetIdx].AddAutoRelationship(dt, unioffice.WorksheetType, len(wb._caaa), unioffice.TableType)
+	if s._bcgb.TableParts == nil {
+		s._bcgb.TableParts = sml.NewCT_TableParts()
+	}
+	tp := sml.NewCT_TablePart()
+	tp.IdAttr = rel.ID()
+	s._bcgb.TableParts.TablePart = append(s._bcgb.TableParts.TablePart, tp)
+	s._bcgb.TableParts.CountAttr = unioffice.Uint32(uint32(len(s._bcgb.TableParts.TablePart)))
+	wb.ContentTypes.AddOverride(unioffice.AbsoluteFilename(dt, unioffice.TableType, len(wb._caaa)), unioffice.TableContentType)
+	wb.renumberTables()
+	return t, nil
+}
+
+// Tables returns the tables on the sheet.
+func (s *Sheet) Tables() []Table {
+	ret := []Table{}
+	idx := s.index()
+	if idx < 0 || s._bcgb.TableParts == nil {
+		return ret
+	}
+	for _, tp := range s._bcgb.TableParts.TablePart {
+		if t := s._bdb.tableForPart(idx, tp); t != nil {
+			ret = append(ret, Table{t, s._bdb})
+		}
+	}
+	return ret
+}
+
+// RemoveTable removes a table from the sheet.  The cells of the table are left
+// unchanged.
+func (s *Sheet) RemoveTable(t Table) error {
+	idx := s.index()
+	if idx < 0 || s._bcgb.TableParts == nil {
+		return errors.New("table not found on sheet")
+	}
+	wb := s._bdb
+	for i, tp := range s._bcgb.TableParts.TablePart {
+		if wb.tableForPart(idx, tp) != t._cbab {
+			continue
+		}
+		parts := s._bcgb.TableParts
+		parts.TablePart = append(parts.TablePart[:i], parts.TablePart[i+1:]...)
+		parts.CountAttr = unioffice.Uint32(uint32(len(parts.TablePart)))
+		if len(parts.TablePart) == 0 {
+			s._bcgb.TableParts = nil
+		}
+		rels := wb._fdbe[idx].X()
+		for j, r := range rels.Relationship {
+			if r.IdAttr == tp.IdAttr {
+				rels.Relationship = append(rels.Relationship[:j], rels.Relationship[j+1:]...)
+				break
+			}
+		}
+		dt := unioffice.DocTypeSpreadsheet
+		wb.ContentTypes.RemoveOverride(unioffice.AbsoluteFilename(dt, unioffice.TableType, len(wb._caaa)))
+		wb.renumberTables()
+		return nil
+	}
+	return errors.New("table not found on sheet")
+}
+
+// tableForPart returns the table referenced by a table part of a sheet.
+func (wb *Workbook) tableForPart(sheetIdx int, tp *sml.CT_TablePart) *sml.Table {
+	dt := unioffice.DocTypeSpreadsheet
+	for _, r := range wb._fdbe[sheetIdx].X().Relationship {
+		if r.IdAttr != tp.IdAttr || r.TypeAttr != unioffice.TableType {
+			continue
+		}
+		for i, t := range wb._caaa {
+			if r.TargetAttr == unioffice.RelativeFilename(dt, unioffice.WorksheetType, unioffice.TableType, i+1) {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// renumberTables orders the tables of the workbook by the sheet they are on
+// and updates the relationships of the sheets to match.
+func (wb *Workbook) renumberTables() {
+	dt := unioffice.DocTypeSpreadsheet
+	type partRel struct {
+		tbl *sml.Table
+		rel string
+	}
+	ordered := []*sml.Table{}
+	rels := map[int][]partRel{}
+	for i, ws := range wb._fbed {
+		if ws.TableParts == nil {
+			continue
+		}
+		for _, tp := range ws.TableParts.TablePart {
+			if t := wb.tableForPart(i, tp); t != nil {
+				ordered = append(ordered, t)
+				rels[i] = append(rels[i], partRel{t, tp.IdAttr})
+			}
+		}
+	}
+	for i, prs := range rels {
+		for _, pr := range prs {
+			for _, r := range wb._fdbe[i].X().Relationship {
+				if r.IdAttr != pr.rel {
+					continue
+				}
+				for k, t := range ordered {
+					if t == pr.tbl {
+						r.TargetAttr = unioffice.RelativeFilename(dt, unioffice.WorksheetType, unioffice.TableType, k+1)
+					}
+				}
+			}
+		}
+	}
+	wb._caaa = ordered
+}
+
+// tableNameUsed returns true if a name is used by another table or a defined
+// name.  Names are compared case insensitively.
+func (wb *Workbook) tableNameUsed(name string, except *sml.Table) bool {
+	for _, t := range wb._caaa {
+		if t == except {
+			continue
+		}
+		if strings.EqualFold(t.DisplayNameAttr, name) || (t.NameAttr != nil && strings.EqualFold(*t.NameAttr, name)) {
+			return true
+		}
+	}
+	for _, dn := range wb.DefinedNames() {
+		if strings.EqualFold(dn.Name(), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTableRange parses a range of the form A1:D10.
+func parseTableRange(ref string) (reference.CellReference, reference.CellReference, error) {
+	from, to, err := reference.ParseRangeReference(ref)
+	if err != nil {
+		return from, to, err
+	}
+	if to.RowIdx < from.RowIdx {
+		from.RowIdx, to.RowIdx = to.RowIdx, from.RowIdx
+	}
+	if to.ColumnIdx < from.ColumnIdx {
+		from.ColumnIdx, to.ColumnIdx = to.ColumnIdx, from.ColumnIdx
+	}
+	from.Column = reference.IndexToColumn(from.ColumnIdx)
+	to.Column = reference.IndexToColumn(to.ColumnIdx)
+	return from, to, nil
+}
+
+func rangeString(from, to reference.CellReference) string {
+	return fmt.Sprintf("%s%d:%s%d", reference.IndexToColumn(from.ColumnIdx), from.RowIdx,
+		reference.IndexToColumn(to.ColumnIdx), to.RowIdx)
+}
+
+// checkTableOverlap returns an error if a range overlaps a table on the sheet
+// other than except.
+func (s *Sheet) checkTableOverlap(except *sml.Table, from, to reference.CellReference) error {
+	for _, t := range s.Tables() {
+		if t._cbab == except {
+			continue
+		}
+		tf, tt, err := parseTableRange(t.Reference())
+		if err != nil {
+			continue
+		}
+		if from.ColumnIdx <= tt.ColumnIdx && tf.ColumnIdx <= to.ColumnIdx &&
+			from.RowIdx <= tt.RowIdx && tf.RowIdx <= to.RowIdx {
+			return fmt.Errorf("range overlaps table %s", t.Name())
+		}
+	}
+	return nil
+}
+
+// sheet returns the sheet containing the table.
+func (t Table) sheet() (Sheet, error) {
+	if t.wb == nil {
+		return Sheet{}, errors.New("table is not part of a workbook")
+	}
+	for i, s := range t.wb.Sheets() {
+		if s._bcgb.TableParts == nil {
+			continue
+		}
+		for _, tp := range s._bcgb.TableParts.TablePart {
+			if t.wb.tableForPart(i, tp) == t._cbab {
+				return s, nil
+			}
+		}
+	}
+	return Sheet{}, errors.New("table not found in workbook")
+}
+
+// updateColumns sets the table columns to match a range, keeping the existing
+// columns that are still within the range and naming new columns from the
+// header row.
+func (t Table) updateColumns(s Sheet, from, to reference.CellReference, old map[uint32]*sml.CT_TableColumn) {
+	cols := []*sml.CT_TableColumn{}
+	used := map[string]bool{}
+	for c := from.ColumnIdx; c <= to.ColumnIdx; c++ {
+		if tc, ok := old[c]; ok {
+			used[strings.ToLower(tc.NameAttr)] = true
+		}
+	}
+	id := uint32(1)
+	for _, tc := range old {
+		if tc.IdAttr >= id {
+			id = tc.IdAttr + 1
+		}
+	}
+	for c := from.ColumnIdx; c <= to.ColumnIdx; c++ {
+		if tc, ok := old[c]; ok {
+			cols = append(cols, tc)
+			continue
+		}
+		hdr := s.Cell(fmt.Sprintf("%s%d", reference.IndexToColumn(c), from.RowIdx))
+		name := strings.TrimSpace(hdr.GetFormattedValue())
+		if name == "" {
+			name = fmt.Sprintf("Column%d", c-from.ColumnIdx+1)
+		}
+		base := name
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s%d", base, n)
+		}
+		used[strings.ToLower(name)] = true
+		// the header cells must contain the column names
+		if hdr.GetString() != name || hdr.HasFormula() {
+			hdr.SetString(name)
+		}
+		tc := sml.NewCT_TableColumn()
+		tc.IdAttr = id
+		id++
+		tc.NameAttr = name
+		cols = append(cols, tc)
+	}
+	t._cbab.TableColumns = sml.NewCT_TableColumns()
+	t._cbab.TableColumns.TableColumn = cols
+	t._cbab.TableColumns.CountAttr = unioffice.Uint32(uint32(len(cols)))
+}
+
+// SetName renames the table.  Table names must begin with a letter or an
+// underscore, can't contain spaces and must be unique within the workbook.
+// Structured references to the table in the formulas, defined names,
+// conditional formatting and data validations of the workbook are changed to
+// the new name.
+func (t Table) SetName(name string) error {
+	if name == "" {
+		return errors.New("table name can't be empty")
+	}
+	for i, r := range name {
+		if unicode.IsSpace(r) || (i == 0 && !unicode.IsLetter(r) && r != '_' && r != '\\') ||
+			(!unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' && r != '\\') {
+			return fmt.Errorf("invalid table name %s", name)
+		}
+	}
+	if isCellName(name) {
+		return fmt.Errorf("invalid table name %s, names can't be cell references", name)
+	}
+	if t.wb != nil && t.wb.tableNameUsed(name, t._cbab) {
+		return fmt.Errorf("name %s is already in use", name)
+	}
+	old := t._cbab.DisplayNameAttr
+	t._cbab.NameAttr = unioffice.String(name)
+	t._cbab.DisplayNameAttr = name
+	if t.wb != nil && old != name {
+		t.wb.renameTableRefs(old, name)
+	}
+	return nil
+}
+
+// renameTableRefs changes the structured references to a table that's renamed
+// throughout the workbook.
+func (wb *Workbook) renameTableRefs(old, name string) {
+	for _, s := range wb.Sheets() {
+		for _, r := range s._bcgb.SheetData.Row {
+			for _, c := range r.C {
+				if c.F != nil {
+					c.F.Content = formula.RenameTable(c.F.Content, old, name)
+				}
+			}
+		}
+		for _, cf := range s._bcgb.ConditionalFormatting {
+			for _, rule := range cf.CfRule {
+				for i, f := range rule.Formula {
+					rule.Formula[i] = formula.RenameTable(f, old, name)
+				}
+			}
+		}
+		if s._bcgb.DataValidations != nil {
+			for _, dv := range s._bcgb.DataValidations.DataValidation {
+				if dv.Formula1 != nil {
+					dv.Formula1 = unioffice.String(formula.RenameTable(*dv.Formula1, old, name))
+				}
+				if dv.Formula2 != nil {
+					dv.Formula2 = unioffice.String(formula.RenameTable(*dv.Formula2, old, name))
+				}
+			}
+		}
+	}
+	for _, dn := range wb.DefinedNames() {
+		dn.SetContent(formula.RenameTable(dn.Content(), old, name))
+	}
+}
+
+// isCellName returns true if a name could be confused with an A1 or R1C1
+// style cell reference.
+func isCellName(name string) bool {
+	switch strings.ToUpper(name) {
+	case "R", "C":
+		return true
+	}
+	if cref, err := reference.ParseCellReference(name); err == nil && strings.IndexAny(name, "$!") < 0 &&
+		cref.ColumnIdx < 16384 && cref.RowIdx >= 1 && cref.RowIdx <= 1048576 {
+		return true
+	}
+	up := strings.ToUpper(name)
+	if up[0] == 'R' {
+		i := 1
+		for i < len(up) && up[i] >= '0' && up[i] <= '9' {
+			i++
+		}
+		if i < len(up) && up[i] == 'C' {
+			i++
+			for i < len(up) && up[i] >= '0' && up[i] <= '9' {
+				i++
+			}
+			return i == len(up)
+		}
+	}
+	return false
+}
+
+// SetReference resizes the table to a new range on the same sheet.  The header
+// row must remain on the first row of the range, columns that are still within
+// the range keep their names and totals.
+func (t Table) SetReference(ref string) error {
+	s, err := t.sheet()
+	if err != nil {
+		return err
+	}
+	from, to, err := parseTableRange(ref)
+	if err != nil {
+		return err
+	}
+	totals := t.totalsRowCount()
+	if to.RowIdx < from.RowIdx+1+totals {
+		return errors.New("table must contain a header row and at least one row of data")
+	}
+	if err := s.checkTableOverlap(t._cbab, from, to); err != nil {
+		return err
+	}
+	oldFrom, oldTo, err := parseTableRange(t._cbab.RefAttr)
+	if err != nil {
+		return err
+	}
+	if totals > 0 {
+		t.clearTotals(s, oldFrom, oldTo)
+	}
+	t.syncColumns()
+	old := map[uint32]*sml.CT_TableColumn{}
+	if t._cbab.TableColumns != nil {
+		for i, tc := range t._cbab.TableColumns.TableColumn {
+			old[oldFrom.ColumnIdx+uint32(i)] = tc
+		}
+	}
+	t.updateColumns(s, from, to, old)
+	t._cbab.RefAttr = rangeString(from, to)
+	t.updateAutoFilter()
+	if totals > 0 {
+		t.writeTotals(s)
+	}
+	return nil
+}
+
+// headerRowCount returns the number of header rows, zero or one.
+func (t Table) headerRowCount() uint32 {
+	if t._cbab.HeaderRowCountAttr != nil {
+		return *t._cbab.HeaderRowCountAttr
+	}
+	return 1
+}
+
+// totalsRowCount returns the number of totals rows, zero or one.
+func (t Table) totalsRowCount() uint32 {
+	if t._cbab.TotalsRowCountAttr != nil {
+		return *t._cbab.TotalsRowCountAttr
+	}
+	return 0
+}
+
+// updateAutoFilter updates the range of the autofilter to cover the header and
+// data rows.
+func (t Table) updateAutoFilter() {
+	if t._cbab.AutoFilter == nil {
+		return
+	}
+	from, to, err := parseTableRange(t._cbab.RefAttr)
+	if err != nil {
+		return
+	}
+	to.RowIdx -= t.totalsRowCount()
+	t._cbab.AutoFilter.RefAttr = unioffice.String(rangeString(from, to))
+}
+
+// SetAutoFilter controls whether the header row of the table displays filter
+// buttons.
+func (t Table) SetAutoFilter(b bool) {
+	if !b {
+		t._cbab.AutoFilter = nil
+		return
+	}
+	if t._cbab.AutoFilter == nil {
+		t._cbab.AutoFilter = sml.NewCT_AutoFilter()
+	}
+	t.updateAutoFilter()
+}
+
+// HasAutoFilter returns true if the header row of the table displays filter
+// buttons.
+func (t Table) HasAutoFilter() bool { return t._cbab.AutoFilter != nil }
+
+// SetTotalsRow shows or hides the totals row of the table.  Showing the totals
+// row adds a row below the table and hiding it removes the row and clears its
+// cells.
+func (t Table) SetTotalsRow(b bool) error {
+	s, err := t.sheet()
+	if err != nil {
+		return err
+	}
+	from, to, err := parseTableRange(t._cbab.RefAttr)
+	if err != nil {
+		return err
+	}
+	shown := t.totalsRowCount() > 0
+	switch {
+	case b && !shown:
+		to.RowIdx++
+		if err := s.checkTableOverlap(t._cbab, from, to); err != nil {
+			return err
+		}
+		t._cbab.RefAttr = rangeString(from, to)
+		t._cbab.TotalsRowCountAttr = unioffice.Uint32(1)
+		t._cbab.TotalsRowShownAttr = nil
+		cols := t._cbab.TableColumns
+		if cols != nil && len(cols.TableColumn) > 0 {
+			first := cols.TableColumn[0]
+			if first.TotalsRowLabelAttr == nil && (first.TotalsRowFunctionAttr == sml.ST_TotalsRowFunctionUnset ||
+				first.TotalsRowFunctionAttr == sml.ST_TotalsRowFunctionNone) {
+				first.TotalsRowLabelAttr = unioffice.String("Total")
+			}
+		}
+		t.writeTotals(s)
+	case !b && shown:
+		t.clearTotals(s, from, to)
+		to.RowIdx--
+		t._cbab.RefAttr = rangeString(from, to)
+		t._cbab.TotalsRowCountAttr = nil
+		t._cbab.TotalsRowShownAttr = unioffice.Bool(false)
+	}
+	t.updateAutoFilter()
+	return nil
+}
+
+// HasTotalsRow returns true if the totals row of the table is shown.
+func (t Table) HasTotalsRow() bool { return t.totalsRowCount() > 0 }
+
+// SetColumnTotal sets the function used to compute the totals row for a
+// column.
+func (t Table) SetColumnTotal(column string, fn TableTotalFunction) error {
+	if int(fn) >= len(tableTotalFunctions) {
+		return fmt.Errorf("unsupported total function %d", fn)
+	}
+	tc := t.column(column)
+	if tc == nil {
+		return fmt.Errorf("column %s not found", column)
+	}
+	tc.TotalsRowFunctionAttr = tableTotalFunctions[fn].typ
+	if fn != TableTotalFunctionNone {
+		tc.TotalsRowLabelAttr = nil
+	}
+	if t.totalsRowCount() > 0 {
+		s, err := t.sheet()
+		if err != nil {
+			return err
+		}
+		t.writeTotals(s)
+	}
+	return nil
+}
+
+// SetColumnTotalLabel sets the text displayed in the totals row for a column.
+func (t Table) SetColumnTotalLabel(column, label string) error {
+	tc := t.column(column)
+	if tc == nil {
+		return fmt.Errorf("column %s not found", column)
+	}
+	tc.TotalsRowFunctionAttr = sml.ST_TotalsRowFunctionUnset
+	tc.TotalsRowLabelAttr = unioffice.String(label)
+	if t.totalsRowCount() > 0 {
+		s, err := t.sheet()
+		if err != nil {
+			return err
+		}
+		t.writeTotals(s)
+	}
+	return nil
+}
+
+// column returns the named column, names are compared case insensitively.
+// The columns are renamed to match their header cells first.
+func (t Table) column(name string) *sml.CT_TableColumn {
+	t.syncColumns()
+	if t._cbab.TableColumns == nil {
+		return nil
+	}
+	for _, tc := range t._cbab.TableColumns.TableColumn {
+		if strings.EqualFold(tc.NameAttr, name) {
+			return tc
+		}
+	}
+	return nil
+}
+
+// Columns returns the names of the columns of the table.  Columns follow the
+// text of the header cells, so changing a header cell renames its column.  The
+// table itself is updated with the new names when the workbook is saved or
+// the table is changed.
+func (t Table) Columns() []string {
+	return t.columnNames()
+}
+
+// columnNames returns the names of the columns of the table as renamed by the
+// text of their header cells.  Columns keep their names if their header cells
+// are empty or have the name of another column.
+func (t Table) columnNames() []string {
+	ret := []string{}
+	if t._cbab.TableColumns == nil {
+		return ret
+	}
+	for _, tc := range t._cbab.TableColumns.TableColumn {
+		ret = append(ret, tc.NameAttr)
+	}
+	if t.headerRowCount() == 0 {
+		return ret
+	}
+	s, err := t.sheet()
+	if err != nil {
+		return ret
+	}
+	from, _, err := parseTableRange(t._cbab.RefAttr)
+	if err != nil {
+		return ret
+	}
+	for i := range ret {
+		ref := reference.CellReference{RowIdx: from.RowIdx, ColumnIdx: from.ColumnIdx + uint32(i)}
+		ref.Column = reference.IndexToColumn(ref.ColumnIdx)
+		x := s.findCell(ref)
+		if x == nil {
+			continue
+		}
+		name := strings.TrimSpace(Cell{t.wb, &s, nil, x}.GetFormattedValue())
+		if name == "" || name == ret[i] {
+			continue
+		}
+		used := false
+		for j, n := range ret {
+			used = used || j != i && strings.EqualFold(n, name)
+		}
+		if !used {
+			ret[i] = name
+		}
+	}
+	return ret
+}
+
+// syncColumns renames the columns of the table whose header cells have been
+// changed.
+func (t Table) syncColumns() {
+	if t._cbab.TableColumns == nil {
+		return
+	}
+	for i, name := range t.columnNames() {
+		t._cbab.TableColumns.TableColumn[i].NameAttr = name
+	}
+}
+
+// syncTableColumns renames the columns of the tables of the workbook to match
+// their header cells.
+func (wb *Workbook) syncTableColumns() {
+	for _, t := range wb.Tables() {
+		t.syncColumns()
+	}
+}
+
+// escapeTableColumn escapes the characters with a special meaning in the
+// column specifier of a structured reference.
+func escapeTableColumn(name string) string {
+	r := strings.NewReplacer("'", "''", "[", "'[", "]", "']", "#", "'#")
+	return r.Replace(name)
+}
+
+// writeTotals writes the labels and formulas of the totals row.  The columns
+// are renamed to match their header cells first.
+func (t Table) writeTotals(s Sheet) {
+	t.syncColumns()
+	from, to, err := parseTableRange(t._cbab.RefAttr)
+	if err != nil || t._cbab.TableColumns == nil {
+		return
+	}
+	for i, tc := range t._cbab.TableColumns.TableColumn {
+		c := s.Cell(fmt.Sprintf("%s%d", reference.IndexToColumn(from.ColumnIdx+uint32(i)), to.RowIdx))
+		fn := -1
+		for k, f := range tableTotalFunctions {
+			if f.typ == tc.TotalsRowFunctionAttr && f.subtotal != 0 {
+				fn = k
+			}
+		}
+		switch {
+		case fn >= 0:
+			c.SetFormulaRaw(fmt.Sprintf("SUBTOTAL(%d,%s[%s])", tableTotalFunctions[fn].subtotal,
+				t._cbab.DisplayNameAttr, escapeTableColumn(tc.NameAttr)))
+		case tc.TotalsRowLabelAttr != nil:
+			c.SetString(*tc.TotalsRowLabelAttr)
+		default:
+			c.Clear()
+		}
+	}
+}
+
+// clearTotals clears the cells of the totals row.
+func (t Table) clearTotals(s Sheet, from, to reference.CellReference) {
+	for c := from.ColumnIdx; c <= to.ColumnIdx; c++ {
+		s.Cell(fmt.Sprintf("%s%d", reference.IndexToColumn(c), to.RowIdx)).Clear()
+	}
+}
+
+// SetStyle sets the built-in or custom table style (e.g. 'TableStyleMedium2').
+func (t Table) SetStyle(name string) {
+	t.styleInfo().NameAttr = unioffice.String(name)
+}
+
+// Style returns the name of the table style.
+func (t Table) Style() string {
+	if t._cbab.TableStyleInfo == nil || t._cbab.TableStyleInfo.NameAttr == nil {
+		return ""
+	}
+	return *t._cbab.TableStyleInfo.NameAttr
+}
+
+func (t Table) styleInfo() *sml.CT_TableStyleInfo {
+	if t._cbab.TableStyleInfo == nil {
+		t._cbab.TableStyleInfo = sml.NewCT_TableStyleInfo()
+	}
+	return t._cbab.TableStyleInfo
+}
+
+// SetShowRowStripes controls whether alternate rows are banded.
+func (t Table) SetShowRowStripes(b bool) { t.styleInfo().ShowRowStripesAttr = unioffice.Bool(b) }
+
+// SetShowColumnStripes controls whether alternate columns are banded.
+func (t Table) SetShowColumnStripes(b bool) { t.styleInfo().ShowColumnStripesAttr = unioffice.Bool(b) }
+
+// SetShowFirstColumn controls whether the first column is highlighted.
+func (t Table) SetShowFirstColumn(b bool) { t.styleInfo().ShowFirstColumnAttr = unioffice.Bool(b) }
+
+// SetShowLastColumn controls whether the last column is highlighted.
+func (t Table) SetShowLastColumn(b bool) { t.styleInfo().ShowLastColumnAttr = unioffice.Bool(b) }
+
+// Table returns the location of a table for resolving structured references.
+// An empty name refers to the table containing the cell being evaluated.
+func (e *evalContext) Table(name string) (formula.TableInfo, bool) {
+	var cell reference.CellReference
+	if e.thisCell != "" {
+		cell, _ = reference.ParseCellReference(e.thisCell)
+	}
+	for _, t := range e._afdd._bdb.Tables() {
+		s, err := t.sheet()
+		if err != nil {
+			continue
+		}
+		onSheet := s.X() == e._afdd.X()
+		if name == "" {
+			from, to, err := parseTableRange(t.Reference())
+			if !onSheet || e.thisCell == "" || err != nil ||
+				cell.ColumnIdx < from.ColumnIdx || cell.ColumnIdx > to.ColumnIdx ||
+				cell.RowIdx < from.RowIdx || cell.RowIdx > to.RowIdx {
+				continue
+			}
+		} else if !strings.EqualFold(t.Name(), name) && !strings.EqualFold(t._cbab.DisplayNameAttr, name) {
+			continue
+		}
+		info := formula.TableInfo{
+			Sheet:          s.Name(),
+			Ref:            t.Reference(),
+			Columns:        t.Columns(),
+			HeaderRowCount: t.headerRowCount(),
+			TotalsRowCount: t.totalsRowCount(),
+		}
+		if onSheet {
+			info.ThisRow = cell.RowIdx
+		}
+		return info, true
+	}
+	return formula.TableInfo{}, false
+}
@@ -0,0 +1,334 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+)
+
+func TestTableColumnsFollowHeaders(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetString("Item")
+	s.Cell("B1").SetString("Amount")
+	s.Cell("B2").SetNumber(2)
+	s.Cell("B3").SetNumber(3)
+	tbl, err := s.AddTable("A1:B3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Cell("B1").SetString("Total")
+	if got := strings.Join(tbl.Columns(), " "); got != "Item Total" {
+		t.Errorf("expected columns Item Total, got %s", got)
+	}
+	// saving renames the column in the table
+	saveAndRead(t, wb)
+	s.Cell("A1").SetString("total")
+	s.Cell("D1").SetFormulaRaw("SUM(Table1[Total])")
+	s.Cell("D2").SetFormulaRaw("SUM(Table1[Amount])")
+	g := NewDependencyGraph(wb)
+	if err := g.Recalculate(WithWorkers(2)); err != nil {
+		t.Fatal(err)
+	}
+	// A1 keeps its name as it would clash with the name of B1
+	if got := strings.Join(tbl.Columns(), " "); got != "Item Total" {
+		t.Errorf("expected columns Item Total, got %s", got)
+	}
+	if got := s.Cell("D1").GetFormattedValue(); got != "5" {
+		t.Errorf("expected 5 for the renamed column, got %s", got)
+	}
+	if got, _ := g.result(s.X(), reference.CellReference{ColumnIdx: 3, RowIdx: 2}); got.Type != formula.ResultTypeError || got.ValueString != "#REF!" {
+		t.Errorf("expected #REF! for the old column name, got %s", got.Value())
+	}
+
+	s.Cell("A1").SetString("Name")
+	rd := saveAndRead(t, wb)
+	if got := strings.Join(rd.Tables()[0].Columns(), " "); got != "Name Total" {
+		t.Errorf("expected columns Name Total after saving, got %s", got)
+	}
+}
+
+// tableSheet returns a sheet with a table of items and amounts over A1:B4.
+func tableSheet(t *testing.T) (*Workbook, Sheet, Table) {
+	t.Helper()
+	wb := New()
+	s := wb.AddSheet()
+	s.Cell("A1").SetString("Item")
+	s.Cell("B1").SetString("Amount")
+	for i, v := range []float64{2, 3, 5} {
+		row := string(rune('2' + i))
+		s.Cell("A" + row).SetString("item" + row)
+		s.Cell("B" + row).SetNumber(v)
+	}
+	tbl, err := s.AddTable("A1:B4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wb, s, tbl
+}
+
+func TestAddTable(t *testing.T) {
+	wb, s, tbl := tableSheet(t)
+	if tbl.Name() != "Table1" || tbl.Reference() != "A1:B4" || tbl.Style() != DefaultTableStyle {
+		t.Errorf("expected Table1 over A1:B4 with the default style, got %s over %s with %s", tbl.Name(), tbl.Reference(), tbl.Style())
+	}
+	if got := strings.Join(tbl.Columns(), " "); got != "Item Amount" {
+		t.Errorf("expected columns Item Amount, got %s", got)
+	}
+	if !tbl.HasAutoFilter() || *tbl.X().AutoFilter.RefAttr != "A1:B4" {
+		t.Errorf("expected an autofilter over A1:B4")
+	}
+
+	// headers are given default names and made unique
+	s.Cell("D1").SetString("x")
+	s.Cell("E1").SetString("X")
+	other, err := s.AddTable("D1:F2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Name() != "Table2" {
+		t.Errorf("expected Table2, got %s", other.Name())
+	}
+	if got := strings.Join(other.Columns(), " "); got != "x X2 Column3" {
+		t.Errorf("expected columns x X2 Column3, got %s", got)
+	}
+	if got := s.Cell("F1").GetString(); got != "Column3" {
+		t.Errorf("expected the header Column3 in F1, got %s", got)
+	}
+
+	for _, ref := range []string{"B2:C5", "H1:H1", "bad"} {
+		if _, err := s.AddTable(ref); err == nil {
+			t.Errorf("expected an error adding a table over %s", ref)
+		}
+	}
+
+	rd := saveAndRead(t, wb)
+	tables := rd.Sheets()[0].Tables()
+	if len(tables) != 2 || tables[0].Reference() != "A1:B4" || tables[1].Reference() != "D1:F2" {
+		t.Fatalf("expected the tables to be read back, got %v", tables)
+	}
+}
+
+func TestTableSetName(t *testing.T) {
+	wb, s, tbl := tableSheet(t)
+	if err := tbl.SetTotalsRow(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := tbl.SetColumnTotal("Amount", TableTotalFunctionSum); err != nil {
+		t.Fatal(err)
+	}
+	other := wb.AddSheet()
+	other.Cell("A1").SetFormulaRaw(`SUM(table1[Amount])+COUNTA(Table1[[#Data],[Item]])+LEN("Table1[Amount]")`)
+	other.Cell("A2").SetFormulaRaw("SUM(Table10[Amount])")
+	wb.AddDefinedName("Amounts", "Table1[Amount]")
+
+	for _, name := range []string{"", "1st", "a b", "A1", "R1C1", "Amounts"} {
+		if err := tbl.SetName(name); err == nil {
+			t.Errorf("expected an error renaming the table to %q", name)
+		}
+	}
+	if err := tbl.SetName("Sales"); err != nil {
+		t.Fatal(err)
+	}
+	if tbl.Name() != "Sales" || tbl.X().DisplayNameAttr != "Sales" {
+		t.Errorf("expected the table to be named Sales, got %s", tbl.Name())
+	}
+	for cell, exp := range map[Cell]string{
+		s.Cell("B5"):     "SUBTOTAL(109,Sales[Amount])",
+		other.Cell("A1"): `SUM(Sales[Amount])+COUNTA(Sales[[#Data],[Item]])+LEN("Table1[Amount]")`,
+		other.Cell("A2"): "SUM(Table10[Amount])",
+	} {
+		if got := cell.GetFormula(); got != exp {
+			t.Errorf("expected %s in %s, got %s", exp, cell.Reference(), got)
+		}
+	}
+	if got := wb.DefinedNames()[0].Content(); got != "Sales[Amount]" {
+		t.Errorf("expected the defined name to refer to Sales[Amount], got %s", got)
+	}
+	wb.RecalculateFormulas()
+	if got := s.Cell("B5").GetFormattedValue(); got != "10" {
+		t.Errorf("expected a total of 10 after renaming, got %s", got)
+	}
+}
+
+func TestTableSetReference(t *testing.T) {
+	_, s, tbl := tableSheet(t)
+	if err := tbl.SetTotalsRow(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := tbl.SetColumnTotal("Amount", TableTotalFunctionSum); err != nil {
+		t.Fatal(err)
+	}
+	s.Cell("C1").SetString("Tax")
+	s.Cell("B5").SetNumber(7)
+	if err := tbl.SetReference("A1:C6"); err != nil {
+		t.Fatal(err)
+	}
+	if tbl.Reference() != "A1:C6" || *tbl.X().AutoFilter.RefAttr != "A1:C5" {
+		t.Errorf("expected A1:C6 filtered over A1:C5, got %s", tbl.Reference())
+	}
+	if got := strings.Join(tbl.Columns(), " "); got != "Item Amount Tax" {
+		t.Errorf("expected columns Item Amount Tax, got %s", got)
+	}
+	// the totals row moves to the last row of the table
+	if got := s.Cell("A6").GetString(); got != "Total" {
+		t.Errorf("expected the label Total in A6, got %s", got)
+	}
+	if got := s.Cell("B6").GetFormula(); got != "SUBTOTAL(109,Table1[Amount])" {
+		t.Errorf("expected the total formula in B6, got %s", got)
+	}
+
+	// the totals of removed columns are cleared
+	if err := tbl.SetReference("A1:A6"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Cell("B6").GetFormula(); got != "" {
+		t.Errorf("expected the total of a removed column to be cleared, got %s", got)
+	}
+	if got := strings.Join(tbl.Columns(), " "); got != "Item" {
+		t.Errorf("expected the column Item, got %s", got)
+	}
+
+	for _, ref := range []string{"A1:A2", "bad"} {
+		if err := tbl.SetReference(ref); err == nil {
+			t.Errorf("expected an error resizing the table to %s", ref)
+		}
+	}
+	if _, err := s.AddTable("E1:F3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tbl.SetReference("A1:E6"); err == nil {
+		t.Errorf("expected an error resizing the table over another table")
+	}
+}
+
+func TestTableTotals(t *testing.T) {
+	for _, tc := range []struct {
+		fn  TableTotalFunction
+		sub string
+		exp string
+	}{
+		{TableTotalFunctionSum, "109", "10"},
+		{TableTotalFunctionMin, "105", "2"},
+		{TableTotalFunctionMax, "104", "5"},
+		{TableTotalFunctionAverage, "101", "3.333333333"},
+		{TableTotalFunctionCount, "103", "3"},
+		{TableTotalFunctionCountNums, "102", "3"},
+	} {
+		wb, s, tbl := tableSheet(t)
+		if err := tbl.SetColumnTotal("amount", tc.fn); err != nil {
+			t.Fatal(err)
+		}
+		if tbl.HasTotalsRow() || s.Cell("B5").GetFormula() != "" {
+			t.Errorf("expected no totals row until it's shown")
+		}
+		if err := tbl.SetTotalsRow(true); err != nil {
+			t.Fatal(err)
+		}
+		if !tbl.HasTotalsRow() || tbl.Reference() != "A1:B5" || *tbl.X().AutoFilter.RefAttr != "A1:B4" {
+			t.Errorf("expected a totals row in row 5, got %s", tbl.Reference())
+		}
+		if got := s.Cell("B5").GetFormula(); got != "SUBTOTAL("+tc.sub+",Table1[Amount])" {
+			t.Errorf("expected SUBTOTAL(%s,Table1[Amount]), got %s", tc.sub, got)
+		}
+		wb.RecalculateFormulas()
+		if got := s.Cell("B5").GetFormattedValue(); !strings.HasPrefix(got, tc.exp) {
+			t.Errorf("expected %s for function %d, got %s", tc.exp, tc.fn, got)
+		}
+	}
+
+	_, s, tbl := tableSheet(t)
+	if err := tbl.SetTotalsRow(true); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Cell("A5").GetString(); got != "Total" {
+		t.Errorf("expected the default label Total in A5, got %s", got)
+	}
+	if err := tbl.SetColumnTotalLabel("Item", "Sum"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Cell("A5").GetString(); got != "Sum" {
+		t.Errorf("expected the label Sum in A5, got %s", got)
+	}
+	if err := tbl.SetColumnTotal("Missing", TableTotalFunctionSum); err == nil {
+		t.Errorf("expected an error for a missing column")
+	}
+	if err := tbl.SetColumnTotal("Amount", TableTotalFunction(100)); err == nil {
+		t.Errorf("expected an error for an unsupported function")
+	}
+
+	// hiding the totals row clears it
+	s.Cell("A6").SetString("below")
+	if err := tbl.SetTotalsRow(false); err != nil {
+		t.Fatal(err)
+	}
+	if tbl.HasTotalsRow() || tbl.Reference() != "A1:B4" || s.Cell("A5").GetString() != "" {
+		t.Errorf("expected the totals row to be removed, got %s", tbl.Reference())
+	}
+	// showing the totals row over another table fails
+	if _, err := s.AddTable("A5:B6"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tbl.SetTotalsRow(true); err == nil {
+		t.Errorf("expected an error showing the totals row over another table")
+	}
+}
+
+func TestRemoveTable(t *testing.T) {
+	wb, s, tbl := tableSheet(t)
+	other := wb.AddSheet()
+	other.Cell("A1").SetString("a")
+	second, err := other.AddTable("A1:A3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.RemoveTable(tbl); err == nil {
+		t.Errorf("expected an error removing a table from another sheet")
+	}
+	if err := s.RemoveTable(tbl); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RemoveTable(tbl); err == nil {
+		t.Errorf("expected an error removing a table twice")
+	}
+	if len(s.Tables()) != 0 || s.X().TableParts != nil {
+		t.Errorf("expected no tables on the sheet, got %v", s.Tables())
+	}
+	// the cells are left as they were
+	if got := s.Cell("B2").GetFormattedValue(); got != "2" {
+		t.Errorf("expected 2 in B2, got %s", got)
+	}
+	if got := wb.Tables(); len(got) != 1 || got[0].Name() != second.Name() {
+		t.Errorf("expected only %s in the workbook, got %v", second.Name(), got)
+	}
+
+	rd := saveAndRead(t, wb)
+	if len(rd.Sheets()[0].Tables()) != 0 || len(rd.Sheets()[1].Tables()) != 1 {
+		t.Errorf("expected only the table of the second sheet after saving")
+	}
+}
+
+func TestTableColumnsDontChangeTable(t *testing.T) {
+	wb, s, tbl := tableSheet(t)
+	s.Cell("B1").SetString("Price")
+	if got := strings.Join(tbl.Columns(), " "); got != "Item Price" {
+		t.Errorf("expected columns Item Price, got %s", got)
+	}
+	// reading the columns doesn't rename them in the table
+	if got := tbl.X().TableColumns.TableColumn[1].NameAttr; got != "Amount" {
+		t.Errorf("expected the table to keep the column Amount until it's saved, got %s", got)
+	}
+	saveAndRead(t, wb)
+	if got := tbl.X().TableColumns.TableColumn[1].NameAttr; got != "Price" {
+		t.Errorf("expected the column to be renamed Price on saving, got %s", got)
+	}
+}
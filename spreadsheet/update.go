@@ -0,0 +1,900 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/unidoc/unioffice"
+	crt "github.com/unidoc/unioffice/schema/soo/dml/chart"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+	"github.com/unidoc/unioffice/spreadsheet/update"
+	"github.com/unidoc/unioffice/vmldrawing"
+)
+
+// InsertRows inserts n empty rows before the row number rowNum, moving the
+// rows below down.  References to the moved cells in formulas, defined names,
+// merged cells, conditional formatting, data validations, hyperlinks,
+// autofilters, tables, charts and comments are updated to match.  Formulas
+// keep their cached values until they're recalculated, except that arrays
+// spill again from where their formulas are moved to.
+func (s *Sheet) InsertRows(rowNum, n int) error {
+	if rowNum < 1 || rowNum > maxRowIdx || n < 1 {
+		return fmt.Errorf("invalid rows %d to %d", rowNum, rowNum+n-1)
+	}
+	return s.updateReferences(&update.UpdateQuery{UpdateType: update.UpdateActionInsertRow, RowIdx: uint32(rowNum), Count: uint32(n)})
+}
+
+// RemoveRow removes a row from the sheet and moves the rows below up.  This
+// updates references the same as RemoveRows, references to the removed
+// cells become #REF!.
+func (s *Sheet) RemoveRow(rowNum int) error { return s.RemoveRows(rowNum, 1) }
+
+// RemoveRows removes n rows starting at the row number rowNum and moves the
+// rows below up.  References to the moved cells are updated as for InsertRows,
+// references to the removed cells become #REF! and ranges that are partially
+// removed shrink.
+func (s *Sheet) RemoveRows(rowNum, n int) error {
+	if rowNum < 1 || rowNum > maxRowIdx || n < 1 {
+		return fmt.Errorf("invalid rows %d to %d", rowNum, rowNum+n-1)
+	}
+	return s.updateReferences(&update.UpdateQuery{UpdateType: update.UpdateActionRemoveRow, RowIdx: uint32(rowNum), Count: uint32(n)})
+}
+
+// InsertColumn inserts an empty column before column (e.g. 'C'), moving the
+// columns to the right of it one step right.  References are updated as for
+// InsertRows.
+func (s *Sheet) InsertColumn(column string) error { return s.InsertColumns(column, 1) }
+
+// InsertColumns inserts n empty columns before column (e.g. 'C'), moving the
+// columns to the right.  References are updated as for InsertRows.
+func (s *Sheet) InsertColumns(column string, n int) error {
+	idx, err := columnIndex(column)
+	if err != nil || n < 1 {
+		return fmt.Errorf("invalid columns %s and %d", column, n)
+	}
+	return s.updateReferences(&update.UpdateQuery{UpdateType: update.UpdateActionInsertColumn, ColumnIdx: idx, Count: uint32(n)})
+}
+
+// RemoveColumns removes n columns starting at column (e.g. 'C') and moves the
+// columns to the right of them left.  References are updated as for
+// RemoveRows.
+func (s *Sheet) RemoveColumns(column string, n int) error {
+	idx, err := columnIndex(column)
+	if err != nil || n < 1 {
+		return fmt.Errorf("invalid columns %s and %d", column, n)
+	}
+	return s.updateReferences(&update.UpdateQuery{UpdateType: update.UpdateActionRemoveColumn, ColumnIdx: idx, Count: uint32(n)})
+}
+
+// columnIndex returns the index of a column name such as 'C'.
+func columnIndex(column string) (uint32, error) {
+	column = strings.ToUpper(strings.TrimSpace(column))
+	if column == "" || len(column) > 3 {
+		return 0, errors.New("invalid column")
+	}
+	for _, c := range column {
+		if c < 'A' || c > 'Z' {
+			return 0, errors.New("invalid column")
+		}
+	}
+	idx := reference.ColumnToIndex(column)
+	if idx > maxColumnIdx {
+		return 0, errors.New("invalid column")
+	}
+	return idx, nil
+}
+
+// isRemoval returns true if the query removes rows or columns.
+func isRemoval(q *update.UpdateQuery) bool {
+	return q.UpdateType == update.UpdateActionRemoveRow || q.UpdateType == update.UpdateActionRemoveColumn
+}
+
+// isRowUpdate returns true if the query inserts or removes rows.
+func isRowUpdate(q *update.UpdateQuery) bool {
+	return q.UpdateType == update.UpdateActionRemoveRow || q.UpdateType == update.UpdateActionInsertRow
+}
+
+// isColumnUpdate returns true if the query inserts or removes columns.
+func isColumnUpdate(q *update.UpdateQuery) bool {
+	return q.UpdateType == update.UpdateActionRemoveColumn || q.UpdateType == update.UpdateActionInsertColumn
+}
+
+// updateRef updates a reference or a range on the sheet that is updated,
+// returning false if it's removed.
+func updateRef(ref string, q *update.UpdateQuery) (string, bool) {
+	cq := *q
+	cq.UpdateCurrentSheet = true
+	ref = formula.UpdateFormula(ref, &cq)
+	return ref, !strings.Contains(ref, "#REF!")
+}
+
+// updateSqref updates a list of ranges, dropping the ranges that are removed.
+func updateSqref(sqref []string, q *update.UpdateQuery) []string {
+	ret := []string{}
+	for _, refs := range sqref {
+		for _, ref := range strings.Fields(refs) {
+			if ref, ok := updateRef(ref, q); ok {
+				ret = append(ret, ref)
+			}
+		}
+	}
+	return ret
+}
+
+// updateReferences inserts or removes rows or columns of the sheet, or moves
+// a range of its cells, and updates the references to the cells of the sheet
+// throughout the workbook.
+func (s *Sheet) updateReferences(q *update.UpdateQuery) error {
+	q.SheetToUpdate = s.Name()
+	if q.Count == 0 {
+		q.Count = 1
+	}
+	if err := s.checkArrayFormulas(q); err != nil {
+		return err
+	}
+	wb := s._bdb
+	anchors := []*sml.CT_Cell{}
+	for x := range wb.spills[s._bcgb] {
+		anchors = append(anchors, x)
+	}
+	s.clearSpills()
+	// formulas are updated before the cells move so that shared formulas can
+	// be expanded from where they are
+	for _, ws := range wb.Sheets() {
+		sq := *q
+		sq.UpdateCurrentSheet = ws._bcgb == s._bcgb
+		ws.updateFormulas(&sq)
+		ws.updateFormattingFormulas(&sq)
+	}
+	s.moveCells(q)
+	s.updateColumnWidths(q)
+	s.updateMergedCells(q)
+	s.updateConditionalFormatting(q)
+	s.updateDataValidations(q)
+	s.updateHyperlinks(q)
+	s.updateAutoFilter(q)
+	s.updateViews(q)
+	s.updateTables(q)
+	s.updateComments(q)
+	wb.updateDefinedNames(q)
+	wb.updateCharts(q)
+	wb.updatePivotSources(s.Name(), q)
+	s.respill(anchors)
+	return nil
+}
+
+// respill recalculates formulas whose arrays spilled before the sheet was
+// updated, or that were copied from formulas that did, so that they spill into
+// the cells around where they now are.  The other formulas aren't
+// recalculated.
+func (s *Sheet) respill(anchors []*sml.CT_Cell) {
+	if len(anchors) == 0 {
+		return
+	}
+	g := newDependencyGraph(s._bdb, []Sheet{*s})
+	o := newRecalcOptions(nil)
+	for _, x := range anchors {
+		if x.F == nil || x.RAttr == nil {
+			continue
+		}
+		ref, err := reference.ParseCellReference(*x.RAttr)
+		if err != nil {
+			continue
+		}
+		if n, ok := g.nodes[cellKey{s._bcgb, ref.ColumnIdx, ref.RowIdx}]; ok && n.cell.X() == x {
+			g.store(n, g.eval(n, o), o)
+		}
+	}
+}
+
+// checkArrayFormulas returns an error if an array formula would be split by
+// the update, like Excel doesn't allow changing part of an array.
+func (s *Sheet) checkArrayFormulas(q *update.UpdateQuery) error {
+	for _, r := range s._bcgb.SheetData.Row {
+		for _, c := range r.C {
+			if c.F == nil || c.F.TAttr != sml.ST_CellFormulaTypeArray {
+				continue
+			}
+			from, to, ok := s.arrayFormulaRange(c)
+			if !ok {
+				continue
+			}
+			if q.UpdateType == update.UpdateActionMove {
+				if !moveSplits(from, to, q) {
+					continue
+				}
+				return fmt.Errorf("can't change part of the array formula in %s", rangeString(from, to))
+			}
+			first, last := from.ColumnIdx, to.ColumnIdx
+			idx := q.ColumnIdx
+			if isRowUpdate(q) {
+				first, last, idx = from.RowIdx, to.RowIdx, q.RowIdx
+			}
+			split := idx > first && idx <= last
+			if isRemoval(q) {
+				end := idx + q.Count - 1
+				split = idx <= last && end >= first && (idx > first || end < last)
+			}
+			if split {
+				return fmt.Errorf("can't change part of the array formula in %s", rangeString(from, to))
+			}
+		}
+	}
+	return nil
+}
+
+// moveSplits returns true if moving the range described by q would move or
+// overwrite only part of the range from:to.
+func moveSplits(from, to reference.CellReference, q *update.UpdateQuery) bool {
+	// ranges which are moved or overwritten entirely aren't split
+	r := rangeString(from, to)
+	if moved, ok := updateRef(r, q); !ok || moved != r {
+		return false
+	}
+	src := area{col1: q.ColumnIdx, row1: q.RowIdx, col2: q.ColumnIdx + q.Columns - 1, row2: q.RowIdx + q.Rows - 1}
+	dst := src.offset(q.ColumnOffset, q.RowOffset)
+	a := area{col1: from.ColumnIdx, row1: from.RowIdx, col2: to.ColumnIdx, row2: to.RowIdx}
+	return a.overlaps(src) || a.overlaps(dst)
+}
+
+// arrayFormulaRange returns the range of an array formula, evaluating the
+// formula if the range isn't stored.
+func (s *Sheet) arrayFormulaRange(c *sml.CT_Cell) (reference.CellReference, reference.CellReference, bool) {
+	if c.F.RefAttr != nil {
+		from, to, err := parseTableRange(*c.F.RefAttr)
+		return from, to, err == nil
+	}
+	if c.RAttr == nil {
+		return reference.CellReference{}, reference.CellReference{}, false
+	}
+	from, err := reference.ParseCellReference(*c.RAttr)
+	if err != nil {
+		return from, from, false
+	}
+	to := from
+	res := formula.NewEvaluator().Eval(s.FormulaContext(), c.F.Content)
+	switch res.Type {
+	case formula.ResultTypeArray:
+		if len(res.ValueArray) > 0 {
+			to.RowIdx += uint32(len(res.ValueArray) - 1)
+			to.ColumnIdx += uint32(len(res.ValueArray[0]) - 1)
+		}
+	case formula.ResultTypeList:
+		if len(res.ValueList) > 0 {
+			to.ColumnIdx += uint32(len(res.ValueList) - 1)
+		}
+	}
+	return from, to, true
+}
+
+// updateFormulas updates the formulas of the cells of the sheet.  Shared
+// formulas that no longer fit the pattern of the first cell are replaced by
+// formulas of their own.
+func (s *Sheet) updateFormulas(q *update.UpdateQuery) {
+	masters := map[uint32]*sml.CT_Cell{}
+	members := map[uint32][]*sml.CT_Cell{}
+	for _, r := range s._bcgb.SheetData.Row {
+		for _, c := range r.C {
+			f := c.F
+			if f == nil {
+				continue
+			}
+			if f.TAttr == sml.ST_CellFormulaTypeShared && f.SiAttr != nil {
+				if f.Content != "" {
+					masters[*f.SiAttr] = c
+				} else {
+					members[*f.SiAttr] = append(members[*f.SiAttr], c)
+				}
+				continue
+			}
+			f.Content = formula.UpdateFormula(f.Content, q)
+			if f.RefAttr != nil && q.UpdateCurrentSheet {
+				if ref, ok := updateRef(*f.RefAttr, q); ok {
+					f.RefAttr = unioffice.String(ref)
+				}
+			}
+		}
+	}
+	for si, m := range masters {
+		updateSharedFormula(m, members[si], q)
+	}
+}
+
+// copiedFormula returns a formula as copied by a number of columns and rows,
+// with its relative references moved by the same offset.
+func copiedFormula(f string, cols, rows int) string {
+	if cols == 0 && rows == 0 {
+		return f
+	}
+	return formula.UpdateFormula(f, &update.UpdateQuery{
+		UpdateType:   update.UpdateActionCopy,
+		ColumnOffset: cols,
+		RowOffset:    rows,
+	})
+}
+
+// cellOffset returns the number of columns and rows from one cell to another.
+func cellOffset(from, to string) (int, int, bool) {
+	f, err := reference.ParseCellReference(from)
+	if err != nil {
+		return 0, 0, false
+	}
+	t, err := reference.ParseCellReference(to)
+	if err != nil {
+		return 0, 0, false
+	}
+	return int(t.ColumnIdx) - int(f.ColumnIdx), int(t.RowIdx) - int(f.RowIdx), true
+}
+
+// movedCell returns where a cell ends up after the update, returning false if
+// it's removed.  Cells of other sheets don't move.
+func movedCell(ref string, q *update.UpdateQuery) (string, bool) {
+	if !q.UpdateCurrentSheet {
+		return ref, true
+	}
+	return updateRef(ref, q)
+}
+
+// updateSharedFormula updates a shared formula whose first cell is m.  If the
+// updated formulas of the other cells can't be derived from the first cell
+// any longer, each cell gets a formula of its own.
+func updateSharedFormula(m *sml.CT_Cell, members []*sml.CT_Cell, q *update.UpdateQuery) {
+	if m.RAttr == nil {
+		return
+	}
+	f := m.F.Content
+	updated := formula.UpdateFormula(f, q)
+	to, mok := movedCell(*m.RAttr, q)
+	shared := mok
+	formulas := make([]string, len(members))
+	for i, c := range members {
+		if c.RAttr == nil {
+			shared = false
+			continue
+		}
+		dc, dr, ok := cellOffset(*m.RAttr, *c.RAttr)
+		if !ok {
+			shared = false
+			continue
+		}
+		formulas[i] = formula.UpdateFormula(copiedFormula(f, dc, dr), q)
+		if !shared {
+			continue
+		}
+		if cto, ok := movedCell(*c.RAttr, q); ok {
+			dc, dr, _ := cellOffset(to, cto)
+			shared = copiedFormula(updated, dc, dr) == formulas[i]
+		}
+	}
+	if shared {
+		m.F.Content = updated
+		if m.F.RefAttr != nil && q.UpdateCurrentSheet {
+			if ref, ok := updateRef(*m.F.RefAttr, q); ok {
+				m.F.RefAttr = unioffice.String(ref)
+			}
+		}
+		return
+	}
+	m.F = sml.NewCT_CellFormula()
+	m.F.Content = updated
+	for i, c := range members {
+		c.F = sml.NewCT_CellFormula()
+		c.F.Content = formulas[i]
+	}
+}
+
+// updateFormattingFormulas updates the formulas of the conditional
+// formatting and data validations of the sheet.
+func (s *Sheet) updateFormattingFormulas(q *update.UpdateQuery) {
+	for _, cf := range s._bcgb.ConditionalFormatting {
+		for _, rule := range cf.CfRule {
+			for i, f := range rule.Formula {
+				rule.Formula[i] = formula.UpdateFormula(f, q)
+			}
+		}
+	}
+	if s._bcgb.DataValidations != nil {
+		for _, dv := range s._bcgb.DataValidations.DataValidation {
+			if dv.Formula1 != nil {
+				dv.Formula1 = unioffice.String(formula.UpdateFormula(*dv.Formula1, q))
+			}
+			if dv.Formula2 != nil {
+				dv.Formula2 = unioffice.String(formula.UpdateFormula(*dv.Formula2, q))
+			}
+		}
+	}
+	if s._bcgb.Hyperlinks != nil {
+		for _, hl := range s._bcgb.Hyperlinks.Hyperlink {
+			if hl.LocationAttr != nil {
+				hl.LocationAttr = unioffice.String(formula.UpdateFormula(*hl.LocationAttr, q))
+			}
+		}
+	}
+}
+
+// moveCells moves the rows and cells of the sheet, dropping the ones that are
+// removed or pushed off the sheet.
+func (s *Sheet) moveCells(q *update.UpdateQuery) {
+	if q.UpdateType == update.UpdateActionMove {
+		s.moveRange(q)
+		return
+	}
+	sd := s._bcgb.SheetData
+	rows := []*sml.CT_Row{}
+	for _, r := range sd.Row {
+		if r.RAttr == nil {
+			rows = append(rows, r)
+			continue
+		}
+		if isRowUpdate(q) {
+			ref, ok := updateRef(fmt.Sprintf("%d:%d", *r.RAttr, *r.RAttr), q)
+			if !ok {
+				continue
+			}
+			n, _ := strconv.Atoi(strings.Split(ref, ":")[0])
+			r.RAttr = unioffice.Uint32(uint32(n))
+		}
+		cells := []*sml.CT_Cell{}
+		for _, c := range r.C {
+			if c.RAttr == nil {
+				cells = append(cells, c)
+				continue
+			}
+			ref, err := reference.ParseCellReference(*c.RAttr)
+			if err != nil {
+				cells = append(cells, c)
+				continue
+			}
+			if isRowUpdate(q) {
+				ref.RowIdx = *r.RAttr
+			} else if moved, ok := updateRef(ref.Column+strconv.Itoa(int(ref.RowIdx)), q); ok {
+				ref, _ = reference.ParseCellReference(moved)
+			} else {
+				continue
+			}
+			c.RAttr = unioffice.String(fmt.Sprintf("%s%d", reference.IndexToColumn(ref.ColumnIdx), ref.RowIdx))
+			cells = append(cells, c)
+		}
+		r.C = cells
+		rows = append(rows, r)
+	}
+	sd.Row = rows
+}
+
+// moveRange moves the cells of the range described by q, dropping the cells
+// that it's moved over.
+func (s *Sheet) moveRange(q *update.UpdateQuery) {
+	moved := []*sml.CT_Cell{}
+	for _, r := range s._bcgb.SheetData.Row {
+		kept := r.C[:0]
+		for _, c := range r.C {
+			if c.RAttr == nil {
+				kept = append(kept, c)
+				continue
+			}
+			ref, ok := updateRef(*c.RAttr, q)
+			switch {
+			case !ok:
+			case ref != *c.RAttr:
+				c.RAttr = unioffice.String(ref)
+				moved = append(moved, c)
+			default:
+				kept = append(kept, c)
+			}
+		}
+		r.C = kept
+	}
+	s.placeCells(moved)
+}
+
+// updateColumnWidths moves the column properties of the sheet.
+func (s *Sheet) updateColumnWidths(q *update.UpdateQuery) {
+	if !isColumnUpdate(q) {
+		return
+	}
+	for _, cols := range s._bcgb.Cols {
+		kept := cols.Col[:0]
+		for _, c := range cols.Col {
+			if c.MinAttr < 1 || c.MaxAttr < c.MinAttr {
+				kept = append(kept, c)
+				continue
+			}
+			ref, ok := updateRef(reference.IndexToColumn(c.MinAttr-1)+":"+reference.IndexToColumn(c.MaxAttr-1), q)
+			if !ok {
+				continue
+			}
+			from, to, err := reference.ParseColumnRangeReference(ref)
+			if err != nil {
+				continue
+			}
+			c.MinAttr, c.MaxAttr = from.ColumnIdx+1, to.ColumnIdx+1
+			kept = append(kept, c)
+		}
+		cols.Col = kept
+	}
+	kept := s._bcgb.Cols[:0]
+	for _, cols := range s._bcgb.Cols {
+		if len(cols.Col) > 0 {
+			kept = append(kept, cols)
+		}
+	}
+	s._bcgb.Cols = kept
+}
+
+// updateMergedCells updates the merged cells of the sheet, removing the ones
+// that are removed or reduced to a single cell.
+func (s *Sheet) updateMergedCells(q *update.UpdateQuery) {
+	mc := s._bcgb.MergeCells
+	if mc == nil {
+		return
+	}
+	kept := mc.MergeCell[:0]
+	for _, m := range mc.MergeCell {
+		ref, ok := updateRef(m.RefAttr, q)
+		if !ok {
+			continue
+		}
+		if from, to, err := reference.ParseRangeReference(ref); err == nil && from.String() == to.String() {
+			continue
+		}
+		m.RefAttr = ref
+		kept = append(kept, m)
+	}
+	mc.MergeCell = kept
+	if len(kept) == 0 {
+		s._bcgb.MergeCells = nil
+		return
+	}
+	mc.CountAttr = unioffice.Uint32(uint32(len(kept)))
+}
+
+// updateConditionalFormatting updates the ranges that conditional formatting
+// applies to, removing the formatting of ranges that are removed entirely.
+func (s *Sheet) updateConditionalFormatting(q *update.UpdateQuery) {
+	kept := s._bcgb.ConditionalFormatting[:0]
+	for _, cf := range s._bcgb.ConditionalFormatting {
+		if cf.SqrefAttr != nil {
+			sqref := sml.ST_Sqref(updateSqref(*cf.SqrefAttr, q))
+			if len(sqref) == 0 {
+				continue
+			}
+			cf.SqrefAttr = &sqref
+		}
+		kept = append(kept, cf)
+	}
+	s._bcgb.ConditionalFormatting = kept
+}
+
+// updateDataValidations updates the ranges that data validations apply to,
+// removing the validations of ranges that are removed entirely.
+func (s *Sheet) updateDataValidations(q *update.UpdateQuery) {
+	dvs := s._bcgb.DataValidations
+	if dvs == nil {
+		return
+	}
+	kept := dvs.DataValidation[:0]
+	for _, dv := range dvs.DataValidation {
+		dv.SqrefAttr = updateSqref(dv.SqrefAttr, q)
+		if len(dv.SqrefAttr) > 0 {
+			kept = append(kept, dv)
+		}
+	}
+	dvs.DataValidation = kept
+	if len(kept) == 0 {
+		s._bcgb.DataValidations = nil
+		return
+	}
+	dvs.CountAttr = unioffice.Uint32(uint32(len(kept)))
+}
+
+// updateHyperlinks moves the hyperlinks of the sheet, removing the ones whose
+// cells are removed.
+func (s *Sheet) updateHyperlinks(q *update.UpdateQuery) {
+	hls := s._bcgb.Hyperlinks
+	if hls == nil {
+		return
+	}
+	kept := hls.Hyperlink[:0]
+	for _, hl := range hls.Hyperlink {
+		if ref, ok := updateRef(hl.RefAttr, q); ok {
+			hl.RefAttr = ref
+			kept = append(kept, hl)
+		}
+	}
+	hls.Hyperlink = kept
+	if len(kept) == 0 {
+		s._bcgb.Hyperlinks = nil
+	}
+}
+
+// updateFilterColumns updates the column indexes of the filter columns of an
+// autofilter, which are relative to the first column of its range, dropping
+// the filters of columns that are removed.
+func updateFilterColumns(af *sml.CT_AutoFilter, oldRef string, q *update.UpdateQuery) {
+	if !isColumnUpdate(q) || len(af.FilterColumn) == 0 || af.RefAttr == nil {
+		return
+	}
+	from, _, err := reference.ParseRangeReference(oldRef)
+	if err != nil {
+		return
+	}
+	newFrom, _, err := reference.ParseRangeReference(*af.RefAttr)
+	if err != nil {
+		return
+	}
+	kept := af.FilterColumn[:0]
+	for _, fc := range af.FilterColumn {
+		col := reference.IndexToColumn(from.ColumnIdx + fc.ColIdAttr)
+		ref, ok := updateRef(col+":"+col, q)
+		if !ok {
+			continue
+		}
+		moved, _, err := reference.ParseColumnRangeReference(ref)
+		if err != nil || moved.ColumnIdx < newFrom.ColumnIdx {
+			continue
+		}
+		fc.ColIdAttr = moved.ColumnIdx - newFrom.ColumnIdx
+		kept = append(kept, fc)
+	}
+	af.FilterColumn = kept
+}
+
+// updateSortState updates the ranges of a sort state, returning false if the
+// sorted range is removed.
+func updateSortState(ss *sml.CT_SortState, q *update.UpdateQuery) bool {
+	ref, ok := updateRef(ss.RefAttr, q)
+	if !ok {
+		return false
+	}
+	ss.RefAttr = ref
+	kept := ss.SortCondition[:0]
+	for _, sc := range ss.SortCondition {
+		if ref, ok := updateRef(sc.RefAttr, q); ok {
+			sc.RefAttr = ref
+			kept = append(kept, sc)
+		}
+	}
+	ss.SortCondition = kept
+	return len(kept) > 0
+}
+
+// updateAutoFilter updates the autofilter and the sort state of the sheet.
+func (s *Sheet) updateAutoFilter(q *update.UpdateQuery) {
+	if af := s._bcgb.AutoFilter; af != nil && af.RefAttr != nil {
+		old := *af.RefAttr
+		if ref, ok := updateRef(old, q); ok {
+			af.RefAttr = unioffice.String(ref)
+			updateFilterColumns(af, old, q)
+			if af.SortState != nil && !updateSortState(af.SortState, q) {
+				af.SortState = nil
+			}
+		} else {
+			s._bcgb.AutoFilter = nil
+		}
+	}
+	if s._bcgb.SortState != nil && !updateSortState(s._bcgb.SortState, q) {
+		s._bcgb.SortState = nil
+	}
+}
+
+// updateViews updates the dimension, the selection and the top left cell of
+// the panes of the sheet.  References which are removed are left as is as
+// they still refer to cells of the sheet.
+func (s *Sheet) updateViews(q *update.UpdateQuery) {
+	if d := s._bcgb.Dimension; d != nil {
+		if ref, ok := updateRef(d.RefAttr, q); ok {
+			d.RefAttr = ref
+		}
+	}
+	if s._bcgb.SheetViews == nil {
+		return
+	}
+	for _, sv := range s._bcgb.SheetViews.SheetView {
+		if sv.Pane != nil && sv.Pane.TopLeftCellAttr != nil {
+			if ref, ok := updateRef(*sv.Pane.TopLeftCellAttr, q); ok {
+				sv.Pane.TopLeftCellAttr = unioffice.String(ref)
+			}
+		}
+		for _, sel := range sv.Selection {
+			if sel.ActiveCellAttr != nil {
+				if ref, ok := updateRef(*sel.ActiveCellAttr, q); ok {
+					sel.ActiveCellAttr = unioffice.String(ref)
+				}
+			}
+			if sel.SqrefAttr != nil {
+				if sqref := sml.ST_Sqref(updateSqref(*sel.SqrefAttr, q)); len(sqref) > 0 {
+					sel.SqrefAttr = &sqref
+				}
+			}
+		}
+	}
+}
+
+// updateTables updates the ranges of the tables of the sheet, removing the
+// tables that are removed entirely.
+func (s *Sheet) updateTables(q *update.UpdateQuery) {
+	for _, t := range s.Tables() {
+		oldFrom, _, err := parseTableRange(t._cbab.RefAttr)
+		if err != nil {
+			continue
+		}
+		ref, ok := updateRef(t._cbab.RefAttr, q)
+		if !ok {
+			s.RemoveTable(t)
+			continue
+		}
+		from, to, err := parseTableRange(ref)
+		if err != nil {
+			continue
+		}
+		if isColumnUpdate(q) && t._cbab.TableColumns != nil {
+			old := map[uint32]*sml.CT_TableColumn{}
+			for i, tc := range t._cbab.TableColumns.TableColumn {
+				col := reference.IndexToColumn(oldFrom.ColumnIdx + uint32(i))
+				if moved, ok := updateRef(col+":"+col, q); ok {
+					if c, _, err := reference.ParseColumnRangeReference(moved); err == nil {
+						old[c.ColumnIdx] = tc
+					}
+				}
+			}
+			t.updateColumns(*s, from, to, old)
+		}
+		t._cbab.RefAttr = rangeString(from, to)
+		t.updateAutoFilter()
+		if t._cbab.SortState != nil && !updateSortState(t._cbab.SortState, q) {
+			t._cbab.SortState = nil
+		}
+	}
+}
+
+// updateComments moves the comments of the sheet and their shapes, removing
+// the comments whose cells are removed.
+func (s *Sheet) updateComments(q *update.UpdateQuery) {
+	idx := s.index()
+	wb := s._bdb
+	if idx < 0 || idx >= len(wb._cbge) || wb._cbge[idx] == nil || wb._cbge[idx].CommentList == nil {
+		return
+	}
+	cl := wb._cbge[idx].CommentList
+	kept := cl.Comment[:0]
+	for _, c := range cl.Comment {
+		if ref, ok := updateRef(c.RefAttr, q); ok {
+			c.RefAttr = ref
+			kept = append(kept, c)
+		}
+	}
+	cl.Comment = kept
+	vml := s.commentDrawing()
+	if vml == nil {
+		return
+	}
+	shapes := vml.Shape[:0]
+	for _, sh := range vml.Shape {
+		keep := true
+		for _, el := range sh.EG_ShapeElements {
+			cd := el.ClientData
+			if cd == nil || cd.Row == nil || cd.Column == nil {
+				continue
+			}
+			ref := fmt.Sprintf("%s%d", reference.IndexToColumn(uint32(*cd.Column)), *cd.Row+1)
+			moved, ok := updateRef(ref, q)
+			if !ok {
+				keep = false
+				break
+			}
+			if r, err := reference.ParseCellReference(moved); err == nil {
+				*cd.Row = int64(r.RowIdx) - 1
+				*cd.Column = int64(r.ColumnIdx)
+			}
+		}
+		if keep {
+			shapes = append(shapes, sh)
+		}
+	}
+	vml.Shape = shapes
+}
+
+// commentDrawing returns the legacy drawing holding the comment shapes of the
+// sheet, or nil if it doesn't have one.
+func (s *Sheet) commentDrawing() *vmldrawing.Container {
+	idx := s.index()
+	wb := s._bdb
+	if s._bcgb.LegacyDrawing == nil || idx < 0 || idx >= len(wb._fdbe) {
+		return nil
+	}
+	for _, r := range wb._fdbe[idx].Relationships() {
+		if r.ID() != s._bcgb.LegacyDrawing.IdAttr {
+			continue
+		}
+		// drawings are numbered in the order they are stored
+		name := strings.TrimSuffix(path.Base(r.Target()), path.Ext(r.Target()))
+		n, err := strconv.Atoi(strings.TrimLeft(name, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+		if err != nil || n < 1 || n > len(wb._cbbfe) {
+			return nil
+		}
+		return wb._cbbfe[n-1]
+	}
+	return nil
+}
+
+// updateDefinedNames updates the references of the defined names.
+func (wb *Workbook) updateDefinedNames(q *update.UpdateQuery) {
+	nq := *q
+	nq.UpdateCurrentSheet = false
+	for _, dn := range wb.DefinedNames() {
+		dn.SetContent(formula.UpdateFormula(dn.Content(), &nq))
+	}
+}
+
+// updateCharts updates the references of the series of the charts.
+func (wb *Workbook) updateCharts(q *update.UpdateQuery) {
+	cq := *q
+	cq.UpdateCurrentSheet = false
+	for _, cs := range wb._fgcda {
+		updateChartRefs(reflect.ValueOf(cs), &cq)
+	}
+}
+
+// updateChartRefs walks a chart updating the formulas of the references to
+// cells.
+func updateChartRefs(v reflect.Value, q *update.UpdateQuery) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		switch x := v.Interface().(type) {
+		case *crt.CT_NumRef:
+			x.F = formula.UpdateFormula(x.F, q)
+			return
+		case *crt.CT_StrRef:
+			x.F = formula.UpdateFormula(x.F, q)
+			return
+		case *crt.CT_MultiLvlStrRef:
+			x.F = formula.UpdateFormula(x.F, q)
+			return
+		}
+		updateChartRefs(v.Elem(), q)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				updateChartRefs(v.Field(i), q)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			updateChartRefs(v.Index(i), q)
+		}
+	}
+}
+
+// updatePivotSources updates the source ranges of the pivot caches that are
+// on the updated sheet.
+func (wb *Workbook) updatePivotSources(sheet string, q *update.UpdateQuery) {
+	for _, pc := range wb.pivotCaches {
+		src := pc.def.CacheSource
+		if src == nil || src.WorksheetSource == nil {
+			continue
+		}
+		ws := src.WorksheetSource
+		if ws.RefAttr == nil || ws.SheetAttr == nil || !strings.EqualFold(*ws.SheetAttr, sheet) {
+			continue
+		}
+		if ref, ok := updateRef(*ws.RefAttr, q); ok {
+			ws.RefAttr = unioffice.String(ref)
+		}
+	}
+}
@@ -10,17 +10,29 @@
 // terms that can be accessed at https://unidoc.io/eula/
 
 // Package update contains definitions needed for updating references after removing rows/columns.
-package update ;const (UpdateActionRemoveColumn UpdateAction =iota ;);
+package update ;const (UpdateActionRemoveColumn UpdateAction =iota ;UpdateActionInsertColumn ;UpdateActionRemoveRow ;UpdateActionInsertRow ;UpdateActionCopy ;UpdateActionMove ;);
 
-// UpdateQuery contains terms of how to update references after removing row/column.
+// UpdateQuery contains terms of how to update references after removing or inserting rows/columns.
 type UpdateQuery struct{
 
 // UpdateType is one of the update types like UpdateActionRemoveColumn.
 UpdateType UpdateAction ;
 
-// ColumnIdx is the index of the column removed.
+// ColumnIdx is the index of the column removed or inserted.
 ColumnIdx uint32 ;
 
+// RowIdx is the 1-based number of the row removed or inserted.
+RowIdx uint32 ;
+
+// Count is the number of rows or columns removed or inserted, zero means one.
+Count uint32 ;
+
+// ColumnOffset and RowOffset are the number of columns and rows that cells are copied or moved by.
+ColumnOffset int ;RowOffset int ;
+
+// Columns and Rows are the size of the range moved by UpdateActionMove, starting at ColumnIdx and RowIdx.
+Columns uint32 ;Rows uint32 ;
+
 // SheetToUpdate contains the name of the sheet on which removing happened.
 SheetToUpdate string ;
 
@@ -0,0 +1,195 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"strings"
+	"testing"
+)
+
+// updateSheet returns a sheet with formulas, defined names, merged cells, a
+// data validation and a table that refer to its cells.
+func updateSheet(t *testing.T) (*Workbook, Sheet) {
+	t.Helper()
+	wb := New()
+	s := wb.AddSheet()
+	s.SetName("Data")
+	for i := 1; i <= 5; i++ {
+		s.Cell("A" + string(rune('0'+i))).SetNumber(float64(i))
+	}
+	s.Cell("J1").SetFormulaRaw("SUM(A2:A4)")
+	s.Cell("K1").SetFormulaRaw("A3*2")
+	s.Cell("L1").SetFormulaRaw("$A$5+A1")
+	s.Cell("M1").SetFormulaRaw("SUM(Table1[b])")
+	wb.AddDefinedName("Range", "Data!$A$2:$A$4")
+	wb.AddDefinedName("Point", "Data!$A$3")
+	s.AddMergedCells("D2", "E3")
+	dv := s.AddDataValidation()
+	dv.SetRange("A2:A4")
+	s.Cell("G5").SetString("a")
+	s.Cell("H5").SetString("b")
+	if _, err := s.AddTable("G5:H8"); err != nil {
+		t.Fatal(err)
+	}
+	return wb, s
+}
+
+func TestUpdateReferences(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		update      func(s *Sheet) error
+		formulas    map[string]string
+		names       map[string]string
+		merges      string
+		validations string
+		table       string
+		columns     string
+	}{
+		{
+			name:     "insert rows",
+			update:   func(s *Sheet) error { return s.InsertRows(3, 2) },
+			formulas: map[string]string{"J1": "SUM(A2:A6)", "K1": "A5*2", "L1": "$A$7+A1", "M1": "SUM(Table1[b])"},
+			names:    map[string]string{"Range": "Data!$A$2:$A$6", "Point": "Data!$A$5"},
+			merges:   "D2:E5", validations: "A2:A6", table: "G7:H10",
+		},
+		{
+			name:     "insert rows below",
+			update:   func(s *Sheet) error { return s.InsertRows(9, 3) },
+			formulas: map[string]string{"J1": "SUM(A2:A4)", "K1": "A3*2", "L1": "$A$5+A1"},
+			names:    map[string]string{"Range": "Data!$A$2:$A$4", "Point": "Data!$A$3"},
+			merges:   "D2:E3", validations: "A2:A4", table: "G5:H8",
+		},
+		{
+			name:     "remove row",
+			update:   func(s *Sheet) error { return s.RemoveRows(3, 1) },
+			formulas: map[string]string{"J1": "SUM(A2:A3)", "K1": "#REF!*2", "L1": "$A$4+A1", "M1": "SUM(Table1[b])"},
+			names:    map[string]string{"Range": "Data!$A$2:$A$3", "Point": "Data!#REF!"},
+			merges:   "D2:E2", validations: "A2:A3", table: "G4:H7",
+		},
+		{
+			name:     "remove rows",
+			update:   func(s *Sheet) error { return s.RemoveRows(2, 3) },
+			formulas: map[string]string{"J1": "SUM(#REF!)", "K1": "#REF!*2", "L1": "$A$2+A1"},
+			names:    map[string]string{"Range": "Data!#REF!", "Point": "Data!#REF!"},
+			merges:   "", validations: "", table: "G2:H5",
+		},
+		{
+			name:     "remove table rows",
+			update:   func(s *Sheet) error { return s.RemoveRows(6, 2) },
+			formulas: map[string]string{"J1": "SUM(A2:A4)", "M1": "SUM(Table1[b])"},
+			merges:   "D2:E3", validations: "A2:A4", table: "G5:H6",
+		},
+		{
+			name:     "insert columns",
+			update:   func(s *Sheet) error { return s.InsertColumns("B", 2) },
+			formulas: map[string]string{"L1": "SUM(A2:A4)", "M1": "A3*2", "N1": "$A$5+A1", "O1": "SUM(Table1[b])"},
+			names:    map[string]string{"Range": "Data!$A$2:$A$4", "Point": "Data!$A$3"},
+			merges:   "F2:G3", validations: "A2:A4", table: "I5:J8", columns: "a b",
+		},
+		{
+			name:     "remove column",
+			update:   func(s *Sheet) error { return s.RemoveColumns("A", 1) },
+			formulas: map[string]string{"I1": "SUM(#REF!)", "J1": "#REF!*2", "K1": "#REF!+#REF!", "L1": "SUM(Table1[b])"},
+			names:    map[string]string{"Range": "Data!#REF!", "Point": "Data!#REF!"},
+			merges:   "C2:D3", validations: "", table: "F5:G8",
+		},
+		{
+			name:     "remove merged columns",
+			update:   func(s *Sheet) error { return s.RemoveColumns("D", 2) },
+			formulas: map[string]string{"H1": "SUM(A2:A4)", "I1": "A3*2", "J1": "$A$5+A1"},
+			merges:   "", validations: "A2:A4", table: "E5:F8",
+		},
+		{
+			name:     "remove table column",
+			update:   func(s *Sheet) error { return s.RemoveColumns("G", 1) },
+			formulas: map[string]string{"I1": "SUM(A2:A4)"},
+			merges:   "D2:E3", validations: "A2:A4", table: "G5:G8", columns: "b",
+		},
+		{
+			name:     "remove table columns",
+			update:   func(s *Sheet) error { return s.RemoveColumns("G", 2) },
+			formulas: map[string]string{"H1": "SUM(A2:A4)"},
+			merges:   "D2:E3", validations: "A2:A4", table: "",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			wb, s := updateSheet(t)
+			if err := tc.update(&s); err != nil {
+				t.Fatal(err)
+			}
+			for ref, f := range tc.formulas {
+				if got := s.Cell(ref).GetFormula(); got != f {
+					t.Errorf("expected %s in %s, got %s", f, ref, got)
+				}
+			}
+			for _, dn := range wb.DefinedNames() {
+				if exp, ok := tc.names[dn.Name()]; ok && dn.Content() != exp {
+					t.Errorf("expected %s for name %s, got %s", exp, dn.Name(), dn.Content())
+				}
+			}
+			merges := []string{}
+			for _, m := range s.MergedCells() {
+				merges = append(merges, m.Reference())
+			}
+			if got := strings.Join(merges, " "); got != tc.merges {
+				t.Errorf("expected merged cells %q, got %q", tc.merges, got)
+			}
+			validations := []string{}
+			if dvs := s.X().DataValidations; dvs != nil {
+				for _, dv := range dvs.DataValidation {
+					validations = append(validations, strings.Join(DataValidation{dv}.Ranges(), " "))
+				}
+			}
+			if got := strings.Join(validations, " "); got != tc.validations {
+				t.Errorf("expected data validations %q, got %q", tc.validations, got)
+			}
+			tables := []string{}
+			for _, tbl := range s.Tables() {
+				tables = append(tables, tbl.Reference())
+				if got := strings.Join(tbl.Columns(), " "); tc.columns != "" && got != tc.columns {
+					t.Errorf("expected table columns %q, got %q", tc.columns, got)
+				}
+			}
+			if got := strings.Join(tables, " "); got != tc.table {
+				t.Errorf("expected tables %q, got %q", tc.table, got)
+			}
+		})
+	}
+}
+
+func TestRemoveColumnRecalculates(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		remove func(s *Sheet) error
+		exp    string
+	}{
+		// RemoveColumn recalculates the formulas of the workbook as it always has
+		{"RemoveColumn", func(s *Sheet) error { return s.RemoveColumn("B") }, "5"},
+		// RemoveColumns leaves the formulas to be recalculated by the caller
+		{"RemoveColumns", func(s *Sheet) error { return s.RemoveColumns("B", 1) }, "7"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			wb := New()
+			s := wb.AddSheet()
+			s.Cell("A1").SetNumber(1)
+			s.Cell("B1").SetNumber(2)
+			s.Cell("C1").SetNumber(4)
+			s.Cell("E1").SetFormulaRaw("SUM(A1:C1)")
+			wb.RecalculateFormulas()
+			if err := tc.remove(&s); err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Cell("D1").GetFormula(); got != "SUM(A1:B1)" {
+				t.Errorf("expected SUM(A1:B1) in D1, got %s", got)
+			}
+			if got := s.Cell("D1").GetFormattedValue(); got != tc.exp {
+				t.Errorf("expected %s in D1, got %s", tc.exp, got)
+			}
+		})
+	}
+}
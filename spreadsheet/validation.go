@@ -0,0 +1,380 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/unidoc/unioffice"
+	"github.com/unidoc/unioffice/schema/soo/sml"
+	"github.com/unidoc/unioffice/spreadsheet/formula"
+	"github.com/unidoc/unioffice/spreadsheet/reference"
+)
+
+// DVCompareTypeTextLength restricts the length of the text of a cell.
+const DVCompareTypeTextLength = DVompareTypeTextLength
+
+// DataValidations returns the data validations of the sheet.
+func (s *Sheet) DataValidations() []DataValidation {
+	if s._bcgb.DataValidations == nil {
+		return nil
+	}
+	dvs := []DataValidation{}
+	for _, dv := range s._bcgb.DataValidations.DataValidation {
+		dvs = append(dvs, DataValidation{dv})
+	}
+	return dvs
+}
+
+// SetCustom sets the validation to accept values for which a formula is
+// true.  The formula is written for the first cell of the range of the
+// validation and is relative to it (e.g. "ISNUMBER(A1)" for "A1:A10").
+func (d DataValidation) SetCustom(f string) {
+	d.clear()
+	d._fcc.TypeAttr = sml.ST_DataValidationTypeCustom
+	d._fcc.OperatorAttr = sml.ST_DataValidationOperatorUnset
+	d._fcc.Formula1 = unioffice.String(f)
+	d._fcc.Formula2 = nil
+}
+
+// Ranges returns the cells or ranges of cells that the validation applies
+// to.
+func (d DataValidation) Ranges() []string {
+	return append([]string(nil), d._fcc.SqrefAttr...)
+}
+
+// AllowBlank returns true if blank values are accepted.
+func (d DataValidation) AllowBlank() bool {
+	return d._fcc.AllowBlankAttr != nil && *d._fcc.AllowBlankAttr
+}
+
+// SetInputMessage sets the title and text of the message shown when a cell
+// of the validation is selected, and shows it.
+func (d DataValidation) SetInputMessage(title, msg string) {
+	d._fcc.PromptTitleAttr = optionalString(title)
+	d._fcc.PromptAttr = optionalString(msg)
+	d._fcc.ShowInputMessageAttr = unioffice.Bool(true)
+}
+
+// InputMessage returns the title and text of the message shown when a cell
+// of the validation is selected.
+func (d DataValidation) InputMessage() (string, string) {
+	return stringValue(d._fcc.PromptTitleAttr), stringValue(d._fcc.PromptAttr)
+}
+
+// SetShowInputMessage controls if the input message is shown.
+func (d DataValidation) SetShowInputMessage(b bool) {
+	d._fcc.ShowInputMessageAttr = optionalBool(b)
+}
+
+// ShowInputMessage returns true if the input message is shown.
+func (d DataValidation) ShowInputMessage() bool {
+	return d._fcc.ShowInputMessageAttr != nil && *d._fcc.ShowInputMessageAttr
+}
+
+// SetErrorMessage sets the title and text of the message shown when an
+// invalid value is entered, and shows it.
+func (d DataValidation) SetErrorMessage(title, msg string) {
+	d._fcc.ErrorTitleAttr = optionalString(title)
+	d._fcc.ErrorAttr = optionalString(msg)
+	d._fcc.ShowErrorMessageAttr = unioffice.Bool(true)
+}
+
+// ErrorMessage returns the title and text of the message shown when an
+// invalid value is entered.
+func (d DataValidation) ErrorMessage() (string, string) {
+	return stringValue(d._fcc.ErrorTitleAttr), stringValue(d._fcc.ErrorAttr)
+}
+
+// SetShowErrorMessage controls if the error message is shown when an invalid
+// value is entered.
+func (d DataValidation) SetShowErrorMessage(b bool) {
+	d._fcc.ShowErrorMessageAttr = optionalBool(b)
+}
+
+// ShowErrorMessage returns true if the error message is shown when an
+// invalid value is entered.
+func (d DataValidation) ShowErrorMessage() bool {
+	return d._fcc.ShowErrorMessageAttr != nil && *d._fcc.ShowErrorMessageAttr
+}
+
+// SetErrorStyle sets whether an invalid value is rejected (stop) or can be
+// kept after a warning or information message.
+func (d DataValidation) SetErrorStyle(s sml.ST_DataValidationErrorStyle) {
+	d._fcc.ErrorStyleAttr = s
+}
+
+// ErrorStyle returns whether an invalid value is rejected (stop) or can be
+// kept after a warning or information message.
+func (d DataValidation) ErrorStyle() sml.ST_DataValidationErrorStyle {
+	if d._fcc.ErrorStyleAttr == sml.ST_DataValidationErrorStyleUnset {
+		return sml.ST_DataValidationErrorStyleStop
+	}
+	return d._fcc.ErrorStyleAttr
+}
+
+// SetInCellDropDown controls if a list validation shows a drop-down of its
+// values in the cell, which it does by default.
+func (d DataValidation) SetInCellDropDown(b bool) {
+	// the attribute is named showDropDown but hides the drop-down when true
+	d._fcc.ShowDropDownAttr = optionalBool(!b)
+}
+
+// InCellDropDown returns true if a list validation shows a drop-down of its
+// values in the cell.
+func (d DataValidation) InCellDropDown() bool {
+	return d._fcc.ShowDropDownAttr == nil || !*d._fcc.ShowDropDownAttr
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func optionalBool(b bool) *bool {
+	if !b {
+		return nil
+	}
+	return &b
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// DataValidationViolation is a cell whose value doesn't satisfy the data
+// validation of its sheet that applies to it.
+type DataValidationViolation struct {
+	// Reference is the reference of the cell, e.g. "B2", and Value is its
+	// formatted value.
+	Reference  string
+	Value      string
+	Validation DataValidation
+}
+
+// Message returns the error message of the validation, or the message Excel
+// shows if it has none.
+func (v DataValidationViolation) Message() string {
+	if _, msg := v.Validation.ErrorMessage(); msg != "" {
+		return msg
+	}
+	return "This value doesn't match the data validation restrictions defined for this cell."
+}
+
+// CheckDataValidations returns the cells of the sheet whose values don't
+// satisfy the data validation that applies to them, by validation and then by
+// row and column.  Blank cells are only checked by validations that don't
+// allow blank values, and only within the rows and columns that have cells.
+// As with Excel, only the first validation that applies to a cell is checked.
+func (s *Sheet) CheckDataValidations() []DataValidationViolation {
+	dvs := s.DataValidations()
+	if len(dvs) == 0 {
+		return nil
+	}
+	used, ok := s.usedArea()
+	if !ok {
+		return nil
+	}
+	cells := map[cellKey]Cell{}
+	for _, r := range s._bcgb.SheetData.Row {
+		for _, c := range r.C {
+			if c.RAttr == nil {
+				continue
+			}
+			if ref, err := reference.ParseCellReference(*c.RAttr); err == nil {
+				cells[cellKey{s._bcgb, ref.ColumnIdx, ref.RowIdx}] = Cell{s._bdb, s, r, c}
+			}
+		}
+	}
+	ctx := s.FormulaContext()
+	ev := formula.NewEvaluator()
+	checked := map[cellKey]bool{}
+	violations := []DataValidationViolation{}
+	for _, dv := range dvs {
+		areas := []area{}
+		for _, ref := range dv._fcc.SqrefAttr {
+			if a, ok := s.areaOf(ref); ok {
+				areas = append(areas, a)
+			}
+		}
+		if len(areas) == 0 {
+			continue
+		}
+		top := reference.CellReference{Column: reference.IndexToColumn(areas[0].col1), ColumnIdx: areas[0].col1, RowIdx: areas[0].row1}
+		for _, a := range areas {
+			if !a.overlaps(used) {
+				continue
+			}
+			for row := maxUint32(a.row1, used.row1); row <= minUint32(a.row2, used.row2); row++ {
+				for col := maxUint32(a.col1, used.col1); col <= minUint32(a.col2, used.col2); col++ {
+					k := cellKey{s._bcgb, col, row}
+					if checked[k] {
+						continue
+					}
+					checked[k] = true
+					c := cells[k]
+					v := formula.MakeEmptyResult()
+					if c._dbd != nil {
+						v = cellValue(c)
+					}
+					if v.Type == formula.ResultTypeEmpty || v.Type == formula.ResultTypeString && v.ValueString == "" {
+						if dv.AllowBlank() || dv._fcc.TypeAttr == sml.ST_DataValidationTypeUnset || dv._fcc.TypeAttr == sml.ST_DataValidationTypeNone {
+							continue
+						}
+					} else if s.validValue(dv, v, ev, ctx, top, col, row) {
+						continue
+					}
+					text := ""
+					if c._dbd != nil {
+						text = c.GetFormattedValue()
+					}
+					ref := reference.IndexToColumn(col) + strconv.Itoa(int(row))
+					violations = append(violations, DataValidationViolation{ref, text, dv})
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// validValue returns true if a value of the cell at a column and row
+// satisfies a validation.
+func (s *Sheet) validValue(dv DataValidation, v formula.Result, ev formula.Evaluator, ctx formula.Context, top reference.CellReference, col, row uint32) bool {
+	x := dv._fcc
+	l := s._bdb.Locale()
+	parseDate := func(t string) (float64, bool) {
+		n, ok := l.ParseDate(t)
+		if ok && s._bdb.Uses1904Dates() {
+			n -= 1462
+		}
+		return n, ok
+	}
+	operand := func(f *string) (float64, bool) {
+		if f == nil {
+			return 0, false
+		}
+		// the values are stored as text when they aren't formulas
+		if n, err := strconv.ParseFloat(*f, 64); err == nil {
+			return n, true
+		}
+		if n, ok := parseDate(*f); ok {
+			return n, true
+		}
+		res := evalRelative(ev, ctx, *f, top, col, row)
+		if l := res.ListValues(); (res.Type == formula.ResultTypeArray || res.Type == formula.ResultTypeList) && len(l) > 0 {
+			res = l[0]
+		}
+		switch {
+		case res.Type == formula.ResultTypeNumber:
+			return res.ValueNumber, true
+		case res.Type == formula.ResultTypeString:
+			if n, ok := l.ParseNumber(res.ValueString); ok {
+				return n, true
+			}
+			return parseDate(res.ValueString)
+		}
+		return 0, false
+	}
+	compare := func(n float64) bool {
+		a, ok := operand(x.Formula1)
+		if !ok {
+			return false
+		}
+		switch x.OperatorAttr {
+		case sml.ST_DataValidationOperatorEqual:
+			return n == a
+		case sml.ST_DataValidationOperatorNotEqual:
+			return n != a
+		case sml.ST_DataValidationOperatorLessThan:
+			return n < a
+		case sml.ST_DataValidationOperatorLessThanOrEqual:
+			return n <= a
+		case sml.ST_DataValidationOperatorGreaterThan:
+			return n > a
+		case sml.ST_DataValidationOperatorGreaterThanOrEqual:
+			return n >= a
+		}
+		b, ok := operand(x.Formula2)
+		if !ok {
+			return false
+		}
+		between := n >= a && n <= b || n >= b && n <= a
+		return between == (x.OperatorAttr != sml.ST_DataValidationOperatorNotBetween)
+	}
+
+	switch x.TypeAttr {
+	case sml.ST_DataValidationTypeUnset, sml.ST_DataValidationTypeNone:
+		return true
+	case sml.ST_DataValidationTypeCustom:
+		if x.Formula1 == nil {
+			return true
+		}
+		return truthy(evalRelative(ev, ctx, *x.Formula1, top, col, row))
+	case sml.ST_DataValidationTypeList:
+		return s.inList(x.Formula1, v, ev, ctx, top, col, row)
+	case sml.ST_DataValidationTypeTextLength:
+		if v.Type == formula.ResultTypeError {
+			return false
+		}
+		return compare(float64(utf8.RuneCountInString(v.Value())))
+	}
+	if !isNumber(v) {
+		return false
+	}
+	if x.TypeAttr == sml.ST_DataValidationTypeWhole && v.ValueNumber != float64(int64(v.ValueNumber)) {
+		return false
+	}
+	return compare(v.ValueNumber)
+}
+
+// inList returns true if a value is one of the values of a list validation,
+// which are either separated by commas within quotes or the values of a
+// range.  Text is compared ignoring case.
+func (s *Sheet) inList(f *string, v formula.Result, ev formula.Evaluator, ctx formula.Context, top reference.CellReference, col, row uint32) bool {
+	if f == nil || v.Type == formula.ResultTypeError {
+		return false
+	}
+	items := []formula.Result{}
+	if list := strings.TrimSpace(*f); strings.HasPrefix(list, `"`) && strings.HasSuffix(list, `"`) && len(list) > 1 {
+		for _, item := range strings.Split(list[1:len(list)-1], ",") {
+			item = strings.TrimSpace(item)
+			if n, err := strconv.ParseFloat(item, 64); err == nil {
+				items = append(items, formula.MakeNumberResult(n))
+			} else {
+				items = append(items, formula.MakeStringResult(item))
+			}
+		}
+	} else {
+		res := evalRelative(ev, ctx, list, top, col, row)
+		switch res.Type {
+		case formula.ResultTypeArray, formula.ResultTypeList:
+			for _, r := range res.ValueArray {
+				items = append(items, r...)
+			}
+			items = append(items, res.ValueList...)
+		default:
+			items = append(items, res)
+		}
+	}
+	for _, item := range items {
+		if item.Type == formula.ResultTypeEmpty || item.Type == formula.ResultTypeError {
+			continue
+		}
+		if c, ok := compareResults(v, item); ok && c == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+// Copyright 2017 FoxyUtils ehf. All rights reserved.
+//
+// Use of this software package and source code is governed by the terms of the
+// UniDoc End User License Agreement (EULA) that is available at:
+// https://unidoc.io/eula/
+// A trial license code for evaluation can be obtained at https://unidoc.io.
+
+package spreadsheet
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/unidoc/unioffice/schema/soo/sml"
+)
+
+// violations returns the references and values of the cells that don't
+// satisfy the validations of a sheet.
+func violations(s Sheet) string {
+	got := []string{}
+	for _, v := range s.CheckDataValidations() {
+		got = append(got, v.Reference+"="+v.Value)
+	}
+	return strings.Join(got, " ")
+}
+
+func TestCheckDataValidations(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	for _, tc := range []struct {
+		name   string
+		values []interface{}
+		set    func(s Sheet, dv DataValidation)
+		exp    string
+	}{
+		{"whole", []interface{}{5, 5.5, 11, "x", 0, 1}, func(s Sheet, dv DataValidation) {
+			c := dv.SetComparison(DVCompareTypeWholeNumber, DVCompareOpBetween)
+			c.SetValue("1")
+			c.SetValue2("10")
+		}, "A2=5.5 A3=11 A4=x A5=0"},
+		{"whole with reversed bounds", []interface{}{5, 11}, func(s Sheet, dv DataValidation) {
+			c := dv.SetComparison(DVCompareTypeWholeNumber, DVCompareOpNotBetween)
+			c.SetValue("10")
+			c.SetValue2("1")
+		}, "A1=5"},
+		{"decimal", []interface{}{1, 1.5, 2, "2", true}, func(s Sheet, dv DataValidation) {
+			dv.SetComparison(DVCompareTypeDecimal, DVCompareOpGreater).SetValue("1.5")
+		}, "A1=1 A2=1.5 A4=2 A5=TRUE"},
+		// operands are formulas relative to the first cell
+		{"decimal formula", []interface{}{10, 10, 10}, func(s Sheet, dv DataValidation) {
+			dv.SetComparison(DVCompareTypeDecimal, DVCompareOpLessEqual).SetValue("ROW(A1)*4")
+		}, "A1=10 A2=10"},
+
+		{"inline list", []interface{}{"Apple", "pear", 3, "plum", 4}, func(s Sheet, dv DataValidation) {
+			dv.SetList().SetValues([]string{"apple", "Pear", " 3"})
+		}, "A4=plum A5=4"},
+		{"range list", []interface{}{"A", 1, "c", "1"}, func(s Sheet, dv DataValidation) {
+			s.Cell("C1").SetString("a")
+			s.Cell("C2").SetString("b")
+			s.Cell("C3").SetNumber(1)
+			dv.SetList().SetRange("$C$1:$C$3")
+		}, "A3=c A4=1"},
+
+		{"date", []interface{}{day(2023, 3, 15), day(2024, 1, 1), "x", day(2022, 12, 31)}, func(s Sheet, dv DataValidation) {
+			c := dv.SetComparison(DVCompareTypeDate, DVCompareOpBetween)
+			c.SetValue("2023-01-01")
+			c.SetValue2("DATE(2023,12,31)")
+		}, "A2=1/1/2024 A3=x A4=12/31/2022"},
+		{"time", []interface{}{0.25, 0.75, 0.5}, func(s Sheet, dv DataValidation) {
+			dv.SetComparison(DVCompareTypeTime, DVCompareOpLess).SetValue("12:00")
+		}, "A2=0.75 A3=0.5"},
+		{"text length", []interface{}{"abc", "abcd", 12345, "äöü"}, func(s Sheet, dv DataValidation) {
+			dv.SetComparison(DVCompareTypeTextLength, DVCompareOpLessEqual).SetValue("3")
+		}, "A2=abcd A3=12345"},
+		{"custom", []interface{}{1, "x", 2, 2}, func(s Sheet, dv DataValidation) {
+			dv.SetCustom("AND(ISNUMBER(A1),COUNTIF($A$1:$A$4,A1)=1)")
+		}, "A2=x A3=2 A4=2"},
+
+		// blank cells are only checked within the used rows, and empty text
+		// is blank
+		{"blank", []interface{}{1, nil, ""}, func(s Sheet, dv DataValidation) {
+			dv.SetComparison(DVCompareTypeWholeNumber, DVCompareOpGreater).SetValue("0")
+		}, "A2= A3="},
+		{"allow blank", []interface{}{1, nil, "", 0}, func(s Sheet, dv DataValidation) {
+			dv.SetComparison(DVCompareTypeWholeNumber, DVCompareOpGreater).SetValue("0")
+			dv.SetAllowBlank(true)
+		}, "A4=0"},
+	} {
+		wb, s := cfSheet(tc.values...)
+		date := wb.StyleSheet.AddCellStyle()
+		date.SetNumberFormatStandard(StandardFormatDate)
+		for i, v := range tc.values {
+			if d, ok := v.(time.Time); ok {
+				c := s.Cell(fmt.Sprintf("A%d", i+1))
+				c.SetDate(d)
+				c.SetStyle(date)
+			}
+		}
+		dv := s.AddDataValidation()
+		dv.SetRange("A1:A100")
+		tc.set(s, dv)
+		if got := violations(s); got != tc.exp {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.exp, got)
+		}
+	}
+}
+
+func TestCheckDataValidationsOrder(t *testing.T) {
+	_, s := cfSheet(1, 20, 30)
+	s.Cell("B1").SetNumber(40)
+	s.Cell("B3").SetNumber(5)
+	dv := s.AddDataValidation()
+	dv.SetRange("A2:B3")
+	dv.SetComparison(DVCompareTypeWholeNumber, DVCompareOpLess).SetValue("10")
+	dv.SetErrorMessage("Too large", "Enter a number below 10")
+	// only the first validation that applies to a cell is checked
+	other := s.AddDataValidation()
+	other.SetRange("A1:B3")
+	other.SetComparison(DVCompareTypeWholeNumber, DVCompareOpGreater).SetValue("1")
+
+	got := s.CheckDataValidations()
+	if exp := "A2=20 B2= A3=30 A1=1"; violations(s) != exp {
+		t.Fatalf("expected %q, got %q", exp, violations(s))
+	}
+	if got[0].Message() != "Enter a number below 10" {
+		t.Errorf("expected the error message of the validation, got %s", got[0].Message())
+	}
+	if got[3].Validation.X() != other.X() || !strings.HasPrefix(got[3].Message(), "This value doesn't match") {
+		t.Errorf("expected the default message of the second validation, got %s", got[3].Message())
+	}
+
+	empty := New().AddSheet()
+	if v := empty.CheckDataValidations(); v != nil {
+		t.Errorf("expected no violations without validations, got %v", v)
+	}
+}
+
+func TestDataValidationMessages(t *testing.T) {
+	wb := New()
+	s := wb.AddSheet()
+	dv := s.AddDataValidation()
+	dv.SetRange("A1:B2")
+	dv.SetList().SetValues([]string{"a", "b"})
+	if dv.ErrorStyle() != sml.ST_DataValidationErrorStyleStop || !dv.InCellDropDown() || dv.AllowBlank() || dv.ShowInputMessage() {
+		t.Errorf("unexpected defaults")
+	}
+	dv.SetInputMessage("Title", "Pick a letter")
+	dv.SetErrorMessage("Wrong", "Not a letter")
+	dv.SetErrorStyle(sml.ST_DataValidationErrorStyleWarning)
+	dv.SetInCellDropDown(false)
+	dv.SetAllowBlank(true)
+
+	dv = saveAndRead(t, wb).Sheets()[0].DataValidations()[0]
+	if title, msg := dv.InputMessage(); title != "Title" || msg != "Pick a letter" || !dv.ShowInputMessage() {
+		t.Errorf("expected the input message, got %q %q", title, msg)
+	}
+	if title, msg := dv.ErrorMessage(); title != "Wrong" || msg != "Not a letter" || !dv.ShowErrorMessage() {
+		t.Errorf("expected the error message, got %q %q", title, msg)
+	}
+	if dv.ErrorStyle() != sml.ST_DataValidationErrorStyleWarning || dv.InCellDropDown() || !dv.AllowBlank() {
+		t.Errorf("expected a warning without a drop-down allowing blanks")
+	}
+	if got := dv.Ranges(); len(got) != 1 || got[0] != "A1:B2" {
+		t.Errorf("expected the range A1:B2, got %v", got)
+	}
+
+	dv.SetShowInputMessage(false)
+	dv.SetShowErrorMessage(false)
+	dv.SetInputMessage("", "")
+	dv.SetShowInputMessage(false)
+	dv.SetInCellDropDown(true)
+	if dv.ShowInputMessage() || dv.ShowErrorMessage() || !dv.InCellDropDown() {
+		t.Errorf("expected the messages to be hidden and the drop-down shown")
+	}
+	if x := dv.X(); x.PromptTitleAttr != nil || x.PromptAttr != nil || x.ShowInputMessageAttr != nil || x.ShowDropDownAttr != nil {
+		t.Errorf("expected empty messages and defaults to be left out")
+	}
+}